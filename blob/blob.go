@@ -0,0 +1,52 @@
+// Package blob stores binary objects such as uploaded images outside of the database.
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotFound is returned when there is no object with the key.
+	ErrNotFound = errors.New("blob not found")
+
+	// ErrInvalidKey is returned when the key is empty or could refer to something outside of the store.
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store keeps objects by key. Keys are relative paths separated by '/', e.g. "items/3/photo.jpg".
+type Store interface {
+
+	// Put stores the data under the key, replacing any object that already has the key.
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+
+	// Get opens the object with the key. The caller must close the body of the returned object. This will return
+	// ErrNotFound if there is no object with the key.
+	Get(ctx context.Context, key string) (*Object, error)
+
+	// Delete removes the object with the key. Deleting a key that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Object is a stored object that is being read.
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// validateKey rejects keys that could refer to something outside of the store.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return errors.Wrapf(ErrInvalidKey, "'%s' is not a relative path", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return errors.Wrapf(ErrInvalidKey, "'%s' has an empty or relative segment", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package blob
+
+import (
+	"context"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// FileStore keeps objects as files in a directory on the local filesystem. The content type is derived from the
+// extension of the key.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a FileStore that keeps its objects in the directory. The directory is created when the first
+// object is stored.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{
+		dir: dir,
+	}
+}
+
+// Put writes the data to the file of the key. The file is replaced in a single step so readers never see part of it.
+func (fs *FileStore) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	filePath, err := fs.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return errors.Wrapf(err, "unable to create directory for '%s'", key)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return errors.Wrapf(err, "unable to create file for '%s'", key)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		return errors.Wrapf(err, "unable to write '%s'", key)
+	}
+	if err = file.Close(); err != nil {
+		return errors.Wrapf(err, "unable to write '%s'", key)
+	}
+
+	if err = os.Rename(file.Name(), filePath); err != nil {
+		return errors.Wrapf(err, "unable to store '%s'", key)
+	}
+	return nil
+}
+
+// Get opens the file of the key. This will return ErrNotFound if the file does not exist.
+func (fs *FileStore) Get(ctx context.Context, key string) (*Object, error) {
+	filePath, err := fs.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Wrapf(ErrNotFound, "unable to find '%s'", key)
+		}
+		return nil, errors.Wrapf(err, "unable to open '%s'", key)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, errors.Wrapf(err, "unable to read '%s'", key)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &Object{
+		Body:        file,
+		ContentType: contentType,
+		Size:        info.Size(),
+	}, nil
+}
+
+// Delete removes the file of the key.
+func (fs *FileStore) Delete(ctx context.Context, key string) error {
+	filePath, err := fs.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "unable to delete '%s'", key)
+	}
+	return nil
+}
+
+func (fs *FileStore) filePath(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(fs.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,281 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3Service        = "s3"
+	s3TimeFormat     = "20060102T150405Z"
+	s3DateFormat     = "20060102"
+	s3EmptyBodyHash  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3HeaderDate     = "X-Amz-Date"
+	s3HeaderBodyHash = "X-Amz-Content-Sha256"
+)
+
+// S3Settings configures the connection to an S3 compatible service.
+type S3Settings struct {
+	// Endpoint is the base URL of the service, e.g. https://s3.us-east-1.amazonaws.com or http://localhost:9000.
+	Endpoint string
+
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// Client sends the requests. http.DefaultClient is used when it is nil.
+	Client *http.Client
+}
+
+// S3Store keeps objects in a bucket of an S3 compatible service such as AWS S3 or MinIO. Requests use path style URLs
+// so the bucket does not need its own DNS name.
+type S3Store struct {
+	settings S3Settings
+	endpoint *url.URL
+	now      func() time.Time
+}
+
+// NewS3Store creates an S3Store that keeps its objects in the bucket described by the settings.
+func NewS3Store(settings S3Settings) (*S3Store, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(settings.Endpoint, "/"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid S3 endpoint '%s'", settings.Endpoint)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, errors.Errorf("invalid S3 endpoint '%s'", settings.Endpoint)
+	}
+	if settings.Bucket == "" {
+		return nil, errors.New("an S3 bucket is required")
+	}
+	if settings.Client == nil {
+		settings.Client = http.DefaultClient
+	}
+
+	return &S3Store{
+		settings: settings,
+		endpoint: endpoint,
+		now:      time.Now,
+	}, nil
+}
+
+// Put uploads the data to the key in the bucket.
+func (s3 *S3Store) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	request, err := s3.newRequest(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", contentType)
+	s3.sign(request, data)
+
+	response, err := s3.settings.Client.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "unable to upload '%s'", key)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return s3.responseError(response, "unable to upload '%s'", key)
+	}
+	return nil
+}
+
+// Get downloads the object with the key from the bucket. This will return ErrNotFound if the bucket does not have the
+// key.
+func (s3 *S3Store) Get(ctx context.Context, key string) (*Object, error) {
+	request, err := s3.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	s3.sign(request, nil)
+
+	response, err := s3.settings.Client.Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to download '%s'", key)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		if response.StatusCode == http.StatusNotFound {
+			return nil, errors.Wrapf(ErrNotFound, "unable to find '%s'", key)
+		}
+		return nil, s3.responseError(response, "unable to download '%s'", key)
+	}
+
+	contentType := response.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &Object{
+		Body:        response.Body,
+		ContentType: contentType,
+		Size:        response.ContentLength,
+	}, nil
+}
+
+// Delete removes the key from the bucket.
+func (s3 *S3Store) Delete(ctx context.Context, key string) error {
+	request, err := s3.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	s3.sign(request, nil)
+
+	response, err := s3.settings.Client.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "unable to delete '%s'", key)
+	}
+	defer response.Body.Close()
+
+	// S3 answers 204 whether or not the key existed but other services may answer 404.
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK &&
+		response.StatusCode != http.StatusNotFound {
+		return s3.responseError(response, "unable to delete '%s'", key)
+	}
+	return nil
+}
+
+func (s3 *S3Store) newRequest(ctx context.Context, method string, key string, data []byte) (*http.Request, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	objectURL := *s3.endpoint
+	objectURL.Path = objectURL.Path + "/" + s3.settings.Bucket + "/" + key
+	objectURL.RawPath = ""
+
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, objectURL.String(), body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create request for '%s'", key)
+	}
+	return request, nil
+}
+
+func (s3 *S3Store) responseError(response *http.Response, format string, args ...interface{}) error {
+	message, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+	return errors.Wrapf(
+		errors.Errorf("S3 responded with %d: %s", response.StatusCode, strings.TrimSpace(string(message))),
+		format,
+		args...,
+	)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to the request. Every header already on the request is
+// signed along with the host.
+func (s3 *S3Store) sign(request *http.Request, body []byte) {
+	now := s3.now().UTC()
+	bodyHash := s3EmptyBodyHash
+	if len(body) > 0 {
+		bodyHash = hashHex(body)
+	}
+	request.Header.Set(s3HeaderDate, now.Format(s3TimeFormat))
+	request.Header.Set(s3HeaderBodyHash, bodyHash)
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(request)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		canonicalizeQuery(request.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		bodyHash,
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(s3DateFormat), s3.settings.Region, s3Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3TimeFormat),
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s3.settings.SecretAccessKey), now.Format(s3DateFormat))
+	key = hmacSHA256(key, s3.settings.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm,
+		s3.settings.AccessKeyID,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func canonicalizeHeaders(request *http.Request) (string, string) {
+	headers := map[string]string{
+		"host": request.URL.Host,
+	}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	if request.ContentLength > 0 {
+		headers["content-length"] = strconv.FormatInt(request.ContentLength, 10)
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name)
+		canonical.WriteString(":")
+		canonical.WriteString(strings.TrimSpace(headers[name]))
+		canonical.WriteString("\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+func canonicalizeQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+	return strings.ReplaceAll(strings.Join(pairs, "&"), "+", "%20")
+}
+
+func hashHex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
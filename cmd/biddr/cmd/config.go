@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/MMarsolek/AuctionHouse/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Configuration related sub commands",
+	Long:  "Configuration related sub commands",
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Prints the effective configuration",
+	Long:  "Prints the configuration the server would start with as a YAML config file. Secrets are redacted.",
+	Args:  cobra.NoArgs,
+	RunE:  printConfig,
+}
+
+func init() {
+	configCmd.AddCommand(configPrintCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func printConfig(cmd *cobra.Command, args []string) error {
+	// The server's flags are added to the ones given to this command so the whole configuration is shown.
+	flags := pflag.NewFlagSet(cmd.Root().Name(), pflag.ContinueOnError)
+	addFlag := func(flag *pflag.Flag) {
+		if flags.Lookup(flag.Name) == nil && flag.Name != "help" {
+			flags.AddFlag(flag)
+		}
+	}
+	cmd.Flags().VisitAll(addFlag)
+	serverCmd.Flags().VisitAll(addFlag)
+
+	sources, err := loadConfig(cmd, flags)
+	if err != nil {
+		return errors.Wrap(err, "unable to load config")
+	}
+
+	return errors.Wrap(config.Write(cmd.OutOrStdout(), flags, sources), "unable to print config")
+}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/storage/relational"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -24,29 +27,253 @@ var dbInitCmd = &cobra.Command{
 	RunE:  initDatabase,
 }
 
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Changes the database schema",
+	Long:  "Changes the database schema by applying or rolling back migrations",
+}
+
+var dbMigrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Applies all pending migrations",
+	Long:  "Applies all pending migrations",
+	RunE:  migrateUp,
+}
+
+var dbMigrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Rolls back the most recent migration",
+	Long:  "Rolls back the most recent migration",
+	RunE:  migrateDown,
+}
+
+var dbMigrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Lists every migration and whether it has been applied",
+	Long:  "Lists every migration and whether it has been applied",
+	RunE:  migrateStatus,
+}
+
+var dbBackupCmd = &cobra.Command{
+	Use:   "backup <path>",
+	Short: "Writes a copy of the database to the path",
+	Long:  "Writes a consistent copy of the SQLite database to the path. This is safe to run while the server is running.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  backupDatabase,
+}
+
+var dbRestoreCmd = &cobra.Command{
+	Use:   "restore <path>",
+	Short: "Replaces the database with a backup",
+	Long:  "Replaces the SQLite database with a backup after checking the backup is intact. Stop the server first.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  restoreDatabase,
+}
+
+var dbPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently removes deleted items and users",
+	Long: "Permanently removes the items and users that were deleted along with every bid on those items and by those " +
+		"users. Purged items and users can no longer be restored.",
+	Args: cobra.NoArgs,
+	RunE: purgeDatabase,
+}
+
+const purgeParamOlderThan = "older-than"
+
 func init() {
+	dbPurgeCmd.Flags().Duration(purgeParamOlderThan, 0, "Only purge items and users that were deleted at least this long ago, e.g. 720h")
+	for _, migrateCmd := range []*cobra.Command{dbInitCmd, dbMigrateUpCmd, dbMigrateDownCmd} {
+		migrateCmd.Flags().String(serverParamCurrency, model.DefaultCurrency, "The ISO 4217 code of the currency of the event. Amounts stored before they had a currency are migrated as amounts in it.")
+	}
+
+	dbCmd.AddCommand(dbPurgeCmd)
+	dbCmd.AddCommand(dbBackupCmd)
+	dbCmd.AddCommand(dbRestoreCmd)
+	dbMigrateCmd.AddCommand(dbMigrateUpCmd)
+	dbMigrateCmd.AddCommand(dbMigrateDownCmd)
+	dbMigrateCmd.AddCommand(dbMigrateStatusCmd)
 	dbCmd.AddCommand(dbInitCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
 	rootCmd.AddCommand(dbCmd)
 }
 
 func initDatabase(cmd *cobra.Command, args []string) error {
-	if _, err := os.Stat(databaseFileName); err == nil {
+	location, err := databaseLocation(cmd)
+	if err != nil {
+		return errors.Wrap(err, "unable to get database location")
+	}
+
+	// Only SQLite files are recreated. Other databases are never dropped and only have their missing tables created.
+	databasePath, err := relational.SQLiteFilePath(location)
+	if err != nil {
+		cmd.Printf("Not deleting %s since it is not a SQLite file, applying migrations instead\n", location)
+	} else if _, err = os.Stat(databasePath); err == nil {
 		var input string
-		fmt.Print("Database already exists. Drop and recreate tables? [y/N]: ")
+		fmt.Printf("Database %s already exists. Drop and recreate tables? [y/N]: ", databasePath)
 		fmt.Scanln(&input)
 		input = strings.ToLower(input)
 		if !strings.HasPrefix(input, "y") {
 			return nil
 		}
 
-		err = os.Remove(databaseFileName)
-		if err != nil {
-			return errors.Wrapf(err, "could not delete file %s", databaseFileName)
+		if err = bunDB.Close(); err != nil {
+			return errors.Wrap(err, "unable to close database")
+		}
+
+		for _, path := range []string{databasePath, databasePath + "-wal", databasePath + "-shm"} {
+			if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "could not delete file %s", path)
+			}
+		}
+
+		if err = openDB(cmd); err != nil {
+			return errors.Wrap(err, "unable to reopen database")
 		}
 	}
-	err := relational.CreateSchema(cmd.Context(), bunDB)
+
+	ctx, err := migrationContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, err = relational.NewMigrator(bunDB).Up(ctx)
 	if err != nil {
 		return errors.Wrap(err, "unable to create schema")
 	}
 	return nil
 }
+
+// migrationContext returns the context migrations are run with, which tells them the currency of the event.
+func migrationContext(cmd *cobra.Command) (context.Context, error) {
+	currency, err := cmd.Flags().GetString(serverParamCurrency)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get currency")
+	}
+	if err = model.ValidateCurrency(currency); err != nil {
+		return nil, errors.Wrapf(err, "invalid --%s", serverParamCurrency)
+	}
+	return relational.WithEventCurrency(cmd.Context(), currency), nil
+}
+
+func migrateUp(cmd *cobra.Command, args []string) error {
+	ctx, err := migrationContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	applied, err := relational.NewMigrator(bunDB).Up(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to migrate up")
+	}
+
+	if len(applied) == 0 {
+		cmd.Println("Database is already up to date")
+		return nil
+	}
+	for _, name := range applied {
+		cmd.Printf("Applied %s\n", name)
+	}
+	return nil
+}
+
+func migrateDown(cmd *cobra.Command, args []string) error {
+	ctx, err := migrationContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	rolledBack, err := relational.NewMigrator(bunDB).Down(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to migrate down")
+	}
+
+	if rolledBack == "" {
+		cmd.Println("No migrations have been applied")
+		return nil
+	}
+	cmd.Printf("Rolled back %s\n", rolledBack)
+	return nil
+}
+
+func migrateStatus(cmd *cobra.Command, args []string) error {
+	statuses, err := relational.NewMigrator(bunDB).Status(cmd.Context())
+	if err != nil {
+		return errors.Wrap(err, "unable to get migration status")
+	}
+
+	for _, status := range statuses {
+		if status.Applied {
+			cmd.Printf("%s\tapplied %s\n", status.Name, status.MigratedAt.Format(time.RFC3339))
+		} else {
+			cmd.Printf("%s\tpending\n", status.Name)
+		}
+	}
+	return nil
+}
+
+func backupDatabase(cmd *cobra.Command, args []string) error {
+	err := relational.Backup(cmd.Context(), bunDB, args[0])
+	if err != nil {
+		return errors.Wrap(err, "unable to back up database")
+	}
+
+	cmd.Printf("Backed up database to %s\n", args[0])
+	return nil
+}
+
+func restoreDatabase(cmd *cobra.Command, args []string) error {
+	location, err := databaseLocation(cmd)
+	if err != nil {
+		return errors.Wrap(err, "unable to get database location")
+	}
+
+	databasePath, err := relational.SQLiteFilePath(location)
+	if err != nil {
+		return errors.Wrap(err, "unable to find database file")
+	}
+
+	var input string
+	fmt.Printf("Replace %s with %s? Everything since the backup will be lost. [y/N]: ", databasePath, args[0])
+	fmt.Scanln(&input)
+	if !strings.HasPrefix(strings.ToLower(input), "y") {
+		return nil
+	}
+
+	// The database must be closed before its file is replaced.
+	if err = bunDB.Close(); err != nil {
+		return errors.Wrap(err, "unable to close database")
+	}
+
+	err = relational.Restore(cmd.Context(), args[0], databasePath)
+	if err != nil {
+		return errors.Wrap(err, "unable to restore database")
+	}
+
+	cmd.Printf("Restored %s from %s\n", databasePath, args[0])
+	return nil
+}
+
+func purgeDatabase(cmd *cobra.Command, args []string) error {
+	olderThan, err := cmd.Flags().GetDuration(purgeParamOlderThan)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get %s", purgeParamOlderThan)
+	}
+	deletedBefore := time.Now().Add(-olderThan)
+
+	var input string
+	fmt.Printf("Permanently remove every item and user deleted before %s along with their bids? [y/N]: ", deletedBefore.Format(time.RFC3339))
+	fmt.Scanln(&input)
+	if !strings.HasPrefix(strings.ToLower(input), "y") {
+		return nil
+	}
+
+	result, err := relational.Purge(cmd.Context(), bunDB, deletedBefore)
+	if err != nil {
+		return errors.Wrap(err, "unable to purge database")
+	}
+
+	cmd.Printf("Purged %d items, %d users, %d bids, %d pledges, %d ticket purchases and %d images\n", result.Items,
+		result.Users, result.Bids, result.Pledges, result.Tickets, result.Images)
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/MMarsolek/AuctionHouse/itemfile"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage/relational"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var itemsCmd = &cobra.Command{
+	Use:               "items",
+	Short:             "Auction item related sub commands",
+	Long:              "Auction item related sub commands",
+	PersistentPreRunE: bootstrapDB,
+}
+
+var itemsImportCmd = &cobra.Command{
+	Use:   "import <file.csv|file.json>",
+	Short: "Creates or updates items from a CSV or JSON file",
+	Long: "Creates or updates an item for every row of a CSV or JSON file. Items are matched by name, ignoring case. " +
+		"Fair market values must be in the currency of the event. Nothing is imported if any row is invalid.",
+	Args: cobra.ExactArgs(1),
+	RunE: importItems,
+}
+
+var itemsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Writes every item with its highest bid as a CSV file",
+	Long:  "Writes every item with its highest bid as a CSV file that can be opened as a spreadsheet or imported again",
+	Args:  cobra.NoArgs,
+	RunE:  exportItems,
+}
+
+const (
+	itemsParamDryRun = "dry-run"
+	itemsParamOutput = "output"
+)
+
+func init() {
+	itemsImportCmd.Flags().Bool(itemsParamDryRun, false, "Check every row and show what would be imported without changing anything")
+	itemsImportCmd.Flags().String(serverParamCurrency, model.DefaultCurrency, "The ISO 4217 code of the currency of the event, which fair market values without a currency are in")
+	itemsExportCmd.Flags().StringP(itemsParamOutput, "o", "", "The file to write to. Defaults to standard output.")
+
+	itemsCmd.AddCommand(itemsImportCmd)
+	itemsCmd.AddCommand(itemsExportCmd)
+	rootCmd.AddCommand(itemsCmd)
+}
+
+func importItems(cmd *cobra.Command, args []string) error {
+	dryRun, err := cmd.Flags().GetBool(itemsParamDryRun)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get %s", itemsParamDryRun)
+	}
+
+	currency, err := cmd.Flags().GetString(serverParamCurrency)
+	if err != nil {
+		return errors.Wrap(err, "unable to get currency")
+	}
+	if err = model.ValidateCurrency(currency); err != nil {
+		return errors.Wrapf(err, "invalid --%s", serverParamCurrency)
+	}
+	if err = relational.EnsureEventCurrency(cmd.Context(), bunDB, currency); err != nil {
+		return errors.Wrapf(err, "invalid --%s", serverParamCurrency)
+	}
+
+	format, err := itemfile.FormatOf(args[0])
+	if err != nil {
+		return errors.Wrap(err, "unable to determine file format")
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return errors.Wrapf(err, "unable to open %s", args[0])
+	}
+	defer file.Close()
+
+	rows, rowErrors, err := itemfile.Read(file, format, currency)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read %s", args[0])
+	}
+
+	result, err := itemfile.Import(cmd.Context(), relational.NewTransactor(bunDB), rows, rowErrors, dryRun)
+	if err != nil {
+		return errors.Wrap(err, "unable to import items")
+	}
+
+	if len(result.Errors) > 0 {
+		for _, rowErr := range result.Errors {
+			cmd.Println(rowErr.Error())
+		}
+		return errors.Errorf("%s has invalid rows, nothing was imported", args[0])
+	}
+
+	for _, row := range result.Rows {
+		cmd.Printf("row %d: %s '%s'\n", row.Number, row.Action, row.Item.Name)
+	}
+	if dryRun {
+		cmd.Printf("Would create %d items and update %d items\n",
+			result.Count(itemfile.ActionCreated), result.Count(itemfile.ActionUpdated))
+		return nil
+	}
+	cmd.Printf("Created %d items and updated %d items\n",
+		result.Count(itemfile.ActionCreated), result.Count(itemfile.ActionUpdated))
+	return nil
+}
+
+func exportItems(cmd *cobra.Command, args []string) error {
+	output, err := cmd.Flags().GetString(itemsParamOutput)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get %s", itemsParamOutput)
+	}
+
+	items, err := relational.NewAuctionItemClient(bunDB).GetAll(cmd.Context())
+	if err != nil {
+		return errors.Wrap(err, "unable to get items")
+	}
+
+	highestBids, err := relational.NewAuctionBidClient(bunDB).GetAllWinningBids(cmd.Context())
+	if err != nil {
+		return errors.Wrap(err, "unable to get highest bids")
+	}
+
+	var w io.Writer = cmd.OutOrStdout()
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return errors.Wrapf(err, "unable to create %s", output)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err = itemfile.WriteCSV(w, items, highestBids); err != nil {
+		return errors.Wrap(err, "unable to export items")
+	}
+	if output != "" {
+		cmd.Printf("Exported %d items to %s\n", len(items), output)
+	}
+	return nil
+}
@@ -2,32 +2,54 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/MMarsolek/AuctionHouse/config"
 	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/MMarsolek/AuctionHouse/storage/relational"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/dialect/sqlitedialect"
 	"go.uber.org/zap"
-	_ "modernc.org/sqlite"
 )
 
 const databaseFileName = "biddr.db"
 
+const (
+	rootParamConfig         = "config"
+	rootParamLogLevel       = "log-level"
+	rootParamDB             = "db"
+	rootParamDBMaxOpenConns = "db-max-open-conns"
+	rootParamDBMaxIdleConns = "db-max-idle-conns"
+	rootParamDBConnLifetime = "db-conn-max-lifetime"
+	rootParamDBBusyTimeout  = "db-busy-timeout"
+	rootParamDBWAL          = "db-wal"
+)
+
 var bunDB *bun.DB
 
+// logLevel is changed once the configuration is loaded since the logger is created before the flags are parsed.
+var logLevel = zap.NewAtomicLevelAt(zap.DebugLevel)
+
 var rootCmd = &cobra.Command{
-	Use:           "biddr",
-	Short:         "Starts a server that can accept bids for auctions",
+	Use:   "biddr",
+	Short: "Starts a server that can accept bids for auctions",
+	Long: fmt.Sprintf(`Starts a server that can accept bids for auctions.
+
+Every flag can also be set in the config file given with --%s or with an environment variable named after the flag,
+e.g. %s for --admin-password. Flags take precedence over environment variables, which take precedence over the config
+file.`, rootParamConfig, config.EnvName("admin-password")),
 	SilenceErrors: true,
 }
 
 // Execute acts as the entry point into this program.
 func Execute(defaultCommand string) {
-	logger, err := zap.NewDevelopment()
+	loggerConfig := zap.NewDevelopmentConfig()
+	loggerConfig.Level = logLevel
+	logger, err := loggerConfig.Build()
 	if err != nil {
 		panic(err)
 	}
@@ -45,12 +67,147 @@ func Execute(defaultCommand string) {
 	}
 }
 
+func init() {
+	rootCmd.PersistentFlags().String(rootParamConfig, "", fmt.Sprintf("A YAML or TOML file whose keys are flag names, e.g. 'port: 8080'. Can also be set with %s.", config.EnvName(rootParamConfig)))
+	rootCmd.PersistentFlags().String(rootParamLogLevel, "debug", "The lowest level that is logged. One of debug, info, warn or error.")
+	rootCmd.PersistentFlags().String(rootParamDB, "", fmt.Sprintf("The database to use. Either the path of a SQLite file, a SQLite file: DSN or a postgres:// URL. Defaults to %s.", databaseFileName))
+	rootCmd.PersistentFlags().Int(rootParamDBMaxOpenConns, 0, "The maximum number of open database connections. 0 means no limit.")
+	rootCmd.PersistentFlags().Int(rootParamDBMaxIdleConns, 2, "The maximum number of idle database connections kept open")
+	rootCmd.PersistentFlags().Duration(rootParamDBConnLifetime, 0, "How long a database connection is reused before it is closed. 0 means forever.")
+	rootCmd.PersistentFlags().Duration(rootParamDBBusyTimeout, 5*time.Second, "How long SQLite waits for a lock held by another connection before failing")
+	rootCmd.PersistentFlags().Bool(rootParamDBWAL, true, "Use write-ahead logging so SQLite readers do not block writers")
+}
+
+// loadConfig sets every flag that was not given on the command line from the environment or the config file. The
+// source of each flag is returned.
+func loadConfig(cmd *cobra.Command, flags *pflag.FlagSet) (map[string]config.Source, error) {
+	path, err := flags.GetString(rootParamConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get config file")
+	}
+	if envPath, ok := os.LookupEnv(config.EnvName(rootParamConfig)); ok && !flags.Changed(rootParamConfig) {
+		path = envPath
+	}
+
+	var fileSettings map[string]string
+	if path != "" {
+		fileSettings, err = config.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read config file")
+		}
+
+		// Keys are checked against every command so one file can be shared by all of them.
+		knownFlags := allFlagNames(cmd.Root())
+		for key := range fileSettings {
+			if !knownFlags[key] {
+				return nil, errors.Errorf("'%s' in '%s' is not a setting", key, path)
+			}
+		}
+	}
+
+	sources, err := config.Apply(flags, fileSettings, os.LookupEnv)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to apply config")
+	}
+
+	level, err := flags.GetString(rootParamLogLevel)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get log level")
+	}
+	if err = logLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, errors.Wrapf(err, "'%s' is not a log level", level)
+	}
+	return sources, nil
+}
+
+func allFlagNames(cmd *cobra.Command) map[string]bool {
+	names := make(map[string]bool)
+	addNames := func(flag *pflag.Flag) {
+		names[flag.Name] = true
+	}
+
+	cmd.LocalFlags().VisitAll(addNames)
+	cmd.PersistentFlags().VisitAll(addNames)
+	for _, child := range cmd.Commands() {
+		for name := range allFlagNames(child) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
 func bootstrapDB(cmd *cobra.Command, args []string) error {
-	rawDB, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=cache%%3Dshared&_pragma=foreign_keys%%3Dtrue", databaseFileName))
+	if _, err := loadConfig(cmd, cmd.Flags()); err != nil {
+		return errors.Wrap(err, "unable to load config")
+	}
+	return openDB(cmd)
+}
+
+func openDB(cmd *cobra.Command) error {
+	location, err := databaseLocation(cmd)
 	if err != nil {
-		return errors.Wrap(err, "unable to open database")
+		return errors.Wrap(err, "unable to get database location")
 	}
 
-	bunDB = bun.NewDB(rawDB, sqlitedialect.New())
+	options, err := databaseOptions(cmd)
+	if err != nil {
+		return errors.Wrap(err, "unable to get database options")
+	}
+
+	bunDB, err = relational.Open(location, options...)
+	if err != nil {
+		return errors.Wrap(err, "unable to open database")
+	}
 	return nil
 }
+
+// databaseLocation returns the database that was configured, or the default SQLite file when it was not set.
+func databaseLocation(cmd *cobra.Command) (string, error) {
+	if !cmd.Flags().Changed(rootParamDB) {
+		return databaseFileName, nil
+	}
+
+	location, err := cmd.Flags().GetString(rootParamDB)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to get %s", rootParamDB)
+	}
+	return location, nil
+}
+
+func databaseOptions(cmd *cobra.Command) ([]relational.Option, error) {
+	maxOpenConns, err := cmd.Flags().GetInt(rootParamDBMaxOpenConns)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get max open connections")
+	}
+
+	maxIdleConns, err := cmd.Flags().GetInt(rootParamDBMaxIdleConns)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get max idle connections")
+	}
+
+	connLifetime, err := cmd.Flags().GetDuration(rootParamDBConnLifetime)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get connection lifetime")
+	}
+
+	busyTimeout, err := cmd.Flags().GetDuration(rootParamDBBusyTimeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get busy timeout")
+	}
+
+	wal, err := cmd.Flags().GetBool(rootParamDBWAL)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get wal")
+	}
+
+	options := []relational.Option{
+		relational.WithMaxOpenConns(maxOpenConns),
+		relational.WithMaxIdleConns(maxIdleConns),
+		relational.WithConnMaxLifetime(connLifetime),
+		relational.WithBusyTimeout(busyTimeout),
+	}
+	if wal {
+		options = append(options, relational.WithWAL())
+	}
+	return options, nil
+}
@@ -5,12 +5,18 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/MMarsolek/AuctionHouse/blob"
+	"github.com/MMarsolek/AuctionHouse/config"
+	"github.com/MMarsolek/AuctionHouse/events"
 	"github.com/MMarsolek/AuctionHouse/log"
 	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/server"
+	"github.com/MMarsolek/AuctionHouse/server/controller"
 	"github.com/MMarsolek/AuctionHouse/server/controller/auth"
 	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/memory"
 	"github.com/MMarsolek/AuctionHouse/storage/relational"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -26,13 +32,52 @@ const (
 	serverParamAdminUser        = "admin-username"
 	serverParamAdminDisplayName = "admin-display-name"
 	serverParamAdminPassword    = "admin-password"
+	serverParamBackplane        = "backplane"
+	serverParamRedisAddress     = "redis-address"
+	serverParamRedisChannel     = "redis-channel"
+	serverParamStorage          = "storage"
+	serverParamAutoMigrate      = "auto-migrate"
+	serverParamBackupDir        = "backup-dir"
+	serverParamBackupInterval   = "backup-interval"
+	serverParamBackupRetention  = "backup-retention"
+	serverParamJWTSecret        = "jwt-secret"
+	serverParamJWTIssuer        = "jwt-issuer"
+	serverParamTokenLifetime    = "token-lifetime"
+	serverParamStaticDir        = "static-dir"
+	serverParamReadTimeout      = "read-timeout"
+	serverParamWriteTimeout     = "write-timeout"
+	serverParamImageStore       = "image-store"
+	serverParamImageDir         = "image-dir"
+	serverParamMaxImageSize     = "max-image-size"
+	serverParamS3Endpoint       = "s3-endpoint"
+	serverParamS3Region         = "s3-region"
+	serverParamS3Bucket         = "s3-bucket"
+	serverParamS3AccessKeyID    = "s3-access-key-id"
+	serverParamS3SecretKey      = "s3-secret-access-key"
+	serverParamCurrency         = "currency"
+	serverParamLocale           = "locale"
+)
+
+const (
+	backplaneMemory = "memory"
+	backplaneRedis  = "redis"
+)
+
+const (
+	storageRelational = "relational"
+	storageMemory     = "memory"
+)
+
+const (
+	imageStoreFile = "file"
+	imageStoreS3   = "s3"
 )
 
 var serverCmd = &cobra.Command{
 	Use:               "server",
 	Short:             "Starts a fileserver and accepts bids for auctions",
 	Long:              "Starts a fileserver and accepts bids for auctions",
-	PersistentPreRunE: bootstrapDB,
+	PersistentPreRunE: bootstrapServerStorage,
 	RunE:              startServer,
 }
 
@@ -41,33 +86,107 @@ func init() {
 	serverCmd.Flags().IntP(serverParamPort, "p", 8080, "The port to use.")
 	serverCmd.Flags().StringP(serverParamAdminUser, "u", defaultAdminUser, "The default admin username")
 	serverCmd.Flags().StringP(serverParamAdminDisplayName, "d", "admin", "The default admin display name")
-	serverCmd.Flags().StringP(serverParamAdminPassword, "P", defaultAdminPassword, "The default admin password")
+	serverCmd.Flags().StringP(serverParamAdminPassword, "P", defaultAdminPassword, fmt.Sprintf("The default admin password. Prefer setting %s since flags are visible to every user.", config.EnvName(serverParamAdminPassword)))
+	serverCmd.Flags().String(serverParamBackplane, backplaneMemory, "How live updates are shared between server instances. Either 'memory' or 'redis'.")
+	serverCmd.Flags().String(serverParamRedisAddress, "localhost:6379", "The address of the Redis server when using the redis backplane")
+	serverCmd.Flags().String(serverParamRedisChannel, "biddr-events", "The Redis channel used when using the redis backplane")
+	serverCmd.Flags().Bool(serverParamAutoMigrate, false, "Apply pending database migrations on start instead of refusing to start")
+	serverCmd.Flags().String(serverParamBackupDir, "backups", "The directory periodic backups are written to")
+	serverCmd.Flags().Duration(serverParamBackupInterval, 0, "How often to back up the SQLite database, e.g. 15m. Backups are disabled when 0.")
+	serverCmd.Flags().Int(serverParamBackupRetention, 24, "The number of periodic backups to keep")
+	serverCmd.Flags().String(serverParamStorage, storageRelational, "Where data is stored. Either 'relational' for the database or 'memory' for a demo that loses everything on exit.")
+	serverCmd.Flags().String(serverParamJWTSecret, "", "The secret that signs login tokens. Every server instance must share it. A random secret is used when empty, which logs everyone out on restart.")
+	serverCmd.Flags().String(serverParamJWTIssuer, auth.DefaultIssuer, "The issuer stored in login tokens")
+	serverCmd.Flags().Duration(serverParamTokenLifetime, auth.DefaultTokenLifetime, "How long a login token is valid")
+	serverCmd.Flags().String(serverParamStaticDir, "./dist", "The directory of the web client")
+	serverCmd.Flags().Duration(serverParamReadTimeout, 15*time.Second, "How long reading a request may take. 0 means no limit.")
+	serverCmd.Flags().Duration(serverParamWriteTimeout, 15*time.Second, "How long writing a response may take. 0 means no limit.")
+	serverCmd.Flags().String(serverParamImageStore, imageStoreFile, "Where uploaded item images are kept. Either 'file' for the image directory or 's3' for an S3 compatible bucket.")
+	serverCmd.Flags().String(serverParamImageDir, "images", "The directory uploaded item images are kept in when using the file image store")
+	serverCmd.Flags().Int64(serverParamMaxImageSize, controller.DefaultMaxImageSize, "The largest item image that can be uploaded in bytes")
+	serverCmd.Flags().String(serverParamS3Endpoint, "https://s3.amazonaws.com", "The URL of the S3 compatible service when using the s3 image store, e.g. http://localhost:9000 for MinIO")
+	serverCmd.Flags().String(serverParamS3Region, "us-east-1", "The region of the bucket when using the s3 image store")
+	serverCmd.Flags().String(serverParamS3Bucket, "", "The bucket item images are kept in when using the s3 image store")
+	serverCmd.Flags().String(serverParamS3AccessKeyID, "", "The access key ID used when using the s3 image store")
+	serverCmd.Flags().String(serverParamS3SecretKey, "", fmt.Sprintf("The secret access key used when using the s3 image store. Prefer setting %s since flags are visible to every user.", config.EnvName(serverParamS3SecretKey)))
+	serverCmd.Flags().String(serverParamCurrency, model.DefaultCurrency, "The ISO 4217 code of the currency every bid of the event is in, e.g. USD or EUR")
+	serverCmd.Flags().String(serverParamLocale, model.DefaultLocale, "The locale amounts of money are formatted for, e.g. en-US or de-DE")
+	config.MarkSecret(serverCmd.Flags(), serverParamAdminPassword)
+	config.MarkSecret(serverCmd.Flags(), serverParamJWTSecret)
+	config.MarkSecret(serverCmd.Flags(), serverParamS3SecretKey)
+}
+
+func bootstrapServerStorage(cmd *cobra.Command, args []string) error {
+	_, err := loadConfig(cmd, cmd.Flags())
+	if err != nil {
+		return errors.Wrap(err, "unable to load config")
+	}
+
+	storageType, err := cmd.Flags().GetString(serverParamStorage)
+	if err != nil {
+		return errors.Wrap(err, "unable to get storage")
+	}
+
+	if storageType != storageRelational {
+		return nil
+	}
+	return openDB(cmd)
 }
 
 func startServer(cmd *cobra.Command, args []string) error {
-	err := relational.CreateSchema(cmd.Context(), bunDB)
+	settings, err := serverSettings(cmd)
 	if err != nil {
-		return errors.Wrap(err, "unable to create database")
+		return errors.Wrap(err, "unable to get server settings")
 	}
 
-	port, err := cmd.Flags().GetInt(serverParamPort)
+	clients, transactor, err := createStorageClients(cmd, settings.Currency)
 	if err != nil {
-		return errors.Wrap(err, "unable to get port")
+		return errors.Wrap(err, "unable to create storage")
 	}
 
-	userClient := relational.NewUserClient(bunDB)
-	err = tryCreateDefaultAdmin(cmd, userClient)
+	err = configureTokens(cmd)
+	if err != nil {
+		return errors.Wrap(err, "unable to configure login tokens")
+	}
+
+	err = tryCreateDefaultAdmin(cmd, clients.Users)
 	if err != nil {
 		return errors.Wrap(err, "unable to create default admin")
 	}
 
-	address := fmt.Sprintf(":%d", port)
+	backplane, err := createBackplane(cmd)
+	if err != nil {
+		return errors.Wrap(err, "unable to create backplane")
+	}
+	defer backplane.Close()
+
+	blobStore, err := createBlobStore(cmd)
+	if err != nil {
+		return errors.Wrap(err, "unable to create image store")
+	}
+
+	eventBus := events.NewBus(events.DefaultHistorySize)
+	err = eventBus.Connect(cmd.Context(), backplane)
+	if err != nil {
+		return errors.Wrap(err, "unable to connect to backplane")
+	}
+
 	ahServer := server.NewAuctionHouseServer(
 		cmd.Context(),
-		userClient,
-		relational.NewAuctionItemClient(bunDB),
-		relational.NewAuctionBidClient(bunDB),
-		address,
+		clients.Users,
+		clients.Items,
+		clients.Bids,
+		clients.Images,
+		clients.Categories,
+		clients.Lots,
+		clients.Donations,
+		clients.Raffles,
+		clients.Limits,
+		clients.Outbids,
+		transactor,
+		blobStore,
+		eventBus,
+		settings,
 	)
 
 	incomingSignals := make(chan os.Signal, 1)
@@ -81,7 +200,7 @@ func startServer(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
-	log.Info(cmd.Context(), "Web server started", "address", address)
+	log.Info(cmd.Context(), "Web server started", "address", settings.Address)
 	if err := ahServer.ListenAndServe(); err != nil {
 		log.Info(cmd.Context(), "Shutting down the server", "err", err)
 	}
@@ -89,6 +208,282 @@ func startServer(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func serverSettings(cmd *cobra.Command) (server.Settings, error) {
+	port, err := cmd.Flags().GetInt(serverParamPort)
+	if err != nil {
+		return server.Settings{}, errors.Wrap(err, "unable to get port")
+	}
+
+	staticDir, err := cmd.Flags().GetString(serverParamStaticDir)
+	if err != nil {
+		return server.Settings{}, errors.Wrap(err, "unable to get static directory")
+	}
+
+	readTimeout, err := cmd.Flags().GetDuration(serverParamReadTimeout)
+	if err != nil {
+		return server.Settings{}, errors.Wrap(err, "unable to get read timeout")
+	}
+
+	writeTimeout, err := cmd.Flags().GetDuration(serverParamWriteTimeout)
+	if err != nil {
+		return server.Settings{}, errors.Wrap(err, "unable to get write timeout")
+	}
+
+	maxImageSize, err := cmd.Flags().GetInt64(serverParamMaxImageSize)
+	if err != nil {
+		return server.Settings{}, errors.Wrap(err, "unable to get max image size")
+	}
+	if maxImageSize <= 0 {
+		return server.Settings{}, errors.Errorf("--%s must be greater than 0", serverParamMaxImageSize)
+	}
+
+	currency, err := cmd.Flags().GetString(serverParamCurrency)
+	if err != nil {
+		return server.Settings{}, errors.Wrap(err, "unable to get currency")
+	}
+	if err = model.ValidateCurrency(currency); err != nil {
+		return server.Settings{}, errors.Wrapf(err, "invalid --%s", serverParamCurrency)
+	}
+
+	locale, err := cmd.Flags().GetString(serverParamLocale)
+	if err != nil {
+		return server.Settings{}, errors.Wrap(err, "unable to get locale")
+	}
+	if err = model.ValidateLocale(locale); err != nil {
+		return server.Settings{}, errors.Wrapf(err, "invalid --%s", serverParamLocale)
+	}
+
+	return server.Settings{
+		Address:      fmt.Sprintf(":%d", port),
+		StaticDir:    staticDir,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+		MaxImageSize: maxImageSize,
+		Currency:     currency,
+		Locale:       locale,
+	}, nil
+}
+
+func configureTokens(cmd *cobra.Command) error {
+	secret, err := cmd.Flags().GetString(serverParamJWTSecret)
+	if err != nil {
+		return errors.Wrap(err, "unable to get jwt secret")
+	}
+
+	issuer, err := cmd.Flags().GetString(serverParamJWTIssuer)
+	if err != nil {
+		return errors.Wrap(err, "unable to get jwt issuer")
+	}
+
+	lifetime, err := cmd.Flags().GetDuration(serverParamTokenLifetime)
+	if err != nil {
+		return errors.Wrap(err, "unable to get token lifetime")
+	}
+
+	if secret == "" {
+		log.Info(cmd.Context(), fmt.Sprintf("No %s set, everyone will be logged out when the server restarts", serverParamJWTSecret))
+	}
+
+	return auth.Configure(auth.TokenSettings{
+		Secret:   []byte(secret),
+		Issuer:   issuer,
+		Lifetime: lifetime,
+	})
+}
+
+// createStorageClients creates the clients of the configured storage. The database refuses the currency when its bids
+// are in another currency.
+func createStorageClients(cmd *cobra.Command, currency string) (*storage.Clients, storage.Transactor, error) {
+	storageType, err := cmd.Flags().GetString(serverParamStorage)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to get storage")
+	}
+
+	switch storageType {
+	case storageRelational:
+		err = prepareSchema(cmd, currency)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "unable to prepare database")
+		}
+
+		err = relational.EnsureEventCurrency(cmd.Context(), bunDB, currency)
+		if errors.Is(err, relational.ErrEventCurrencyChanged) {
+			return nil, nil, errors.Wrapf(err, "--%s must match the currency of the existing bids", serverParamCurrency)
+		}
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "unable to check currency of the event")
+		}
+
+		err = startBackupScheduler(cmd)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "unable to start backups")
+		}
+
+		return &storage.Clients{
+			Users:      relational.NewUserClient(bunDB),
+			Items:      relational.NewAuctionItemClient(bunDB),
+			Bids:       relational.NewAuctionBidClient(bunDB),
+			Images:     relational.NewItemImageClient(bunDB),
+			Categories: relational.NewCategoryClient(bunDB),
+			Lots:       relational.NewLotClient(bunDB),
+			Donations:  relational.NewDonationClient(bunDB),
+			Raffles:    relational.NewRaffleClient(bunDB),
+			Limits:     relational.NewSpendingLimitClient(bunDB),
+			Outbids:    relational.NewOutbidNoticeClient(bunDB),
+		}, relational.NewTransactor(bunDB), nil
+	case storageMemory:
+		log.Info(cmd.Context(), "Using in-memory storage, all data will be lost when the server stops")
+		db := memory.NewDatabase()
+		return &storage.Clients{
+			Users:      memory.NewUserClient(db),
+			Items:      memory.NewAuctionItemClient(db),
+			Bids:       memory.NewAuctionBidClient(db),
+			Images:     memory.NewItemImageClient(db),
+			Categories: memory.NewCategoryClient(db),
+			Lots:       memory.NewLotClient(db),
+			Donations:  memory.NewDonationClient(db),
+			Raffles:    memory.NewRaffleClient(db),
+			Limits:     memory.NewSpendingLimitClient(db),
+			Outbids:    memory.NewOutbidNoticeClient(db),
+		}, memory.NewTransactor(db), nil
+	}
+
+	return nil, nil, errors.Errorf("%s is not a valid storage", storageType)
+}
+
+// prepareSchema applies pending migrations when auto-migrate is enabled. Otherwise an error is returned if the schema is
+// out of date so the server never runs against tables it does not understand. Bids stored before bids had a currency
+// are migrated as amounts in the currency.
+func prepareSchema(cmd *cobra.Command, currency string) error {
+	autoMigrate, err := cmd.Flags().GetBool(serverParamAutoMigrate)
+	if err != nil {
+		return errors.Wrap(err, "unable to get auto-migrate")
+	}
+
+	migrator := relational.NewMigrator(bunDB)
+	if autoMigrate {
+		applied, err := migrator.Up(relational.WithEventCurrency(cmd.Context(), currency))
+		if err != nil {
+			return errors.Wrap(err, "unable to apply migrations")
+		}
+		if len(applied) > 0 {
+			log.Info(cmd.Context(), "Applied migrations", "migrations", applied)
+		}
+		return nil
+	}
+
+	err = migrator.VerifyUpToDate(cmd.Context())
+	if errors.Is(err, relational.ErrSchemaOutOfDate) {
+		return errors.Wrapf(err, "run 'biddr db migrate up' or start the server with --%s", serverParamAutoMigrate)
+	}
+	return err
+}
+
+// startBackupScheduler periodically backs up the database in the background when a backup interval is set.
+func startBackupScheduler(cmd *cobra.Command) error {
+	interval, err := cmd.Flags().GetDuration(serverParamBackupInterval)
+	if err != nil {
+		return errors.Wrap(err, "unable to get backup interval")
+	}
+	if interval <= 0 {
+		return nil
+	}
+
+	location, err := databaseLocation(cmd)
+	if err != nil {
+		return errors.Wrap(err, "unable to get database location")
+	}
+	if _, err = relational.SQLiteFilePath(location); err != nil {
+		return errors.Wrap(err, "periodic backups require a SQLite database file")
+	}
+
+	directory, err := cmd.Flags().GetString(serverParamBackupDir)
+	if err != nil {
+		return errors.Wrap(err, "unable to get backup directory")
+	}
+
+	retention, err := cmd.Flags().GetInt(serverParamBackupRetention)
+	if err != nil {
+		return errors.Wrap(err, "unable to get backup retention")
+	}
+	if retention < 1 {
+		return errors.Errorf("--%s must be at least 1", serverParamBackupRetention)
+	}
+
+	scheduler := relational.NewBackupScheduler(bunDB, directory, interval, retention)
+	go func() {
+		if err := scheduler.Run(cmd.Context()); err != nil {
+			log.Error(cmd.Context(), "Periodic backups stopped", "err", err)
+		}
+	}()
+
+	log.Info(cmd.Context(), "Backing up database periodically", "directory", directory, "interval", interval, "retention", retention)
+	return nil
+}
+
+func createBackplane(cmd *cobra.Command) (events.Backplane, error) {
+	backplaneType, err := cmd.Flags().GetString(serverParamBackplane)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get backplane")
+	}
+
+	switch backplaneType {
+	case backplaneMemory:
+		return events.NewMemoryBackplane(), nil
+	case backplaneRedis:
+		redisAddress, err := cmd.Flags().GetString(serverParamRedisAddress)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get redis address")
+		}
+
+		redisChannel, err := cmd.Flags().GetString(serverParamRedisChannel)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get redis channel")
+		}
+
+		log.Info(cmd.Context(), "Using redis backplane", "address", redisAddress, "channel", redisChannel)
+		return events.NewRedisBackplane(redisAddress, redisChannel), nil
+	}
+
+	return nil, errors.Errorf("%s is not a valid backplane", backplaneType)
+}
+
+func createBlobStore(cmd *cobra.Command) (blob.Store, error) {
+	imageStore, err := cmd.Flags().GetString(serverParamImageStore)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get image store")
+	}
+
+	switch imageStore {
+	case imageStoreFile:
+		imageDir, err := cmd.Flags().GetString(serverParamImageDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get image directory")
+		}
+
+		log.Info(cmd.Context(), "Keeping images in directory", "directory", imageDir)
+		return blob.NewFileStore(imageDir), nil
+	case imageStoreS3:
+		var settings blob.S3Settings
+		for param, value := range map[string]*string{
+			serverParamS3Endpoint:    &settings.Endpoint,
+			serverParamS3Region:      &settings.Region,
+			serverParamS3Bucket:      &settings.Bucket,
+			serverParamS3AccessKeyID: &settings.AccessKeyID,
+			serverParamS3SecretKey:   &settings.SecretAccessKey,
+		} {
+			if *value, err = cmd.Flags().GetString(param); err != nil {
+				return nil, errors.Wrapf(err, "unable to get %s", param)
+			}
+		}
+
+		log.Info(cmd.Context(), "Keeping images in S3 bucket", "endpoint", settings.Endpoint, "bucket", settings.Bucket)
+		return blob.NewS3Store(settings)
+	}
+
+	return nil, errors.Errorf("%s is not a valid image store", imageStore)
+}
+
 func tryCreateDefaultAdmin(cmd *cobra.Command, userClient storage.UserClient) error {
 	defaultAdminUser, err := cmd.Flags().GetString(serverParamAdminUser)
 	if err != nil {
@@ -0,0 +1,143 @@
+// Package drawing draws the winning tickets of raffles. The seed of every drawing is read from crypto/rand and the
+// winners are derived from it, so anyone with the seed and the list of tickets can repeat a drawing to audit it.
+package drawing
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/pkg/errors"
+)
+
+// SeedSize is the number of random bytes in a seed.
+const SeedSize = 32
+
+var (
+	ErrInvalidSeed  = errors.New("seed is not valid hex")
+	ErrNoTickets    = errors.New("no tickets were sold")
+	ErrWrongWinners = errors.New("winners do not match the seed and tickets")
+)
+
+// New draws the winners of a raffle with the number of prizes from the tickets that were bought, using a new random
+// seed. No ticket wins twice, so every ticket wins when there are fewer tickets than prizes. This will return
+// ErrNoTickets if no tickets were bought.
+func New(purchases []*model.TicketPurchase, prizes int) (*model.RaffleDrawing, error) {
+	tickets := Tickets(purchases)
+	if len(tickets) == 0 {
+		return nil, ErrNoTickets
+	}
+
+	seed, err := NewSeed()
+	if err != nil {
+		return nil, err
+	}
+
+	winners, err := Draw(seed, len(tickets), prizes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.RaffleDrawing{
+		Seed:    seed,
+		Tickets: tickets,
+		Winners: winners,
+		DrawnAt: time.Now().UTC(),
+	}, nil
+}
+
+// Verify repeats the drawing with its seed and tickets. This will return ErrWrongWinners if the winners of the drawing
+// are not the ones that were drawn.
+func Verify(drawing *model.RaffleDrawing) error {
+	winners, err := Draw(drawing.Seed, len(drawing.Tickets), len(drawing.Winners))
+	if err != nil {
+		return err
+	}
+
+	if len(winners) != len(drawing.Winners) {
+		return ErrWrongWinners
+	}
+	for i, winner := range winners {
+		if drawing.Winners[i] != winner {
+			return errors.Wrapf(ErrWrongWinners, "winner %d should be ticket %d", i+1, winner)
+		}
+	}
+	return nil
+}
+
+// NewSeed reads a random seed from crypto/rand and encodes it as hex.
+func NewSeed() (string, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return "", errors.Wrap(err, "unable to read random seed")
+	}
+	return hex.EncodeToString(seed), nil
+}
+
+// Tickets lists the username of the buyer of every ticket in the order the tickets were bought. The number of a ticket
+// is its index plus 1.
+func Tickets(purchases []*model.TicketPurchase) []string {
+	var tickets []string
+	for _, purchase := range purchases {
+		for i := 0; i < purchase.Quantity; i++ {
+			tickets = append(tickets, purchase.Buyer.Username)
+		}
+	}
+	return tickets
+}
+
+// Draw picks the numbers of the winning tickets out of the tickets numbered from 1, in the order they are drawn. The
+// same seed always draws the same winners. At most prizes tickets are drawn and no ticket wins twice.
+func Draw(seed string, tickets int, prizes int) ([]int, error) {
+	rawSeed, err := hex.DecodeString(seed)
+	if err != nil || len(rawSeed) == 0 {
+		return nil, errors.Wrapf(ErrInvalidSeed, "unable to decode seed '%s'", seed)
+	}
+	if prizes > tickets {
+		prizes = tickets
+	}
+
+	// The winners are the start of a shuffle of every ticket, so each remaining ticket is equally likely to be drawn.
+	numbers := make([]int, tickets)
+	for i := range numbers {
+		numbers[i] = i + 1
+	}
+	stream := &seedStream{seed: rawSeed}
+	for i := 0; i < prizes; i++ {
+		j := i + int(stream.uniform(uint64(tickets-i)))
+		numbers[i], numbers[j] = numbers[j], numbers[i]
+	}
+	return numbers[:prizes], nil
+}
+
+// seedStream produces an endless sequence of numbers from a seed by hashing the seed with a counter.
+type seedStream struct {
+	seed    []byte
+	counter uint64
+}
+
+// next returns the next number of the sequence.
+func (stream *seedStream) next() uint64 {
+	block := make([]byte, len(stream.seed)+8)
+	copy(block, stream.seed)
+	binary.BigEndian.PutUint64(block[len(stream.seed):], stream.counter)
+	stream.counter++
+
+	sum := sha256.Sum256(block)
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// uniform returns a number from 0 up to but not including n where every number is equally likely. Numbers from the
+// sequence that would favor the lower results are skipped.
+func (stream *seedStream) uniform(n uint64) uint64 {
+	limit := math.MaxUint64 - (math.MaxUint64%n+1)%n
+	for {
+		if value := stream.next(); value <= limit {
+			return value % n
+		}
+	}
+}
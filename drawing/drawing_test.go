@@ -0,0 +1,99 @@
+package drawing
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/stretchr/testify/require"
+)
+
+const testSeed = "8f14e45fceea167a5a36dedd4bea2543c4ca4238a0b923820dcc509a6f75849b"
+
+func TestNewSeedIsRandom(t *testing.T) {
+	seed, err := NewSeed()
+	require.NoError(t, err)
+	raw, err := hex.DecodeString(seed)
+	require.NoError(t, err)
+	require.Len(t, raw, SeedSize)
+
+	other, err := NewSeed()
+	require.NoError(t, err)
+	require.NotEqual(t, seed, other)
+}
+
+func TestTicketsNumbersTicketsInOrderBought(t *testing.T) {
+	tickets := Tickets([]*model.TicketPurchase{
+		{Buyer: &model.User{Username: "alice"}, Quantity: 2},
+		{Buyer: &model.User{Username: "bob"}, Quantity: 1},
+		{Buyer: &model.User{Username: "alice"}, Quantity: 1},
+	})
+	require.Equal(t, []string{"alice", "alice", "bob", "alice"}, tickets)
+}
+
+func TestDrawIsRepeatable(t *testing.T) {
+	winners, err := Draw(testSeed, 100, 3)
+	require.NoError(t, err)
+	require.Len(t, winners, 3)
+
+	again, err := Draw(testSeed, 100, 3)
+	require.NoError(t, err)
+	require.Equal(t, winners, again)
+
+	other, err := Draw("00"+testSeed[2:], 100, 3)
+	require.NoError(t, err)
+	require.NotEqual(t, winners, other)
+}
+
+func TestDrawNeverPicksTicketTwice(t *testing.T) {
+	winners, err := Draw(testSeed, 5, 10)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int{1, 2, 3, 4, 5}, winners)
+}
+
+func TestDrawIsUniform(t *testing.T) {
+	counts := make([]int, 4)
+	for i := 0; i < 4000; i++ {
+		seed := hex.EncodeToString([]byte{byte(i), byte(i >> 8)})
+		winners, err := Draw(seed, 4, 1)
+		require.NoError(t, err)
+		counts[winners[0]-1]++
+	}
+	for _, count := range counts {
+		require.InDelta(t, 1000, count, 150)
+	}
+}
+
+func TestDrawFailsOnInvalidSeed(t *testing.T) {
+	_, err := Draw("not hex", 10, 1)
+	require.ErrorIs(t, err, ErrInvalidSeed)
+
+	_, err = Draw("", 10, 1)
+	require.ErrorIs(t, err, ErrInvalidSeed)
+}
+
+func TestNewDrawsFromTicketsBought(t *testing.T) {
+	drawing, err := New([]*model.TicketPurchase{
+		{Buyer: &model.User{Username: "alice"}, Quantity: 3},
+		{Buyer: &model.User{Username: "bob"}, Quantity: 2},
+	}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "alice", "alice", "bob", "bob"}, drawing.Tickets)
+	require.Len(t, drawing.Winners, 2)
+	require.False(t, drawing.DrawnAt.IsZero())
+	require.NoError(t, Verify(drawing))
+
+	_, err = New(nil, 1)
+	require.ErrorIs(t, err, ErrNoTickets)
+}
+
+func TestVerifyDetectsChangedWinners(t *testing.T) {
+	winners, err := Draw(testSeed, 10, 2)
+	require.NoError(t, err)
+	drawing := &model.RaffleDrawing{
+		Seed:    testSeed,
+		Tickets: make([]string, 10),
+		Winners: []int{winners[1], winners[0]},
+	}
+	require.ErrorIs(t, Verify(drawing), ErrWrongWinners)
+}
@@ -0,0 +1,184 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+)
+
+const backplaneBufferSize = 64
+
+// Backplane shares published events between every instance of the server that is connected to it.
+type Backplane interface {
+
+	// Publish sends the message to every instance connected to the backplane, including this one.
+	Publish(ctx context.Context, message []byte) error
+
+	// Subscribe returns a channel that receives every message sent to the backplane. The channel is closed once the
+	// context is done.
+	Subscribe(ctx context.Context) (<-chan []byte, error)
+
+	// Close releases any resources held by the backplane.
+	Close() error
+}
+
+// envelope is how an event is sent across the backplane. Origin identifies the Bus that published the event so it is
+// not delivered to that Bus's subscribers a second time, and ID is the ID that Bus assigned to the event.
+type envelope struct {
+	Origin  string
+	ID      uint64
+	Type    Type
+	Payload interface{}
+}
+
+func init() {
+	gob.Register(&BidPlaced{})
+	gob.Register(&ItemCreated{})
+	gob.Register(&ItemUpdated{})
+	gob.Register(&ItemDeleted{})
+	gob.Register(&AuctionClosed{})
+	gob.Register(&PledgeRecorded{})
+	gob.Register(&PledgeRemoved{})
+	gob.Register(&RaffleDrawn{})
+}
+
+func encodeEnvelope(env *envelope) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(env); err != nil {
+		return nil, errors.Wrapf(err, "unable to encode '%s' event", env.Type)
+	}
+	return buffer.Bytes(), nil
+}
+
+func decodeEnvelope(message []byte) (*envelope, error) {
+	var env envelope
+	if err := gob.NewDecoder(bytes.NewReader(message)).Decode(&env); err != nil {
+		return nil, errors.Wrap(err, "unable to decode event")
+	}
+	return &env, nil
+}
+
+// MemoryBackplane shares messages between buses in the same process. It is suitable when only one instance of the
+// server is running.
+type MemoryBackplane struct {
+	lock        sync.RWMutex
+	subscribers map[chan []byte]<-chan struct{}
+}
+
+// NewMemoryBackplane creates an empty MemoryBackplane.
+func NewMemoryBackplane() *MemoryBackplane {
+	return &MemoryBackplane{
+		subscribers: make(map[chan []byte]<-chan struct{}),
+	}
+}
+
+// Publish sends the message to every subscriber.
+func (mb *MemoryBackplane) Publish(ctx context.Context, message []byte) error {
+	mb.lock.RLock()
+	defer mb.lock.RUnlock()
+
+	for subscriber, done := range mb.subscribers {
+		select {
+		case subscriber <- message:
+		case <-done:
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "unable to publish message")
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a channel that receives every published message until the context is done.
+func (mb *MemoryBackplane) Subscribe(ctx context.Context) (<-chan []byte, error) {
+	subscriber := make(chan []byte, backplaneBufferSize)
+
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+	mb.subscribers[subscriber] = ctx.Done()
+
+	go func() {
+		<-ctx.Done()
+
+		mb.lock.Lock()
+		defer mb.lock.Unlock()
+		delete(mb.subscribers, subscriber)
+		close(subscriber)
+	}()
+
+	return subscriber, nil
+}
+
+// Close does nothing since there are no resources to release.
+func (mb *MemoryBackplane) Close() error {
+	return nil
+}
+
+// RedisBackplane shares messages between instances using Redis pub/sub.
+type RedisBackplane struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewRedisBackplane creates a RedisBackplane that publishes to the channel on the Redis server at the address.
+func NewRedisBackplane(address string, channel string) *RedisBackplane {
+	return &RedisBackplane{
+		client:  redis.NewClient(&redis.Options{Addr: address}),
+		channel: channel,
+	}
+}
+
+// Publish sends the message to the Redis channel.
+func (rb *RedisBackplane) Publish(ctx context.Context, message []byte) error {
+	if err := rb.client.Publish(ctx, rb.channel, message).Err(); err != nil {
+		return errors.Wrapf(err, "unable to publish to redis channel '%s'", rb.channel)
+	}
+	return nil
+}
+
+// Subscribe listens to the Redis channel until the context is done. This does not return until the subscription has
+// been confirmed by Redis so no messages published afterwards are missed.
+func (rb *RedisBackplane) Subscribe(ctx context.Context) (<-chan []byte, error) {
+	pubsub := rb.client.Subscribe(ctx, rb.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, errors.Wrapf(err, "unable to subscribe to redis channel '%s'", rb.channel)
+	}
+
+	subscriber := make(chan []byte, backplaneBufferSize)
+	go func() {
+		defer close(subscriber)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				select {
+				case subscriber <- []byte(message.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return subscriber, nil
+}
+
+// Close closes the connection to Redis.
+func (rb *RedisBackplane) Close() error {
+	if err := rb.client.Close(); err != nil {
+		return errors.Wrap(err, "unable to close redis client")
+	}
+	return nil
+}
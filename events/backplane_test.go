@@ -0,0 +1,136 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBackplaneSharesEventsBetweenBuses(t *testing.T) {
+	testBackplaneSharesEventsBetweenBuses(t, NewMemoryBackplane())
+}
+
+func TestRedisBackplaneSharesEventsBetweenBuses(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+
+	backplane := NewRedisBackplane(server.Addr(), "events")
+	defer backplane.Close()
+
+	testBackplaneSharesEventsBetweenBuses(t, backplane)
+}
+
+func TestRedisBackplaneSubscribeErrorsWhenServerUnavailable(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	address := server.Addr()
+	server.Close()
+
+	backplane := NewRedisBackplane(address, "events")
+	defer backplane.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = backplane.Subscribe(ctx)
+	require.Error(t, err)
+}
+
+func TestMemoryBackplaneClosesSubscriptionWhenContextDone(t *testing.T) {
+	backplane := NewMemoryBackplane()
+	ctx, cancel := context.WithCancel(context.Background())
+	messages, err := backplane.Subscribe(ctx)
+	require.NoError(t, err)
+
+	cancel()
+	_, ok := <-messages
+	require.False(t, ok)
+	require.NoError(t, backplane.Publish(context.Background(), []byte("message")))
+}
+
+func testBackplaneSharesEventsBetweenBuses(t *testing.T, backplane Backplane) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	firstBus := NewBus(DefaultHistorySize)
+	secondBus := NewBus(DefaultHistorySize)
+	require.NoError(t, firstBus.Connect(ctx, backplane))
+	require.NoError(t, secondBus.Connect(ctx, backplane))
+
+	firstSub := firstBus.Subscribe()
+	defer firstSub.Close()
+	secondSub := secondBus.Subscribe()
+	defer secondSub.Close()
+
+	payload := &BidPlaced{
+		ItemName:       "item",
+		Username:       "bidder",
+		Amount:         model.Money{Amount: 1050, Currency: "USD"},
+		PreviousBidder: "previous",
+	}
+	firstBus.Publish(TypeBidPlaced, payload)
+	secondBus.Publish(TypeItemDeleted, &ItemDeleted{ItemName: "item"})
+
+	for _, sub := range []*Subscription{firstSub, secondSub} {
+		events := []*Event{readEvent(t, sub), readEvent(t, sub)}
+		if events[0].Type != TypeBidPlaced {
+			events[0], events[1] = events[1], events[0]
+		}
+
+		require.EqualValues(t, TypeBidPlaced, events[0].Type)
+		require.Equal(t, payload, events[0].Payload)
+		require.EqualValues(t, TypeItemDeleted, events[1].Type)
+		require.Equal(t, &ItemDeleted{ItemName: "item"}, events[1].Payload)
+	}
+
+	select {
+	case event := <-firstSub.Events():
+		require.Failf(t, "unexpected event", "received duplicate event %s", event.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEventsKeepGlobalIDAcrossBuses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backplane := NewMemoryBackplane()
+	firstBus := NewBus(DefaultHistorySize)
+	secondBus := NewBus(DefaultHistorySize)
+	require.NoError(t, firstBus.Connect(ctx, backplane))
+	require.NoError(t, secondBus.Connect(ctx, backplane))
+	secondSub := secondBus.Subscribe()
+	defer secondSub.Close()
+
+	secondBus.Publish(TypeItemDeleted, &ItemDeleted{ItemName: "other"})
+	readEvent(t, secondSub)
+	first := firstBus.Publish(TypeBidPlaced, &BidPlaced{ItemName: "first"})
+	received := readEvent(t, secondSub)
+	firstBus.Publish(TypeBidPlaced, &BidPlaced{ItemName: "second"})
+	readEvent(t, secondSub)
+
+	require.EqualValues(t, first.GlobalID, received.GlobalID)
+	require.NotEqual(t, first.ID, received.ID)
+	require.True(t, first.Local)
+	require.False(t, received.Local)
+
+	missed, sub, err := secondBus.SubscribeAfterGlobalID(first.GlobalID)
+	require.NoError(t, err)
+	defer sub.Close()
+	require.Len(t, missed, 1)
+	require.Equal(t, &BidPlaced{ItemName: "second"}, missed[0].Payload)
+}
+
+func readEvent(t *testing.T, sub *Subscription) *Event {
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for event")
+		return nil
+	}
+}
@@ -0,0 +1,241 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/pkg/errors"
+)
+
+// DefaultHistorySize is the number of events a Bus retains so late subscribers can catch up.
+const DefaultHistorySize = 1000
+
+const subscriptionBufferSize = 64
+
+// ErrInvalidGlobalID is returned when a global event ID was not assigned by a Bus.
+var ErrInvalidGlobalID = errors.New("invalid global event ID")
+
+// Subscription receives every event published on the Bus after it was created.
+type Subscription struct {
+	id     uint64
+	bus    *Bus
+	events chan *Event
+}
+
+// Events returns the channel the events are delivered on. The channel is closed when the subscription is closed or
+// when the subscriber falls too far behind to keep up.
+func (sub *Subscription) Events() <-chan *Event {
+	return sub.events
+}
+
+// Close stops the subscription from receiving any more events.
+func (sub *Subscription) Close() {
+	sub.bus.unsubscribe(sub.id)
+}
+
+// Bus is an in-process publisher of events that fans out every event to all subscribers. A Bus can be connected to a
+// Backplane to also receive the events published by other instances of the server. Event IDs are only meaningful to
+// the Bus that assigned them, while global event IDs are meaningful to every Bus connected to the same Backplane.
+type Bus struct {
+	lock          sync.RWMutex
+	origin        string
+	lastEventID   uint64
+	history       []*Event
+	historySize   int
+	nextSubID     uint64
+	subscriptions map[uint64]*Subscription
+
+	backplane    Backplane
+	backplaneCtx context.Context
+}
+
+// NewBus creates a Bus that retains up to historySize events for replaying.
+func NewBus(historySize int) *Bus {
+	origin := make([]byte, 8)
+	if _, err := rand.Read(origin); err != nil {
+		panic(err)
+	}
+
+	return &Bus{
+		origin:        hex.EncodeToString(origin),
+		historySize:   historySize,
+		subscriptions: make(map[uint64]*Subscription),
+	}
+}
+
+// Connect shares every event published on this Bus with the backplane and delivers events published by other
+// instances to this Bus's subscribers until the context is done.
+func (bus *Bus) Connect(ctx context.Context, backplane Backplane) error {
+	messages, err := backplane.Subscribe(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to subscribe to backplane")
+	}
+
+	bus.lock.Lock()
+	bus.backplane = backplane
+	bus.backplaneCtx = ctx
+	bus.lock.Unlock()
+
+	go func() {
+		for message := range messages {
+			env, err := decodeEnvelope(message)
+			if err != nil {
+				log.Error(ctx, "unable to read event from backplane", "err", err)
+				continue
+			}
+
+			if env.Origin == bus.origin {
+				continue
+			}
+			bus.deliver(env.Type, env.Payload, env.Origin, env.ID)
+		}
+	}()
+
+	return nil
+}
+
+// Publish assigns the next ID to the event and sends it to every subscriber and to the backplane if connected.
+func (bus *Bus) Publish(eventType Type, payload interface{}) *Event {
+	event := bus.deliver(eventType, payload, "", 0)
+
+	bus.lock.RLock()
+	backplane, ctx := bus.backplane, bus.backplaneCtx
+	bus.lock.RUnlock()
+
+	if backplane != nil {
+		message, err := encodeEnvelope(&envelope{
+			Origin:  bus.origin,
+			ID:      event.ID,
+			Type:    eventType,
+			Payload: payload,
+		})
+		if err == nil {
+			err = backplane.Publish(ctx, message)
+		}
+		if err != nil {
+			log.Error(ctx, "unable to share event with other instances", "eventType", eventType, "err", err)
+		}
+	}
+
+	return event
+}
+
+// deliver assigns the next ID to the event and sends it to every local subscriber. The origin and origin ID are those of
+// the instance that published the event, or empty when it was published on this Bus.
+func (bus *Bus) deliver(eventType Type, payload interface{}, origin string, originID uint64) *Event {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+
+	bus.lastEventID++
+	event := &Event{
+		ID:      bus.lastEventID,
+		Type:    eventType,
+		Payload: payload,
+	}
+	event.Local = origin == ""
+	if event.Local {
+		origin, originID = bus.origin, event.ID
+	}
+	event.GlobalID = globalID(origin, originID)
+
+	if bus.historySize > 0 {
+		if len(bus.history) >= bus.historySize {
+			bus.history = bus.history[1:]
+		}
+		bus.history = append(bus.history, event)
+	}
+
+	for id, sub := range bus.subscriptions {
+		select {
+		case sub.events <- event:
+		default:
+			// The subscriber cannot keep up. Closing it lets the subscriber reconnect and replay from its last event.
+			close(sub.events)
+			delete(bus.subscriptions, id)
+		}
+	}
+
+	return event
+}
+
+// Subscribe creates a Subscription that receives every event published from now on.
+func (bus *Bus) Subscribe() *Subscription {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+
+	return bus.subscribe()
+}
+
+// SubscribeAfter creates a Subscription and returns all retained events with an ID greater than lastEventID. The
+// returned events should be handled before reading from the Subscription so nothing is missed or repeated.
+func (bus *Bus) SubscribeAfter(lastEventID uint64) ([]*Event, *Subscription) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+
+	var missed []*Event
+	for _, event := range bus.history {
+		if event.ID > lastEventID {
+			missed = append(missed, event)
+		}
+	}
+
+	return missed, bus.subscribe()
+}
+
+// SubscribeAfterGlobalID is like SubscribeAfter but the last event is identified by its global ID, so it may have been
+// received from any instance sharing the Backplane. Every retained event is returned when the last event is no longer
+// retained. ErrInvalidGlobalID is returned if the global ID is malformed.
+func (bus *Bus) SubscribeAfterGlobalID(lastGlobalID string) ([]*Event, *Subscription, error) {
+	separator := strings.LastIndex(lastGlobalID, "-")
+	if separator <= 0 {
+		return nil, nil, errors.Wrapf(ErrInvalidGlobalID, "'%s' does not have an origin", lastGlobalID)
+	}
+	if _, err := strconv.ParseUint(lastGlobalID[separator+1:], 10, 64); err != nil {
+		return nil, nil, errors.Wrapf(ErrInvalidGlobalID, "'%s' does not have an ID", lastGlobalID)
+	}
+
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+
+	missed := bus.history
+	for i, event := range bus.history {
+		if event.GlobalID == lastGlobalID {
+			missed = bus.history[i+1:]
+			break
+		}
+	}
+
+	return append([]*Event(nil), missed...), bus.subscribe(), nil
+}
+
+func (bus *Bus) subscribe() *Subscription {
+	bus.nextSubID++
+	sub := &Subscription{
+		id:     bus.nextSubID,
+		bus:    bus,
+		events: make(chan *Event, subscriptionBufferSize),
+	}
+	bus.subscriptions[sub.id] = sub
+	return sub
+}
+
+func (bus *Bus) unsubscribe(id uint64) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+
+	if sub, ok := bus.subscriptions[id]; ok {
+		close(sub.events)
+		delete(bus.subscriptions, id)
+	}
+}
+
+// globalID combines the origin of the Bus that published an event with the ID that Bus assigned to it.
+func globalID(origin string, id uint64) string {
+	return fmt.Sprintf("%s-%d", origin, id)
+}
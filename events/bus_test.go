@@ -0,0 +1,130 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishAssignsIncreasingIDs(t *testing.T) {
+	bus := NewBus(DefaultHistorySize)
+	first := bus.Publish(TypeBidPlaced, &BidPlaced{})
+	second := bus.Publish(TypeBidPlaced, &BidPlaced{})
+
+	require.EqualValues(t, 1, first.ID)
+	require.EqualValues(t, 2, second.ID)
+}
+
+func TestSubscribeReceivesPublishedEvents(t *testing.T) {
+	bus := NewBus(DefaultHistorySize)
+	subs := []*Subscription{bus.Subscribe(), bus.Subscribe()}
+	payload := &BidPlaced{ItemName: "item", Username: "user", Amount: model.Money{Amount: 1000, Currency: "USD"}}
+	bus.Publish(TypeBidPlaced, payload)
+
+	for _, sub := range subs {
+		event := <-sub.Events()
+		require.EqualValues(t, TypeBidPlaced, event.Type)
+		require.Equal(t, payload, event.Payload)
+		sub.Close()
+	}
+}
+
+func TestSubscribeDoesNotReceiveEarlierEvents(t *testing.T) {
+	bus := NewBus(DefaultHistorySize)
+	bus.Publish(TypeBidPlaced, &BidPlaced{})
+	sub := bus.Subscribe()
+	defer sub.Close()
+
+	select {
+	case event := <-sub.Events():
+		require.Failf(t, "unexpected event", "received event %d", event.ID)
+	default:
+	}
+}
+
+func TestSubscribeAfterReturnsMissedEvents(t *testing.T) {
+	bus := NewBus(DefaultHistorySize)
+	for i := 0; i < 5; i++ {
+		bus.Publish(TypeBidPlaced, &BidPlaced{Amount: model.Money{Amount: int64(i), Currency: "USD"}})
+	}
+
+	missed, sub := bus.SubscribeAfter(3)
+	defer sub.Close()
+
+	require.Len(t, missed, 2)
+	require.EqualValues(t, 4, missed[0].ID)
+	require.EqualValues(t, 5, missed[1].ID)
+}
+
+func TestSubscribeAfterOnlyReturnsRetainedHistory(t *testing.T) {
+	bus := NewBus(2)
+	for i := 0; i < 5; i++ {
+		bus.Publish(TypeBidPlaced, &BidPlaced{Amount: model.Money{Amount: int64(i), Currency: "USD"}})
+	}
+
+	missed, sub := bus.SubscribeAfter(0)
+	defer sub.Close()
+
+	require.Len(t, missed, 2)
+	require.EqualValues(t, 4, missed[0].ID)
+}
+
+func TestSubscribeAfterGlobalIDReturnsEventsAfterIt(t *testing.T) {
+	bus := NewBus(DefaultHistorySize)
+	var published []*Event
+	for i := 0; i < 3; i++ {
+		published = append(published, bus.Publish(TypeBidPlaced, &BidPlaced{}))
+	}
+
+	missed, sub, err := bus.SubscribeAfterGlobalID(published[0].GlobalID)
+	require.NoError(t, err)
+	defer sub.Close()
+	require.Equal(t, published[1:], missed)
+	require.True(t, missed[0].Local)
+}
+
+func TestSubscribeAfterGlobalIDReturnsRetainedHistoryForUnknownEvent(t *testing.T) {
+	bus := NewBus(2)
+	for i := 0; i < 3; i++ {
+		bus.Publish(TypeBidPlaced, &BidPlaced{})
+	}
+
+	missed, sub, err := bus.SubscribeAfterGlobalID("0123456789abcdef-1")
+	require.NoError(t, err)
+	defer sub.Close()
+	require.Len(t, missed, 2)
+}
+
+func TestSubscribeAfterGlobalIDRejectsMalformedID(t *testing.T) {
+	bus := NewBus(DefaultHistorySize)
+	for _, globalID := range []string{"1", "-1", "origin-", "origin-one"} {
+		_, _, err := bus.SubscribeAfterGlobalID(globalID)
+		require.ErrorIs(t, err, ErrInvalidGlobalID, globalID)
+	}
+}
+
+func TestCloseStopsDelivery(t *testing.T) {
+	bus := NewBus(DefaultHistorySize)
+	sub := bus.Subscribe()
+	sub.Close()
+	bus.Publish(TypeBidPlaced, &BidPlaced{})
+
+	_, ok := <-sub.Events()
+	require.False(t, ok)
+}
+
+func TestSlowSubscriberIsClosed(t *testing.T) {
+	bus := NewBus(DefaultHistorySize)
+	sub := bus.Subscribe()
+	for i := 0; i <= subscriptionBufferSize; i++ {
+		bus.Publish(TypeBidPlaced, &BidPlaced{})
+	}
+
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	require.EqualValues(t, subscriptionBufferSize, received)
+	sub.Close()
+}
@@ -0,0 +1,158 @@
+package events
+
+import (
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+)
+
+// Type defines the kind of event that was published.
+type Type string
+
+const (
+	TypeBidPlaced      Type = "BidPlaced"
+	TypeItemCreated    Type = "ItemCreated"
+	TypeItemUpdated    Type = "ItemUpdated"
+	TypeItemDeleted    Type = "ItemDeleted"
+	TypeAuctionClosed  Type = "AuctionClosed"
+	TypePledgeRecorded Type = "PledgeRecorded"
+	TypePledgeRemoved  Type = "PledgeRemoved"
+	TypeRaffleDrawn    Type = "RaffleDrawn"
+)
+
+// Event is a single occurrence published on the Bus. The ID increases with every event the Bus delivers, including the
+// events of other instances, and is only meaningful to that Bus. The GlobalID is made up of the origin of the Bus that
+// published the event and the ID it assigned, so it identifies the event on every instance sharing a Backplane.
+type Event struct {
+	ID       uint64
+	GlobalID string
+	Type     Type
+	Payload  interface{}
+
+	// Local is true when the event was published on this Bus and false when it was published by another instance.
+	Local bool
+}
+
+// BidPlaced is published when a new bid is successfully placed on an item.
+type BidPlaced struct {
+	ItemID   uint64      `json:"itemId"`
+	ItemName string      `json:"itemName"`
+	Username string      `json:"username"`
+	Amount   model.Money `json:"amount"`
+
+	// LotID and LotName are set when the item is sold in a lot, in which case the bid is on the whole lot.
+	LotID   uint64 `json:"lotId,omitempty"`
+	LotName string `json:"lotName,omitempty"`
+
+	// PreviousBidder is the username of the bidder who lost their winning place to this bid, which is the previous
+	// highest bidder of an item won by a single bidder. It is empty if nobody lost their place and is never sent to
+	// clients.
+	PreviousBidder string `json:"-"`
+
+	// MinimumBid is the least the previous bidder has to bid to win back a place, which is just over the lowest winning
+	// bid after this bid was placed. It is only set along with PreviousBidder and is never sent to clients.
+	MinimumBid model.Money `json:"-"`
+
+	// NoticeID is the ID of the model.OutbidNotice stored for the previous bidder, which is removed by the instance that
+	// tells them. It is only set along with PreviousBidder and is never sent to clients.
+	NoticeID uint64 `json:"-"`
+}
+
+// ItemCreated is published when a new item is added to the auction.
+type ItemCreated struct {
+	ItemID          uint64       `json:"id"`
+	ItemName        string       `json:"name"`
+	ImageRef        string       `json:"image,omitempty"`
+	Description     string       `json:"description,omitempty"`
+	Category        string       `json:"category,omitempty"`
+	Tags            []string     `json:"tags,omitempty"`
+	DonorName       string       `json:"donorName,omitempty"`
+	DonorBusiness   string       `json:"donorBusiness,omitempty"`
+	FairMarketValue *model.Money `json:"fairMarketValue,omitempty"`
+	Quantity        int          `json:"quantity,omitempty"`
+	ClosesAt        *time.Time   `json:"closesAt,omitempty"`
+}
+
+// ItemUpdated is published when an item is changed. Only the fields that were changed are set besides the ID and name.
+// PreviousName is set when the item was renamed and Cleared lists the fields that were removed.
+type ItemUpdated struct {
+	ItemID          uint64       `json:"id"`
+	ItemName        string       `json:"name"`
+	PreviousName    string       `json:"previousName,omitempty"`
+	ImageRef        string       `json:"image,omitempty"`
+	Description     string       `json:"description,omitempty"`
+	Category        string       `json:"category,omitempty"`
+	Tags            []string     `json:"tags,omitempty"`
+	DonorName       string       `json:"donorName,omitempty"`
+	DonorBusiness   string       `json:"donorBusiness,omitempty"`
+	FairMarketValue *model.Money `json:"fairMarketValue,omitempty"`
+	Quantity        int          `json:"quantity,omitempty"`
+	ClosesAt        *time.Time   `json:"closesAt,omitempty"`
+	Cleared         []string     `json:"cleared,omitempty"`
+}
+
+// ItemDeleted is published when an item is removed from the auction.
+type ItemDeleted struct {
+	ItemID   uint64 `json:"id"`
+	ItemName string `json:"name"`
+}
+
+// AuctionClosed is published when bidding on an item ends because its closing time passed.
+type AuctionClosed struct {
+	ItemID   uint64    `json:"id"`
+	ItemName string    `json:"name"`
+	ClosedAt time.Time `json:"closedAt"`
+
+	// LotID and LotName are set when the item is sold in a lot.
+	LotID   uint64 `json:"lotId,omitempty"`
+	LotName string `json:"lotName,omitempty"`
+}
+
+// PledgeRecorded is published when a bidder pledges a donation at one of the appeal levels. The totals include the
+// pledge.
+type PledgeRecorded struct {
+	PledgeID  uint64      `json:"pledgeId"`
+	LevelID   uint64      `json:"levelId"`
+	LevelName string      `json:"levelName"`
+	Username  string      `json:"username"`
+	Amount    model.Money `json:"amount"`
+	DonationTotals
+}
+
+// PledgeRemoved is published when a pledge is taken back, usually because it was recorded by mistake. The totals no
+// longer include the pledge.
+type PledgeRemoved struct {
+	PledgeID  uint64      `json:"pledgeId"`
+	LevelID   uint64      `json:"levelId"`
+	LevelName string      `json:"levelName"`
+	Username  string      `json:"username"`
+	Amount    model.Money `json:"amount"`
+	DonationTotals
+}
+
+// DonationTotals are the running totals of the appeal shown while pledges come in.
+type DonationTotals struct {
+	// LevelPledges and LevelTotal count the pledges at the level of the pledge and the sum of their amounts.
+	LevelPledges int         `json:"levelPledges"`
+	LevelTotal   model.Money `json:"levelTotal"`
+
+	// Pledges and Total count every pledge and the sum of their amounts.
+	Pledges int         `json:"pledges"`
+	Total   model.Money `json:"total"`
+}
+
+// RaffleDrawn is published when the winning tickets of a raffle are drawn.
+type RaffleDrawn struct {
+	RaffleID   uint64 `json:"raffleId"`
+	RaffleName string `json:"raffleName"`
+
+	// Winners are in the order they were drawn.
+	Winners []RaffleWinner `json:"winners"`
+}
+
+// RaffleWinner is a winning ticket of a raffle and who bought it.
+type RaffleWinner struct {
+	Ticket      int    `json:"ticket"`
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName,omitempty"`
+}
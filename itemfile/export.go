@@ -0,0 +1,92 @@
+package itemfile
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/pkg/errors"
+)
+
+// exportColumns are the columns of an exported CSV file in order.
+var exportColumns = []string{
+	columnID,
+	columnName,
+	columnDescription,
+	columnImage,
+	columnCategory,
+	columnTags,
+	columnDonorName,
+	columnDonorBusiness,
+	columnFairMarketValue,
+	columnQuantity,
+	columnClosesAt,
+	columnCurrentBid,
+	columnHighBidder,
+	columnHighBidderName,
+}
+
+// WriteCSV writes every item with its highest winning bid as a CSV file in the order the items were created. The fair
+// market value and current bid are written with their currency, e.g. "12.50 USD". The fair market value is empty when
+// the donor did not state one and the current bid is empty along with the high bidder for items without bids. The
+// file can be imported again since importing ignores the columns about bids.
+func WriteCSV(w io.Writer, items []*model.AuctionItem, winningBids []*model.AuctionBid) error {
+	highestBidByItem := make(map[uint64]*model.AuctionBid, len(winningBids))
+	for _, bid := range winningBids {
+		if highestBid, ok := highestBidByItem[bid.Item.ID]; !ok || bid.BidAmount.Amount > highestBid.BidAmount.Amount {
+			highestBidByItem[bid.Item.ID] = bid
+		}
+	}
+
+	sorted := make([]*model.AuctionItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportColumns); err != nil {
+		return errors.Wrap(err, "unable to write CSV header")
+	}
+	for _, item := range sorted {
+		var fairMarketValue, closesAt, currentBid, highBidder, highBidderName string
+		if item.FairMarketValue.Amount != 0 {
+			fairMarketValue = item.FairMarketValue.String()
+		}
+		if !item.ClosesAt.IsZero() {
+			closesAt = item.ClosesAt.UTC().Format(time.RFC3339)
+		}
+		if bid, ok := highestBidByItem[item.ID]; ok {
+			currentBid = bid.BidAmount.String()
+			highBidder = bid.Bidder.Username
+			highBidderName = bid.Bidder.DisplayName
+		}
+
+		err := writer.Write([]string{
+			strconv.FormatUint(item.ID, 10),
+			item.Name,
+			item.Description,
+			item.ImageRef,
+			item.Category,
+			strings.Join(item.Tags, TagSeparator),
+			item.DonorName,
+			item.DonorBusiness,
+			fairMarketValue,
+			strconv.Itoa(item.Quantity),
+			closesAt,
+			currentBid,
+			highBidder,
+			highBidderName,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "unable to write item '%s'", item.Name)
+		}
+	}
+
+	writer.Flush()
+	return errors.Wrap(writer.Error(), "unable to write CSV file")
+}
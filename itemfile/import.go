@@ -0,0 +1,158 @@
+package itemfile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+// Action is what importing a row did to the item with its name.
+type Action string
+
+const (
+	ActionCreated Action = "created"
+	ActionUpdated Action = "updated"
+)
+
+// ImportedRow is a row that was imported.
+type ImportedRow struct {
+	Number int
+	Action Action
+
+	// Item is the item as it was stored.
+	Item *model.AuctionItem
+
+	// Previous is the item before it was updated. It is nil when the item was created.
+	Previous *model.AuctionItem
+}
+
+// ImportResult describes what an import did, or would have done for a dry run.
+type ImportResult struct {
+	// Rows are the rows in the order of the file. They are empty when Errors is not.
+	Rows []*ImportedRow
+
+	// Errors are the reasons the rows could not be imported. Nothing is imported when there are any.
+	Errors []*RowError
+
+	DryRun bool
+}
+
+// Count returns how many rows had the action.
+func (result *ImportResult) Count(action Action) int {
+	count := 0
+	for _, row := range result.Rows {
+		if row.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// errRollback rolls back the transaction of an import that must not be kept.
+var errRollback = errors.New("import rolled back")
+
+// Import creates or updates an item for every row within a single transaction. An item is updated when an item with
+// the name of the row already exists, ignoring case. Every field of an updated item is replaced by the row except the
+// image, which is kept when the row does not have one. The categories of the rows must already exist.
+//
+// Nothing is imported if any row is invalid, in which case the result has the errors of every invalid row, including
+// the rowErrors returned by Read. A dry run checks every row and reports what would be done without keeping any change.
+func Import(
+	ctx context.Context,
+	transactor storage.Transactor,
+	rows []*Row,
+	rowErrors []*RowError,
+	dryRun bool,
+) (*ImportResult, error) {
+	result := &ImportResult{DryRun: dryRun}
+	err := transactor.RunInTx(ctx, func(ctx context.Context, clients *storage.Clients) error {
+		result.Rows = nil
+		result.Errors = append([]*RowError(nil), rowErrors...)
+
+		categories, err := clients.Categories.GetAll(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to get categories")
+		}
+		categoryNames := make(map[string]bool, len(categories))
+		for _, category := range categories {
+			categoryNames[strings.ToLower(category.Name)] = true
+		}
+
+		deleted, err := clients.Items.GetDeleted(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to get deleted items")
+		}
+		deletedNames := make(map[string]bool, len(deleted))
+		for _, item := range deleted {
+			deletedNames[strings.ToLower(item.Name)] = true
+		}
+
+		for _, row := range rows {
+			var message string
+			switch {
+			case row.Item.Category != "" && !categoryNames[strings.ToLower(row.Item.Category)]:
+				message = fmt.Sprintf("category '%s' does not exist", row.Item.Category)
+			case deletedNames[strings.ToLower(row.Item.Name)]:
+				message = fmt.Sprintf("item '%s' was deleted and must be restored before it can be imported", row.Item.Name)
+			}
+			if message != "" {
+				result.Errors = append(result.Errors, &RowError{Number: row.Number, Message: message})
+			}
+		}
+		if len(result.Errors) > 0 {
+			sortRowErrors(result.Errors)
+			return errRollback
+		}
+
+		for _, row := range rows {
+			imported, err := importRow(ctx, clients.Items, row)
+			if err != nil {
+				return errors.Wrapf(err, "unable to import row %d", row.Number)
+			}
+			result.Rows = append(result.Rows, imported)
+		}
+
+		if dryRun {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, errors.Wrap(err, "unable to import items")
+	}
+	if len(result.Errors) > 0 {
+		result.Rows = nil
+	}
+	return result, nil
+}
+
+// importRow creates the item of the row or replaces the item that already has its name.
+func importRow(ctx context.Context, itemClient storage.AuctionItemClient, row *Row) (*ImportedRow, error) {
+	item := *row.Item
+	existing, err := itemClient.Get(ctx, item.Name)
+	if errors.Is(err, storage.ErrEntityNotFound) {
+		if err = itemClient.Create(ctx, &item); err != nil {
+			return nil, errors.Wrapf(err, "unable to create item '%s'", item.Name)
+		}
+		return &ImportedRow{Number: row.Number, Action: ActionCreated, Item: &item}, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "unable to get item '%s'", item.Name)
+	}
+
+	item.ID = existing.ID
+	item.Version = existing.Version
+	if item.ImageRef == "" {
+		item.ImageRef = existing.ImageRef
+	}
+	if item.Quantity == 0 {
+		item.Quantity = existing.Quantity
+	}
+	if err = itemClient.Replace(ctx, &item); err != nil {
+		return nil, errors.Wrapf(err, "unable to update item '%s'", item.Name)
+	}
+	return &ImportedRow{Number: row.Number, Action: ActionUpdated, Item: &item, Previous: existing}, nil
+}
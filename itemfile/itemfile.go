@@ -0,0 +1,307 @@
+// Package itemfile reads and writes auction items as CSV and JSON files so donated items can be added in bulk and the
+// results of the auction can be taken into a spreadsheet.
+package itemfile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/pkg/errors"
+)
+
+// Format is the kind of file the items are kept in.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// TagSeparator separates the tags of an item within a CSV cell.
+const TagSeparator = ";"
+
+// The columns of a CSV file. Importing ignores the columns that are only written by exporting so an exported file can
+// be imported again.
+const (
+	columnID              = "id"
+	columnName            = "name"
+	columnDescription     = "description"
+	columnImage           = "image"
+	columnCategory        = "category"
+	columnTags            = "tags"
+	columnDonorName       = "donorName"
+	columnDonorBusiness   = "donorBusiness"
+	columnFairMarketValue = "fairMarketValue"
+	columnQuantity        = "quantity"
+	columnClosesAt        = "closesAt"
+	columnCurrentBid      = "currentBid"
+	columnHighBidder      = "highBidder"
+	columnHighBidderName  = "highBidderName"
+)
+
+// ErrInvalidFile is returned when the file as a whole cannot be read, such as a CSV file without a name column.
+var ErrInvalidFile = errors.New("invalid item file")
+
+// FormatOf determines the format of the file by its extension. This will return ErrInvalidFile if the extension is not
+// .csv or .json.
+func FormatOf(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", errors.Wrapf(ErrInvalidFile, "'%s' is not a .csv or .json file", path)
+	}
+}
+
+// Row is an item read from a file.
+type Row struct {
+	// Number is the position of the row in the file starting at 1. The header of a CSV file is not counted.
+	Number int
+
+	Item *model.AuctionItem
+}
+
+// RowError describes why a row of a file cannot be imported.
+type RowError struct {
+	Number  int
+	Message string
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Number, e.Message)
+}
+
+// jsonRow is an item of a JSON file. It has the same members as the body that creates an item through the API.
+type jsonRow struct {
+	Name            string      `json:"name"`
+	ImageRef        string      `json:"image"`
+	Description     string      `json:"description"`
+	Category        string      `json:"category"`
+	Tags            []string    `json:"tags"`
+	DonorName       string      `json:"donorName"`
+	DonorBusiness   string      `json:"donorBusiness"`
+	FairMarketValue model.Money `json:"fairMarketValue"`
+	Quantity        int         `json:"quantity"`
+	ClosesAt        time.Time   `json:"closesAt"`
+}
+
+// Read parses and validates every row of the file. Invalid rows are returned as RowErrors instead of stopping the read
+// so every problem can be fixed at once. Names are compared ignoring case and must be unique within the file. Fair
+// market values must be in the currency of the event, which a CSV file may leave out. This will return ErrInvalidFile
+// if the file as a whole cannot be read.
+func Read(r io.Reader, format Format, currency string) ([]*Row, []*RowError, error) {
+	var rows []*Row
+	var rowErrors []*RowError
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, rowErrors, err = readCSV(r, currency)
+	case FormatJSON:
+		rows, rowErrors, err = readJSON(r)
+	default:
+		return nil, nil, errors.Errorf("unknown format '%s'", format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var valid []*Row
+	firstRows := make(map[string]int, len(rows))
+	for _, row := range rows {
+		item := row.Item
+		item.Name = strings.TrimSpace(item.Name)
+		item.Category = strings.TrimSpace(item.Category)
+		item.Tags = cleanTags(item.Tags)
+		if item.FairMarketValue.Amount == 0 {
+			item.FairMarketValue = model.Money{}
+		}
+
+		var message string
+		nameID := strings.ToLower(item.Name)
+		switch {
+		case item.Name == "":
+			message = "name is required"
+		case firstRows[nameID] != 0:
+			message = fmt.Sprintf("name '%s' is already used by row %d", item.Name, firstRows[nameID])
+		case item.FairMarketValue.Amount < 0:
+			message = "fairMarketValue cannot be negative"
+		case item.FairMarketValue.Amount != 0 && item.FairMarketValue.Currency != currency:
+			message = fmt.Sprintf("fairMarketValue must be in %s", currency)
+		case item.Quantity < 0:
+			message = "quantity must be at least 1"
+		}
+		if message != "" {
+			rowErrors = append(rowErrors, &RowError{Number: row.Number, Message: message})
+			continue
+		}
+
+		firstRows[nameID] = row.Number
+		valid = append(valid, row)
+	}
+	sortRowErrors(rowErrors)
+	return valid, rowErrors, nil
+}
+
+func readCSV(r io.Reader, currency string) ([]*Row, []*RowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errors.Wrap(ErrInvalidFile, "CSV file is empty")
+	} else if err != nil {
+		return nil, nil, errors.Wrapf(ErrInvalidFile, "unable to read CSV header: %s", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		switch column {
+		case columnName, columnDescription, columnImage, columnCategory, columnTags, columnDonorName, columnDonorBusiness,
+			columnFairMarketValue, columnQuantity, columnClosesAt:
+			if _, ok := columns[column]; ok {
+				return nil, nil, errors.Wrapf(ErrInvalidFile, "column '%s' is repeated", column)
+			}
+			columns[column] = i
+		case columnID, columnCurrentBid, columnHighBidder, columnHighBidderName:
+		default:
+			return nil, nil, errors.Wrapf(ErrInvalidFile, "unknown column '%s'", column)
+		}
+	}
+	if _, ok := columns[columnName]; !ok {
+		return nil, nil, errors.Wrapf(ErrInvalidFile, "column '%s' is required", columnName)
+	}
+
+	var rows []*Row
+	var rowErrors []*RowError
+	for number := 1; ; number++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, errors.Wrap(err, "unable to read CSV file")
+			}
+			rowErrors = append(rowErrors, &RowError{Number: number, Message: parseErr.Err.Error()})
+			continue
+		}
+
+		cell := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		item := &model.AuctionItem{
+			Name:          cell(columnName),
+			ImageRef:      cell(columnImage),
+			Description:   cell(columnDescription),
+			Category:      cell(columnCategory),
+			DonorName:     cell(columnDonorName),
+			DonorBusiness: cell(columnDonorBusiness),
+		}
+		if tags := cell(columnTags); tags != "" {
+			item.Tags = strings.Split(tags, TagSeparator)
+		}
+		if value := cell(columnFairMarketValue); value != "" {
+			// Spreadsheets tend to drop the currency so an amount without one is in the currency of the event.
+			amount := value
+			if len(strings.Fields(amount)) == 1 {
+				amount += " " + currency
+			}
+			if item.FairMarketValue, err = model.ParseMoney(amount); err != nil {
+				rowErrors = append(rowErrors, &RowError{
+					Number:  number,
+					Message: fmt.Sprintf("fairMarketValue '%s' is not an amount such as \"150.00 %s\"", value, currency),
+				})
+				continue
+			}
+		}
+		if value := cell(columnQuantity); value != "" {
+			if item.Quantity, err = strconv.Atoi(value); err != nil {
+				rowErrors = append(rowErrors, &RowError{
+					Number:  number,
+					Message: fmt.Sprintf("quantity '%s' is not a whole number", value),
+				})
+				continue
+			}
+		}
+		if value := cell(columnClosesAt); value != "" {
+			if item.ClosesAt, err = time.Parse(time.RFC3339, value); err != nil {
+				rowErrors = append(rowErrors, &RowError{
+					Number:  number,
+					Message: fmt.Sprintf("closesAt '%s' is not an RFC 3339 time", value),
+				})
+				continue
+			}
+		}
+		rows = append(rows, &Row{Number: number, Item: item})
+	}
+	return rows, rowErrors, nil
+}
+
+func readJSON(r io.Reader) ([]*Row, []*RowError, error) {
+	var rawRows []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&rawRows); err != nil {
+		return nil, nil, errors.Wrapf(ErrInvalidFile, "JSON file must be an array of items: %s", err)
+	}
+
+	var rows []*Row
+	var rowErrors []*RowError
+	for i, rawRow := range rawRows {
+		var row jsonRow
+		decoder := json.NewDecoder(bytes.NewReader(rawRow))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil {
+			rowErrors = append(rowErrors, &RowError{Number: i + 1, Message: err.Error()})
+			continue
+		}
+
+		rows = append(rows, &Row{
+			Number: i + 1,
+			Item: &model.AuctionItem{
+				Name:            row.Name,
+				ImageRef:        row.ImageRef,
+				Description:     row.Description,
+				Category:        row.Category,
+				Tags:            row.Tags,
+				DonorName:       row.DonorName,
+				DonorBusiness:   row.DonorBusiness,
+				FairMarketValue: row.FairMarketValue,
+				Quantity:        row.Quantity,
+				ClosesAt:        row.ClosesAt,
+			},
+		})
+	}
+	return rows, rowErrors, nil
+}
+
+// cleanTags removes the spaces around each tag and drops the tags that are left empty.
+func cleanTags(tags []string) []string {
+	var cleaned []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return cleaned
+}
+
+// sortRowErrors orders the errors by row so they read like the file. The errors of a row keep their order.
+func sortRowErrors(rowErrors []*RowError) {
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Number < rowErrors[j].Number
+	})
+}
@@ -0,0 +1,240 @@
+package itemfile
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatOfUsesExtension(t *testing.T) {
+	format, err := FormatOf("items.CSV")
+	require.NoError(t, err)
+	require.EqualValues(t, FormatCSV, format)
+
+	format, err = FormatOf("dir/items.json")
+	require.NoError(t, err)
+	require.EqualValues(t, FormatJSON, format)
+
+	_, err = FormatOf("items.xlsx")
+	require.ErrorIs(t, err, ErrInvalidFile)
+}
+
+func TestReadCSVParsesEveryColumn(t *testing.T) {
+	file := "\ufeffname,description,image,category,tags,donorName,donorBusiness,fairMarketValue,quantity\n" +
+		"Quilt , Hand made,quilt.png,Crafts,red; blue;,Jane,Quilters,150,3\n" +
+		"Lamp,,,,,,,25.50 USD,\n" +
+		"Mug,,,,,,,,\n"
+	rows, rowErrors, err := Read(strings.NewReader(file), FormatCSV, "USD")
+	require.NoError(t, err)
+	require.Empty(t, rowErrors)
+	require.EqualValues(t, []*Row{
+		{
+			Number: 1,
+			Item: &model.AuctionItem{
+				Name:            "Quilt",
+				Description:     "Hand made",
+				ImageRef:        "quilt.png",
+				Category:        "Crafts",
+				Tags:            []string{"red", "blue"},
+				DonorName:       "Jane",
+				DonorBusiness:   "Quilters",
+				FairMarketValue: usd(15000),
+				Quantity:        3,
+			},
+		},
+		{Number: 2, Item: &model.AuctionItem{Name: "Lamp", FairMarketValue: usd(2550)}},
+		{Number: 3, Item: &model.AuctionItem{Name: "Mug"}},
+	}, rows)
+}
+
+func TestReadCSVReportsEveryInvalidRow(t *testing.T) {
+	file := "name,fairMarketValue,closesAt\n" +
+		"Quilt,abc,\n" +
+		",10,\n" +
+		"Mug,-1,\n" +
+		"Lamp,5,\n" +
+		"LAMP,6,\n" +
+		"Vase,7,tomorrow\n" +
+		"Bowl,8 EUR,\n" +
+		"Cup,1.005,\n"
+	rows, rowErrors, err := Read(strings.NewReader(file), FormatCSV, "USD")
+	require.NoError(t, err)
+	require.EqualValues(t, []*Row{{Number: 4, Item: &model.AuctionItem{Name: "Lamp", FairMarketValue: usd(500)}}}, rows)
+	require.EqualValues(t, []*RowError{
+		{Number: 1, Message: "fairMarketValue 'abc' is not an amount such as \"150.00 USD\""},
+		{Number: 2, Message: "name is required"},
+		{Number: 3, Message: "fairMarketValue cannot be negative"},
+		{Number: 5, Message: "name 'LAMP' is already used by row 4"},
+		{Number: 6, Message: "closesAt 'tomorrow' is not an RFC 3339 time"},
+		{Number: 7, Message: "fairMarketValue must be in USD"},
+		{Number: 8, Message: "fairMarketValue '1.005' is not an amount such as \"150.00 USD\""},
+	}, rowErrors)
+}
+
+func TestReadCSVIgnoresExportedColumns(t *testing.T) {
+	file := "id,name,currentBid,highBidder,highBidderName\n1,Quilt,20,bob,Bob\n"
+	rows, rowErrors, err := Read(strings.NewReader(file), FormatCSV, "USD")
+	require.NoError(t, err)
+	require.Empty(t, rowErrors)
+	require.EqualValues(t, []*Row{{Number: 1, Item: &model.AuctionItem{Name: "Quilt"}}}, rows)
+}
+
+func TestReadCSVRejectsInvalidHeaders(t *testing.T) {
+	for _, file := range []string{"", "description\nHand made\n", "name,color\nQuilt,red\n", "name,name\nQuilt,Mug\n"} {
+		_, _, err := Read(strings.NewReader(file), FormatCSV, "USD")
+		require.ErrorIs(t, err, ErrInvalidFile, file)
+	}
+}
+
+func TestReadJSONParsesItems(t *testing.T) {
+	file := `[
+		{"name": "Quilt", "category": "Crafts", "tags": ["red"], "fairMarketValue": "150.00 USD"},
+		{"name": "Mug", "color": "red"},
+		{"name": 5}
+	]`
+	rows, rowErrors, err := Read(strings.NewReader(file), FormatJSON, "USD")
+	require.NoError(t, err)
+	require.EqualValues(t, []*Row{
+		{
+			Number: 1,
+			Item: &model.AuctionItem{
+				Name:            "Quilt",
+				Category:        "Crafts",
+				Tags:            []string{"red"},
+				FairMarketValue: usd(15000),
+			},
+		},
+	}, rows)
+	require.Len(t, rowErrors, 2)
+	require.EqualValues(t, 2, rowErrors[0].Number)
+	require.Contains(t, rowErrors[0].Message, "color")
+	require.EqualValues(t, 3, rowErrors[1].Number)
+}
+
+func TestReadJSONRejectsNonArray(t *testing.T) {
+	_, _, err := Read(strings.NewReader(`{"name": "Quilt"}`), FormatJSON, "USD")
+	require.ErrorIs(t, err, ErrInvalidFile)
+}
+
+func TestImportCreatesAndUpdatesItems(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDatabase()
+	items := memory.NewAuctionItemClient(db)
+	require.NoError(t, memory.NewCategoryClient(db).Create(ctx, &model.Category{Name: "Crafts"}))
+	existing := &model.AuctionItem{Name: "Quilt", ImageRef: "quilt.png", Description: "Old"}
+	require.NoError(t, items.Create(ctx, existing))
+
+	rows := []*Row{
+		{Number: 1, Item: &model.AuctionItem{Name: "quilt", Description: "New", Category: "crafts"}},
+		{Number: 2, Item: &model.AuctionItem{Name: "Mug"}},
+	}
+	result, err := Import(ctx, memory.NewTransactor(db), rows, nil, false)
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+	require.Len(t, result.Rows, 2)
+	require.EqualValues(t, ActionUpdated, result.Rows[0].Action)
+	require.EqualValues(t, "Old", result.Rows[0].Previous.Description)
+	require.EqualValues(t, ActionCreated, result.Rows[1].Action)
+	require.EqualValues(t, 1, result.Count(ActionCreated))
+	require.EqualValues(t, 1, result.Count(ActionUpdated))
+
+	quilt, err := items.GetByID(ctx, existing.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, "quilt", quilt.Name)
+	require.EqualValues(t, "New", quilt.Description)
+	require.EqualValues(t, "quilt.png", quilt.ImageRef)
+
+	_, err = items.Get(ctx, "Mug")
+	require.NoError(t, err)
+}
+
+func TestImportDryRunKeepsNothing(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDatabase()
+
+	rows := []*Row{{Number: 1, Item: &model.AuctionItem{Name: "Mug"}}}
+	result, err := Import(ctx, memory.NewTransactor(db), rows, nil, true)
+	require.NoError(t, err)
+	require.True(t, result.DryRun)
+	require.Len(t, result.Rows, 1)
+	require.EqualValues(t, ActionCreated, result.Rows[0].Action)
+
+	all, err := memory.NewAuctionItemClient(db).GetAll(ctx)
+	require.NoError(t, err)
+	require.Empty(t, all)
+}
+
+func TestImportKeepsNothingWhenAnyRowIsInvalid(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDatabase()
+	items := memory.NewAuctionItemClient(db)
+	require.NoError(t, items.Create(ctx, &model.AuctionItem{Name: "Lamp"}))
+	require.NoError(t, items.Delete(ctx, "Lamp"))
+
+	rows := []*Row{
+		{Number: 1, Item: &model.AuctionItem{Name: "Mug"}},
+		{Number: 2, Item: &model.AuctionItem{Name: "Quilt", Category: "Crafts"}},
+		{Number: 4, Item: &model.AuctionItem{Name: "Lamp"}},
+	}
+	rowErrors := []*RowError{{Number: 3, Message: "name is required"}}
+	result, err := Import(ctx, memory.NewTransactor(db), rows, rowErrors, false)
+	require.NoError(t, err)
+	require.Empty(t, result.Rows)
+	require.EqualValues(t, []*RowError{
+		{Number: 2, Message: "category 'Crafts' does not exist"},
+		{Number: 3, Message: "name is required"},
+		{Number: 4, Message: "item 'Lamp' was deleted and must be restored before it can be imported"},
+	}, result.Errors)
+
+	all, err := items.GetAll(ctx)
+	require.NoError(t, err)
+	require.Empty(t, all)
+}
+
+func TestWriteCSVWritesItemsWithHighestBids(t *testing.T) {
+	quilt := &model.AuctionItem{
+		ID:              1,
+		Name:            "Quilt",
+		Description:     "Red, blue",
+		Category:        "Crafts",
+		Tags:            []string{"red", "blue"},
+		DonorName:       "Jane",
+		FairMarketValue: usd(15000),
+		Quantity:        2,
+		ClosesAt:        time.Date(2030, time.June, 1, 20, 0, 0, 0, time.UTC),
+	}
+	mug := &model.AuctionItem{ID: 2, Name: "Mug", Quantity: 1}
+	amy := &model.User{Username: "amy", DisplayName: "Amy"}
+	bob := &model.User{Username: "bob", DisplayName: "Bob"}
+	bids := []*model.AuctionBid{
+		{BidAmount: model.Money{Amount: 3000, Currency: "USD"}, Bidder: amy, Item: quilt},
+		{BidAmount: model.Money{Amount: 4050, Currency: "USD"}, Bidder: bob, Item: quilt},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, WriteCSV(&buffer, []*model.AuctionItem{mug, quilt}, bids))
+	require.EqualValues(t, "id,name,description,image,category,tags,donorName,donorBusiness,fairMarketValue,"+
+		"quantity,closesAt,currentBid,highBidder,highBidderName\n"+
+		"1,Quilt,\"Red, blue\",,Crafts,red;blue,Jane,,150.00 USD,2,2030-06-01T20:00:00Z,40.50 USD,bob,Bob\n"+
+		"2,Mug,,,,,,,,1,,,,\n", buffer.String())
+
+	rows, rowErrors, err := Read(&buffer, FormatCSV, "USD")
+	require.NoError(t, err)
+	require.Empty(t, rowErrors)
+	require.Len(t, rows, 2)
+	require.EqualValues(t, "Red, blue", rows[0].Item.Description)
+	require.EqualValues(t, []string{"red", "blue"}, rows[0].Item.Tags)
+	require.True(t, quilt.ClosesAt.Equal(rows[0].Item.ClosesAt))
+	require.True(t, rows[1].Item.ClosesAt.IsZero())
+	require.EqualValues(t, quilt.FairMarketValue, rows[0].Item.FairMarketValue)
+}
+
+func usd(amount int64) model.Money {
+	return model.Money{Amount: amount, Currency: "USD"}
+}
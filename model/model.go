@@ -1,23 +1,190 @@
 package model
 
+import "time"
+
 // User defines how to identify and name someone who can create bids or manage the system.
 type User struct {
 	Username       string
 	DisplayName    string
 	HashedPassword string
 	Permission     PermissionLevel
+
+	// DeletedAt is when the user was deleted. It is zero unless the user was deleted and has not been restored.
+	DeletedAt time.Time
 }
 
 // AuctionItem defines the item that is being auctioned off.
 type AuctionItem struct {
+	// ID is assigned when the item is created and never changes, unlike the name.
+	ID          uint64
 	Name        string
 	ImageRef    string
 	Description string
+
+	// Category is the name of the category the item is in. It is empty when the item is not in a category.
+	Category string
+
+	// Tags are free-form labels of the item. Tags are compared ignoring case.
+	Tags []string
+
+	// DonorName and DonorBusiness are who donated the item so they can be thanked after the auction.
+	DonorName     string
+	DonorBusiness string
+
+	// FairMarketValue is what the donor stated the item is worth. It is zero when the donor did not state a value.
+	FairMarketValue Money
+
+	// Quantity is how many bidders win the item, each with their own highest bid. It is 1 for an item that is won by a
+	// single bidder. A lot is won by as many bidders as the quantity of its first item.
+	Quantity int
+
+	// LotID is the ID of the lot the item is sold in. It is 0 when the item is sold on its own.
+	LotID uint64
+
+	// ClosesAt is when bidding on the item closes. It is zero when bidding stays open until the auction ends.
+	ClosesAt time.Time
+
+	// DeletedAt is when the item was deleted. It is zero unless the item was deleted and has not been restored.
+	DeletedAt time.Time
+
+	// Version is incremented every time the item changes so concurrent changes can be detected.
+	Version uint64
+}
+
+// IsClosed determines whether bidding on the item has closed by the time given.
+func (item *AuctionItem) IsClosed(now time.Time) bool {
+	return !item.ClosesAt.IsZero() && !now.Before(item.ClosesAt)
+}
+
+// Category groups similar items together. Every item is in at most one category.
+type Category struct {
+	ID   uint64
+	Name string
+}
+
+// Lot sells several items together as a single unit. Bids on any item of the lot are bids on the whole lot.
+type Lot struct {
+	ID   uint64
+	Name string
+
+	// Items are the items sold in the lot ordered by ID. Every lot has at least two items.
+	Items []*AuctionItem
 }
 
 // AuctionBid creates the link between the user and the item and how much was being bid.
 type AuctionBid struct {
-	BidAmount int
+	BidAmount Money
 	Bidder    *User
 	Item      *AuctionItem
 }
+
+// AppealLevel is one of the fixed amounts guests are asked to give during a fund-a-need appeal or paddle raise. Pledges
+// are made against a level instead of an item.
+type AppealLevel struct {
+	ID     uint64
+	Name   string
+	Amount Money
+}
+
+// Pledge is a donation a bidder promised at one of the appeal levels. The bidder owes it along with the items they won.
+type Pledge struct {
+	ID     uint64
+	Level  *AppealLevel
+	Bidder *User
+
+	// Amount is the amount of the level when the pledge was recorded.
+	Amount Money
+
+	// RecordedBy is the username of whoever recorded the pledge, which is an admin when it was recorded on behalf of the
+	// bidder.
+	RecordedBy string
+}
+
+// Raffle is sold by the ticket alongside the items of the auction. The winning tickets are drawn at random once ticket
+// sales end.
+type Raffle struct {
+	ID          uint64
+	Name        string
+	Description string
+
+	// TicketPrice is what a single ticket costs.
+	TicketPrice Money
+
+	// Prizes is how many tickets are drawn as winners.
+	Prizes int
+
+	// Drawing is nil until the raffle is drawn.
+	Drawing *RaffleDrawing
+}
+
+// TicketPurchase is a number of tickets for a raffle bought by a user at once.
+type TicketPurchase struct {
+	ID       uint64
+	RaffleID uint64
+	Buyer    *User
+	Quantity int
+
+	// Price is what a single ticket cost when the tickets were bought.
+	Price Money
+
+	// RecordedBy is the username of whoever recorded the purchase, which is an admin when tickets were sold on behalf of
+	// the buyer.
+	RecordedBy string
+}
+
+// RaffleDrawing records how the winners of a raffle were drawn so the drawing can be repeated to audit it.
+type RaffleDrawing struct {
+	// Seed is the random seed the winners were drawn with, encoded as hex.
+	Seed string
+
+	// Tickets has the username of the buyer of every ticket that was sold. Tickets are numbered from 1 in the order they
+	// were bought.
+	Tickets []string
+
+	// Winners has the numbers of the winning tickets in the order they were drawn.
+	Winners []int
+
+	DrawnAt time.Time
+}
+
+// SpendingLimit caps the total a bidder can commit to the items they are winning. A limit for a bidder is credit they
+// were pre-authorized for and replaces the limit of the event.
+type SpendingLimit struct {
+	// Username is the bidder the limit applies to. It is empty for the limit of the event, which applies to every
+	// bidder without a limit of their own.
+	Username string
+
+	// Amount is the most the winning bids of the bidder can add up to.
+	Amount Money
+}
+
+// OutbidNotice tells a bidder that their bid is no longer winning. It is kept until the bidder is told, which can be
+// after they connect again when they were not connected at the time.
+type OutbidNotice struct {
+	ID       uint64
+	Username string
+
+	// ItemID and ItemName are the item the bid was placed on. LotID and LotName are set when the item is sold in a lot,
+	// in which case the notice is about the lot.
+	ItemID   uint64
+	ItemName string
+	LotID    uint64
+	LotName  string
+
+	// CurrentBid is the bid that outbid the bidder and MinimumBid is the least they have to bid to win back a place.
+	CurrentBid Money
+	MinimumBid Money
+}
+
+// ItemImage is one of the images of an item. The image and its thumbnail are kept in blob storage under their keys.
+type ItemImage struct {
+	ID     uint64
+	ItemID uint64
+
+	// Position orders the images of an item starting at 0. The first image is the one shown for the item.
+	Position int
+
+	Key          string
+	ThumbnailKey string
+	ContentType  string
+}
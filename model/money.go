@@ -0,0 +1,286 @@
+package model
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultCurrency and DefaultLocale are used by an event that does not choose its own.
+const (
+	DefaultCurrency = "USD"
+	DefaultLocale   = "en-US"
+)
+
+var (
+	// ErrInvalidMoney is returned when text is not an amount of money such as "12.50 USD".
+	ErrInvalidMoney = errors.New("invalid amount of money")
+
+	// ErrUnknownCurrency is returned for a currency code that is not supported.
+	ErrUnknownCurrency = errors.New("unknown currency")
+
+	// ErrUnknownLocale is returned for a locale that amounts of money cannot be formatted for.
+	ErrUnknownLocale = errors.New("unknown locale")
+
+	// ErrCurrencyMismatch is returned when amounts of money in different currencies are added together.
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+)
+
+// currency describes how amounts of an ISO 4217 currency are written.
+type currency struct {
+	// digits is how many minor units are written after the decimal separator, e.g. 2 for the cents of a dollar.
+	digits int
+
+	// symbol identifies the currency anywhere while localSymbol is used in the locales the currency belongs to, e.g.
+	// "CA$" and "$" for Canadian dollars.
+	symbol      string
+	localSymbol string
+}
+
+var currencies = map[string]currency{
+	"AUD": {digits: 2, symbol: "A$", localSymbol: "$"},
+	"CAD": {digits: 2, symbol: "CA$", localSymbol: "$"},
+	"CHF": {digits: 2, symbol: "CHF", localSymbol: "CHF"},
+	"EUR": {digits: 2, symbol: "€", localSymbol: "€"},
+	"GBP": {digits: 2, symbol: "£", localSymbol: "£"},
+	"JPY": {digits: 0, symbol: "¥", localSymbol: "￥"},
+	"MXN": {digits: 2, symbol: "MX$", localSymbol: "$"},
+	"NZD": {digits: 2, symbol: "NZ$", localSymbol: "$"},
+	"USD": {digits: 2, symbol: "US$", localSymbol: "$"},
+}
+
+// locale describes how amounts of money are written for the people of a region.
+type locale struct {
+	// currency is the code of the currency used in the region, which is written with its local symbol.
+	currency string
+
+	decimal string
+	group   string
+
+	// symbolAfter puts the symbol after the amount and symbolSpace separates the symbol from the amount with a space that
+	// does not break.
+	symbolAfter bool
+	symbolSpace bool
+}
+
+var locales = map[string]locale{
+	"de-ch": {currency: "CHF", decimal: ".", group: "’", symbolSpace: true},
+	"de-de": {currency: "EUR", decimal: ",", group: ".", symbolAfter: true, symbolSpace: true},
+	"en-au": {currency: "AUD", decimal: ".", group: ","},
+	"en-ca": {currency: "CAD", decimal: ".", group: ","},
+	"en-gb": {currency: "GBP", decimal: ".", group: ","},
+	"en-ie": {currency: "EUR", decimal: ".", group: ","},
+	"en-nz": {currency: "NZD", decimal: ".", group: ","},
+	"en-us": {currency: "USD", decimal: ".", group: ","},
+	"es-es": {currency: "EUR", decimal: ",", group: ".", symbolAfter: true, symbolSpace: true},
+	"es-mx": {currency: "MXN", decimal: ".", group: ","},
+	"fr-ca": {currency: "CAD", decimal: ",", group: "\u202f", symbolAfter: true, symbolSpace: true},
+	"fr-fr": {currency: "EUR", decimal: ",", group: "\u202f", symbolAfter: true, symbolSpace: true},
+	"it-it": {currency: "EUR", decimal: ",", group: ".", symbolAfter: true, symbolSpace: true},
+	"ja-jp": {currency: "JPY", decimal: ".", group: ","},
+	"nl-nl": {currency: "EUR", decimal: ",", group: ".", symbolSpace: true},
+}
+
+// Money is an amount in the minor units of a currency, e.g. cents, so amounts are never rounded. It is written as a
+// decimal string followed by the currency code, e.g. "12.50 USD", so clients never have to handle it as a float.
+type Money struct {
+	// Amount is in the minor units of the currency.
+	Amount int64
+
+	// Currency is the ISO 4217 code of the currency, e.g. "USD".
+	Currency string
+}
+
+// ValidateCurrency returns ErrUnknownCurrency unless amounts in the currency can be parsed and formatted.
+func ValidateCurrency(code string) error {
+	if _, ok := currencies[code]; !ok {
+		return errors.Wrapf(ErrUnknownCurrency, "'%s' is not supported", code)
+	}
+	return nil
+}
+
+// ValidateLocale returns ErrUnknownLocale unless amounts of money can be formatted for the locale, e.g. "en-US".
+func ValidateLocale(tag string) error {
+	if _, ok := locales[strings.ToLower(tag)]; !ok {
+		return errors.Wrapf(ErrUnknownLocale, "'%s' is not supported", tag)
+	}
+	return nil
+}
+
+// WholeUnits converts an amount of whole units of the currency, e.g. dollars, to money.
+func WholeUnits(amount int, code string) Money {
+	return Money{Amount: int64(amount) * minorUnitsPerUnit(currencies[code].digits), Currency: code}
+}
+
+// Units returns the amount in whole units of the currency, e.g. dollars, dropping any minor units.
+func (m Money) Units() int64 {
+	return m.Amount / minorUnitsPerUnit(currencies[m.Currency].digits)
+}
+
+// UnitsRoundedUp returns the amount in whole units of the currency, rounding any minor units up to the next unit.
+func (m Money) UnitsRoundedUp() int64 {
+	units := m.Units()
+	if m.Amount%minorUnitsPerUnit(currencies[m.Currency].digits) > 0 {
+		units++
+	}
+	return units
+}
+
+// ParseMoney parses a decimal amount followed by its currency code, e.g. "12.50 USD". The amount cannot be more precise
+// than the minor units of the currency.
+func ParseMoney(text string) (Money, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 2 {
+		return Money{}, errors.Wrapf(ErrInvalidMoney, "'%s' must be an amount and a currency, e.g. \"12.50 USD\"", text)
+	}
+
+	code := strings.ToUpper(fields[1])
+	if err := ValidateCurrency(code); err != nil {
+		return Money{}, err
+	}
+	digits := currencies[code].digits
+
+	number := fields[0]
+	negative := strings.HasPrefix(number, "-")
+	number = strings.TrimPrefix(number, "-")
+	whole, fraction := number, ""
+	if separator := strings.IndexByte(number, '.'); separator >= 0 {
+		whole, fraction = number[:separator], number[separator+1:]
+	}
+	if whole == "" || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, errors.Wrapf(ErrInvalidMoney, "'%s' is not a decimal number", fields[0])
+	}
+	if len(fraction) > digits {
+		return Money{}, errors.Wrapf(ErrInvalidMoney, "'%s' has more than %d decimal places", fields[0], digits)
+	}
+
+	amount, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", digits-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, errors.Wrapf(ErrInvalidMoney, "'%s' is too large", fields[0])
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: code}, nil
+}
+
+// Add returns the sum of both amounts. Money without a currency takes on the currency of the other amount. This will
+// return ErrCurrencyMismatch if the amounts are in different currencies.
+func (m Money) Add(other Money) (Money, error) {
+	sum := Money{Amount: m.Amount + other.Amount, Currency: m.Currency}
+	switch {
+	case sum.Currency == "":
+		sum.Currency = other.Currency
+	case other.Currency != "" && other.Currency != sum.Currency:
+		return Money{}, errors.Wrapf(ErrCurrencyMismatch, "unable to add %s to %s", other, m)
+	}
+	return sum, nil
+}
+
+// Times returns the amount multiplied by n, e.g. the total of n tickets at a price.
+func (m Money) Times(n int) Money {
+	return Money{Amount: m.Amount * int64(n), Currency: m.Currency}
+}
+
+// String writes the amount as a decimal number followed by the currency code, e.g. "12.50 USD".
+func (m Money) String() string {
+	text := m.decimal(".", "")
+	if m.Currency == "" {
+		return text
+	}
+	return text + " " + m.Currency
+}
+
+// Format writes the amount the way people of the locale expect, e.g. "$1,234.50" for "en-US" and "1.234,50 €" for
+// "de-DE". The plain string is returned for an unknown locale or currency.
+func (m Money) Format(tag string) string {
+	loc, ok := locales[strings.ToLower(tag)]
+	cur, known := currencies[m.Currency]
+	if !ok || !known {
+		return m.String()
+	}
+
+	symbol := cur.symbol
+	if m.Currency == loc.currency {
+		symbol = cur.localSymbol
+	}
+
+	text := Money{Amount: abs(m.Amount), Currency: m.Currency}.decimal(loc.decimal, loc.group)
+	separator := ""
+	if loc.symbolSpace {
+		separator = "\u00a0"
+	}
+	if loc.symbolAfter {
+		text = text + separator + symbol
+	} else {
+		text = symbol + separator + text
+	}
+	if m.Amount < 0 {
+		return "-" + text
+	}
+	return text
+}
+
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalText(raw []byte) error {
+	money, err := ParseMoney(string(raw))
+	if err != nil {
+		return errors.Wrap(err, "unable to unmarshal money")
+	}
+	*m = money
+	return nil
+}
+
+// decimal writes the amount with the separators given without a currency.
+func (m Money) decimal(decimalSeparator string, groupSeparator string) string {
+	digits := currencies[m.Currency].digits
+	text := strconv.FormatInt(abs(m.Amount), 10)
+	if len(text) <= digits {
+		text = strings.Repeat("0", digits-len(text)+1) + text
+	}
+	whole, fraction := text[:len(text)-digits], text[len(text)-digits:]
+
+	if groupSeparator != "" {
+		var grouped strings.Builder
+		for i, digit := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				grouped.WriteString(groupSeparator)
+			}
+			grouped.WriteRune(digit)
+		}
+		whole = grouped.String()
+	}
+
+	if m.Amount < 0 {
+		whole = "-" + whole
+	}
+	if fraction == "" {
+		return whole
+	}
+	return whole + decimalSeparator + fraction
+}
+
+func minorUnitsPerUnit(digits int) int64 {
+	return int64(math.Pow10(digits))
+}
+
+func isDigits(text string) bool {
+	for _, digit := range text {
+		if digit < '0' || digit > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(amount int64) int64 {
+	if amount < 0 {
+		return -amount
+	}
+	return amount
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoneyReadsMinorUnits(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected Money
+	}{
+		{text: "12.50 USD", expected: Money{Amount: 1250, Currency: "USD"}},
+		{text: "12 USD", expected: Money{Amount: 1200, Currency: "USD"}},
+		{text: "0.5 eur", expected: Money{Amount: 50, Currency: "EUR"}},
+		{text: "1500 JPY", expected: Money{Amount: 1500, Currency: "JPY"}},
+		{text: " -3.25  GBP ", expected: Money{Amount: -325, Currency: "GBP"}},
+	}
+	for _, test := range tests {
+		money, err := ParseMoney(test.text)
+		require.NoError(t, err, test.text)
+		require.EqualValues(t, test.expected, money, test.text)
+	}
+}
+
+func TestParseMoneyRejectsInvalidAmounts(t *testing.T) {
+	invalid := []string{"", "12.50", "USD", "12.505 USD", "1.5 JPY", "1,000 USD", "abc USD", ".50 USD"}
+	invalid = append(invalid, "99999999999999999999 USD")
+	for _, text := range invalid {
+		_, err := ParseMoney(text)
+		require.ErrorIs(t, err, ErrInvalidMoney, text)
+	}
+
+	_, err := ParseMoney("12.50 XYZ")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestMoneyStringRoundTripsThroughParseMoney(t *testing.T) {
+	amounts := []Money{{Amount: 1250, Currency: "USD"}, {Amount: 5, Currency: "EUR"}, {Amount: -100, Currency: "CAD"}}
+	for _, money := range append(amounts, Money{Amount: 1500, Currency: "JPY"}) {
+		parsed, err := ParseMoney(money.String())
+		require.NoError(t, err, money.String())
+		require.EqualValues(t, money, parsed)
+	}
+	require.EqualValues(t, "0.05 EUR", Money{Amount: 5, Currency: "EUR"}.String())
+}
+
+func TestMoneyMarshalsToJSONString(t *testing.T) {
+	raw, err := json.Marshal(struct {
+		Amount Money `json:"amount"`
+	}{Amount: Money{Amount: 123456, Currency: "USD"}})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":"1234.56 USD"}`, string(raw))
+
+	var decoded struct {
+		Amount Money `json:"amount"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	require.EqualValues(t, Money{Amount: 123456, Currency: "USD"}, decoded.Amount)
+
+	require.Error(t, json.Unmarshal([]byte(`{"amount":12.5}`), &decoded))
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"12.5"}`), &decoded), ErrInvalidMoney)
+}
+
+func TestMoneyFormatFollowsLocale(t *testing.T) {
+	tests := []struct {
+		money    Money
+		locale   string
+		expected string
+	}{
+		{money: Money{Amount: 123450, Currency: "USD"}, locale: "en-US", expected: "$1,234.50"},
+		{money: Money{Amount: 123450, Currency: "CAD"}, locale: "en-US", expected: "CA$1,234.50"},
+		{money: Money{Amount: 123450, Currency: "EUR"}, locale: "de-DE", expected: "1.234,50\u00a0€"},
+		{money: Money{Amount: 123450, Currency: "EUR"}, locale: "nl-nl", expected: "€\u00a01.234,50"},
+		{money: Money{Amount: 123450, Currency: "USD"}, locale: "fr-FR", expected: "1\u202f234,50\u00a0US$"},
+		{money: Money{Amount: 1234567, Currency: "JPY"}, locale: "ja-JP", expected: "￥1,234,567"},
+		{money: Money{Amount: 5, Currency: "GBP"}, locale: "en-GB", expected: "£0.05"},
+		{money: Money{Amount: -2500, Currency: "USD"}, locale: "en-US", expected: "-$25.00"},
+		{money: Money{Amount: 2500, Currency: "USD"}, locale: "xx-XX", expected: "25.00 USD"},
+	}
+	for _, test := range tests {
+		require.EqualValues(t, test.expected, test.money.Format(test.locale), test.locale)
+	}
+}
+
+func TestWholeUnitsUsesMinorUnitsOfCurrency(t *testing.T) {
+	require.EqualValues(t, Money{Amount: 2500, Currency: "USD"}, WholeUnits(25, "USD"))
+	require.EqualValues(t, Money{Amount: 25, Currency: "JPY"}, WholeUnits(25, "JPY"))
+}
+
+func TestAddKeepsCurrency(t *testing.T) {
+	total, err := Money{}.Add(WholeUnits(25, "USD"))
+	require.NoError(t, err)
+	total, err = total.Add(Money{Amount: 100})
+	require.NoError(t, err)
+	require.EqualValues(t, Money{Amount: 2600, Currency: "USD"}, total)
+
+	_, err = total.Add(WholeUnits(1, "EUR"))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestTimesKeepsCurrency(t *testing.T) {
+	require.EqualValues(t, Money{Amount: 1500, Currency: "USD"}, Money{Amount: 500, Currency: "USD"}.Times(3))
+}
+
+func TestUnitsDropOrRoundUpMinorUnits(t *testing.T) {
+	require.EqualValues(t, 12, Money{Amount: 1250, Currency: "USD"}.Units())
+	require.EqualValues(t, 13, Money{Amount: 1250, Currency: "USD"}.UnitsRoundedUp())
+	require.EqualValues(t, 12, Money{Amount: 1200, Currency: "USD"}.UnitsRoundedUp())
+	require.EqualValues(t, 1500, Money{Amount: 1500, Currency: "JPY"}.UnitsRoundedUp())
+}
+
+func TestValidateCurrencyAndLocale(t *testing.T) {
+	require.NoError(t, ValidateCurrency("USD"))
+	require.ErrorIs(t, ValidateCurrency("usd"), ErrUnknownCurrency)
+	require.NoError(t, ValidateLocale("en-us"))
+	require.ErrorIs(t, ValidateLocale("en"), ErrUnknownLocale)
+}
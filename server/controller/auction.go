@@ -1,14 +1,22 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/MMarsolek/AuctionHouse/events"
 	"github.com/MMarsolek/AuctionHouse/log"
 	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/server/controller/auth"
+	"github.com/MMarsolek/AuctionHouse/server/controller/bidding"
 	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
 	"github.com/MMarsolek/AuctionHouse/storage"
 	"github.com/gorilla/mux"
@@ -29,6 +37,11 @@ type (
 
 	// swagger:model
 	itemResponse struct {
+		// The ID of the item.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
 		// The name of the item.
 		//
 		// Required: true
@@ -39,13 +52,21 @@ type (
 
 		// The description of the item.
 		Description string `json:"description,omitempty"`
+
+		// The ID of the lot the item is sold in. Bids on the item are bids on the whole lot.
+		LotID uint64 `json:"lotId,omitempty"`
 	}
 
 	getHighestBidResponse struct {
-		// The amount of money being bid for this item.
+		// The amount of money being bid for this item as a decimal string followed by the currency, e.g. "12.50 USD".
 		//
 		// Required: true
-		BidAmount int `json:"bidAmount"`
+		BidAmount model.Money `json:"bidAmount"`
+
+		// The amount of money being bid written for the locale of the event, e.g. "$12.50".
+		//
+		// Required: true
+		FormattedBidAmount string `json:"formattedBidAmount"`
 
 		// The item being bid on.
 		//
@@ -59,6 +80,55 @@ type (
 	}
 
 	getItemResponse struct {
+		// The ID of the item.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The name of the item.
+		//
+		// Required: true
+		Name string `json:"name"`
+
+		// The reference to the image source.
+		ImageRef string `json:"image,omitempty"`
+
+		// The description of the item.
+		Description string `json:"description,omitempty"`
+
+		// The name of the category the item is in.
+		Category string `json:"category,omitempty"`
+
+		// The free-form labels of the item.
+		Tags []string `json:"tags,omitempty"`
+
+		// The name of the person who donated the item.
+		DonorName string `json:"donorName,omitempty"`
+
+		// The business that donated the item.
+		DonorBusiness string `json:"donorBusiness,omitempty"`
+
+		// What the donor stated the item is worth as a decimal string followed by the currency, e.g. "150.00 USD".
+		FairMarketValue *model.Money `json:"fairMarketValue,omitempty"`
+
+		// How many bidders win the item, each paying their own highest bid.
+		//
+		// Required: true
+		Quantity int `json:"quantity"`
+
+		// The ID of the lot the item is sold in. Bids on the item are bids on the whole lot.
+		LotID uint64 `json:"lotId,omitempty"`
+
+		// When bidding on the item closes. It is left out when bidding stays open until the auction ends.
+		ClosesAt *time.Time `json:"closesAt,omitempty"`
+	}
+
+	getDeletedItemResponse struct {
+		// The ID of the item. This is used to restore the item.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
 		// The name of the item.
 		//
 		// Required: true
@@ -69,6 +139,11 @@ type (
 
 		// The description of the item.
 		Description string `json:"description,omitempty"`
+
+		// When the item was deleted.
+		//
+		// Required: true
+		DeletedAt time.Time `json:"deletedAt"`
 	}
 
 	postItemRequest struct {
@@ -82,41 +157,183 @@ type (
 
 		// Reference to the image source.
 		ImageRef string `json:"image,omitempty"`
+
+		// Name of the category to put the item in. The category must already exist.
+		Category string `json:"category,omitempty"`
+
+		// Free-form labels of the item.
+		Tags []string `json:"tags,omitempty"`
+
+		// Name of the person who donated the item.
+		DonorName string `json:"donorName,omitempty"`
+
+		// Business that donated the item.
+		DonorBusiness string `json:"donorBusiness,omitempty"`
+
+		// What the donor stated the item is worth as a decimal string followed by the currency of the auction, e.g.
+		// "150.00 USD". It cannot be negative.
+		FairMarketValue *model.Money `json:"fairMarketValue,omitempty"`
+
+		// How many bidders win the item, each paying their own highest bid. It is 1 when left out.
+		Quantity int `json:"quantity,omitempty"`
+
+		// When bidding on the item closes. Bidding stays open until the auction ends when left out.
+		ClosesAt time.Time `json:"closesAt,omitempty"`
 	}
 
 	putItemRequest struct {
+		// The new name of the item. The bids on the item are kept.
+		Name string `json:"name,omitempty"`
+
 		// Description of the item.
 		Description string `json:"description,omitempty"`
 
 		// Reference to the image source.
 		ImageRef string `json:"image,omitempty"`
+
+		// Name of the category to move the item to. The category must already exist.
+		Category string `json:"category,omitempty"`
+
+		// Free-form labels that replace the labels of the item.
+		Tags []string `json:"tags,omitempty"`
+
+		// Name of the person who donated the item.
+		DonorName string `json:"donorName,omitempty"`
+
+		// Business that donated the item.
+		DonorBusiness string `json:"donorBusiness,omitempty"`
+
+		// What the donor stated the item is worth as a decimal string followed by the currency of the auction, e.g.
+		// "150.00 USD". It cannot be negative.
+		FairMarketValue *model.Money `json:"fairMarketValue,omitempty"`
+
+		// How many bidders win the item, each paying their own highest bid.
+		Quantity int `json:"quantity,omitempty"`
+
+		// When bidding on the item closes.
+		ClosesAt time.Time `json:"closesAt,omitempty"`
+	}
+
+	// patchItemRequest documents the merge patch read by parseItemMergePatch.
+	patchItemRequest struct {
+		// The new name of the item. The bids on the item are kept. It cannot be null.
+		Name *string `json:"name,omitempty"`
+
+		// Description of the item. Null removes the description.
+		Description *string `json:"description,omitempty"`
+
+		// Reference to the image source. Null removes the image.
+		ImageRef *string `json:"image,omitempty"`
+
+		// Name of the category to move the item to. Null takes the item out of its category.
+		Category *string `json:"category,omitempty"`
+
+		// Free-form labels that replace the labels of the item. Null removes every label.
+		Tags *[]string `json:"tags,omitempty"`
+
+		// Name of the person who donated the item. Null removes the name.
+		DonorName *string `json:"donorName,omitempty"`
+
+		// Business that donated the item. Null removes the business.
+		DonorBusiness *string `json:"donorBusiness,omitempty"`
+
+		// What the donor stated the item is worth as a decimal string followed by the currency of the auction, e.g.
+		// "150.00 USD". Null removes the value.
+		FairMarketValue *model.Money `json:"fairMarketValue,omitempty"`
+
+		// How many bidders win the item, each paying their own highest bid. It cannot be null.
+		Quantity *int `json:"quantity,omitempty"`
+
+		// When bidding on the item closes. Null keeps bidding open until the auction ends.
+		ClosesAt *time.Time `json:"closesAt,omitempty"`
 	}
 
 	postBidRequest struct {
-		// The amount to bid on the item for.
+		// The amount to bid on the item for as a decimal string followed by the currency of the event, e.g. "12.50 USD".
 		//
 		// Required: true
-		BidAmount int `json:"bidAmount"`
+		BidAmount model.Money `json:"bidAmount"`
+
+		// wholeUnits is set in place of the bid amount when an older client sends an integer number of whole units of
+		// the currency of the event.
+		wholeUnits *int
+	}
+
+	getCurrencyResponse struct {
+		// The ISO 4217 code of the currency every bid must be in.
+		//
+		// Required: true
+		Currency string `json:"currency"`
+
+		// The locale amounts of money are formatted for, e.g. "en-US".
+		//
+		// Required: true
+		Locale string `json:"locale"`
 	}
 )
 
+// mergePatchMediaType is the Content-Type of a JSON Merge Patch.
+const mergePatchMediaType = "application/merge-patch+json"
+
+var (
+	errNegativeFairMarketValue = errors.New("fair market value cannot be negative")
+	errFairMarketValueFormat   = errors.New("fair market value must be a string such as \"150.00 USD\"")
+	errFairMarketValueCurrency = errors.New("fair market value is not in the currency of the auction")
+	errInvalidQuantity         = errors.New("quantity must be at least 1")
+	errBidAmountFormat         = errors.New("bid amount must be a string such as \"12.50 USD\"")
+)
+
+// UnmarshalJSON reads the bid amount as money or, as older clients send it, an integer number of whole units.
+func (request *postBidRequest) UnmarshalJSON(raw []byte) error {
+	var body struct {
+		BidAmount json.RawMessage `json:"bidAmount"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return errors.Wrap(err, "unable to unmarshal postBidRequest")
+	}
+	if len(body.BidAmount) == 0 {
+		return nil
+	}
+
+	var wholeUnits int
+	if err := json.Unmarshal(body.BidAmount, &wholeUnits); err == nil {
+		request.wholeUnits = &wholeUnits
+		return nil
+	}
+	return errors.Wrap(json.Unmarshal(body.BidAmount, &request.BidAmount), "unable to unmarshal bid amount")
+}
+
 // AuctionHandler provides handlers for endpoints involving model.AuctionItems and model.AuctionBids.
 type AuctionHandler struct {
 	userClient        storage.UserClient
 	auctionItemClient storage.AuctionItemClient
 	auctionBidClient  storage.AuctionBidClient
+	transactor        storage.Transactor
+	eventBus          *events.Bus
+	currency          string
+	locale            string
 }
 
-// NewAuctionHandler creates a new AuctionHandler with the necessary storage objects.
+// NewAuctionHandler creates a new AuctionHandler with the necessary storage objects. Bids are placed using the
+// transactor and every change to items and bids is published on the eventBus. Every bid must be in the currency of the
+// event and amounts are formatted for its locale.
 func NewAuctionHandler(
 	userClient storage.UserClient,
 	auctionItemClient storage.AuctionItemClient,
 	auctionBidClient storage.AuctionBidClient,
+	transactor storage.Transactor,
+	eventBus *events.Bus,
+	currency string,
+	locale string,
 ) *AuctionHandler {
 	return &AuctionHandler{
 		userClient:        userClient,
 		auctionItemClient: auctionItemClient,
 		auctionBidClient:  auctionBidClient,
+		transactor:        transactor,
+		eventBus:          eventBus,
+		currency:          currency,
+		locale:            locale,
 	}
 }
 
@@ -129,25 +346,38 @@ func (handler *AuctionHandler) RegisterRoutes(router *mux.Router) {
 	auctionsRouterAdmin.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin))
 
 	itemsAdmin := auctionsRouterAdmin.PathPrefix("/items").Subrouter()
+	itemsAdmin.HandleFunc("", wrapHandler(handler.GetDeletedItems)).Methods(http.MethodGet).Queries("deleted", "true")
 	itemsAdmin.HandleFunc("", wrapHandler(handler.PostItem)).Methods(http.MethodPost)
-	itemsAdmin.HandleFunc("/{itemName}", wrapHandler(handler.PutItem)).Methods(http.MethodPut)
-	itemsAdmin.HandleFunc("/{itemName}", wrapHandler(handler.DeleteItem)).Methods(http.MethodDelete)
+	// Registered before the routes of a single item so they are not taken for the items with the IDs import and export.
+	itemsAdmin.HandleFunc("/import", wrapHandler(handler.ImportItems)).Methods(http.MethodPost)
+	itemsAdmin.HandleFunc("/export", wrapHandler(handler.ExportItems)).Methods(http.MethodGet)
+	itemsAdmin.HandleFunc("/{item}/restore", wrapHandler(handler.RestoreItem)).Methods(http.MethodPost)
+	itemsAdmin.HandleFunc("/{item}", wrapHandler(handler.PutItem)).Methods(http.MethodPut)
+	itemsAdmin.HandleFunc("/{item}", wrapHandler(handler.PatchItem)).Methods(http.MethodPatch)
+	itemsAdmin.HandleFunc("/{item}", wrapHandler(handler.DeleteItem)).Methods(http.MethodDelete)
+
+	// Importing and exporting used to be served under /admin/items. The paths are kept for clients that still use them.
+	itemFilesAdmin := auctionsRouterAdmin.PathPrefix("/admin/items").Subrouter()
+	itemFilesAdmin.HandleFunc("/import", wrapHandler(handler.ImportItems)).Methods(http.MethodPost)
+	itemFilesAdmin.HandleFunc("/export", wrapHandler(handler.ExportItems)).Methods(http.MethodGet)
 
 	auctionsRouterBidder := auctionsRouter.NewRoute().Subrouter()
 	auctionsRouterBidder.Use(middleware.VerifyPermissions(model.PermissionLevelBidder))
 
 	bidsBidder := auctionsRouterBidder.PathPrefix("/bids").Subrouter()
-	bidsBidder.HandleFunc("/{itemName}", wrapHandler(handler.PostBid)).Methods(http.MethodPost)
+	bidsBidder.HandleFunc("/{item}", wrapHandler(handler.PostBid)).Methods(http.MethodPost)
 
 	auctionsRouterBoth := auctionsRouter.NewRoute().Subrouter()
 	auctionsRouterBoth.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin, model.PermissionLevelBidder))
+	auctionsRouterBoth.HandleFunc("/currency", wrapHandler(handler.GetCurrency)).Methods(http.MethodGet)
 
 	itemsBoth := auctionsRouterBoth.PathPrefix("/items").Subrouter()
 	itemsBoth.HandleFunc("", wrapHandler(handler.GetItems)).Methods(http.MethodGet)
-	itemsBoth.HandleFunc("/{itemName}", wrapHandler(handler.GetItem)).Methods(http.MethodGet)
+	itemsBoth.HandleFunc("/{item}", wrapHandler(handler.GetItem)).Methods(http.MethodGet)
 
 	bidsBoth := auctionsRouterBoth.PathPrefix("/bids").Subrouter()
-	bidsBoth.HandleFunc("/{itemName}", wrapHandler(handler.GetHighestBid)).Methods(http.MethodGet)
+	bidsBoth.HandleFunc("/{item}", wrapHandler(handler.GetHighestBid)).Methods(http.MethodGet)
+	bidsBoth.HandleFunc("/{item}/winners", wrapHandler(handler.GetWinningBids)).Methods(http.MethodGet)
 	bidsBoth.HandleFunc("", wrapHandler(handler.GetHighestBids)).Methods(http.MethodGet)
 
 }
@@ -157,10 +387,10 @@ func (handler *AuctionHandler) RegisterRoutes(router *mux.Router) {
 // getItemRequestDoc is for swagger generation only.
 // swagger:parameters getItemRequest
 type getItemRequestDoc struct {
-	// Name of the item.
+	// ID of the item. The name of the item is also accepted so older clients keep working.
 	//
 	// In: path
-	Name string `json:"itemName"`
+	Item string `json:"item"`
 
 	// Expected to be "Bearer <auth_token>"
 	//
@@ -172,6 +402,8 @@ type getItemRequestDoc struct {
 //
 // swagger:response getItemResponse
 type getItemResponseDoc struct {
+	// The version of the item to send in the If-Match header when changing the item.
+	ETag string
 
 	// In: body
 	Body getItemResponse
@@ -181,11 +413,12 @@ type getItemResponseDoc struct {
 
 // GetItem is the handler that retrieves the model.AuctionItem as serialized JSON.
 //
-// swagger:route GET /api/v1/auctions/items/{itemName} Auctions getItemRequest
+// swagger:route GET /api/v1/auctions/items/{item} Auctions getItemRequest
 //
 // Gets the item specified by the name.
 //
-// This will retrieve a item from storage based on the name.
+// This will retrieve a item from storage based on the name. The ETag header identifies the version of the item and must
+// be sent back in the If-Match header to change the item.
 //
 //  Produces:
 //  - application/json
@@ -199,9 +432,9 @@ type getItemResponseDoc struct {
 //    200: getItemResponse
 //    404: noBody
 func (handler *AuctionHandler) GetItem(w http.ResponseWriter, r *http.Request) error {
-	itemName := mux.Vars(r)["itemName"]
-	r = r.WithContext(log.WithFields(r.Context(), "itemName", itemName))
-	item, err := handler.auctionItemClient.Get(r.Context(), itemName)
+	itemReference := mux.Vars(r)["item"]
+	r = r.WithContext(log.WithFields(r.Context(), "item", itemReference))
+	item, err := getItem(r.Context(), handler.auctionItemClient, itemReference)
 	if err != nil {
 		if errors.Is(err, storage.ErrEntityNotFound) {
 			w.WriteHeader(http.StatusNotFound)
@@ -210,16 +443,12 @@ func (handler *AuctionHandler) GetItem(w http.ResponseWriter, r *http.Request) e
 		return errors.Wrap(err, "could not retrieve auction item")
 	}
 
-	rawItem, err := json.Marshal(getItemResponse{
-		Name:        item.Name,
-		ImageRef:    item.ImageRef,
-		Description: item.Description,
-	})
-
+	rawItem, err := json.Marshal(newGetItemResponse(item))
 	if err != nil {
 		return errors.Wrap(err, "could not marshal item")
 	}
 
+	w.Header().Set("ETag", formatETag(item.Version))
 	fmt.Fprint(w, string(rawItem))
 	return nil
 }
@@ -233,12 +462,52 @@ type getItemsRequestDoc struct {
 	//
 	// In: header
 	Authorization string
+
+	// Only return the items in the category.
+	//
+	// In: query
+	Category string `json:"category"`
+
+	// Only return the items with the tag. Repeat the parameter to only return the items with every one of the tags.
+	//
+	// In: query
+	Tag []string `json:"tag"`
+
+	// Only return the items whose name or description has a word starting with each word of the search.
+	//
+	// In: query
+	Q string `json:"q"`
+
+	// The order of the items. Items are ordered by when they were created by default. Items without a closing time are
+	// sorted as if they close after every other item.
+	//
+	// In: query
+	// Enum: created,name,currentBid,bidCount,closing
+	Sort string `json:"sort"`
+
+	// Whether the items are ordered from the lowest to the highest or the other way around.
+	//
+	// In: query
+	// Enum: asc,desc
+	Order string `json:"order"`
+
+	// The most items to return. Defaults to 50 and cannot be more than 200.
+	//
+	// In: query
+	Limit int `json:"limit"`
+
+	// Continues the listing of a previous request. Use the next link of that request instead of setting it directly.
+	//
+	// In: query
+	Cursor string `json:"cursor"`
 }
 
 // Contains data about the item and how to identify them.
 //
 // swagger:response getItemsResponse
 type getItemsResponseDoc struct {
+	// Links to the next page of items as rel="next". It is missing on the last page.
+	Link string
 
 	// In: body
 	Body []getItemResponse
@@ -250,9 +519,11 @@ type getItemsResponseDoc struct {
 //
 // swagger:route GET /api/v1/auctions/items Auctions getItemsRequest
 //
-// Gets all items that are currently stored in the system.
+// Gets a page of the items that are currently stored in the system.
 //
-// This will retrieve all items from storage.
+// This will retrieve a page of items from storage. The items can be narrowed down to a category, to the items that have
+// every one of the tags and to the items that match the search, all ignoring case. The Link header has the URL of the
+// next page, which keeps the same search and order.
 //
 //  Produces:
 //  - application/json
@@ -265,23 +536,180 @@ type getItemsResponseDoc struct {
 //  Responses:
 //    200: getItemsResponse
 func (handler *AuctionHandler) GetItems(w http.ResponseWriter, r *http.Request) error {
-	items, err := handler.auctionItemClient.GetAll(r.Context())
+	options, err := parseListOptions(r.URL.Query())
 	if err != nil {
-		return errors.Wrap(err, "could not retrieve auction item")
+		log.Info(r.Context(), "invalid item listing", "query", r.URL.RawQuery, "err", err)
+		return errors.Wrap(writeItemListError(w, err), "could not list auction items")
+	}
+
+	page, err := handler.auctionItemClient.List(r.Context(), options)
+	if err != nil {
+		return errors.Wrap(writeItemListError(w, err), "could not retrieve auction items")
+	}
+
+	if page.NextCursor != "" {
+		query := r.URL.Query()
+		query.Set("cursor", page.NextCursor)
+		next := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.String()))
+	}
+
+	responseObjects := make([]*getItemResponse, len(page.Items))
+	for i, item := range page.Items {
+		responseObjects[i] = newGetItemResponse(item)
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal item")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+const (
+	// defaultItemLimit and maxItemLimit bound how many items are returned by GetItems at once.
+	defaultItemLimit = 50
+	maxItemLimit     = 200
+)
+
+var (
+	errInvalidItemSort  = errors.New("sort must be one of created, name, currentBid, bidCount or closing")
+	errInvalidItemOrder = errors.New("order must be asc or desc")
+	errInvalidItemLimit = errors.Errorf("limit must be between 1 and %d", maxItemLimit)
+)
+
+// parseListOptions reads the storage.ListOptions from the query parameters of GetItems.
+func parseListOptions(query url.Values) (storage.ListOptions, error) {
+	options := storage.ListOptions{
+		ItemFilter: storage.ItemFilter{
+			Category: query.Get("category"),
+			Tags:     query["tag"],
+		},
+		Search: query.Get("q"),
+		Limit:  defaultItemLimit,
+		Cursor: query.Get("cursor"),
+	}
+
+	switch sortBy := storage.ItemSort(query.Get("sort")); sortBy {
+	case "", storage.ItemSortCreated, storage.ItemSortName, storage.ItemSortCurrentBid, storage.ItemSortBidCount,
+		storage.ItemSortClosing:
+		options.Sort = sortBy
+	default:
+		return options, errors.Wrapf(errInvalidItemSort, "unknown sort '%s'", sortBy)
+	}
+
+	switch order := query.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		options.Descending = true
+	default:
+		return options, errors.Wrapf(errInvalidItemOrder, "unknown order '%s'", order)
 	}
 
-	responseObjects := make([]*getItemResponse, len(items))
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit < 1 || limit > maxItemLimit {
+			return options, errors.Wrapf(errInvalidItemLimit, "invalid limit '%s'", rawLimit)
+		}
+		options.Limit = limit
+	}
+	return options, nil
+}
+
+// writeItemListError responds to a request that could not list the items because of the client. Any other error is
+// returned so it is handled as a server error.
+func writeItemListError(w http.ResponseWriter, err error) error {
+	var message string
+	switch {
+	case errors.Is(err, errInvalidItemSort):
+		message = errInvalidItemSort.Error()
+	case errors.Is(err, errInvalidItemOrder):
+		message = errInvalidItemOrder.Error()
+	case errors.Is(err, errInvalidItemLimit):
+		message = errInvalidItemLimit.Error()
+	case errors.Is(err, storage.ErrInvalidCursor):
+		message = "cursor does not continue the listing"
+	default:
+		return err
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
+	response, err := json.Marshal(newErrorResponse(message))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal error response")
+	}
+	fmt.Fprint(w, string(response))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getDeletedItemsRequestDoc is for swagger generation only.
+// swagger:parameters getDeletedItemsRequest
+type getDeletedItemsRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// Must be "true" to list the deleted items.
+	//
+	// In: query
+	Deleted string `json:"deleted"`
+}
+
+// Contains data about the deleted items and when they were deleted.
+//
+// swagger:response getDeletedItemsResponse
+type getDeletedItemsResponseDoc struct {
+
+	// In: body
+	Body []getDeletedItemResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetDeletedItems is the handler that retrieves all deleted model.AuctionItems as serialized JSON.
+//
+// swagger:route GET /api/v1/auctions/items?deleted=true Auctions getDeletedItemsRequest
+//
+// Gets all items that were deleted and can still be restored.
+//
+// This will retrieve the deleted items from storage in the order they were deleted. This route is only available to
+// Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getDeletedItemsResponse
+func (handler *AuctionHandler) GetDeletedItems(w http.ResponseWriter, r *http.Request) error {
+	items, err := handler.auctionItemClient.GetDeleted(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve deleted auction items")
+	}
+
+	responseObjects := make([]*getDeletedItemResponse, len(items))
 	for i, item := range items {
-		responseObjects[i] = &getItemResponse{
+		responseObjects[i] = &getDeletedItemResponse{
+			ID:          item.ID,
 			Name:        item.Name,
 			ImageRef:    item.ImageRef,
 			Description: item.Description,
+			DeletedAt:   item.DeletedAt,
 		}
 	}
 
 	rawResponse, err := json.Marshal(responseObjects)
 	if err != nil {
-		return errors.Wrap(err, "could not marshal item")
+		return errors.Wrap(err, "could not marshal items")
 	}
 
 	fmt.Fprint(w, string(rawResponse))
@@ -310,7 +738,8 @@ type postItemRequestDoc struct {
 //
 // Creates a new item for auction.
 //
-// This will create a new item available for being auctioned. This route is only available to Admin users.
+// This will create a new item available for being auctioned and return it with the ID used to identify it later. This
+// route is only available to Admin users.
 //
 //  Consumes:
 //  - application/json
@@ -324,7 +753,7 @@ type postItemRequestDoc struct {
 //    api_key:
 //
 //  Responses:
-//    201: noBody
+//    201: getItemResponse
 //    400: errorMessage
 func (handler *AuctionHandler) PostItem(w http.ResponseWriter, r *http.Request) error {
 	rawBody, err := io.ReadAll(r.Body)
@@ -336,32 +765,48 @@ func (handler *AuctionHandler) PostItem(w http.ResponseWriter, r *http.Request)
 	err = json.Unmarshal(rawBody, &request)
 	if err != nil {
 		log.Info(r.Context(), "invalid json request", "body", string(rawBody), "err", err)
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrUnknownCurrency) {
+			return errors.Wrap(writeItemChangeError(w, errFairMarketValueFormat), "could not create item")
+		}
 		w.WriteHeader(http.StatusBadRequest)
 		return nil
 	}
 	defer r.Body.Close()
 
 	newItem := &model.AuctionItem{
-		Name:        request.Name,
-		Description: request.Description,
-		ImageRef:    request.ImageRef,
+		Name:            request.Name,
+		Description:     request.Description,
+		ImageRef:        request.ImageRef,
+		Category:        request.Category,
+		Tags:            cleanTags(request.Tags),
+		DonorName:       request.DonorName,
+		DonorBusiness:   request.DonorBusiness,
+		FairMarketValue: valueOrZero(request.FairMarketValue),
+		Quantity:        request.Quantity,
+		ClosesAt:        request.ClosesAt,
+	}
+	if err = handler.checkFairMarketValue(newItem.FairMarketValue); err != nil {
+		return errors.Wrap(writeItemChangeError(w, err), "could not create item")
+	}
+	if newItem.Quantity < 0 {
+		return errors.Wrap(writeItemChangeError(w, errInvalidQuantity), "could not create item")
 	}
 
 	err = handler.auctionItemClient.Create(r.Context(), newItem)
 	if err != nil {
-		if errors.Is(err, storage.ErrEntityAlreadyExists) {
-			w.WriteHeader(http.StatusBadRequest)
-			response, marshalErr := json.Marshal(newErrorResponse("item already exists"))
-			if marshalErr != nil {
-				return errors.Wrap(marshalErr, "could not marshal error response")
-			}
-			fmt.Fprint(w, string(response))
-			return nil
-		}
-		return errors.Wrap(err, "could not store item")
+		return errors.Wrap(writeItemChangeError(w, err), "could not store item")
+	}
+
+	handler.eventBus.Publish(events.TypeItemCreated, newItemCreatedEvent(newItem))
+
+	rawItem, err := json.Marshal(newGetItemResponse(newItem))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal item")
 	}
 
+	w.Header().Set("ETag", formatETag(newItem.Version))
 	w.WriteHeader(http.StatusCreated)
+	fmt.Fprint(w, string(rawItem))
 	return nil
 }
 
@@ -375,8 +820,16 @@ type putItemRequestDoc struct {
 	// In: header
 	Authorization string
 
+	// The ETag of the item that is being changed.
+	//
+	// In: header
+	// Required: true
+	IfMatch string `json:"If-Match"`
+
+	// ID of the item. The name of the item is also accepted so older clients keep working.
+	//
 	// In: path
-	ItemName string `json:"itemName"`
+	Item string `json:"item"`
 
 	// In: body
 	Body putItemRequest
@@ -386,11 +839,13 @@ type putItemRequestDoc struct {
 
 // PutItem is the handler that updates the fields of a model.AuctionItem.
 //
-// swagger:route PUT /api/v1/auctions/items/{itemName} Auctions putItemRequest
+// swagger:route PUT /api/v1/auctions/items/{item} Auctions putItemRequest
 //
 // Updates fields for an item.
 //
-// This will update an existing item available for being auctioned. This route is only available to Admin users.
+// This will update an existing item available for being auctioned. Setting the name renames the item while keeping its
+// bids. Fields that are empty are kept, use PATCH to remove them. Setting the tags replaces every tag of the item. This
+// route is only available to Admin users.
 //
 //  Consumes:
 //  - application/json
@@ -407,8 +862,10 @@ type putItemRequestDoc struct {
 //    200: noBody
 //    400: errorMessage
 //    404: errorMessage
+//    412: errorMessage
+//    428: errorMessage
 func (handler *AuctionHandler) PutItem(w http.ResponseWriter, r *http.Request) error {
-	itemName := mux.Vars(r)["itemName"]
+	itemReference := mux.Vars(r)["item"]
 
 	rawBody, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -419,58 +876,132 @@ func (handler *AuctionHandler) PutItem(w http.ResponseWriter, r *http.Request) e
 	err = json.Unmarshal(rawBody, &request)
 	if err != nil {
 		log.Info(r.Context(), "invalid json request", "body", string(rawBody), "err", err)
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrUnknownCurrency) {
+			return errors.Wrap(writeItemChangeError(w, errFairMarketValueFormat), "could not update item")
+		}
 		w.WriteHeader(http.StatusBadRequest)
 		return nil
 	}
 	defer r.Body.Close()
 
-	updateFields := &model.AuctionItem{
-		Name:        itemName,
-		Description: request.Description,
-		ImageRef:    request.ImageRef,
+	fairMarketValue := valueOrZero(request.FairMarketValue)
+	if err = handler.checkFairMarketValue(fairMarketValue); err != nil {
+		return errors.Wrap(writeItemChangeError(w, err), "could not update item")
 	}
+	if request.Quantity < 0 {
+		return errors.Wrap(writeItemChangeError(w, errInvalidQuantity), "could not update item")
+	}
+	tags := cleanTags(request.Tags)
+
+	var item *model.AuctionItem
+	var event *events.ItemUpdated
+	err = handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		var err error
+		item, err = getItem(ctx, clients.Items, itemReference)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve item")
+		}
+		if err = checkIfMatch(r, item.Version); err != nil {
+			return err
+		}
 
-	err = handler.auctionItemClient.Update(r.Context(), updateFields)
-	if err != nil {
-		if errors.Is(err, storage.ErrEntityNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			response, marshalErr := json.Marshal(newErrorResponse("item does not exist"))
-			if marshalErr != nil {
-				return errors.Wrap(marshalErr, "could not marshal error response")
-			}
-			fmt.Fprint(w, string(response))
-			return nil
+		event = &events.ItemUpdated{
+			ItemID:          item.ID,
+			ItemName:        item.Name,
+			ImageRef:        request.ImageRef,
+			Description:     request.Description,
+			Category:        request.Category,
+			Tags:            tags,
+			DonorName:       request.DonorName,
+			DonorBusiness:   request.DonorBusiness,
+			FairMarketValue: optionalMoney(fairMarketValue),
+			Quantity:        request.Quantity,
+			ClosesAt:        optionalTime(request.ClosesAt),
+		}
+		if request.Name != "" && request.Name != item.Name {
+			event.ItemName = request.Name
+			event.PreviousName = item.Name
+			item.Name = request.Name
+		}
+		if request.ImageRef != "" {
+			item.ImageRef = request.ImageRef
 		}
-		return errors.Wrap(err, "could not store item")
+		if request.Description != "" {
+			item.Description = request.Description
+		}
+		if request.Category != "" {
+			item.Category = request.Category
+		}
+		if len(tags) > 0 {
+			item.Tags = tags
+		}
+		if request.DonorName != "" {
+			item.DonorName = request.DonorName
+		}
+		if request.DonorBusiness != "" {
+			item.DonorBusiness = request.DonorBusiness
+		}
+		if fairMarketValue.Amount != 0 {
+			item.FairMarketValue = fairMarketValue
+		}
+		if request.Quantity != 0 {
+			item.Quantity = request.Quantity
+		}
+		if !request.ClosesAt.IsZero() {
+			item.ClosesAt = request.ClosesAt
+		}
+
+		return errors.Wrap(clients.Items.Replace(ctx, item), "could not store item")
+	})
+	if err != nil {
+		return errors.Wrap(writeItemChangeError(w, err), "could not update item")
 	}
 
+	handler.eventBus.Publish(events.TypeItemUpdated, event)
+
+	w.Header().Set("ETag", formatETag(item.Version))
 	w.WriteHeader(http.StatusOK)
 	return nil
 }
 
 // ----- Start Documentation Generation Types --------------
 
-// deleteItemRequestDoc is for swagger generation only.
-// swagger:parameters deleteItemRequest
-type deleteItemRequestDoc struct {
+// patchItemRequestDoc is for swagger generation only.
+// swagger:parameters patchItemRequest
+type patchItemRequestDoc struct {
 	// Expected to be "Bearer <auth_token>"
 	//
 	// In: header
 	Authorization string
 
+	// The ETag of the item that is being changed.
+	//
+	// In: header
+	// Required: true
+	IfMatch string `json:"If-Match"`
+
+	// ID of the item. The name of the item is also accepted so older clients keep working.
+	//
 	// In: path
-	ItemName string `json:"itemName"`
+	Item string `json:"item"`
+
+	// In: body
+	Body patchItemRequest
 }
 
 // ----- End Documentation Generation Types --------------
 
-// DeleteItem is the handler that removes a model.AuctionItem from storage.
+// PatchItem is the handler that changes a model.AuctionItem using a JSON Merge Patch.
 //
-// swagger:route DELETE /api/v1/auctions/items/{itemName} Auctions deleteItemRequest
+// swagger:route PATCH /api/v1/auctions/items/{item} Auctions patchItemRequest
 //
-// Deletes an item from the server.
+// Changes fields of an item.
+//
+// This will apply a JSON Merge Patch (RFC 7386) to an existing item. Fields that are left out are kept and fields that
+// are null are removed. The name cannot be removed. This route is only available to Admin users.
 //
-// This will delete an existing item. This route is only available to Admin users.
+//  Consumes:
+//  - application/merge-patch+json
 //
 //  Produces:
 //  - application/json
@@ -481,31 +1012,278 @@ type deleteItemRequestDoc struct {
 //    api_key:
 //
 //  Responses:
-//    200: noBody
+//    200: getItemResponse
+//    400: errorMessage
 //    404: errorMessage
-func (handler *AuctionHandler) DeleteItem(w http.ResponseWriter, r *http.Request) error {
-	itemName := mux.Vars(r)["itemName"]
+//    412: errorMessage
+//    415: errorMessage
+//    428: errorMessage
+func (handler *AuctionHandler) PatchItem(w http.ResponseWriter, r *http.Request) error {
+	itemReference := mux.Vars(r)["item"]
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != mergePatchMediaType {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		response, marshalErr := json.Marshal(newErrorResponse("Content-Type must be %s", mergePatchMediaType))
+		if marshalErr != nil {
+			return errors.Wrap(marshalErr, "could not marshal error response")
+		}
+		fmt.Fprint(w, string(response))
+		return nil
+	}
 
-	err := handler.auctionItemClient.Delete(r.Context(), itemName)
+	rawBody, err := io.ReadAll(r.Body)
 	if err != nil {
-		if errors.Is(err, storage.ErrEntityNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			response, marshalErr := json.Marshal(newErrorResponse("item does not exist"))
-			if marshalErr != nil {
-				return errors.Wrap(marshalErr, "could not marshal error response")
-			}
-			fmt.Fprint(w, string(response))
-			return nil
+		return errors.Wrap(err, "could not read body")
+	}
+	defer r.Body.Close()
+
+	patch, err := parseItemMergePatch(rawBody, handler.currency)
+	if err != nil {
+		log.Info(r.Context(), "invalid merge patch", "body", string(rawBody), "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		response, marshalErr := json.Marshal(newErrorResponse(err.Error()))
+		if marshalErr != nil {
+			return errors.Wrap(marshalErr, "could not marshal error response")
 		}
-		return errors.Wrap(err, "could not delete item")
+		fmt.Fprint(w, string(response))
+		return nil
 	}
 
+	var item *model.AuctionItem
+	var event *events.ItemUpdated
+	err = handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		var err error
+		item, err = getItem(ctx, clients.Items, itemReference)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve item")
+		}
+		if err = checkIfMatch(r, item.Version); err != nil {
+			return err
+		}
+
+		event = &events.ItemUpdated{
+			ItemID:   item.ID,
+			ItemName: item.Name,
+		}
+		if patch.name != nil && *patch.name != item.Name {
+			event.ItemName = *patch.name
+			event.PreviousName = item.Name
+			item.Name = *patch.name
+		}
+		if patch.imageRef != nil {
+			item.ImageRef = *patch.imageRef
+			event.ImageRef = *patch.imageRef
+		}
+		if patch.description != nil {
+			item.Description = *patch.description
+			event.Description = *patch.description
+		}
+		if patch.category != nil {
+			item.Category = *patch.category
+			event.Category = *patch.category
+		}
+		if patch.tags != nil {
+			item.Tags = *patch.tags
+			event.Tags = *patch.tags
+		}
+		if patch.donorName != nil {
+			item.DonorName = *patch.donorName
+			event.DonorName = *patch.donorName
+		}
+		if patch.donorBusiness != nil {
+			item.DonorBusiness = *patch.donorBusiness
+			event.DonorBusiness = *patch.donorBusiness
+		}
+		if patch.fairMarketValue != nil {
+			item.FairMarketValue = *patch.fairMarketValue
+			event.FairMarketValue = optionalMoney(*patch.fairMarketValue)
+		}
+		if patch.quantity != nil {
+			item.Quantity = *patch.quantity
+			event.Quantity = *patch.quantity
+		}
+		if patch.closesAt != nil {
+			item.ClosesAt = *patch.closesAt
+			event.ClosesAt = optionalTime(*patch.closesAt)
+		}
+		event.Cleared = patch.cleared
+
+		return errors.Wrap(clients.Items.Replace(ctx, item), "could not store item")
+	})
+	if err != nil {
+		return errors.Wrap(writeItemChangeError(w, err), "could not patch item")
+	}
+
+	handler.eventBus.Publish(events.TypeItemUpdated, event)
+
+	rawItem, err := json.Marshal(newGetItemResponse(item))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal item")
+	}
+
+	w.Header().Set("ETag", formatETag(item.Version))
+	fmt.Fprint(w, string(rawItem))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// deleteItemRequestDoc is for swagger generation only.
+// swagger:parameters deleteItemRequest
+type deleteItemRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// The ETag of the item that is being changed.
+	//
+	// In: header
+	// Required: true
+	IfMatch string `json:"If-Match"`
+
+	// ID of the item. The name of the item is also accepted so older clients keep working.
+	//
+	// In: path
+	Item string `json:"item"`
+}
+
+// ----- End Documentation Generation Types --------------
+
+// DeleteItem is the handler that removes a model.AuctionItem from storage.
+//
+// swagger:route DELETE /api/v1/auctions/items/{item} Auctions deleteItemRequest
+//
+// Deletes an item from the server.
+//
+// This will delete an existing item. An item sold in a lot must be split from the lot before it can be deleted. This
+// route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: noBody
+//    404: errorMessage
+//    409: errorMessage
+//    412: errorMessage
+//    428: errorMessage
+func (handler *AuctionHandler) DeleteItem(w http.ResponseWriter, r *http.Request) error {
+	itemReference := mux.Vars(r)["item"]
+
+	var item *model.AuctionItem
+	err := handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		var err error
+		item, err = getItem(ctx, clients.Items, itemReference)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve item")
+		}
+		if err = checkIfMatch(r, item.Version); err != nil {
+			return err
+		}
+		return clients.Items.Delete(ctx, item.Name)
+	})
+	if err != nil {
+		return errors.Wrap(writeItemChangeError(w, err), "could not delete item")
+	}
+
+	handler.eventBus.Publish(events.TypeItemDeleted, &events.ItemDeleted{
+		ItemID:   item.ID,
+		ItemName: item.Name,
+	})
+
 	w.WriteHeader(http.StatusOK)
 	return nil
 }
 
 // ----- Start Documentation Generation Types --------------
 
+// restoreItemRequestDoc is for swagger generation only.
+// swagger:parameters restoreItemRequest
+type restoreItemRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the deleted item.
+	//
+	// In: path
+	Item string `json:"item"`
+}
+
+// ----- End Documentation Generation Types --------------
+
+// RestoreItem is the handler that brings back a deleted model.AuctionItem.
+//
+// swagger:route POST /api/v1/auctions/items/{item}/restore Auctions restoreItemRequest
+//
+// Restores a deleted item.
+//
+// This will restore an item that was deleted along with its bids. The item must be referenced by its ID. This route is
+// only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getItemResponse
+//    404: errorMessage
+func (handler *AuctionHandler) RestoreItem(w http.ResponseWriter, r *http.Request) error {
+	itemReference := mux.Vars(r)["item"]
+	r = r.WithContext(log.WithFields(r.Context(), "item", itemReference))
+
+	var item *model.AuctionItem
+	err := handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		id, err := strconv.ParseUint(itemReference, 10, 64)
+		if err != nil {
+			return errors.Wrap(storage.ErrEntityNotFound, "item is not an ID")
+		}
+		if err = clients.Items.Restore(ctx, id); err != nil {
+			return errors.Wrap(err, "could not restore item")
+		}
+		item, err = clients.Items.GetByID(ctx, id)
+		return errors.Wrap(err, "could not retrieve restored item")
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrEntityNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			response, marshalErr := json.Marshal(newErrorResponse("item does not exist"))
+			if marshalErr != nil {
+				return errors.Wrap(marshalErr, "could not marshal error response")
+			}
+			fmt.Fprint(w, string(response))
+			return nil
+		}
+		return errors.Wrap(err, "could not restore item")
+	}
+
+	// Clients treat the restored item as a new one.
+	handler.eventBus.Publish(events.TypeItemCreated, newItemCreatedEvent(item))
+
+	rawItem, err := json.Marshal(newGetItemResponse(item))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal item")
+	}
+
+	w.Header().Set("ETag", formatETag(item.Version))
+	fmt.Fprint(w, string(rawItem))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
 // getHighestBidRequestDoc is for swagger generation only.
 // swagger:parameters getHighestBidRequest
 type getHighestBidRequestDoc struct {
@@ -514,8 +1292,10 @@ type getHighestBidRequestDoc struct {
 	// In: header
 	Authorization string
 
+	// ID of the item. The name of the item is also accepted so older clients keep working.
+	//
 	// In: path
-	ItemName string `json:"itemName"`
+	Item string `json:"item"`
 }
 
 // Contains data about the bid, what the item is, and who made it.
@@ -532,11 +1312,12 @@ type getHighestBidResponseDoc struct {
 // GetHighestBid is the handler that finds the highest bid for a model.AuctionItem and retrieves the model.AuctionBid as
 // serialized JSON.
 //
-// swagger:route GET /api/v1/auctions/bids/{itemName} Auctions getHighestBidRequest
+// swagger:route GET /api/v1/auctions/bids/{item} Auctions getHighestBidRequest
 //
 // Retrieves the highest bid for the specified item.
 //
-// This will retrieve the highest bid for the specified item.
+// This will retrieve the highest bid for the specified item. The highest bid of an item sold in a lot is the highest
+// bid on the lot, which is recorded against the first item of the lot.
 //
 //  Produces:
 //  - application/json
@@ -550,9 +1331,7 @@ type getHighestBidResponseDoc struct {
 //    200: getHighestBidResponse
 //    404: errorMessage
 func (handler *AuctionHandler) GetHighestBid(w http.ResponseWriter, r *http.Request) error {
-	itemName := mux.Vars(r)["itemName"]
-
-	item, err := handler.auctionItemClient.Get(r.Context(), itemName)
+	item, err := getItem(r.Context(), handler.auctionItemClient, mux.Vars(r)["item"])
 	if err != nil {
 		if errors.Is(err, storage.ErrEntityNotFound) {
 			w.WriteHeader(http.StatusNotFound)
@@ -580,18 +1359,7 @@ func (handler *AuctionHandler) GetHighestBid(w http.ResponseWriter, r *http.Requ
 		return errors.Wrap(err, "could not get item")
 	}
 
-	rawResponse, err := json.Marshal(&getHighestBidResponse{
-		BidAmount: highestBid.BidAmount,
-		Bidder: &userResponse{
-			Username:    highestBid.Bidder.Username,
-			DisplayName: highestBid.Bidder.DisplayName,
-		},
-		Item: &itemResponse{
-			Name:        highestBid.Item.Name,
-			Description: highestBid.Item.Description,
-			ImageRef:    highestBid.Item.ImageRef,
-		},
-	})
+	rawResponse, err := json.Marshal(newGetHighestBidResponse(highestBid, handler.locale))
 	if err != nil {
 		errors.Wrap(err, "unable to marshal highest bid response")
 	}
@@ -602,6 +1370,87 @@ func (handler *AuctionHandler) GetHighestBid(w http.ResponseWriter, r *http.Requ
 
 // ----- Start Documentation Generation Types --------------
 
+// getWinningBidsRequestDoc is for swagger generation only.
+// swagger:parameters getWinningBidsRequest
+type getWinningBidsRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the item. The name of the item is also accepted.
+	//
+	// In: path
+	Item string `json:"item"`
+}
+
+// Contains data about every winning bid, what the item is, and who made it.
+//
+// swagger:response getWinningBidsResponse
+type getWinningBidsResponseDoc struct {
+
+	// In: body
+	Body []getHighestBidResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetWinningBids is the handler that finds the bids currently winning a model.AuctionItem and retrieves the
+// model.AuctionBids as serialized JSON.
+//
+// swagger:route GET /api/v1/auctions/bids/{item}/winners Auctions getWinningBidsRequest
+//
+// Retrieves the winning bids for the specified item.
+//
+// This will retrieve the highest bid of each bidder who currently wins the specified item, highest first. An item is
+// won by as many bidders as its quantity and bidders who bid the same amount are ordered by who bid first. The winning
+// bids of an item sold in a lot are the winning bids on the lot, which are recorded against the first item of the lot.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getWinningBidsResponse
+//    404: errorMessage
+func (handler *AuctionHandler) GetWinningBids(w http.ResponseWriter, r *http.Request) error {
+	item, err := getItem(r.Context(), handler.auctionItemClient, mux.Vars(r)["item"])
+	if err != nil {
+		if errors.Is(err, storage.ErrEntityNotFound) {
+			writeErrorMessage(w, http.StatusNotFound, "item does not exist")
+			return nil
+		}
+		return errors.Wrap(err, "could not get item")
+	}
+
+	winningBids, err := handler.auctionBidClient.GetWinningBids(r.Context(), item)
+	if err != nil {
+		if errors.Is(err, storage.ErrEntityNotFound) {
+			writeErrorMessage(w, http.StatusNotFound, "no bids for item")
+			return nil
+		}
+		return errors.Wrap(err, "could not get winning bids")
+	}
+
+	responseObjects := make([]*getHighestBidResponse, len(winningBids))
+	for i, winningBid := range winningBids {
+		responseObjects[i] = newGetHighestBidResponse(winningBid, handler.locale)
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal response")
+	}
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
 // getHighestBidsRequestDoc is for swagger generation only.
 // swagger:parameters getHighestBidsRequest
 type getHighestBidsRequestDoc struct {
@@ -622,14 +1471,16 @@ type getHighestBidsResponseDoc struct {
 
 // ----- End Documentation Generation Types --------------
 
-// GetHighestBid is the handler that finds the highest bids all model.AuctionItems and retrieves all model.AuctionBids as
-// serialized JSON.
+// GetHighestBids is the handler that finds the winning bids of all model.AuctionItems and retrieves the
+// model.AuctionBids as serialized JSON.
 //
 // swagger:route GET /api/v1/auctions/bids Auctions getHighestBidsRequest
 //
-// Retrieves the highest bid for all items.
+// Retrieves the winning bids for all items.
 //
-// This will retrieve the highest bid for all items.
+// This will retrieve every winning bid for all items, ordered by item and then amount. An item is listed once for each
+// bidder who wins it, so an item with a quantity can be listed several times. The winning bids of a lot are recorded
+// against the first item of the lot.
 //
 //  Produces:
 //  - application/json
@@ -642,25 +1493,14 @@ type getHighestBidsResponseDoc struct {
 //  Responses:
 //    200: getHighestBidsResponse
 func (handler *AuctionHandler) GetHighestBids(w http.ResponseWriter, r *http.Request) error {
-	highestBids, err := handler.auctionBidClient.GetAllHighestBids(r.Context())
+	highestBids, err := handler.auctionBidClient.GetAllWinningBids(r.Context())
 	if err != nil {
 		return errors.Wrap(err, "unable to get highest bids")
 	}
 
 	responseObjects := make([]*getHighestBidResponse, len(highestBids))
 	for i, highestBid := range highestBids {
-		responseObjects[i] = &getHighestBidResponse{
-			BidAmount: highestBid.BidAmount,
-			Bidder: &userResponse{
-				Username:    highestBid.Bidder.Username,
-				DisplayName: highestBid.Bidder.DisplayName,
-			},
-			Item: &itemResponse{
-				Name:        highestBid.Item.Name,
-				Description: highestBid.Item.Description,
-				ImageRef:    highestBid.Item.ImageRef,
-			},
-		}
+		responseObjects[i] = newGetHighestBidResponse(highestBid, handler.locale)
 	}
 
 	rawResponse, err := json.Marshal(responseObjects)
@@ -673,6 +1513,59 @@ func (handler *AuctionHandler) GetHighestBids(w http.ResponseWriter, r *http.Req
 
 // ----- Start Documentation Generation Types --------------
 
+// getCurrencyRequestDoc is for swagger generation only.
+// swagger:parameters getCurrencyRequest
+type getCurrencyRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// Contains the currency of the auction and the locale amounts are formatted for.
+//
+// swagger:response getCurrencyResponse
+type getCurrencyResponseDoc struct {
+
+	// In: body
+	Body getCurrencyResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetCurrency is the handler that describes the currency every bid of the auction must be in.
+//
+// swagger:route GET /api/v1/auctions/currency Auctions getCurrencyRequest
+//
+// Retrieves the currency of the auction.
+//
+// This will retrieve the ISO 4217 code of the currency every bid must be in and the locale amounts are formatted for.
+// Amounts are sent as a decimal string followed by the currency, e.g. "12.50 USD".
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getCurrencyResponse
+func (handler *AuctionHandler) GetCurrency(w http.ResponseWriter, r *http.Request) error {
+	rawResponse, err := json.Marshal(&getCurrencyResponse{
+		Currency: handler.currency,
+		Locale:   handler.locale,
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal response")
+	}
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
 // postBidRequestDoc is for swagger generation only.
 // swagger:parameters postBidRequest
 type postBidRequestDoc struct {
@@ -681,8 +1574,10 @@ type postBidRequestDoc struct {
 	// In: header
 	Authorization string
 
+	// ID of the item. The name of the item is also accepted so older clients keep working.
+	//
 	// In: path
-	ItemName string `json:"itemName"`
+	Item string `json:"item"`
 
 	// In: body
 	Body postBidRequest
@@ -692,12 +1587,20 @@ type postBidRequestDoc struct {
 
 // PostBid is the handler that lets a user bid for an existing item.
 //
-// swagger:route POST /api/v1/auctions/bids/{itemName} Auctions postBidRequest
+// swagger:route POST /api/v1/auctions/bids/{item} Auctions postBidRequest
 //
 // Makes a new bid on an item.
 //
-// This will place a bid on the specified item. The user is identified by the authorization token. This is only
-// available for Bidder users.
+// This will place a bid on the specified item. A bid on an item sold in a lot is a bid on the whole lot. The user is
+// identified by the authorization token. This is only available for Bidder users. The previous highest bidder of the
+// item is privately notified that they were outbid.
+//
+// The amount is a decimal string followed by the currency of the auction, e.g. "12.50 USD", so it is never rounded. An
+// integer amount of whole units of the currency, as sent by older clients, is also accepted.
+//
+// A bidder with a spending limit, or any bidder when the auction has one, cannot bid more than their limit less what
+// they are already winning on other items. Such a bid is rejected with 402. A bid on an item whose closing time has
+// passed is rejected with 409.
 //
 //  Consumes:
 //  - application/json
@@ -713,9 +1616,11 @@ type postBidRequestDoc struct {
 //  Responses:
 //    201: noBody
 //    400: errorMessage
+//    402: errorMessage
 //    404: errorMessage
+//    409: errorMessage
 func (handler *AuctionHandler) PostBid(w http.ResponseWriter, r *http.Request) error {
-	itemName := mux.Vars(r)["itemName"]
+	itemReference := mux.Vars(r)["item"]
 	username := auth.ExtractUsername(r.Context())
 
 	rawBody, err := io.ReadAll(r.Body)
@@ -727,53 +1632,350 @@ func (handler *AuctionHandler) PostBid(w http.ResponseWriter, r *http.Request) e
 	err = json.Unmarshal(rawBody, &request)
 	if err != nil {
 		log.Info(r.Context(), "invalid json request", "body", string(rawBody), "err", err)
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrUnknownCurrency) {
+			return errors.Wrap(writeErrorMessage(w, http.StatusBadRequest, errBidAmountFormat.Error()), "could not place bid")
+		}
 		w.WriteHeader(http.StatusBadRequest)
 		return nil
 	}
 	defer r.Body.Close()
 
-	user, err := handler.userClient.Get(r.Context(), username)
+	if request.wholeUnits != nil {
+		request.BidAmount = model.WholeUnits(*request.wholeUnits, handler.currency)
+	}
+
+	_, err = bidding.Place(r.Context(), handler.transactor, handler.eventBus, handler.currency, &bidding.Bid{
+		Username: username,
+		FindItem: func(ctx context.Context, itemClient storage.AuctionItemClient) (*model.AuctionItem, error) {
+			return getItem(ctx, itemClient, itemReference)
+		},
+		Amount: request.BidAmount,
+	})
 	if err != nil {
-		if errors.Is(err, storage.ErrEntityNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			response, marshalErr := json.Marshal(newErrorResponse("user does not exist"))
-			if marshalErr != nil {
-				return errors.Wrap(marshalErr, "could not marshal error response")
-			}
-			fmt.Fprint(w, string(response))
-			return nil
+		var message string
+		var status int
+		switch {
+		case errors.Is(err, bidding.ErrWrongCurrency):
+			status = http.StatusBadRequest
+			message = fmt.Sprintf("%s, which is %s", bidding.ErrWrongCurrency.Error(), handler.currency)
+		case errors.Is(err, bidding.ErrInvalidAmount):
+			status, message = http.StatusBadRequest, bidding.ErrInvalidAmount.Error()
+		case errors.Is(err, bidding.ErrBidderNotFound):
+			status, message = http.StatusNotFound, bidding.ErrBidderNotFound.Error()
+		case errors.Is(err, bidding.ErrItemNotFound):
+			status, message = http.StatusNotFound, bidding.ErrItemNotFound.Error()
+		case errors.Is(err, storage.ErrBidTooLow):
+			status, message = http.StatusBadRequest, "bid too low"
+		case errors.Is(err, storage.ErrOverSpendingLimit):
+			status, message = http.StatusPaymentRequired, "bid would exceed your spending limit"
+		case errors.Is(err, bidding.ErrClosed):
+			status, message = http.StatusConflict, bidding.ErrClosed.Error()
+		default:
+			return errors.Wrap(err, "could not place bid")
 		}
-		return errors.Wrap(err, "could not retrieve user")
+		return errors.Wrap(writeErrorMessage(w, status, message), "could not place bid")
 	}
 
-	item, err := handler.auctionItemClient.Get(r.Context(), itemName)
-	if err != nil {
-		if errors.Is(err, storage.ErrEntityNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			response, marshalErr := json.Marshal(newErrorResponse("item does not exist"))
-			if marshalErr != nil {
-				return errors.Wrap(marshalErr, "could not marshal error response")
-			}
-			fmt.Fprint(w, string(response))
-			return nil
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// newGetHighestBidResponse converts the bid into the response that describes it with the amount formatted for the
+// locale.
+func newGetHighestBidResponse(bid *model.AuctionBid, locale string) *getHighestBidResponse {
+	return &getHighestBidResponse{
+		BidAmount:          bid.BidAmount,
+		FormattedBidAmount: bid.BidAmount.Format(locale),
+		Bidder: &userResponse{
+			Username:    bid.Bidder.Username,
+			DisplayName: bid.Bidder.DisplayName,
+		},
+		Item: &itemResponse{
+			ID:          bid.Item.ID,
+			Name:        bid.Item.Name,
+			Description: bid.Item.Description,
+			ImageRef:    bid.Item.ImageRef,
+			LotID:       bid.Item.LotID,
+		},
+	}
+}
+
+// getItem finds the item by the reference from the path. The reference is the ID of the item, but the name of the item
+// is accepted as well so older clients keep working. A name that is a number is only used if no item has that ID.
+func getItem(ctx context.Context, itemClient storage.AuctionItemClient, reference string) (*model.AuctionItem, error) {
+	if id, err := strconv.ParseUint(reference, 10, 64); err == nil {
+		item, err := itemClient.GetByID(ctx, id)
+		if !errors.Is(err, storage.ErrEntityNotFound) {
+			return item, err
 		}
-		return errors.Wrap(err, "could not retrieve item")
+	}
+	return itemClient.Get(ctx, reference)
+}
+
+// writeItemChangeError responds to a request that could not change an item because of the client. Any other error is
+// returned so it is handled as a server error.
+func writeItemChangeError(w http.ResponseWriter, err error) error {
+	var message string
+	var status int
+	switch {
+	case errors.Is(err, storage.ErrEntityNotFound):
+		status, message = http.StatusNotFound, "item does not exist"
+	case errors.Is(err, storage.ErrEntityAlreadyExists):
+		status, message = http.StatusBadRequest, "item already exists"
+	case errors.Is(err, storage.ErrUnknownCategory):
+		status, message = http.StatusBadRequest, "category does not exist"
+	case errors.Is(err, errNegativeFairMarketValue):
+		status, message = http.StatusBadRequest, errNegativeFairMarketValue.Error()
+	case errors.Is(err, errFairMarketValueFormat):
+		status, message = http.StatusBadRequest, errFairMarketValueFormat.Error()
+	case errors.Is(err, errFairMarketValueCurrency):
+		status, message = http.StatusBadRequest, errFairMarketValueCurrency.Error()
+	case errors.Is(err, errInvalidQuantity):
+		status, message = http.StatusBadRequest, errInvalidQuantity.Error()
+	case errors.Is(err, errIfMatchRequired):
+		status, message = http.StatusPreconditionRequired, "If-Match header with the ETag of the item is required"
+	case errors.Is(err, storage.ErrVersionMismatch):
+		status, message = http.StatusPreconditionFailed, "item was changed since it was retrieved"
+	case errors.Is(err, storage.ErrItemInLot):
+		status, message = http.StatusConflict, "item is sold in a lot and must be split from it first"
+	default:
+		return err
 	}
 
-	_, err = handler.auctionBidClient.PlaceBid(r.Context(), user, item, request.BidAmount)
+	w.WriteHeader(status)
+	response, err := json.Marshal(newErrorResponse(message))
 	if err != nil {
-		if errors.Is(err, storage.ErrBidTooLow) {
-			w.WriteHeader(http.StatusBadRequest)
-			response, marshalErr := json.Marshal(newErrorResponse("bid too low"))
-			if marshalErr != nil {
-				return errors.Wrap(marshalErr, "could not marshal error response")
-			}
-			fmt.Fprint(w, string(response))
-			return nil
+		return errors.Wrap(err, "could not marshal error response")
+	}
+	fmt.Fprint(w, string(response))
+	return nil
+}
+
+// itemMergePatch holds the changes a JSON Merge Patch makes to an item. Fields that are nil are left unchanged.
+type itemMergePatch struct {
+	name            *string
+	imageRef        *string
+	description     *string
+	category        *string
+	tags            *[]string
+	donorName       *string
+	donorBusiness   *string
+	fairMarketValue *model.Money
+	quantity        *int
+	closesAt        *time.Time
+
+	// cleared lists the fields that are removed by the patch.
+	cleared []string
+}
+
+// parseItemMergePatch reads a JSON Merge Patch for an item. A field that is null or empty is removed from the item,
+// which is not allowed for the name. The fair market value must be in the currency. The returned errors are meant for
+// the client.
+func parseItemMergePatch(rawBody []byte, currency string) (*itemMergePatch, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(rawBody, &members); err != nil {
+		return nil, errors.New("merge patch must be a JSON object")
+	}
+
+	for member := range members {
+		switch member {
+		case "name", "image", "description", "category", "tags", "donorName", "donorBusiness", "fairMarketValue",
+			"quantity", "closesAt":
+		default:
+			return nil, errors.Errorf("unknown field '%s'", member)
 		}
-		return errors.Wrap(err, "could not place bid")
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	patch := &itemMergePatch{}
+	var err error
+	if patch.name, err = mergePatchString(members, "name"); err != nil {
+		return nil, err
+	}
+	if patch.name != nil && *patch.name == "" {
+		return nil, errors.New("name cannot be removed")
+	}
+
+	if patch.imageRef, err = mergePatchString(members, "image"); err != nil {
+		return nil, err
+	}
+	if patch.imageRef != nil && *patch.imageRef == "" {
+		patch.cleared = append(patch.cleared, "image")
+	}
+
+	if patch.description, err = mergePatchString(members, "description"); err != nil {
+		return nil, err
+	}
+	if patch.description != nil && *patch.description == "" {
+		patch.cleared = append(patch.cleared, "description")
+	}
+
+	for _, field := range []struct {
+		member string
+		value  **string
+	}{
+		{member: "category", value: &patch.category},
+		{member: "donorName", value: &patch.donorName},
+		{member: "donorBusiness", value: &patch.donorBusiness},
+	} {
+		if *field.value, err = mergePatchString(members, field.member); err != nil {
+			return nil, err
+		}
+		if *field.value != nil && **field.value == "" {
+			patch.cleared = append(patch.cleared, field.member)
+		}
+	}
+
+	if rawTags, ok := members["tags"]; ok {
+		var tags []string
+		if err = json.Unmarshal(rawTags, &tags); err != nil {
+			return nil, errors.New("field 'tags' must be an array of strings or null")
+		}
+		tags = cleanTags(tags)
+		patch.tags = &tags
+		if len(tags) == 0 {
+			patch.cleared = append(patch.cleared, "tags")
+		}
+	}
+
+	if rawValue, ok := members["fairMarketValue"]; ok {
+		var value *model.Money
+		if err = json.Unmarshal(rawValue, &value); err != nil {
+			return nil, errors.New("field 'fairMarketValue' must be a string such as \"150.00 USD\" or null")
+		}
+		fairMarketValue := valueOrZero(value)
+		switch {
+		case fairMarketValue.Amount < 0:
+			return nil, errNegativeFairMarketValue
+		case fairMarketValue.Amount != 0 && fairMarketValue.Currency != currency:
+			return nil, errFairMarketValueCurrency
+		}
+		patch.fairMarketValue = &fairMarketValue
+		if fairMarketValue.Amount == 0 {
+			patch.cleared = append(patch.cleared, "fairMarketValue")
+		}
+	}
+
+	if rawValue, ok := members["quantity"]; ok {
+		var value *int
+		if err = json.Unmarshal(rawValue, &value); err != nil || value == nil {
+			return nil, errors.New("field 'quantity' must be an integer")
+		}
+		if *value < 1 {
+			return nil, errInvalidQuantity
+		}
+		patch.quantity = value
+	}
+
+	if rawValue, ok := members["closesAt"]; ok {
+		var value *time.Time
+		if err = json.Unmarshal(rawValue, &value); err != nil {
+			return nil, errors.New("field 'closesAt' must be an RFC 3339 time or null")
+		}
+		if value == nil {
+			value = &time.Time{}
+			patch.cleared = append(patch.cleared, "closesAt")
+		}
+		patch.closesAt = value
+	}
+	return patch, nil
+}
+
+// mergePatchString returns the string the member of a merge patch is set to. It returns nil when the patch leaves the
+// member out and an empty string when the member is null.
+func mergePatchString(members map[string]json.RawMessage, member string) (*string, error) {
+	rawValue, ok := members[member]
+	if !ok {
+		return nil, nil
+	}
+
+	var value *string
+	if err := json.Unmarshal(rawValue, &value); err != nil {
+		return nil, errors.Errorf("field '%s' must be a string or null", member)
+	}
+	if value == nil {
+		value = new(string)
+	}
+	return value, nil
+}
+
+// newGetItemResponse describes the item the same way for every endpoint that returns it.
+func newGetItemResponse(item *model.AuctionItem) *getItemResponse {
+	return &getItemResponse{
+		ID:              item.ID,
+		Name:            item.Name,
+		ImageRef:        item.ImageRef,
+		Description:     item.Description,
+		Category:        item.Category,
+		Tags:            item.Tags,
+		DonorName:       item.DonorName,
+		DonorBusiness:   item.DonorBusiness,
+		FairMarketValue: optionalMoney(item.FairMarketValue),
+		Quantity:        item.Quantity,
+		LotID:           item.LotID,
+		ClosesAt:        optionalTime(item.ClosesAt),
+	}
+}
+
+// newItemCreatedEvent describes the item to the clients that are told it was created.
+func newItemCreatedEvent(item *model.AuctionItem) *events.ItemCreated {
+	return &events.ItemCreated{
+		ItemID:          item.ID,
+		ItemName:        item.Name,
+		ImageRef:        item.ImageRef,
+		Description:     item.Description,
+		Category:        item.Category,
+		Tags:            item.Tags,
+		DonorName:       item.DonorName,
+		DonorBusiness:   item.DonorBusiness,
+		FairMarketValue: optionalMoney(item.FairMarketValue),
+		Quantity:        item.Quantity,
+		ClosesAt:        optionalTime(item.ClosesAt),
+	}
+}
+
+// checkFairMarketValue returns an error meant for the client unless the value is not negative and is in the currency of
+// the auction. A value of 0 is no value at all, so its currency does not matter.
+func (handler *AuctionHandler) checkFairMarketValue(value model.Money) error {
+	switch {
+	case value.Amount < 0:
+		return errNegativeFairMarketValue
+	case value.Amount != 0 && value.Currency != handler.currency:
+		return errFairMarketValueCurrency
+	}
 	return nil
 }
+
+// valueOrZero returns the amount, or no money at all when the amount is nil or 0.
+func valueOrZero(amount *model.Money) model.Money {
+	if amount == nil || amount.Amount == 0 {
+		return model.Money{}
+	}
+	return *amount
+}
+
+// optionalMoney returns nil for an amount of 0 so it is left out of JSON.
+func optionalMoney(amount model.Money) *model.Money {
+	if amount.Amount == 0 {
+		return nil
+	}
+	return &amount
+}
+
+// optionalTime returns nil for the zero time so it is left out of JSON.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// cleanTags removes the spaces around each tag and drops the tags that are left empty.
+func cleanTags(tags []string) []string {
+	var cleaned []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return cleaned
+}
@@ -2,17 +2,22 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/MMarsolek/AuctionHouse/events"
 	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/storage"
 	"github.com/MMarsolek/AuctionHouse/storage/mocks"
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -25,11 +30,16 @@ type auctionHandlerTestSuite struct {
 	userStoreMock   *mocks.UserClient
 	auctionItemMock *mocks.AuctionItemClient
 	auctionBidMock  *mocks.AuctionBidClient
+	categoryMock    *mocks.CategoryClient
+	lotMock         *mocks.LotClient
+	outbidMock      *mocks.OutbidNoticeClient
+	transactorMock  *mocks.Transactor
+	eventBus        *events.Bus
 	handler         *AuctionHandler
 }
 
 func (ts *auctionHandlerTestSuite) SetupSuite() {
-	ts.handler = NewAuctionHandler(ts.userStoreMock, ts.auctionItemMock, ts.auctionBidMock)
+	ts.handler = NewAuctionHandler(ts.userStoreMock, ts.auctionItemMock, ts.auctionBidMock, ts.transactorMock, ts.eventBus, "USD", "en-US")
 	var router *mux.Router
 	ts.server, router = newTestServer()
 	ts.handler.RegisterRoutes(router)
@@ -40,15 +50,37 @@ func (ts *auctionHandlerTestSuite) SetupTest() {
 	ts.userStoreMock = new(mocks.UserClient)
 	ts.auctionItemMock = new(mocks.AuctionItemClient)
 	ts.auctionBidMock = new(mocks.AuctionBidClient)
+	ts.categoryMock = new(mocks.CategoryClient)
+	ts.lotMock = new(mocks.LotClient)
+	ts.outbidMock = new(mocks.OutbidNoticeClient)
+	ts.transactorMock = new(mocks.Transactor)
+	ts.transactorMock.On("RunInTx", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(
+		func(ctx context.Context, fn func(context.Context, *storage.Clients) error) error {
+			return fn(ctx, &storage.Clients{
+				Users:      ts.userStoreMock,
+				Items:      ts.auctionItemMock,
+				Bids:       ts.auctionBidMock,
+				Categories: ts.categoryMock,
+				Lots:       ts.lotMock,
+				Outbids:    ts.outbidMock,
+			})
+		},
+	).Maybe()
+	ts.eventBus = events.NewBus(events.DefaultHistorySize)
 	ts.handler.userClient = ts.userStoreMock
 	ts.handler.auctionItemClient = ts.auctionItemMock
 	ts.handler.auctionBidClient = ts.auctionBidMock
+	ts.handler.transactor = ts.transactorMock
+	ts.handler.eventBus = ts.eventBus
 }
 
 func (ts *auctionHandlerTestSuite) TearDownTest() {
 	ts.userStoreMock.AssertExpectations(ts.T())
 	ts.auctionItemMock.AssertExpectations(ts.T())
 	ts.auctionBidMock.AssertExpectations(ts.T())
+	ts.categoryMock.AssertExpectations(ts.T())
+	ts.lotMock.AssertExpectations(ts.T())
+	ts.outbidMock.AssertExpectations(ts.T())
 }
 
 func (ts *auctionHandlerTestSuite) TearDownSuite() {
@@ -90,6 +122,58 @@ func (ts *auctionHandlerTestSuite) TestGetItemRetrievesItem() {
 	getItemTest(model.PermissionLevelBidder)
 }
 
+func (ts *auctionHandlerTestSuite) TestGetItemRetrievesItemByID() {
+	item := &model.AuctionItem{ID: 12, Name: "foo", Version: 3}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "items/12", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	ts.Require().EqualValues(`"3"`, response.Header.Get("ETag"))
+	var returnedItem getItemResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&returnedItem))
+	ts.Require().EqualValues(item.ID, returnedItem.ID)
+	ts.Require().EqualValues(item.Name, returnedItem.Name)
+}
+
+func (ts *auctionHandlerTestSuite) TestGetItemFallsBackToNumericName() {
+	item := &model.AuctionItem{ID: 1, Name: "2022"}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(2022)).Return(nil, storage.ErrEntityNotFound)
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "items/2022", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestGetItemRetrievesItemsNamedLikeItemFileRoutes() {
+	imported := &model.AuctionItem{ID: 1, Name: "import"}
+	exported := &model.AuctionItem{ID: 2, Name: "export"}
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), "import").Return(imported, nil)
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), exported.ID).Return(exported, nil)
+
+	// GET /items/export exports the items, so the item named export can only be retrieved by its ID.
+	for _, reference := range []string{"import", "2"} {
+		r := ts.makeAuthenticatedRequest(http.MethodGet, "items/"+reference, nil, &model.User{
+			Permission: model.PermissionLevelBidder,
+		})
+		response, err := ts.client.Do(r)
+		ts.Require().NoError(err)
+		response.Body.Close()
+		ts.Require().EqualValues(http.StatusOK, response.StatusCode, reference)
+	}
+}
+
 func (ts *auctionHandlerTestSuite) TestGetItem404OnNonExistantItem() {
 	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), "whatever").Return(nil, storage.ErrEntityNotFound)
 
@@ -117,7 +201,9 @@ func (ts *auctionHandlerTestSuite) TestGetItemsRetrievesMultipleItems() {
 				Description: "bardescription",
 			},
 		}
-		ts.auctionItemMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return(items, nil)
+		ts.auctionItemMock.On("List", mock.AnythingOfType("*context.valueCtx"), storage.ListOptions{
+			Limit: defaultItemLimit,
+		}).Return(&storage.ItemPage{Items: items}, nil)
 
 		r := ts.makeAuthenticatedRequest(http.MethodGet, "items", nil, &model.User{
 			Permission: model.PermissionLevelBidder,
@@ -127,6 +213,7 @@ func (ts *auctionHandlerTestSuite) TestGetItemsRetrievesMultipleItems() {
 
 		defer response.Body.Close()
 		ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+		ts.Require().Empty(response.Header.Get("Link"))
 		rawResponse, err := io.ReadAll(response.Body)
 		ts.Require().NoError(err)
 		var returnedItems []*getItemResponse
@@ -143,6 +230,122 @@ func (ts *auctionHandlerTestSuite) TestGetItemsRetrievesMultipleItems() {
 	getItemTest(model.PermissionLevelBidder)
 }
 
+func (ts *auctionHandlerTestSuite) TestGetItemsFiltersByCategoryAndTags() {
+	items := []*model.AuctionItem{{ID: 1, Name: "Golf Clubs", Category: "Sports", Tags: []string{"golf", "outdoors"}}}
+	ts.auctionItemMock.On("List", mock.AnythingOfType("*context.valueCtx"), storage.ListOptions{
+		ItemFilter: storage.ItemFilter{
+			Category: "Sports",
+			Tags:     []string{"golf", "outdoors"},
+		},
+		Limit: defaultItemLimit,
+	}).Return(&storage.ItemPage{Items: items}, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "items?category=Sports&tag=golf&tag=outdoors", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var returnedItems []getItemResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&returnedItems))
+	ts.Require().Equal([]getItemResponse{{
+		ID:       1,
+		Name:     "Golf Clubs",
+		Category: "Sports",
+		Tags:     []string{"golf", "outdoors"},
+	}}, returnedItems)
+}
+
+func (ts *auctionHandlerTestSuite) TestGetItemsSearchesSortsAndLinksToNextPage() {
+	items := []*model.AuctionItem{{ID: 4, Name: "Red Wine"}}
+	ts.auctionItemMock.On("List", mock.AnythingOfType("*context.valueCtx"), storage.ListOptions{
+		Search:     "wine",
+		Sort:       storage.ItemSortCurrentBid,
+		Descending: true,
+		Limit:      1,
+		Cursor:     "previous",
+	}).Return(&storage.ItemPage{Items: items, NextCursor: "next"}, nil)
+
+	r := ts.makeAuthenticatedRequest(
+		http.MethodGet, "items?q=wine&sort=currentBid&order=desc&limit=1&cursor=previous", nil, &model.User{
+			Permission: model.PermissionLevelBidder,
+		},
+	)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	ts.Require().Equal(
+		`</api/v1/auctions/items?cursor=next&limit=1&order=desc&q=wine&sort=currentBid>; rel="next"`,
+		response.Header.Get("Link"),
+	)
+	var returnedItems []getItemResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&returnedItems))
+	ts.Require().Equal([]getItemResponse{{ID: 4, Name: "Red Wine"}}, returnedItems)
+}
+
+func (ts *auctionHandlerTestSuite) TestGetItemsSortsByClosingTime() {
+	closesAt := time.Date(2030, time.June, 1, 20, 0, 0, 0, time.UTC)
+	items := []*model.AuctionItem{{ID: 4, Name: "Red Wine", ClosesAt: closesAt}, {ID: 5, Name: "White Wine"}}
+	ts.auctionItemMock.On("List", mock.AnythingOfType("*context.valueCtx"), storage.ListOptions{
+		Sort:  storage.ItemSortClosing,
+		Limit: defaultItemLimit,
+	}).Return(&storage.ItemPage{Items: items}, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "items?sort=closing", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var returnedItems []getItemResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&returnedItems))
+	ts.Require().Len(returnedItems, 2)
+	ts.Require().True(closesAt.Equal(*returnedItems[0].ClosesAt))
+	ts.Require().Nil(returnedItems[1].ClosesAt)
+}
+
+func (ts *auctionHandlerTestSuite) TestGetItems400OnInvalidListOptions() {
+	queries := map[string]string{
+		"sort=ending": "sort must be one of created, name, currentBid, bidCount or closing",
+		"order=up":    "order must be asc or desc",
+		"limit=0":     "limit must be between 1 and 200",
+		"limit=201":   "limit must be between 1 and 200",
+		"limit=ten":   "limit must be between 1 and 200",
+	}
+	for query, message := range queries {
+		r := ts.makeAuthenticatedRequest(http.MethodGet, "items?"+query, nil, &model.User{
+			Permission: model.PermissionLevelBidder,
+		})
+		response, err := ts.client.Do(r)
+		ts.Require().NoError(err)
+		ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode, query)
+
+		var errResponse errorResponse
+		ts.Require().NoError(json.NewDecoder(response.Body).Decode(&errResponse))
+		response.Body.Close()
+		ts.Require().EqualValues(message, errResponse.Message, query)
+	}
+}
+
+func (ts *auctionHandlerTestSuite) TestGetItems400OnInvalidCursor() {
+	ts.auctionItemMock.On("List", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("storage.ListOptions")).Return(
+		nil, errors.Wrap(storage.ErrInvalidCursor, "wrong order"),
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "items?cursor=abc", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
 func (ts *auctionHandlerTestSuite) TestPostItemStoresNewItem() {
 	ts.auctionItemMock.On("Create", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.AuctionItem")).Return(nil)
 	itemRequest := postItemRequest{
@@ -152,13 +355,106 @@ func (ts *auctionHandlerTestSuite) TestPostItemStoresNewItem() {
 	}
 	rawRequest, err := json.Marshal(itemRequest)
 	ts.Require().NoError(err)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
 
 	r := ts.makeAuthenticatedRequest(http.MethodPost, "items", bytes.NewReader(rawRequest), &model.User{
 		Permission: model.PermissionLevelAdmin,
 	})
 	response, err := ts.client.Do(r)
 	ts.Require().NoError(err)
+	defer response.Body.Close()
 	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	var createdItem getItemResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&createdItem))
+	ts.Require().EqualValues(itemRequest.Name, createdItem.Name)
+
+	event := <-sub.Events()
+	ts.Require().EqualValues(events.TypeItemCreated, event.Type)
+	ts.Require().Equal(&events.ItemCreated{
+		ItemName:    itemRequest.Name,
+		ImageRef:    itemRequest.ImageRef,
+		Description: itemRequest.Description,
+	}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostItemStoresCategoryTagsAndDonor() {
+	ts.auctionItemMock.On("Create", mock.AnythingOfType("*context.valueCtx"), &model.AuctionItem{
+		Name:            "Golf Clubs",
+		Category:        "Sports",
+		Tags:            []string{"golf", "outdoors"},
+		DonorName:       "Pat Smith",
+		DonorBusiness:   "Smith Golf",
+		FairMarketValue: usd(50000),
+	}).Return(nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	body := `{"name":"Golf Clubs","category":"Sports","tags":[" golf ","","outdoors"],"donorName":"Pat Smith",` +
+		`"donorBusiness":"Smith Golf","fairMarketValue":"500.00 USD"}`
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items", strings.NewReader(body), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+
+	var createdItem getItemResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&createdItem))
+	fairMarketValue := usd(50000)
+	ts.Require().Equal(getItemResponse{
+		Name:            "Golf Clubs",
+		Category:        "Sports",
+		Tags:            []string{"golf", "outdoors"},
+		DonorName:       "Pat Smith",
+		DonorBusiness:   "Smith Golf",
+		FairMarketValue: &fairMarketValue,
+	}, createdItem)
+
+	event := <-sub.Events()
+	ts.Require().Equal(&events.ItemCreated{
+		ItemName:        "Golf Clubs",
+		Category:        "Sports",
+		Tags:            []string{"golf", "outdoors"},
+		DonorName:       "Pat Smith",
+		DonorBusiness:   "Smith Golf",
+		FairMarketValue: &fairMarketValue,
+	}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostItem400OnUnknownCategory() {
+	ts.auctionItemMock.On("Create", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.AuctionItem")).Return(storage.ErrUnknownCategory)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items", strings.NewReader(`{"name":"item","category":"missing"}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+
+	var errResponse errorResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&errResponse))
+	ts.Require().EqualValues("category does not exist", errResponse.Message)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostItem400OnNegativeFairMarketValue() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items", strings.NewReader(`{"name":"item","fairMarketValue":"-1.00 USD"}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostItem400OnNegativeQuantity() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items", strings.NewReader(`{"name":"item","quantity":-1}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
 }
 
 func (ts *auctionHandlerTestSuite) TestPostItem403OnBidderRequest() {
@@ -189,20 +485,150 @@ func (ts *auctionHandlerTestSuite) TestPostItem400OnItemThatAlreadyExists() {
 }
 
 func (ts *auctionHandlerTestSuite) TestPutItemStoresUpdates() {
-	ts.auctionItemMock.On("Update", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.AuctionItem")).Return(nil)
+	item := &model.AuctionItem{ID: 3, Name: "someItem", ImageRef: "old image", Description: "old description", Version: 2}
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+	ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), &model.AuctionItem{
+		ID:          item.ID,
+		Name:        item.Name,
+		ImageRef:    "old image",
+		Description: "description",
+		Version:     2,
+	}).Return(func(ctx context.Context, item *model.AuctionItem) error {
+		item.Version++
+		return nil
+	})
 	itemRequest := putItemRequest{
-		ImageRef:    "image",
 		Description: "description",
 	}
 	rawRequest, err := json.Marshal(itemRequest)
 	ts.Require().NoError(err)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
 
 	r := ts.makeAuthenticatedRequest(http.MethodPut, "items/someItem", bytes.NewReader(rawRequest), &model.User{
 		Permission: model.PermissionLevelAdmin,
 	})
+	r.Header.Set("If-Match", `"2"`)
 	response, err := ts.client.Do(r)
 	ts.Require().NoError(err)
 	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	ts.Require().EqualValues(`"3"`, response.Header.Get("ETag"))
+
+	event := <-sub.Events()
+	ts.Require().EqualValues(events.TypeItemUpdated, event.Type)
+	ts.Require().Equal(&events.ItemUpdated{
+		ItemID:      item.ID,
+		ItemName:    item.Name,
+		Description: itemRequest.Description,
+	}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestPutItemRenamesItemByID() {
+	item := &model.AuctionItem{ID: 7, Name: "someItem", Version: 1}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), &model.AuctionItem{
+		ID:          item.ID,
+		Name:        "newName",
+		ImageRef:    "image",
+		Description: "description",
+		Version:     1,
+	}).Return(nil)
+	itemRequest := putItemRequest{
+		Name:        "newName",
+		ImageRef:    "image",
+		Description: "description",
+	}
+	rawRequest, err := json.Marshal(itemRequest)
+	ts.Require().NoError(err)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodPut, "items/7", bytes.NewReader(rawRequest), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("If-Match", "*")
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	event := <-sub.Events()
+	ts.Require().EqualValues(events.TypeItemUpdated, event.Type)
+	ts.Require().Equal(&events.ItemUpdated{
+		ItemID:       item.ID,
+		ItemName:     "newName",
+		PreviousName: "someItem",
+		ImageRef:     itemRequest.ImageRef,
+		Description:  itemRequest.Description,
+	}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestPutItem400WhenNewNameTaken() {
+	item := &model.AuctionItem{ID: 7, Name: "someItem", Version: 1}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.AuctionItem")).Return(storage.ErrEntityAlreadyExists)
+	rawRequest, err := json.Marshal(putItemRequest{Name: "taken"})
+	ts.Require().NoError(err)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPut, "items/7", bytes.NewReader(rawRequest), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("If-Match", `"1"`)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestPutItem428WithoutIfMatch() {
+	item := &model.AuctionItem{ID: 7, Name: "someItem", Version: 1}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	rawRequest, err := json.Marshal(putItemRequest{Description: "description"})
+	ts.Require().NoError(err)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPut, "items/7", bytes.NewReader(rawRequest), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusPreconditionRequired, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestPutItem412WhenIfMatchIsStale() {
+	item := &model.AuctionItem{ID: 7, Name: "someItem", Version: 2}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	rawRequest, err := json.Marshal(putItemRequest{Description: "description"})
+	ts.Require().NoError(err)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPut, "items/7", bytes.NewReader(rawRequest), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("If-Match", `"1"`)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusPreconditionFailed, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestPutItem412WhenItemChangesBeforeReplace() {
+	item := &model.AuctionItem{ID: 7, Name: "someItem", Version: 1}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.AuctionItem")).Return(storage.ErrVersionMismatch)
+	rawRequest, err := json.Marshal(putItemRequest{Description: "description"})
+	ts.Require().NoError(err)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodPut, "items/7", bytes.NewReader(rawRequest), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("If-Match", `"1"`)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusPreconditionFailed, response.StatusCode)
+
+	select {
+	case event := <-sub.Events():
+		ts.Failf("unexpected event", "%+v", event)
+	default:
+	}
 }
 
 func (ts *auctionHandlerTestSuite) TestPutItem403OnBidderRequest() {
@@ -215,7 +641,7 @@ func (ts *auctionHandlerTestSuite) TestPutItem403OnBidderRequest() {
 }
 
 func (ts *auctionHandlerTestSuite) TestPutItem404WhenItemNotFound() {
-	ts.auctionItemMock.On("Update", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.AuctionItem")).Return(storage.ErrEntityNotFound)
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), "someItem").Return(nil, storage.ErrEntityNotFound)
 	itemRequest := putItemRequest{
 		ImageRef:    "image",
 		Description: "description",
@@ -226,21 +652,256 @@ func (ts *auctionHandlerTestSuite) TestPutItem404WhenItemNotFound() {
 	r := ts.makeAuthenticatedRequest(http.MethodPut, "items/someItem", bytes.NewReader(rawRequest), &model.User{
 		Permission: model.PermissionLevelAdmin,
 	})
+	r.Header.Set("If-Match", "*")
 	response, err := ts.client.Do(r)
 	ts.Require().NoError(err)
 	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
 }
 
+func (ts *auctionHandlerTestSuite) TestPatchItemClearsNullFields() {
+	item := &model.AuctionItem{ID: 7, Name: "someItem", ImageRef: "image", Description: "description", Version: 4}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), &model.AuctionItem{
+		ID:       item.ID,
+		Name:     "newName",
+		ImageRef: "image",
+		Version:  4,
+	}).Return(func(ctx context.Context, item *model.AuctionItem) error {
+		item.Version++
+		return nil
+	})
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodPatch, "items/7", strings.NewReader(`{"name":"newName","description":null}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("Content-Type", mergePatchMediaType)
+	r.Header.Set("If-Match", `"4"`)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	ts.Require().EqualValues(`"5"`, response.Header.Get("ETag"))
+	rawResponse, err := io.ReadAll(response.Body)
+	ts.Require().NoError(err)
+	var returnedItem getItemResponse
+	ts.Require().NoError(json.Unmarshal(rawResponse, &returnedItem))
+	ts.Require().Equal(getItemResponse{ID: item.ID, Name: "newName", ImageRef: "image"}, returnedItem)
+
+	event := <-sub.Events()
+	ts.Require().EqualValues(events.TypeItemUpdated, event.Type)
+	ts.Require().Equal(&events.ItemUpdated{
+		ItemID:       item.ID,
+		ItemName:     "newName",
+		PreviousName: "someItem",
+		Cleared:      []string{"description"},
+	}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestPatchItemSetsAndClearsClosingTime() {
+	closesAt := time.Date(2030, time.June, 1, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		body     string
+		closesAt time.Time
+		event    *events.ItemUpdated
+	}{
+		{
+			body:     `{"closesAt":"2030-06-01T20:00:00Z"}`,
+			closesAt: closesAt,
+			event:    &events.ItemUpdated{ItemID: 7, ItemName: "someItem", ClosesAt: &closesAt},
+		},
+		{
+			body:  `{"closesAt":null}`,
+			event: &events.ItemUpdated{ItemID: 7, ItemName: "someItem", Cleared: []string{"closesAt"}},
+		},
+	}
+	for _, test := range tests {
+		ts.SetupTest()
+		item := &model.AuctionItem{ID: 7, Name: "someItem", ClosesAt: closesAt.Add(time.Hour), Version: 4}
+		ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+		ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), &model.AuctionItem{
+			ID:       item.ID,
+			Name:     item.Name,
+			ClosesAt: test.closesAt,
+			Version:  4,
+		}).Return(nil)
+		sub := ts.eventBus.Subscribe()
+
+		r := ts.makeAuthenticatedRequest(http.MethodPatch, "items/7", strings.NewReader(test.body), &model.User{
+			Permission: model.PermissionLevelAdmin,
+		})
+		r.Header.Set("Content-Type", mergePatchMediaType)
+		r.Header.Set("If-Match", `"4"`)
+		response, err := ts.client.Do(r)
+		ts.Require().NoError(err)
+		response.Body.Close()
+		ts.Require().EqualValues(http.StatusOK, response.StatusCode, test.body)
+
+		event := <-sub.Events()
+		ts.Require().Equal(test.event, event.Payload, test.body)
+		sub.Close()
+	}
+}
+
+func (ts *auctionHandlerTestSuite) TestPatchItemChangesAndClearsDetails() {
+	item := &model.AuctionItem{
+		ID:              7,
+		Name:            "someItem",
+		Category:        "Sports",
+		Tags:            []string{"golf"},
+		DonorName:       "Pat Smith",
+		DonorBusiness:   "Smith Golf",
+		FairMarketValue: usd(50000),
+		Quantity:        1,
+		Version:         4,
+	}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), &model.AuctionItem{
+		ID:        item.ID,
+		Name:      "someItem",
+		Category:  "Travel",
+		DonorName: "Sam Smith",
+		Quantity:  3,
+		Version:   4,
+	}).Return(nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	body := `{"category":"Travel","tags":null,"donorName":"Sam Smith","donorBusiness":null,"fairMarketValue":null,` +
+		`"quantity":3}`
+	r := ts.makeAuthenticatedRequest(http.MethodPatch, "items/7", strings.NewReader(body), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("Content-Type", mergePatchMediaType)
+	r.Header.Set("If-Match", `"4"`)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	event := <-sub.Events()
+	ts.Require().Equal(&events.ItemUpdated{
+		ItemID:    item.ID,
+		ItemName:  "someItem",
+		Category:  "Travel",
+		DonorName: "Sam Smith",
+		Quantity:  3,
+		Cleared:   []string{"donorBusiness", "tags", "fairMarketValue"},
+	}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestPatchItem400OnInvalidPatch() {
+	bodies := []string{
+		`[]`, `{"name":null}`, `{"name":""}`, `{"description":5}`, `{"id":3}`, `{"tags":"golf"}`,
+		`{"fairMarketValue":5}`, `{"fairMarketValue":"-1.00 USD"}`, `{"fairMarketValue":"5.00 EUR"}`,
+		`{"quantity":0}`, `{"quantity":1.5}`, `{"quantity":null}`,
+	}
+	for _, body := range bodies {
+		r := ts.makeAuthenticatedRequest(http.MethodPatch, "items/7", strings.NewReader(body), &model.User{
+			Permission: model.PermissionLevelAdmin,
+		})
+		r.Header.Set("Content-Type", mergePatchMediaType)
+		r.Header.Set("If-Match", `"1"`)
+		response, err := ts.client.Do(r)
+		ts.Require().NoError(err)
+		ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode, body)
+	}
+}
+
+func (ts *auctionHandlerTestSuite) TestPatchItem415WithoutMergePatchContentType() {
+	r := ts.makeAuthenticatedRequest(http.MethodPatch, "items/7", strings.NewReader(`{"description":null}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("If-Match", `"1"`)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusUnsupportedMediaType, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestPatchItem412WhenIfMatchIsStale() {
+	item := &model.AuctionItem{ID: 7, Name: "someItem", Version: 2}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPatch, "items/7", strings.NewReader(`{"description":null}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("Content-Type", mergePatchMediaType)
+	r.Header.Set("If-Match", `"1"`)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusPreconditionFailed, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestPatchItem403OnBidderRequest() {
+	r := ts.makeAuthenticatedRequest(http.MethodPatch, "items/7", strings.NewReader(`{}`), &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	r.Header.Set("Content-Type", mergePatchMediaType)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
 func (ts *auctionHandlerTestSuite) TestDeleteItemRemovesItemFromStorage() {
 	itemName := "someItem"
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), itemName).Return(&model.AuctionItem{ID: 3, Name: itemName, Version: 1}, nil)
 	ts.auctionItemMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), itemName).Return(nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
 
 	r := ts.makeAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("items/%s", itemName), nil, &model.User{
 		Permission: model.PermissionLevelAdmin,
 	})
+	r.Header.Set("If-Match", `"1"`)
 	response, err := ts.client.Do(r)
 	ts.Require().NoError(err)
 	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	event := <-sub.Events()
+	ts.Require().EqualValues(events.TypeItemDeleted, event.Type)
+	ts.Require().Equal(&events.ItemDeleted{ItemID: 3, ItemName: itemName}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestDeleteItem428WithoutIfMatch() {
+	itemName := "someItem"
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), itemName).Return(&model.AuctionItem{ID: 3, Name: itemName, Version: 1}, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("items/%s", itemName), nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusPreconditionRequired, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestDeleteItem412WhenIfMatchIsStale() {
+	itemName := "someItem"
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), itemName).Return(&model.AuctionItem{ID: 3, Name: itemName, Version: 2}, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("items/%s", itemName), nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("If-Match", `"1"`)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusPreconditionFailed, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestDeleteItem409WhenItemIsInLot() {
+	itemName := "someItem"
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), itemName).Return(&model.AuctionItem{ID: 3, Name: itemName, LotID: 5, Version: 1}, nil)
+	ts.auctionItemMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), itemName).Return(storage.ErrItemInLot)
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("items/%s", itemName), nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("If-Match", `"1"`)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusConflict, response.StatusCode)
 }
 
 func (ts *auctionHandlerTestSuite) TestDeleteItem403OnBidderRequest() {
@@ -254,7 +915,7 @@ func (ts *auctionHandlerTestSuite) TestDeleteItem403OnBidderRequest() {
 
 func (ts *auctionHandlerTestSuite) TestDeleteItem404WhenItemDoesNotExist() {
 	itemName := "someItem"
-	ts.auctionItemMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), itemName).Return(storage.ErrEntityNotFound)
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), itemName).Return(nil, storage.ErrEntityNotFound)
 
 	r := ts.makeAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("items/%s", itemName), nil, &model.User{
 		Permission: model.PermissionLevelAdmin,
@@ -264,6 +925,102 @@ func (ts *auctionHandlerTestSuite) TestDeleteItem404WhenItemDoesNotExist() {
 	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
 }
 
+func (ts *auctionHandlerTestSuite) TestGetDeletedItemsRetrievesDeletedItems() {
+	deletedAt := time.Date(2021, time.October, 3, 12, 0, 0, 0, time.UTC)
+	items := []*model.AuctionItem{
+		{ID: 4, Name: "foo", Description: "description", DeletedAt: deletedAt},
+		{ID: 7, Name: "bar", ImageRef: "barref", DeletedAt: deletedAt.Add(time.Hour)},
+	}
+	ts.auctionItemMock.On("GetDeleted", mock.AnythingOfType("*context.valueCtx")).Return(items, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "items?deleted=true", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	rawResponse, err := io.ReadAll(response.Body)
+	ts.Require().NoError(err)
+	var returnedItems []*getDeletedItemResponse
+	ts.Require().NoError(json.Unmarshal(rawResponse, &returnedItems))
+
+	ts.Require().Len(returnedItems, len(items))
+	for i, item := range items {
+		ts.Require().EqualValues(item.ID, returnedItems[i].ID)
+		ts.Require().EqualValues(item.Name, returnedItems[i].Name)
+		ts.Require().EqualValues(item.ImageRef, returnedItems[i].ImageRef)
+		ts.Require().EqualValues(item.Description, returnedItems[i].Description)
+		ts.Require().True(item.DeletedAt.Equal(returnedItems[i].DeletedAt))
+	}
+}
+
+func (ts *auctionHandlerTestSuite) TestGetDeletedItems403OnBidderRequest() {
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "items?deleted=true", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestRestoreItemRestoresItemAndPublishesItemCreated() {
+	item := &model.AuctionItem{ID: 3, Name: "someItem", Description: "description"}
+	ts.auctionItemMock.On("Restore", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(nil)
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items/3/restore", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	rawResponse, err := io.ReadAll(response.Body)
+	ts.Require().NoError(err)
+	var returnedItem getItemResponse
+	ts.Require().NoError(json.Unmarshal(rawResponse, &returnedItem))
+	ts.Require().EqualValues(item.ID, returnedItem.ID)
+	ts.Require().EqualValues(item.Name, returnedItem.Name)
+
+	event := <-sub.Events()
+	ts.Require().EqualValues(events.TypeItemCreated, event.Type)
+	ts.Require().Equal(&events.ItemCreated{ItemID: 3, ItemName: item.Name, Description: item.Description}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestRestoreItem404WhenItemNotDeleted() {
+	ts.auctionItemMock.On("Restore", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(storage.ErrEntityNotFound)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items/3/restore", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestRestoreItem404WhenItemIsNotAnID() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items/someItem/restore", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestRestoreItem403OnBidderRequest() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items/3/restore", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
 func (ts *auctionHandlerTestSuite) TestGetHighestBidRetrievesBidFromStorage() {
 	getHighestBidTest := func(permission model.PermissionLevel) {
 		item := &model.AuctionItem{
@@ -271,7 +1028,7 @@ func (ts *auctionHandlerTestSuite) TestGetHighestBidRetrievesBidFromStorage() {
 		}
 		ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
 		highestBid := &model.AuctionBid{
-			BidAmount: 100,
+			BidAmount: model.Money{Amount: 100, Currency: "USD"},
 			Item:      item,
 			Bidder: &model.User{
 				Username: "user",
@@ -293,7 +1050,8 @@ func (ts *auctionHandlerTestSuite) TestGetHighestBidRetrievesBidFromStorage() {
 		ts.Require().NoError(json.Unmarshal(rawResponse, &bidResponse))
 
 		ts.Require().EqualValues(highestBid.BidAmount, bidResponse.BidAmount)
-		ts.Require().EqualValues(highestBid.Item, bidResponse.Item)
+		ts.Require().EqualValues(highestBid.Item.ID, bidResponse.Item.ID)
+		ts.Require().EqualValues(highestBid.Item.Name, bidResponse.Item.Name)
 		ts.Require().EqualValues(highestBid.Bidder.Username, bidResponse.Bidder.Username)
 	}
 
@@ -337,11 +1095,56 @@ func (ts *auctionHandlerTestSuite) TestGetHighestBid404WhenItemHasNoBids() {
 	getHighestBidTest(model.PermissionLevelBidder)
 }
 
+func (ts *auctionHandlerTestSuite) TestGetWinningBidsRetrievesEveryWinnerOfItem() {
+	getWinningBidsTest := func(permission model.PermissionLevel) {
+		item := &model.AuctionItem{ID: 2, Name: "item", Quantity: 2}
+		ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+		winningBids := []*model.AuctionBid{
+			{BidAmount: model.Money{Amount: 150, Currency: "USD"}, Item: item, Bidder: &model.User{Username: "user1"}},
+			{BidAmount: model.Money{Amount: 100, Currency: "USD"}, Item: item, Bidder: &model.User{Username: "user2"}},
+		}
+		ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(winningBids, nil)
+
+		r := ts.makeAuthenticatedRequest(http.MethodGet, fmt.Sprintf("bids/%s/winners", item.Name), nil, &model.User{
+			Permission: permission,
+		})
+		response, err := ts.client.Do(r)
+		ts.Require().NoError(err)
+		ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+		defer response.Body.Close()
+		var bidResponses []*getHighestBidResponse
+		ts.Require().NoError(json.NewDecoder(response.Body).Decode(&bidResponses))
+		ts.Require().Len(bidResponses, 2)
+		for i, winningBid := range winningBids {
+			ts.Require().EqualValues(winningBid.BidAmount, bidResponses[i].BidAmount)
+			ts.Require().EqualValues(item.ID, bidResponses[i].Item.ID)
+			ts.Require().EqualValues(winningBid.Bidder.Username, bidResponses[i].Bidder.Username)
+		}
+	}
+
+	getWinningBidsTest(model.PermissionLevelAdmin)
+	getWinningBidsTest(model.PermissionLevelBidder)
+}
+
+func (ts *auctionHandlerTestSuite) TestGetWinningBids404WhenItemHasNoBids() {
+	item := &model.AuctionItem{Name: "item"}
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "bids/item/winners", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
 func (ts *auctionHandlerTestSuite) TestGetHighestBidsRetrievesAllBidsForEveryItem() {
 	getHighestBidsTest := func(permission model.PermissionLevel) {
 		items := []*model.AuctionBid{
 			{
-				BidAmount: 100,
+				BidAmount: model.Money{Amount: 100, Currency: "USD"},
 				Item: &model.AuctionItem{
 					Name: "item1",
 				},
@@ -350,7 +1153,7 @@ func (ts *auctionHandlerTestSuite) TestGetHighestBidsRetrievesAllBidsForEveryIte
 				},
 			},
 			{
-				BidAmount: 150,
+				BidAmount: model.Money{Amount: 150, Currency: "USD"},
 				Item: &model.AuctionItem{
 					Name: "item2",
 				},
@@ -359,7 +1162,7 @@ func (ts *auctionHandlerTestSuite) TestGetHighestBidsRetrievesAllBidsForEveryIte
 				},
 			},
 		}
-		ts.auctionBidMock.On("GetAllHighestBids", mock.AnythingOfType("*context.valueCtx")).Return(items, nil)
+		ts.auctionBidMock.On("GetAllWinningBids", mock.AnythingOfType("*context.valueCtx")).Return(items, nil)
 
 		r := ts.makeAuthenticatedRequest(http.MethodGet, "bids", nil, &model.User{
 			Permission: permission,
@@ -397,8 +1200,9 @@ func (ts *auctionHandlerTestSuite) TestPostBidPlacesBidOnItem() {
 		ImageRef:    "image",
 		Description: "desc",
 	}
-	bidAmount := 100
+	bidAmount := model.Money{Amount: 100, Currency: "USD"}
 	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
 	ts.auctionBidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
 
 	rawRequest, err := json.Marshal(postBidRequest{
@@ -411,6 +1215,166 @@ func (ts *auctionHandlerTestSuite) TestPostBidPlacesBidOnItem() {
 	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
 }
 
+func (ts *auctionHandlerTestSuite) TestPostBidPublishesBidPlacedWithPreviousBidder() {
+	user := &model.User{
+		Username:    "user1",
+		DisplayName: "User 1",
+		Permission:  model.PermissionLevelBidder,
+	}
+	previousBidder := &model.User{
+		Username:    "user2",
+		DisplayName: "User 2",
+		Permission:  model.PermissionLevelBidder,
+	}
+	ts.userStoreMock.On("Get", mock.AnythingOfType("*context.valueCtx"), user.Username).Return(user, nil)
+	item := &model.AuctionItem{
+		Name:        "Item1",
+		ImageRef:    "image",
+		Description: "desc",
+	}
+	bidAmount := model.Money{Amount: 100, Currency: "USD"}
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return([]*model.AuctionBid{{
+		BidAmount: model.Money{Amount: 50, Currency: "USD"},
+		Bidder:    previousBidder,
+		Item:      item,
+	}}, nil).Once()
+	ts.auctionBidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return([]*model.AuctionBid{{
+		BidAmount: bidAmount,
+		Bidder:    user,
+		Item:      item,
+	}}, nil).Once()
+	ts.outbidMock.On("Add", mock.AnythingOfType("*context.valueCtx"), &model.OutbidNotice{
+		Username:   previousBidder.Username,
+		ItemName:   item.Name,
+		CurrentBid: bidAmount,
+		MinimumBid: model.Money{Amount: 101, Currency: "USD"},
+	}).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.OutbidNotice).ID = 5
+	})
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	rawRequest, err := json.Marshal(postBidRequest{
+		BidAmount: bidAmount,
+	})
+	ts.Require().NoError(err)
+	r := ts.makeAuthenticatedRequest(http.MethodPost, fmt.Sprintf("bids/%s", item.Name), bytes.NewReader(rawRequest), user)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+
+	event := <-sub.Events()
+	ts.Require().EqualValues(events.TypeBidPlaced, event.Type)
+	ts.Require().Equal(&events.BidPlaced{
+		ItemName:       item.Name,
+		Username:       user.Username,
+		Amount:         bidAmount,
+		PreviousBidder: previousBidder.Username,
+		MinimumBid:     model.Money{Amount: 101, Currency: "USD"},
+		NoticeID:       5,
+	}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostBidPublishesMinimumBidThatBeatsLowestWinningBid() {
+	user := &model.User{Username: "user1", Permission: model.PermissionLevelBidder}
+	second := &model.User{Username: "user2", Permission: model.PermissionLevelBidder}
+	third := &model.User{Username: "user3", Permission: model.PermissionLevelBidder}
+	ts.userStoreMock.On("Get", mock.AnythingOfType("*context.valueCtx"), user.Username).Return(user, nil)
+	item := &model.AuctionItem{Name: "Item1", Quantity: 2}
+	bidAmount := model.Money{Amount: 10000, Currency: "USD"}
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return([]*model.AuctionBid{
+		{BidAmount: model.Money{Amount: 8000, Currency: "USD"}, Bidder: second, Item: item},
+		{BidAmount: model.Money{Amount: 5000, Currency: "USD"}, Bidder: third, Item: item},
+	}, nil).Once()
+	ts.auctionBidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return([]*model.AuctionBid{
+		{BidAmount: bidAmount, Bidder: user, Item: item},
+		{BidAmount: model.Money{Amount: 8000, Currency: "USD"}, Bidder: second, Item: item},
+	}, nil).Once()
+	ts.outbidMock.On("Add", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "bids/Item1", strings.NewReader(`{"bidAmount":"100.00 USD"}`), user)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+
+	event := <-sub.Events()
+	bid := event.Payload.(*events.BidPlaced)
+	ts.Require().EqualValues(third.Username, bid.PreviousBidder)
+	ts.Require().EqualValues(model.Money{Amount: 8001, Currency: "USD"}, bid.MinimumBid)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostBidPublishesNoPreviousBidderWhileWinningPlaceIsOpen() {
+	user := &model.User{Username: "user1", Permission: model.PermissionLevelBidder}
+	ts.userStoreMock.On("Get", mock.AnythingOfType("*context.valueCtx"), user.Username).Return(user, nil)
+	item := &model.AuctionItem{Name: "Item1", Quantity: 2}
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return([]*model.AuctionBid{{
+		BidAmount: model.Money{Amount: 50, Currency: "USD"},
+		Bidder:    &model.User{Username: "user2"},
+		Item:      item,
+	}}, nil)
+	bidAmount := model.Money{Amount: 40, Currency: "USD"}
+	ts.auctionBidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, fmt.Sprintf("bids/%s", item.Name), strings.NewReader(`{"bidAmount":"0.40 USD"}`), user)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+
+	event := <-sub.Events()
+	ts.Require().Equal(&events.BidPlaced{ItemName: item.Name, Username: user.Username, Amount: bidAmount}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostBidPublishesLotOfItemInLot() {
+	user := &model.User{
+		Username:   "user1",
+		Permission: model.PermissionLevelBidder,
+	}
+	ts.userStoreMock.On("Get", mock.AnythingOfType("*context.valueCtx"), user.Username).Return(user, nil)
+	item := &model.AuctionItem{
+		ID:    4,
+		Name:  "Theater Tickets",
+		LotID: 9,
+	}
+	bidAmount := model.Money{Amount: 100, Currency: "USD"}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.lotMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.LotID).Return(&model.Lot{
+		ID:   item.LotID,
+		Name: "Dinner and a Show",
+	}, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
+	ts.auctionBidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	rawRequest, err := json.Marshal(postBidRequest{
+		BidAmount: bidAmount,
+	})
+	ts.Require().NoError(err)
+	r := ts.makeAuthenticatedRequest(http.MethodPost, fmt.Sprintf("bids/%d", item.ID), bytes.NewReader(rawRequest), user)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+
+	event := <-sub.Events()
+	ts.Require().Equal(&events.BidPlaced{
+		ItemID:   item.ID,
+		ItemName: item.Name,
+		Username: user.Username,
+		Amount:   bidAmount,
+		LotID:    item.LotID,
+		LotName:  "Dinner and a Show",
+	}, event.Payload)
+}
+
 func (ts *auctionHandlerTestSuite) TestPostBid404WhenUserNotFound() {
 	user := &model.User{
 		Username:    "user1",
@@ -420,7 +1384,7 @@ func (ts *auctionHandlerTestSuite) TestPostBid404WhenUserNotFound() {
 	ts.userStoreMock.On("Get", mock.AnythingOfType("*context.valueCtx"), user.Username).Return(nil, storage.ErrEntityNotFound)
 
 	rawRequest, err := json.Marshal(postBidRequest{
-		BidAmount: 100,
+		BidAmount: model.Money{Amount: 100, Currency: "USD"},
 	})
 	ts.Require().NoError(err)
 	r := ts.makeAuthenticatedRequest(http.MethodPost, "bids/doesnotmatter", bytes.NewReader(rawRequest), user)
@@ -444,7 +1408,7 @@ func (ts *auctionHandlerTestSuite) TestPostBid404WhenItemNotFound() {
 	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(nil, storage.ErrEntityNotFound)
 
 	rawRequest, err := json.Marshal(postBidRequest{
-		BidAmount: 100,
+		BidAmount: model.Money{Amount: 100, Currency: "USD"},
 	})
 	ts.Require().NoError(err)
 	r := ts.makeAuthenticatedRequest(http.MethodPost, fmt.Sprintf("bids/%s", item.Name), bytes.NewReader(rawRequest), user)
@@ -465,8 +1429,9 @@ func (ts *auctionHandlerTestSuite) TestPostBid400WhenBidTooLow() {
 		ImageRef:    "image",
 		Description: "desc",
 	}
-	bidAmount := 100
+	bidAmount := model.Money{Amount: 100, Currency: "USD"}
 	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
 	ts.auctionBidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, storage.ErrBidTooLow)
 
 	rawRequest, err := json.Marshal(postBidRequest{
@@ -479,6 +1444,133 @@ func (ts *auctionHandlerTestSuite) TestPostBid400WhenBidTooLow() {
 	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
 }
 
+func (ts *auctionHandlerTestSuite) TestPostBid409WhenItemHasClosed() {
+	user := &model.User{
+		Username:   "user1",
+		Permission: model.PermissionLevelBidder,
+	}
+	ts.userStoreMock.On("Get", mock.AnythingOfType("*context.valueCtx"), user.Username).Return(user, nil)
+	item := &model.AuctionItem{
+		Name:     "Item1",
+		ClosesAt: time.Now().Add(-time.Minute),
+	}
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "bids/Item1", strings.NewReader(`{"bidAmount":"50.00 USD"}`), user)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusConflict, response.StatusCode)
+	var errResponse errorResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&errResponse))
+	ts.Require().EqualValues("bidding on the item has closed", errResponse.Message)
+	ts.auctionBidMock.AssertNotCalled(ts.T(), "PlaceBid", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostBid402WhenBidIsOverSpendingLimit() {
+	user := &model.User{
+		Username:   "user1",
+		Permission: model.PermissionLevelBidder,
+	}
+	ts.userStoreMock.On("Get", mock.AnythingOfType("*context.valueCtx"), user.Username).Return(user, nil)
+	item := &model.AuctionItem{
+		Name: "Item1",
+	}
+	bidAmount := model.Money{Amount: 5000, Currency: "USD"}
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
+	ts.auctionBidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, storage.ErrOverSpendingLimit)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "bids/Item1", strings.NewReader(`{"bidAmount":"50.00 USD"}`), user)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusPaymentRequired, response.StatusCode)
+	var errResponse errorResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&errResponse))
+	ts.Require().EqualValues("bid would exceed your spending limit", errResponse.Message)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostBidAcceptsWholeUnitsFromOlderClients() {
+	user := &model.User{
+		Username:   "user1",
+		Permission: model.PermissionLevelBidder,
+	}
+	ts.userStoreMock.On("Get", mock.AnythingOfType("*context.valueCtx"), user.Username).Return(user, nil)
+	item := &model.AuctionItem{Name: "Item1"}
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), item.Name).Return(item, nil)
+	ts.auctionBidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
+	bidAmount := model.Money{Amount: 2500, Currency: "USD"}
+	ts.auctionBidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "bids/Item1", strings.NewReader(`{"bidAmount":25}`), user)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostBid400WhenBidIsNotInCurrencyOfAuction() {
+	tests := map[string]string{
+		`{"bidAmount":"100 EUR"}`:   "bid is not in the currency of the auction, which is USD",
+		`{"bidAmount":"0.00 USD"}`:  "bid amount must be more than 0",
+		`{"bidAmount":0}`:           "bid amount must be more than 0",
+		`{"bidAmount":"1.005 USD"}`: "bid amount must be a string such as \"12.50 USD\"",
+	}
+	for body, message := range tests {
+		r := ts.makeAuthenticatedRequest(http.MethodPost, "bids/Item1", strings.NewReader(body), &model.User{
+			Permission: model.PermissionLevelBidder,
+		})
+		response, err := ts.client.Do(r)
+		ts.Require().NoError(err)
+		ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode, body)
+
+		var errResponse errorResponse
+		ts.Require().NoError(json.NewDecoder(response.Body).Decode(&errResponse), body)
+		response.Body.Close()
+		ts.Require().EqualValues(message, errResponse.Message, body)
+	}
+}
+
+func (ts *auctionHandlerTestSuite) TestGetCurrencyReturnsCurrencyAndLocaleOfAuction() {
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "currency", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	var currency getCurrencyResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&currency))
+	ts.Require().EqualValues(getCurrencyResponse{Currency: "USD", Locale: "en-US"}, currency)
+}
+
+func (ts *auctionHandlerTestSuite) TestPostBid500AndNoEventWhenTransactionFails() {
+	user := &model.User{
+		Username:   "user1",
+		Permission: model.PermissionLevelBidder,
+	}
+	ts.transactorMock = new(mocks.Transactor)
+	ts.transactorMock.On("RunInTx", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(errors.New("commit failed"))
+	ts.handler.transactor = ts.transactorMock
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	rawRequest, err := json.Marshal(postBidRequest{
+		BidAmount: model.Money{Amount: 100, Currency: "USD"},
+	})
+	ts.Require().NoError(err)
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "bids/Item1", bytes.NewReader(rawRequest), user)
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusInternalServerError, response.StatusCode)
+	ts.Require().Empty(sub.Events())
+	ts.transactorMock.AssertExpectations(ts.T())
+}
+
 func (ts *auctionHandlerTestSuite) TestPostBid403OnAdminRequest() {
 	r := ts.makeAuthenticatedRequest(http.MethodPost, "bids/someItem", nil, &model.User{
 		Permission: model.PermissionLevelAdmin,
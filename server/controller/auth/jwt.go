@@ -21,10 +21,25 @@ type userTokenPayload struct {
 	Permission model.PermissionLevel `json:"permission"`
 }
 
-var secretHash jwt.Algorithm
+// minimumSecretLength is the length of the random secret used when no secret is configured.
+const minimumSecretLength = 16
+
+const (
+	// DefaultIssuer is the issuer of tokens when no issuer is configured.
+	DefaultIssuer = "AuctionHouse"
+
+	// DefaultTokenLifetime is how long tokens are valid when no lifetime is configured.
+	DefaultTokenLifetime = 8 * time.Hour
+)
+
+var (
+	secretHash    jwt.Algorithm
+	issuer        = DefaultIssuer
+	tokenLifetime = DefaultTokenLifetime
+)
 
 func init() {
-	secret := make([]byte, 16)
+	secret := make([]byte, minimumSecretLength)
 	_, err := rand.Read(secret)
 	if err != nil {
 		panic(err)
@@ -33,14 +48,44 @@ func init() {
 	secretHash = jwt.NewHS256(secret)
 }
 
+// TokenSettings controls how tokens are signed and how long they are valid.
+type TokenSettings struct {
+	// Secret signs every token. Tokens signed by one server are accepted by every server sharing the secret. A random
+	// secret is kept when this is empty, which logs everyone out when the server restarts.
+	Secret []byte
+
+	// Issuer is stored in every token.
+	Issuer string
+
+	// Lifetime is how long a token is valid after it is created.
+	Lifetime time.Duration
+}
+
+// Configure changes how tokens are created and verified. This must be called before the server starts.
+func Configure(settings TokenSettings) error {
+	if len(settings.Secret) > 0 && len(settings.Secret) < minimumSecretLength {
+		return errors.Errorf("secret must be at least %d bytes", minimumSecretLength)
+	}
+	if settings.Lifetime <= 0 {
+		return errors.New("lifetime must be greater than 0")
+	}
+
+	if len(settings.Secret) > 0 {
+		secretHash = jwt.NewHS256(settings.Secret)
+	}
+	issuer = settings.Issuer
+	tokenLifetime = settings.Lifetime
+	return nil
+}
+
 // NewToken creates a new token for the specified user.
 func NewToken(user *model.User) ([]byte, error) {
 	now := time.Now().UTC()
 	payload := userTokenPayload{
 		Payload: jwt.Payload{
-			Issuer:         "AuctionHouse",
+			Issuer:         issuer,
 			Subject:        "user",
-			ExpirationTime: jwt.NumericDate(now.Add(time.Hour * 8)),
+			ExpirationTime: jwt.NumericDate(now.Add(tokenLifetime)),
 			IssuedAt:       jwt.NumericDate(now),
 		},
 		Username:   user.Username,
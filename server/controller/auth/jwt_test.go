@@ -5,8 +5,10 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/gbrlsnchs/jwt/v3"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,3 +48,32 @@ func TestVerifyTokenReturnsVerifiedTokenWithPayload(t *testing.T) {
 	require.EqualValues(t, user.Username, result.Username)
 	require.EqualValues(t, user.Permission, result.Permission)
 }
+
+func TestConfigureSetsSecretIssuerAndLifetime(t *testing.T) {
+	previousHash, previousIssuer, previousLifetime := secretHash, issuer, tokenLifetime
+	defer func() {
+		secretHash, issuer, tokenLifetime = previousHash, previousIssuer, previousLifetime
+	}()
+
+	require.NoError(t, Configure(TokenSettings{
+		Secret:   []byte("a shared secret for every server"),
+		Issuer:   "biddr",
+		Lifetime: time.Minute,
+	}))
+	rawToken, err := NewToken(&model.User{Username: "hunter"})
+	require.NoError(t, err)
+
+	var payload jwt.Payload
+	_, err = jwt.Verify(rawToken, jwt.NewHS256([]byte("a shared secret for every server")), &payload)
+	require.NoError(t, err)
+	require.EqualValues(t, "biddr", payload.Issuer)
+	require.EqualValues(t, time.Minute, payload.ExpirationTime.Sub(payload.IssuedAt.Time))
+}
+
+func TestConfigureRejectsShortSecret(t *testing.T) {
+	require.Error(t, Configure(TokenSettings{Secret: []byte("short"), Lifetime: time.Hour}))
+}
+
+func TestConfigureRejectsZeroLifetime(t *testing.T) {
+	require.Error(t, Configure(TokenSettings{Lifetime: 0}))
+}
@@ -0,0 +1,156 @@
+// Package bidding places bids for every transport so a bid is checked, stored and published the same way whether it
+// arrives over HTTP or a websocket. Handlers only translate the errors for their clients. It also announces when
+// bidding on an item closes.
+package bidding
+
+import (
+	"context"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrWrongCurrency  = errors.New("bid is not in the currency of the auction")
+	ErrInvalidAmount  = errors.New("bid amount must be more than 0")
+	ErrBidderNotFound = errors.New("user does not exist")
+	ErrItemNotFound   = errors.New("item does not exist")
+	ErrClosed         = errors.New("bidding on the item has closed")
+)
+
+// ItemFinder finds the item a bid is placed on using the client of the transaction the bid is placed in. It returns
+// storage.ErrEntityNotFound when there is no such item.
+type ItemFinder func(ctx context.Context, itemClient storage.AuctionItemClient) (*model.AuctionItem, error)
+
+// Bid describes a bid a user wants to place.
+type Bid struct {
+	Username string
+	FindItem ItemFinder
+	Amount   model.Money
+}
+
+// Place places the bid in a single transaction and publishes it on the eventBus. The bidder who lost their winning place
+// to the bid is published with it, along with the least they have to bid to win a place back and the model.OutbidNotice
+// stored for them.
+//
+// This will return ErrWrongCurrency if the bid is not in the currency of the event, ErrInvalidAmount unless the bid is
+// more than 0, ErrBidderNotFound or ErrItemNotFound if the user or item does not exist and ErrClosed if bidding on the
+// item has closed. The errors of storage.AuctionBidClient.PlaceBid, such as storage.ErrBidTooLow, are returned wrapped.
+func Place(
+	ctx context.Context,
+	transactor storage.Transactor,
+	eventBus *events.Bus,
+	currency string,
+	bid *Bid,
+) (*events.BidPlaced, error) {
+	if bid.Amount.Currency != currency {
+		return nil, errors.Wrapf(ErrWrongCurrency, "'%s' is not %s", bid.Amount.Currency, currency)
+	}
+	if bid.Amount.Amount <= 0 {
+		return nil, errors.Wrapf(ErrInvalidAmount, "'%s' is not a valid bid", bid.Amount)
+	}
+
+	// The winning bids are read in the same transaction as the new bid is placed so the right bidder is told they were
+	// outbid.
+	event := &events.BidPlaced{
+		Username: bid.Username,
+		Amount:   bid.Amount,
+	}
+	err := transactor.RunInTx(ctx, func(ctx context.Context, clients *storage.Clients) error {
+		user, err := clients.Users.Get(ctx, bid.Username)
+		if errors.Is(err, storage.ErrEntityNotFound) {
+			return errors.Wrapf(ErrBidderNotFound, "could not retrieve user '%s'", bid.Username)
+		}
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve user")
+		}
+
+		item, err := bid.FindItem(ctx, clients.Items)
+		if errors.Is(err, storage.ErrEntityNotFound) {
+			return errors.Wrap(ErrItemNotFound, "could not retrieve item")
+		}
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve item")
+		}
+		if item.IsClosed(time.Now()) {
+			return errors.Wrapf(ErrClosed, "item closed at %s", item.ClosesAt)
+		}
+		event.ItemID = item.ID
+		event.ItemName = item.Name
+		if item.LotID != 0 {
+			lot, err := clients.Lots.GetByID(ctx, item.LotID)
+			if err != nil {
+				return errors.Wrap(err, "could not retrieve lot of item")
+			}
+			event.LotID = lot.ID
+			event.LotName = lot.Name
+		}
+
+		winningBids, err := clients.Bids.GetWinningBids(ctx, item)
+		if err != nil && !errors.Is(err, storage.ErrEntityNotFound) {
+			return errors.Wrap(err, "could not retrieve winning bids")
+		}
+		event.PreviousBidder = outbidBidder(winningBids, user.Username)
+
+		if _, err = clients.Bids.PlaceBid(ctx, user, item, bid.Amount); err != nil {
+			return errors.Wrap(err, "could not place bid")
+		}
+		if event.PreviousBidder == "" {
+			return nil
+		}
+
+		// The outbid bidder only has to beat whichever bid is now in the last winning place.
+		winningBids, err = clients.Bids.GetWinningBids(ctx, item)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve winning bids")
+		}
+		event.MinimumBid = minimumWinningBid(winningBids)
+
+		// The notice is stored along with the bid so whichever instance the outbid bidder is connected to, now or when
+		// they next connect, can tell them.
+		notice := &model.OutbidNotice{
+			Username:   event.PreviousBidder,
+			ItemID:     event.ItemID,
+			ItemName:   event.ItemName,
+			LotID:      event.LotID,
+			LotName:    event.LotName,
+			CurrentBid: event.Amount,
+			MinimumBid: event.MinimumBid,
+		}
+		if err = clients.Outbids.Add(ctx, notice); err != nil {
+			return errors.Wrap(err, "could not store outbid notice")
+		}
+		event.NoticeID = notice.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventBus.Publish(events.TypeBidPlaced, event)
+	return event, nil
+}
+
+// outbidBidder finds who loses their winning place when the user outbids the winning bids of an item. Nobody loses
+// their place while places are still open or when the user was already winning.
+func outbidBidder(winningBids []*model.AuctionBid, username string) string {
+	if len(winningBids) == 0 || len(winningBids) < winningBids[0].Item.Quantity {
+		return ""
+	}
+	for _, bid := range winningBids {
+		if bid.Bidder.Username == username {
+			return ""
+		}
+	}
+	return winningBids[len(winningBids)-1].Bidder.Username
+}
+
+// minimumWinningBid is the least a bidder who is not winning has to bid to take a place from the winning bids of an item
+// whose places are all taken, which is one minor unit more than the lowest of them.
+func minimumWinningBid(winningBids []*model.AuctionBid) model.Money {
+	lowest := winningBids[len(winningBids)-1].BidAmount
+	return model.Money{Amount: lowest.Amount + 1, Currency: lowest.Currency}
+}
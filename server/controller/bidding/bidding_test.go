@@ -0,0 +1,136 @@
+package bidding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/memory"
+	"github.com/stretchr/testify/suite"
+)
+
+type placeTestSuite struct {
+	suite.Suite
+
+	ctx        context.Context
+	db         *memory.Database
+	transactor storage.Transactor
+	eventBus   *events.Bus
+	item       *model.AuctionItem
+}
+
+func (ts *placeTestSuite) SetupTest() {
+	ts.ctx = context.Background()
+	ts.db = memory.NewDatabase()
+	ts.transactor = memory.NewTransactor(ts.db)
+	ts.eventBus = events.NewBus(events.DefaultHistorySize)
+
+	for _, username := range []string{"first", "second"} {
+		user := &model.User{Username: username, Permission: model.PermissionLevelBidder}
+		ts.Require().NoError(memory.NewUserClient(ts.db).Create(ts.ctx, user))
+	}
+	ts.item = &model.AuctionItem{Name: "item"}
+	ts.Require().NoError(memory.NewAuctionItemClient(ts.db).Create(ts.ctx, ts.item))
+}
+
+func TestPlace(t *testing.T) {
+	suite.Run(t, new(placeTestSuite))
+}
+
+func (ts *placeTestSuite) TestPlacePublishesBidWithOutbidBidder() {
+	_, err := ts.place("first", ts.item.Name, usd(1000))
+	ts.Require().NoError(err)
+
+	event, err := ts.place("second", ts.item.Name, usd(1250))
+	ts.Require().NoError(err)
+	ts.Require().NotZero(event.NoticeID)
+	ts.Require().EqualValues(&events.BidPlaced{
+		ItemID:         ts.item.ID,
+		ItemName:       ts.item.Name,
+		Username:       "second",
+		Amount:         usd(1250),
+		PreviousBidder: "first",
+		MinimumBid:     usd(1251),
+		NoticeID:       event.NoticeID,
+	}, event)
+
+	published, subscription := ts.eventBus.SubscribeAfter(0)
+	defer subscription.Close()
+	ts.Require().Len(published, 2)
+	ts.Require().EqualValues(event, published[1].Payload)
+}
+
+func (ts *placeTestSuite) TestPlaceStoresOutbidNoticeForOutbidBidder() {
+	_, err := ts.place("first", ts.item.Name, usd(1000))
+	ts.Require().NoError(err)
+	event, err := ts.place("second", ts.item.Name, usd(1250))
+	ts.Require().NoError(err)
+
+	notices, err := memory.NewOutbidNoticeClient(ts.db).Take(ts.ctx, "first")
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.OutbidNotice{{
+		ID:         event.NoticeID,
+		Username:   "first",
+		ItemID:     ts.item.ID,
+		ItemName:   ts.item.Name,
+		CurrentBid: usd(1250),
+		MinimumBid: usd(1251),
+	}}, notices)
+}
+
+func (ts *placeTestSuite) TestPlaceDoesNotPublishFailedBids() {
+	euros := model.Money{Amount: 1000, Currency: "EUR"}
+	tests := []struct {
+		username string
+		itemName string
+		amount   model.Money
+		expected error
+	}{
+		{username: "first", itemName: ts.item.Name, amount: euros, expected: ErrWrongCurrency},
+		{username: "first", itemName: ts.item.Name, amount: usd(0), expected: ErrInvalidAmount},
+		{username: "missing", itemName: ts.item.Name, amount: usd(1000), expected: ErrBidderNotFound},
+		{username: "first", itemName: "missing", amount: usd(1000), expected: ErrItemNotFound},
+	}
+	for _, test := range tests {
+		_, err := ts.place(test.username, test.itemName, test.amount)
+		ts.Require().ErrorIs(err, test.expected, test.expected.Error())
+	}
+
+	published, subscription := ts.eventBus.SubscribeAfter(0)
+	defer subscription.Close()
+	ts.Require().Empty(published)
+}
+
+func (ts *placeTestSuite) TestPlaceReturnsErrClosedOnceItemHasClosed() {
+	ts.item.ClosesAt = time.Now().Add(-time.Minute)
+	ts.Require().NoError(memory.NewAuctionItemClient(ts.db).Update(ts.ctx, ts.item))
+
+	_, err := ts.place("first", ts.item.Name, usd(1000))
+	ts.Require().ErrorIs(err, ErrClosed)
+}
+
+func (ts *placeTestSuite) TestPlaceReturnsErrorsOfStorage() {
+	_, err := ts.place("first", ts.item.Name, usd(1000))
+	ts.Require().NoError(err)
+
+	_, err = ts.place("second", ts.item.Name, usd(1000))
+	ts.Require().ErrorIs(err, storage.ErrBidTooLow)
+}
+
+func (ts *placeTestSuite) place(username string, itemName string, amount model.Money) (*events.BidPlaced, error) {
+	return Place(ts.ctx, ts.transactor, ts.eventBus, "USD", &Bid{
+		Username: username,
+		FindItem: func(ctx context.Context, itemClient storage.AuctionItemClient) (*model.AuctionItem, error) {
+			return itemClient.Get(ctx, itemName)
+		},
+		Amount: amount,
+	})
+}
+
+// usd is an amount of cents.
+func usd(amount int64) model.Money {
+	return model.Money{Amount: amount, Currency: "USD"}
+}
@@ -0,0 +1,81 @@
+package bidding
+
+import (
+	"context"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+// DefaultClosingInterval is how often the closing times of the items are checked.
+const DefaultClosingInterval = time.Second
+
+// StartClosingAnnouncer checks every interval for items whose closing time has passed and publishes an
+// events.AuctionClosed for each of them until the context is done. Closings are claimed through the itemClient, so
+// every instance sharing the storage can run an announcer and each closing is still published once.
+func StartClosingAnnouncer(
+	ctx context.Context,
+	itemClient storage.AuctionItemClient,
+	lotClient storage.LotClient,
+	eventBus *events.Bus,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := AnnounceClosings(ctx, itemClient, lotClient, eventBus, now); err != nil {
+					log.Error(ctx, "unable to announce closed items", "err", err)
+				}
+			}
+		}
+	}()
+}
+
+// AnnounceClosings publishes an events.AuctionClosed for every item whose closing time passed by now and was not
+// announced yet, in the order the items closed.
+func AnnounceClosings(
+	ctx context.Context,
+	itemClient storage.AuctionItemClient,
+	lotClient storage.LotClient,
+	eventBus *events.Bus,
+	now time.Time,
+) error {
+	items, err := itemClient.ClaimClosed(ctx, now)
+	if err != nil {
+		return errors.Wrap(err, "unable to claim closed items")
+	}
+
+	lots := make(map[uint64]*model.Lot)
+	for _, item := range items {
+		event := &events.AuctionClosed{
+			ItemID:   item.ID,
+			ItemName: item.Name,
+			ClosedAt: item.ClosesAt,
+		}
+		if item.LotID != 0 {
+			lot, ok := lots[item.LotID]
+			if !ok {
+				// The closing is already claimed, so it is still announced without the name of the lot.
+				lot, err = lotClient.GetByID(ctx, item.LotID)
+				if err != nil {
+					log.Error(ctx, "unable to get lot of closed item", "item", item.Name, "err", err)
+					lot = &model.Lot{ID: item.LotID}
+				}
+				lots[item.LotID] = lot
+			}
+			event.LotID = lot.ID
+			event.LotName = lot.Name
+		}
+		eventBus.Publish(events.TypeAuctionClosed, event)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package bidding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/memory"
+	"github.com/stretchr/testify/suite"
+)
+
+type closingTestSuite struct {
+	suite.Suite
+
+	ctx        context.Context
+	itemClient storage.AuctionItemClient
+	lotClient  storage.LotClient
+	eventBus   *events.Bus
+	now        time.Time
+}
+
+func (ts *closingTestSuite) SetupTest() {
+	ts.ctx = context.Background()
+	db := memory.NewDatabase()
+	ts.itemClient = memory.NewAuctionItemClient(db)
+	ts.lotClient = memory.NewLotClient(db)
+	ts.eventBus = events.NewBus(events.DefaultHistorySize)
+	ts.now = time.Date(2030, time.June, 1, 20, 0, 0, 0, time.UTC)
+}
+
+func TestClosing(t *testing.T) {
+	suite.Run(t, new(closingTestSuite))
+}
+
+func (ts *closingTestSuite) TestAnnounceClosingsPublishesEachClosedItemOnce() {
+	items := []*model.AuctionItem{
+		{Name: "cello", ClosesAt: ts.now.Add(-time.Minute)},
+		{Name: "drum"},
+		{Name: "flute", ClosesAt: ts.now.Add(-time.Hour)},
+		{Name: "harp", ClosesAt: ts.now.Add(time.Hour)},
+	}
+	for _, item := range items {
+		ts.Require().NoError(ts.itemClient.Create(ts.ctx, item))
+	}
+
+	ts.Require().NoError(AnnounceClosings(ts.ctx, ts.itemClient, ts.lotClient, ts.eventBus, ts.now))
+	ts.Require().NoError(AnnounceClosings(ts.ctx, ts.itemClient, ts.lotClient, ts.eventBus, ts.now))
+
+	published, subscription := ts.eventBus.SubscribeAfter(0)
+	defer subscription.Close()
+	ts.Require().Len(published, 2)
+	ts.Require().Equal(events.TypeAuctionClosed, published[0].Type)
+	ts.Require().EqualValues(&events.AuctionClosed{
+		ItemID:   items[2].ID,
+		ItemName: "flute",
+		ClosedAt: items[2].ClosesAt,
+	}, published[0].Payload)
+	ts.Require().EqualValues(&events.AuctionClosed{
+		ItemID:   items[0].ID,
+		ItemName: "cello",
+		ClosedAt: items[0].ClosesAt,
+	}, published[1].Payload)
+}
+
+func (ts *closingTestSuite) TestAnnounceClosingsNamesLotOfItem() {
+	items := []*model.AuctionItem{
+		{Name: "cello", ClosesAt: ts.now.Add(-time.Minute)},
+		{Name: "bow", ClosesAt: ts.now.Add(-time.Minute)},
+	}
+	for _, item := range items {
+		ts.Require().NoError(ts.itemClient.Create(ts.ctx, item))
+	}
+	lot := &model.Lot{Name: "strings"}
+	ts.Require().NoError(ts.lotClient.Create(ts.ctx, lot, []uint64{items[0].ID, items[1].ID}))
+
+	ts.Require().NoError(AnnounceClosings(ts.ctx, ts.itemClient, ts.lotClient, ts.eventBus, ts.now))
+
+	published, subscription := ts.eventBus.SubscribeAfter(0)
+	defer subscription.Close()
+	ts.Require().Len(published, 2)
+	for _, event := range published {
+		closed := event.Payload.(*events.AuctionClosed)
+		ts.Require().Equal(lot.ID, closed.LotID)
+		ts.Require().Equal("strings", closed.LotName)
+	}
+}
+
+func (ts *closingTestSuite) TestStartClosingAnnouncerPublishesOnceClosingTimePasses() {
+	ctx, cancel := context.WithCancel(ts.ctx)
+	defer cancel()
+	item := &model.AuctionItem{Name: "cello", ClosesAt: time.Now().Add(50 * time.Millisecond)}
+	ts.Require().NoError(ts.itemClient.Create(ts.ctx, item))
+	subscription := ts.eventBus.Subscribe()
+	defer subscription.Close()
+
+	StartClosingAnnouncer(ctx, ts.itemClient, ts.lotClient, ts.eventBus, 10*time.Millisecond)
+
+	select {
+	case event := <-subscription.Events():
+		ts.Require().Equal(events.TypeAuctionClosed, event.Type)
+		ts.Require().Equal(item.ID, event.Payload.(*events.AuctionClosed).ItemID)
+	case <-time.After(time.Second):
+		ts.Fail("closing was not announced")
+	}
+}
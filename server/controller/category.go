@@ -0,0 +1,321 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+type (
+	// swagger:model
+	categoryResponse struct {
+		// The ID of the category.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The name of the category. It is used to put items in the category.
+		//
+		// Required: true
+		Name string `json:"name"`
+	}
+
+	postCategoryRequest struct {
+		// Name of the category. It must be unique, ignoring case.
+		//
+		// Required: true
+		Name string `json:"name"`
+	}
+)
+
+// CategoryHandler provides handlers for endpoints involving model.Categories.
+type CategoryHandler struct {
+	categoryClient storage.CategoryClient
+	transactor     storage.Transactor
+	eventBus       *events.Bus
+}
+
+// NewCategoryHandler creates a new CategoryHandler with the necessary storage objects. The items taken out of a deleted
+// category are published on the eventBus.
+func NewCategoryHandler(
+	categoryClient storage.CategoryClient,
+	transactor storage.Transactor,
+	eventBus *events.Bus,
+) *CategoryHandler {
+	return &CategoryHandler{
+		categoryClient: categoryClient,
+		transactor:     transactor,
+		eventBus:       eventBus,
+	}
+}
+
+// RegisterRoutes registers all of the paths to the handler functions.
+func (handler *CategoryHandler) RegisterRoutes(router *mux.Router) {
+	categoriesRouter := router.PathPrefix("/v1/auctions/categories").Subrouter()
+	categoriesRouter.Use(middleware.VerifyAuthToken)
+
+	categoriesAdmin := categoriesRouter.NewRoute().Subrouter()
+	categoriesAdmin.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin))
+	categoriesAdmin.HandleFunc("", wrapHandler(handler.PostCategory)).Methods(http.MethodPost)
+	categoriesAdmin.HandleFunc("/{category}", wrapHandler(handler.DeleteCategory)).Methods(http.MethodDelete)
+
+	categoriesBoth := categoriesRouter.NewRoute().Subrouter()
+	categoriesBoth.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin, model.PermissionLevelBidder))
+	categoriesBoth.HandleFunc("", wrapHandler(handler.GetCategories)).Methods(http.MethodGet)
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getCategoriesRequestDoc is for swagger generation only.
+// swagger:parameters getCategoriesRequest
+type getCategoriesRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// Contains every category ordered by name.
+//
+// swagger:response getCategoriesResponse
+type getCategoriesResponseDoc struct {
+
+	// In: body
+	Body []categoryResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetCategories is the handler that retrieves all model.Categories as serialized JSON.
+//
+// swagger:route GET /api/v1/auctions/categories Categories getCategoriesRequest
+//
+// Gets all categories.
+//
+// This will retrieve every category ordered by name. Use the category query parameter of the items to get the items in
+// a category.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getCategoriesResponse
+func (handler *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) error {
+	categories, err := handler.categoryClient.GetAll(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve categories")
+	}
+
+	responseObjects := make([]*categoryResponse, len(categories))
+	for i, category := range categories {
+		responseObjects[i] = &categoryResponse{
+			ID:   category.ID,
+			Name: category.Name,
+		}
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal categories")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// postCategoryRequestDoc is for swagger generation only.
+// swagger:parameters postCategoryRequest
+type postCategoryRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// In: body
+	Body postCategoryRequest
+}
+
+// Contains the category that was created.
+//
+// swagger:response postCategoryResponse
+type postCategoryResponseDoc struct {
+
+	// In: body
+	Body categoryResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PostCategory is the handler that creates a new model.Category.
+//
+// swagger:route POST /api/v1/auctions/categories Categories postCategoryRequest
+//
+// Creates a new category.
+//
+// This will create a new category that items can be put in. This route is only available to Admin users.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    201: postCategoryResponse
+//    400: errorMessage
+func (handler *CategoryHandler) PostCategory(w http.ResponseWriter, r *http.Request) error {
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return errors.Wrap(err, "could not read body")
+	}
+	defer r.Body.Close()
+
+	var request postCategoryRequest
+	err = json.Unmarshal(rawBody, &request)
+	if err != nil {
+		log.Info(r.Context(), "invalid json request", "body", string(rawBody), "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return nil
+	}
+
+	category := &model.Category{
+		Name: strings.TrimSpace(request.Name),
+	}
+	if category.Name == "" {
+		return errors.Wrap(writeCategoryError(w, errCategoryNameRequired), "could not create category")
+	}
+
+	if err = handler.categoryClient.Create(r.Context(), category); err != nil {
+		return errors.Wrap(writeCategoryError(w, err), "could not store category")
+	}
+
+	rawCategory, err := json.Marshal(&categoryResponse{
+		ID:   category.ID,
+		Name: category.Name,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal category")
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprint(w, string(rawCategory))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// deleteCategoryRequestDoc is for swagger generation only.
+// swagger:parameters deleteCategoryRequest
+type deleteCategoryRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// Name of the category.
+	//
+	// In: path
+	Category string `json:"category"`
+}
+
+// ----- End Documentation Generation Types --------------
+
+// DeleteCategory is the handler that removes a model.Category from storage.
+//
+// swagger:route DELETE /api/v1/auctions/categories/{category} Categories deleteCategoryRequest
+//
+// Deletes a category.
+//
+// This will delete the category. The items in the category are kept without a category. This route is only available
+// to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: noBody
+//    404: errorMessage
+func (handler *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) error {
+	name := mux.Vars(r)["category"]
+	r = r.WithContext(log.WithFields(r.Context(), "category", name))
+
+	// The items are read in the same transaction so every item taken out of the category is published.
+	var items []*model.AuctionItem
+	err := handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		var err error
+		items, err = clients.Items.GetMatching(ctx, storage.ItemFilter{Category: name})
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve items in category")
+		}
+		return clients.Categories.Delete(ctx, name)
+	})
+	if err != nil {
+		return errors.Wrap(writeCategoryError(w, err), "could not delete category")
+	}
+
+	for _, item := range items {
+		handler.eventBus.Publish(events.TypeItemUpdated, &events.ItemUpdated{
+			ItemID:   item.ID,
+			ItemName: item.Name,
+			Cleared:  []string{"category"},
+		})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+var errCategoryNameRequired = errors.New("name of the category is required")
+
+// writeCategoryError responds to a request that could not change a category because of the client. Any other error is
+// returned so it is handled as a server error.
+func writeCategoryError(w http.ResponseWriter, err error) error {
+	var message string
+	var status int
+	switch {
+	case errors.Is(err, storage.ErrEntityNotFound):
+		status, message = http.StatusNotFound, "category does not exist"
+	case errors.Is(err, storage.ErrEntityAlreadyExists):
+		status, message = http.StatusBadRequest, "category already exists"
+	case errors.Is(err, errCategoryNameRequired):
+		status, message = http.StatusBadRequest, errCategoryNameRequired.Error()
+	default:
+		return err
+	}
+
+	w.WriteHeader(status)
+	response, err := json.Marshal(newErrorResponse(message))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal error response")
+	}
+	fmt.Fprint(w, string(response))
+	return nil
+}
@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type categoryHandlerTestSuite struct {
+	suite.Suite
+
+	client          *http.Client
+	server          *httptest.Server
+	categoryMock    *mocks.CategoryClient
+	auctionItemMock *mocks.AuctionItemClient
+	transactorMock  *mocks.Transactor
+	eventBus        *events.Bus
+	handler         *CategoryHandler
+}
+
+func (ts *categoryHandlerTestSuite) SetupSuite() {
+	ts.handler = NewCategoryHandler(ts.categoryMock, ts.transactorMock, ts.eventBus)
+	var router *mux.Router
+	ts.server, router = newTestServer()
+	ts.handler.RegisterRoutes(router)
+	ts.client = &http.Client{}
+}
+
+func (ts *categoryHandlerTestSuite) SetupTest() {
+	ts.categoryMock = new(mocks.CategoryClient)
+	ts.auctionItemMock = new(mocks.AuctionItemClient)
+	ts.transactorMock = new(mocks.Transactor)
+	ts.transactorMock.On("RunInTx", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(
+		func(ctx context.Context, fn func(context.Context, *storage.Clients) error) error {
+			return fn(ctx, &storage.Clients{
+				Items:      ts.auctionItemMock,
+				Categories: ts.categoryMock,
+			})
+		},
+	).Maybe()
+	ts.eventBus = events.NewBus(events.DefaultHistorySize)
+	ts.handler.categoryClient = ts.categoryMock
+	ts.handler.transactor = ts.transactorMock
+	ts.handler.eventBus = ts.eventBus
+}
+
+func (ts *categoryHandlerTestSuite) TearDownTest() {
+	ts.categoryMock.AssertExpectations(ts.T())
+	ts.auctionItemMock.AssertExpectations(ts.T())
+}
+
+func (ts *categoryHandlerTestSuite) TearDownSuite() {
+	ts.server.Close()
+}
+
+func TestCategoryHandler(t *testing.T) {
+	suite.Run(t, new(categoryHandlerTestSuite))
+}
+
+func (ts *categoryHandlerTestSuite) TestGetCategoriesRetrievesAllCategories() {
+	getCategoriesTest := func(permission model.PermissionLevel) {
+		categories := []*model.Category{{ID: 1, Name: "Sports"}, {ID: 2, Name: "Travel"}}
+		ts.categoryMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return(categories, nil).Once()
+
+		r := ts.makeAuthenticatedRequest(http.MethodGet, "", nil, &model.User{
+			Permission: permission,
+		})
+		response, err := ts.client.Do(r)
+		ts.Require().NoError(err)
+
+		defer response.Body.Close()
+		ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+		var returnedCategories []categoryResponse
+		ts.Require().NoError(json.NewDecoder(response.Body).Decode(&returnedCategories))
+		ts.Require().Equal([]categoryResponse{{ID: 1, Name: "Sports"}, {ID: 2, Name: "Travel"}}, returnedCategories)
+	}
+
+	getCategoriesTest(model.PermissionLevelAdmin)
+	getCategoriesTest(model.PermissionLevelBidder)
+}
+
+func (ts *categoryHandlerTestSuite) TestPostCategoryStoresNewCategory() {
+	ts.categoryMock.On("Create", mock.AnythingOfType("*context.valueCtx"), &model.Category{Name: "Sports"}).Return(
+		func(ctx context.Context, category *model.Category) error {
+			category.ID = 5
+			return nil
+		},
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "", strings.NewReader(`{"name":" Sports "}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	var createdCategory categoryResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&createdCategory))
+	ts.Require().Equal(categoryResponse{ID: 5, Name: "Sports"}, createdCategory)
+}
+
+func (ts *categoryHandlerTestSuite) TestPostCategory400OnDuplicateName() {
+	ts.categoryMock.On("Create", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.Category")).Return(
+		errors.Wrap(storage.ErrEntityAlreadyExists, "duplicate"),
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "", strings.NewReader(`{"name":"sports"}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+	var errResponse errorResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&errResponse))
+	ts.Require().EqualValues("category already exists", errResponse.Message)
+}
+
+func (ts *categoryHandlerTestSuite) TestPostCategory400OnEmptyName() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "", strings.NewReader(`{"name":"  "}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *categoryHandlerTestSuite) TestPostCategory403OnBidderRequest() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "", strings.NewReader(`{"name":"Sports"}`), &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *categoryHandlerTestSuite) TestDeleteCategoryPublishesItemsTakenOutOfCategory() {
+	items := []*model.AuctionItem{{ID: 1, Name: "Golf Clubs", Category: "Sports"}}
+	ts.auctionItemMock.On("GetMatching", mock.AnythingOfType("*context.valueCtx"), storage.ItemFilter{
+		Category: "Sports",
+	}).Return(items, nil)
+	ts.categoryMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), "Sports").Return(nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, "/Sports", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	event := <-sub.Events()
+	ts.Require().Equal(&events.ItemUpdated{
+		ItemID:   1,
+		ItemName: "Golf Clubs",
+		Cleared:  []string{"category"},
+	}, event.Payload)
+}
+
+func (ts *categoryHandlerTestSuite) TestDeleteCategory404OnMissingCategory() {
+	ts.auctionItemMock.On("GetMatching", mock.AnythingOfType("*context.valueCtx"), storage.ItemFilter{
+		Category: "Sports",
+	}).Return([]*model.AuctionItem{}, nil)
+	ts.categoryMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), "Sports").Return(
+		errors.Wrap(storage.ErrEntityNotFound, "missing"),
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, "/Sports", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *categoryHandlerTestSuite) makeAuthenticatedRequest(method string, path string, body io.Reader, user *model.User) *http.Request {
+	return makeAuthenticatedRequest(ts.T(), method, ts.server.URL+"/api/v1/auctions/categories"+path, body, user)
+}
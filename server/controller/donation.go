@@ -0,0 +1,808 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/server/controller/auth"
+	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+type (
+	// swagger:model
+	appealLevelResponse struct {
+		// The ID of the level.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The name of the level, e.g. what the amount pays for.
+		//
+		// Required: true
+		Name string `json:"name"`
+
+		// The amount pledged at the level as a decimal string followed by the currency, e.g. "500.00 USD".
+		//
+		// Required: true
+		Amount model.Money `json:"amount"`
+	}
+
+	// swagger:model
+	pledgeResponse struct {
+		// The ID of the pledge.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The level the pledge was made at.
+		//
+		// Required: true
+		Level *appealLevelResponse `json:"level"`
+
+		// The bidder who owes the pledge.
+		//
+		// Required: true
+		Bidder *userResponse `json:"bidder"`
+
+		// The amount of the level when the pledge was recorded as a decimal string followed by the currency, e.g.
+		// "500.00 USD".
+		//
+		// Required: true
+		Amount model.Money `json:"amount"`
+
+		// The username of whoever recorded the pledge.
+		//
+		// Required: true
+		RecordedBy string `json:"recordedBy"`
+	}
+
+	// swagger:model
+	levelTotalsResponse struct {
+		appealLevelResponse
+
+		// How many pledges were made at the level.
+		//
+		// Required: true
+		Pledges int `json:"pledges"`
+
+		// The sum of the pledges made at the level as a decimal string followed by the currency, e.g. "1500.00 USD".
+		//
+		// Required: true
+		Total model.Money `json:"total"`
+	}
+
+	// swagger:model
+	donationTotalsResponse struct {
+		// How many pledges were made.
+		//
+		// Required: true
+		Pledges int `json:"pledges"`
+
+		// The sum of every pledge as a decimal string followed by the currency, e.g. "1500.00 USD".
+		//
+		// Required: true
+		Total model.Money `json:"total"`
+
+		// The totals of every level from the highest amount down.
+		//
+		// Required: true
+		Levels []*levelTotalsResponse `json:"levels"`
+	}
+
+	postAppealLevelRequest struct {
+		// Name of the level. It must be unique, ignoring case.
+		//
+		// Required: true
+		Name string `json:"name"`
+
+		// The amount pledged at the level as a decimal string followed by the currency of the auction, e.g.
+		// "500.00 USD". It must be more than 0.
+		//
+		// Required: true
+		Amount model.Money `json:"amount"`
+	}
+
+	postPledgeRequest struct {
+		// ID of the level to pledge at.
+		//
+		// Required: true
+		LevelID uint64 `json:"levelId"`
+
+		// Username of the bidder who pledges. Admins must give it since they record pledges on behalf of bidders.
+		// Bidders can only pledge for themselves and may leave it out.
+		Username string `json:"username,omitempty"`
+	}
+)
+
+// DonationHandler provides handlers for endpoints involving model.AppealLevels and model.Pledges.
+type DonationHandler struct {
+	donationClient storage.DonationClient
+	transactor     storage.Transactor
+	eventBus       *events.Bus
+	currency       string
+}
+
+// NewDonationHandler creates a new DonationHandler with the necessary storage objects. Every recorded and removed pledge
+// is published on the eventBus along with the new totals. Every appeal level must be in the currency of the event.
+func NewDonationHandler(
+	donationClient storage.DonationClient,
+	transactor storage.Transactor,
+	eventBus *events.Bus,
+	currency string,
+) *DonationHandler {
+	return &DonationHandler{
+		donationClient: donationClient,
+		transactor:     transactor,
+		eventBus:       eventBus,
+		currency:       currency,
+	}
+}
+
+// RegisterRoutes registers all of the paths to the handler functions.
+func (handler *DonationHandler) RegisterRoutes(router *mux.Router) {
+	donationsRouter := router.PathPrefix("/v1/donations").Subrouter()
+	donationsRouter.Use(middleware.VerifyAuthToken)
+
+	donationsAdmin := donationsRouter.NewRoute().Subrouter()
+	donationsAdmin.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin))
+	donationsAdmin.HandleFunc("/levels", wrapHandler(handler.PostAppealLevel)).Methods(http.MethodPost)
+	donationsAdmin.HandleFunc("/levels/{level}", wrapHandler(handler.DeleteAppealLevel)).Methods(http.MethodDelete)
+	donationsAdmin.HandleFunc("/pledges", wrapHandler(handler.GetPledges)).Methods(http.MethodGet)
+	donationsAdmin.HandleFunc("/pledges/{pledge}", wrapHandler(handler.DeletePledge)).Methods(http.MethodDelete)
+
+	donationsBoth := donationsRouter.NewRoute().Subrouter()
+	donationsBoth.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin, model.PermissionLevelBidder))
+	donationsBoth.HandleFunc("/levels", wrapHandler(handler.GetAppealLevels)).Methods(http.MethodGet)
+	donationsBoth.HandleFunc("/pledges", wrapHandler(handler.PostPledge)).Methods(http.MethodPost)
+	donationsBoth.HandleFunc("/totals", wrapHandler(handler.GetDonationTotals)).Methods(http.MethodGet)
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getAppealLevelsRequestDoc is for swagger generation only.
+// swagger:parameters getAppealLevelsRequest
+type getAppealLevelsRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// Contains every appeal level from the highest amount down.
+//
+// swagger:response getAppealLevelsResponse
+type getAppealLevelsResponseDoc struct {
+
+	// In: body
+	Body []appealLevelResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetAppealLevels is the handler that retrieves all model.AppealLevels as serialized JSON.
+//
+// swagger:route GET /api/v1/donations/levels Donations getAppealLevelsRequest
+//
+// Gets all appeal levels.
+//
+// This will retrieve every level guests can pledge at from the highest amount down.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getAppealLevelsResponse
+func (handler *DonationHandler) GetAppealLevels(w http.ResponseWriter, r *http.Request) error {
+	levels, err := handler.donationClient.GetLevels(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve appeal levels")
+	}
+
+	responseObjects := make([]*appealLevelResponse, len(levels))
+	for i, level := range levels {
+		responseObjects[i] = newAppealLevelResponse(level)
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal appeal levels")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// postAppealLevelRequestDoc is for swagger generation only.
+// swagger:parameters postAppealLevelRequest
+type postAppealLevelRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// In: body
+	Body postAppealLevelRequest
+}
+
+// Contains the appeal level that was created.
+//
+// swagger:response postAppealLevelResponse
+type postAppealLevelResponseDoc struct {
+
+	// In: body
+	Body appealLevelResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PostAppealLevel is the handler that creates a new model.AppealLevel.
+//
+// swagger:route POST /api/v1/donations/levels Donations postAppealLevelRequest
+//
+// Creates a new appeal level.
+//
+// This will create a fixed amount guests can pledge during the appeal. This route is only available to Admin users.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    201: postAppealLevelResponse
+//    400: errorMessage
+func (handler *DonationHandler) PostAppealLevel(w http.ResponseWriter, r *http.Request) error {
+	var request postAppealLevelRequest
+	if ok, err := readDonationRequest(w, r, &request); !ok {
+		return err
+	}
+
+	level := &model.AppealLevel{
+		Name:   strings.TrimSpace(request.Name),
+		Amount: request.Amount,
+	}
+	if level.Name == "" {
+		return errors.Wrap(writeDonationError(w, errLevelNameRequired), "could not create appeal level")
+	}
+	if level.Amount.Amount <= 0 {
+		return errors.Wrap(writeDonationError(w, errLevelAmountInvalid), "could not create appeal level")
+	}
+	if level.Amount.Currency != handler.currency {
+		message := fmt.Sprintf("%s, which is %s", errLevelCurrency.Error(), handler.currency)
+		return errors.Wrap(writeErrorMessage(w, http.StatusBadRequest, message), "could not create appeal level")
+	}
+
+	if err := handler.donationClient.CreateLevel(r.Context(), level); err != nil {
+		return errors.Wrap(writeDonationError(w, err), "could not store appeal level")
+	}
+
+	rawLevel, err := json.Marshal(newAppealLevelResponse(level))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal appeal level")
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprint(w, string(rawLevel))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// deleteAppealLevelRequestDoc is for swagger generation only.
+// swagger:parameters deleteAppealLevelRequest
+type deleteAppealLevelRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the level.
+	//
+	// In: path
+	Level string `json:"level"`
+}
+
+// ----- End Documentation Generation Types --------------
+
+// DeleteAppealLevel is the handler that removes a model.AppealLevel from storage.
+//
+// swagger:route DELETE /api/v1/donations/levels/{level} Donations deleteAppealLevelRequest
+//
+// Deletes an appeal level.
+//
+// This will delete the level. Levels that already have pledges cannot be deleted, so remove the pledges first. This
+// route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: noBody
+//    404: errorMessage
+//    409: errorMessage
+func (handler *DonationHandler) DeleteAppealLevel(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseUint(mux.Vars(r)["level"], 10, 64)
+	if err != nil {
+		return errors.Wrap(writeDonationError(w, errors.Wrap(storage.ErrEntityNotFound, err.Error())),
+			"could not delete appeal level")
+	}
+
+	if err = handler.donationClient.DeleteLevel(r.Context(), id); err != nil {
+		return errors.Wrap(writeDonationError(w, err), "could not delete appeal level")
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getPledgesRequestDoc is for swagger generation only.
+// swagger:parameters getPledgesRequest
+type getPledgesRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// Contains every pledge in the order they were recorded.
+//
+// swagger:response getPledgesResponse
+type getPledgesResponseDoc struct {
+
+	// In: body
+	Body []pledgeResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetPledges is the handler that retrieves all model.Pledges as serialized JSON.
+//
+// swagger:route GET /api/v1/donations/pledges Donations getPledgesRequest
+//
+// Gets all pledges.
+//
+// This will retrieve every pledge in the order they were recorded, including the pledges of deleted users. This route
+// is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getPledgesResponse
+func (handler *DonationHandler) GetPledges(w http.ResponseWriter, r *http.Request) error {
+	pledges, err := handler.donationClient.GetPledges(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve pledges")
+	}
+
+	responseObjects := make([]*pledgeResponse, len(pledges))
+	for i, pledge := range pledges {
+		responseObjects[i] = newPledgeResponse(pledge)
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal pledges")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// postPledgeRequestDoc is for swagger generation only.
+// swagger:parameters postPledgeRequest
+type postPledgeRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// In: body
+	Body postPledgeRequest
+}
+
+// Contains the pledge that was recorded.
+//
+// swagger:response postPledgeResponse
+type postPledgeResponseDoc struct {
+
+	// In: body
+	Body pledgeResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PostPledge is the handler that records a new model.Pledge.
+//
+// swagger:route POST /api/v1/donations/pledges Donations postPledgeRequest
+//
+// Records a pledge.
+//
+// This will record a pledge of the amount of the level. Bidders pledge for themselves while admins record the pledges
+// of the bidders who raise their paddle. The pledge is owed along with the items the bidder wins and the new totals are
+// sent to every client.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    201: postPledgeResponse
+//    400: errorMessage
+//    403: errorMessage
+//    404: errorMessage
+func (handler *DonationHandler) PostPledge(w http.ResponseWriter, r *http.Request) error {
+	var request postPledgeRequest
+	if ok, err := readDonationRequest(w, r, &request); !ok {
+		return err
+	}
+
+	recordedBy := auth.ExtractUsername(r.Context())
+	username := strings.TrimSpace(request.Username)
+	if auth.ExtractPermission(r.Context()) == model.PermissionLevelAdmin {
+		if username == "" {
+			return errors.Wrap(writeDonationError(w, errPledgeUsernameRequired), "could not record pledge")
+		}
+	} else if username == "" {
+		username = recordedBy
+	} else if username != recordedBy {
+		return errors.Wrap(writeDonationError(w, errPledgeForOtherBidder), "could not record pledge")
+	}
+
+	pledge := &model.Pledge{
+		Level:      &model.AppealLevel{ID: request.LevelID},
+		Bidder:     &model.User{Username: username},
+		RecordedBy: recordedBy,
+	}
+	var totals events.DonationTotals
+	err := handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		if err := clients.Donations.CreatePledge(ctx, pledge); err != nil {
+			return err
+		}
+
+		var err error
+		totals, err = handler.donationTotals(ctx, clients.Donations, pledge.Level.ID)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(writeDonationError(w, err), "could not store pledge")
+	}
+
+	handler.eventBus.Publish(events.TypePledgeRecorded, &events.PledgeRecorded{
+		PledgeID:       pledge.ID,
+		LevelID:        pledge.Level.ID,
+		LevelName:      pledge.Level.Name,
+		Username:       pledge.Bidder.Username,
+		Amount:         pledge.Amount,
+		DonationTotals: totals,
+	})
+
+	rawPledge, err := json.Marshal(newPledgeResponse(pledge))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal pledge")
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprint(w, string(rawPledge))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// deletePledgeRequestDoc is for swagger generation only.
+// swagger:parameters deletePledgeRequest
+type deletePledgeRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the pledge.
+	//
+	// In: path
+	Pledge string `json:"pledge"`
+}
+
+// ----- End Documentation Generation Types --------------
+
+// DeletePledge is the handler that removes a model.Pledge from storage.
+//
+// swagger:route DELETE /api/v1/donations/pledges/{pledge} Donations deletePledgeRequest
+//
+// Deletes a pledge.
+//
+// This will delete a pledge that was recorded by mistake so the bidder no longer owes it. The new totals are sent to
+// every client. This route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: noBody
+//    404: errorMessage
+func (handler *DonationHandler) DeletePledge(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseUint(mux.Vars(r)["pledge"], 10, 64)
+	if err != nil {
+		return errors.Wrap(writeDonationError(w, errors.Wrap(storage.ErrEntityNotFound, err.Error())),
+			"could not delete pledge")
+	}
+
+	var pledge *model.Pledge
+	var totals events.DonationTotals
+	err = handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		var err error
+		if pledge, err = clients.Donations.DeletePledge(ctx, id); err != nil {
+			return err
+		}
+
+		totals, err = handler.donationTotals(ctx, clients.Donations, pledge.Level.ID)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(writeDonationError(w, err), "could not delete pledge")
+	}
+
+	handler.eventBus.Publish(events.TypePledgeRemoved, &events.PledgeRemoved{
+		PledgeID:       pledge.ID,
+		LevelID:        pledge.Level.ID,
+		LevelName:      pledge.Level.Name,
+		Username:       pledge.Bidder.Username,
+		Amount:         pledge.Amount,
+		DonationTotals: totals,
+	})
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getDonationTotalsRequestDoc is for swagger generation only.
+// swagger:parameters getDonationTotalsRequest
+type getDonationTotalsRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// Contains the totals of the appeal.
+//
+// swagger:response getDonationTotalsResponse
+type getDonationTotalsResponseDoc struct {
+
+	// In: body
+	Body donationTotalsResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetDonationTotals is the handler that sums up the model.Pledges of every model.AppealLevel.
+//
+// swagger:route GET /api/v1/donations/totals Donations getDonationTotalsRequest
+//
+// Gets the totals of the appeal.
+//
+// This will retrieve how many pledges were made and how much they add up to, overall and for every level. Use it to
+// show the totals when the projector screen opens and keep them up to date with the PledgeRecorded and PledgeRemoved
+// websocket messages.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getDonationTotalsResponse
+func (handler *DonationHandler) GetDonationTotals(w http.ResponseWriter, r *http.Request) error {
+	var levels []*model.AppealLevel
+	var pledges []*model.Pledge
+	err := handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		var err error
+		if levels, err = clients.Donations.GetLevels(ctx); err != nil {
+			return errors.Wrap(err, "could not retrieve appeal levels")
+		}
+
+		pledges, err = clients.Donations.GetPledges(ctx)
+		return errors.Wrap(err, "could not retrieve pledges")
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve donations")
+	}
+
+	response := &donationTotalsResponse{
+		Total:  model.Money{Currency: handler.currency},
+		Levels: make([]*levelTotalsResponse, len(levels)),
+	}
+	byLevel := make(map[uint64]*levelTotalsResponse, len(levels))
+	for i, level := range levels {
+		response.Levels[i] = &levelTotalsResponse{
+			appealLevelResponse: *newAppealLevelResponse(level),
+			Total:               model.Money{Currency: handler.currency},
+		}
+		byLevel[level.ID] = response.Levels[i]
+	}
+	for _, pledge := range pledges {
+		response.Pledges++
+		totals := []*model.Money{&response.Total}
+		if levelTotals, ok := byLevel[pledge.Level.ID]; ok {
+			levelTotals.Pledges++
+			totals = append(totals, &levelTotals.Total)
+		}
+		if err = addTo(pledge.Amount, totals...); err != nil {
+			return errors.Wrapf(err, "could not add pledge %d", pledge.ID)
+		}
+	}
+
+	rawResponse, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal donation totals")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+var (
+	errLevelNameRequired      = errors.New("name of the appeal level is required")
+	errLevelAmountInvalid     = errors.New("amount of the appeal level must be more than 0")
+	errLevelAmountFormat      = errors.New("amount of the appeal level must be a string such as \"500.00 USD\"")
+	errLevelCurrency          = errors.New("amount of the appeal level is not in the currency of the auction")
+	errPledgeUsernameRequired = errors.New("username of the bidder who pledges is required")
+	errPledgeForOtherBidder   = errors.New("bidders can only pledge for themselves")
+)
+
+// newAppealLevelResponse converts the level to the response sent to clients.
+func newAppealLevelResponse(level *model.AppealLevel) *appealLevelResponse {
+	return &appealLevelResponse{
+		ID:     level.ID,
+		Name:   level.Name,
+		Amount: level.Amount,
+	}
+}
+
+// newPledgeResponse converts the pledge to the response sent to clients.
+func newPledgeResponse(pledge *model.Pledge) *pledgeResponse {
+	return &pledgeResponse{
+		ID:    pledge.ID,
+		Level: newAppealLevelResponse(pledge.Level),
+		Bidder: &userResponse{
+			Username:    pledge.Bidder.Username,
+			DisplayName: pledge.Bidder.DisplayName,
+		},
+		Amount:     pledge.Amount,
+		RecordedBy: pledge.RecordedBy,
+	}
+}
+
+// donationTotals sums up every pledge in storage overall and for the level with the ID. This will return
+// model.ErrCurrencyMismatch if a pledge is not in the currency of the event.
+func (handler *DonationHandler) donationTotals(
+	ctx context.Context,
+	donationClient storage.DonationClient,
+	levelID uint64,
+) (events.DonationTotals, error) {
+	pledges, err := donationClient.GetPledges(ctx)
+	if err != nil {
+		return events.DonationTotals{}, errors.Wrap(err, "could not retrieve pledges")
+	}
+
+	totals := events.DonationTotals{
+		LevelTotal: model.Money{Currency: handler.currency},
+		Total:      model.Money{Currency: handler.currency},
+	}
+	for _, pledge := range pledges {
+		totals.Pledges++
+		amountTotals := []*model.Money{&totals.Total}
+		if pledge.Level.ID == levelID {
+			totals.LevelPledges++
+			amountTotals = append(amountTotals, &totals.LevelTotal)
+		}
+		if err = addTo(pledge.Amount, amountTotals...); err != nil {
+			return events.DonationTotals{}, errors.Wrapf(err, "could not add pledge %d", pledge.ID)
+		}
+	}
+	return totals, nil
+}
+
+// readDonationRequest decodes the body into the request. A body that is not valid JSON is answered with a bad request,
+// in which case false is returned.
+func readDonationRequest(w http.ResponseWriter, r *http.Request, request interface{}) (bool, error) {
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false, errors.Wrap(err, "could not read body")
+	}
+	defer r.Body.Close()
+
+	if err = json.Unmarshal(rawBody, request); err != nil {
+		log.Info(r.Context(), "invalid json request", "body", string(rawBody), "err", err)
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrUnknownCurrency) {
+			return false, writeDonationError(w, errLevelAmountFormat)
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		return false, nil
+	}
+	return true, nil
+}
+
+// writeDonationError responds to a request that could not change an appeal level or pledge because of the client. Any
+// other error is returned so it is handled as a server error.
+func writeDonationError(w http.ResponseWriter, err error) error {
+	var message string
+	var status int
+	switch {
+	case errors.Is(err, storage.ErrEntityNotFound):
+		status, message = http.StatusNotFound, "appeal level, pledge or bidder does not exist"
+	case errors.Is(err, storage.ErrEntityAlreadyExists):
+		status, message = http.StatusBadRequest, "appeal level already exists"
+	case errors.Is(err, storage.ErrLevelHasPledges):
+		status, message = http.StatusConflict, "appeal level cannot be deleted once it has pledges"
+	case errors.Is(err, errLevelNameRequired):
+		status, message = http.StatusBadRequest, errLevelNameRequired.Error()
+	case errors.Is(err, errLevelAmountInvalid):
+		status, message = http.StatusBadRequest, errLevelAmountInvalid.Error()
+	case errors.Is(err, errLevelAmountFormat):
+		status, message = http.StatusBadRequest, errLevelAmountFormat.Error()
+	case errors.Is(err, errPledgeUsernameRequired):
+		status, message = http.StatusBadRequest, errPledgeUsernameRequired.Error()
+	case errors.Is(err, errPledgeForOtherBidder):
+		status, message = http.StatusForbidden, errPledgeForOtherBidder.Error()
+	default:
+		return err
+	}
+
+	return writeErrorMessage(w, status, message)
+}
@@ -0,0 +1,324 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type donationHandlerTestSuite struct {
+	suite.Suite
+
+	client         *http.Client
+	server         *httptest.Server
+	donationMock   *mocks.DonationClient
+	transactorMock *mocks.Transactor
+	eventBus       *events.Bus
+	handler        *DonationHandler
+}
+
+func (ts *donationHandlerTestSuite) SetupSuite() {
+	ts.handler = NewDonationHandler(ts.donationMock, ts.transactorMock, ts.eventBus, "USD")
+	var router *mux.Router
+	ts.server, router = newTestServer()
+	ts.handler.RegisterRoutes(router)
+	ts.client = &http.Client{}
+}
+
+func (ts *donationHandlerTestSuite) SetupTest() {
+	ts.donationMock = new(mocks.DonationClient)
+	ts.transactorMock = new(mocks.Transactor)
+	ts.transactorMock.On("RunInTx", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(
+		func(ctx context.Context, fn func(context.Context, *storage.Clients) error) error {
+			return fn(ctx, &storage.Clients{
+				Donations: ts.donationMock,
+			})
+		},
+	).Maybe()
+	ts.eventBus = events.NewBus(events.DefaultHistorySize)
+	ts.handler.donationClient = ts.donationMock
+	ts.handler.transactor = ts.transactorMock
+	ts.handler.eventBus = ts.eventBus
+}
+
+func (ts *donationHandlerTestSuite) TearDownTest() {
+	ts.donationMock.AssertExpectations(ts.T())
+}
+
+func (ts *donationHandlerTestSuite) TearDownSuite() {
+	ts.server.Close()
+}
+
+func TestDonationHandler(t *testing.T) {
+	suite.Run(t, new(donationHandlerTestSuite))
+}
+
+func (ts *donationHandlerTestSuite) TestPostAppealLevelStoresNewLevel() {
+	ts.donationMock.On(
+		"CreateLevel",
+		mock.AnythingOfType("*context.valueCtx"),
+		&model.AppealLevel{Name: "School Supplies", Amount: usd(10000)},
+	).Return(
+		func(ctx context.Context, level *model.AppealLevel) error {
+			level.ID = 4
+			return nil
+		},
+	)
+
+	body := strings.NewReader(`{"name":" School Supplies ","amount":"100.00 USD"}`)
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/levels", body, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	var level appealLevelResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&level))
+	ts.Require().Equal(appealLevelResponse{ID: 4, Name: "School Supplies", Amount: usd(10000)}, level)
+}
+
+func (ts *donationHandlerTestSuite) TestPostAppealLevel400OnInvalidAmount() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/levels", strings.NewReader(`{"name":"Books","amount":"0.00 USD"}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *donationHandlerTestSuite) TestPostAppealLevel400OnAmountInOtherCurrency() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/levels", strings.NewReader(`{"name":"Books","amount":"50.00 EUR"}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+	var errResponse errorResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&errResponse))
+	ts.Require().EqualValues(errLevelCurrency.Error()+", which is USD", errResponse.Message)
+}
+
+func (ts *donationHandlerTestSuite) TestPostAppealLevel403OnBidderRequest() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/levels", strings.NewReader(`{"name":"Books","amount":"50.00 USD"}`), &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *donationHandlerTestSuite) TestDeleteAppealLevel409WhenLevelHasPledges() {
+	ts.donationMock.On("DeleteLevel", mock.AnythingOfType("*context.valueCtx"), uint64(4)).Return(
+		errors.Wrap(storage.ErrLevelHasPledges, "pledged"),
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, "/levels/4", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusConflict, response.StatusCode)
+}
+
+func (ts *donationHandlerTestSuite) TestPostPledgeRecordsPledgeOfBidder() {
+	ts.expectPledge("bidder", "bidder")
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/pledges", strings.NewReader(`{"levelId":4}`), &model.User{
+		Username:   "bidder",
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	var pledge pledgeResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&pledge))
+	ts.Require().EqualValues(7, pledge.ID)
+	ts.Require().EqualValues("bidder", pledge.Bidder.Username)
+	ts.Require().EqualValues("bidder", pledge.RecordedBy)
+	ts.Require().Equal(usd(10000), pledge.Amount)
+
+	event := <-sub.Events()
+	ts.Require().Equal(&events.PledgeRecorded{
+		PledgeID:  7,
+		LevelID:   4,
+		LevelName: "School Supplies",
+		Username:  "bidder",
+		Amount:    usd(10000),
+		DonationTotals: events.DonationTotals{
+			LevelPledges: 1,
+			LevelTotal:   usd(10000),
+			Pledges:      2,
+			Total:        usd(60000),
+		},
+	}, event.Payload)
+}
+
+func (ts *donationHandlerTestSuite) TestPostPledgeRecordsPledgeOnBehalfOfBidder() {
+	ts.expectPledge("bidder", "admin")
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/pledges", strings.NewReader(`{"levelId":4,"username":"bidder"}`), &model.User{
+		Username:   "admin",
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+}
+
+func (ts *donationHandlerTestSuite) TestPostPledge400WhenAdminOmitsBidder() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/pledges", strings.NewReader(`{"levelId":4}`), &model.User{
+		Username:   "admin",
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *donationHandlerTestSuite) TestPostPledge403WhenBidderPledgesForOtherBidder() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/pledges", strings.NewReader(`{"levelId":4,"username":"other"}`), &model.User{
+		Username:   "bidder",
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *donationHandlerTestSuite) TestPostPledge404OnMissingLevel() {
+	ts.donationMock.On("CreatePledge", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(
+		errors.Wrap(storage.ErrEntityNotFound, "missing"),
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/pledges", strings.NewReader(`{"levelId":9}`), &model.User{
+		Username:   "bidder",
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *donationHandlerTestSuite) TestGetDonationTotalsSumsPledgesByLevel() {
+	levels := []*model.AppealLevel{
+		{ID: 1, Name: "Playground", Amount: usd(50000)},
+		{ID: 4, Name: "School Supplies", Amount: usd(10000)},
+	}
+	pledges := []*model.Pledge{
+		{ID: 1, Level: levels[1], Bidder: &model.User{Username: "a"}, Amount: usd(10000)},
+		{ID: 2, Level: levels[1], Bidder: &model.User{Username: "b"}, Amount: usd(10000)},
+		{ID: 3, Level: levels[0], Bidder: &model.User{Username: "a"}, Amount: usd(50000)},
+	}
+	ts.donationMock.On("GetLevels", mock.AnythingOfType("*context.valueCtx")).Return(levels, nil)
+	ts.donationMock.On("GetPledges", mock.AnythingOfType("*context.valueCtx")).Return(pledges, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "/totals", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var totals donationTotalsResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&totals))
+	ts.Require().Equal(donationTotalsResponse{
+		Pledges: 3,
+		Total:   usd(70000),
+		Levels: []*levelTotalsResponse{
+			{
+				appealLevelResponse: appealLevelResponse{ID: 1, Name: "Playground", Amount: usd(50000)},
+				Pledges:             1,
+				Total:               usd(50000),
+			},
+			{
+				appealLevelResponse: appealLevelResponse{ID: 4, Name: "School Supplies", Amount: usd(10000)},
+				Pledges:             2,
+				Total:               usd(20000),
+			},
+		},
+	}, totals)
+}
+
+func (ts *donationHandlerTestSuite) TestDeletePledgePublishesRemainingTotals() {
+	level := &model.AppealLevel{ID: 4, Name: "School Supplies", Amount: usd(10000)}
+	ts.donationMock.On("DeletePledge", mock.AnythingOfType("*context.valueCtx"), uint64(7)).Return(&model.Pledge{
+		ID:     7,
+		Level:  level,
+		Bidder: &model.User{Username: "bidder"},
+		Amount: usd(10000),
+	}, nil)
+	ts.donationMock.On("GetPledges", mock.AnythingOfType("*context.valueCtx")).Return([]*model.Pledge{
+		{ID: 1, Level: &model.AppealLevel{ID: 1}, Bidder: &model.User{Username: "other"}, Amount: usd(50000)},
+	}, nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, "/pledges/7", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	event := <-sub.Events()
+	ts.Require().Equal(&events.PledgeRemoved{
+		PledgeID:  7,
+		LevelID:   4,
+		LevelName: "School Supplies",
+		Username:  "bidder",
+		Amount:    usd(10000),
+		DonationTotals: events.DonationTotals{
+			LevelTotal: usd(0),
+			Pledges:    1,
+			Total:      usd(50000),
+		},
+	}, event.Payload)
+}
+
+// expectPledge expects the pledge of the bidder at the level with ID 4 to be stored as the pledge with ID 7. One other
+// pledge was made before it.
+func (ts *donationHandlerTestSuite) expectPledge(bidder string, recordedBy string) {
+	level := &model.AppealLevel{ID: 4, Name: "School Supplies", Amount: usd(10000)}
+	ts.donationMock.On("CreatePledge", mock.AnythingOfType("*context.valueCtx"), &model.Pledge{
+		Level:      &model.AppealLevel{ID: 4},
+		Bidder:     &model.User{Username: bidder},
+		RecordedBy: recordedBy,
+	}).Return(
+		func(ctx context.Context, pledge *model.Pledge) error {
+			pledge.ID = 7
+			pledge.Level = level
+			pledge.Amount = level.Amount
+			return nil
+		},
+	)
+	ts.donationMock.On("GetPledges", mock.AnythingOfType("*context.valueCtx")).Return([]*model.Pledge{
+		{ID: 1, Level: &model.AppealLevel{ID: 1}, Bidder: &model.User{Username: "other"}, Amount: usd(50000)},
+		{ID: 7, Level: level, Bidder: &model.User{Username: bidder}, Amount: usd(10000)},
+	}, nil)
+}
+
+func (ts *donationHandlerTestSuite) makeAuthenticatedRequest(method string, path string, body io.Reader, user *model.User) *http.Request {
+	return makeAuthenticatedRequest(ts.T(), method, ts.server.URL+"/api/v1/donations"+path, body, user)
+}
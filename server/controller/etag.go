@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+// errIfMatchRequired is returned when a request that changes an entity does not say which version it changes.
+var errIfMatchRequired = errors.New("If-Match header is required")
+
+// formatETag returns the strong ETag identifying the version of an entity.
+func formatETag(version uint64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// checkIfMatch compares the If-Match header of the request against the version of the entity. This will return
+// errIfMatchRequired if the header is missing and storage.ErrVersionMismatch if none of its ETags match the version.
+func checkIfMatch(r *http.Request, version uint64) error {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return errIfMatchRequired
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == formatETag(version) {
+			return nil
+		}
+	}
+	return errors.Wrapf(storage.ErrVersionMismatch, "'%s' does not match version %d", header, version)
+}
@@ -0,0 +1,716 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/blob"
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+type (
+	// swagger:model
+	imageResponse struct {
+		// The ID of the image. This is used to delete and reorder the image.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The position of the image among the images of the item, starting at 0. The first image is shown for the item.
+		//
+		// Required: true
+		Position int `json:"position"`
+
+		// Where to download the image as it was uploaded.
+		//
+		// Required: true
+		URL string `json:"url"`
+
+		// Where to download the thumbnail of the image.
+		//
+		// Required: true
+		ThumbnailURL string `json:"thumbnailUrl"`
+
+		// The media type of the image as it was uploaded.
+		//
+		// Required: true
+		ContentType string `json:"contentType"`
+	}
+
+	putImageOrderRequest struct {
+		// The IDs of every image of the item in their new order.
+		//
+		// Required: true
+		ImageIDs []uint64 `json:"imageIds"`
+	}
+)
+
+const (
+	// DefaultMaxImageSize is the largest image file that is accepted unless configured otherwise.
+	DefaultMaxImageSize = 10 << 20
+
+	// maxImagesPerUpload limits how many images a single request can add.
+	maxImagesPerUpload = 10
+
+	// imageFormField is the field of the multipart form that has the images.
+	imageFormField = "image"
+
+	// imagesPath is where the stored images are served from.
+	imagesPath = "/api/v1/images/"
+
+	imageCacheControl = "public, max-age=31536000, immutable"
+)
+
+var errInvalidUpload = errors.New("invalid image upload")
+
+// ImageHandler provides handlers for endpoints involving the model.ItemImages of items.
+type ImageHandler struct {
+	itemClient   storage.AuctionItemClient
+	imageClient  storage.ItemImageClient
+	transactor   storage.Transactor
+	blobStore    blob.Store
+	eventBus     *events.Bus
+	maxImageSize int64
+}
+
+// NewImageHandler creates a new ImageHandler. The images and their thumbnails are kept in the blobStore while their
+// order is kept with the imageClient. Images larger than maxImageSize bytes are rejected.
+func NewImageHandler(
+	itemClient storage.AuctionItemClient,
+	imageClient storage.ItemImageClient,
+	transactor storage.Transactor,
+	blobStore blob.Store,
+	eventBus *events.Bus,
+	maxImageSize int64,
+) *ImageHandler {
+	return &ImageHandler{
+		itemClient:   itemClient,
+		imageClient:  imageClient,
+		transactor:   transactor,
+		blobStore:    blobStore,
+		eventBus:     eventBus,
+		maxImageSize: maxImageSize,
+	}
+}
+
+// RegisterRoutes registers all of the paths to the handler functions.
+func (handler *ImageHandler) RegisterRoutes(router *mux.Router) {
+	// The images are referenced by the items so they are public like the web client.
+	router.HandleFunc("/v1/images/{key:.+}", wrapHandler(handler.GetImage)).Methods(http.MethodGet)
+
+	itemImagesRouter := router.PathPrefix("/v1/auctions/items/{item}/images").Subrouter()
+	itemImagesRouter.Use(middleware.VerifyAuthToken)
+
+	itemImagesAdmin := itemImagesRouter.NewRoute().Subrouter()
+	itemImagesAdmin.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin))
+	itemImagesAdmin.HandleFunc("", wrapHandler(handler.PostImages)).Methods(http.MethodPost)
+	itemImagesAdmin.HandleFunc("/order", wrapHandler(handler.PutImageOrder)).Methods(http.MethodPut)
+	itemImagesAdmin.HandleFunc("/{image}", wrapHandler(handler.DeleteImage)).Methods(http.MethodDelete)
+
+	itemImagesBoth := itemImagesRouter.NewRoute().Subrouter()
+	itemImagesBoth.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin, model.PermissionLevelBidder))
+	itemImagesBoth.HandleFunc("", wrapHandler(handler.GetImages)).Methods(http.MethodGet)
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getImagesRequestDoc is for swagger generation only.
+// swagger:parameters getImagesRequest
+type getImagesRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the item. The name of the item is also accepted so older clients keep working.
+	//
+	// In: path
+	Item string `json:"item"`
+}
+
+// Contains the images of the item in order.
+//
+// swagger:response getImagesResponse
+type getImagesResponseDoc struct {
+
+	// In: body
+	Body []imageResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetImages is the handler that retrieves the model.ItemImages of an item as serialized JSON.
+//
+// swagger:route GET /api/v1/auctions/items/{item}/images Images getImagesRequest
+//
+// Gets the images of an item.
+//
+// This will retrieve every image of the item in order of their position.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getImagesResponse
+//    404: errorMessage
+func (handler *ImageHandler) GetImages(w http.ResponseWriter, r *http.Request) error {
+	itemReference := mux.Vars(r)["item"]
+	r = r.WithContext(log.WithFields(r.Context(), "item", itemReference))
+
+	item, err := getItem(r.Context(), handler.itemClient, itemReference)
+	if err != nil {
+		return errors.Wrap(writeImageError(w, err), "could not retrieve item")
+	}
+
+	images, err := handler.imageClient.GetAll(r.Context(), item.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve images")
+	}
+
+	return errors.Wrap(writeImages(w, http.StatusOK, images), "could not write images")
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// postImagesRequestDoc is for swagger generation only.
+// swagger:parameters postImagesRequest
+type postImagesRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the item. The name of the item is also accepted so older clients keep working.
+	//
+	// In: path
+	Item string `json:"item"`
+
+	// The JPEG, PNG or WebP images to add. The field can be repeated to add several images at once.
+	//
+	// In: formData
+	// swagger:file
+	Image interface{} `json:"image"`
+}
+
+// Contains the images that were added.
+//
+// swagger:response postImagesResponse
+type postImagesResponseDoc struct {
+
+	// In: body
+	Body []imageResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PostImages is the handler that adds uploaded images to a model.AuctionItem.
+//
+// swagger:route POST /api/v1/auctions/items/{item}/images Images postImagesRequest
+//
+// Adds images to an item.
+//
+// This will add the uploaded images after the existing images of the item. A thumbnail is created for each image and
+// the image of the item is set to the thumbnail of its first image. This route is only available to Admin users.
+//
+//  Consumes:
+//  - multipart/form-data
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    201: postImagesResponse
+//    400: errorMessage
+//    404: errorMessage
+//    413: errorMessage
+//    415: errorMessage
+func (handler *ImageHandler) PostImages(w http.ResponseWriter, r *http.Request) error {
+	itemReference := mux.Vars(r)["item"]
+	r = r.WithContext(log.WithFields(r.Context(), "item", itemReference))
+
+	item, err := getItem(r.Context(), handler.itemClient, itemReference)
+	if err != nil {
+		return errors.Wrap(writeImageError(w, err), "could not retrieve item")
+	}
+
+	uploads, err := handler.readUploads(w, r)
+	if err != nil {
+		log.Info(r.Context(), "invalid image upload", "err", err)
+		return errors.Wrap(writeImageError(w, err), "could not read images")
+	}
+
+	images := make([]*model.ItemImage, len(uploads))
+	var storedKeys []string
+	for i, upload := range uploads {
+		name, err := newImageName()
+		if err != nil {
+			return errors.Wrap(err, "could not name image")
+		}
+
+		images[i] = &model.ItemImage{
+			ItemID:       item.ID,
+			Key:          fmt.Sprintf("items/%d/%s.%s", item.ID, name, upload.extension),
+			ThumbnailKey: fmt.Sprintf("items/%d/%s-thumbnail.%s", item.ID, name, thumbnailExtension),
+			ContentType:  upload.contentType,
+		}
+
+		err = handler.blobStore.Put(r.Context(), images[i].Key, upload.contentType, upload.data)
+		if err != nil {
+			handler.deleteBlobs(r.Context(), storedKeys...)
+			return errors.Wrap(err, "could not store image")
+		}
+		storedKeys = append(storedKeys, images[i].Key)
+
+		err = handler.blobStore.Put(r.Context(), images[i].ThumbnailKey, thumbnailMediaType, upload.thumbnail)
+		if err != nil {
+			handler.deleteBlobs(r.Context(), storedKeys...)
+			return errors.Wrap(err, "could not store thumbnail")
+		}
+		storedKeys = append(storedKeys, images[i].ThumbnailKey)
+	}
+
+	var event *events.ItemUpdated
+	err = handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		for _, image := range images {
+			if err := clients.Images.Add(ctx, image); err != nil {
+				return errors.Wrap(err, "could not add image")
+			}
+		}
+
+		var err error
+		event, err = updateItemImageRef(ctx, clients, item.ID)
+		return err
+	})
+	if err != nil {
+		handler.deleteBlobs(r.Context(), storedKeys...)
+		return errors.Wrap(writeImageError(w, err), "could not add images")
+	}
+
+	if event != nil {
+		handler.eventBus.Publish(events.TypeItemUpdated, event)
+	}
+
+	return errors.Wrap(writeImages(w, http.StatusCreated, images), "could not write images")
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// putImageOrderRequestDoc is for swagger generation only.
+// swagger:parameters putImageOrderRequest
+type putImageOrderRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the item. The name of the item is also accepted so older clients keep working.
+	//
+	// In: path
+	Item string `json:"item"`
+
+	// In: body
+	Body putImageOrderRequest
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PutImageOrder is the handler that changes the order of the model.ItemImages of an item.
+//
+// swagger:route PUT /api/v1/auctions/items/{item}/images/order Images putImageOrderRequest
+//
+// Reorders the images of an item.
+//
+// This will move the images of the item to the order of the IDs, which must include every image of the item once. The
+// image of the item is set to the thumbnail of the new first image. This route is only available to Admin users.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getImagesResponse
+//    400: errorMessage
+//    404: errorMessage
+func (handler *ImageHandler) PutImageOrder(w http.ResponseWriter, r *http.Request) error {
+	itemReference := mux.Vars(r)["item"]
+	r = r.WithContext(log.WithFields(r.Context(), "item", itemReference))
+
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return errors.Wrap(err, "could not read body")
+	}
+	defer r.Body.Close()
+
+	var request putImageOrderRequest
+	err = json.Unmarshal(rawBody, &request)
+	if err != nil {
+		log.Info(r.Context(), "invalid json request", "body", string(rawBody), "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return nil
+	}
+
+	var images []*model.ItemImage
+	var event *events.ItemUpdated
+	err = handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		item, err := getItem(ctx, clients.Items, itemReference)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve item")
+		}
+		if err = clients.Images.Reorder(ctx, item.ID, request.ImageIDs); err != nil {
+			return errors.Wrap(err, "could not reorder images")
+		}
+		if event, err = updateItemImageRef(ctx, clients, item.ID); err != nil {
+			return err
+		}
+
+		images, err = clients.Images.GetAll(ctx, item.ID)
+		return errors.Wrap(err, "could not retrieve images")
+	})
+	if err != nil {
+		return errors.Wrap(writeImageError(w, err), "could not reorder images")
+	}
+
+	if event != nil {
+		handler.eventBus.Publish(events.TypeItemUpdated, event)
+	}
+
+	return errors.Wrap(writeImages(w, http.StatusOK, images), "could not write images")
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// deleteImageRequestDoc is for swagger generation only.
+// swagger:parameters deleteImageRequest
+type deleteImageRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the item. The name of the item is also accepted so older clients keep working.
+	//
+	// In: path
+	Item string `json:"item"`
+
+	// ID of the image.
+	//
+	// In: path
+	Image string `json:"image"`
+}
+
+// ----- End Documentation Generation Types --------------
+
+// DeleteImage is the handler that removes a model.ItemImage from an item.
+//
+// swagger:route DELETE /api/v1/auctions/items/{item}/images/{image} Images deleteImageRequest
+//
+// Deletes an image of an item.
+//
+// This will remove the image and its thumbnail. The image of the item is set to the thumbnail of the first image that
+// is left. This route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: noBody
+//    404: errorMessage
+func (handler *ImageHandler) DeleteImage(w http.ResponseWriter, r *http.Request) error {
+	itemReference := mux.Vars(r)["item"]
+	imageReference := mux.Vars(r)["image"]
+	r = r.WithContext(log.WithFields(r.Context(), "item", itemReference, "image", imageReference))
+
+	var deleted *model.ItemImage
+	var event *events.ItemUpdated
+	err := handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		imageID, err := strconv.ParseUint(imageReference, 10, 64)
+		if err != nil {
+			return errors.Wrap(storage.ErrEntityNotFound, "image is not an ID")
+		}
+
+		item, err := getItem(ctx, clients.Items, itemReference)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve item")
+		}
+
+		images, err := clients.Images.GetAll(ctx, item.ID)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve images")
+		}
+		for _, image := range images {
+			if image.ID == imageID {
+				deleted = image
+			}
+		}
+		if deleted == nil {
+			return errors.Wrapf(storage.ErrEntityNotFound, "item does not have image %d", imageID)
+		}
+
+		if err = clients.Images.Delete(ctx, item.ID, imageID); err != nil {
+			return errors.Wrap(err, "could not delete image")
+		}
+		event, err = updateItemImageRef(ctx, clients, item.ID)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(writeImageError(w, err), "could not delete image")
+	}
+
+	if event != nil {
+		handler.eventBus.Publish(events.TypeItemUpdated, event)
+	}
+	handler.deleteBlobs(r.Context(), deleted.Key, deleted.ThumbnailKey)
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getImageRequestDoc is for swagger generation only.
+// swagger:parameters getImageRequest
+type getImageRequestDoc struct {
+	// The key of the image from its URL.
+	//
+	// In: path
+	Key string `json:"key"`
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetImage is the handler that downloads a stored image or thumbnail.
+//
+// swagger:route GET /api/v1/images/{key} Images getImageRequest
+//
+// Downloads an image.
+//
+// This will send the image or thumbnail found at the URL given for an image of an item. Images never change once they
+// are stored so they can be cached indefinitely. This route does not require authentication.
+//
+//  Produces:
+//  - image/jpeg
+//  - image/png
+//  - image/webp
+//
+//  Schemes: http
+//
+//  Responses:
+//    200: noBody
+//    404: noBody
+func (handler *ImageHandler) GetImage(w http.ResponseWriter, r *http.Request) error {
+	key := mux.Vars(r)["key"]
+	object, err := handler.blobStore.Get(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) || errors.Is(err, blob.ErrInvalidKey) {
+			w.WriteHeader(http.StatusNotFound)
+			return nil
+		}
+		return errors.Wrap(err, "could not retrieve image")
+	}
+	defer object.Body.Close()
+
+	w.Header().Set("Content-Type", object.ContentType)
+	if object.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	}
+	w.Header().Set("Cache-Control", imageCacheControl)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = io.Copy(w, object.Body); err != nil {
+		// The status was already sent so the client only sees the image end early.
+		log.Warn(r.Context(), "could not send image", "key", key, "err", err)
+	}
+	return nil
+}
+
+// readUploads reads and checks every image in the multipart form of the request. The returned errors wrap
+// errInvalidUpload or the errors of processImage.
+func (handler *ImageHandler) readUploads(w http.ResponseWriter, r *http.Request) ([]*processedImage, error) {
+	// The limit leaves room for the headers of each part besides the images themselves.
+	r.Body = http.MaxBytesReader(w, r.Body, maxImagesPerUpload*(handler.maxImageSize+4096))
+	defer r.Body.Close()
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, errors.Wrap(errInvalidUpload, "request must be multipart/form-data")
+	}
+
+	var uploads []*processedImage
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(errInvalidUpload, err.Error())
+		}
+		if part.FormName() != imageFormField {
+			continue
+		}
+		if len(uploads) == maxImagesPerUpload {
+			return nil, errors.Wrapf(errInvalidUpload, "at most %d images can be uploaded at once", maxImagesPerUpload)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, handler.maxImageSize+1))
+		if err != nil {
+			return nil, errors.Wrap(errInvalidUpload, err.Error())
+		}
+		if int64(len(data)) > handler.maxImageSize {
+			return nil, errors.Wrapf(errImageTooLarge, "'%s' is larger than %d bytes", part.FileName(), handler.maxImageSize)
+		}
+
+		upload, err := processImage(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not process '%s'", part.FileName())
+		}
+		uploads = append(uploads, upload)
+	}
+
+	if len(uploads) == 0 {
+		return nil, errors.Wrapf(errInvalidUpload, "no images in the '%s' field", imageFormField)
+	}
+	return uploads, nil
+}
+
+// deleteBlobs removes the stored images. Failures are only logged since the images are no longer referenced.
+func (handler *ImageHandler) deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := handler.blobStore.Delete(ctx, key); err != nil {
+			log.Warn(ctx, "could not delete image", "key", key, "err", err)
+		}
+	}
+}
+
+// updateItemImageRef points the image of the item at the thumbnail of its first image. An image that was set by hand
+// is only replaced once the item has images of its own. The returned event is nil if the image did not change.
+func updateItemImageRef(ctx context.Context, clients *storage.Clients, itemID uint64) (*events.ItemUpdated, error) {
+	item, err := clients.Items.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not retrieve item")
+	}
+	images, err := clients.Images.GetAll(ctx, itemID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not retrieve images")
+	}
+
+	var imageRef string
+	if len(images) > 0 {
+		imageRef = imagesPath + images[0].ThumbnailKey
+	} else if !strings.HasPrefix(item.ImageRef, imagesPath) {
+		return nil, nil
+	}
+	if item.ImageRef == imageRef {
+		return nil, nil
+	}
+
+	item.ImageRef = imageRef
+	if err = clients.Items.Replace(ctx, item); err != nil {
+		return nil, errors.Wrap(err, "could not update image of item")
+	}
+
+	event := &events.ItemUpdated{
+		ItemID:   item.ID,
+		ItemName: item.Name,
+		ImageRef: imageRef,
+	}
+	if imageRef == "" {
+		event.Cleared = []string{"image"}
+	}
+	return event, nil
+}
+
+// newImageName returns a random name so the URL of every stored image is unique and the image can be cached forever.
+func newImageName() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.Wrap(err, "could not generate random name")
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func writeImages(w http.ResponseWriter, status int, images []*model.ItemImage) error {
+	responseObjects := make([]*imageResponse, len(images))
+	for i, image := range images {
+		responseObjects[i] = &imageResponse{
+			ID:           image.ID,
+			Position:     image.Position,
+			URL:          imagesPath + image.Key,
+			ThumbnailURL: imagesPath + image.ThumbnailKey,
+			ContentType:  image.ContentType,
+		}
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal images")
+	}
+
+	w.WriteHeader(status)
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// writeImageError responds to a request that could not change the images of an item because of the client. Any other
+// error is returned so it is handled as a server error.
+func writeImageError(w http.ResponseWriter, err error) error {
+	var message string
+	var status int
+	switch {
+	case errors.Is(err, storage.ErrEntityNotFound):
+		status, message = http.StatusNotFound, "item or image does not exist"
+	case errors.Is(err, storage.ErrInvalidOrder):
+		status, message = http.StatusBadRequest, "imageIds must have every image of the item once"
+	case errors.Is(err, errUnsupportedImageType):
+		status, message = http.StatusUnsupportedMediaType, err.Error()
+	case errors.Is(err, errImageTooLarge):
+		status, message = http.StatusRequestEntityTooLarge, err.Error()
+	case errors.Is(err, errInvalidImage), errors.Is(err, errInvalidUpload):
+		status, message = http.StatusBadRequest, err.Error()
+	default:
+		return writeItemChangeError(w, err)
+	}
+
+	w.WriteHeader(status)
+	response, err := json.Marshal(newErrorResponse("%s", message))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal error response")
+	}
+	fmt.Fprint(w, string(response))
+	return nil
+}
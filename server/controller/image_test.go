@@ -0,0 +1,415 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/blob"
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type imageHandlerTestSuite struct {
+	suite.Suite
+
+	client          *http.Client
+	server          *httptest.Server
+	auctionItemMock *mocks.AuctionItemClient
+	itemImageMock   *mocks.ItemImageClient
+	transactorMock  *mocks.Transactor
+	blobStore       *blob.FileStore
+	eventBus        *events.Bus
+	handler         *ImageHandler
+}
+
+func (ts *imageHandlerTestSuite) SetupSuite() {
+	ts.handler = NewImageHandler(ts.auctionItemMock, ts.itemImageMock, ts.transactorMock, ts.blobStore, ts.eventBus, DefaultMaxImageSize)
+	var router *mux.Router
+	ts.server, router = newTestServer()
+	ts.handler.RegisterRoutes(router)
+	ts.client = &http.Client{}
+}
+
+func (ts *imageHandlerTestSuite) SetupTest() {
+	ts.auctionItemMock = new(mocks.AuctionItemClient)
+	ts.itemImageMock = new(mocks.ItemImageClient)
+	ts.transactorMock = new(mocks.Transactor)
+	ts.transactorMock.On("RunInTx", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(
+		func(ctx context.Context, fn func(context.Context, *storage.Clients) error) error {
+			return fn(ctx, &storage.Clients{
+				Items:  ts.auctionItemMock,
+				Images: ts.itemImageMock,
+			})
+		},
+	).Maybe()
+	ts.blobStore = blob.NewFileStore(ts.T().TempDir())
+	ts.eventBus = events.NewBus(events.DefaultHistorySize)
+	ts.handler.itemClient = ts.auctionItemMock
+	ts.handler.imageClient = ts.itemImageMock
+	ts.handler.transactor = ts.transactorMock
+	ts.handler.blobStore = ts.blobStore
+	ts.handler.eventBus = ts.eventBus
+	ts.handler.maxImageSize = DefaultMaxImageSize
+}
+
+func (ts *imageHandlerTestSuite) TearDownTest() {
+	ts.auctionItemMock.AssertExpectations(ts.T())
+	ts.itemImageMock.AssertExpectations(ts.T())
+}
+
+func (ts *imageHandlerTestSuite) TearDownSuite() {
+	ts.server.Close()
+}
+
+func TestImageHandler(t *testing.T) {
+	suite.Run(t, new(imageHandlerTestSuite))
+}
+
+func (ts *imageHandlerTestSuite) TestPostImagesStoresImagesAndSetsItemImage() {
+	item := &model.AuctionItem{ID: 3, Name: "foo", ImageRef: "https://example.com/old.png", Version: 2}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+
+	var added []*model.ItemImage
+	ts.itemImageMock.On("Add", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.ItemImage")).Return(
+		func(ctx context.Context, image *model.ItemImage) error {
+			image.ID = uint64(10 + len(added))
+			image.Position = len(added)
+			added = append(added, image)
+			return nil
+		},
+	).Twice()
+	ts.itemImageMock.On("GetAll", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(
+		func(ctx context.Context, itemID uint64) []*model.ItemImage {
+			return added
+		},
+		nil,
+	)
+	ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(replaced *model.AuctionItem) bool {
+		return replaced.ImageRef == imagesPath+added[0].ThumbnailKey
+	})).Return(nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	response := ts.postImages("3", model.PermissionLevelAdmin, encodeTestPNG(ts.T(), 800, 400), encodeTestJPEG(ts.T(), 100, 200))
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+
+	var images []*imageResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&images))
+	ts.Require().Len(images, 2)
+	ts.Require().EqualValues(10, images[0].ID)
+	ts.Require().EqualValues("image/png", images[0].ContentType)
+	ts.Require().True(strings.HasSuffix(images[0].URL, ".png"), images[0].URL)
+	ts.Require().EqualValues("image/jpeg", images[1].ContentType)
+	ts.Require().EqualValues(1, images[1].Position)
+
+	for i, expected := range []image.Point{{400, 200}, {100, 200}} {
+		ts.Require().True(strings.HasPrefix(added[i].Key, "items/3/"), added[i].Key)
+		thumbnail := ts.readBlob(added[i].ThumbnailKey)
+		config, err := jpeg.DecodeConfig(bytes.NewReader(thumbnail))
+		ts.Require().NoError(err)
+		ts.Require().EqualValues(expected, image.Point{config.Width, config.Height})
+	}
+	ts.Require().NotEmpty(ts.readBlob(added[0].Key))
+
+	event := <-sub.Events()
+	ts.Require().EqualValues(events.TypeItemUpdated, event.Type)
+	ts.Require().Equal(&events.ItemUpdated{
+		ItemID:   item.ID,
+		ItemName: item.Name,
+		ImageRef: imagesPath + added[0].ThumbnailKey,
+	}, event.Payload)
+}
+
+func (ts *imageHandlerTestSuite) TestPostImages415OnUnsupportedType() {
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.AuctionItem{ID: 3}, nil)
+
+	response := ts.postImages("3", model.PermissionLevelAdmin, []byte("GIF89a not really a supported image"))
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusUnsupportedMediaType, response.StatusCode)
+}
+
+func (ts *imageHandlerTestSuite) TestPostImages400OnCorruptImage() {
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.AuctionItem{ID: 3}, nil)
+
+	corrupt := encodeTestPNG(ts.T(), 10, 10)[:40]
+	response := ts.postImages("3", model.PermissionLevelAdmin, corrupt)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *imageHandlerTestSuite) TestPostImages413WhenFileTooLarge() {
+	ts.handler.maxImageSize = 64
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.AuctionItem{ID: 3}, nil)
+
+	response := ts.postImages("3", model.PermissionLevelAdmin, encodeTestPNG(ts.T(), 100, 100))
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusRequestEntityTooLarge, response.StatusCode)
+}
+
+func (ts *imageHandlerTestSuite) TestPostImages400WithoutImages() {
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.AuctionItem{ID: 3}, nil)
+
+	response := ts.postImages("3", model.PermissionLevelAdmin)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *imageHandlerTestSuite) TestPostImages404WhenItemMissing() {
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(nil, storage.ErrEntityNotFound)
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), "3").Return(nil, storage.ErrEntityNotFound)
+
+	response := ts.postImages("3", model.PermissionLevelAdmin, encodeTestPNG(ts.T(), 10, 10))
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *imageHandlerTestSuite) TestPostImages403OnBidderRequest() {
+	response := ts.postImages("3", model.PermissionLevelBidder, encodeTestPNG(ts.T(), 10, 10))
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *imageHandlerTestSuite) TestPostImagesDeletesStoredImagesWhenAddFails() {
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.AuctionItem{ID: 3}, nil)
+	var added *model.ItemImage
+	ts.itemImageMock.On("Add", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.ItemImage")).Return(
+		func(ctx context.Context, image *model.ItemImage) error {
+			added = image
+			return storage.ErrEntityNotFound
+		},
+	)
+
+	response := ts.postImages("3", model.PermissionLevelAdmin, encodeTestPNG(ts.T(), 10, 10))
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+
+	for _, key := range []string{added.Key, added.ThumbnailKey} {
+		_, err := ts.blobStore.Get(context.Background(), key)
+		ts.Require().ErrorIs(err, blob.ErrNotFound)
+	}
+}
+
+func (ts *imageHandlerTestSuite) TestGetImagesReturnsImagesInOrder() {
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.AuctionItem{ID: 3}, nil)
+	ts.itemImageMock.On("GetAll", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return([]*model.ItemImage{
+		{ID: 7, ItemID: 3, Position: 0, Key: "items/3/a.png", ThumbnailKey: "items/3/a-thumbnail.jpg", ContentType: "image/png"},
+		{ID: 5, ItemID: 3, Position: 1, Key: "items/3/b.webp", ThumbnailKey: "items/3/b-thumbnail.jpg", ContentType: "image/webp"},
+	}, nil)
+
+	r := makeAuthenticatedRequest(ts.T(), http.MethodGet, ts.fullPath("auctions/items/3/images"), nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	var images []*imageResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&images))
+	ts.Require().EqualValues([]*imageResponse{
+		{ID: 7, Position: 0, URL: "/api/v1/images/items/3/a.png", ThumbnailURL: "/api/v1/images/items/3/a-thumbnail.jpg", ContentType: "image/png"},
+		{ID: 5, Position: 1, URL: "/api/v1/images/items/3/b.webp", ThumbnailURL: "/api/v1/images/items/3/b-thumbnail.jpg", ContentType: "image/webp"},
+	}, images)
+}
+
+func (ts *imageHandlerTestSuite) TestDeleteImageRemovesImageAndClearsItemImage() {
+	item := &model.AuctionItem{ID: 3, Name: "foo", ImageRef: imagesPath + "items/3/a-thumbnail.jpg", Version: 4}
+	deleted := &model.ItemImage{ID: 7, ItemID: 3, Key: "items/3/a.png", ThumbnailKey: "items/3/a-thumbnail.jpg"}
+	ts.Require().NoError(ts.blobStore.Put(context.Background(), deleted.Key, "image/png", []byte("image")))
+	ts.Require().NoError(ts.blobStore.Put(context.Background(), deleted.ThumbnailKey, "image/jpeg", []byte("thumbnail")))
+
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.itemImageMock.On("GetAll", mock.AnythingOfType("*context.valueCtx"), item.ID).Return([]*model.ItemImage{deleted}, nil).Once()
+	ts.itemImageMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), item.ID, deleted.ID).Return(nil)
+	ts.itemImageMock.On("GetAll", mock.AnythingOfType("*context.valueCtx"), item.ID).Return([]*model.ItemImage{}, nil).Once()
+	ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(replaced *model.AuctionItem) bool {
+		return replaced.ImageRef == ""
+	})).Return(nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := makeAuthenticatedRequest(ts.T(), http.MethodDelete, ts.fullPath("auctions/items/3/images/7"), nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	for _, key := range []string{deleted.Key, deleted.ThumbnailKey} {
+		_, err = ts.blobStore.Get(context.Background(), key)
+		ts.Require().ErrorIs(err, blob.ErrNotFound)
+	}
+
+	event := <-sub.Events()
+	ts.Require().Equal(&events.ItemUpdated{ItemID: item.ID, ItemName: item.Name, Cleared: []string{"image"}}, event.Payload)
+}
+
+func (ts *imageHandlerTestSuite) TestDeleteImageKeepsImageSetByHand() {
+	item := &model.AuctionItem{ID: 3, Name: "foo", ImageRef: "https://example.com/image.png"}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.itemImageMock.On("GetAll", mock.AnythingOfType("*context.valueCtx"), item.ID).Return([]*model.ItemImage{{ID: 7, ItemID: 3}}, nil).Once()
+	ts.itemImageMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), item.ID, uint64(7)).Return(nil)
+	ts.itemImageMock.On("GetAll", mock.AnythingOfType("*context.valueCtx"), item.ID).Return([]*model.ItemImage{}, nil).Once()
+
+	r := makeAuthenticatedRequest(ts.T(), http.MethodDelete, ts.fullPath("auctions/items/3/images/7"), nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	ts.auctionItemMock.AssertNotCalled(ts.T(), "Replace", mock.Anything, mock.Anything)
+}
+
+func (ts *imageHandlerTestSuite) TestDeleteImage404WhenNotImageOfItem() {
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.AuctionItem{ID: 3}, nil)
+	ts.itemImageMock.On("GetAll", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return([]*model.ItemImage{{ID: 7, ItemID: 3}}, nil)
+
+	r := makeAuthenticatedRequest(ts.T(), http.MethodDelete, ts.fullPath("auctions/items/3/images/8"), nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *imageHandlerTestSuite) TestPutImageOrderMovesFirstImageToItem() {
+	item := &model.AuctionItem{ID: 3, Name: "foo", ImageRef: imagesPath + "items/3/a-thumbnail.jpg"}
+	reordered := []*model.ItemImage{
+		{ID: 5, ItemID: 3, Position: 0, Key: "items/3/b.png", ThumbnailKey: "items/3/b-thumbnail.jpg"},
+		{ID: 7, ItemID: 3, Position: 1, Key: "items/3/a.png", ThumbnailKey: "items/3/a-thumbnail.jpg"},
+	}
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.itemImageMock.On("Reorder", mock.AnythingOfType("*context.valueCtx"), item.ID, []uint64{5, 7}).Return(nil)
+	ts.itemImageMock.On("GetAll", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(reordered, nil)
+	ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(replaced *model.AuctionItem) bool {
+		return replaced.ImageRef == imagesPath+"items/3/b-thumbnail.jpg"
+	})).Return(nil)
+
+	response := ts.putImageOrder(`{"imageIds":[5,7]}`)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	var images []*imageResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&images))
+	ts.Require().Len(images, 2)
+	ts.Require().EqualValues(5, images[0].ID)
+}
+
+func (ts *imageHandlerTestSuite) TestPutImageOrder400OnInvalidOrder() {
+	ts.auctionItemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.AuctionItem{ID: 3}, nil)
+	ts.itemImageMock.On("Reorder", mock.AnythingOfType("*context.valueCtx"), uint64(3), []uint64{5}).Return(storage.ErrInvalidOrder)
+
+	response := ts.putImageOrder(`{"imageIds":[5]}`)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *imageHandlerTestSuite) TestGetImageServesStoredImage() {
+	ts.Require().NoError(ts.blobStore.Put(context.Background(), "items/3/a.png", "image/png", []byte("image")))
+
+	response, err := ts.client.Get(ts.fullPath("images/items/3/a.png"))
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	ts.Require().EqualValues("image/png", response.Header.Get("Content-Type"))
+	ts.Require().EqualValues(imageCacheControl, response.Header.Get("Cache-Control"))
+	body, err := io.ReadAll(response.Body)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("image", string(body))
+}
+
+func (ts *imageHandlerTestSuite) TestGetImage404WhenMissing() {
+	response, err := ts.client.Get(ts.fullPath("images/items/3/missing.png"))
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *imageHandlerTestSuite) fullPath(path string) string {
+	return fmt.Sprintf("%s/api/v1/%s", ts.server.URL, path)
+}
+
+func (ts *imageHandlerTestSuite) postImages(itemReference string, permission model.PermissionLevel, images ...[]byte) *http.Response {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for i, data := range images {
+		part, err := writer.CreateFormFile(imageFormField, fmt.Sprintf("image%d", i))
+		ts.Require().NoError(err)
+		_, err = part.Write(data)
+		ts.Require().NoError(err)
+	}
+	ts.Require().NoError(writer.Close())
+
+	r := makeAuthenticatedRequest(ts.T(), http.MethodPost, ts.fullPath(fmt.Sprintf("auctions/items/%s/images", itemReference)), &body, &model.User{
+		Permission: permission,
+	})
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	return response
+}
+
+func (ts *imageHandlerTestSuite) putImageOrder(body string) *http.Response {
+	r := makeAuthenticatedRequest(ts.T(), http.MethodPut, ts.fullPath("auctions/items/3/images/order"), strings.NewReader(body), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	return response
+}
+
+func (ts *imageHandlerTestSuite) readBlob(key string) []byte {
+	object, err := ts.blobStore.Get(context.Background(), key)
+	ts.Require().NoError(err)
+	defer object.Body.Close()
+	data, err := io.ReadAll(object.Body)
+	ts.Require().NoError(err)
+	return data
+}
+
+func newTestImage(width int, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func encodeTestPNG(t *testing.T, width int, height int) []byte {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, newTestImage(width, height)); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func encodeTestJPEG(t *testing.T, width int, height int) []byte {
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, newTestImage(width, height), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
@@ -0,0 +1,384 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/itemfile"
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/pkg/errors"
+)
+
+type (
+	// swagger:model
+	importItemsResponse struct {
+		// Whether the items were only checked and nothing was changed.
+		//
+		// Required: true
+		DryRun bool `json:"dryRun"`
+
+		// How many items were created.
+		//
+		// Required: true
+		Created int `json:"created"`
+
+		// How many items were updated.
+		//
+		// Required: true
+		Updated int `json:"updated"`
+
+		// What was done for every row in the order of the file.
+		//
+		// Required: true
+		Rows []*importedRowResponse `json:"rows"`
+	}
+
+	// swagger:model
+	importedRowResponse struct {
+		// The position of the row in the file starting at 1. The header of a CSV file is not counted.
+		//
+		// Required: true
+		Row int `json:"row"`
+
+		// Whether the item was created or updated.
+		//
+		// Required: true
+		// Enum: created,updated
+		Action string `json:"action"`
+
+		// The item as it was stored.
+		//
+		// Required: true
+		Item *getItemResponse `json:"item"`
+	}
+
+	// swagger:model
+	importItemsErrorResponse struct {
+		// Why the file was not imported.
+		//
+		// Required: true
+		Message string `json:"message"`
+
+		// The reason each invalid row cannot be imported.
+		//
+		// Required: true
+		Errors []*rowErrorResponse `json:"errors"`
+	}
+
+	// swagger:model
+	rowErrorResponse struct {
+		// The position of the row in the file starting at 1. The header of a CSV file is not counted.
+		//
+		// Required: true
+		Row int `json:"row"`
+
+		// Why the row cannot be imported.
+		//
+		// Required: true
+		Message string `json:"message"`
+	}
+)
+
+// maxItemFileSize is the largest file of items that can be imported at once.
+const maxItemFileSize = 10 << 20
+
+// exportFileName is the name browsers save exported items under.
+const exportFileName = "items.csv"
+
+// ----- Start Documentation Generation Types --------------
+
+// importItemsRequestDoc is for swagger generation only.
+// swagger:parameters importItemsRequest
+type importItemsRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// Whether to only check the file and report what would be imported without changing anything.
+	//
+	// In: query
+	DryRun bool `json:"dryRun"`
+
+	// A CSV file with a header row or a JSON array of items with the same fields as when creating an item.
+	//
+	// In: body
+	Body string
+}
+
+// Contains what was done for every row of the file.
+//
+// swagger:response importItemsResponse
+type importItemsResponseDoc struct {
+
+	// In: body
+	Body importItemsResponse
+}
+
+// Contains every row that cannot be imported.
+//
+// swagger:response importItemsErrorResponse
+type importItemsErrorResponseDoc struct {
+
+	// In: body
+	Body importItemsErrorResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// ImportItems is the handler that creates or updates many model.AuctionItems from a file.
+//
+// swagger:route POST /api/v1/auctions/items/import Auctions importItemsRequest
+//
+// Imports items from a CSV or JSON file.
+//
+// This will create or update an item for every row of the file. Rows are matched to existing items by name, ignoring
+// case, and replace every field of the item except the image, which is kept when the row does not have one. A CSV file
+// has a header row naming its columns, tags are separated by semicolons and the columns written by exporting are
+// ignored. The categories of the rows must already exist. Nothing is imported if any row is invalid, in which case
+// every invalid row is reported. This route is only available to Admin users. It is also served at
+// /api/v1/auctions/admin/items/import for older clients.
+//
+//  Consumes:
+//  - text/csv
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: importItemsResponse
+//    400: importItemsErrorResponse
+//    413: errorMessage
+//    415: errorMessage
+func (handler *AuctionHandler) ImportItems(w http.ResponseWriter, r *http.Request) error {
+	var format itemfile.Format
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case err != nil:
+	case mediaType == "text/csv":
+		format = itemfile.FormatCSV
+	case mediaType == "application/json":
+		format = itemfile.FormatJSON
+	}
+	if format == "" {
+		return errors.Wrap(
+			writeErrorMessage(w, http.StatusUnsupportedMediaType, "Content-Type must be text/csv or application/json"),
+			"could not import items",
+		)
+	}
+
+	dryRun := false
+	if rawDryRun := r.URL.Query().Get("dryRun"); rawDryRun != "" {
+		if dryRun, err = strconv.ParseBool(rawDryRun); err != nil {
+			return errors.Wrap(writeErrorMessage(w, http.StatusBadRequest, "dryRun must be true or false"),
+				"could not import items")
+		}
+	}
+
+	defer r.Body.Close()
+	rawBody, err := io.ReadAll(io.LimitReader(r.Body, maxItemFileSize+1))
+	if err != nil {
+		return errors.Wrap(err, "could not read body")
+	}
+	if len(rawBody) > maxItemFileSize {
+		return errors.Wrap(
+			writeErrorMessage(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("file is larger than %d bytes", maxItemFileSize)),
+			"could not import items",
+		)
+	}
+
+	rows, rowErrors, err := itemfile.Read(bytes.NewReader(rawBody), format, handler.currency)
+	if errors.Is(err, itemfile.ErrInvalidFile) {
+		log.Info(r.Context(), "invalid item file", "err", err)
+		return errors.Wrap(writeImportErrors(w, err.Error(), nil), "could not import items")
+	} else if err != nil {
+		return errors.Wrap(err, "could not read items")
+	}
+
+	result, err := itemfile.Import(r.Context(), handler.transactor, rows, rowErrors, dryRun)
+	if err != nil {
+		return errors.Wrap(err, "could not import items")
+	}
+	if len(result.Errors) > 0 {
+		message := "file has invalid rows, nothing was imported"
+		return errors.Wrap(writeImportErrors(w, message, result.Errors), "could not import items")
+	}
+
+	response := &importItemsResponse{
+		DryRun:  result.DryRun,
+		Created: result.Count(itemfile.ActionCreated),
+		Updated: result.Count(itemfile.ActionUpdated),
+		Rows:    make([]*importedRowResponse, len(result.Rows)),
+	}
+	for i, row := range result.Rows {
+		response.Rows[i] = &importedRowResponse{
+			Row:    row.Number,
+			Action: string(row.Action),
+			Item:   newGetItemResponse(row.Item),
+		}
+
+		if dryRun {
+			continue
+		}
+		if row.Action == itemfile.ActionCreated {
+			handler.eventBus.Publish(events.TypeItemCreated, newItemCreatedEvent(row.Item))
+		} else {
+			handler.eventBus.Publish(events.TypeItemUpdated, newImportedItemUpdatedEvent(row.Previous, row.Item))
+		}
+	}
+
+	rawResponse, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal import result")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// exportItemsRequestDoc is for swagger generation only.
+// swagger:parameters exportItemsRequest
+type exportItemsRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// ----- End Documentation Generation Types --------------
+
+// ExportItems is the handler that writes every model.AuctionItem with its highest bid as a CSV file.
+//
+// swagger:route GET /api/v1/auctions/items/export Auctions exportItemsRequest
+//
+// Exports every item as a CSV file.
+//
+// This will return every item in the order they were created along with its current bid and high bidder so the results
+// of the auction can be opened as a spreadsheet. The file can be imported again. This route is only available to Admin
+// users. It is also served at /api/v1/auctions/admin/items/export for older clients.
+//
+//  Produces:
+//  - text/csv
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: noBody
+func (handler *AuctionHandler) ExportItems(w http.ResponseWriter, r *http.Request) error {
+	items, err := handler.auctionItemClient.GetAll(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve auction items")
+	}
+
+	highestBids, err := handler.auctionBidClient.GetAllWinningBids(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve highest bids")
+	}
+
+	// The file is built before anything is sent so a failure can still be reported as a server error.
+	var file bytes.Buffer
+	if err = itemfile.WriteCSV(&file, items, highestBids); err != nil {
+		return errors.Wrap(err, "could not export items")
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": exportFileName,
+	}))
+	w.WriteHeader(http.StatusOK)
+	if _, err = file.WriteTo(w); err != nil {
+		log.Warn(r.Context(), "could not send exported items", "err", err)
+	}
+	return nil
+}
+
+// writeImportErrors responds to an import that was rejected because of the file.
+func writeImportErrors(w http.ResponseWriter, message string, rowErrors []*itemfile.RowError) error {
+	response := &importItemsErrorResponse{
+		Message: message,
+		Errors:  make([]*rowErrorResponse, len(rowErrors)),
+	}
+	for i, rowErr := range rowErrors {
+		response.Errors[i] = &rowErrorResponse{
+			Row:     rowErr.Number,
+			Message: rowErr.Message,
+		}
+	}
+
+	rawResponse, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal error response")
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// writeErrorMessage responds with the status and the message as an errorResponse.
+func writeErrorMessage(w http.ResponseWriter, status int, message string) error {
+	response, err := json.Marshal(newErrorResponse("%s", message))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal error response")
+	}
+
+	w.WriteHeader(status)
+	fmt.Fprint(w, string(response))
+	return nil
+}
+
+// newImportedItemUpdatedEvent describes every field of an item that was replaced by an import. The fields the import
+// emptied are listed as cleared, the same way a merge patch would list them.
+func newImportedItemUpdatedEvent(previous *model.AuctionItem, item *model.AuctionItem) *events.ItemUpdated {
+	event := &events.ItemUpdated{
+		ItemID:          item.ID,
+		ItemName:        item.Name,
+		ImageRef:        item.ImageRef,
+		Description:     item.Description,
+		Category:        item.Category,
+		Tags:            item.Tags,
+		DonorName:       item.DonorName,
+		DonorBusiness:   item.DonorBusiness,
+		FairMarketValue: optionalMoney(item.FairMarketValue),
+		Quantity:        item.Quantity,
+	}
+	if previous.Name != item.Name {
+		event.PreviousName = previous.Name
+	}
+
+	for _, field := range []struct {
+		name    string
+		cleared bool
+	}{
+		{name: "image", cleared: previous.ImageRef != "" && item.ImageRef == ""},
+		{name: "description", cleared: previous.Description != "" && item.Description == ""},
+		{name: "category", cleared: previous.Category != "" && item.Category == ""},
+		{name: "tags", cleared: len(previous.Tags) > 0 && len(item.Tags) == 0},
+		{name: "donorName", cleared: previous.DonorName != "" && item.DonorName == ""},
+		{name: "donorBusiness", cleared: previous.DonorBusiness != "" && item.DonorBusiness == ""},
+		{name: "fairMarketValue", cleared: previous.FairMarketValue.Amount != 0 && item.FairMarketValue.Amount == 0},
+	} {
+		if field.cleared {
+			event.Cleared = append(event.Cleared, field.name)
+		}
+	}
+	return event
+}
@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/stretchr/testify/mock"
+)
+
+func (ts *auctionHandlerTestSuite) TestImportItemsCreatesAndUpdatesItems() {
+	existing := &model.AuctionItem{ID: 3, Name: "Quilt", ImageRef: "quilt.png", Description: "Old", DonorName: "Jane"}
+	ts.categoryMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return([]*model.Category{{Name: "Crafts"}}, nil)
+	ts.auctionItemMock.On("GetDeleted", mock.AnythingOfType("*context.valueCtx")).Return(nil, nil)
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), "quilt").Return(existing, nil)
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), "Mug").Return(nil, storage.ErrEntityNotFound)
+	ts.auctionItemMock.On("Replace", mock.AnythingOfType("*context.valueCtx"), &model.AuctionItem{
+		ID:          3,
+		Name:        "quilt",
+		ImageRef:    "quilt.png",
+		Description: "New",
+		Category:    "Crafts",
+	}).Return(nil)
+	ts.auctionItemMock.On("Create", mock.AnythingOfType("*context.valueCtx"), &model.AuctionItem{
+		Name: "Mug",
+		Tags: []string{"kitchen", "blue"},
+	}).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.AuctionItem).ID = 4
+	}).Return(nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	body := "name,description,category,tags\nquilt,New,Crafts,\nMug,,,kitchen;blue\n"
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items/import", strings.NewReader(body), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("Content-Type", "text/csv")
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	var result importItemsResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&result))
+	ts.Require().Equal(importItemsResponse{
+		Created: 1,
+		Updated: 1,
+		Rows: []*importedRowResponse{
+			{
+				Row:    1,
+				Action: "updated",
+				Item:   &getItemResponse{ID: 3, Name: "quilt", ImageRef: "quilt.png", Description: "New", Category: "Crafts"},
+			},
+			{Row: 2, Action: "created", Item: &getItemResponse{ID: 4, Name: "Mug", Tags: []string{"kitchen", "blue"}}},
+		},
+	}, result)
+
+	event := <-sub.Events()
+	ts.Require().EqualValues(events.TypeItemUpdated, event.Type)
+	ts.Require().Equal(&events.ItemUpdated{
+		ItemID:       3,
+		ItemName:     "quilt",
+		PreviousName: "Quilt",
+		ImageRef:     "quilt.png",
+		Description:  "New",
+		Category:     "Crafts",
+		Cleared:      []string{"donorName"},
+	}, event.Payload)
+
+	event = <-sub.Events()
+	ts.Require().EqualValues(events.TypeItemCreated, event.Type)
+	ts.Require().Equal(&events.ItemCreated{ItemID: 4, ItemName: "Mug", Tags: []string{"kitchen", "blue"}}, event.Payload)
+}
+
+func (ts *auctionHandlerTestSuite) TestImportItemsDryRunPublishesNothing() {
+	ts.categoryMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return(nil, nil)
+	ts.auctionItemMock.On("GetDeleted", mock.AnythingOfType("*context.valueCtx")).Return(nil, nil)
+	ts.auctionItemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), "Mug").Return(nil, storage.ErrEntityNotFound)
+	ts.auctionItemMock.On("Create", mock.AnythingOfType("*context.valueCtx"), &model.AuctionItem{Name: "Mug"}).Return(nil)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items/import?dryRun=true", strings.NewReader(`[{"name":"Mug"}]`),
+		&model.User{Permission: model.PermissionLevelAdmin})
+	r.Header.Set("Content-Type", "application/json")
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	var result importItemsResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&result))
+	ts.Require().True(result.DryRun)
+	ts.Require().EqualValues(1, result.Created)
+	ts.Require().Empty(sub.Events())
+}
+
+func (ts *auctionHandlerTestSuite) TestImportItems400ListsEveryInvalidRow() {
+	ts.categoryMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return(nil, nil)
+	ts.auctionItemMock.On("GetDeleted", mock.AnythingOfType("*context.valueCtx")).Return(nil, nil)
+
+	body := "name,category,fairMarketValue\nQuilt,Crafts,10\n,,\nMug,,ten\n"
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items/import", strings.NewReader(body), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+
+	var result importItemsErrorResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&result))
+	ts.Require().Equal(importItemsErrorResponse{
+		Message: "file has invalid rows, nothing was imported",
+		Errors: []*rowErrorResponse{
+			{Row: 1, Message: "category 'Crafts' does not exist"},
+			{Row: 2, Message: "name is required"},
+			{Row: 3, Message: `fairMarketValue 'ten' is not an amount such as "150.00 USD"`},
+		},
+	}, result)
+}
+
+func (ts *auctionHandlerTestSuite) TestImportItems400WhenFileIsInvalid() {
+	bodies := map[string]string{
+		"text/csv":         "description\nNo name\n",
+		"application/json": `{"name":"Mug"}`,
+	}
+	for contentType, body := range bodies {
+		r := ts.makeAuthenticatedRequest(http.MethodPost, "items/import", strings.NewReader(body), &model.User{
+			Permission: model.PermissionLevelAdmin,
+		})
+		r.Header.Set("Content-Type", contentType)
+		response, err := ts.client.Do(r)
+		ts.Require().NoError(err)
+		ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode, body)
+	}
+}
+
+func (ts *auctionHandlerTestSuite) TestImportItems415WithoutCSVOrJSONContentType() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "items/import", strings.NewReader("name\nMug\n"), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	r.Header.Set("Content-Type", "text/plain")
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusUnsupportedMediaType, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestExportItemsWritesCSV() {
+	quilt := &model.AuctionItem{
+		ID:              1,
+		Name:            "Quilt",
+		Tags:            []string{"red", "blue"},
+		FairMarketValue: usd(15000),
+		Quantity:        1,
+	}
+	ts.auctionItemMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return([]*model.AuctionItem{quilt}, nil)
+	ts.auctionBidMock.On("GetAllWinningBids", mock.AnythingOfType("*context.valueCtx")).Return([]*model.AuctionBid{
+		{BidAmount: usd(4050), Bidder: &model.User{Username: "bob", DisplayName: "Bob"}, Item: quilt},
+	}, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "items/export", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	ts.Require().EqualValues("text/csv; charset=utf-8", response.Header.Get("Content-Type"))
+	ts.Require().EqualValues("attachment; filename=items.csv", response.Header.Get("Content-Disposition"))
+
+	rawResponse, err := io.ReadAll(response.Body)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("id,name,description,image,category,tags,donorName,donorBusiness,fairMarketValue,"+
+		"quantity,closesAt,currentBid,highBidder,highBidderName\n"+
+		"1,Quilt,,,,red;blue,,,150.00 USD,1,,40.50 USD,bob,Bob\n", string(rawResponse))
+}
+
+func (ts *auctionHandlerTestSuite) TestExportItems403ForBidders() {
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "items/export", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *auctionHandlerTestSuite) TestExportItemsIsStillServedUnderAdmin() {
+	ts.auctionItemMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return(nil, nil)
+	ts.auctionBidMock.On("GetAllWinningBids", mock.AnythingOfType("*context.valueCtx")).Return(nil, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "admin/items/export", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	ts.Require().EqualValues("text/csv; charset=utf-8", response.Header.Get("Content-Type"))
+}
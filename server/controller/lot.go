@@ -0,0 +1,546 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+type (
+	// swagger:model
+	lotResponse struct {
+		// The ID of the lot.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The name of the lot.
+		//
+		// Required: true
+		Name string `json:"name"`
+
+		// The items sold together in the lot ordered by ID. Bids on any of them are bids on the lot.
+		//
+		// Required: true
+		Items []*getItemResponse `json:"items"`
+	}
+
+	postLotRequest struct {
+		// Name of the lot. It must be unique, ignoring case.
+		//
+		// Required: true
+		Name string `json:"name"`
+
+		// IDs of the items to sell together. At least two items that are not in a lot and have no bids are required.
+		//
+		// Required: true
+		ItemIDs []uint64 `json:"itemIds"`
+	}
+
+	postMergeLotRequest struct {
+		// IDs of the lots whose items are moved into the lot. The lots are removed.
+		LotIDs []uint64 `json:"lotIds"`
+
+		// IDs of the items that are not in a lot to add to the lot.
+		ItemIDs []uint64 `json:"itemIds"`
+	}
+
+	postSplitLotRequest struct {
+		// IDs of the items to take out of the lot. The whole lot is split up when this is empty or fewer than two items
+		// would be left.
+		ItemIDs []uint64 `json:"itemIds"`
+	}
+)
+
+// LotHandler provides handlers for endpoints involving model.Lots.
+type LotHandler struct {
+	lotClient  storage.LotClient
+	transactor storage.Transactor
+}
+
+// NewLotHandler creates a new LotHandler with the necessary storage objects.
+func NewLotHandler(lotClient storage.LotClient, transactor storage.Transactor) *LotHandler {
+	return &LotHandler{
+		lotClient:  lotClient,
+		transactor: transactor,
+	}
+}
+
+// RegisterRoutes registers all of the paths to the handler functions.
+func (handler *LotHandler) RegisterRoutes(router *mux.Router) {
+	lotsRouter := router.PathPrefix("/v1/auctions/lots").Subrouter()
+	lotsRouter.Use(middleware.VerifyAuthToken)
+
+	lotsAdmin := lotsRouter.NewRoute().Subrouter()
+	lotsAdmin.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin))
+	lotsAdmin.HandleFunc("", wrapHandler(handler.PostLot)).Methods(http.MethodPost)
+	lotsAdmin.HandleFunc("/{lot}/merge", wrapHandler(handler.PostMergeLot)).Methods(http.MethodPost)
+	lotsAdmin.HandleFunc("/{lot}/split", wrapHandler(handler.PostSplitLot)).Methods(http.MethodPost)
+
+	lotsBoth := lotsRouter.NewRoute().Subrouter()
+	lotsBoth.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin, model.PermissionLevelBidder))
+	lotsBoth.HandleFunc("", wrapHandler(handler.GetLots)).Methods(http.MethodGet)
+	lotsBoth.HandleFunc("/{lot}", wrapHandler(handler.GetLot)).Methods(http.MethodGet)
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getLotsRequestDoc is for swagger generation only.
+// swagger:parameters getLotsRequest
+type getLotsRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// Contains every lot in the order they were created.
+//
+// swagger:response getLotsResponse
+type getLotsResponseDoc struct {
+
+	// In: body
+	Body []lotResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetLots is the handler that retrieves all model.Lots as serialized JSON.
+//
+// swagger:route GET /api/v1/auctions/lots Lots getLotsRequest
+//
+// Gets all lots.
+//
+// This will retrieve every lot along with the items sold in it.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getLotsResponse
+func (handler *LotHandler) GetLots(w http.ResponseWriter, r *http.Request) error {
+	lots, err := handler.lotClient.GetAll(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve lots")
+	}
+
+	responseObjects := make([]*lotResponse, len(lots))
+	for i, lot := range lots {
+		responseObjects[i] = newLotResponse(lot)
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal lots")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getLotRequestDoc is for swagger generation only.
+// swagger:parameters getLotRequest
+type getLotRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the lot.
+	//
+	// In: path
+	Lot uint64 `json:"lot"`
+}
+
+// Contains the lot along with the items sold in it.
+//
+// swagger:response getLotResponse
+type getLotResponseDoc struct {
+
+	// In: body
+	Body lotResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetLot is the handler that retrieves a model.Lot as serialized JSON.
+//
+// swagger:route GET /api/v1/auctions/lots/{lot} Lots getLotRequest
+//
+// Gets a lot.
+//
+// This will retrieve the lot along with the items sold in it.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getLotResponse
+//    404: errorMessage
+func (handler *LotHandler) GetLot(w http.ResponseWriter, r *http.Request) error {
+	id, err := lotIDFromPath(r)
+	if err != nil {
+		return errors.Wrap(writeLotError(w, err), "could not retrieve lot")
+	}
+	r = r.WithContext(log.WithFields(r.Context(), "lot", id))
+
+	lot, err := handler.lotClient.GetByID(r.Context(), id)
+	if err != nil {
+		return errors.Wrap(writeLotError(w, err), "could not retrieve lot")
+	}
+
+	return errors.Wrap(writeLot(w, http.StatusOK, lot), "could not write lot")
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// postLotRequestDoc is for swagger generation only.
+// swagger:parameters postLotRequest
+type postLotRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// In: body
+	Body postLotRequest
+}
+
+// Contains the lot that was created.
+//
+// swagger:response postLotResponse
+type postLotResponseDoc struct {
+
+	// In: body
+	Body lotResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PostLot is the handler that creates a new model.Lot.
+//
+// swagger:route POST /api/v1/auctions/lots Lots postLotRequest
+//
+// Creates a new lot.
+//
+// This will create a lot that sells the items together. Bids on any item in the lot are bids on the whole lot, so the
+// items cannot have bids yet. This route is only available to Admin users.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    201: postLotResponse
+//    400: errorMessage
+//    404: errorMessage
+//    409: errorMessage
+func (handler *LotHandler) PostLot(w http.ResponseWriter, r *http.Request) error {
+	var request postLotRequest
+	if ok, err := readLotRequest(w, r, &request); !ok {
+		return err
+	}
+
+	lot := &model.Lot{
+		Name: strings.TrimSpace(request.Name),
+	}
+	if lot.Name == "" {
+		return errors.Wrap(writeLotError(w, errLotNameRequired), "could not create lot")
+	}
+
+	err := handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		return clients.Lots.Create(ctx, lot, request.ItemIDs)
+	})
+	if err != nil {
+		return errors.Wrap(writeLotError(w, err), "could not store lot")
+	}
+
+	return errors.Wrap(writeLot(w, http.StatusCreated, lot), "could not write lot")
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// postMergeLotRequestDoc is for swagger generation only.
+// swagger:parameters postMergeLotRequest
+type postMergeLotRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the lot to merge into.
+	//
+	// In: path
+	Lot uint64 `json:"lot"`
+
+	// In: body
+	Body postMergeLotRequest
+}
+
+// Contains the lot after the merge.
+//
+// swagger:response postMergeLotResponse
+type postMergeLotResponseDoc struct {
+
+	// In: body
+	Body lotResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PostMergeLot is the handler that merges lots and items into a model.Lot.
+//
+// swagger:route POST /api/v1/auctions/lots/{lot}/merge Lots postMergeLotRequest
+//
+// Merges lots and items into a lot.
+//
+// This will move the items of the other lots and the standalone items into the lot and remove the other lots. It is
+// only allowed before any of the items have bids. This route is only available to Admin users.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: postMergeLotResponse
+//    404: errorMessage
+//    409: errorMessage
+func (handler *LotHandler) PostMergeLot(w http.ResponseWriter, r *http.Request) error {
+	id, err := lotIDFromPath(r)
+	if err != nil {
+		return errors.Wrap(writeLotError(w, err), "could not merge lot")
+	}
+	r = r.WithContext(log.WithFields(r.Context(), "lot", id))
+
+	var request postMergeLotRequest
+	if ok, err := readLotRequest(w, r, &request); !ok {
+		return err
+	}
+
+	var lot *model.Lot
+	err = handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		if err := clients.Lots.Merge(ctx, id, request.LotIDs, request.ItemIDs); err != nil {
+			return err
+		}
+		var err error
+		lot, err = clients.Lots.GetByID(ctx, id)
+		return errors.Wrap(err, "could not retrieve merged lot")
+	})
+	if err != nil {
+		return errors.Wrap(writeLotError(w, err), "could not merge lot")
+	}
+
+	return errors.Wrap(writeLot(w, http.StatusOK, lot), "could not write lot")
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// postSplitLotRequestDoc is for swagger generation only.
+// swagger:parameters postSplitLotRequest
+type postSplitLotRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the lot to split.
+	//
+	// In: path
+	Lot uint64 `json:"lot"`
+
+	// In: body
+	Body postSplitLotRequest
+}
+
+// Contains the lot after the split.
+//
+// swagger:response postSplitLotResponse
+type postSplitLotResponseDoc struct {
+
+	// In: body
+	Body lotResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PostSplitLot is the handler that takes items out of a model.Lot.
+//
+// swagger:route POST /api/v1/auctions/lots/{lot}/split Lots postSplitLotRequest
+//
+// Splits items out of a lot.
+//
+// This will take the items out of the lot so they are sold on their own. The lot is removed when fewer than two items
+// would be left or no items are given, in which case nothing is returned. It is only allowed before the lot has bids.
+// This route is only available to Admin users.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: postSplitLotResponse
+//    204: noBody
+//    404: errorMessage
+//    409: errorMessage
+func (handler *LotHandler) PostSplitLot(w http.ResponseWriter, r *http.Request) error {
+	id, err := lotIDFromPath(r)
+	if err != nil {
+		return errors.Wrap(writeLotError(w, err), "could not split lot")
+	}
+	r = r.WithContext(log.WithFields(r.Context(), "lot", id))
+
+	var request postSplitLotRequest
+	if ok, err := readLotRequest(w, r, &request); !ok {
+		return err
+	}
+
+	var lot *model.Lot
+	err = handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		if err := clients.Lots.Split(ctx, id, request.ItemIDs); err != nil {
+			return err
+		}
+		var err error
+		lot, err = clients.Lots.GetByID(ctx, id)
+		if errors.Is(err, storage.ErrEntityNotFound) {
+			// The lot was split up entirely.
+			lot, err = nil, nil
+		}
+		return errors.Wrap(err, "could not retrieve split lot")
+	})
+	if err != nil {
+		return errors.Wrap(writeLotError(w, err), "could not split lot")
+	}
+
+	if lot == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	return errors.Wrap(writeLot(w, http.StatusOK, lot), "could not write lot")
+}
+
+var (
+	errLotNameRequired = errors.New("name of the lot is required")
+	errLotIDInvalid    = errors.New("lot is not an ID")
+)
+
+// newLotResponse converts the lot to the response sent to clients.
+func newLotResponse(lot *model.Lot) *lotResponse {
+	items := make([]*getItemResponse, len(lot.Items))
+	for i, item := range lot.Items {
+		items[i] = newGetItemResponse(item)
+	}
+	return &lotResponse{
+		ID:    lot.ID,
+		Name:  lot.Name,
+		Items: items,
+	}
+}
+
+// lotIDFromPath parses the ID of the lot from the path.
+func lotIDFromPath(r *http.Request) (uint64, error) {
+	id, err := strconv.ParseUint(mux.Vars(r)["lot"], 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(errLotIDInvalid, err.Error())
+	}
+	return id, nil
+}
+
+// readLotRequest decodes the body into the request. A body that is not valid JSON is answered with a bad request, in
+// which case false is returned.
+func readLotRequest(w http.ResponseWriter, r *http.Request, request interface{}) (bool, error) {
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false, errors.Wrap(err, "could not read body")
+	}
+	defer r.Body.Close()
+
+	if err = json.Unmarshal(rawBody, request); err != nil {
+		log.Info(r.Context(), "invalid json request", "body", string(rawBody), "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return false, nil
+	}
+	return true, nil
+}
+
+// writeLot responds with the lot and the status.
+func writeLot(w http.ResponseWriter, status int, lot *model.Lot) error {
+	rawLot, err := json.Marshal(newLotResponse(lot))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal lot")
+	}
+
+	w.WriteHeader(status)
+	fmt.Fprint(w, string(rawLot))
+	return nil
+}
+
+// writeLotError responds to a request that could not change a lot because of the client. Any other error is returned so
+// it is handled as a server error.
+func writeLotError(w http.ResponseWriter, err error) error {
+	var message string
+	var status int
+	switch {
+	case errors.Is(err, errLotIDInvalid):
+		status, message = http.StatusNotFound, "lot does not exist"
+	case errors.Is(err, storage.ErrEntityNotFound):
+		status, message = http.StatusNotFound, "lot or item does not exist"
+	case errors.Is(err, storage.ErrEntityAlreadyExists):
+		status, message = http.StatusBadRequest, "lot already exists"
+	case errors.Is(err, storage.ErrLotTooSmall):
+		status, message = http.StatusBadRequest, storage.ErrLotTooSmall.Error()
+	case errors.Is(err, errLotNameRequired):
+		status, message = http.StatusBadRequest, errLotNameRequired.Error()
+	case errors.Is(err, storage.ErrItemInLot):
+		status, message = http.StatusConflict, "item is already sold in another lot"
+	case errors.Is(err, storage.ErrLotHasBids):
+		status, message = http.StatusConflict, "lot cannot change once its items have bids"
+	default:
+		return err
+	}
+
+	return writeErrorMessage(w, status, message)
+}
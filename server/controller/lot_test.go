@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type lotHandlerTestSuite struct {
+	suite.Suite
+
+	client         *http.Client
+	server         *httptest.Server
+	lotMock        *mocks.LotClient
+	transactorMock *mocks.Transactor
+	handler        *LotHandler
+}
+
+func (ts *lotHandlerTestSuite) SetupSuite() {
+	ts.handler = NewLotHandler(ts.lotMock, ts.transactorMock)
+	var router *mux.Router
+	ts.server, router = newTestServer()
+	ts.handler.RegisterRoutes(router)
+	ts.client = &http.Client{}
+}
+
+func (ts *lotHandlerTestSuite) SetupTest() {
+	ts.lotMock = new(mocks.LotClient)
+	ts.transactorMock = new(mocks.Transactor)
+	ts.transactorMock.On("RunInTx", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(
+		func(ctx context.Context, fn func(context.Context, *storage.Clients) error) error {
+			return fn(ctx, &storage.Clients{
+				Lots: ts.lotMock,
+			})
+		},
+	).Maybe()
+	ts.handler.lotClient = ts.lotMock
+	ts.handler.transactor = ts.transactorMock
+}
+
+func (ts *lotHandlerTestSuite) TearDownTest() {
+	ts.lotMock.AssertExpectations(ts.T())
+}
+
+func (ts *lotHandlerTestSuite) TearDownSuite() {
+	ts.server.Close()
+}
+
+func TestLotHandler(t *testing.T) {
+	suite.Run(t, new(lotHandlerTestSuite))
+}
+
+func (ts *lotHandlerTestSuite) TestGetLotsRetrievesAllLots() {
+	getLotsTest := func(permission model.PermissionLevel) {
+		lots := []*model.Lot{{
+			ID:    3,
+			Name:  "Dinner and a Show",
+			Items: []*model.AuctionItem{{ID: 1, Name: "Dinner"}, {ID: 2, Name: "Theater Tickets"}},
+		}}
+		ts.lotMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return(lots, nil).Once()
+
+		r := ts.makeAuthenticatedRequest(http.MethodGet, "", nil, &model.User{
+			Permission: permission,
+		})
+		response, err := ts.client.Do(r)
+		ts.Require().NoError(err)
+
+		defer response.Body.Close()
+		ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+		var returnedLots []lotResponse
+		ts.Require().NoError(json.NewDecoder(response.Body).Decode(&returnedLots))
+		ts.Require().Len(returnedLots, 1)
+		ts.Require().EqualValues("Dinner and a Show", returnedLots[0].Name)
+		ts.Require().Len(returnedLots[0].Items, 2)
+		ts.Require().EqualValues("Theater Tickets", returnedLots[0].Items[1].Name)
+	}
+
+	getLotsTest(model.PermissionLevelAdmin)
+	getLotsTest(model.PermissionLevelBidder)
+}
+
+func (ts *lotHandlerTestSuite) TestGetLot404WhenLotNotFound() {
+	ts.lotMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(nil, storage.ErrEntityNotFound)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "/3", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *lotHandlerTestSuite) TestPostLotStoresNewLot() {
+	ts.lotMock.On(
+		"Create", mock.AnythingOfType("*context.valueCtx"), &model.Lot{Name: "Dinner and a Show"}, []uint64{1, 2},
+	).Return(
+		func(ctx context.Context, lot *model.Lot, itemIDs []uint64) error {
+			lot.ID = 3
+			lot.Items = []*model.AuctionItem{{ID: 1, Name: "Dinner", LotID: 3}, {ID: 2, Name: "Theater Tickets", LotID: 3}}
+			return nil
+		},
+	)
+
+	body := strings.NewReader(`{"name":" Dinner and a Show ","itemIds":[1,2]}`)
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "", body, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	var createdLot lotResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&createdLot))
+	ts.Require().EqualValues(3, createdLot.ID)
+	ts.Require().Len(createdLot.Items, 2)
+	ts.Require().EqualValues(3, createdLot.Items[0].LotID)
+}
+
+func (ts *lotHandlerTestSuite) TestPostLot400WhenTooFewItems() {
+	ts.lotMock.On("Create", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.Lot"), []uint64{1}).Return(
+		errors.Wrap(storage.ErrLotTooSmall, "too small"),
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "", strings.NewReader(`{"name":"Dinner","itemIds":[1]}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *lotHandlerTestSuite) TestPostLot403OnBidderRequest() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "", strings.NewReader(`{"name":"Dinner","itemIds":[1,2]}`), &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *lotHandlerTestSuite) TestPostMergeLotMergesLotsAndItems() {
+	ts.lotMock.On("Merge", mock.AnythingOfType("*context.valueCtx"), uint64(3), []uint64{4}, []uint64{7}).Return(nil)
+	ts.lotMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.Lot{
+		ID:    3,
+		Name:  "Dinner and a Show",
+		Items: []*model.AuctionItem{{ID: 1}, {ID: 2}, {ID: 5}, {ID: 6}, {ID: 7}},
+	}, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/3/merge", strings.NewReader(`{"lotIds":[4],"itemIds":[7]}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var mergedLot lotResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&mergedLot))
+	ts.Require().Len(mergedLot.Items, 5)
+}
+
+func (ts *lotHandlerTestSuite) TestPostMergeLot409WhenItemsHaveBids() {
+	ts.lotMock.On("Merge", mock.AnythingOfType("*context.valueCtx"), uint64(3), []uint64{4}, []uint64(nil)).Return(
+		errors.Wrap(storage.ErrLotHasBids, "bids"),
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/3/merge", strings.NewReader(`{"lotIds":[4]}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusConflict, response.StatusCode)
+}
+
+func (ts *lotHandlerTestSuite) TestPostSplitLotReturnsRemainingLot() {
+	ts.lotMock.On("Split", mock.AnythingOfType("*context.valueCtx"), uint64(3), []uint64{7}).Return(nil)
+	ts.lotMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.Lot{
+		ID:    3,
+		Name:  "Dinner and a Show",
+		Items: []*model.AuctionItem{{ID: 1}, {ID: 2}},
+	}, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/3/split", strings.NewReader(`{"itemIds":[7]}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var splitLot lotResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&splitLot))
+	ts.Require().Len(splitLot.Items, 2)
+}
+
+func (ts *lotHandlerTestSuite) TestPostSplitLot204WhenLotIsRemoved() {
+	ts.lotMock.On("Split", mock.AnythingOfType("*context.valueCtx"), uint64(3), []uint64(nil)).Return(nil)
+	ts.lotMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(nil, storage.ErrEntityNotFound)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/3/split", strings.NewReader(`{}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNoContent, response.StatusCode)
+}
+
+func (ts *lotHandlerTestSuite) makeAuthenticatedRequest(method string, path string, body io.Reader, user *model.User) *http.Request {
+	return makeAuthenticatedRequest(ts.T(), method, ts.server.URL+"/api/v1/auctions/lots"+path, body, user)
+}
@@ -0,0 +1,932 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/drawing"
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/server/controller/auth"
+	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+type (
+	// swagger:model
+	raffleResponse struct {
+		// The ID of the raffle.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The name of the raffle.
+		//
+		// Required: true
+		Name string `json:"name"`
+
+		// The description of the prizes of the raffle.
+		Description string `json:"description,omitempty"`
+
+		// What a single ticket costs as a decimal string followed by the currency, e.g. "5.00 USD".
+		//
+		// Required: true
+		TicketPrice model.Money `json:"ticketPrice"`
+
+		// How many tickets are drawn as winners.
+		//
+		// Required: true
+		Prizes int `json:"prizes"`
+
+		// When the winners were drawn. It is left out until the raffle is drawn.
+		DrawnAt *time.Time `json:"drawnAt,omitempty"`
+
+		// The winning tickets in the order they were drawn. It is left out until the raffle is drawn.
+		Winners []*raffleWinnerResponse `json:"winners,omitempty"`
+	}
+
+	// swagger:model
+	raffleWinnerResponse struct {
+		// The number of the winning ticket. Tickets are numbered from 1 in the order they were bought.
+		//
+		// Required: true
+		Ticket int `json:"ticket"`
+
+		// The username of the buyer of the ticket.
+		//
+		// Required: true
+		Username string `json:"username"`
+
+		// The display name of the buyer of the ticket.
+		DisplayName string `json:"displayName,omitempty"`
+	}
+
+	// swagger:model
+	ticketPurchaseResponse struct {
+		// The ID of the purchase.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The ID of the raffle the tickets are for.
+		//
+		// Required: true
+		RaffleID uint64 `json:"raffleId"`
+
+		// The user who bought the tickets.
+		//
+		// Required: true
+		Buyer *userResponse `json:"buyer"`
+
+		// How many tickets were bought.
+		//
+		// Required: true
+		Quantity int `json:"quantity"`
+
+		// What a single ticket cost when the tickets were bought as a decimal string followed by the currency, e.g.
+		// "5.00 USD".
+		//
+		// Required: true
+		Price model.Money `json:"price"`
+
+		// What the buyer owes for the tickets as a decimal string followed by the currency, e.g. "15.00 USD".
+		//
+		// Required: true
+		Total model.Money `json:"total"`
+
+		// The username of whoever recorded the purchase.
+		//
+		// Required: true
+		RecordedBy string `json:"recordedBy"`
+	}
+
+	// swagger:model
+	raffleDrawingResponse struct {
+		// The random seed the winners were drawn with, encoded as hex. Every winner is derived from the seed so the
+		// drawing can be repeated with the seed and the tickets.
+		//
+		// Required: true
+		Seed string `json:"seed"`
+
+		// The username of the buyer of every ticket. The number of a ticket is its index plus 1.
+		//
+		// Required: true
+		Tickets []string `json:"tickets"`
+
+		// The winning tickets in the order they were drawn.
+		//
+		// Required: true
+		Winners []*raffleWinnerResponse `json:"winners"`
+
+		// When the winners were drawn.
+		//
+		// Required: true
+		DrawnAt time.Time `json:"drawnAt"`
+
+		// Whether repeating the drawing with the seed and tickets draws the same winners.
+		//
+		// Required: true
+		Verified bool `json:"verified"`
+	}
+
+	postRaffleRequest struct {
+		// Name of the raffle. It must be unique, ignoring case.
+		//
+		// Required: true
+		Name string `json:"name"`
+
+		// The description of the prizes of the raffle.
+		Description string `json:"description,omitempty"`
+
+		// What a single ticket costs as a decimal string followed by the currency of the auction, e.g. "5.00 USD". It
+		// must be more than 0.
+		//
+		// Required: true
+		TicketPrice model.Money `json:"ticketPrice"`
+
+		// How many tickets are drawn as winners. It defaults to 1.
+		Prizes int `json:"prizes,omitempty"`
+	}
+
+	postTicketsRequest struct {
+		// How many tickets to buy. It must be more than 0.
+		//
+		// Required: true
+		Quantity int `json:"quantity"`
+
+		// Username of the user who buys the tickets. Admins must give it since they sell tickets on behalf of users.
+		// Bidders can only buy tickets for themselves and may leave it out.
+		Username string `json:"username,omitempty"`
+	}
+)
+
+// RaffleHandler provides handlers for endpoints involving model.Raffles and their tickets.
+type RaffleHandler struct {
+	raffleClient storage.RaffleClient
+	transactor   storage.Transactor
+	eventBus     *events.Bus
+	currency     string
+}
+
+// NewRaffleHandler creates a new RaffleHandler with the necessary storage objects. The winners of every drawing are
+// published on the eventBus. Every ticket price must be in the currency of the event.
+func NewRaffleHandler(
+	raffleClient storage.RaffleClient,
+	transactor storage.Transactor,
+	eventBus *events.Bus,
+	currency string,
+) *RaffleHandler {
+	return &RaffleHandler{
+		raffleClient: raffleClient,
+		transactor:   transactor,
+		eventBus:     eventBus,
+		currency:     currency,
+	}
+}
+
+// RegisterRoutes registers all of the paths to the handler functions.
+func (handler *RaffleHandler) RegisterRoutes(router *mux.Router) {
+	rafflesRouter := router.PathPrefix("/v1/raffles").Subrouter()
+	rafflesRouter.Use(middleware.VerifyAuthToken)
+
+	rafflesAdmin := rafflesRouter.NewRoute().Subrouter()
+	rafflesAdmin.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin))
+	rafflesAdmin.HandleFunc("", wrapHandler(handler.PostRaffle)).Methods(http.MethodPost)
+	rafflesAdmin.HandleFunc("/{raffle}/tickets", wrapHandler(handler.GetTickets)).Methods(http.MethodGet)
+	rafflesAdmin.HandleFunc("/{raffle}/drawing", wrapHandler(handler.PostDrawing)).Methods(http.MethodPost)
+	rafflesAdmin.HandleFunc("/{raffle}/drawing", wrapHandler(handler.GetDrawing)).Methods(http.MethodGet)
+
+	rafflesBoth := rafflesRouter.NewRoute().Subrouter()
+	rafflesBoth.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin, model.PermissionLevelBidder))
+	rafflesBoth.HandleFunc("", wrapHandler(handler.GetRaffles)).Methods(http.MethodGet)
+	rafflesBoth.HandleFunc("/{raffle}", wrapHandler(handler.GetRaffle)).Methods(http.MethodGet)
+	rafflesBoth.HandleFunc("/{raffle}/tickets", wrapHandler(handler.PostTickets)).Methods(http.MethodPost)
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getRafflesRequestDoc is for swagger generation only.
+// swagger:parameters getRafflesRequest
+type getRafflesRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// Contains every raffle in the order they were created.
+//
+// swagger:response getRafflesResponse
+type getRafflesResponseDoc struct {
+
+	// In: body
+	Body []raffleResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetRaffles is the handler that retrieves all model.Raffles as serialized JSON.
+//
+// swagger:route GET /api/v1/raffles Raffles getRafflesRequest
+//
+// Gets all raffles.
+//
+// This will retrieve every raffle in the order they were created along with the winners of the raffles that were
+// drawn.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getRafflesResponse
+func (handler *RaffleHandler) GetRaffles(w http.ResponseWriter, r *http.Request) error {
+	raffles, err := handler.raffleClient.GetAll(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve raffles")
+	}
+
+	responseObjects := make([]*raffleResponse, len(raffles))
+	for i, raffle := range raffles {
+		responseObjects[i] = newRaffleResponse(raffle)
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal raffles")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getRaffleRequestDoc is for swagger generation only.
+// swagger:parameters getRaffleRequest
+type getRaffleRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the raffle.
+	//
+	// In: path
+	Raffle string `json:"raffle"`
+}
+
+// Contains the raffle.
+//
+// swagger:response getRaffleResponse
+type getRaffleResponseDoc struct {
+
+	// In: body
+	Body raffleResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetRaffle is the handler that retrieves a single model.Raffle as serialized JSON.
+//
+// swagger:route GET /api/v1/raffles/{raffle} Raffles getRaffleRequest
+//
+// Gets a raffle.
+//
+// This will retrieve the raffle along with its winners once it is drawn.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getRaffleResponse
+//    404: errorMessage
+func (handler *RaffleHandler) GetRaffle(w http.ResponseWriter, r *http.Request) error {
+	id, err := raffleIDFromPath(r)
+	if err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not retrieve raffle")
+	}
+
+	raffle, err := handler.raffleClient.GetByID(r.Context(), id)
+	if err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not retrieve raffle")
+	}
+
+	rawRaffle, err := json.Marshal(newRaffleResponse(raffle))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal raffle")
+	}
+
+	fmt.Fprint(w, string(rawRaffle))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// postRaffleRequestDoc is for swagger generation only.
+// swagger:parameters postRaffleRequest
+type postRaffleRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// In: body
+	Body postRaffleRequest
+}
+
+// Contains the raffle that was created.
+//
+// swagger:response postRaffleResponse
+type postRaffleResponseDoc struct {
+
+	// In: body
+	Body raffleResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PostRaffle is the handler that creates a new model.Raffle.
+//
+// swagger:route POST /api/v1/raffles Raffles postRaffleRequest
+//
+// Creates a new raffle.
+//
+// This will create a raffle that tickets can be bought for until it is drawn. This route is only available to Admin
+// users.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    201: postRaffleResponse
+//    400: errorMessage
+func (handler *RaffleHandler) PostRaffle(w http.ResponseWriter, r *http.Request) error {
+	var request postRaffleRequest
+	if ok, err := readRaffleRequest(w, r, &request); !ok {
+		return err
+	}
+
+	raffle := &model.Raffle{
+		Name:        strings.TrimSpace(request.Name),
+		Description: strings.TrimSpace(request.Description),
+		TicketPrice: request.TicketPrice,
+		Prizes:      request.Prizes,
+	}
+	if raffle.Prizes == 0 {
+		raffle.Prizes = 1
+	}
+	if raffle.Name == "" {
+		return errors.Wrap(writeRaffleError(w, errRaffleNameRequired), "could not create raffle")
+	}
+	if raffle.TicketPrice.Amount <= 0 {
+		return errors.Wrap(writeRaffleError(w, errTicketPriceInvalid), "could not create raffle")
+	}
+	if raffle.TicketPrice.Currency != handler.currency {
+		message := fmt.Sprintf("%s, which is %s", errTicketPriceCurrency.Error(), handler.currency)
+		return errors.Wrap(writeErrorMessage(w, http.StatusBadRequest, message), "could not create raffle")
+	}
+	if raffle.Prizes < 0 {
+		return errors.Wrap(writeRaffleError(w, errRafflePrizesInvalid), "could not create raffle")
+	}
+
+	if err := handler.raffleClient.Create(r.Context(), raffle); err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not store raffle")
+	}
+
+	rawRaffle, err := json.Marshal(newRaffleResponse(raffle))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal raffle")
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprint(w, string(rawRaffle))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getTicketsRequestDoc is for swagger generation only.
+// swagger:parameters getTicketsRequest
+type getTicketsRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the raffle.
+	//
+	// In: path
+	Raffle string `json:"raffle"`
+}
+
+// Contains every ticket purchase of the raffle in the order they were bought.
+//
+// swagger:response getTicketsResponse
+type getTicketsResponseDoc struct {
+
+	// In: body
+	Body []ticketPurchaseResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetTickets is the handler that retrieves all model.TicketPurchases of a model.Raffle as serialized JSON.
+//
+// swagger:route GET /api/v1/raffles/{raffle}/tickets Raffles getTicketsRequest
+//
+// Gets the tickets of a raffle.
+//
+// This will retrieve every ticket purchase of the raffle in the order they were bought, including the purchases of
+// deleted users. This route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getTicketsResponse
+//    404: errorMessage
+func (handler *RaffleHandler) GetTickets(w http.ResponseWriter, r *http.Request) error {
+	id, err := raffleIDFromPath(r)
+	if err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not retrieve tickets")
+	}
+
+	purchases, err := handler.raffleClient.GetTickets(r.Context(), id)
+	if err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not retrieve tickets")
+	}
+
+	responseObjects := make([]*ticketPurchaseResponse, len(purchases))
+	for i, purchase := range purchases {
+		responseObjects[i] = newTicketPurchaseResponse(purchase)
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal tickets")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// postTicketsRequestDoc is for swagger generation only.
+// swagger:parameters postTicketsRequest
+type postTicketsRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the raffle.
+	//
+	// In: path
+	Raffle string `json:"raffle"`
+
+	// In: body
+	Body postTicketsRequest
+}
+
+// Contains the tickets that were bought.
+//
+// swagger:response postTicketsResponse
+type postTicketsResponseDoc struct {
+
+	// In: body
+	Body ticketPurchaseResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PostTickets is the handler that records a new model.TicketPurchase.
+//
+// swagger:route POST /api/v1/raffles/{raffle}/tickets Raffles postTicketsRequest
+//
+// Buys raffle tickets.
+//
+// This will record tickets bought at the current ticket price of the raffle. Bidders buy tickets for themselves while
+// admins record the tickets sold to users at the event. Tickets cannot be bought once the raffle is drawn.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    201: postTicketsResponse
+//    400: errorMessage
+//    403: errorMessage
+//    404: errorMessage
+//    409: errorMessage
+func (handler *RaffleHandler) PostTickets(w http.ResponseWriter, r *http.Request) error {
+	id, err := raffleIDFromPath(r)
+	if err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not buy tickets")
+	}
+
+	var request postTicketsRequest
+	if ok, err := readRaffleRequest(w, r, &request); !ok {
+		return err
+	}
+	if request.Quantity <= 0 {
+		return errors.Wrap(writeRaffleError(w, errTicketQuantityInvalid), "could not buy tickets")
+	}
+
+	recordedBy := auth.ExtractUsername(r.Context())
+	username := strings.TrimSpace(request.Username)
+	if auth.ExtractPermission(r.Context()) == model.PermissionLevelAdmin {
+		if username == "" {
+			return errors.Wrap(writeRaffleError(w, errTicketBuyerRequired), "could not buy tickets")
+		}
+	} else if username == "" {
+		username = recordedBy
+	} else if username != recordedBy {
+		return errors.Wrap(writeRaffleError(w, errTicketsForOtherUser), "could not buy tickets")
+	}
+
+	purchase := &model.TicketPurchase{
+		RaffleID:   id,
+		Buyer:      &model.User{Username: username},
+		Quantity:   request.Quantity,
+		RecordedBy: recordedBy,
+	}
+	err = handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		return clients.Raffles.BuyTickets(ctx, purchase)
+	})
+	if err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not store tickets")
+	}
+
+	rawPurchase, err := json.Marshal(newTicketPurchaseResponse(purchase))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal tickets")
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprint(w, string(rawPurchase))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// postDrawingRequestDoc is for swagger generation only.
+// swagger:parameters postDrawingRequest
+type postDrawingRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the raffle.
+	//
+	// In: path
+	Raffle string `json:"raffle"`
+}
+
+// Contains the drawing along with everything needed to audit it.
+//
+// swagger:response raffleDrawingResponse
+type raffleDrawingResponseDoc struct {
+
+	// In: body
+	Body raffleDrawingResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PostDrawing is the handler that draws the winning tickets of a model.Raffle.
+//
+// swagger:route POST /api/v1/raffles/{raffle}/drawing Raffles postDrawingRequest
+//
+// Draws the winners of a raffle.
+//
+// This will end ticket sales and draw as many winning tickets as the raffle has prizes. A ticket can only win once. The
+// random seed is read from crypto/rand and is recorded with the list of tickets so the drawing can be audited. The
+// winners are announced to every client with the RaffleDrawn websocket message. A raffle can only be drawn once. This
+// route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    201: raffleDrawingResponse
+//    404: errorMessage
+//    409: errorMessage
+func (handler *RaffleHandler) PostDrawing(w http.ResponseWriter, r *http.Request) error {
+	id, err := raffleIDFromPath(r)
+	if err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not draw raffle")
+	}
+
+	var raffle *model.Raffle
+	var purchases []*model.TicketPurchase
+	err = handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		var err error
+		if raffle, err = clients.Raffles.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if raffle.Drawing != nil {
+			return errors.Wrapf(storage.ErrRaffleDrawn, "unable to draw raffle %d", id)
+		}
+		if purchases, err = clients.Raffles.GetTickets(ctx, id); err != nil {
+			return err
+		}
+
+		if raffle.Drawing, err = drawing.New(purchases, raffle.Prizes); err != nil {
+			return err
+		}
+		return clients.Raffles.RecordDrawing(ctx, id, raffle.Drawing)
+	})
+	if err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not draw raffle")
+	}
+
+	winners := newRaffleWinnerResponses(raffle.Drawing, purchases)
+	drawnEvent := &events.RaffleDrawn{
+		RaffleID:   raffle.ID,
+		RaffleName: raffle.Name,
+		Winners:    make([]events.RaffleWinner, len(winners)),
+	}
+	for i, winner := range winners {
+		drawnEvent.Winners[i] = events.RaffleWinner(*winner)
+	}
+	handler.eventBus.Publish(events.TypeRaffleDrawn, drawnEvent)
+
+	rawDrawing, err := json.Marshal(newRaffleDrawingResponse(raffle.Drawing, winners))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal drawing")
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprint(w, string(rawDrawing))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getDrawingRequestDoc is for swagger generation only.
+// swagger:parameters getDrawingRequest
+type getDrawingRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// ID of the raffle.
+	//
+	// In: path
+	Raffle string `json:"raffle"`
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetDrawing is the handler that retrieves the model.RaffleDrawing of a model.Raffle for auditing.
+//
+// swagger:route GET /api/v1/raffles/{raffle}/drawing Raffles getDrawingRequest
+//
+// Gets the drawing of a raffle.
+//
+// This will retrieve the seed and tickets the winners were drawn from and repeat the drawing to verify the winners.
+// This route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: raffleDrawingResponse
+//    404: errorMessage
+func (handler *RaffleHandler) GetDrawing(w http.ResponseWriter, r *http.Request) error {
+	id, err := raffleIDFromPath(r)
+	if err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not retrieve drawing")
+	}
+
+	var raffle *model.Raffle
+	var purchases []*model.TicketPurchase
+	err = handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		var err error
+		if raffle, err = clients.Raffles.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if raffle.Drawing == nil {
+			return errRaffleNotDrawn
+		}
+		purchases, err = clients.Raffles.GetTickets(ctx, id)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(writeRaffleError(w, err), "could not retrieve drawing")
+	}
+
+	response := newRaffleDrawingResponse(raffle.Drawing, newRaffleWinnerResponses(raffle.Drawing, purchases))
+	if err = drawing.Verify(raffle.Drawing); err != nil {
+		log.Error(r.Context(), "raffle drawing cannot be verified", "raffle", id, "err", err)
+		response.Verified = false
+	}
+
+	rawDrawing, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal drawing")
+	}
+
+	fmt.Fprint(w, string(rawDrawing))
+	return nil
+}
+
+var (
+	errRaffleIDInvalid       = errors.New("raffle is not an ID")
+	errRaffleNameRequired    = errors.New("name of the raffle is required")
+	errTicketPriceInvalid    = errors.New("ticket price must be more than 0")
+	errTicketPriceFormat     = errors.New("ticket price must be a string such as \"5.00 USD\"")
+	errTicketPriceCurrency   = errors.New("ticket price is not in the currency of the auction")
+	errRafflePrizesInvalid   = errors.New("prizes of the raffle cannot be negative")
+	errTicketQuantityInvalid = errors.New("quantity of tickets must be more than 0")
+	errTicketBuyerRequired   = errors.New("username of the user who buys the tickets is required")
+	errTicketsForOtherUser   = errors.New("bidders can only buy tickets for themselves")
+	errRaffleNotDrawn        = errors.New("raffle was not drawn yet")
+)
+
+// newRaffleResponse converts the raffle to the response sent to clients. The winners are only listed by ticket and
+// username.
+func newRaffleResponse(raffle *model.Raffle) *raffleResponse {
+	response := &raffleResponse{
+		ID:          raffle.ID,
+		Name:        raffle.Name,
+		Description: raffle.Description,
+		TicketPrice: raffle.TicketPrice,
+		Prizes:      raffle.Prizes,
+	}
+	if raffle.Drawing != nil {
+		drawnAt := raffle.Drawing.DrawnAt
+		response.DrawnAt = &drawnAt
+		response.Winners = newRaffleWinnerResponses(raffle.Drawing, nil)
+	}
+	return response
+}
+
+// newRaffleWinnerResponses looks up the buyer of every winning ticket. The display names of the buyers are taken from
+// the purchases when they are given.
+func newRaffleWinnerResponses(raffleDrawing *model.RaffleDrawing, purchases []*model.TicketPurchase) []*raffleWinnerResponse {
+	displayNames := make(map[string]string, len(purchases))
+	for _, purchase := range purchases {
+		displayNames[purchase.Buyer.Username] = purchase.Buyer.DisplayName
+	}
+
+	winners := make([]*raffleWinnerResponse, 0, len(raffleDrawing.Winners))
+	for _, ticket := range raffleDrawing.Winners {
+		if ticket < 1 || ticket > len(raffleDrawing.Tickets) {
+			continue
+		}
+		username := raffleDrawing.Tickets[ticket-1]
+		winners = append(winners, &raffleWinnerResponse{
+			Ticket:      ticket,
+			Username:    username,
+			DisplayName: displayNames[username],
+		})
+	}
+	return winners
+}
+
+// newRaffleDrawingResponse converts the drawing to the response sent to admins. The drawing is assumed to be verified.
+func newRaffleDrawingResponse(raffleDrawing *model.RaffleDrawing, winners []*raffleWinnerResponse) *raffleDrawingResponse {
+	return &raffleDrawingResponse{
+		Seed:     raffleDrawing.Seed,
+		Tickets:  raffleDrawing.Tickets,
+		Winners:  winners,
+		DrawnAt:  raffleDrawing.DrawnAt,
+		Verified: true,
+	}
+}
+
+// newTicketPurchaseResponse converts the purchase to the response sent to clients.
+func newTicketPurchaseResponse(purchase *model.TicketPurchase) *ticketPurchaseResponse {
+	return &ticketPurchaseResponse{
+		ID:       purchase.ID,
+		RaffleID: purchase.RaffleID,
+		Buyer: &userResponse{
+			Username:    purchase.Buyer.Username,
+			DisplayName: purchase.Buyer.DisplayName,
+		},
+		Quantity:   purchase.Quantity,
+		Price:      purchase.Price,
+		Total:      purchase.Price.Times(purchase.Quantity),
+		RecordedBy: purchase.RecordedBy,
+	}
+}
+
+func raffleIDFromPath(r *http.Request) (uint64, error) {
+	id, err := strconv.ParseUint(mux.Vars(r)["raffle"], 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(errRaffleIDInvalid, err.Error())
+	}
+	return id, nil
+}
+
+// readRaffleRequest decodes the body into the request. A body that is not valid JSON is answered with a bad request, in
+// which case false is returned.
+func readRaffleRequest(w http.ResponseWriter, r *http.Request, request interface{}) (bool, error) {
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false, errors.Wrap(err, "could not read body")
+	}
+	defer r.Body.Close()
+
+	if err = json.Unmarshal(rawBody, request); err != nil {
+		log.Info(r.Context(), "invalid json request", "body", string(rawBody), "err", err)
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrUnknownCurrency) {
+			return false, writeRaffleError(w, errTicketPriceFormat)
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		return false, nil
+	}
+	return true, nil
+}
+
+// writeRaffleError responds to a request that could not use a raffle because of the client. Any other error is
+// returned so it is handled as a server error.
+func writeRaffleError(w http.ResponseWriter, err error) error {
+	var message string
+	var status int
+	switch {
+	case errors.Is(err, errRaffleIDInvalid):
+		status, message = http.StatusNotFound, "raffle does not exist"
+	case errors.Is(err, storage.ErrEntityNotFound):
+		status, message = http.StatusNotFound, "raffle or user does not exist"
+	case errors.Is(err, storage.ErrEntityAlreadyExists):
+		status, message = http.StatusBadRequest, "raffle already exists"
+	case errors.Is(err, storage.ErrRaffleDrawn):
+		status, message = http.StatusConflict, "raffle was already drawn"
+	case errors.Is(err, drawing.ErrNoTickets):
+		status, message = http.StatusConflict, "raffle cannot be drawn until tickets are sold"
+	case errors.Is(err, errRaffleNotDrawn):
+		status, message = http.StatusNotFound, errRaffleNotDrawn.Error()
+	case errors.Is(err, errRaffleNameRequired):
+		status, message = http.StatusBadRequest, errRaffleNameRequired.Error()
+	case errors.Is(err, errTicketPriceInvalid):
+		status, message = http.StatusBadRequest, errTicketPriceInvalid.Error()
+	case errors.Is(err, errTicketPriceFormat):
+		status, message = http.StatusBadRequest, errTicketPriceFormat.Error()
+	case errors.Is(err, errRafflePrizesInvalid):
+		status, message = http.StatusBadRequest, errRafflePrizesInvalid.Error()
+	case errors.Is(err, errTicketQuantityInvalid):
+		status, message = http.StatusBadRequest, errTicketQuantityInvalid.Error()
+	case errors.Is(err, errTicketBuyerRequired):
+		status, message = http.StatusBadRequest, errTicketBuyerRequired.Error()
+	case errors.Is(err, errTicketsForOtherUser):
+		status, message = http.StatusForbidden, errTicketsForOtherUser.Error()
+	default:
+		return err
+	}
+
+	return writeErrorMessage(w, status, message)
+}
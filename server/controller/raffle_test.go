@@ -0,0 +1,326 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/drawing"
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type raffleHandlerTestSuite struct {
+	suite.Suite
+
+	client         *http.Client
+	server         *httptest.Server
+	raffleMock     *mocks.RaffleClient
+	transactorMock *mocks.Transactor
+	eventBus       *events.Bus
+	handler        *RaffleHandler
+}
+
+func (ts *raffleHandlerTestSuite) SetupSuite() {
+	ts.handler = NewRaffleHandler(ts.raffleMock, ts.transactorMock, ts.eventBus, "USD")
+	var router *mux.Router
+	ts.server, router = newTestServer()
+	ts.handler.RegisterRoutes(router)
+	ts.client = &http.Client{}
+}
+
+func (ts *raffleHandlerTestSuite) SetupTest() {
+	ts.raffleMock = new(mocks.RaffleClient)
+	ts.transactorMock = new(mocks.Transactor)
+	ts.transactorMock.On("RunInTx", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(
+		func(ctx context.Context, fn func(context.Context, *storage.Clients) error) error {
+			return fn(ctx, &storage.Clients{
+				Raffles: ts.raffleMock,
+			})
+		},
+	).Maybe()
+	ts.eventBus = events.NewBus(events.DefaultHistorySize)
+	ts.handler.raffleClient = ts.raffleMock
+	ts.handler.transactor = ts.transactorMock
+	ts.handler.eventBus = ts.eventBus
+}
+
+func (ts *raffleHandlerTestSuite) TearDownTest() {
+	ts.raffleMock.AssertExpectations(ts.T())
+}
+
+func (ts *raffleHandlerTestSuite) TearDownSuite() {
+	ts.server.Close()
+}
+
+func TestRaffleHandler(t *testing.T) {
+	suite.Run(t, new(raffleHandlerTestSuite))
+}
+
+func (ts *raffleHandlerTestSuite) TestGetRafflesListsWinnersOfDrawnRaffles() {
+	raffles := []*model.Raffle{
+		{ID: 1, Name: "Quilt", TicketPrice: usd(500), Prizes: 1, Drawing: &model.RaffleDrawing{
+			Seed:    "8f14",
+			Tickets: []string{"alice", "bob"},
+			Winners: []int{2},
+			DrawnAt: time.Date(2021, 10, 2, 20, 0, 0, 0, time.UTC),
+		}},
+		{ID: 2, Name: "Wine Basket", TicketPrice: usd(1000), Prizes: 2},
+	}
+	ts.raffleMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return(raffles, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var returnedRaffles []*raffleResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&returnedRaffles))
+	ts.Require().Len(returnedRaffles, 2)
+	ts.Require().Equal([]*raffleWinnerResponse{{Ticket: 2, Username: "bob"}}, returnedRaffles[0].Winners)
+	ts.Require().NotNil(returnedRaffles[0].DrawnAt)
+	ts.Require().Nil(returnedRaffles[1].DrawnAt)
+	ts.Require().Empty(returnedRaffles[1].Winners)
+}
+
+func (ts *raffleHandlerTestSuite) TestPostRaffleDefaultsToOnePrize() {
+	ts.raffleMock.On("Create", mock.AnythingOfType("*context.valueCtx"), &model.Raffle{
+		Name:        "Quilt",
+		Description: "Hand made",
+		TicketPrice: usd(500),
+		Prizes:      1,
+	}).Return(
+		func(ctx context.Context, raffle *model.Raffle) error {
+			raffle.ID = 3
+			return nil
+		},
+	)
+
+	body := strings.NewReader(`{"name":" Quilt ","description":"Hand made","ticketPrice":"5.00 USD"}`)
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "", body, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	var raffle raffleResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&raffle))
+	ts.Require().Equal(raffleResponse{
+		ID:          3,
+		Name:        "Quilt",
+		Description: "Hand made",
+		TicketPrice: usd(500),
+		Prizes:      1,
+	}, raffle)
+}
+
+func (ts *raffleHandlerTestSuite) TestPostRaffle400OnInvalidTicketPrice() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "", strings.NewReader(`{"name":"Quilt","ticketPrice":"0.00 USD"}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *raffleHandlerTestSuite) TestPostTicketsRecordsPurchaseOfBidder() {
+	ts.raffleMock.On("BuyTickets", mock.AnythingOfType("*context.valueCtx"), &model.TicketPurchase{
+		RaffleID:   3,
+		Buyer:      &model.User{Username: "bidder"},
+		Quantity:   4,
+		RecordedBy: "bidder",
+	}).Return(
+		func(ctx context.Context, purchase *model.TicketPurchase) error {
+			purchase.ID = 9
+			purchase.Price = usd(500)
+			return nil
+		},
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/3/tickets", strings.NewReader(`{"quantity":4}`), &model.User{
+		Username:   "bidder",
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	var purchase ticketPurchaseResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&purchase))
+	ts.Require().EqualValues(9, purchase.ID)
+	ts.Require().Equal(usd(500), purchase.Price)
+	ts.Require().Equal(usd(2000), purchase.Total)
+	ts.Require().EqualValues("bidder", purchase.Buyer.Username)
+}
+
+func (ts *raffleHandlerTestSuite) TestPostTickets403WhenBidderBuysForOtherUser() {
+	body := strings.NewReader(`{"quantity":1,"username":"other"}`)
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/3/tickets", body, &model.User{
+		Username:   "bidder",
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *raffleHandlerTestSuite) TestPostTickets409WhenRaffleDrawn() {
+	ts.raffleMock.On("BuyTickets", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(
+		errors.Wrap(storage.ErrRaffleDrawn, "drawn"),
+	)
+
+	body := strings.NewReader(`{"quantity":1,"username":"bidder"}`)
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/3/tickets", body, &model.User{
+		Username:   "admin",
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusConflict, response.StatusCode)
+}
+
+func (ts *raffleHandlerTestSuite) TestPostDrawingRecordsAndAnnouncesWinners() {
+	ts.raffleMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.Raffle{
+		ID:          3,
+		Name:        "Quilt",
+		TicketPrice: usd(500),
+		Prizes:      2,
+	}, nil)
+	ts.raffleMock.On("GetTickets", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return([]*model.TicketPurchase{
+		{ID: 1, RaffleID: 3, Buyer: &model.User{Username: "alice", DisplayName: "Alice"}, Quantity: 2, Price: usd(500)},
+		{ID: 2, RaffleID: 3, Buyer: &model.User{Username: "bob", DisplayName: "Bob"}, Quantity: 1, Price: usd(500)},
+	}, nil)
+	var recorded *model.RaffleDrawing
+	ts.raffleMock.On(
+		"RecordDrawing", mock.AnythingOfType("*context.valueCtx"), uint64(3), mock.AnythingOfType("*model.RaffleDrawing"),
+	).Return(
+		func(ctx context.Context, raffleID uint64, raffleDrawing *model.RaffleDrawing) error {
+			recorded = raffleDrawing
+			return nil
+		},
+	)
+	sub := ts.eventBus.Subscribe()
+	defer sub.Close()
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/3/drawing", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	var returnedDrawing raffleDrawingResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&returnedDrawing))
+	ts.Require().NotNil(recorded)
+	ts.Require().Equal([]string{"alice", "alice", "bob"}, recorded.Tickets)
+	ts.Require().Len(recorded.Winners, 2)
+	ts.Require().NoError(drawing.Verify(recorded))
+	ts.Require().EqualValues(recorded.Seed, returnedDrawing.Seed)
+	ts.Require().True(returnedDrawing.Verified)
+
+	event := <-sub.Events()
+	ts.Require().IsType(&events.RaffleDrawn{}, event.Payload)
+	drawn := event.Payload.(*events.RaffleDrawn)
+	ts.Require().EqualValues("Quilt", drawn.RaffleName)
+	ts.Require().Len(drawn.Winners, 2)
+	for i, winner := range drawn.Winners {
+		ts.Require().EqualValues(recorded.Winners[i], winner.Ticket)
+		ts.Require().EqualValues(recorded.Tickets[winner.Ticket-1], winner.Username)
+		ts.Require().NotEmpty(winner.DisplayName)
+	}
+}
+
+func (ts *raffleHandlerTestSuite) TestPostDrawing409WhenNoTicketsSold() {
+	ts.raffleMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.Raffle{
+		ID:     3,
+		Name:   "Quilt",
+		Prizes: 1,
+	}, nil)
+	ts.raffleMock.On("GetTickets", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(
+		[]*model.TicketPurchase{}, nil,
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/3/drawing", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusConflict, response.StatusCode)
+}
+
+func (ts *raffleHandlerTestSuite) TestPostDrawing403OnBidderRequest() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "/3/drawing", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *raffleHandlerTestSuite) TestGetDrawingDetectsTamperedWinners() {
+	winners, err := drawing.Draw("8f14e45fceea167a", 3, 1)
+	ts.Require().NoError(err)
+	tampered := winners[0]%3 + 1
+	ts.raffleMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.Raffle{
+		ID:     3,
+		Name:   "Quilt",
+		Prizes: 1,
+		Drawing: &model.RaffleDrawing{
+			Seed:    "8f14e45fceea167a",
+			Tickets: []string{"alice", "alice", "bob"},
+			Winners: []int{tampered},
+		},
+	}, nil)
+	ts.raffleMock.On("GetTickets", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(
+		[]*model.TicketPurchase{}, nil,
+	)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "/3/drawing", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var returnedDrawing raffleDrawingResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&returnedDrawing))
+	ts.Require().False(returnedDrawing.Verified)
+	ts.Require().EqualValues(tampered, returnedDrawing.Winners[0].Ticket)
+}
+
+func (ts *raffleHandlerTestSuite) TestGetDrawing404WhenNotDrawn() {
+	ts.raffleMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), uint64(3)).Return(&model.Raffle{
+		ID:   3,
+		Name: "Quilt",
+	}, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "/3/drawing", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *raffleHandlerTestSuite) makeAuthenticatedRequest(method string, path string, body io.Reader, user *model.User) *http.Request {
+	return makeAuthenticatedRequest(ts.T(), method, ts.server.URL+"/api/v1/raffles"+path, body, user)
+}
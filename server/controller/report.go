@@ -0,0 +1,464 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+type (
+	// swagger:model
+	donorReportResponse struct {
+		// The name of the person who donated the items.
+		DonorName string `json:"donorName,omitempty"`
+
+		// The business that donated the items.
+		DonorBusiness string `json:"donorBusiness,omitempty"`
+
+		// The items that were donated in the order they were created.
+		//
+		// Required: true
+		Items []*donatedItemResponse `json:"items"`
+
+		// The sum of what the donor stated the items are worth.
+		//
+		// Required: true
+		TotalFairMarketValue model.Money `json:"totalFairMarketValue"`
+
+		// The sum of the winning bids on the items.
+		//
+		// Required: true
+		TotalRaised model.Money `json:"totalRaised"`
+	}
+
+	// swagger:model
+	donatedItemResponse struct {
+		// The ID of the item.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The name of the item.
+		//
+		// Required: true
+		Name string `json:"name"`
+
+		// What the donor stated the item is worth. It is 0 when the donor did not state a value.
+		//
+		// Required: true
+		FairMarketValue model.Money `json:"fairMarketValue"`
+
+		// The sum of the winning bids on the item, which is its highest bid unless several bidders win it. It is 0 when
+		// there are no bids.
+		//
+		// Required: true
+		HighestBid model.Money `json:"highestBid"`
+	}
+
+	// swagger:model
+	bidderReportResponse struct {
+		// The username of the bidder.
+		//
+		// Required: true
+		Username string `json:"username"`
+
+		// The name of the bidder shown to other users.
+		//
+		// Required: true
+		DisplayName string `json:"displayName"`
+
+		// The items the bidder won in the order of their IDs.
+		//
+		// Required: true
+		Items []*wonItemResponse `json:"items"`
+
+		// The pledges the bidder made in the order they were recorded.
+		//
+		// Required: true
+		Pledges []*bidderPledgeResponse `json:"pledges"`
+
+		// The sum of the winning bids of the bidder.
+		//
+		// Required: true
+		TotalWon model.Money `json:"totalWon"`
+
+		// The sum of the pledges of the bidder.
+		//
+		// Required: true
+		TotalPledged model.Money `json:"totalPledged"`
+
+		// What the bidder owes for their items and pledges.
+		//
+		// Required: true
+		TotalOwed model.Money `json:"totalOwed"`
+	}
+
+	// swagger:model
+	wonItemResponse struct {
+		// The ID of the item. A lot is won through its first item.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The name of the item.
+		//
+		// Required: true
+		Name string `json:"name"`
+
+		// The ID of the lot the item is sold in, if any.
+		LotID uint64 `json:"lotId,omitempty"`
+
+		// The winning bid of the bidder.
+		//
+		// Required: true
+		Amount model.Money `json:"amount"`
+	}
+
+	// swagger:model
+	bidderPledgeResponse struct {
+		// The ID of the pledge.
+		//
+		// Required: true
+		ID uint64 `json:"id"`
+
+		// The name of the appeal level of the pledge.
+		//
+		// Required: true
+		Level string `json:"level"`
+
+		// The amount of the pledge.
+		//
+		// Required: true
+		Amount model.Money `json:"amount"`
+	}
+)
+
+// ReportHandler provides handlers for endpoints that summarize the auction.
+type ReportHandler struct {
+	itemClient     storage.AuctionItemClient
+	bidClient      storage.AuctionBidClient
+	donationClient storage.DonationClient
+	currency       string
+}
+
+// NewReportHandler creates a new ReportHandler with the necessary storage objects. Totals are in the currency of the
+// event.
+func NewReportHandler(
+	itemClient storage.AuctionItemClient,
+	bidClient storage.AuctionBidClient,
+	donationClient storage.DonationClient,
+	currency string,
+) *ReportHandler {
+	return &ReportHandler{
+		itemClient:     itemClient,
+		bidClient:      bidClient,
+		donationClient: donationClient,
+		currency:       currency,
+	}
+}
+
+// RegisterRoutes registers all of the paths to the handler functions.
+func (handler *ReportHandler) RegisterRoutes(router *mux.Router) {
+	reportsRouter := router.PathPrefix("/v1/auctions/reports").Subrouter()
+	reportsRouter.Use(middleware.VerifyAuthToken)
+	reportsRouter.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin))
+	reportsRouter.HandleFunc("/donors", wrapHandler(handler.GetDonorReport)).Methods(http.MethodGet)
+	reportsRouter.HandleFunc("/bidders", wrapHandler(handler.GetBidderReport)).Methods(http.MethodGet)
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getDonorReportRequestDoc is for swagger generation only.
+// swagger:parameters getDonorReportRequest
+type getDonorReportRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// Contains the items of every donor and how much they raised.
+//
+// swagger:response getDonorReportResponse
+type getDonorReportResponseDoc struct {
+
+	// In: body
+	Body []donorReportResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetDonorReport is the handler that groups the model.AuctionItems by who donated them.
+//
+// swagger:route GET /api/v1/auctions/reports/donors Reports getDonorReportRequest
+//
+// Gets the items of every donor.
+//
+// This will retrieve every donor along with the items they donated, what the items are worth and how much their winning
+// bids raised so the donors can be thanked. Donors are matched by their name and business, ignoring case, and ordered by
+// name. Items without a donor are left out. This route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getDonorReportResponse
+func (handler *ReportHandler) GetDonorReport(w http.ResponseWriter, r *http.Request) error {
+	items, err := handler.itemClient.GetAll(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve auction items")
+	}
+
+	winningBids, err := handler.bidClient.GetAllWinningBids(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve winning bids")
+	}
+
+	report, err := buildDonorReport(items, winningBids, handler.currency)
+	if err != nil {
+		return errors.Wrap(err, "could not build donor report")
+	}
+
+	rawResponse, err := json.Marshal(report)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal donor report")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// buildDonorReport groups the items by their donor, ignoring case. The donors are ordered by name and then business.
+// Fair market values and amounts raised are in the currency. This will return model.ErrCurrencyMismatch if a fair
+// market value or winning bid is in another currency.
+func buildDonorReport(
+	items []*model.AuctionItem,
+	winningBids []*model.AuctionBid,
+	currency string,
+) ([]*donorReportResponse, error) {
+	raisedByItem := make(map[uint64]model.Money, len(winningBids))
+	for _, bid := range winningBids {
+		raised, ok := raisedByItem[bid.Item.ID]
+		if !ok {
+			raised = model.Money{Currency: currency}
+		}
+		if err := addTo(bid.BidAmount, &raised); err != nil {
+			return nil, errors.Wrapf(err, "unable to add bid on item %d", bid.Item.ID)
+		}
+		raisedByItem[bid.Item.ID] = raised
+	}
+
+	sorted := make([]*model.AuctionItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var report []*donorReportResponse
+	donors := make(map[string]*donorReportResponse)
+	for _, item := range sorted {
+		if item.DonorName == "" && item.DonorBusiness == "" {
+			continue
+		}
+
+		key := strings.ToLower(item.DonorName) + "\x00" + strings.ToLower(item.DonorBusiness)
+		donor, ok := donors[key]
+		if !ok {
+			donor = &donorReportResponse{
+				DonorName:            item.DonorName,
+				DonorBusiness:        item.DonorBusiness,
+				TotalFairMarketValue: model.Money{Currency: currency},
+				TotalRaised:          model.Money{Currency: currency},
+			}
+			donors[key] = donor
+			report = append(report, donor)
+		}
+
+		raised, ok := raisedByItem[item.ID]
+		if !ok {
+			raised = model.Money{Currency: currency}
+		}
+		fairMarketValue := model.Money{Currency: currency}
+		if err := addTo(item.FairMarketValue, &fairMarketValue, &donor.TotalFairMarketValue); err != nil {
+			return nil, errors.Wrapf(err, "unable to add fair market value of item %d", item.ID)
+		}
+		donor.Items = append(donor.Items, &donatedItemResponse{
+			ID:              item.ID,
+			Name:            item.Name,
+			FairMarketValue: fairMarketValue,
+			HighestBid:      raised,
+		})
+		if err := addTo(raised, &donor.TotalRaised); err != nil {
+			return nil, errors.Wrapf(err, "unable to add item %d", item.ID)
+		}
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		nameI, nameJ := strings.ToLower(report[i].DonorName), strings.ToLower(report[j].DonorName)
+		if nameI != nameJ {
+			return nameI < nameJ
+		}
+		return strings.ToLower(report[i].DonorBusiness) < strings.ToLower(report[j].DonorBusiness)
+	})
+
+	if report == nil {
+		report = []*donorReportResponse{}
+	}
+	return report, nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getBidderReportRequestDoc is for swagger generation only.
+// swagger:parameters getBidderReportRequest
+type getBidderReportRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// Contains what every bidder won and pledged and how much they owe.
+//
+// swagger:response getBidderReportResponse
+type getBidderReportResponseDoc struct {
+
+	// In: body
+	Body []bidderReportResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetBidderReport is the handler that groups the winning model.AuctionBids and model.Pledges by bidder.
+//
+// swagger:route GET /api/v1/auctions/reports/bidders Reports getBidderReportRequest
+//
+// Gets what every bidder owes.
+//
+// This will retrieve every bidder who won an item or made a pledge along with how much they owe in total so they can be
+// invoiced at checkout. Bidders are ordered by username and deleted users are included since they still owe what they
+// won. This route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getBidderReportResponse
+func (handler *ReportHandler) GetBidderReport(w http.ResponseWriter, r *http.Request) error {
+	winningBids, err := handler.bidClient.GetAllWinningBids(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve winning bids")
+	}
+
+	pledges, err := handler.donationClient.GetPledges(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve pledges")
+	}
+
+	report, err := buildBidderReport(winningBids, pledges, handler.currency)
+	if err != nil {
+		return errors.Wrap(err, "could not build bidder report")
+	}
+
+	rawResponse, err := json.Marshal(report)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal bidder report")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// buildBidderReport groups the winning bids and pledges by bidder. The bidders are ordered by username. Amounts are in
+// the currency. This will return model.ErrCurrencyMismatch if a winning bid or pledge is in another currency.
+func buildBidderReport(
+	winningBids []*model.AuctionBid,
+	pledges []*model.Pledge,
+	currency string,
+) ([]*bidderReportResponse, error) {
+	report := []*bidderReportResponse{}
+	bidders := make(map[string]*bidderReportResponse)
+	bidderReport := func(user *model.User) *bidderReportResponse {
+		bidder, ok := bidders[user.Username]
+		if !ok {
+			bidder = &bidderReportResponse{
+				Username:     user.Username,
+				DisplayName:  user.DisplayName,
+				Items:        []*wonItemResponse{},
+				Pledges:      []*bidderPledgeResponse{},
+				TotalWon:     model.Money{Currency: currency},
+				TotalPledged: model.Money{Currency: currency},
+				TotalOwed:    model.Money{Currency: currency},
+			}
+			bidders[user.Username] = bidder
+			report = append(report, bidder)
+		}
+		return bidder
+	}
+
+	sorted := make([]*model.AuctionBid, len(winningBids))
+	copy(sorted, winningBids)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Item.ID < sorted[j].Item.ID
+	})
+	for _, bid := range sorted {
+		bidder := bidderReport(bid.Bidder)
+		bidder.Items = append(bidder.Items, &wonItemResponse{
+			ID:     bid.Item.ID,
+			Name:   bid.Item.Name,
+			LotID:  bid.Item.LotID,
+			Amount: bid.BidAmount,
+		})
+		if err := addTo(bid.BidAmount, &bidder.TotalWon, &bidder.TotalOwed); err != nil {
+			return nil, errors.Wrapf(err, "unable to add bid of '%s'", bid.Bidder.Username)
+		}
+	}
+
+	for _, pledge := range pledges {
+		bidder := bidderReport(pledge.Bidder)
+		bidder.Pledges = append(bidder.Pledges, &bidderPledgeResponse{
+			ID:     pledge.ID,
+			Level:  pledge.Level.Name,
+			Amount: pledge.Amount,
+		})
+		if err := addTo(pledge.Amount, &bidder.TotalPledged, &bidder.TotalOwed); err != nil {
+			return nil, errors.Wrapf(err, "unable to add pledge of '%s'", pledge.Bidder.Username)
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Username < report[j].Username
+	})
+	return report, nil
+}
+
+// addTo adds the amount to every total. This will return model.ErrCurrencyMismatch if the amount is in a different
+// currency than a total.
+func addTo(amount model.Money, totals ...*model.Money) error {
+	for _, total := range totals {
+		sum, err := total.Add(amount)
+		if err != nil {
+			return err
+		}
+		*total = sum
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type reportHandlerTestSuite struct {
+	suite.Suite
+
+	client          *http.Client
+	server          *httptest.Server
+	auctionItemMock *mocks.AuctionItemClient
+	auctionBidMock  *mocks.AuctionBidClient
+	donationMock    *mocks.DonationClient
+	handler         *ReportHandler
+}
+
+func (ts *reportHandlerTestSuite) SetupSuite() {
+	ts.handler = NewReportHandler(ts.auctionItemMock, ts.auctionBidMock, ts.donationMock, "USD")
+	var router *mux.Router
+	ts.server, router = newTestServer()
+	ts.handler.RegisterRoutes(router)
+	ts.client = &http.Client{}
+}
+
+func (ts *reportHandlerTestSuite) SetupTest() {
+	ts.auctionItemMock = new(mocks.AuctionItemClient)
+	ts.auctionBidMock = new(mocks.AuctionBidClient)
+	ts.handler.itemClient = ts.auctionItemMock
+	ts.donationMock = new(mocks.DonationClient)
+	ts.handler.bidClient = ts.auctionBidMock
+	ts.handler.donationClient = ts.donationMock
+}
+
+func (ts *reportHandlerTestSuite) TearDownTest() {
+	ts.auctionItemMock.AssertExpectations(ts.T())
+	ts.auctionBidMock.AssertExpectations(ts.T())
+	ts.donationMock.AssertExpectations(ts.T())
+}
+
+func (ts *reportHandlerTestSuite) TearDownSuite() {
+	ts.server.Close()
+}
+
+func TestReportHandler(t *testing.T) {
+	suite.Run(t, new(reportHandlerTestSuite))
+}
+
+func (ts *reportHandlerTestSuite) TestGetDonorReportGroupsItemsByDonor() {
+	items := []*model.AuctionItem{
+		{
+			ID:              3,
+			Name:            "Tee Time",
+			DonorName:       "pat smith",
+			DonorBusiness:   "Smith Golf",
+			FairMarketValue: usd(10000),
+			Quantity:        2,
+		},
+		{ID: 1, Name: "Golf Clubs", DonorName: "Pat Smith", DonorBusiness: "Smith Golf", FairMarketValue: usd(50000)},
+		{ID: 2, Name: "Cruise", DonorBusiness: "Acme Travel", FairMarketValue: usd(200000)},
+		{ID: 4, Name: "Mystery Box"},
+	}
+	bids := []*model.AuctionBid{
+		{Item: items[1], BidAmount: usd(65000)},
+		{Item: items[2], BidAmount: usd(150000)},
+		{Item: items[0], BidAmount: usd(12000)},
+		{Item: items[0], BidAmount: usd(9000)},
+	}
+	ts.auctionItemMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return(items, nil)
+	ts.auctionBidMock.On("GetAllWinningBids", mock.AnythingOfType("*context.valueCtx")).Return(bids, nil)
+
+	r := makeAuthenticatedRequest(ts.T(), http.MethodGet, ts.server.URL+"/api/v1/auctions/reports/donors", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var report []*donorReportResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&report))
+	ts.Require().Equal([]*donorReportResponse{
+		{
+			DonorBusiness: "Acme Travel",
+			Items: []*donatedItemResponse{
+				{ID: 2, Name: "Cruise", FairMarketValue: usd(200000), HighestBid: usd(150000)},
+			},
+			TotalFairMarketValue: usd(200000),
+			TotalRaised:          usd(150000),
+		},
+		{
+			DonorName:     "Pat Smith",
+			DonorBusiness: "Smith Golf",
+			Items: []*donatedItemResponse{
+				{ID: 1, Name: "Golf Clubs", FairMarketValue: usd(50000), HighestBid: usd(65000)},
+				{ID: 3, Name: "Tee Time", FairMarketValue: usd(10000), HighestBid: usd(21000)},
+			},
+			TotalFairMarketValue: usd(60000),
+			TotalRaised:          usd(86000),
+		},
+	}, report)
+}
+
+func (ts *reportHandlerTestSuite) TestGetDonorReport403OnBidderRequest() {
+	r := makeAuthenticatedRequest(ts.T(), http.MethodGet, ts.server.URL+"/api/v1/auctions/reports/donors", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *reportHandlerTestSuite) TestGetBidderReportAddsPledgesToWinningBids() {
+	alice := &model.User{Username: "alice", DisplayName: "Alice"}
+	bob := &model.User{Username: "bob", DisplayName: "Bob"}
+	carol := &model.User{Username: "carol", DisplayName: "Carol"}
+	level := &model.AppealLevel{ID: 1, Name: "School Supplies", Amount: usd(10000)}
+	bids := []*model.AuctionBid{
+		{Item: &model.AuctionItem{ID: 5, Name: "Cruise"}, Bidder: bob, BidAmount: usd(150000)},
+		{Item: &model.AuctionItem{ID: 2, Name: "Golf Clubs", LotID: 7}, Bidder: bob, BidAmount: usd(65000)},
+		{Item: &model.AuctionItem{ID: 3, Name: "Tee Time"}, Bidder: alice, BidAmount: usd(12000)},
+	}
+	pledges := []*model.Pledge{
+		{ID: 1, Level: level, Bidder: carol, Amount: usd(10000)},
+		{ID: 2, Level: level, Bidder: bob, Amount: usd(10000)},
+	}
+	ts.auctionBidMock.On("GetAllWinningBids", mock.AnythingOfType("*context.valueCtx")).Return(bids, nil)
+	ts.donationMock.On("GetPledges", mock.AnythingOfType("*context.valueCtx")).Return(pledges, nil)
+
+	r := makeAuthenticatedRequest(ts.T(), http.MethodGet, ts.server.URL+"/api/v1/auctions/reports/bidders", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var report []*bidderReportResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&report))
+	ts.Require().Equal([]*bidderReportResponse{
+		{
+			Username:    "alice",
+			DisplayName: "Alice",
+			Items: []*wonItemResponse{
+				{ID: 3, Name: "Tee Time", Amount: usd(12000)},
+			},
+			Pledges:      []*bidderPledgeResponse{},
+			TotalWon:     usd(12000),
+			TotalPledged: usd(0),
+			TotalOwed:    usd(12000),
+		},
+		{
+			Username:    "bob",
+			DisplayName: "Bob",
+			Items: []*wonItemResponse{
+				{ID: 2, Name: "Golf Clubs", LotID: 7, Amount: usd(65000)},
+				{ID: 5, Name: "Cruise", Amount: usd(150000)},
+			},
+			Pledges: []*bidderPledgeResponse{
+				{ID: 2, Level: "School Supplies", Amount: usd(10000)},
+			},
+			TotalWon:     usd(215000),
+			TotalPledged: usd(10000),
+			TotalOwed:    usd(225000),
+		},
+		{
+			Username:    "carol",
+			DisplayName: "Carol",
+			Items:       []*wonItemResponse{},
+			Pledges: []*bidderPledgeResponse{
+				{ID: 1, Level: "School Supplies", Amount: usd(10000)},
+			},
+			TotalWon:     usd(0),
+			TotalPledged: usd(10000),
+			TotalOwed:    usd(10000),
+		},
+	}, report)
+}
+
+func (ts *reportHandlerTestSuite) TestGetBidderReport500WhenBidIsInOtherCurrency() {
+	bids := []*model.AuctionBid{
+		{Item: &model.AuctionItem{ID: 5, Name: "Cruise"}, Bidder: &model.User{Username: "bob"}, BidAmount: usd(150000)},
+		{
+			Item:      &model.AuctionItem{ID: 3, Name: "Tee Time"},
+			Bidder:    &model.User{Username: "bob"},
+			BidAmount: model.Money{Amount: 12000, Currency: "EUR"},
+		},
+	}
+	ts.auctionBidMock.On("GetAllWinningBids", mock.AnythingOfType("*context.valueCtx")).Return(bids, nil)
+	ts.donationMock.On("GetPledges", mock.AnythingOfType("*context.valueCtx")).Return(nil, nil)
+
+	r := makeAuthenticatedRequest(ts.T(), http.MethodGet, ts.server.URL+"/api/v1/auctions/reports/bidders", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusInternalServerError, response.StatusCode)
+}
+
+func (ts *reportHandlerTestSuite) TestGetBidderReport403OnBidderRequest() {
+	r := makeAuthenticatedRequest(ts.T(), http.MethodGet, ts.server.URL+"/api/v1/auctions/reports/bidders", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func usd(amount int64) model.Money {
+	return model.Money{Amount: amount, Currency: "USD"}
+}
@@ -0,0 +1,485 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+type (
+	// swagger:model
+	spendingLimitResponse struct {
+		// The username of the bidder the limit is for. It is empty for the limit of the event, which applies to every
+		// bidder without a limit of their own.
+		Username string `json:"username,omitempty"`
+
+		// The most the winning bids of the bidder can add up to as a decimal string followed by the currency, e.g.
+		// "50.00 USD".
+		//
+		// Required: true
+		Amount model.Money `json:"amount"`
+
+		// The limit written for the locale of the event, e.g. "$50.00".
+		//
+		// Required: true
+		FormattedAmount string `json:"formattedAmount"`
+
+		// What the winning bids of the bidder add up to right now. It is left out for the limit of the event.
+		Committed *model.Money `json:"committed,omitempty"`
+	}
+
+	putSpendingLimitRequest struct {
+		// The limit as a decimal string followed by the currency of the event, e.g. "50.00 USD". A limit of 0 stops
+		// the bidder from bidding at all.
+		//
+		// Required: true
+		Amount model.Money `json:"amount"`
+	}
+)
+
+var (
+	errLimitAmountFormat  = errors.New("limit amount must be a string such as \"50.00 USD\"")
+	errLimitAmountInvalid = errors.New("limit amount must not be less than 0")
+	errLimitCurrency      = errors.New("limit is not in the currency of the auction")
+)
+
+// SpendingLimitHandler provides handlers for endpoints involving model.SpendingLimits.
+type SpendingLimitHandler struct {
+	limitClient storage.SpendingLimitClient
+	bidClient   storage.AuctionBidClient
+	transactor  storage.Transactor
+	currency    string
+	locale      string
+}
+
+// NewSpendingLimitHandler creates a new SpendingLimitHandler with the necessary storage objects. Every limit must be in
+// the currency of the event and amounts are formatted for its locale.
+func NewSpendingLimitHandler(
+	limitClient storage.SpendingLimitClient,
+	bidClient storage.AuctionBidClient,
+	transactor storage.Transactor,
+	currency string,
+	locale string,
+) *SpendingLimitHandler {
+	return &SpendingLimitHandler{
+		limitClient: limitClient,
+		bidClient:   bidClient,
+		transactor:  transactor,
+		currency:    currency,
+		locale:      locale,
+	}
+}
+
+// RegisterRoutes registers all of the paths to the handler functions.
+func (handler *SpendingLimitHandler) RegisterRoutes(router *mux.Router) {
+	limitsRouter := router.PathPrefix("/v1/auctions/limits").Subrouter()
+	limitsRouter.Use(middleware.VerifyAuthToken)
+	limitsRouter.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin))
+	limitsRouter.HandleFunc("", wrapHandler(handler.GetSpendingLimits)).Methods(http.MethodGet)
+	limitsRouter.HandleFunc("/event", wrapHandler(handler.PutEventSpendingLimit)).Methods(http.MethodPut)
+	limitsRouter.HandleFunc("/event", wrapHandler(handler.DeleteEventSpendingLimit)).Methods(http.MethodDelete)
+	limitsRouter.HandleFunc("/users/{username}", wrapHandler(handler.PutUserSpendingLimit)).Methods(http.MethodPut)
+	limitsRouter.HandleFunc("/users/{username}", wrapHandler(handler.DeleteUserSpendingLimit)).Methods(http.MethodDelete)
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// getSpendingLimitsRequestDoc is for swagger generation only.
+// swagger:parameters getSpendingLimitsRequest
+type getSpendingLimitsRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// Contains the limit of the event, when there is one, followed by the limits of the bidders ordered by username.
+//
+// swagger:response getSpendingLimitsResponse
+type getSpendingLimitsResponseDoc struct {
+
+	// In: body
+	Body []spendingLimitResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetSpendingLimits is the handler that retrieves all model.SpendingLimits as serialized JSON.
+//
+// swagger:route GET /api/v1/auctions/limits Limits getSpendingLimitsRequest
+//
+// Gets all spending limits.
+//
+// This will retrieve the limit of the event and every limit of a bidder along with what the bidder is already
+// committed to by the items they are winning. This route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getSpendingLimitsResponse
+func (handler *SpendingLimitHandler) GetSpendingLimits(w http.ResponseWriter, r *http.Request) error {
+	limits, err := handler.limitClient.GetAll(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve spending limits")
+	}
+
+	winningBids, err := handler.bidClient.GetAllWinningBids(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve winning bids")
+	}
+
+	committed, err := handler.committedByBidder(winningBids)
+	if err != nil {
+		return errors.Wrap(err, "could not add up winning bids")
+	}
+
+	responseObjects := make([]*spendingLimitResponse, len(limits))
+	for i, limit := range limits {
+		responseObjects[i] = handler.newSpendingLimitResponse(limit, committed)
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal spending limits")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// putEventSpendingLimitRequestDoc is for swagger generation only.
+// swagger:parameters putEventSpendingLimitRequest
+type putEventSpendingLimitRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// In: body
+	Body putSpendingLimitRequest
+}
+
+// Contains the limit that was set.
+//
+// swagger:response putSpendingLimitResponse
+type putSpendingLimitResponseDoc struct {
+
+	// In: body
+	Body spendingLimitResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PutEventSpendingLimit is the handler that sets the model.SpendingLimit of the event.
+//
+// swagger:route PUT /api/v1/auctions/limits/event Limits putEventSpendingLimitRequest
+//
+// Sets the spending limit of the event.
+//
+// This will set the limit that applies to every bidder without a limit of their own. Bids that are already placed are
+// kept even when they add up to more than the new limit. This route is only available to Admin users.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: putSpendingLimitResponse
+//    400: errorMessage
+func (handler *SpendingLimitHandler) PutEventSpendingLimit(w http.ResponseWriter, r *http.Request) error {
+	return handler.putSpendingLimit(w, r, "")
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// putUserSpendingLimitRequestDoc is for swagger generation only.
+// swagger:parameters putUserSpendingLimitRequest
+type putUserSpendingLimitRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// Username of the bidder.
+	//
+	// In: path
+	Username string `json:"username"`
+
+	// In: body
+	Body putSpendingLimitRequest
+}
+
+// ----- End Documentation Generation Types --------------
+
+// PutUserSpendingLimit is the handler that sets the model.SpendingLimit of a bidder.
+//
+// swagger:route PUT /api/v1/auctions/limits/users/{username} Limits putUserSpendingLimitRequest
+//
+// Sets the spending limit of a bidder.
+//
+// This will set the credit the bidder was pre-authorized for. It is used in place of the limit of the event, so it can
+// be higher or lower. Bids that are already placed are kept even when they add up to more than the new limit. This
+// route is only available to Admin users.
+//
+//  Consumes:
+//  - application/json
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: putSpendingLimitResponse
+//    400: errorMessage
+//    404: errorMessage
+func (handler *SpendingLimitHandler) PutUserSpendingLimit(w http.ResponseWriter, r *http.Request) error {
+	username := mux.Vars(r)["username"]
+	r = r.WithContext(log.WithFields(r.Context(), "username", username))
+	return handler.putSpendingLimit(w, r, username)
+}
+
+// putSpendingLimit sets the limit of the user, or of the event when the username is empty, to the amount in the body of
+// the request.
+func (handler *SpendingLimitHandler) putSpendingLimit(w http.ResponseWriter, r *http.Request, username string) error {
+	var request putSpendingLimitRequest
+	if ok, err := readSpendingLimitRequest(w, r, &request); !ok {
+		return err
+	}
+
+	if request.Amount.Currency != handler.currency {
+		message := fmt.Sprintf("%s, which is %s", errLimitCurrency.Error(), handler.currency)
+		return errors.Wrap(writeErrorMessage(w, http.StatusBadRequest, message), "could not set spending limit")
+	}
+	if request.Amount.Amount < 0 {
+		return errors.Wrap(writeSpendingLimitError(w, errLimitAmountInvalid), "could not set spending limit")
+	}
+
+	limit := &model.SpendingLimit{
+		Username: username,
+		Amount:   request.Amount,
+	}
+	var winningBids []*model.AuctionBid
+	err := handler.transactor.RunInTx(r.Context(), func(ctx context.Context, clients *storage.Clients) error {
+		if err := clients.Limits.Set(ctx, limit); err != nil {
+			return err
+		}
+		var err error
+		winningBids, err = clients.Bids.GetAllWinningBids(ctx)
+		return errors.Wrap(err, "could not retrieve winning bids")
+	})
+	if err != nil {
+		return errors.Wrap(writeSpendingLimitError(w, err), "could not set spending limit")
+	}
+
+	committed, err := handler.committedByBidder(winningBids)
+	if err != nil {
+		return errors.Wrap(err, "could not add up winning bids")
+	}
+
+	rawLimit, err := json.Marshal(handler.newSpendingLimitResponse(limit, committed))
+	if err != nil {
+		return errors.Wrap(err, "could not marshal spending limit")
+	}
+
+	fmt.Fprint(w, string(rawLimit))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// deleteEventSpendingLimitRequestDoc is for swagger generation only.
+// swagger:parameters deleteEventSpendingLimitRequest
+type deleteEventSpendingLimitRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+}
+
+// ----- End Documentation Generation Types --------------
+
+// DeleteEventSpendingLimit is the handler that removes the model.SpendingLimit of the event.
+//
+// swagger:route DELETE /api/v1/auctions/limits/event Limits deleteEventSpendingLimitRequest
+//
+// Removes the spending limit of the event.
+//
+// This will let every bidder without a limit of their own bid as much as they like. This route is only available to
+// Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: noBody
+//    404: errorMessage
+func (handler *SpendingLimitHandler) DeleteEventSpendingLimit(w http.ResponseWriter, r *http.Request) error {
+	if err := handler.limitClient.Delete(r.Context(), ""); err != nil {
+		return errors.Wrap(writeSpendingLimitError(w, err), "could not delete spending limit")
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// deleteUserSpendingLimitRequestDoc is for swagger generation only.
+// swagger:parameters deleteUserSpendingLimitRequest
+type deleteUserSpendingLimitRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// Username of the bidder.
+	//
+	// In: path
+	Username string `json:"username"`
+}
+
+// ----- End Documentation Generation Types --------------
+
+// DeleteUserSpendingLimit is the handler that removes the model.SpendingLimit of a bidder.
+//
+// swagger:route DELETE /api/v1/auctions/limits/users/{username} Limits deleteUserSpendingLimitRequest
+//
+// Removes the spending limit of a bidder.
+//
+// This will make the limit of the event, when there is one, apply to the bidder again. This route is only available to
+// Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: noBody
+//    404: errorMessage
+func (handler *SpendingLimitHandler) DeleteUserSpendingLimit(w http.ResponseWriter, r *http.Request) error {
+	username := mux.Vars(r)["username"]
+	r = r.WithContext(log.WithFields(r.Context(), "username", username))
+
+	if err := handler.limitClient.Delete(r.Context(), username); err != nil {
+		return errors.Wrap(writeSpendingLimitError(w, err), "could not delete spending limit")
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// committedByBidder adds up the winning bids of each bidder by username. This will return model.ErrCurrencyMismatch if
+// a winning bid is not in the currency of the event.
+func (handler *SpendingLimitHandler) committedByBidder(
+	winningBids []*model.AuctionBid,
+) (map[string]model.Money, error) {
+	committed := make(map[string]model.Money)
+	for _, bid := range winningBids {
+		total, ok := committed[bid.Bidder.Username]
+		if !ok {
+			total = model.Money{Currency: handler.currency}
+		}
+
+		total, err := total.Add(bid.BidAmount)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to add bid of '%s'", bid.Bidder.Username)
+		}
+		committed[bid.Bidder.Username] = total
+	}
+	return committed, nil
+}
+
+func (handler *SpendingLimitHandler) newSpendingLimitResponse(
+	limit *model.SpendingLimit,
+	committed map[string]model.Money,
+) *spendingLimitResponse {
+	response := &spendingLimitResponse{
+		Username:        limit.Username,
+		Amount:          limit.Amount,
+		FormattedAmount: limit.Amount.Format(handler.locale),
+	}
+	if limit.Username != "" {
+		total, ok := committed[limit.Username]
+		if !ok {
+			total = model.Money{Currency: handler.currency}
+		}
+		response.Committed = &total
+	}
+	return response
+}
+
+// readSpendingLimitRequest reads the JSON body of the request. A bad request is written and false is returned if the
+// body is not valid.
+func readSpendingLimitRequest(w http.ResponseWriter, r *http.Request, request interface{}) (bool, error) {
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false, errors.Wrap(err, "could not read body")
+	}
+	defer r.Body.Close()
+
+	if err = json.Unmarshal(rawBody, request); err != nil {
+		log.Info(r.Context(), "invalid json request", "body", string(rawBody), "err", err)
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrUnknownCurrency) {
+			return false, errors.Wrap(writeSpendingLimitError(w, errLimitAmountFormat), "could not read spending limit")
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		return false, nil
+	}
+	return true, nil
+}
+
+// writeSpendingLimitError responds to a request that could not change a spending limit because of the client. Any
+// other error is returned so it is handled as a server error.
+func writeSpendingLimitError(w http.ResponseWriter, err error) error {
+	var message string
+	var status int
+	switch {
+	case errors.Is(err, storage.ErrEntityNotFound):
+		status, message = http.StatusNotFound, "user or spending limit does not exist"
+	case errors.Is(err, errLimitAmountFormat):
+		status, message = http.StatusBadRequest, errLimitAmountFormat.Error()
+	case errors.Is(err, errLimitAmountInvalid):
+		status, message = http.StatusBadRequest, errLimitAmountInvalid.Error()
+	default:
+		return err
+	}
+
+	return writeErrorMessage(w, status, message)
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type spendingLimitHandlerTestSuite struct {
+	suite.Suite
+
+	client         *http.Client
+	server         *httptest.Server
+	limitMock      *mocks.SpendingLimitClient
+	bidMock        *mocks.AuctionBidClient
+	transactorMock *mocks.Transactor
+	handler        *SpendingLimitHandler
+}
+
+func (ts *spendingLimitHandlerTestSuite) SetupSuite() {
+	ts.handler = NewSpendingLimitHandler(ts.limitMock, ts.bidMock, ts.transactorMock, "USD", "en-US")
+	var router *mux.Router
+	ts.server, router = newTestServer()
+	ts.handler.RegisterRoutes(router)
+	ts.client = &http.Client{}
+}
+
+func (ts *spendingLimitHandlerTestSuite) SetupTest() {
+	ts.limitMock = new(mocks.SpendingLimitClient)
+	ts.bidMock = new(mocks.AuctionBidClient)
+	ts.transactorMock = new(mocks.Transactor)
+	ts.transactorMock.On("RunInTx", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(
+		func(ctx context.Context, fn func(context.Context, *storage.Clients) error) error {
+			return fn(ctx, &storage.Clients{
+				Limits: ts.limitMock,
+				Bids:   ts.bidMock,
+			})
+		},
+	).Maybe()
+	ts.handler.limitClient = ts.limitMock
+	ts.handler.bidClient = ts.bidMock
+	ts.handler.transactor = ts.transactorMock
+}
+
+func (ts *spendingLimitHandlerTestSuite) TearDownTest() {
+	ts.limitMock.AssertExpectations(ts.T())
+	ts.bidMock.AssertExpectations(ts.T())
+}
+
+func (ts *spendingLimitHandlerTestSuite) TearDownSuite() {
+	ts.server.Close()
+}
+
+func TestSpendingLimitHandler(t *testing.T) {
+	suite.Run(t, new(spendingLimitHandlerTestSuite))
+}
+
+func (ts *spendingLimitHandlerTestSuite) TestGetSpendingLimitsIncludesWhatEachBidderHasCommitted() {
+	ts.limitMock.On("GetAll", mock.AnythingOfType("*context.valueCtx")).Return([]*model.SpendingLimit{
+		{Amount: usd(5000)},
+		{Username: "alex", Amount: usd(10000)},
+		{Username: "sam", Amount: usd(2500)},
+	}, nil)
+	ts.bidMock.On("GetAllWinningBids", mock.AnythingOfType("*context.valueCtx")).Return([]*model.AuctionBid{
+		{BidAmount: usd(1500), Bidder: &model.User{Username: "alex"}, Item: &model.AuctionItem{ID: 1}},
+		{BidAmount: usd(2000), Bidder: &model.User{Username: "alex"}, Item: &model.AuctionItem{ID: 2}},
+		{BidAmount: usd(900), Bidder: &model.User{Username: "jo"}, Item: &model.AuctionItem{ID: 3}},
+	}, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var limits []spendingLimitResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&limits))
+	ts.Require().Len(limits, 3)
+	ts.Require().Empty(limits[0].Username)
+	ts.Require().EqualValues("$50.00", limits[0].FormattedAmount)
+	ts.Require().Nil(limits[0].Committed)
+	ts.Require().EqualValues(usd(3500), *limits[1].Committed)
+	ts.Require().EqualValues(usd(0), *limits[2].Committed)
+}
+
+func (ts *spendingLimitHandlerTestSuite) TestGetSpendingLimits403OnBidderRequest() {
+	r := ts.makeAuthenticatedRequest(http.MethodGet, "", nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *spendingLimitHandlerTestSuite) TestPutUserSpendingLimitSetsLimitOfBidder() {
+	ts.limitMock.On(
+		"Set", mock.AnythingOfType("*context.valueCtx"), &model.SpendingLimit{Username: "alex", Amount: usd(7500)},
+	).Return(nil)
+	ts.bidMock.On("GetAllWinningBids", mock.AnythingOfType("*context.valueCtx")).Return([]*model.AuctionBid{
+		{BidAmount: usd(1500), Bidder: &model.User{Username: "alex"}, Item: &model.AuctionItem{ID: 1}},
+	}, nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPut, "/users/alex", strings.NewReader(`{"amount":"75.00 USD"}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	var limit spendingLimitResponse
+	ts.Require().NoError(json.NewDecoder(response.Body).Decode(&limit))
+	ts.Require().EqualValues("alex", limit.Username)
+	ts.Require().EqualValues(usd(7500), limit.Amount)
+	ts.Require().EqualValues(usd(1500), *limit.Committed)
+}
+
+func (ts *spendingLimitHandlerTestSuite) TestPutUserSpendingLimit404WhenUserNotFound() {
+	ts.limitMock.On("Set", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(storage.ErrEntityNotFound)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPut, "/users/nobody", strings.NewReader(`{"amount":"75.00 USD"}`), &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *spendingLimitHandlerTestSuite) TestPutEventSpendingLimit400WhenAmountIsInvalid() {
+	for _, body := range []string{`{"amount":"-5.00 USD"}`, `{"amount":"5.00 EUR"}`, `{"amount":"five dollars"}`} {
+		r := ts.makeAuthenticatedRequest(http.MethodPut, "/event", strings.NewReader(body), &model.User{
+			Permission: model.PermissionLevelAdmin,
+		})
+		response, err := ts.client.Do(r)
+		ts.Require().NoError(err)
+		ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode, body)
+	}
+}
+
+func (ts *spendingLimitHandlerTestSuite) TestDeleteEventSpendingLimit404WhenThereIsNoLimit() {
+	ts.limitMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), "").Return(storage.ErrEntityNotFound)
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, "/event", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *spendingLimitHandlerTestSuite) TestDeleteUserSpendingLimitRemovesLimitOfBidder() {
+	ts.limitMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), "alex").Return(nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, "/users/alex", nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+}
+
+func (ts *spendingLimitHandlerTestSuite) makeAuthenticatedRequest(method string, path string, body io.Reader, user *model.User) *http.Request {
+	return makeAuthenticatedRequest(ts.T(), method, ts.server.URL+"/api/v1/auctions/limits"+path, body, user)
+}
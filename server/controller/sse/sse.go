@@ -0,0 +1,163 @@
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultStreamDuration is how long a stream stays open when the server has no write timeout. Clients reconnect
+	// automatically and resume using the Last-Event-ID header.
+	DefaultStreamDuration = 10 * time.Second
+
+	reconnectDelay = time.Second
+)
+
+// Handler streams live auction events to clients that cannot use websockets.
+type Handler struct {
+	eventBus       *events.Bus
+	streamDuration time.Duration
+}
+
+// NewHandler constructs a Handler that relays everything published on the eventBus. Each stream is closed after
+// streamDuration, which must be shorter than the server's write timeout.
+func NewHandler(eventBus *events.Bus, streamDuration time.Duration) *Handler {
+	return &Handler{
+		eventBus:       eventBus,
+		streamDuration: streamDuration,
+	}
+}
+
+// RegisterRoutes registers the route to open an event stream.
+func (handler *Handler) RegisterRoutes(rootRouter *mux.Router) {
+	streamRouter := rootRouter.PathPrefix("/v1/auctions/stream").Subrouter()
+	streamRouter.Use(middleware.VerifyAuthToken)
+
+	streamRouter.HandleFunc("", handler.ServeSSE).Methods(http.MethodGet)
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// Response is a text/event-stream of auction events.
+// swagger:response sseStream
+type sseStreamDoc struct{}
+
+// sseRequestDoc is for swagger generation only.
+// swagger:parameters sseRequest
+type sseRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authorization string
+
+	// The ID of the last event the client received. Every retained event after it is sent before any new events.
+	//
+	// In: header
+	LastEventID string `json:"Last-Event-ID"`
+}
+
+// ----- End Documentation Generation Types --------------
+
+// ServeSSE streams events using Server-Sent Events.
+//
+// swagger:route GET /api/v1/auctions/stream Auctions sseRequest
+//
+// Streams live auction events.
+//
+// This is a fallback for clients that are unable to use websockets. Every event is sent with the event type as the SSE
+// event name and the JSON payload as the data. For example, a BidPlaced event contains the same data as the
+// WSResponseMessagePlaceBidData model. The stream is periodically closed and clients are expected to reconnect with the
+// Last-Event-ID header to resume where they left off. Event IDs are the same on every instance of the server, so the
+// stream can be resumed on a different instance.
+//
+//  Produces:
+//  - text/event-stream
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: sseStream
+//    400: noBody
+func (handler *Handler) ServeSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Error(r.Context(), "response writer does not support streaming")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var (
+		missed       []*events.Event
+		subscription *events.Subscription
+	)
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		var err error
+		missed, subscription, err = handler.eventBus.SubscribeAfterGlobalID(lastEventID)
+		if err != nil {
+			log.Info(r.Context(), "invalid last event ID", "lastEventID", lastEventID, "err", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	} else {
+		subscription = handler.eventBus.Subscribe()
+	}
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+
+	for _, event := range missed {
+		if err := writeEvent(w, event); err != nil {
+			log.Error(r.Context(), "unable to write event", "eventID", event.GlobalID, "err", err)
+			return
+		}
+	}
+	flusher.Flush()
+
+	timer := time.NewTimer(handler.streamDuration)
+	defer timer.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				log.Error(r.Context(), "unable to write event", "eventID", event.GlobalID, "err", err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event *events.Event) error {
+	data, err := json.Marshal(event.Payload)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal event payload")
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.GlobalID, event.Type, data)
+	if err != nil {
+		return errors.Wrap(err, "unable to write to client")
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/server/controller/auth"
+	"github.com/MMarsolek/AuctionHouse/server/controller/bidding"
+	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
+	"github.com/MMarsolek/AuctionHouse/storage/memory"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+)
+
+type handlerTestSuite struct {
+	suite.Suite
+
+	server  *httptest.Server
+	handler *Handler
+	client  *http.Client
+}
+
+func (ts *handlerTestSuite) SetupTest() {
+	router := mux.NewRouter().PathPrefix("/api").Subrouter()
+	ts.server = httptest.NewServer(middleware.RemoveTrailingSlash(router))
+	ts.handler = NewHandler(events.NewBus(events.DefaultHistorySize), time.Second)
+	ts.handler.RegisterRoutes(router)
+	ts.client = &http.Client{}
+}
+
+func (ts *handlerTestSuite) TearDownTest() {
+	ts.server.Close()
+}
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(handlerTestSuite))
+}
+
+func (ts *handlerTestSuite) TestServeSSEStreamsPublishedEvents() {
+	response := ts.openStream("")
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	ts.Require().EqualValues("text/event-stream", response.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(response.Body)
+	ts.Require().EqualValues([]string{"retry: 1000"}, ts.readEvent(reader))
+
+	event := ts.handler.eventBus.Publish(events.TypeBidPlaced, &events.BidPlaced{
+		ItemName: "item1",
+		Username: "bidder",
+		Amount:   model.Money{Amount: 10000, Currency: "USD"},
+	})
+
+	ts.Require().EqualValues([]string{
+		"id: " + event.GlobalID,
+		"event: BidPlaced",
+		`data: {"itemId":0,"itemName":"item1","username":"bidder","amount":"100.00 USD"}`,
+	}, ts.readEvent(reader))
+}
+
+func (ts *handlerTestSuite) TestServeSSEStreamsClosingOfItems() {
+	response := ts.openStream("")
+	defer response.Body.Close()
+	reader := bufio.NewReader(response.Body)
+	ts.readEvent(reader)
+
+	ctx := context.Background()
+	db := memory.NewDatabase()
+	itemClient := memory.NewAuctionItemClient(db)
+	closesAt := time.Date(2030, time.June, 1, 20, 0, 0, 0, time.UTC)
+	item := &model.AuctionItem{Name: "item1", ClosesAt: closesAt}
+	ts.Require().NoError(itemClient.Create(ctx, item))
+	err := bidding.AnnounceClosings(ctx, itemClient, memory.NewLotClient(db), ts.handler.eventBus, closesAt)
+	ts.Require().NoError(err)
+
+	lines := ts.readEvent(reader)
+	ts.Require().Len(lines, 3)
+	ts.Require().EqualValues([]string{
+		"event: AuctionClosed",
+		fmt.Sprintf(`data: {"id":%d,"name":"item1","closedAt":"2030-06-01T20:00:00Z"}`, item.ID),
+	}, lines[1:])
+}
+
+func (ts *handlerTestSuite) TestServeSSEReplaysEventsAfterLastEventID() {
+	var published []*events.Event
+	for i := 1; i <= 3; i++ {
+		published = append(published, ts.handler.eventBus.Publish(events.TypeBidPlaced, &events.BidPlaced{
+			Amount: model.Money{Amount: int64(i), Currency: "USD"},
+		}))
+	}
+
+	response := ts.openStream(published[0].GlobalID)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	reader := bufio.NewReader(response.Body)
+	ts.readEvent(reader)
+	ts.Require().Contains(ts.readEvent(reader), "id: "+published[1].GlobalID)
+	ts.Require().Contains(ts.readEvent(reader), "id: "+published[2].GlobalID)
+}
+
+func (ts *handlerTestSuite) TestServeSSE400OnInvalidLastEventID() {
+	response := ts.openStream("not a number")
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+}
+
+func (ts *handlerTestSuite) TestServeSSE401WithoutToken() {
+	response, err := ts.client.Get(ts.fullPath())
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusUnauthorized, response.StatusCode)
+}
+
+func (ts *handlerTestSuite) TestServeSSEEndsStreamAfterDuration() {
+	ts.handler.streamDuration = 10 * time.Millisecond
+	response := ts.openStream("")
+	defer response.Body.Close()
+
+	reader := bufio.NewReader(response.Body)
+	ts.readEvent(reader)
+	_, err := reader.ReadString('\n')
+	ts.Require().Error(err)
+}
+
+func (ts *handlerTestSuite) openStream(lastEventID string) *http.Response {
+	r, err := http.NewRequest(http.MethodGet, ts.fullPath(), nil)
+	ts.Require().NoError(err)
+
+	token, err := auth.NewToken(&model.User{
+		Username:   "bidder",
+		Permission: model.PermissionLevelBidder,
+	})
+	ts.Require().NoError(err)
+	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	if lastEventID != "" {
+		r.Header.Add("Last-Event-ID", lastEventID)
+	}
+
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	return response
+}
+
+func (ts *handlerTestSuite) readEvent(reader *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		ts.Require().NoError(err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func (ts *handlerTestSuite) fullPath() string {
+	return fmt.Sprintf("%s/api/v1/auctions/stream", ts.server.URL)
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+const (
+	// maxImagePixels limits how large an image may be once decoded so a small file cannot use up the memory.
+	maxImagePixels = 40 * 1000 * 1000
+
+	// thumbnailSize is the largest width and height of a thumbnail.
+	thumbnailSize = 400
+
+	thumbnailQuality   = 85
+	thumbnailMediaType = "image/jpeg"
+	thumbnailExtension = "jpg"
+)
+
+var (
+	errUnsupportedImageType = errors.New("image must be a JPEG, PNG or WebP")
+	errImageTooLarge        = errors.New("image is too large")
+	errInvalidImage         = errors.New("image could not be read")
+)
+
+// imageFormat describes how to read an accepted type of image.
+type imageFormat struct {
+	extension    string
+	decode       func(data []byte) (image.Image, error)
+	decodeConfig func(data []byte) (image.Config, error)
+}
+
+// imageFormats has the accepted types of images by their media type.
+var imageFormats = map[string]*imageFormat{
+	"image/jpeg": {
+		extension:    "jpg",
+		decode:       func(data []byte) (image.Image, error) { return jpeg.Decode(bytes.NewReader(data)) },
+		decodeConfig: func(data []byte) (image.Config, error) { return jpeg.DecodeConfig(bytes.NewReader(data)) },
+	},
+	"image/png": {
+		extension:    "png",
+		decode:       func(data []byte) (image.Image, error) { return png.Decode(bytes.NewReader(data)) },
+		decodeConfig: func(data []byte) (image.Config, error) { return png.DecodeConfig(bytes.NewReader(data)) },
+	},
+	"image/webp": {
+		extension:    "webp",
+		decode:       func(data []byte) (image.Image, error) { return webp.Decode(bytes.NewReader(data)) },
+		decodeConfig: func(data []byte) (image.Config, error) { return webp.DecodeConfig(bytes.NewReader(data)) },
+	},
+}
+
+// processedImage is an uploaded image that was checked along with its thumbnail.
+type processedImage struct {
+	data        []byte
+	contentType string
+	extension   string
+	thumbnail   []byte
+}
+
+// processImage checks the uploaded image and creates its thumbnail. The type of the image is determined by its contents
+// rather than what the client claims. The returned errors wrap errUnsupportedImageType, errImageTooLarge or
+// errInvalidImage.
+func processImage(data []byte) (*processedImage, error) {
+	contentType := http.DetectContentType(data)
+	format, ok := imageFormats[contentType]
+	if !ok {
+		return nil, errors.Wrapf(errUnsupportedImageType, "detected %s", contentType)
+	}
+
+	// The size is checked before decoding so the pixels are never allocated for images that are too large.
+	config, err := format.decodeConfig(data)
+	if err != nil {
+		return nil, errors.Wrap(errInvalidImage, err.Error())
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, errors.Wrap(errInvalidImage, "image has no pixels")
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, errors.Wrapf(errImageTooLarge, "%dx%d pixels is more than %d", config.Width, config.Height, maxImagePixels)
+	}
+
+	decoded, err := format.decode(data)
+	if err != nil {
+		return nil, errors.Wrap(errInvalidImage, err.Error())
+	}
+
+	thumbnail, err := createThumbnail(decoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create thumbnail")
+	}
+
+	return &processedImage{
+		data:        data,
+		contentType: contentType,
+		extension:   format.extension,
+		thumbnail:   thumbnail,
+	}, nil
+}
+
+// createThumbnail scales the image down to fit within thumbnailSize and encodes it as a JPEG. Smaller images are not
+// scaled up. Transparent pixels are drawn over white since JPEG does not support transparency.
+func createThumbnail(source image.Image) ([]byte, error) {
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > thumbnailSize || height > thumbnailSize {
+		if width >= height {
+			width, height = thumbnailSize, height*thumbnailSize/width
+		} else {
+			width, height = width*thumbnailSize/height, thumbnailSize
+		}
+	}
+	// Very narrow images would otherwise round down to nothing.
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(thumbnail, thumbnail.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), source, bounds, draw.Over, nil)
+
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, thumbnail, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, errors.Wrap(err, "could not encode thumbnail")
+	}
+	return buffer.Bytes(), nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/MMarsolek/AuctionHouse/log"
 	"github.com/MMarsolek/AuctionHouse/model"
@@ -31,6 +32,26 @@ type (
 		Permission model.PermissionLevel `json:"permission"`
 	}
 
+	getDeletedUserResponse struct {
+		// The username for the user. This is used to restore the user.
+		//
+		// Required: true
+		Username string `json:"username"`
+
+		// The human readable display name of the user.
+		DisplayName string `json:"displayName,omitempty"`
+
+		// The type of permission for this user.
+		//
+		// Required: true
+		Permission model.PermissionLevel `json:"permission"`
+
+		// When the user was deleted.
+		//
+		// Required: true
+		DeletedAt time.Time `json:"deletedAt"`
+	}
+
 	postUserRequest struct {
 		// The username for the user.
 		//
@@ -96,9 +117,14 @@ func (handler *UserHandler) RegisterRoutes(router *mux.Router) {
 	usersRouter := router.PathPrefix("/v1/users").Subrouter()
 	usersRouterWithAuth := usersRouter.NewRoute().Subrouter()
 	usersRouterWithAuth.Use(middleware.VerifyAuthToken)
+	usersRouterAdmin := usersRouterWithAuth.NewRoute().Subrouter()
+	usersRouterAdmin.Use(middleware.VerifyPermissions(model.PermissionLevelAdmin))
 
 	usersRouter.HandleFunc("/login", wrapHandler(handler.PostLogin)).Methods(http.MethodPost)
 	usersRouter.HandleFunc("", wrapHandler(handler.PostUser)).Methods(http.MethodPost)
+	usersRouterAdmin.HandleFunc("", wrapHandler(handler.GetDeletedUsers)).Methods(http.MethodGet).Queries("deleted", "true")
+	usersRouterAdmin.HandleFunc("/{username}", wrapHandler(handler.DeleteUser)).Methods(http.MethodDelete)
+	usersRouterAdmin.HandleFunc("/{username}/restore", wrapHandler(handler.RestoreUser)).Methods(http.MethodPost)
 	usersRouterWithAuth.HandleFunc("/{username}", wrapHandler(handler.GetUser)).Methods(http.MethodGet)
 }
 
@@ -176,6 +202,194 @@ func (handler *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) erro
 
 // ----- Start Documentation Generation Types --------------
 
+// getDeletedUsersRequestDoc is for swagger generation only.
+// swagger:parameters getDeletedUsersRequest
+type getDeletedUsersRequestDoc struct {
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authentication string
+
+	// Must be "true" to list the deleted users.
+	//
+	// In: query
+	Deleted string `json:"deleted"`
+}
+
+// Contains data about the deleted users and when they were deleted.
+//
+// swagger:response getDeletedUsersResponse
+type getDeletedUsersResponseDoc struct {
+
+	// In: body
+	Body []getDeletedUserResponse
+}
+
+// ----- End Documentation Generation Types --------------
+
+// GetDeletedUsers is the handler that retrieves all deleted model.Users as serialized JSON.
+//
+// swagger:route GET /api/v1/users?deleted=true Users getDeletedUsersRequest
+//
+// Gets all users that were deleted and can still be restored.
+//
+// This will retrieve the deleted users from storage in the order they were deleted. This route is only available to
+// Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: getDeletedUsersResponse
+func (handler *UserHandler) GetDeletedUsers(w http.ResponseWriter, r *http.Request) error {
+	users, err := handler.userClient.GetDeleted(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve deleted users")
+	}
+
+	responseObjects := make([]*getDeletedUserResponse, len(users))
+	for i, user := range users {
+		responseObjects[i] = &getDeletedUserResponse{
+			Username:    user.Username,
+			DisplayName: user.DisplayName,
+			Permission:  user.Permission,
+			DeletedAt:   user.DeletedAt,
+		}
+	}
+
+	rawResponse, err := json.Marshal(responseObjects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal users")
+	}
+
+	fmt.Fprint(w, string(rawResponse))
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// deleteUserRequestDoc is for swagger generation only.
+// swagger:parameters deleteUserRequest
+type deleteUserRequestDoc struct {
+	// Username of the user.
+	//
+	// In: path
+	Username string `json:"username"`
+
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authentication string
+}
+
+// ----- End Documentation Generation Types --------------
+
+// DeleteUser is the handler that removes a model.User from storage.
+//
+// swagger:route DELETE /api/v1/users/{username} Users deleteUserRequest
+//
+// Deletes a user from the server.
+//
+// This will delete an existing user. The bids of the user are kept and the username cannot be used by anyone else
+// until the user is purged. This route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: noBody
+//    404: errorMessage
+func (handler *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) error {
+	username := mux.Vars(r)["username"]
+	r = r.WithContext(log.WithFields(r.Context(), "username", username))
+	err := handler.userClient.Delete(r.Context(), username)
+	if err != nil {
+		if errors.Is(err, storage.ErrEntityNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			response, marshalErr := json.Marshal(newErrorResponse("user does not exist"))
+			if marshalErr != nil {
+				return errors.Wrap(marshalErr, "could not marshal error response")
+			}
+			fmt.Fprint(w, string(response))
+			return nil
+		}
+		return errors.Wrap(err, "could not delete user")
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
+// restoreUserRequestDoc is for swagger generation only.
+// swagger:parameters restoreUserRequest
+type restoreUserRequestDoc struct {
+	// Username of the deleted user.
+	//
+	// In: path
+	Username string `json:"username"`
+
+	// Expected to be "Bearer <auth_token>"
+	//
+	// In: header
+	Authentication string
+}
+
+// ----- End Documentation Generation Types --------------
+
+// RestoreUser is the handler that brings back a deleted model.User.
+//
+// swagger:route POST /api/v1/users/{username}/restore Users restoreUserRequest
+//
+// Restores a deleted user.
+//
+// This will restore a user that was deleted so they can log in again. This route is only available to Admin users.
+//
+//  Produces:
+//  - application/json
+//
+//  Schemes: http
+//
+//  Security:
+//    api_key:
+//
+//  Responses:
+//    200: noBody
+//    404: errorMessage
+func (handler *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) error {
+	username := mux.Vars(r)["username"]
+	r = r.WithContext(log.WithFields(r.Context(), "username", username))
+	err := handler.userClient.Restore(r.Context(), username)
+	if err != nil {
+		if errors.Is(err, storage.ErrEntityNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			response, marshalErr := json.Marshal(newErrorResponse("user does not exist"))
+			if marshalErr != nil {
+				return errors.Wrap(marshalErr, "could not marshal error response")
+			}
+			fmt.Fprint(w, string(response))
+			return nil
+		}
+		return errors.Wrap(err, "could not restore user")
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// ----- Start Documentation Generation Types --------------
+
 // Contains data about the user and how to identify them.
 //
 // swagger:parameters postUserRequest
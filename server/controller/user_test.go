@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/server/controller/auth"
@@ -218,6 +219,95 @@ func (ts *userHandlerTestSuite) TestPostLogin400OnPasswordMismatch() {
 	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
 }
 
+func (ts *userHandlerTestSuite) TestGetDeletedUsersRetrievesDeletedUsers() {
+	deletedAt := time.Date(2021, time.October, 3, 12, 0, 0, 0, time.UTC)
+	users := []*model.User{
+		{Username: "hunter", DisplayName: "Hunter", Permission: model.PermissionLevelBidder, DeletedAt: deletedAt},
+		{Username: "other", Permission: model.PermissionLevelBidder, DeletedAt: deletedAt.Add(time.Hour)},
+	}
+	ts.userStoreMock.On("GetDeleted", mock.AnythingOfType("*context.valueCtx")).Return(users, nil)
+
+	r := makeAuthenticatedRequest(ts.T(), http.MethodGet, fmt.Sprintf("%s/api/v1/users?deleted=true", ts.server.URL), nil, &model.User{
+		Permission: model.PermissionLevelAdmin,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	rawResponse, err := io.ReadAll(response.Body)
+	ts.Require().NoError(err)
+	var returnedUsers []*getDeletedUserResponse
+	ts.Require().NoError(json.Unmarshal(rawResponse, &returnedUsers))
+
+	ts.Require().Len(returnedUsers, len(users))
+	for i, user := range users {
+		ts.Require().EqualValues(user.Username, returnedUsers[i].Username)
+		ts.Require().EqualValues(user.DisplayName, returnedUsers[i].DisplayName)
+		ts.Require().EqualValues(user.Permission, returnedUsers[i].Permission)
+		ts.Require().True(user.DeletedAt.Equal(returnedUsers[i].DeletedAt))
+	}
+}
+
+func (ts *userHandlerTestSuite) TestGetDeletedUsers403OnBidderRequest() {
+	r := makeAuthenticatedRequest(ts.T(), http.MethodGet, fmt.Sprintf("%s/api/v1/users?deleted=true", ts.server.URL), nil, &model.User{
+		Permission: model.PermissionLevelBidder,
+	})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *userHandlerTestSuite) TestDeleteUserRemovesUserFromStorage() {
+	ts.userStoreMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), "hunter").Return(nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, "hunter", &model.User{Permission: model.PermissionLevelAdmin})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+}
+
+func (ts *userHandlerTestSuite) TestDeleteUser404WhenUserDoesNotExist() {
+	ts.userStoreMock.On("Delete", mock.AnythingOfType("*context.valueCtx"), "hunter").Return(storage.ErrEntityNotFound)
+
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, "hunter", &model.User{Permission: model.PermissionLevelAdmin})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *userHandlerTestSuite) TestDeleteUser403OnBidderRequest() {
+	r := ts.makeAuthenticatedRequest(http.MethodDelete, "hunter", &model.User{Permission: model.PermissionLevelBidder})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
+func (ts *userHandlerTestSuite) TestRestoreUserRestoresUser() {
+	ts.userStoreMock.On("Restore", mock.AnythingOfType("*context.valueCtx"), "hunter").Return(nil)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "hunter/restore", &model.User{Permission: model.PermissionLevelAdmin})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+}
+
+func (ts *userHandlerTestSuite) TestRestoreUser404WhenUserNotDeleted() {
+	ts.userStoreMock.On("Restore", mock.AnythingOfType("*context.valueCtx"), "hunter").Return(storage.ErrEntityNotFound)
+
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "hunter/restore", &model.User{Permission: model.PermissionLevelAdmin})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *userHandlerTestSuite) TestRestoreUser403OnBidderRequest() {
+	r := ts.makeAuthenticatedRequest(http.MethodPost, "hunter/restore", &model.User{Permission: model.PermissionLevelBidder})
+	response, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(http.StatusForbidden, response.StatusCode)
+}
+
 func (ts *userHandlerTestSuite) fullPath(path string) string {
 	return fmt.Sprintf("%s/api/v1/users/%s", ts.server.URL, path)
 }
@@ -16,11 +16,31 @@ type SocketCommand string
 const (
 	SocketCommandUnknown  SocketCommand = "Unknown"
 	SocketCommandPlaceBid SocketCommand = "PlaceBid"
+	SocketCommandOutbid   SocketCommand = "Outbid"
+
+	SocketCommandItemCreated   SocketCommand = "ItemCreated"
+	SocketCommandItemUpdated   SocketCommand = "ItemUpdated"
+	SocketCommandItemDeleted   SocketCommand = "ItemDeleted"
+	SocketCommandAuctionClosed SocketCommand = "AuctionClosed"
+
+	SocketCommandPledgeRecorded SocketCommand = "PledgeRecorded"
+	SocketCommandPledgeRemoved  SocketCommand = "PledgeRemoved"
+	SocketCommandRaffleDrawn    SocketCommand = "RaffleDrawn"
 )
 
 var socketCommandMapping = map[string]SocketCommand{
 	strings.ToLower(string(SocketCommandUnknown)):  SocketCommandUnknown,
 	strings.ToLower(string(SocketCommandPlaceBid)): SocketCommandPlaceBid,
+	strings.ToLower(string(SocketCommandOutbid)):   SocketCommandOutbid,
+
+	strings.ToLower(string(SocketCommandItemCreated)):   SocketCommandItemCreated,
+	strings.ToLower(string(SocketCommandItemUpdated)):   SocketCommandItemUpdated,
+	strings.ToLower(string(SocketCommandItemDeleted)):   SocketCommandItemDeleted,
+	strings.ToLower(string(SocketCommandAuctionClosed)): SocketCommandAuctionClosed,
+
+	strings.ToLower(string(SocketCommandPledgeRecorded)): SocketCommandPledgeRecorded,
+	strings.ToLower(string(SocketCommandPledgeRemoved)):  SocketCommandPledgeRemoved,
+	strings.ToLower(string(SocketCommandRaffleDrawn)):    SocketCommandRaffleDrawn,
 }
 
 func (sc SocketCommand) MarshalText() ([]byte, error) {
@@ -3,6 +3,8 @@ package ws
 import (
 	"encoding/json"
 
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/pkg/errors"
 )
 
@@ -21,19 +23,35 @@ func (cm *commandMessage) UnmarshalText(raw []byte) error {
 		return errors.Wrap(err, "unable to unmarshal commandMessage")
 	}
 
+	if proxy.Command != SocketCommandPlaceBid {
+		return errors.Errorf("unrecognized command '%s'", proxy.Command)
+	}
+
 	cm.Command = proxy.Command
-	if proxy.Command == SocketCommandPlaceBid {
-		var payload commandMessagePlaceBid
-		err = json.Unmarshal(proxy.RawPayload, &payload)
-		if err != nil {
-			return errors.Wrapf(err, "unable to unmarshal payload to '%s'", proxy.Command)
+	cm.RawPayload = proxy.RawPayload
+	return nil
+}
+
+// decodePayload decodes the raw payload into the Payload of the command using the form of the protocol. Bid amounts in
+// v1 are whole units of the currency of the event.
+func (cm *commandMessage) decodePayload(protocol string, currency string) error {
+	var payload commandMessagePlaceBid
+	if protocol == ProtocolV1 {
+		var payloadV1 commandMessagePlaceBidV1
+		if err := json.Unmarshal(cm.RawPayload, &payloadV1); err != nil {
+			return errors.Wrapf(err, "unable to unmarshal payload to '%s'", cm.Command)
 		}
 
-		cm.Payload = &payload
-	} else {
-		return errors.Errorf("unrecognized command '%s'", proxy.Command)
+		payload = commandMessagePlaceBid{
+			ItemID:    payloadV1.ItemID,
+			ItemName:  payloadV1.ItemName,
+			BidAmount: model.WholeUnits(payloadV1.BidAmount, currency),
+		}
+	} else if err := json.Unmarshal(cm.RawPayload, &payload); err != nil {
+		return errors.Wrapf(err, "unable to unmarshal payload to '%s'", cm.Command)
 	}
 
+	cm.Payload = &payload
 	return nil
 }
 
@@ -54,7 +72,14 @@ func (cm commandMessage) MarshalText() ([]byte, error) {
 }
 
 type commandMessagePlaceBid struct {
-	ItemName  string `json:"itemName"`
+	ItemID    uint64      `json:"itemId,omitempty"`
+	ItemName  string      `json:"itemName,omitempty"`
+	BidAmount model.Money `json:"bidAmount"`
+}
+
+type commandMessagePlaceBidV1 struct {
+	ItemID    uint64 `json:"itemId,omitempty"`
+	ItemName  string `json:"itemName,omitempty"`
 	BidAmount int    `json:"bidAmount"`
 }
 
@@ -64,3 +89,56 @@ type responseMessage struct {
 	Message    string        `json:"message,omitempty"`
 	Data       interface{}   `json:"data,omitempty"`
 }
+
+type responseMessageOutbidData struct {
+	ItemName   string      `json:"itemName"`
+	LotName    string      `json:"lotName,omitempty"`
+	CurrentBid model.Money `json:"currentBid"`
+	MinimumBid model.Money `json:"minimumBid"`
+}
+
+type responseMessagePlaceBidDataV1 struct {
+	ItemID   uint64 `json:"itemId"`
+	ItemName string `json:"itemName"`
+	Username string `json:"username"`
+	Amount   int64  `json:"amount"`
+	LotID    uint64 `json:"lotId,omitempty"`
+	LotName  string `json:"lotName,omitempty"`
+}
+
+type responseMessageOutbidDataV1 struct {
+	ItemName   string `json:"itemName"`
+	LotName    string `json:"lotName,omitempty"`
+	CurrentBid int64  `json:"currentBid"`
+	MinimumBid int64  `json:"minimumBid"`
+}
+
+// The v1 forms of the events below write their amounts in place of the amounts of the embedded event, since a field
+// of the outer struct hides the field of an embedded struct with the same JSON name.
+
+type itemCreatedDataV1 struct {
+	*events.ItemCreated
+	FairMarketValue int64 `json:"fairMarketValue,omitempty"`
+}
+
+type itemUpdatedDataV1 struct {
+	*events.ItemUpdated
+	FairMarketValue int64 `json:"fairMarketValue,omitempty"`
+}
+
+type pledgeRecordedDataV1 struct {
+	*events.PledgeRecorded
+	Amount int64 `json:"amount"`
+	donationTotalsV1
+}
+
+type pledgeRemovedDataV1 struct {
+	*events.PledgeRemoved
+	Amount int64 `json:"amount"`
+	donationTotalsV1
+}
+
+type donationTotalsV1 struct {
+	LevelTotal int64 `json:"levelTotal"`
+	Total      int64 `json:"total"`
+}
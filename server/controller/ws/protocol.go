@@ -0,0 +1,112 @@
+package ws
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+)
+
+const (
+	// ProtocolV1 is the original message format. Clients that do not request a subprotocol are assumed to use it.
+	ProtocolV1 = "biddr.v1"
+
+	// ProtocolV2 writes amounts of money as a decimal string followed by the currency, e.g. "12.50 USD", where v1 uses
+	// whole units of the currency of the event.
+	ProtocolV2 = "biddr.v2"
+
+	// closeCodeUnsupportedProtocol is sent when none of the subprotocols requested by the client are supported. Codes
+	// 4000-4999 are reserved for applications by RFC 6455.
+	closeCodeUnsupportedProtocol = 4001
+)
+
+var supportedProtocols = []string{ProtocolV1, ProtocolV2}
+
+// schemas contains the JSON Schemas of every command and response for each supported protocol version.
+//
+//go:embed schemas
+var schemas embed.FS
+
+// negotiateProtocol picks the first requested subprotocol that is supported. The second return value is false when
+// none of the requested subprotocols are supported.
+func negotiateProtocol(requested []string) (string, bool) {
+	if len(requested) == 0 {
+		return ProtocolV1, true
+	}
+
+	for _, protocol := range requested {
+		for _, supported := range supportedProtocols {
+			if protocol == supported {
+				return protocol, true
+			}
+		}
+	}
+
+	return requested[0], false
+}
+
+func unsupportedProtocolReason() string {
+	return fmt.Sprintf("unsupported protocol version, supported: %s", strings.Join(supportedProtocols, ","))
+}
+
+// convertData converts the data of a response to the form of the protocol. The data is unchanged unless the protocol is
+// v1, which writes amounts of money as whole units of the currency. A minimum bid is rounded up so it still wins.
+func convertData(protocol string, data interface{}) interface{} {
+	if protocol != ProtocolV1 {
+		return data
+	}
+
+	switch data := data.(type) {
+	case *events.BidPlaced:
+		return &responseMessagePlaceBidDataV1{
+			ItemID:   data.ItemID,
+			ItemName: data.ItemName,
+			Username: data.Username,
+			Amount:   data.Amount.Units(),
+			LotID:    data.LotID,
+			LotName:  data.LotName,
+		}
+	case *responseMessageOutbidData:
+		return &responseMessageOutbidDataV1{
+			ItemName:   data.ItemName,
+			LotName:    data.LotName,
+			CurrentBid: data.CurrentBid.Units(),
+			MinimumBid: data.MinimumBid.UnitsRoundedUp(),
+		}
+	case *events.ItemCreated:
+		return &itemCreatedDataV1{ItemCreated: data, FairMarketValue: optionalUnits(data.FairMarketValue)}
+	case *events.ItemUpdated:
+		return &itemUpdatedDataV1{ItemUpdated: data, FairMarketValue: optionalUnits(data.FairMarketValue)}
+	case *events.PledgeRecorded:
+		return &pledgeRecordedDataV1{
+			PledgeRecorded:   data,
+			Amount:           data.Amount.Units(),
+			donationTotalsV1: newDonationTotalsV1(data.DonationTotals),
+		}
+	case *events.PledgeRemoved:
+		return &pledgeRemovedDataV1{
+			PledgeRemoved:    data,
+			Amount:           data.Amount.Units(),
+			donationTotalsV1: newDonationTotalsV1(data.DonationTotals),
+		}
+	default:
+		return data
+	}
+}
+
+// optionalUnits returns the whole units of the amount, or 0 so it is left out when there is no amount.
+func optionalUnits(amount *model.Money) int64 {
+	if amount == nil {
+		return 0
+	}
+	return amount.Units()
+}
+
+func newDonationTotalsV1(totals events.DonationTotals) donationTotalsV1 {
+	return donationTotalsV1{
+		LevelTotal: totals.LevelTotal.Units(),
+		Total:      totals.Total.Units(),
+	}
+}
@@ -0,0 +1,93 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateProtocolDefaultsToV1WhenNoneRequested(t *testing.T) {
+	protocol, supported := negotiateProtocol(nil)
+	require.True(t, supported)
+	require.EqualValues(t, ProtocolV1, protocol)
+}
+
+func TestNegotiateProtocolPicksFirstSupportedProtocol(t *testing.T) {
+	protocol, supported := negotiateProtocol([]string{"biddr.v3", ProtocolV2, ProtocolV1})
+	require.True(t, supported)
+	require.EqualValues(t, ProtocolV2, protocol)
+}
+
+func TestNegotiateProtocolRejectsUnknownProtocols(t *testing.T) {
+	protocol, supported := negotiateProtocol([]string{"biddr.v3", "chat"})
+	require.False(t, supported)
+	require.EqualValues(t, "biddr.v3", protocol)
+}
+
+func TestUnsupportedProtocolReasonFitsInCloseFrame(t *testing.T) {
+	require.LessOrEqual(t, len(unsupportedProtocolReason()), 123)
+}
+
+func TestSchemaIndexCoversEverySocketCommand(t *testing.T) {
+	for _, version := range []string{"v1", "v2"} {
+		rawIndex, err := schemas.ReadFile("schemas/" + version + "/index.json")
+		require.NoError(t, err)
+		var index struct {
+			Responses map[string]string `json:"responses"`
+		}
+		require.NoError(t, json.Unmarshal(rawIndex, &index))
+
+		for _, command := range socketCommandMapping {
+			if command == SocketCommandUnknown {
+				continue
+			}
+			require.Contains(t, index.Responses, string(command), version)
+		}
+	}
+}
+
+func TestConvertDataWritesWholeUnitsForV1(t *testing.T) {
+	bid := &events.BidPlaced{ItemName: "Quilt", Amount: model.Money{Amount: 1250, Currency: "USD"}}
+	require.Same(t, bid, convertData(ProtocolV2, bid))
+	require.EqualValues(t, &responseMessagePlaceBidDataV1{ItemName: "Quilt", Amount: 12}, convertData(ProtocolV1, bid))
+}
+
+func TestConvertDataWritesWholeUnitsOfPledgesAndItemsForV1(t *testing.T) {
+	value := model.Money{Amount: 15000, Currency: "USD"}
+	data, err := json.Marshal(convertData(ProtocolV1, &events.ItemCreated{ItemName: "Quilt", FairMarketValue: &value}))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":0,"name":"Quilt","fairMarketValue":150}`, string(data))
+
+	data, err = json.Marshal(convertData(ProtocolV1, &events.ItemUpdated{ItemID: 1, ItemName: "Quilt"}))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "fairMarketValue")
+
+	pledge := &events.PledgeRecorded{
+		LevelName: "School Supplies",
+		Amount:    model.Money{Amount: 10000, Currency: "USD"},
+		DonationTotals: events.DonationTotals{
+			LevelPledges: 2,
+			LevelTotal:   model.Money{Amount: 20000, Currency: "USD"},
+			Pledges:      3,
+			Total:        model.Money{Amount: 70000, Currency: "USD"},
+		},
+	}
+	var v1 map[string]interface{}
+	data, err = json.Marshal(convertData(ProtocolV1, pledge))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &v1))
+	require.EqualValues(t, 100, v1["amount"])
+	require.EqualValues(t, 200, v1["levelTotal"])
+	require.EqualValues(t, 700, v1["total"])
+	require.EqualValues(t, 2, v1["levelPledges"])
+
+	var v2 map[string]interface{}
+	data, err = json.Marshal(convertData(ProtocolV2, pledge))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &v2))
+	require.EqualValues(t, "100.00 USD", v2["amount"])
+	require.EqualValues(t, "700.00 USD", v2["total"])
+}
@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 	"sync"
 	"time"
 
+	"github.com/MMarsolek/AuctionHouse/events"
 	"github.com/MMarsolek/AuctionHouse/log"
 	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/server/controller/auth"
+	"github.com/MMarsolek/AuctionHouse/server/controller/bidding"
 	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
 	"github.com/MMarsolek/AuctionHouse/storage"
 	"github.com/gorilla/mux"
@@ -26,29 +29,59 @@ func newErrorMessage(command SocketCommand, statusCode int, message string, args
 	}
 }
 
+// maxPendingMessages is the most outbid notices delivered to a user when they connect. The latest are delivered.
+const maxPendingMessages = 20
+
 type sessionData struct {
 	ws         *websocket.Conn
 	ctx        context.Context
 	username   string
 	permission model.PermissionLevel
+	protocol   string
+	writeLock  sync.Mutex
+}
+
+// writeJSON serializes writes to the websocket since the connection does not support concurrent writers. The data of
+// the message is converted to the protocol of the session.
+func (data *sessionData) writeJSON(message *responseMessage) error {
+	converted := *message
+	converted.Data = convertData(data.protocol, message.Data)
+
+	data.writeLock.Lock()
+	defer data.writeLock.Unlock()
+
+	return data.ws.WriteJSON(&converted)
 }
 
-// Handler handles websocket connections and allows for clients to be updated when a bid is placed.
+// Handler handles websocket connections and allows for clients to be updated when anything in the auction changes.
 type Handler struct {
 	upgrader           *websocket.Upgrader
 	currentConnections map[string]*sessionData
 	rwLock             sync.RWMutex
 
-	userClient storage.UserClient
-	itemClient storage.AuctionItemClient
-	bidClient  storage.AuctionBidClient
+	userClient   storage.UserClient
+	itemClient   storage.AuctionItemClient
+	bidClient    storage.AuctionBidClient
+	outbidClient storage.OutbidNoticeClient
+	transactor   storage.Transactor
+	eventBus     *events.Bus
+	currency     string
+	locale       string
 }
 
-// NewHandler constructs a Handler.
+// NewHandler constructs a Handler. Bids are placed using the transactor. Successful bids are published on the eventBus
+// and StartRelay must be called for events to be broadcast to the connected clients. Outbid bidders are told about the
+// notices in the outbidClient. Every bid must be in the currency of the event and amounts in messages are formatted for
+// its locale.
 func NewHandler(
 	userClient storage.UserClient,
 	itemClient storage.AuctionItemClient,
 	bidClient storage.AuctionBidClient,
+	outbidClient storage.OutbidNoticeClient,
+	transactor storage.Transactor,
+	eventBus *events.Bus,
+	currency string,
+	locale string,
 ) *Handler {
 	return &Handler{
 		upgrader: &websocket.Upgrader{
@@ -59,11 +92,18 @@ func NewHandler(
 		userClient:         userClient,
 		itemClient:         itemClient,
 		bidClient:          bidClient,
+		outbidClient:       outbidClient,
+		transactor:         transactor,
+		eventBus:           eventBus,
+		currency:           currency,
+		locale:             locale,
 	}
 }
 
 // RegisterRoutes registers the routes to establish a websocket connection.
 func (handler *Handler) RegisterRoutes(rootRouter *mux.Router) {
+	rootRouter.HandleFunc("/ws/schemas/{version}/{schema}", handler.ServeSchema).Methods(http.MethodGet)
+
 	wsRouter := rootRouter.PathPrefix("/ws").Subrouter()
 	wsRouter.Use(middleware.VerifyAuthToken)
 
@@ -108,15 +148,17 @@ type commandMessageDoc struct {
 // swagger:model commandPlaceBid
 type commandMessagePlaceBidDoc struct {
 
-	// Specifies the item to place a bid on.
-	//
-	// Required: true
+	// Specifies the ID of the item to place a bid on. Takes precedence over the item name.
+	ItemID uint64 `json:"itemId"`
+
+	// Specifies the name of the item to place a bid on. Only used when no item ID is given.
 	ItemName string `json:"itemName"`
 
-	// Specifies the amount to bid.
+	// Specifies the amount to bid as a decimal string followed by the currency of the auction, e.g. "12.50 USD". In
+	// biddr.v1 it is an integer number of whole units of the currency instead.
 	//
 	// Required: true
-	BidAmount int `json:"bidAmount"`
+	BidAmount string `json:"bidAmount"`
 }
 
 // WSResponseMessage
@@ -150,6 +192,11 @@ type responseMessageDoc struct {
 // swagger:model responseMessagePlaceBidData
 type responseMessagePlaceBidDataDoc struct {
 
+	// The ID of the item that was just bid on.
+	//
+	// Required: true
+	ItemID uint64 `json:"itemId"`
+
 	// The name of the item that was just bid on.
 	//
 	// Required: true
@@ -160,14 +207,102 @@ type responseMessagePlaceBidDataDoc struct {
 	// Required: true
 	Username string `json:"username"`
 
-	// The amount the new bid is going for.
+	// The amount the new bid is going for as a decimal string followed by the currency, e.g. "12.50 USD". In biddr.v1
+	// it is an integer number of whole units of the currency instead.
+	//
+	// Required: true
+	NewBid string `json:"amount"`
+
+	// The ID of the lot that was just bid on when the item is sold in a lot. The bid is on the whole lot.
+	LotID uint64 `json:"lotId,omitempty"`
+
+	// The name of the lot that was just bid on when the item is sold in a lot.
+	LotName string `json:"lotName,omitempty"`
+}
+
+// WSResponseMessageOutbidData
+//
+// Defines the additional data sent privately to a user when they are no longer the highest bidder on an item.
+//
+// swagger:model responseMessageOutbidData
+type responseMessageOutbidDataDoc struct {
+
+	// The name of the item the user was outbid on.
 	//
 	// Required: true
-	NewBid int `json:"amount"`
+	ItemName string `json:"itemName"`
+
+	// The name of the lot the user was outbid on when the item is sold in a lot.
+	LotName string `json:"lotName,omitempty"`
+
+	// The amount of the bid that replaced the user's bid as a decimal string followed by the currency, e.g. "12.50 USD".
+	// In biddr.v1 it is an integer number of whole units of the currency instead.
+	//
+	// Required: true
+	CurrentBid string `json:"currentBid"`
+
+	// The smallest amount the user needs to bid to win a place again, which is one cent or other minor unit of the
+	// currency more than the lowest winning bid. In biddr.v1 it is an integer number of whole units of the currency
+	// rounded up instead.
+	//
+	// Required: true
+	MinimumBid string `json:"minimumBid"`
+}
+
+// schemaRequestDoc is for swagger generation only.
+// swagger:parameters schemaRequest
+type schemaRequestDoc struct {
+	// The protocol version, such as "v1".
+	//
+	// In: path
+	Version string `json:"version"`
+
+	// The name of the schema file. The file index.json lists every schema for the version.
+	//
+	// In: path
+	Schema string `json:"schema"`
+}
+
+// A JSON Schema document.
+// swagger:response schemaResponse
+type schemaResponseDoc struct {
+
+	// In: body
+	Body interface{}
 }
 
 // ----- End Documentation Generation Types --------------
 
+// ServeSchema returns the JSON Schema of a websocket command or response.
+//
+// swagger:route GET /api/ws/schemas/{version}/{schema} WebSockets schemaRequest
+//
+// Retrieves a JSON Schema for the websocket protocol.
+//
+// This will retrieve the JSON Schema describing a command or response of the websocket protocol version. Start with
+// index.json to find the schema of every command and response.
+//
+//  Produces:
+//  - application/schema+json
+//
+//  Schemes: http
+//
+//  Responses:
+//    200: schemaResponse
+//    404: noBody
+func (handler *Handler) ServeSchema(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	rawSchema, err := schemas.ReadFile(path.Join("schemas", vars["version"], vars["schema"]))
+	if err != nil {
+		log.Info(r.Context(), "schema not found", "version", vars["version"], "schema", vars["schema"])
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(rawSchema)
+}
+
 // ServeWS upgrades the connection to a websocket and listens for different JSON commands.
 //
 // swagger:route GET /api/v1/ws WebSockets wsRequest
@@ -181,6 +316,21 @@ type responseMessagePlaceBidDataDoc struct {
 // All messages are sent back via websocket as the model WSResponseMessage. The Data field inside of the model varies
 // based on command. For example, a PlaceBid command defines the Data field as a WSResponseMessagePlaceBidData model.
 //
+// Bids placed through either the websocket or the REST API are broadcast to every client, as are the ItemCreated,
+// ItemUpdated and ItemDeleted commands whenever an item changes and the AuctionClosed command when the closing time of
+// an item passes. Pledges to the fund-a-need appeal are broadcast as PledgeRecorded and PledgeRemoved commands along
+// with the new totals of the appeal, and the winners of every raffle are announced with a RaffleDrawn command.
+//
+// When another user places a higher bid, the previous highest bidder is sent an Outbid command with a
+// WSResponseMessageOutbidData model. Outbid messages for users that are not connected to any instance of the server are
+// delivered when they connect, keeping only the latest one for each item.
+//
+// The protocol version is negotiated with the Sec-WebSocket-Protocol header. Amounts of money are integer whole units
+// of the currency in "biddr.v1", which is assumed when the header is omitted, and decimal strings followed by the
+// currency, e.g. "12.50 USD", in "biddr.v2". If none of the requested versions are supported, the connection
+// is closed with the close code 4001. JSON Schemas for every command and response are available at
+// /api/ws/schemas/{version}/index.json.
+//
 //  Produces:
 //  - application/json
 //
@@ -192,42 +342,81 @@ type responseMessagePlaceBidDataDoc struct {
 //  Responses:
 //    101: wsConnection
 func (handler *Handler) ServeWS(w http.ResponseWriter, r *http.Request) {
-	ws, err := handler.upgrader.Upgrade(w, r, nil)
+	requestedProtocols := websocket.Subprotocols(r)
+	protocol, supported := negotiateProtocol(requestedProtocols)
+	responseHeader := http.Header{}
+	if len(requestedProtocols) > 0 {
+		// The requested protocol is echoed back even when unsupported so the client accepts the connection and is able
+		// to read the reason it is being closed.
+		responseHeader.Set("Sec-WebSocket-Protocol", protocol)
+	}
+
+	ws, err := handler.upgrader.Upgrade(w, r, responseHeader)
 	if err != nil {
 		log.Error(r.Context(), "unable to upgrade websocket", "err", err)
+		return
 	}
 	defer ws.Close()
-	r = r.WithContext(log.WithFields(r.Context(), "client", getIdentifierFromWebsocket(ws)))
+	r = r.WithContext(log.WithFields(r.Context(), "client", getIdentifierFromWebsocket(ws), "protocol", protocol))
+
+	if !supported {
+		log.Info(r.Context(), "unsupported websocket protocol", "requestedProtocols", requestedProtocols)
+		err = ws.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(closeCodeUnsupportedProtocol, unsupportedProtocolReason()),
+			time.Now().Add(time.Second),
+		)
+		if err != nil {
+			log.Error(r.Context(), "unable to close websocket", "err", err)
+		}
+		return
+	}
 
+	session := &sessionData{
+		ws:         ws,
+		ctx:        r.Context(),
+		username:   auth.ExtractUsername(r.Context()),
+		permission: auth.ExtractPermission(r.Context()),
+		protocol:   protocol,
+	}
 	func() {
 		handler.rwLock.Lock()
 		defer handler.rwLock.Unlock()
 
-		handler.currentConnections[getIdentifierFromWebsocket(ws)] = &sessionData{
-			ws:         ws,
-			ctx:        r.Context(),
-			username:   auth.ExtractUsername(r.Context()),
-			permission: auth.ExtractPermission(r.Context()),
-		}
+		handler.currentConnections[getIdentifierFromWebsocket(ws)] = session
 	}()
+	handler.deliverPending(session)
 
-	running := true
-	for running {
-		var message commandMessage
-		err = ws.ReadJSON(&message)
+	defer func() {
+		handler.rwLock.Lock()
+		defer handler.rwLock.Unlock()
+
+		delete(handler.currentConnections, getIdentifierFromWebsocket(ws))
+	}()
+
+	for {
+		_, rawMessage, err := ws.ReadMessage()
 		if err != nil {
-			var closeErr *websocket.CloseError
-			if errors.As(err, &closeErr) {
-				func() {
-					handler.rwLock.Lock()
-					defer handler.rwLock.Unlock()
-					running = false
-
-					delete(handler.currentConnections, getIdentifierFromWebsocket(ws))
-				}()
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Error(r.Context(), "websocket closed unexpectedly", "err", err)
 			}
+			return
+		}
+
+		var message commandMessage
+		err = json.Unmarshal(rawMessage, &message)
+		if err == nil {
+			err = message.decodePayload(protocol, handler.currency)
+		}
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrUnknownCurrency) {
+			log.Info(r.Context(), "invalid bid amount from client", "err", err)
+			session.writeJSON(newErrorMessage(SocketCommandPlaceBid, http.StatusBadRequest,
+				"bid amount must be a string such as %q", "12.50 USD"))
+			continue
+		}
+		if err != nil {
 			log.Error(r.Context(), "unable to read JSON from client", "err", err)
-			ws.WriteJSON(newErrorMessage(SocketCommandUnknown, http.StatusBadRequest, "invalid request format"))
+			session.writeJSON(newErrorMessage(SocketCommandUnknown, http.StatusBadRequest, "invalid request format"))
 			continue
 		}
 
@@ -239,7 +428,7 @@ func (handler *Handler) ServeWS(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if err != nil {
-			ws.WriteJSON(newErrorMessage(message.Command, http.StatusInternalServerError, "unhandled error: %v", err))
+			session.writeJSON(newErrorMessage(message.Command, http.StatusInternalServerError, "unhandled error: %v", err))
 			log.Error(r.Context(), "unhandled error", "command", message.Command, "err", err)
 		}
 	}
@@ -247,67 +436,207 @@ func (handler *Handler) ServeWS(w http.ResponseWriter, r *http.Request) {
 
 // handlePlaceBid handles incoming commands where the user wants to place a bid.
 func (handler *Handler) handlePlaceBid(data *sessionData, command *commandMessagePlaceBid) error {
-	user, err := handler.userClient.Get(data.ctx, data.username)
-	if err != nil {
-		return errors.Wrap(err, "could not retrieve user")
+	_, err := bidding.Place(data.ctx, handler.transactor, handler.eventBus, handler.currency, &bidding.Bid{
+		Username: data.username,
+		FindItem: func(ctx context.Context, itemClient storage.AuctionItemClient) (*model.AuctionItem, error) {
+			if command.ItemID != 0 {
+				return itemClient.GetByID(ctx, command.ItemID)
+			}
+			return itemClient.Get(ctx, command.ItemName)
+		},
+		Amount: command.BidAmount,
+	})
+	if err == nil {
+		return nil
 	}
 
-	item, err := handler.itemClient.Get(data.ctx, command.ItemName)
-	if err != nil {
-		if errors.Is(err, storage.ErrEntityNotFound) {
-			if writeErr := data.ws.WriteJSON(newErrorMessage(
-				SocketCommandPlaceBid,
-				http.StatusNotFound,
-				"could not find item '%s'",
-				command.ItemName,
-			)); writeErr != nil {
-				return errors.Wrap(writeErr, "unable to write to client")
+	var message *responseMessage
+	switch {
+	case errors.Is(err, bidding.ErrWrongCurrency):
+		message = newErrorMessage(SocketCommandPlaceBid, http.StatusBadRequest,
+			"%s, which is %s", bidding.ErrWrongCurrency.Error(), handler.currency)
+	case errors.Is(err, bidding.ErrInvalidAmount):
+		message = newErrorMessage(SocketCommandPlaceBid, http.StatusBadRequest, bidding.ErrInvalidAmount.Error())
+	case errors.Is(err, bidding.ErrBidderNotFound):
+		message = newErrorMessage(SocketCommandPlaceBid, http.StatusNotFound, "could not find user '%s'", data.username)
+	case errors.Is(err, bidding.ErrItemNotFound) && command.ItemID != 0:
+		message = newErrorMessage(SocketCommandPlaceBid, http.StatusNotFound, "could not find item %d", command.ItemID)
+	case errors.Is(err, bidding.ErrItemNotFound):
+		message = newErrorMessage(SocketCommandPlaceBid, http.StatusNotFound, "could not find item '%s'", command.ItemName)
+	case errors.Is(err, storage.ErrBidTooLow):
+		message = newErrorMessage(SocketCommandPlaceBid, http.StatusBadRequest, "bid amount is too low")
+	case errors.Is(err, storage.ErrOverSpendingLimit):
+		message = newErrorMessage(SocketCommandPlaceBid, http.StatusPaymentRequired, "bid would exceed your spending limit")
+	case errors.Is(err, bidding.ErrClosed):
+		message = newErrorMessage(SocketCommandPlaceBid, http.StatusConflict, bidding.ErrClosed.Error())
+	default:
+		return err
+	}
+	return errors.Wrap(data.writeJSON(message), "unable to write to client")
+}
+
+// StartRelay subscribes to the event bus and broadcasts every event to all connected clients until the context is
+// done. The subscription is established before this returns so no events published afterwards are missed.
+func (handler *Handler) StartRelay(ctx context.Context) {
+	subscription := handler.eventBus.Subscribe()
+	go func() {
+		var lastEventID uint64
+		for {
+			select {
+			case <-ctx.Done():
+				subscription.Close()
+				return
+			case event, ok := <-subscription.Events():
+				if !ok {
+					// The relay fell behind and was dropped by the bus so pick back up after the last relayed event.
+					var missed []*events.Event
+					missed, subscription = handler.eventBus.SubscribeAfter(lastEventID)
+					for _, missedEvent := range missed {
+						handler.relayEvent(ctx, missedEvent)
+						lastEventID = missedEvent.ID
+					}
+					continue
+				}
+
+				handler.relayEvent(ctx, event)
+				lastEventID = event.ID
 			}
-			return nil
 		}
-		return errors.Wrap(err, "could not retrieve item")
+	}()
+}
+
+// relayEvent converts the event into a message and sends it to every connected client.
+func (handler *Handler) relayEvent(ctx context.Context, event *events.Event) {
+	message := &responseMessage{
+		StatusCode: http.StatusOK,
+		Data:       event.Payload,
 	}
 
-	_, err = handler.bidClient.PlaceBid(data.ctx, user, item, command.BidAmount)
-	if err != nil {
-		if errors.Is(err, storage.ErrBidTooLow) {
-			if writeErr := data.ws.WriteJSON(newErrorMessage(
-				SocketCommandPlaceBid,
-				http.StatusBadRequest,
-				"bid amount is too low",
-			)); writeErr != nil {
-				return errors.Wrap(writeErr, "unable to write to client")
+	switch payload := event.Payload.(type) {
+	case *events.BidPlaced:
+		message.Command = SocketCommandPlaceBid
+		message.StatusCode = http.StatusCreated
+		message.Message = "New bid placed"
+		defer func() {
+			if payload.PreviousBidder != "" && payload.PreviousBidder != payload.Username {
+				handler.notifyOutbid(ctx, payload)
 			}
-			return nil
+		}()
+	case *events.ItemCreated:
+		message.Command = SocketCommandItemCreated
+		message.StatusCode = http.StatusCreated
+		message.Message = "New item added"
+	case *events.ItemUpdated:
+		message.Command = SocketCommandItemUpdated
+		message.Message = "Item updated"
+	case *events.ItemDeleted:
+		message.Command = SocketCommandItemDeleted
+		message.Message = "Item removed"
+	case *events.AuctionClosed:
+		message.Command = SocketCommandAuctionClosed
+		message.Message = "Bidding closed"
+	case *events.PledgeRecorded:
+		message.Command = SocketCommandPledgeRecorded
+		message.StatusCode = http.StatusCreated
+		message.Message = "New pledge recorded"
+	case *events.PledgeRemoved:
+		message.Command = SocketCommandPledgeRemoved
+		message.Message = "Pledge removed"
+	case *events.RaffleDrawn:
+		message.Command = SocketCommandRaffleDrawn
+		message.Message = "Raffle winners drawn"
+	default:
+		log.Error(ctx, "unrecognized event", "eventType", event.Type)
+		return
+	}
+
+	handler.rwLock.RLock()
+	defer handler.rwLock.RUnlock()
+
+	for _, connection := range handler.currentConnections {
+		if err := connection.writeJSON(message); err != nil {
+			log.Error(connection.ctx, "unable to write to client", "command", message.Command, "err", err)
 		}
-		return errors.Wrap(err, "unable to make bid")
 	}
+}
 
-	func() {
+// notifyOutbid privately tells every session of the previous bidder of the bid that their bid is no longer winning. When
+// the item is sold in a lot the bidder is told about the lot instead.
+//
+// Every instance sharing the backplane relays the bid. The instances the bidder is connected to remove the outbid
+// notice stored with the bid once they told the bidder, so the notice is only delivered when they connect if no
+// instance could tell them.
+func (handler *Handler) notifyOutbid(ctx context.Context, bid *events.BidPlaced) {
+	message := handler.outbidMessage(&model.OutbidNotice{
+		Username:   bid.PreviousBidder,
+		ItemName:   bid.ItemName,
+		LotName:    bid.LotName,
+		CurrentBid: bid.Amount,
+		MinimumBid: bid.MinimumBid,
+	})
+
+	delivered := func() bool {
 		handler.rwLock.RLock()
 		defer handler.rwLock.RUnlock()
 
+		delivered := false
 		for _, connection := range handler.currentConnections {
-			if err = connection.ws.WriteJSON(responseMessage{
-				Command:    SocketCommandPlaceBid,
-				StatusCode: http.StatusCreated,
-				Message:    "New bid placed",
-				Data: struct {
-					ItemName string `json:"itemName"`
-					Username string `json:"username"`
-					NewBid   int    `json:"amount"`
-				}{
-					ItemName: item.Name,
-					Username: user.Username,
-					NewBid:   command.BidAmount,
-				},
-			}); err != nil {
-				log.Error(connection.ctx, "unable to write to client", "command", SocketCommandPlaceBid)
+			if connection.username != bid.PreviousBidder {
+				continue
+			}
+
+			if err := connection.writeJSON(message); err != nil {
+				log.Error(connection.ctx, "unable to write to client", "command", SocketCommandOutbid, "err", err)
+				continue
 			}
+			delivered = true
 		}
+		return delivered
 	}()
 
-	return nil
+	if delivered && bid.NoticeID != 0 {
+		if err := handler.outbidClient.Remove(ctx, bid.NoticeID); err != nil {
+			log.Error(ctx, "unable to remove delivered outbid notice", "outbidUser", bid.PreviousBidder, "err", err)
+		}
+	}
+}
+
+// deliverPending tells the user of the session about the outbid notices that no instance could deliver while they were
+// not connected. Only the latest maxPendingMessages notices are delivered.
+func (handler *Handler) deliverPending(session *sessionData) {
+	notices, err := handler.outbidClient.Take(session.ctx, session.username)
+	if err != nil {
+		log.Error(session.ctx, "unable to take pending outbid notices", "err", err)
+		return
+	}
+	if len(notices) > maxPendingMessages {
+		notices = notices[len(notices)-maxPendingMessages:]
+	}
+
+	for _, notice := range notices {
+		if err = session.writeJSON(handler.outbidMessage(notice)); err != nil {
+			log.Error(session.ctx, "unable to deliver pending message", "command", SocketCommandOutbid, "err", err)
+		}
+	}
+}
+
+// outbidMessage tells the user of the notice that their bid is no longer winning.
+func (handler *Handler) outbidMessage(notice *model.OutbidNotice) *responseMessage {
+	outbidOn := notice.ItemName
+	if notice.LotName != "" {
+		outbidOn = notice.LotName
+	}
+	return &responseMessage{
+		Command:    SocketCommandOutbid,
+		StatusCode: http.StatusOK,
+		Message:    fmt.Sprintf("You have been outbid on '%s' with %s", outbidOn, notice.CurrentBid.Format(handler.locale)),
+		Data: &responseMessageOutbidData{
+			ItemName:   notice.ItemName,
+			LotName:    notice.LotName,
+			CurrentBid: notice.CurrentBid,
+			MinimumBid: notice.MinimumBid,
+		},
+	}
 }
 
 func (handler *Handler) getSessionData(ws *websocket.Conn) *sessionData {
@@ -1,17 +1,23 @@
 package ws
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/MMarsolek/AuctionHouse/events"
 	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/server/controller/auth"
 	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
 	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/memory"
 	"github.com/MMarsolek/AuctionHouse/storage/mocks"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
@@ -27,36 +33,82 @@ const (
 type handlerTestSuite struct {
 	suite.Suite
 
-	server   *httptest.Server
-	handler  *Handler
-	userMock *mocks.UserClient
-	itemMock *mocks.AuctionItemClient
-	bidMock  *mocks.AuctionBidClient
+	cancel         context.CancelFunc
+	server         *httptest.Server
+	handler        *Handler
+	userMock       *mocks.UserClient
+	itemMock       *mocks.AuctionItemClient
+	bidMock        *mocks.AuctionBidClient
+	lotMock        *mocks.LotClient
+	outbidMock     *mocks.OutbidNoticeClient
+	transactorMock *mocks.Transactor
+
+	// pendingNotices are the outbid notices of each user taken when they connect.
+	pendingNotices map[string][]*model.OutbidNotice
 }
 
 func (ts *handlerTestSuite) SetupSuite() {
 	router := mux.NewRouter().PathPrefix("/api").Subrouter()
 	ts.server = httptest.NewServer(middleware.RemoveTrailingSlash(router))
-	ts.handler = NewHandler(ts.userMock, ts.itemMock, ts.bidMock)
+	ts.handler = NewHandler(
+		ts.userMock,
+		ts.itemMock,
+		ts.bidMock,
+		ts.outbidMock,
+		ts.transactorMock,
+		events.NewBus(events.DefaultHistorySize),
+		"USD",
+		"en-US",
+	)
 	ts.handler.RegisterRoutes(router)
+
+	var ctx context.Context
+	ctx, ts.cancel = context.WithCancel(context.Background())
+	ts.handler.StartRelay(ctx)
 }
 
 func (ts *handlerTestSuite) SetupTest() {
 	ts.userMock = new(mocks.UserClient)
 	ts.itemMock = new(mocks.AuctionItemClient)
 	ts.bidMock = new(mocks.AuctionBidClient)
+	ts.lotMock = new(mocks.LotClient)
+	ts.outbidMock = new(mocks.OutbidNoticeClient)
+	ts.pendingNotices = make(map[string][]*model.OutbidNotice)
+	ts.outbidMock.On("Take", mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, username string) []*model.OutbidNotice {
+			return ts.pendingNotices[username]
+		},
+		nil,
+	).Maybe()
+	ts.transactorMock = new(mocks.Transactor)
+	ts.transactorMock.On("RunInTx", mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, fn func(context.Context, *storage.Clients) error) error {
+			return fn(ctx, &storage.Clients{
+				Users:   ts.userMock,
+				Items:   ts.itemMock,
+				Bids:    ts.bidMock,
+				Lots:    ts.lotMock,
+				Outbids: ts.outbidMock,
+			})
+		},
+	).Maybe()
 	ts.handler.userClient = ts.userMock
 	ts.handler.itemClient = ts.itemMock
 	ts.handler.bidClient = ts.bidMock
+	ts.handler.outbidClient = ts.outbidMock
+	ts.handler.transactor = ts.transactorMock
 }
 
 func (ts *handlerTestSuite) TearDownTest() {
 	ts.userMock.AssertExpectations(ts.T())
 	ts.itemMock.AssertExpectations(ts.T())
 	ts.bidMock.AssertExpectations(ts.T())
+	ts.lotMock.AssertExpectations(ts.T())
+	ts.outbidMock.AssertExpectations(ts.T())
 }
 
 func (ts *handlerTestSuite) TearDownSuite() {
+	ts.cancel()
 	ts.server.Close()
 }
 
@@ -77,9 +129,10 @@ func (ts *handlerTestSuite) TestServeWSCanPlaceBid() {
 	item := &model.AuctionItem{
 		Name: testItemName,
 	}
-	bidAmount := 1000
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
 	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
 	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(item, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
 	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
 	ws := ts.createWebsocket()
 	defer ws.Close()
@@ -100,7 +153,114 @@ func (ts *handlerTestSuite) TestServeWSCanPlaceBid() {
 	result := response.Data.(map[string]interface{})
 	ts.Require().EqualValues(result["itemName"], item.Name)
 	ts.Require().EqualValues(result["username"], user.Username)
-	ts.Require().EqualValues(result["amount"], bidAmount)
+	ts.Require().EqualValues("10.00 USD", result["amount"])
+}
+
+func (ts *handlerTestSuite) TestServeWSCanPlaceBidByItemID() {
+	user := &model.User{
+		Username: testUserName,
+	}
+	item := &model.AuctionItem{
+		ID:   42,
+		Name: testItemName,
+	}
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
+	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
+	ts.itemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
+	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemID:    item.ID,
+			BidAmount: bidAmount,
+		},
+	}))
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	ts.Require().IsType((map[string]interface{})(nil), response.Data)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues(item.ID, result["itemId"])
+	ts.Require().EqualValues(item.Name, result["itemName"])
+}
+
+func (ts *handlerTestSuite) TestServeWSRejectsBidInOtherCurrency() {
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemName:  testItemName,
+			BidAmount: model.Money{Amount: 1000, Currency: "EUR"},
+		},
+	}))
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+	ts.Require().EqualValues("bid is not in the currency of the auction, which is USD", response.Message)
+	ts.bidMock.AssertNotCalled(ts.T(), "PlaceBid", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ts *handlerTestSuite) TestServeWSPlacesBidInWholeUnitsOverV1() {
+	user := &model.User{Username: testUserName}
+	item := &model.AuctionItem{Name: testItemName}
+	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
+	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(item, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
+	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+	ws, _ := ts.dialWebsocket(testUserName, nil)
+	defer ws.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBidV1{
+			ItemName:  item.Name,
+			BidAmount: 10,
+		},
+	}))
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues(10, result["amount"])
+}
+
+func (ts *handlerTestSuite) TestServeWSRejectsMoneyStringOverV1() {
+	ws, _ := ts.dialWebsocket(testUserName, []string{ProtocolV1})
+	defer ws.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemName:  testItemName,
+			BidAmount: model.Money{Amount: 1000, Currency: "USD"},
+		},
+	}))
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode)
+	ts.bidMock.AssertNotCalled(ts.T(), "PlaceBid", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ts *handlerTestSuite) TestServeWSRejectsBidAmountThatIsNotMoney() {
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	for _, amount := range []string{`10`, `"10.00"`, `"10.001 USD"`} {
+		message := `{"command":"PlaceBid","payload":{"itemName":"` + testItemName + `","bidAmount":` + amount + `}}`
+		ts.Require().NoError(ws.WriteMessage(websocket.TextMessage, []byte(message)))
+
+		var response responseMessage
+		ts.Require().NoError(ws.ReadJSON(&response))
+		ts.Require().EqualValues(http.StatusBadRequest, response.StatusCode, amount)
+	}
 }
 
 func (ts *handlerTestSuite) TestServeWSReturnsErrorJSONOnItemNotFound() {
@@ -110,9 +270,10 @@ func (ts *handlerTestSuite) TestServeWSReturnsErrorJSONOnItemNotFound() {
 	item := &model.AuctionItem{
 		Name: testItemName,
 	}
-	bidAmount := 1000
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
 	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
 	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(item, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
 	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, storage.ErrBidTooLow)
 	ws := ts.createWebsocket()
 	defer ws.Close()
@@ -132,6 +293,64 @@ func (ts *handlerTestSuite) TestServeWSReturnsErrorJSONOnItemNotFound() {
 	ts.Require().Nil(response.Data)
 }
 
+func (ts *handlerTestSuite) TestServeWSReturnsErrorJSONWhenItemHasClosed() {
+	user := &model.User{
+		Username: testUserName,
+	}
+	item := &model.AuctionItem{
+		Name:     testItemName,
+		ClosesAt: time.Now().Add(-time.Minute),
+	}
+	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
+	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(item, nil)
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemName:  item.Name,
+			BidAmount: model.Money{Amount: 5000, Currency: "USD"},
+		},
+	}))
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	ts.Require().EqualValues(http.StatusConflict, response.StatusCode)
+	ts.Require().EqualValues("bidding on the item has closed", response.Message)
+	ts.bidMock.AssertNotCalled(ts.T(), "PlaceBid", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ts *handlerTestSuite) TestServeWSReturnsErrorJSONOnBidOverSpendingLimit() {
+	user := &model.User{
+		Username: testUserName,
+	}
+	item := &model.AuctionItem{
+		Name: testItemName,
+	}
+	bidAmount := model.Money{Amount: 5000, Currency: "USD"}
+	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
+	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(item, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
+	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, storage.ErrOverSpendingLimit)
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemName:  item.Name,
+			BidAmount: bidAmount,
+		},
+	}))
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	ts.Require().EqualValues(http.StatusPaymentRequired, response.StatusCode)
+	ts.Require().EqualValues("bid would exceed your spending limit", response.Message)
+	ts.Require().Nil(response.Data)
+}
+
 func (ts *handlerTestSuite) TestServeWSReturnsErrorJSONOnBidBeingTooLow() {
 	user := &model.User{
 		Username: testUserName,
@@ -139,7 +358,7 @@ func (ts *handlerTestSuite) TestServeWSReturnsErrorJSONOnBidBeingTooLow() {
 	item := &model.AuctionItem{
 		Name: testItemName,
 	}
-	bidAmount := 1000
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
 	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
 	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(nil, storage.ErrEntityNotFound)
 	ws := ts.createWebsocket()
@@ -160,6 +379,26 @@ func (ts *handlerTestSuite) TestServeWSReturnsErrorJSONOnBidBeingTooLow() {
 	ts.Require().Nil(response.Data)
 }
 
+func (ts *handlerTestSuite) TestServeWSReturnsNotFoundWhenBidderDoesNotExist() {
+	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(nil, storage.ErrEntityNotFound)
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemName:  testItemName,
+			BidAmount: model.Money{Amount: 1000, Currency: "USD"},
+		},
+	}))
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+	ts.Require().EqualValues(fmt.Sprintf("could not find user '%s'", testUserName), response.Message)
+	ts.Require().Nil(response.Data)
+}
+
 func (ts *handlerTestSuite) TestServeWSFailsOnUnknownCommand() {
 	ws := ts.createWebsocket()
 	defer ws.Close()
@@ -181,9 +420,10 @@ func (ts *handlerTestSuite) TestServeWSBroadcastsBidsToAllClients() {
 	item := &model.AuctionItem{
 		Name: testItemName,
 	}
-	bidAmount := 1000
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
 	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
 	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(item, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
 	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
 	sockets := make([]*websocket.Conn, 10)
 	for i := range sockets {
@@ -209,7 +449,7 @@ func (ts *handlerTestSuite) TestServeWSBroadcastsBidsToAllClients() {
 		result := response.Data.(map[string]interface{})
 		ts.Require().EqualValues(result["itemName"], item.Name)
 		ts.Require().EqualValues(result["username"], user.Username)
-		ts.Require().EqualValues(result["amount"], bidAmount)
+		ts.Require().EqualValues("10.00 USD", result["amount"])
 	}
 }
 
@@ -220,9 +460,10 @@ func (ts *handlerTestSuite) TestServeWSNoLongerBroadcastsToDisconnectedClient()
 	item := &model.AuctionItem{
 		Name: testItemName,
 	}
-	bidAmount := 1000
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
 	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
 	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(item, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
 	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
 	sockets := make([]*websocket.Conn, 10)
 	prematureCloseIndex := 7
@@ -255,7 +496,7 @@ func (ts *handlerTestSuite) TestServeWSNoLongerBroadcastsToDisconnectedClient()
 		result := response.Data.(map[string]interface{})
 		ts.Require().EqualValues(result["itemName"], item.Name)
 		ts.Require().EqualValues(result["username"], user.Username)
-		ts.Require().EqualValues(result["amount"], bidAmount)
+		ts.Require().EqualValues("10.00 USD", result["amount"])
 	}
 
 	unused := make(map[string]interface{})
@@ -264,21 +505,705 @@ func (ts *handlerTestSuite) TestServeWSNoLongerBroadcastsToDisconnectedClient()
 	ts.Require().ErrorIs(err, net.ErrClosed)
 }
 
+func (ts *handlerTestSuite) TestServeWSPublishesBidsToEventBus() {
+	user := &model.User{
+		Username: testUserName,
+	}
+	item := &model.AuctionItem{
+		Name: testItemName,
+	}
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
+	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
+	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(item, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
+	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+	sub := ts.handler.eventBus.Subscribe()
+	defer sub.Close()
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemName:  item.Name,
+			BidAmount: bidAmount,
+		},
+	}))
+
+	event := <-sub.Events()
+	ts.Require().EqualValues(events.TypeBidPlaced, event.Type)
+	ts.Require().Equal(&events.BidPlaced{
+		ItemName: item.Name,
+		Username: user.Username,
+		Amount:   bidAmount,
+	}, event.Payload)
+
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+}
+
+func (ts *handlerTestSuite) TestServeWSPublishesLotOfItemInLot() {
+	user := &model.User{
+		Username: testUserName,
+	}
+	item := &model.AuctionItem{
+		ID:    2,
+		Name:  testItemName,
+		LotID: 7,
+	}
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
+	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
+	ts.itemMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.ID).Return(item, nil)
+	ts.lotMock.On("GetByID", mock.AnythingOfType("*context.valueCtx"), item.LotID).Return(&model.Lot{
+		ID:   item.LotID,
+		Name: "Dinner and a Show",
+	}, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return(nil, storage.ErrEntityNotFound)
+	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+	sub := ts.handler.eventBus.Subscribe()
+	defer sub.Close()
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemID:    item.ID,
+			BidAmount: bidAmount,
+		},
+	}))
+
+	event := <-sub.Events()
+	ts.Require().Equal(&events.BidPlaced{
+		ItemID:   item.ID,
+		ItemName: item.Name,
+		Username: user.Username,
+		Amount:   bidAmount,
+		LotID:    item.LotID,
+		LotName:  "Dinner and a Show",
+	}, event.Payload)
+
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues(item.LotID, result["lotId"])
+	ts.Require().EqualValues("Dinner and a Show", result["lotName"])
+}
+
+func (ts *handlerTestSuite) TestServeWSSendsOutbidToPreviousHighestBidder() {
+	user := &model.User{
+		Username: testUserName,
+	}
+	previousBidder := &model.User{
+		Username: "previousBidder",
+	}
+	item := &model.AuctionItem{
+		Name: testItemName,
+	}
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
+	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
+	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(item, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return([]*model.AuctionBid{{
+		BidAmount: model.Money{Amount: 500, Currency: "USD"},
+		Bidder:    previousBidder,
+		Item:      item,
+	}}, nil).Once()
+	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return([]*model.AuctionBid{{
+		BidAmount: bidAmount,
+		Bidder:    user,
+		Item:      item,
+	}}, nil).Once()
+	ts.outbidMock.On("Add", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(nil)
+	ws := ts.createWebsocket()
+	defer ws.Close()
+	previousBidderWS := ts.createWebsocketForUser(previousBidder.Username)
+	defer previousBidderWS.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemName:  item.Name,
+			BidAmount: bidAmount,
+		},
+	}))
+
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+
+	ts.Require().NoError(previousBidderWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	response = responseMessage{}
+	ts.Require().NoError(previousBidderWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandOutbid, response.Command)
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	ts.Require().IsType((map[string]interface{})(nil), response.Data)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues(item.Name, result["itemName"])
+	ts.Require().EqualValues("10.00 USD", result["currentBid"])
+	ts.Require().EqualValues("10.01 USD", result["minimumBid"])
+}
+
+func (ts *handlerTestSuite) TestServeWSSendsMinimumBidThatBeatsLowestWinningBid() {
+	user := &model.User{Username: testUserName}
+	second := &model.User{Username: "secondBidder"}
+	third := &model.User{Username: "thirdBidder"}
+	item := &model.AuctionItem{Name: testItemName, Quantity: 2}
+	bidAmount := model.Money{Amount: 1000, Currency: "USD"}
+	ts.userMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testUserName).Return(user, nil)
+	ts.itemMock.On("Get", mock.AnythingOfType("*context.valueCtx"), testItemName).Return(item, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return([]*model.AuctionBid{
+		{BidAmount: model.Money{Amount: 800, Currency: "USD"}, Bidder: second, Item: item},
+		{BidAmount: model.Money{Amount: 500, Currency: "USD"}, Bidder: third, Item: item},
+	}, nil).Once()
+	ts.bidMock.On("PlaceBid", mock.AnythingOfType("*context.valueCtx"), user, item, bidAmount).Return(nil, nil)
+	ts.bidMock.On("GetWinningBids", mock.AnythingOfType("*context.valueCtx"), item).Return([]*model.AuctionBid{
+		{BidAmount: bidAmount, Bidder: user, Item: item},
+		{BidAmount: model.Money{Amount: 800, Currency: "USD"}, Bidder: second, Item: item},
+	}, nil).Once()
+	ts.outbidMock.On("Add", mock.AnythingOfType("*context.valueCtx"), mock.Anything).Return(nil)
+	ws := ts.createWebsocket()
+	defer ws.Close()
+	thirdWS := ts.createWebsocketForUser(third.Username)
+	defer thirdWS.Close()
+
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemName:  item.Name,
+			BidAmount: bidAmount,
+		},
+	}))
+
+	var response responseMessage
+	ts.Require().NoError(thirdWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	response = responseMessage{}
+	ts.Require().NoError(thirdWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandOutbid, response.Command)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues("10.00 USD", result["currentBid"])
+	ts.Require().EqualValues("8.01 USD", result["minimumBid"])
+}
+
+func (ts *handlerTestSuite) TestServeWSDeliversPendingOutbidNoticesWhenUserConnects() {
+	username := "offlineBidder"
+	ts.pendingNotices[username] = []*model.OutbidNotice{
+		{
+			Username:   username,
+			ItemName:   testItemName,
+			CurrentBid: model.Money{Amount: 200, Currency: "USD"},
+			MinimumBid: model.Money{Amount: 201, Currency: "USD"},
+		},
+		{
+			Username:   username,
+			ItemName:   "item2",
+			LotName:    "Dinner and a Show",
+			CurrentBid: model.Money{Amount: 300, Currency: "USD"},
+			MinimumBid: model.Money{Amount: 301, Currency: "USD"},
+		},
+	}
+
+	offlineWS := ts.createWebsocketForUser(username)
+	defer offlineWS.Close()
+
+	var response responseMessage
+	ts.Require().NoError(offlineWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandOutbid, response.Command)
+	ts.Require().EqualValues("You have been outbid on 'item1' with $2.00", response.Message)
+	ts.Require().IsType((map[string]interface{})(nil), response.Data)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues(testItemName, result["itemName"])
+	ts.Require().EqualValues("2.01 USD", result["minimumBid"])
+
+	response = responseMessage{}
+	ts.Require().NoError(offlineWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandOutbid, response.Command)
+	ts.Require().EqualValues("You have been outbid on 'Dinner and a Show' with $3.00", response.Message)
+}
+
+func (ts *handlerTestSuite) TestServeWSDeliversOnlyLatestPendingOutbidNotices() {
+	username := "absentBidder"
+	for i := 0; i < maxPendingMessages+5; i++ {
+		ts.pendingNotices[username] = append(ts.pendingNotices[username], &model.OutbidNotice{
+			Username:   username,
+			ItemName:   fmt.Sprintf("item%d", i),
+			CurrentBid: model.Money{Amount: 200, Currency: "USD"},
+		})
+	}
+
+	absentWS := ts.createWebsocketForUser(username)
+	defer absentWS.Close()
+	ts.handler.eventBus.Publish(events.TypeItemDeleted, &events.ItemDeleted{
+		ItemName: "item",
+	})
+
+	var itemNames []interface{}
+	for {
+		var response responseMessage
+		ts.Require().NoError(absentWS.ReadJSON(&response))
+		if response.Command != SocketCommandOutbid {
+			break
+		}
+		itemNames = append(itemNames, response.Data.(map[string]interface{})["itemName"])
+	}
+	ts.Require().Len(itemNames, maxPendingMessages)
+	ts.Require().EqualValues("item5", itemNames[0])
+	ts.Require().EqualValues(fmt.Sprintf("item%d", maxPendingMessages+4), itemNames[len(itemNames)-1])
+}
+
+func (ts *handlerTestSuite) TestRelayRemovesOutbidNoticeOnceDelivered() {
+	username := "connectedBidder"
+	outbidWS := ts.createWebsocketForUser(username)
+	defer outbidWS.Close()
+	ts.outbidMock.On("Remove", mock.Anything, uint64(12)).Return(nil).Once()
+
+	ts.handler.relayEvent(context.Background(), &events.Event{
+		Type: events.TypeBidPlaced,
+		Payload: &events.BidPlaced{
+			ItemName:       testItemName,
+			Username:       testUserName,
+			Amount:         model.Money{Amount: 200, Currency: "USD"},
+			PreviousBidder: username,
+			NoticeID:       12,
+		},
+	})
+
+	var response responseMessage
+	ts.Require().NoError(outbidWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	ts.Require().NoError(outbidWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandOutbid, response.Command)
+}
+
+func (ts *handlerTestSuite) TestRelayKeepsOutbidNoticeOfUserWhoIsNotConnected() {
+	ts.handler.relayEvent(context.Background(), &events.Event{
+		Type: events.TypeBidPlaced,
+		Payload: &events.BidPlaced{
+			ItemName:       testItemName,
+			Username:       testUserName,
+			Amount:         model.Money{Amount: 200, Currency: "USD"},
+			PreviousBidder: "elsewhereBidder",
+			NoticeID:       12,
+		},
+	})
+
+	ts.outbidMock.AssertNotCalled(ts.T(), "Remove", mock.Anything, mock.Anything)
+}
+
+func (ts *handlerTestSuite) TestRelayTellsOutbidUserAboutLot() {
+	username := "lotBidder"
+	outbidWS := ts.createWebsocketForUser(username)
+	defer outbidWS.Close()
+	ts.handler.eventBus.Publish(events.TypeBidPlaced, &events.BidPlaced{
+		ItemName:       testItemName,
+		Username:       testUserName,
+		Amount:         model.Money{Amount: 200, Currency: "USD"},
+		LotID:          7,
+		LotName:        "Dinner and a Show",
+		PreviousBidder: username,
+	})
+
+	var response responseMessage
+	ts.Require().NoError(outbidWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	response = responseMessage{}
+	ts.Require().NoError(outbidWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandOutbid, response.Command)
+	ts.Require().EqualValues("You have been outbid on 'Dinner and a Show' with $2.00", response.Message)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues(testItemName, result["itemName"])
+	ts.Require().EqualValues("Dinner and a Show", result["lotName"])
+}
+
+func (ts *handlerTestSuite) TestRelaySendsWholeUnitsOverV1() {
+	username := "v1Bidder"
+	outbidWS, _ := ts.dialWebsocket(username, nil)
+	defer outbidWS.Close()
+	ts.handler.eventBus.Publish(events.TypeBidPlaced, &events.BidPlaced{
+		ItemName:       testItemName,
+		Username:       testUserName,
+		Amount:         model.Money{Amount: 1250, Currency: "USD"},
+		PreviousBidder: username,
+		MinimumBid:     model.Money{Amount: 1251, Currency: "USD"},
+	})
+
+	var response responseMessage
+	ts.Require().NoError(outbidWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	ts.Require().EqualValues(12, response.Data.(map[string]interface{})["amount"])
+	response = responseMessage{}
+	ts.Require().NoError(outbidWS.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandOutbid, response.Command)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues(12, result["currentBid"])
+	ts.Require().EqualValues(13, result["minimumBid"])
+}
+
+func (ts *handlerTestSuite) TestRelayDoesNotSendOutbidWhenOutbiddingSelf() {
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.handler.eventBus.Publish(events.TypeBidPlaced, &events.BidPlaced{
+		ItemName:       testItemName,
+		Username:       testUserName,
+		Amount:         model.Money{Amount: 200, Currency: "USD"},
+		PreviousBidder: testUserName,
+	})
+	ts.handler.eventBus.Publish(events.TypeItemDeleted, &events.ItemDeleted{
+		ItemName: testItemName,
+	})
+
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandItemDeleted, response.Command)
+}
+
+func (ts *handlerTestSuite) TestRelayBroadcastsItemEventsToAllClients() {
+	sockets := make([]*websocket.Conn, 3)
+	for i := range sockets {
+		sockets[i] = ts.createWebsocket()
+		defer sockets[i].Close()
+	}
+
+	ts.handler.eventBus.Publish(events.TypeItemCreated, &events.ItemCreated{
+		ItemName:    testItemName,
+		Description: "description",
+	})
+
+	for _, ws := range sockets {
+		var response responseMessage
+		ts.Require().NoError(ws.ReadJSON(&response))
+		ts.Require().EqualValues(SocketCommandItemCreated, response.Command)
+		ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+		ts.Require().IsType((map[string]interface{})(nil), response.Data)
+		result := response.Data.(map[string]interface{})
+		ts.Require().EqualValues(testItemName, result["name"])
+		ts.Require().EqualValues("description", result["description"])
+	}
+}
+
+func (ts *handlerTestSuite) TestRelayBroadcastsClosingOfItems() {
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.handler.eventBus.Publish(events.TypeAuctionClosed, &events.AuctionClosed{
+		ItemID:   1,
+		ItemName: testItemName,
+		ClosedAt: time.Date(2030, time.June, 1, 20, 0, 0, 0, time.UTC),
+	})
+
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandAuctionClosed, response.Command)
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues(testItemName, result["name"])
+	ts.Require().EqualValues("2030-06-01T20:00:00Z", result["closedAt"])
+}
+
+func (ts *handlerTestSuite) TestRelayBroadcastsPledgeTotals() {
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.handler.eventBus.Publish(events.TypePledgeRecorded, &events.PledgeRecorded{
+		PledgeID:  2,
+		LevelID:   1,
+		LevelName: "School Supplies",
+		Username:  testUserName,
+		Amount:    model.Money{Amount: 10000, Currency: "USD"},
+		DonationTotals: events.DonationTotals{
+			LevelPledges: 2,
+			LevelTotal:   model.Money{Amount: 20000, Currency: "USD"},
+			Pledges:      3,
+			Total:        model.Money{Amount: 70000, Currency: "USD"},
+		},
+	})
+	ts.handler.eventBus.Publish(events.TypePledgeRemoved, &events.PledgeRemoved{
+		PledgeID:  2,
+		LevelID:   1,
+		LevelName: "School Supplies",
+		Username:  testUserName,
+		Amount:    model.Money{Amount: 10000, Currency: "USD"},
+		DonationTotals: events.DonationTotals{
+			LevelPledges: 1,
+			LevelTotal:   model.Money{Amount: 10000, Currency: "USD"},
+			Pledges:      2,
+			Total:        model.Money{Amount: 60000, Currency: "USD"},
+		},
+	})
+
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPledgeRecorded, response.Command)
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+	ts.Require().IsType((map[string]interface{})(nil), response.Data)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues("School Supplies", result["levelName"])
+	ts.Require().EqualValues(testUserName, result["username"])
+	ts.Require().EqualValues("100.00 USD", result["amount"])
+	ts.Require().EqualValues("200.00 USD", result["levelTotal"])
+	ts.Require().EqualValues("700.00 USD", result["total"])
+
+	response = responseMessage{}
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandPledgeRemoved, response.Command)
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	result = response.Data.(map[string]interface{})
+	ts.Require().EqualValues(1, result["levelPledges"])
+	ts.Require().EqualValues("600.00 USD", result["total"])
+}
+
+func (ts *handlerTestSuite) TestRelayAnnouncesRaffleWinners() {
+	ws := ts.createWebsocket()
+	defer ws.Close()
+
+	ts.handler.eventBus.Publish(events.TypeRaffleDrawn, &events.RaffleDrawn{
+		RaffleID:   3,
+		RaffleName: "Quilt",
+		Winners:    []events.RaffleWinner{{Ticket: 7, Username: testUserName, DisplayName: "Test User"}},
+	})
+
+	var response responseMessage
+	ts.Require().NoError(ws.ReadJSON(&response))
+	ts.Require().EqualValues(SocketCommandRaffleDrawn, response.Command)
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+	result := response.Data.(map[string]interface{})
+	ts.Require().EqualValues("Quilt", result["raffleName"])
+	ts.Require().Equal([]interface{}{
+		map[string]interface{}{"ticket": float64(7), "username": testUserName, "displayName": "Test User"},
+	}, result["winners"])
+}
+
+func (ts *handlerTestSuite) TestServeWSNegotiatesRequestedProtocol() {
+	ws, response := ts.dialWebsocket(testUserName, []string{"biddr.v0", ProtocolV1})
+	defer ws.Close()
+	ts.Require().EqualValues(http.StatusSwitchingProtocols, response.StatusCode)
+	ts.Require().EqualValues(ProtocolV1, ws.Subprotocol())
+}
+
+func (ts *handlerTestSuite) TestServeWSClosesUnsupportedProtocol() {
+	ws, response := ts.dialWebsocket(testUserName, []string{"biddr.v99"})
+	defer ws.Close()
+	ts.Require().EqualValues(http.StatusSwitchingProtocols, response.StatusCode)
+
+	_, _, err := ws.ReadMessage()
+	ts.Require().Error(err)
+	var closeErr *websocket.CloseError
+	ts.Require().ErrorAs(err, &closeErr)
+	ts.Require().EqualValues(closeCodeUnsupportedProtocol, closeErr.Code)
+	ts.Require().Contains(closeErr.Text, ProtocolV1)
+}
+
+func (ts *handlerTestSuite) TestServeSchemaReturnsEverySchemaInIndex() {
+	var index struct {
+		Protocol  string            `json:"protocol"`
+		Commands  map[string]string `json:"commands"`
+		Responses map[string]string `json:"responses"`
+	}
+	for version, protocol := range map[string]string{"v1": ProtocolV1, "v2": ProtocolV2} {
+		ts.Require().NoError(json.Unmarshal(ts.getSchema(version, "index.json"), &index))
+		ts.Require().EqualValues(protocol, index.Protocol)
+
+		for _, files := range []map[string]string{index.Commands, index.Responses} {
+			for _, file := range files {
+				var schema map[string]interface{}
+				ts.Require().NoError(json.Unmarshal(ts.getSchema(version, file), &schema), file)
+				ts.Require().EqualValues(file, schema["$id"])
+			}
+		}
+	}
+}
+
+func (ts *handlerTestSuite) TestServeSchema404OnUnknownSchema() {
+	response, err := http.Get(fmt.Sprintf("%s/api/ws/schemas/v1/missing.json", ts.server.URL))
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusNotFound, response.StatusCode)
+}
+
+func (ts *handlerTestSuite) getSchema(version string, schema string) []byte {
+	response, err := http.Get(fmt.Sprintf("%s/api/ws/schemas/%s/%s", ts.server.URL, version, schema))
+	ts.Require().NoError(err)
+	defer response.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, response.StatusCode)
+
+	rawSchema, err := io.ReadAll(response.Body)
+	ts.Require().NoError(err)
+	return rawSchema
+}
+
 func (ts *handlerTestSuite) createWebsocket() *websocket.Conn {
+	return ts.createWebsocketForUser(testUserName)
+}
+
+func (ts *handlerTestSuite) createWebsocketForUser(username string) *websocket.Conn {
+	ws, response := ts.dialWebsocket(username, []string{ProtocolV2})
+	ts.Require().EqualValues(http.StatusSwitchingProtocols, response.StatusCode)
+
+	return ws
+}
+
+func (ts *handlerTestSuite) dialWebsocket(username string, protocols []string) (*websocket.Conn, *http.Response) {
 	token, err := auth.NewToken(&model.User{
-		Username:   testUserName,
+		Username:   username,
 		Permission: model.PermissionLevelBidder,
 	})
 	ts.Require().NoError(err)
-	ws, response, err := websocket.DefaultDialer.Dial(ts.serverURL(), http.Header(map[string][]string{
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = protocols
+	ws, response, err := dialer.Dial(ts.serverURL(), http.Header(map[string][]string{
 		"Authorization": {fmt.Sprintf("Bearer %s", token)},
 	}))
 	ts.Require().NoError(err)
-	ts.Require().EqualValues(http.StatusSwitchingProtocols, response.StatusCode)
 
-	return ws
+	return ws, response
 }
 
 func (ts *handlerTestSuite) serverURL() string {
 	return fmt.Sprintf("ws://%s/api/ws", strings.TrimPrefix(ts.server.URL, "http://"))
 }
+
+// instancesTestSuite runs two instances of the handler that share their storage and a backplane the way several
+// servers behind a load balancer do.
+type instancesTestSuite struct {
+	suite.Suite
+
+	cancel    context.CancelFunc
+	servers   []*httptest.Server
+	handlers  []*Handler
+	bidClient storage.AuctionBidClient
+	item      *model.AuctionItem
+}
+
+func (ts *instancesTestSuite) SetupTest() {
+	var ctx context.Context
+	ctx, ts.cancel = context.WithCancel(context.Background())
+	db := memory.NewDatabase()
+	userClient := memory.NewUserClient(db)
+	itemClient := memory.NewAuctionItemClient(db)
+	ts.bidClient = memory.NewAuctionBidClient(db)
+	for _, username := range []string{"first", "second"} {
+		user := &model.User{Username: username, Permission: model.PermissionLevelBidder}
+		ts.Require().NoError(userClient.Create(ctx, user))
+	}
+	ts.item = &model.AuctionItem{Name: testItemName}
+	ts.Require().NoError(itemClient.Create(ctx, ts.item))
+
+	backplane := events.NewMemoryBackplane()
+	ts.servers = nil
+	ts.handlers = nil
+	for i := 0; i < 2; i++ {
+		eventBus := events.NewBus(events.DefaultHistorySize)
+		ts.Require().NoError(eventBus.Connect(ctx, backplane))
+		handler := NewHandler(
+			userClient,
+			itemClient,
+			ts.bidClient,
+			memory.NewOutbidNoticeClient(db),
+			memory.NewTransactor(db),
+			eventBus,
+			"USD",
+			"en-US",
+		)
+		router := mux.NewRouter().PathPrefix("/api").Subrouter()
+		handler.RegisterRoutes(router)
+		handler.StartRelay(ctx)
+		ts.handlers = append(ts.handlers, handler)
+		ts.servers = append(ts.servers, httptest.NewServer(middleware.RemoveTrailingSlash(router)))
+	}
+}
+
+func (ts *instancesTestSuite) TearDownTest() {
+	for _, server := range ts.servers {
+		server.Close()
+	}
+	ts.cancel()
+}
+
+func TestInstances(t *testing.T) {
+	suite.Run(t, new(instancesTestSuite))
+}
+
+func (ts *instancesTestSuite) TestOutbidDeliveredByOneInstanceIsNotDeliveredAgainByAnother() {
+	ts.placeFirstBid()
+	firstWS := ts.dial(0, "first")
+	secondWS := ts.dial(1, "second")
+	defer secondWS.Close()
+
+	ts.placeBid(secondWS, 1250)
+	ts.Require().EqualValues(SocketCommandPlaceBid, ts.read(firstWS).Command)
+	outbid := ts.read(firstWS)
+	ts.Require().EqualValues(SocketCommandOutbid, outbid.Command)
+	ts.Require().EqualValues("You have been outbid on 'item1' with $12.50", outbid.Message)
+
+	// The relay handles events in order, so the notice is removed once the next event reaches the bidder.
+	ts.handlers[0].eventBus.Publish(events.TypeItemDeleted, &events.ItemDeleted{ItemName: "marker"})
+	ts.Require().EqualValues(SocketCommandItemDeleted, ts.read(firstWS).Command)
+	firstWS.Close()
+
+	reconnectedWS := ts.dial(1, "first")
+	defer reconnectedWS.Close()
+	ts.handlers[1].eventBus.Publish(events.TypeItemDeleted, &events.ItemDeleted{ItemName: "marker"})
+	ts.Require().EqualValues(SocketCommandItemDeleted, ts.read(reconnectedWS).Command)
+}
+
+func (ts *instancesTestSuite) TestOutbidForDisconnectedBidderIsDeliveredByAnotherInstance() {
+	ts.placeFirstBid()
+	secondWS := ts.dial(0, "second")
+	defer secondWS.Close()
+
+	ts.placeBid(secondWS, 1250)
+
+	firstWS := ts.dial(1, "first")
+	defer firstWS.Close()
+	outbid := ts.read(firstWS)
+	ts.Require().EqualValues(SocketCommandOutbid, outbid.Command)
+	ts.Require().EqualValues("You have been outbid on 'item1' with $12.50", outbid.Message)
+	ts.Require().EqualValues("12.51 USD", outbid.Data.(map[string]interface{})["minimumBid"])
+}
+
+// placeFirstBid has the first user bid on the item so the second user can outbid them.
+func (ts *instancesTestSuite) placeFirstBid() {
+	first := &model.User{Username: "first"}
+	_, err := ts.bidClient.PlaceBid(context.Background(), first, ts.item, model.Money{Amount: 1000, Currency: "USD"})
+	ts.Require().NoError(err)
+}
+
+// placeBid bids the amount on the item and waits until the bid was placed.
+func (ts *instancesTestSuite) placeBid(ws *websocket.Conn, amount int64) {
+	ts.Require().NoError(ws.WriteJSON(commandMessage{
+		Command: SocketCommandPlaceBid,
+		Payload: commandMessagePlaceBid{
+			ItemName:  ts.item.Name,
+			BidAmount: model.Money{Amount: amount, Currency: "USD"},
+		},
+	}))
+	response := ts.read(ws)
+	ts.Require().EqualValues(SocketCommandPlaceBid, response.Command)
+	ts.Require().EqualValues(http.StatusCreated, response.StatusCode)
+}
+
+func (ts *instancesTestSuite) read(ws *websocket.Conn) *responseMessage {
+	var response responseMessage
+	ts.Require().NoError(ws.SetReadDeadline(time.Now().Add(5 * time.Second)))
+	ts.Require().NoError(ws.ReadJSON(&response))
+	return &response
+}
+
+// dial connects the user to the instance.
+func (ts *instancesTestSuite) dial(instance int, username string) *websocket.Conn {
+	token, err := auth.NewToken(&model.User{Username: username, Permission: model.PermissionLevelBidder})
+	ts.Require().NoError(err)
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{ProtocolV2}
+	url := fmt.Sprintf("ws://%s/api/ws", strings.TrimPrefix(ts.servers[instance].URL, "http://"))
+	ws, _, err := dialer.Dial(url, http.Header{"Authorization": {fmt.Sprintf("Bearer %s", token)}})
+	ts.Require().NoError(err)
+	return ws
+}
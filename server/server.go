@@ -32,41 +32,107 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/MMarsolek/AuctionHouse/blob"
+	"github.com/MMarsolek/AuctionHouse/events"
 	"github.com/MMarsolek/AuctionHouse/server/controller"
+	"github.com/MMarsolek/AuctionHouse/server/controller/bidding"
 	"github.com/MMarsolek/AuctionHouse/server/controller/middleware"
+	"github.com/MMarsolek/AuctionHouse/server/controller/sse"
 	"github.com/MMarsolek/AuctionHouse/server/controller/ws"
 	"github.com/MMarsolek/AuctionHouse/storage"
 	"github.com/gorilla/mux"
 )
 
+// Settings controls how the server listens for requests and what it serves.
+type Settings struct {
+	// Address is the address to listen on, e.g. ":8080".
+	Address string
+
+	// StaticDir is the directory of the web client. Nothing besides the API is served when this is empty.
+	StaticDir string
+
+	// ReadTimeout and WriteTimeout limit how long reading a request and writing its response may take. There is no
+	// limit when they are 0.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// MaxImageSize is the largest image file in bytes that can be uploaded for an item.
+	MaxImageSize int64
+
+	// Currency is the ISO 4217 code of the currency every bid of the event is in and Locale is the locale amounts of
+	// money are formatted for, e.g. "USD" and "en-US".
+	Currency string
+	Locale   string
+}
+
 func NewAuctionHouseServer(
 	ctx context.Context,
 	userClient storage.UserClient,
 	auctionItemClient storage.AuctionItemClient,
 	auctionBidClient storage.AuctionBidClient,
-	address string,
+	itemImageClient storage.ItemImageClient,
+	categoryClient storage.CategoryClient,
+	lotClient storage.LotClient,
+	donationClient storage.DonationClient,
+	raffleClient storage.RaffleClient,
+	spendingLimitClient storage.SpendingLimitClient,
+	outbidNoticeClient storage.OutbidNoticeClient,
+	transactor storage.Transactor,
+	blobStore blob.Store,
+	eventBus *events.Bus,
+	settings Settings,
 ) *http.Server {
 	router := mux.NewRouter()
 
-	fs := http.FileServer(http.Dir("./dist"))
-
-	setupControllers(router.PathPrefix("/api").Subrouter(), userClient, auctionItemClient, auctionBidClient)
-	router.PathPrefix("/").Handler(http.StripPrefix("/", middleware.CSSHeaderSetter(fs)))
+	setupControllers(
+		ctx,
+		router.PathPrefix("/api").Subrouter(),
+		userClient,
+		auctionItemClient,
+		auctionBidClient,
+		itemImageClient,
+		categoryClient,
+		lotClient,
+		donationClient,
+		raffleClient,
+		spendingLimitClient,
+		outbidNoticeClient,
+		transactor,
+		blobStore,
+		eventBus,
+		settings,
+	)
+	if settings.StaticDir != "" {
+		fs := http.FileServer(http.Dir(settings.StaticDir))
+		router.PathPrefix("/").Handler(http.StripPrefix("/", middleware.CSSHeaderSetter(fs)))
+	}
 
 	return &http.Server{
 		BaseContext:  func(net.Listener) context.Context { return ctx },
 		Handler:      middleware.RemoveTrailingSlash(router),
-		Addr:         address,
-		WriteTimeout: 15 * time.Second,
-		ReadTimeout:  15 * time.Second,
+		Addr:         settings.Address,
+		WriteTimeout: settings.WriteTimeout,
+		ReadTimeout:  settings.ReadTimeout,
 	}
 }
 
 func setupControllers(
+	ctx context.Context,
 	rootRouter *mux.Router,
 	userClient storage.UserClient,
 	itemClient storage.AuctionItemClient,
 	bidClient storage.AuctionBidClient,
+	imageClient storage.ItemImageClient,
+	categoryClient storage.CategoryClient,
+	lotClient storage.LotClient,
+	donationClient storage.DonationClient,
+	raffleClient storage.RaffleClient,
+	spendingLimitClient storage.SpendingLimitClient,
+	outbidNoticeClient storage.OutbidNoticeClient,
+	transactor storage.Transactor,
+	blobStore blob.Store,
+	eventBus *events.Bus,
+	settings Settings,
 ) {
 	rootRouter.Use(middleware.LoggingFields)
 	rootRouter.Use(middleware.PanicHandler)
@@ -74,9 +140,45 @@ func setupControllers(
 	userHandler := controller.NewUserHandler(userClient)
 	userHandler.RegisterRoutes(rootRouter)
 
-	auctionHandler := controller.NewAuctionHandler(userClient, itemClient, bidClient)
-	auctionHandler.RegisterRoutes(rootRouter)
+	sseHandler := sse.NewHandler(eventBus, sseStreamDuration(settings.WriteTimeout))
+	sseHandler.RegisterRoutes(rootRouter)
 
-	websocketHandler := ws.NewHandler(userClient, itemClient, bidClient)
+	websocketHandler := ws.NewHandler(
+		userClient, itemClient, bidClient, outbidNoticeClient, transactor, eventBus, settings.Currency, settings.Locale)
 	websocketHandler.RegisterRoutes(rootRouter)
+	websocketHandler.StartRelay(ctx)
+	bidding.StartClosingAnnouncer(ctx, itemClient, lotClient, eventBus, bidding.DefaultClosingInterval)
+
+	auctionHandler := controller.NewAuctionHandler(userClient, itemClient, bidClient, transactor, eventBus, settings.Currency, settings.Locale)
+	auctionHandler.RegisterRoutes(rootRouter)
+
+	imageHandler := controller.NewImageHandler(itemClient, imageClient, transactor, blobStore, eventBus, settings.MaxImageSize)
+	imageHandler.RegisterRoutes(rootRouter)
+
+	categoryHandler := controller.NewCategoryHandler(categoryClient, transactor, eventBus)
+	categoryHandler.RegisterRoutes(rootRouter)
+
+	lotHandler := controller.NewLotHandler(lotClient, transactor)
+	lotHandler.RegisterRoutes(rootRouter)
+
+	donationHandler := controller.NewDonationHandler(donationClient, transactor, eventBus, settings.Currency)
+	donationHandler.RegisterRoutes(rootRouter)
+
+	raffleHandler := controller.NewRaffleHandler(raffleClient, transactor, eventBus, settings.Currency)
+	raffleHandler.RegisterRoutes(rootRouter)
+
+	spendingLimitHandler := controller.NewSpendingLimitHandler(
+		spendingLimitClient, bidClient, transactor, settings.Currency, settings.Locale)
+	spendingLimitHandler.RegisterRoutes(rootRouter)
+
+	reportHandler := controller.NewReportHandler(itemClient, bidClient, donationClient, settings.Currency)
+	reportHandler.RegisterRoutes(rootRouter)
+}
+
+// sseStreamDuration ends event streams before the write timeout would cut them off.
+func sseStreamDuration(writeTimeout time.Duration) time.Duration {
+	if writeTimeout <= 0 {
+		return sse.DefaultStreamDuration
+	}
+	return writeTimeout * 2 / 3
 }
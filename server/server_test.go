@@ -0,0 +1,224 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/blob"
+	"github.com/MMarsolek/AuctionHouse/events"
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/server/controller"
+	"github.com/MMarsolek/AuctionHouse/server/controller/auth"
+	"github.com/MMarsolek/AuctionHouse/storage/memory"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type auctionHouseServerTestSuite struct {
+	suite.Suite
+
+	cancel context.CancelFunc
+	server *httptest.Server
+	client *http.Client
+}
+
+func (ts *auctionHouseServerTestSuite) SetupTest() {
+	var ctx context.Context
+	ctx, ts.cancel = context.WithCancel(context.Background())
+
+	db := memory.NewDatabase()
+	userClient := memory.NewUserClient(db)
+
+	hashedPassword, err := auth.GenerateEncodedPassword("admin")
+	ts.Require().NoError(err)
+	ts.Require().NoError(userClient.Create(ctx, &model.User{
+		Username:       "admin",
+		HashedPassword: hashedPassword,
+		Permission:     model.PermissionLevelAdmin,
+	}))
+
+	ahServer := NewAuctionHouseServer(
+		ctx,
+		userClient,
+		memory.NewAuctionItemClient(db),
+		memory.NewAuctionBidClient(db),
+		memory.NewItemImageClient(db),
+		memory.NewCategoryClient(db),
+		memory.NewLotClient(db),
+		memory.NewDonationClient(db),
+		memory.NewRaffleClient(db),
+		memory.NewSpendingLimitClient(db),
+		memory.NewOutbidNoticeClient(db),
+		memory.NewTransactor(db),
+		blob.NewFileStore(ts.T().TempDir()),
+		events.NewBus(events.DefaultHistorySize),
+		Settings{MaxImageSize: controller.DefaultMaxImageSize, Currency: model.DefaultCurrency, Locale: model.DefaultLocale},
+	)
+	ts.server = httptest.NewServer(ahServer.Handler)
+	ts.client = &http.Client{}
+}
+
+func (ts *auctionHouseServerTestSuite) TearDownTest() {
+	ts.server.Close()
+	ts.cancel()
+}
+
+func TestAuctionHouseServer(t *testing.T) {
+	suite.Run(t, new(auctionHouseServerTestSuite))
+}
+
+func TestAuctionHouseServerServesStaticDir(t *testing.T) {
+	staticDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(staticDir, "app.js"), []byte("biddr"), 0644))
+
+	db := memory.NewDatabase()
+	ahServer := NewAuctionHouseServer(
+		context.Background(),
+		memory.NewUserClient(db),
+		memory.NewAuctionItemClient(db),
+		memory.NewAuctionBidClient(db),
+		memory.NewItemImageClient(db),
+		memory.NewCategoryClient(db),
+		memory.NewLotClient(db),
+		memory.NewDonationClient(db),
+		memory.NewRaffleClient(db),
+		memory.NewSpendingLimitClient(db),
+		memory.NewOutbidNoticeClient(db),
+		memory.NewTransactor(db),
+		blob.NewFileStore(t.TempDir()),
+		events.NewBus(events.DefaultHistorySize),
+		Settings{StaticDir: staticDir},
+	)
+
+	recorder := httptest.NewRecorder()
+	ahServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/app.js", nil))
+	require.EqualValues(t, http.StatusOK, recorder.Code)
+	require.EqualValues(t, "biddr", recorder.Body.String())
+}
+
+func (ts *auctionHouseServerTestSuite) TestBidderCanBidOnItemCreatedByAdmin() {
+	adminToken := ts.login("admin", "admin")
+	ts.Require().EqualValues(http.StatusCreated, ts.send(http.MethodPost, "/api/v1/auctions/items", adminToken, map[string]interface{}{
+		"name":        "Painting",
+		"description": "A painting",
+	}, nil))
+
+	ts.Require().EqualValues(http.StatusCreated, ts.send(http.MethodPost, "/api/v1/users", "", map[string]interface{}{
+		"username":    "bidder",
+		"password":    "password",
+		"displayName": "Bidder",
+	}, nil))
+	bidderToken := ts.login("bidder", "password")
+
+	ts.Require().EqualValues(http.StatusCreated, ts.send(http.MethodPost, "/api/v1/auctions/bids/painting", bidderToken, map[string]interface{}{
+		"bidAmount": "100.25 USD",
+	}, nil))
+	ts.Require().EqualValues(http.StatusBadRequest, ts.send(http.MethodPost, "/api/v1/auctions/bids/painting", bidderToken, map[string]interface{}{
+		"bidAmount": "50 USD",
+	}, nil))
+
+	var highestBid struct {
+		BidAmount          string `json:"bidAmount"`
+		FormattedBidAmount string `json:"formattedBidAmount"`
+		Item               struct {
+			Name string `json:"name"`
+		} `json:"item"`
+		Bidder struct {
+			Username string `json:"username"`
+		} `json:"bidder"`
+	}
+	ts.Require().EqualValues(http.StatusOK, ts.send(http.MethodGet, "/api/v1/auctions/bids/Painting", adminToken, nil, &highestBid))
+	ts.Require().EqualValues("100.25 USD", highestBid.BidAmount)
+	ts.Require().EqualValues("$100.25", highestBid.FormattedBidAmount)
+	ts.Require().EqualValues("Painting", highestBid.Item.Name)
+	ts.Require().EqualValues("bidder", highestBid.Bidder.Username)
+}
+
+func (ts *auctionHouseServerTestSuite) TestUploadedImageIsServedAsItemImage() {
+	adminToken := ts.login("admin", "admin")
+	ts.Require().EqualValues(http.StatusCreated, ts.send(http.MethodPost, "/api/v1/auctions/items", adminToken, map[string]interface{}{
+		"name": "Painting",
+	}, nil))
+
+	var encoded bytes.Buffer
+	ts.Require().NoError(png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 800, 600))))
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", "painting.png")
+	ts.Require().NoError(err)
+	_, err = part.Write(encoded.Bytes())
+	ts.Require().NoError(err)
+	ts.Require().NoError(writer.Close())
+
+	r, err := http.NewRequest(http.MethodPost, ts.server.URL+"/api/v1/auctions/items/painting/images", &body)
+	ts.Require().NoError(err)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", adminToken))
+	resp, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	resp.Body.Close()
+	ts.Require().EqualValues(http.StatusCreated, resp.StatusCode)
+
+	var item struct {
+		ImageRef string `json:"image"`
+	}
+	ts.Require().EqualValues(http.StatusOK, ts.send(http.MethodGet, "/api/v1/auctions/items/painting", adminToken, nil, &item))
+	ts.Require().NotEmpty(item.ImageRef)
+
+	resp, err = ts.client.Get(ts.server.URL + item.ImageRef)
+	ts.Require().NoError(err)
+	defer resp.Body.Close()
+	ts.Require().EqualValues(http.StatusOK, resp.StatusCode)
+	ts.Require().EqualValues("image/jpeg", resp.Header.Get("Content-Type"))
+
+	thumbnail, err := jpeg.DecodeConfig(resp.Body)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(400, thumbnail.Width)
+	ts.Require().EqualValues(300, thumbnail.Height)
+}
+
+func (ts *auctionHouseServerTestSuite) login(username string, password string) string {
+	var response struct {
+		AuthToken string `json:"authToken"`
+	}
+	ts.Require().EqualValues(http.StatusOK, ts.send(http.MethodPost, "/api/v1/users/login", "", map[string]interface{}{
+		"username": username,
+		"password": password,
+	}, &response))
+	return response.AuthToken
+}
+
+func (ts *auctionHouseServerTestSuite) send(method string, path string, token string, body interface{}, response interface{}) int {
+	var rawBody []byte
+	if body != nil {
+		var err error
+		rawBody, err = json.Marshal(body)
+		ts.Require().NoError(err)
+	}
+
+	r, err := http.NewRequest(method, fmt.Sprintf("%s%s", ts.server.URL, path), bytes.NewReader(rawBody))
+	ts.Require().NoError(err)
+	if token != "" {
+		r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := ts.client.Do(r)
+	ts.Require().NoError(err)
+	defer resp.Body.Close()
+
+	if response != nil {
+		ts.Require().NoError(json.NewDecoder(resp.Body).Decode(response))
+	}
+	return resp.StatusCode
+}
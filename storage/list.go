@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// ItemCursor is the position of the last item of an ItemPage. The next page starts with the item after it.
+type ItemCursor struct {
+	Sort       ItemSort `json:"s"`
+	Descending bool     `json:"d,omitempty"`
+
+	// NameID is the lowercased name of the item when sorted by ItemSortName.
+	NameID string `json:"n,omitempty"`
+
+	// Value is the current bid or the bid count of the item when sorted by either of them.
+	Value int `json:"v,omitempty"`
+
+	// ClosesAt is when bidding on the item closes when sorted by ItemSortClosing. It is NeverCloses for an item without
+	// a closing time.
+	ClosesAt *time.Time `json:"c,omitempty"`
+
+	ID uint64 `json:"i"`
+}
+
+// NeverCloses is the closing time items without one are sorted by so they close after every other item.
+var NeverCloses = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// ClosingTimeOrNever returns the closing time, or NeverCloses when there is none.
+func ClosingTimeOrNever(closesAt time.Time) time.Time {
+	if closesAt.IsZero() {
+		return NeverCloses
+	}
+	return closesAt
+}
+
+// NewItemCursor creates the cursor of the item at the position within the listing ordered by the options.
+func NewItemCursor(options ListOptions, nameID string, value int, id uint64) *ItemCursor {
+	return &ItemCursor{
+		Sort:       options.SortOrDefault(),
+		Descending: options.Descending,
+		NameID:     nameID,
+		Value:      value,
+		ID:         id,
+	}
+}
+
+// Encode turns the cursor into an opaque string that is safe to use in URLs.
+func (cursor *ItemCursor) Encode() string {
+	// Marshalling a struct of strings and numbers cannot fail.
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeItemCursor reads the cursor of the options. The cursor is nil when the options do not have one. This will
+// return ErrInvalidCursor if the cursor is malformed or was returned for a different order.
+func DecodeItemCursor(options ListOptions) (*ItemCursor, error) {
+	if options.Cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(options.Cursor)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidCursor, "unable to decode cursor: %s", err)
+	}
+
+	var cursor ItemCursor
+	if err = json.Unmarshal(raw, &cursor); err != nil {
+		return nil, errors.Wrapf(ErrInvalidCursor, "unable to unmarshal cursor: %s", err)
+	}
+	if cursor.Sort != options.SortOrDefault() || cursor.Descending != options.Descending {
+		return nil, errors.Wrapf(ErrInvalidCursor, "cursor is for items sorted by %s", cursor.Sort)
+	}
+	return &cursor, nil
+}
+
+// SortOrDefault returns the order of the options, which is ItemSortCreated when none is given.
+func (options ListOptions) SortOrDefault() ItemSort {
+	if options.Sort == "" {
+		return ItemSortCreated
+	}
+	return options.Sort
+}
+
+// SearchTerms splits the search into lowercased words. Anything that is not a letter or a digit separates words.
+func SearchTerms(search string) []string {
+	return strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,234 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+type auctionBidClient struct {
+	db *Database
+}
+
+// NewAuctionBidClient returns an object that can perform various operations on model.AuctionBids.
+func NewAuctionBidClient(db *Database) storage.AuctionBidClient {
+	return &auctionBidClient{
+		db: db,
+	}
+}
+
+// GetHighestBid gets the highest bid for the specified item, or for its lot when it is sold in one. Bids by deleted
+// users still count. This will return storage.ErrEntityNotFound if the item does not have a bid or was deleted.
+func (bc *auctionBidClient) GetHighestBid(ctx context.Context, item *model.AuctionItem) (*model.AuctionBid, error) {
+	bc.db.lock.RLock()
+	defer bc.db.lock.RUnlock()
+
+	record, ok := bc.db.items[getAuctionItemNameID(item.Name)]
+	if !ok || record.deleted() {
+		return nil, errors.Wrap(storage.ErrEntityNotFound, "item does not have a bid")
+	}
+
+	highestBid := bc.highestBids()[bc.db.bidItem(record)]
+	if highestBid == nil {
+		return nil, errors.Wrap(storage.ErrEntityNotFound, "item does not have a bid")
+	}
+	return highestBid.toModel(), nil
+}
+
+// GetWinningBids gets the highest bid of each bidder that is winning the specified item, or its lot when it is sold in
+// one, from the highest bid down. Bids by deleted users still count. This will return storage.ErrEntityNotFound if the
+// item does not have a bid or was deleted.
+func (bc *auctionBidClient) GetWinningBids(ctx context.Context, item *model.AuctionItem) ([]*model.AuctionBid, error) {
+	bc.db.lock.RLock()
+	defer bc.db.lock.RUnlock()
+
+	record, ok := bc.db.items[getAuctionItemNameID(item.Name)]
+	if !ok || record.deleted() {
+		return nil, errors.Wrap(storage.ErrEntityNotFound, "item does not have a bid")
+	}
+
+	winningBids := bc.winningBids(bc.db.bidItem(record))
+	if len(winningBids) == 0 {
+		return nil, errors.Wrap(storage.ErrEntityNotFound, "item does not have a bid")
+	}
+	return bidRecordsToModels(winningBids), nil
+}
+
+// GetAllWinningBids gets the winning bids for all items in storage that have not been deleted, ordered by item and then
+// from the highest bid down. The bids on a lot are on its first item. Bids by deleted users still count.
+func (bc *auctionBidClient) GetAllWinningBids(ctx context.Context) ([]*model.AuctionBid, error) {
+	bc.db.lock.RLock()
+	defer bc.db.lock.RUnlock()
+
+	var items []*itemRecord
+	for item := range bc.highestBids() {
+		if !item.deleted() {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].item.ID < items[j].item.ID
+	})
+
+	var bids []*bidRecord
+	for _, item := range items {
+		bids = append(bids, bc.winningBids(item)...)
+	}
+	return bidRecordsToModels(bids), nil
+}
+
+// PlaceBid creates a new bid by the specified user for the specified item. A bid on an item in a lot is placed on the
+// first item of the lot. This will return storage.ErrBidTooLow if the specified amount is not higher than the other bids
+// of the user or would not take one of the winning places from the other bidders, storage.ErrOverSpendingLimit if the
+// bid and the winning bids of the user on other items add up to more than their spending limit, and
+// storage.ErrEntityNotFound if the user or item does not exist. The bid is returned with its bidder and the item it was
+// placed on.
+func (bc *auctionBidClient) PlaceBid(ctx context.Context, user *model.User, item *model.AuctionItem, amount model.Money) (*model.AuctionBid, error) {
+	bc.db.lock.Lock()
+	defer bc.db.lock.Unlock()
+
+	bidder, ok := bc.db.users[user.Username]
+	if !ok || bidder.deleted() {
+		return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to find bidder '%s'", user.Username)
+	}
+
+	nameID := getAuctionItemNameID(item.Name)
+	auctionItem, ok := bc.db.items[nameID]
+	if !ok || auctionItem.deleted() {
+		return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to find auction item '%s'", nameID)
+	}
+	auctionItem = bc.db.bidItem(auctionItem)
+
+	// The bid takes the place of the winning bid of the bidder on the item so only their other winning bids count.
+	if limit, ok := bc.spendingLimit(bidder); ok {
+		if limit.Currency != amount.Currency {
+			return nil, errors.Wrapf(model.ErrCurrencyMismatch, "the spending limit is in %s", limit.Currency)
+		}
+		if bc.committed(bidder, auctionItem)+amount.Amount > limit.Amount {
+			return nil, errors.Wrap(storage.ErrOverSpendingLimit, "unable to insert new auction bid")
+		}
+	}
+
+	// Like the highest_value_check trigger, the bid is too low when enough other bidders have bid at least as much to
+	// fill every winning place.
+	higherBidders := 0
+	for _, bid := range bc.bestBids(auctionItem) {
+		if bid.amount.Amount < amount.Amount {
+			continue
+		}
+		if bid.bidder == bidder {
+			return nil, errors.Wrap(storage.ErrBidTooLow, "unable to insert new auction bid")
+		}
+		higherBidders++
+	}
+	if higherBidders >= auctionItem.item.Quantity {
+		return nil, errors.Wrap(storage.ErrBidTooLow, "unable to insert new auction bid")
+	}
+
+	bid := &bidRecord{
+		amount: amount,
+		bidder: bidder,
+		item:   auctionItem,
+	}
+	bc.db.bids = append(bc.db.bids, bid)
+	return bid.toModel(), nil
+}
+
+// spendingLimit finds the limit of the bidder, or of the event when the bidder does not have one. The read lock must be
+// held by the caller.
+func (bc *auctionBidClient) spendingLimit(bidder *userRecord) (model.Money, bool) {
+	if limit, ok := bc.db.limits[bidder.user.Username]; ok {
+		return limit, true
+	}
+	limit, ok := bc.db.limits[""]
+	return limit, ok
+}
+
+// committed adds up the winning bids of the bidder on every item other than the one given. Bids on deleted items do not
+// count. The read lock must be held by the caller.
+func (bc *auctionBidClient) committed(bidder *userRecord, except *itemRecord) int64 {
+	var total int64
+	for item := range bc.highestBids() {
+		if item == except || item.deleted() {
+			continue
+		}
+		for _, bid := range bc.winningBids(item) {
+			if bid.bidder == bidder {
+				total += bid.amount.Amount
+			}
+		}
+	}
+	return total
+}
+
+// highestBids finds the highest bid on each item that has been bid on. The read lock must be held by the caller.
+func (bc *auctionBidClient) highestBids() map[*itemRecord]*bidRecord {
+	highestBids := make(map[*itemRecord]*bidRecord)
+	for _, bid := range bc.db.bids {
+		if highestBid := highestBids[bid.item]; highestBid == nil || bid.amount.Amount > highestBid.amount.Amount {
+			highestBids[bid.item] = bid
+		}
+	}
+	return highestBids
+}
+
+// bestBids finds the highest bid of each bidder on the item from the highest bid down. Bidders with the same bid are
+// ordered by who bid first. The read lock must be held by the caller.
+func (bc *auctionBidClient) bestBids(item *itemRecord) []*bidRecord {
+	var bestBids []*bidRecord
+	places := make(map[*userRecord]int)
+	for _, bid := range bc.db.bids {
+		if bid.item != item {
+			continue
+		}
+		if place, ok := places[bid.bidder]; !ok {
+			places[bid.bidder] = len(bestBids)
+			bestBids = append(bestBids, bid)
+		} else if bid.amount.Amount > bestBids[place].amount.Amount {
+			bestBids[place] = bid
+		}
+	}
+
+	// Bids are appended in the order they are placed so the position of a bid tells who bid first.
+	order := make(map[*bidRecord]int, len(bc.db.bids))
+	for i, bid := range bc.db.bids {
+		order[bid] = i
+	}
+	sort.Slice(bestBids, func(i, j int) bool {
+		if bestBids[i].amount.Amount != bestBids[j].amount.Amount {
+			return bestBids[i].amount.Amount > bestBids[j].amount.Amount
+		}
+		return order[bestBids[i]] < order[bestBids[j]]
+	})
+	return bestBids
+}
+
+// winningBids finds the best bids of as many bidders as the quantity of the item. The read lock must be held by the
+// caller.
+func (bc *auctionBidClient) winningBids(item *itemRecord) []*bidRecord {
+	bestBids := bc.bestBids(item)
+	if len(bestBids) > item.item.Quantity {
+		bestBids = bestBids[:item.item.Quantity]
+	}
+	return bestBids
+}
+
+func bidRecordsToModels(bids []*bidRecord) []*model.AuctionBid {
+	result := make([]*model.AuctionBid, len(bids))
+	for i, bid := range bids {
+		result[i] = bid.toModel()
+	}
+	return result
+}
+
+func (bid *bidRecord) toModel() *model.AuctionBid {
+	bidder := bid.bidder.user
+	return &model.AuctionBid{
+		BidAmount: bid.amount,
+		Bidder:    &bidder,
+		Item:      bid.item.toModel(),
+	}
+}
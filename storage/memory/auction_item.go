@@ -0,0 +1,436 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+type auctionItemClient struct {
+	db *Database
+}
+
+// NewAuctionItemClient returns an object that can perform various operations on model.AuctionItems.
+func NewAuctionItemClient(db *Database) storage.AuctionItemClient {
+	return &auctionItemClient{
+		db: db,
+	}
+}
+
+// Get retrieves the model.AuctionItem by the name. This will return storage.ErrEntityNotFound if the name is not found
+// in storage.
+func (ac *auctionItemClient) Get(ctx context.Context, name string) (*model.AuctionItem, error) {
+	ac.db.lock.RLock()
+	defer ac.db.lock.RUnlock()
+
+	nameID := getAuctionItemNameID(name)
+	record, ok := ac.db.items[nameID]
+	if !ok || record.deleted() {
+		return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to get auction item with name '%s'", nameID)
+	}
+
+	return record.toModel(), nil
+}
+
+// GetByID retrieves the model.AuctionItem by the ID. This will return storage.ErrEntityNotFound if the ID is not found
+// in storage.
+func (ac *auctionItemClient) GetByID(ctx context.Context, id uint64) (*model.AuctionItem, error) {
+	ac.db.lock.RLock()
+	defer ac.db.lock.RUnlock()
+
+	_, record := ac.db.itemByID(id)
+	if record == nil || record.deleted() {
+		return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to get auction item with ID %d", id)
+	}
+
+	return record.toModel(), nil
+}
+
+// GetAll retrieves all model.AuctionItems in storage in the order they were created.
+func (ac *auctionItemClient) GetAll(ctx context.Context) ([]*model.AuctionItem, error) {
+	ac.db.lock.RLock()
+	defer ac.db.lock.RUnlock()
+
+	records := make([]*itemRecord, 0, len(ac.db.items))
+	for _, record := range ac.db.items {
+		if !record.deleted() {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].item.ID < records[j].item.ID
+	})
+
+	return itemRecordsToModels(records), nil
+}
+
+// GetMatching retrieves every model.AuctionItem in the category of the filter that has all of its tags, in the order
+// they were created. A category that does not exist matches no items.
+func (ac *auctionItemClient) GetMatching(ctx context.Context, filter storage.ItemFilter) ([]*model.AuctionItem, error) {
+	ac.db.lock.RLock()
+	defer ac.db.lock.RUnlock()
+
+	var records []*itemRecord
+	for _, record := range ac.db.items {
+		if !record.deleted() && record.matches(filter) {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].item.ID < records[j].item.ID
+	})
+
+	return itemRecordsToModels(records), nil
+}
+
+// List retrieves the page of model.AuctionItems that match the options, starting after the cursor of the options. This
+// will return storage.ErrInvalidCursor if the cursor does not continue a listing in the same order.
+func (ac *auctionItemClient) List(ctx context.Context, options storage.ListOptions) (*storage.ItemPage, error) {
+	cursor, err := storage.DecodeItemCursor(options)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list auction items")
+	}
+
+	ac.db.lock.RLock()
+	defer ac.db.lock.RUnlock()
+
+	// Bids on a lot are on its first item but count for every item of the lot.
+	bidStats := make(map[*itemRecord]*itemBidStats)
+	for _, bid := range ac.db.bids {
+		stats := bidStats[bid.item]
+		if stats == nil {
+			stats = &itemBidStats{}
+			bidStats[bid.item] = stats
+		}
+		stats.count++
+		if bid.amount.Amount > stats.currentBid {
+			stats.currentBid = bid.amount.Amount
+		}
+	}
+
+	terms := storage.SearchTerms(options.Search)
+	var keys []*storage.ItemCursor
+	records := make(map[*storage.ItemCursor]*itemRecord)
+	for _, record := range ac.db.items {
+		if record.deleted() || !record.matches(options.ItemFilter) || !record.matchesSearch(terms) {
+			continue
+		}
+
+		var key *storage.ItemCursor
+		stats := bidStats[ac.db.bidItem(record)]
+		if stats == nil {
+			stats = &itemBidStats{}
+		}
+		switch sortBy := options.SortOrDefault(); sortBy {
+		case storage.ItemSortCreated:
+			key = storage.NewItemCursor(options, "", 0, record.item.ID)
+		case storage.ItemSortName:
+			key = storage.NewItemCursor(options, getAuctionItemNameID(record.item.Name), 0, record.item.ID)
+		case storage.ItemSortCurrentBid:
+			key = storage.NewItemCursor(options, "", int(stats.currentBid), record.item.ID)
+		case storage.ItemSortBidCount:
+			key = storage.NewItemCursor(options, "", stats.count, record.item.ID)
+		case storage.ItemSortClosing:
+			if cursor != nil && cursor.ClosesAt == nil {
+				return nil, errors.Wrap(storage.ErrInvalidCursor, "cursor has no closing time")
+			}
+			closesAt := storage.ClosingTimeOrNever(record.item.ClosesAt)
+			key = storage.NewItemCursor(options, "", 0, record.item.ID)
+			key.ClosesAt = &closesAt
+		default:
+			return nil, errors.Errorf("unable to list auction items sorted by unknown order '%s'", sortBy)
+		}
+		if cursor != nil && !isItemCursorBefore(cursor, key, options.Descending) {
+			continue
+		}
+		keys = append(keys, key)
+		records[key] = record
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return isItemCursorBefore(keys[i], keys[j], options.Descending)
+	})
+
+	page := &storage.ItemPage{}
+	if options.Limit > 0 && len(keys) > options.Limit {
+		keys = keys[:options.Limit]
+		page.NextCursor = keys[len(keys)-1].Encode()
+	}
+	page.Items = make([]*model.AuctionItem, len(keys))
+	for i, key := range keys {
+		page.Items[i] = records[key].toModel()
+	}
+	return page, nil
+}
+
+// itemBidStats summarizes the bids on an item for sorting.
+type itemBidStats struct {
+	currentBid int64
+	count      int
+}
+
+// isItemCursorBefore determines whether the item at the position of the first cursor comes before the item at the
+// second when listed in the order of the cursors.
+func isItemCursorBefore(first *storage.ItemCursor, second *storage.ItemCursor, descending bool) bool {
+	less := first.ID < second.ID
+	if first.NameID != second.NameID {
+		less = first.NameID < second.NameID
+	} else if first.Value != second.Value {
+		less = first.Value < second.Value
+	} else if first.ClosesAt != nil && second.ClosesAt != nil && !first.ClosesAt.Equal(*second.ClosesAt) {
+		less = first.ClosesAt.Before(*second.ClosesAt)
+	}
+
+	if descending {
+		return !less && first.ID != second.ID
+	}
+	return less
+}
+
+// GetDeleted retrieves all deleted model.AuctionItems in the order they were deleted.
+func (ac *auctionItemClient) GetDeleted(ctx context.Context) ([]*model.AuctionItem, error) {
+	ac.db.lock.RLock()
+	defer ac.db.lock.RUnlock()
+
+	var records []*itemRecord
+	for _, record := range ac.db.items {
+		if record.deleted() {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].item.DeletedAt.Before(records[j].item.DeletedAt)
+	})
+
+	return itemRecordsToModels(records), nil
+}
+
+// Delete marks the model.AuctionItem as deleted by name. Its bids are kept but are hidden along with the item. This
+// will return storage.ErrEntityNotFound if the name is not found in storage and storage.ErrItemInLot if the item is sold
+// in a lot.
+func (ac *auctionItemClient) Delete(ctx context.Context, name string) error {
+	ac.db.lock.Lock()
+	defer ac.db.lock.Unlock()
+
+	nameID := getAuctionItemNameID(name)
+	record, ok := ac.db.items[nameID]
+	if !ok || record.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to delete auction item with name '%s'", nameID)
+	}
+	if record.item.LotID != 0 {
+		return errors.Wrapf(storage.ErrItemInLot, "unable to delete auction item with name '%s'", nameID)
+	}
+
+	record.item.DeletedAt = time.Now().UTC()
+	return nil
+}
+
+// Restore brings back the deleted model.AuctionItem with the ID along with its bids. This will return
+// storage.ErrEntityNotFound if there is no deleted item with the ID.
+func (ac *auctionItemClient) Restore(ctx context.Context, id uint64) error {
+	ac.db.lock.Lock()
+	defer ac.db.lock.Unlock()
+
+	_, record := ac.db.itemByID(id)
+	if record == nil || !record.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find deleted auction item %d", id)
+	}
+
+	record.item.DeletedAt = time.Time{}
+	record.item.Version++
+	return nil
+}
+
+// Update changes the existing item by the non-zero fields of the provided model.AuctionItem object. This will return
+// storage.ErrUnknownCategory if the category does not exist.
+func (ac *auctionItemClient) Update(ctx context.Context, item *model.AuctionItem) error {
+	ac.db.lock.Lock()
+	defer ac.db.lock.Unlock()
+
+	nameID := getAuctionItemNameID(item.Name)
+	category, err := ac.db.categoryName(item.Category)
+	if err != nil {
+		return errors.Wrapf(err, "unable to update auction item %s", nameID)
+	}
+
+	record, ok := ac.db.items[nameID]
+	if !ok || record.deleted() {
+		return nil
+	}
+
+	record.item.Name = item.Name
+	if item.ImageRef != "" {
+		record.item.ImageRef = item.ImageRef
+	}
+	if item.Description != "" {
+		record.item.Description = item.Description
+	}
+	if category != "" {
+		record.item.Category = category
+	}
+	if len(item.Tags) > 0 {
+		record.item.Tags = ac.db.addTags(item.Tags)
+	}
+	if item.DonorName != "" {
+		record.item.DonorName = item.DonorName
+	}
+	if item.DonorBusiness != "" {
+		record.item.DonorBusiness = item.DonorBusiness
+	}
+	if item.FairMarketValue.Amount != 0 {
+		record.item.FairMarketValue = item.FairMarketValue
+	}
+	if item.Quantity > 0 {
+		record.item.Quantity = item.Quantity
+	}
+	if !item.ClosesAt.IsZero() {
+		record.item.ClosesAt = item.ClosesAt
+	}
+	record.item.Version++
+	return nil
+}
+
+// Create adds a new model.AuctionItem to storage along with its tags. This will return storage.ErrEntityAlreadyExists if
+// the name is already found in storage and storage.ErrUnknownCategory if the category does not exist.
+func (ac *auctionItemClient) Create(ctx context.Context, item *model.AuctionItem) error {
+	ac.db.lock.Lock()
+	defer ac.db.lock.Unlock()
+
+	nameID := getAuctionItemNameID(item.Name)
+	if _, ok := ac.db.items[nameID]; ok {
+		return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to create auction item %s", nameID)
+	}
+	category, err := ac.db.categoryName(item.Category)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create auction item %s", nameID)
+	}
+
+	ac.db.nextID++
+	item.ID = ac.db.nextID
+	item.Version = 1
+	item.Category = category
+	item.Tags = ac.db.addTags(item.Tags)
+	item.LotID = 0
+	if item.Quantity < 1 {
+		item.Quantity = 1
+	}
+	record := &itemRecord{
+		item: *item,
+	}
+	ac.db.items[nameID] = record
+
+	*item = *record.toModel()
+	return nil
+}
+
+// Rename changes the name of the model.AuctionItem with the ID. The bids on the item are kept. This will return
+// storage.ErrEntityNotFound if the ID is not found in storage and storage.ErrEntityAlreadyExists if another item
+// already has the name.
+func (ac *auctionItemClient) Rename(ctx context.Context, id uint64, name string) error {
+	ac.db.lock.Lock()
+	defer ac.db.lock.Unlock()
+
+	previousNameID, record := ac.db.itemByID(id)
+	if record == nil || record.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find auction item %d", id)
+	}
+
+	nameID := getAuctionItemNameID(name)
+	if existing, ok := ac.db.items[nameID]; ok && existing != record {
+		return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to rename auction item %d to '%s'", id, name)
+	}
+
+	delete(ac.db.items, previousNameID)
+	record.item.Name = name
+	record.item.Version++
+	ac.db.items[nameID] = record
+	return nil
+}
+
+// Replace overwrites every field of the model.AuctionItem with the ID, including the empty ones, as long as the item is
+// still at item.Version. item.Version is set to the new version and a quantity that is not set is stored as 1. This
+// will return storage.ErrEntityNotFound if the ID is not found in storage, storage.ErrVersionMismatch if the item was
+// changed since item.Version, storage.ErrEntityAlreadyExists if another item already has the name and
+// storage.ErrUnknownCategory if the category does not exist.
+func (ac *auctionItemClient) Replace(ctx context.Context, item *model.AuctionItem) error {
+	ac.db.lock.Lock()
+	defer ac.db.lock.Unlock()
+
+	previousNameID, record := ac.db.itemByID(item.ID)
+	if record == nil || record.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find auction item %d", item.ID)
+	}
+	if record.item.Version != item.Version {
+		return errors.Wrapf(storage.ErrVersionMismatch, "auction item %d is no longer at version %d", item.ID, item.Version)
+	}
+
+	nameID := getAuctionItemNameID(item.Name)
+	if existing, ok := ac.db.items[nameID]; ok && existing != record {
+		return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to rename auction item %d to '%s'", item.ID, item.Name)
+	}
+	category, err := ac.db.categoryName(item.Category)
+	if err != nil {
+		return errors.Wrapf(err, "unable to replace auction item %d", item.ID)
+	}
+
+	delete(ac.db.items, previousNameID)
+	record.item.Name = item.Name
+	record.item.ImageRef = item.ImageRef
+	record.item.Description = item.Description
+	record.item.Category = category
+	record.item.Tags = ac.db.addTags(item.Tags)
+	record.item.DonorName = item.DonorName
+	record.item.DonorBusiness = item.DonorBusiness
+	record.item.FairMarketValue = item.FairMarketValue
+	record.item.Quantity = item.Quantity
+	record.item.ClosesAt = item.ClosesAt
+	if record.item.Quantity < 1 {
+		record.item.Quantity = 1
+	}
+	record.item.Version++
+	ac.db.items[nameID] = record
+
+	item.Quantity = record.item.Quantity
+	item.Version = record.item.Version
+	return nil
+}
+
+// ClaimClosed retrieves the model.AuctionItems whose closing time has passed by now and records their closing times so
+// they are not retrieved again, ordered by closing time. An item whose closing time changes is retrieved again once the
+// new time has passed. Deleted items are not retrieved.
+func (ac *auctionItemClient) ClaimClosed(ctx context.Context, now time.Time) ([]*model.AuctionItem, error) {
+	ac.db.lock.Lock()
+	defer ac.db.lock.Unlock()
+
+	var records []*itemRecord
+	for _, record := range ac.db.items {
+		closesAt := record.item.ClosesAt
+		if record.deleted() || closesAt.IsZero() || closesAt.After(now) {
+			continue
+		}
+		if claimed, ok := ac.db.closings[record.item.ID]; ok && claimed.Equal(closesAt) {
+			continue
+		}
+		ac.db.closings[record.item.ID] = closesAt
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].item.ClosesAt.Equal(records[j].item.ClosesAt) {
+			return records[i].item.ClosesAt.Before(records[j].item.ClosesAt)
+		}
+		return records[i].item.ID < records[j].item.ID
+	})
+
+	return itemRecordsToModels(records), nil
+}
+
+func itemRecordsToModels(records []*itemRecord) []*model.AuctionItem {
+	result := make([]*model.AuctionItem, len(records))
+	for i, record := range records {
+		result[i] = record.toModel()
+	}
+	return result
+}
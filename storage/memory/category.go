@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+type categoryClient struct {
+	db *Database
+}
+
+// NewCategoryClient returns an object that can perform various operations on model.Categories.
+func NewCategoryClient(db *Database) storage.CategoryClient {
+	return &categoryClient{
+		db: db,
+	}
+}
+
+// GetAll retrieves every model.Category in storage ordered by name.
+func (cc *categoryClient) GetAll(ctx context.Context) ([]*model.Category, error) {
+	cc.db.lock.RLock()
+	defer cc.db.lock.RUnlock()
+
+	nameIDs := make([]string, 0, len(cc.db.categories))
+	for nameID := range cc.db.categories {
+		nameIDs = append(nameIDs, nameID)
+	}
+	sort.Strings(nameIDs)
+
+	result := make([]*model.Category, len(nameIDs))
+	for i, nameID := range nameIDs {
+		category := cc.db.categories[nameID]
+		result[i] = &category
+	}
+	return result, nil
+}
+
+// Create adds a new model.Category to storage. This will return storage.ErrEntityAlreadyExists if the name is already
+// found in storage.
+func (cc *categoryClient) Create(ctx context.Context, category *model.Category) error {
+	cc.db.lock.Lock()
+	defer cc.db.lock.Unlock()
+
+	nameID := getCategoryNameID(category.Name)
+	if _, ok := cc.db.categories[nameID]; ok {
+		return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to create category %s", nameID)
+	}
+
+	cc.db.nextID++
+	category.ID = cc.db.nextID
+	cc.db.categories[nameID] = *category
+	return nil
+}
+
+// Delete removes the model.Category by name and takes every item out of it, including the deleted items. This will
+// return storage.ErrEntityNotFound if the name is not found in storage.
+func (cc *categoryClient) Delete(ctx context.Context, name string) error {
+	cc.db.lock.Lock()
+	defer cc.db.lock.Unlock()
+
+	nameID := getCategoryNameID(name)
+	if _, ok := cc.db.categories[nameID]; !ok {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to delete category with name '%s'", nameID)
+	}
+
+	for _, record := range cc.db.items {
+		if record.item.Category != "" && getCategoryNameID(record.item.Category) == nameID {
+			record.item.Category = ""
+			record.item.Version++
+		}
+	}
+	delete(cc.db.categories, nameID)
+	return nil
+}
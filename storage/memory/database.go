@@ -0,0 +1,313 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+// Database holds every entity in memory. All of the clients created from the same Database share its contents, which
+// are lost when the process exits.
+type Database struct {
+	lock   sync.RWMutex
+	nextID uint64
+	users  map[string]*userRecord
+	items  map[string]*itemRecord
+	bids   []*bidRecord
+
+	// images has the images of each item by item ID in order of their position.
+	images map[uint64][]model.ItemImage
+
+	// categories has every category by its lowercased name.
+	categories map[string]model.Category
+
+	// tags has the display name of every tag by its lowercased name. Tags keep the display name they were first given.
+	tags map[string]string
+
+	// lots has every lot by ID without its items. The items of a lot refer to it by their LotID.
+	lots map[uint64]model.Lot
+
+	// levels has every appeal level by ID.
+	levels map[uint64]model.AppealLevel
+
+	// pledges has every pledge in the order they were recorded.
+	pledges []*pledgeRecord
+
+	// raffles has every raffle by ID.
+	raffles map[uint64]model.Raffle
+
+	// tickets has every ticket purchase in the order they were bought.
+	tickets []*ticketRecord
+
+	// limits has the spending limit of each user by username. The limit of the event has an empty username.
+	limits map[string]model.Money
+
+	// closings has the closing time of each item by item ID that was already claimed.
+	closings map[uint64]time.Time
+
+	// outbids has every outbid notice that was not taken yet in the order they were added.
+	outbids []model.OutbidNotice
+}
+
+type userRecord struct {
+	user model.User
+}
+
+type itemRecord struct {
+	item model.AuctionItem
+}
+
+type bidRecord struct {
+	amount model.Money
+	bidder *userRecord
+	item   *itemRecord
+}
+
+type pledgeRecord struct {
+	id         uint64
+	levelID    uint64
+	amount     model.Money
+	bidder     *userRecord
+	recordedBy string
+}
+
+type ticketRecord struct {
+	id         uint64
+	raffleID   uint64
+	buyer      *userRecord
+	quantity   int
+	price      model.Money
+	recordedBy string
+}
+
+// NewDatabase creates an empty Database.
+func NewDatabase() *Database {
+	return &Database{
+		users:      make(map[string]*userRecord),
+		items:      make(map[string]*itemRecord),
+		images:     make(map[uint64][]model.ItemImage),
+		categories: make(map[string]model.Category),
+		tags:       make(map[string]string),
+		lots:       make(map[uint64]model.Lot),
+		levels:     make(map[uint64]model.AppealLevel),
+		raffles:    make(map[uint64]model.Raffle),
+		limits:     make(map[string]model.Money),
+		closings:   make(map[uint64]time.Time),
+	}
+}
+
+func (record *userRecord) deleted() bool {
+	return !record.user.DeletedAt.IsZero()
+}
+
+func (record *itemRecord) deleted() bool {
+	return !record.item.DeletedAt.IsZero()
+}
+
+// itemByID finds the item with the ID. The read lock must be held by the caller.
+func (db *Database) itemByID(id uint64) (string, *itemRecord) {
+	for nameID, record := range db.items {
+		if record.item.ID == id {
+			return nameID, record
+		}
+	}
+	return "", nil
+}
+
+// lotItems finds the items in the lot with the ID ordered by ID. The read lock must be held by the caller.
+func (db *Database) lotItems(lotID uint64) []*itemRecord {
+	var records []*itemRecord
+	for _, record := range db.items {
+		if record.item.LotID == lotID {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].item.ID < records[j].item.ID
+	})
+	return records
+}
+
+// bidItem finds the item that bids on the item are placed on. Bids on a lot are placed on its first item so every item
+// of the lot shares them. The read lock must be held by the caller.
+func (db *Database) bidItem(record *itemRecord) *itemRecord {
+	if record.item.LotID == 0 {
+		return record
+	}
+	if items := db.lotItems(record.item.LotID); len(items) > 0 {
+		return items[0]
+	}
+	return record
+}
+
+// toModel copies the item so changes to it do not affect the record.
+func (record *itemRecord) toModel() *model.AuctionItem {
+	item := record.item
+	item.Tags = append([]string(nil), record.item.Tags...)
+	if len(item.Tags) == 0 {
+		item.Tags = nil
+	}
+	return &item
+}
+
+// matches determines whether the item is in the category of the filter and has all of its tags.
+func (record *itemRecord) matches(filter storage.ItemFilter) bool {
+	if filter.Category != "" && getCategoryNameID(record.item.Category) != getCategoryNameID(filter.Category) {
+		return false
+	}
+
+	tags := make(map[string]bool, len(record.item.Tags))
+	for _, tag := range record.item.Tags {
+		tags[getTagNameID(tag)] = true
+	}
+	for _, tag := range filter.Tags {
+		if !tags[getTagNameID(tag)] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesSearch determines whether the name or description of the item has a word starting with each of the terms.
+func (record *itemRecord) matchesSearch(terms []string) bool {
+	words := storage.SearchTerms(record.item.Name + " " + record.item.Description)
+	for _, term := range terms {
+		found := false
+		for _, word := range words {
+			if strings.HasPrefix(word, term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// categoryName finds the name of the category as it was created. The name is empty when the given name is empty. This
+// will return storage.ErrUnknownCategory if the category does not exist. The read lock must be held by the caller.
+func (db *Database) categoryName(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+
+	category, ok := db.categories[getCategoryNameID(name)]
+	if !ok {
+		return "", errors.Wrapf(storage.ErrUnknownCategory, "unable to find category '%s'", name)
+	}
+	return category.Name, nil
+}
+
+// addTags creates the tags that do not exist yet and returns the display names of the tags without repeats, ordered by
+// name. The lock must be held by the caller.
+func (db *Database) addTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var nameIDs []string
+	for _, tag := range tags {
+		nameID := getTagNameID(tag)
+		if nameID == "" || seen[nameID] {
+			continue
+		}
+		seen[nameID] = true
+		nameIDs = append(nameIDs, nameID)
+		if _, ok := db.tags[nameID]; !ok {
+			db.tags[nameID] = tag
+		}
+	}
+	sort.Strings(nameIDs)
+
+	var result []string
+	for _, nameID := range nameIDs {
+		result = append(result, db.tags[nameID])
+	}
+	return result
+}
+
+func getAuctionItemNameID(name string) string {
+	return strings.ToLower(name)
+}
+
+func getCategoryNameID(name string) string {
+	return strings.ToLower(name)
+}
+
+func getTagNameID(name string) string {
+	return strings.ToLower(name)
+}
+
+func getLotNameID(name string) string {
+	return strings.ToLower(name)
+}
+
+// clone returns a deep copy of the database. The read lock must be held by the caller.
+func (db *Database) clone() *Database {
+	cloned := NewDatabase()
+	cloned.nextID = db.nextID
+
+	users := make(map[*userRecord]*userRecord, len(db.users))
+	for username, record := range db.users {
+		users[record] = &userRecord{user: record.user}
+		cloned.users[username] = users[record]
+	}
+
+	items := make(map[*itemRecord]*itemRecord, len(db.items))
+	for nameID, record := range db.items {
+		items[record] = &itemRecord{item: record.item}
+		cloned.items[nameID] = items[record]
+	}
+
+	cloned.bids = make([]*bidRecord, len(db.bids))
+	for i, bid := range db.bids {
+		cloned.bids[i] = &bidRecord{
+			amount: bid.amount,
+			bidder: users[bid.bidder],
+			item:   items[bid.item],
+		}
+	}
+
+	for itemID, images := range db.images {
+		cloned.images[itemID] = append([]model.ItemImage(nil), images...)
+	}
+	for nameID, category := range db.categories {
+		cloned.categories[nameID] = category
+	}
+	for nameID, tag := range db.tags {
+		cloned.tags[nameID] = tag
+	}
+	for id, lot := range db.lots {
+		cloned.lots[id] = lot
+	}
+	for id, level := range db.levels {
+		cloned.levels[id] = level
+	}
+	cloned.pledges = make([]*pledgeRecord, len(db.pledges))
+	for i, pledge := range db.pledges {
+		clonedPledge := *pledge
+		clonedPledge.bidder = users[pledge.bidder]
+		cloned.pledges[i] = &clonedPledge
+	}
+	for id, raffle := range db.raffles {
+		cloned.raffles[id] = raffle
+	}
+	cloned.tickets = make([]*ticketRecord, len(db.tickets))
+	for i, ticket := range db.tickets {
+		clonedTicket := *ticket
+		clonedTicket.buyer = users[ticket.buyer]
+		cloned.tickets[i] = &clonedTicket
+	}
+	for username, amount := range db.limits {
+		cloned.limits[username] = amount
+	}
+	for itemID, closesAt := range db.closings {
+		cloned.closings[itemID] = closesAt
+	}
+	cloned.outbids = append([]model.OutbidNotice(nil), db.outbids...)
+	return cloned
+}
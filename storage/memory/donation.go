@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+type donationClient struct {
+	db *Database
+}
+
+// NewDonationClient returns an object that can perform various operations on model.AppealLevels and model.Pledges.
+func NewDonationClient(db *Database) storage.DonationClient {
+	return &donationClient{
+		db: db,
+	}
+}
+
+// GetLevels retrieves every model.AppealLevel in storage from the highest amount down. Levels with the same amount are
+// ordered by ID.
+func (dc *donationClient) GetLevels(ctx context.Context) ([]*model.AppealLevel, error) {
+	dc.db.lock.RLock()
+	defer dc.db.lock.RUnlock()
+
+	result := make([]*model.AppealLevel, 0, len(dc.db.levels))
+	for _, level := range dc.db.levels {
+		level := level
+		result = append(result, &level)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Amount.Amount != result[j].Amount.Amount {
+			return result[i].Amount.Amount > result[j].Amount.Amount
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// CreateLevel adds a new model.AppealLevel to storage. This will return storage.ErrEntityAlreadyExists if the name is
+// already found in storage, ignoring case.
+func (dc *donationClient) CreateLevel(ctx context.Context, level *model.AppealLevel) error {
+	dc.db.lock.Lock()
+	defer dc.db.lock.Unlock()
+
+	for _, existing := range dc.db.levels {
+		if strings.EqualFold(existing.Name, level.Name) {
+			return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to create appeal level '%s'", level.Name)
+		}
+	}
+
+	dc.db.nextID++
+	level.ID = dc.db.nextID
+	dc.db.levels[level.ID] = *level
+	return nil
+}
+
+// DeleteLevel removes the model.AppealLevel with the ID. This will return storage.ErrEntityNotFound if the ID is not
+// found in storage and storage.ErrLevelHasPledges if a pledge was made at the level.
+func (dc *donationClient) DeleteLevel(ctx context.Context, id uint64) error {
+	dc.db.lock.Lock()
+	defer dc.db.lock.Unlock()
+
+	if _, ok := dc.db.levels[id]; !ok {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to delete appeal level %d", id)
+	}
+	for _, record := range dc.db.pledges {
+		if record.levelID == id {
+			return errors.Wrapf(storage.ErrLevelHasPledges, "unable to delete appeal level %d", id)
+		}
+	}
+
+	delete(dc.db.levels, id)
+	return nil
+}
+
+// GetPledges retrieves every model.Pledge in storage in the order they were recorded. Pledges by deleted users are
+// included.
+func (dc *donationClient) GetPledges(ctx context.Context) ([]*model.Pledge, error) {
+	dc.db.lock.RLock()
+	defer dc.db.lock.RUnlock()
+
+	result := make([]*model.Pledge, len(dc.db.pledges))
+	for i, record := range dc.db.pledges {
+		result[i] = dc.toModel(record)
+	}
+	return result, nil
+}
+
+// CreatePledge adds a new model.Pledge by the bidder at the level. The amount of the pledge is the amount of the level.
+// This will return storage.ErrEntityNotFound if the bidder or the level does not exist.
+func (dc *donationClient) CreatePledge(ctx context.Context, pledge *model.Pledge) error {
+	dc.db.lock.Lock()
+	defer dc.db.lock.Unlock()
+
+	bidder, ok := dc.db.users[pledge.Bidder.Username]
+	if !ok || bidder.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find bidder '%s'", pledge.Bidder.Username)
+	}
+	level, ok := dc.db.levels[pledge.Level.ID]
+	if !ok {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find appeal level %d", pledge.Level.ID)
+	}
+
+	dc.db.nextID++
+	record := &pledgeRecord{
+		id:         dc.db.nextID,
+		levelID:    level.ID,
+		amount:     level.Amount,
+		bidder:     bidder,
+		recordedBy: pledge.RecordedBy,
+	}
+	dc.db.pledges = append(dc.db.pledges, record)
+
+	*pledge = *dc.toModel(record)
+	return nil
+}
+
+// DeletePledge removes the model.Pledge with the ID and returns it. This will return storage.ErrEntityNotFound if the
+// ID is not found in storage.
+func (dc *donationClient) DeletePledge(ctx context.Context, id uint64) (*model.Pledge, error) {
+	dc.db.lock.Lock()
+	defer dc.db.lock.Unlock()
+
+	for i, record := range dc.db.pledges {
+		if record.id == id {
+			dc.db.pledges = append(dc.db.pledges[:i:i], dc.db.pledges[i+1:]...)
+			return dc.toModel(record), nil
+		}
+	}
+	return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to delete pledge %d", id)
+}
+
+// toModel copies the pledge along with its bidder and level. The read lock must be held by the caller.
+func (dc *donationClient) toModel(record *pledgeRecord) *model.Pledge {
+	bidder := record.bidder.user
+	level := dc.db.levels[record.levelID]
+	return &model.Pledge{
+		ID:         record.id,
+		Level:      &level,
+		Bidder:     &bidder,
+		Amount:     record.amount,
+		RecordedBy: record.recordedBy,
+	}
+}
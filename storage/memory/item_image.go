@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+type itemImageClient struct {
+	db *Database
+}
+
+// NewItemImageClient returns an object that can perform various operations on model.ItemImages.
+func NewItemImageClient(db *Database) storage.ItemImageClient {
+	return &itemImageClient{
+		db: db,
+	}
+}
+
+// GetAll retrieves every model.ItemImage of the item ordered by position. Items without images have an empty result.
+func (ic *itemImageClient) GetAll(ctx context.Context, itemID uint64) ([]*model.ItemImage, error) {
+	ic.db.lock.RLock()
+	defer ic.db.lock.RUnlock()
+
+	images := ic.db.images[itemID]
+	result := make([]*model.ItemImage, len(images))
+	for i := range images {
+		image := images[i]
+		result[i] = &image
+	}
+	return result, nil
+}
+
+// Add stores the model.ItemImage after the other images of its item. This will return storage.ErrEntityNotFound if the
+// item does not exist or was deleted.
+func (ic *itemImageClient) Add(ctx context.Context, image *model.ItemImage) error {
+	ic.db.lock.Lock()
+	defer ic.db.lock.Unlock()
+
+	_, record := ic.db.itemByID(image.ItemID)
+	if record == nil || record.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find auction item %d", image.ItemID)
+	}
+
+	ic.db.nextID++
+	image.ID = ic.db.nextID
+	image.Position = len(ic.db.images[image.ItemID])
+	ic.db.images[image.ItemID] = append(ic.db.images[image.ItemID], *image)
+	return nil
+}
+
+// Delete removes the model.ItemImage from the item and closes the gap it leaves in the positions. This will return
+// storage.ErrEntityNotFound if the item does not have the image.
+func (ic *itemImageClient) Delete(ctx context.Context, itemID uint64, imageID uint64) error {
+	ic.db.lock.Lock()
+	defer ic.db.lock.Unlock()
+
+	images := ic.db.images[itemID]
+	for i := range images {
+		if images[i].ID != imageID {
+			continue
+		}
+
+		remaining := append(append([]model.ItemImage(nil), images[:i]...), images[i+1:]...)
+		for position := range remaining {
+			remaining[position].Position = position
+		}
+		ic.db.images[itemID] = remaining
+		return nil
+	}
+	return errors.Wrapf(storage.ErrEntityNotFound, "unable to find image %d of auction item %d", imageID, itemID)
+}
+
+// Reorder moves every model.ItemImage of the item to the position of its ID in imageIDs. This will return
+// storage.ErrInvalidOrder unless imageIDs has every image of the item exactly once.
+func (ic *itemImageClient) Reorder(ctx context.Context, itemID uint64, imageIDs []uint64) error {
+	ic.db.lock.Lock()
+	defer ic.db.lock.Unlock()
+
+	images := ic.db.images[itemID]
+	if len(images) != len(imageIDs) {
+		return errors.Wrapf(storage.ErrInvalidOrder, "expected %d images but got %d", len(images), len(imageIDs))
+	}
+
+	byID := make(map[uint64]model.ItemImage, len(images))
+	for _, image := range images {
+		byID[image.ID] = image
+	}
+
+	reordered := make([]model.ItemImage, len(imageIDs))
+	for position, imageID := range imageIDs {
+		image, ok := byID[imageID]
+		if !ok {
+			return errors.Wrapf(storage.ErrInvalidOrder, "image %d is not an image of the item or is repeated", imageID)
+		}
+		delete(byID, imageID)
+		image.Position = position
+		reordered[position] = image
+	}
+	ic.db.images[itemID] = reordered
+	return nil
+}
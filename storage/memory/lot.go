@@ -0,0 +1,230 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+type lotClient struct {
+	db *Database
+}
+
+// NewLotClient returns an object that can perform various operations on model.Lots.
+func NewLotClient(db *Database) storage.LotClient {
+	return &lotClient{
+		db: db,
+	}
+}
+
+// GetAll retrieves every model.Lot in storage along with its items in the order they were created.
+func (lc *lotClient) GetAll(ctx context.Context) ([]*model.Lot, error) {
+	lc.db.lock.RLock()
+	defer lc.db.lock.RUnlock()
+
+	ids := make([]uint64, 0, len(lc.db.lots))
+	for id := range lc.db.lots {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	result := make([]*model.Lot, len(ids))
+	for i, id := range ids {
+		result[i] = lc.toModel(lc.db.lots[id])
+	}
+	return result, nil
+}
+
+// GetByID retrieves the model.Lot by the ID along with its items. This will return storage.ErrEntityNotFound if the ID
+// is not found in storage.
+func (lc *lotClient) GetByID(ctx context.Context, id uint64) (*model.Lot, error) {
+	lc.db.lock.RLock()
+	defer lc.db.lock.RUnlock()
+
+	lot, ok := lc.db.lots[id]
+	if !ok {
+		return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to get lot %d", id)
+	}
+	return lc.toModel(lot), nil
+}
+
+// Create adds a new model.Lot to storage with the items of the IDs. This will return storage.ErrEntityAlreadyExists if
+// the name is already found in storage, storage.ErrLotTooSmall if fewer than two items are given,
+// storage.ErrEntityNotFound if an item does not exist, storage.ErrItemInLot if an item is already in a lot and
+// storage.ErrLotHasBids if an item has bids.
+func (lc *lotClient) Create(ctx context.Context, lot *model.Lot, itemIDs []uint64) error {
+	lc.db.lock.Lock()
+	defer lc.db.lock.Unlock()
+
+	nameID := getLotNameID(lot.Name)
+	for _, existing := range lc.db.lots {
+		if getLotNameID(existing.Name) == nameID {
+			return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to create lot %s", nameID)
+		}
+	}
+
+	records, err := lc.standaloneItems(itemIDs)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create lot %s", nameID)
+	}
+	if len(records) < 2 {
+		return errors.Wrapf(storage.ErrLotTooSmall, "unable to create lot %s", nameID)
+	}
+	if lc.hasBids(records) {
+		return errors.Wrapf(storage.ErrLotHasBids, "unable to create lot %s", nameID)
+	}
+
+	lc.db.nextID++
+	created := model.Lot{
+		ID:   lc.db.nextID,
+		Name: lot.Name,
+	}
+	lc.db.lots[created.ID] = created
+	moveToLot(records, created.ID)
+
+	*lot = *lc.toModel(created)
+	return nil
+}
+
+// Merge moves every item of the other lots and the items of the IDs into the lot with the ID and removes the other
+// lots. This will return storage.ErrEntityNotFound if a lot or item does not exist, storage.ErrItemInLot if an item is
+// in a lot that is not being merged and storage.ErrLotHasBids if any of the lots or items have bids.
+func (lc *lotClient) Merge(ctx context.Context, id uint64, lotIDs []uint64, itemIDs []uint64) error {
+	lc.db.lock.Lock()
+	defer lc.db.lock.Unlock()
+
+	if _, ok := lc.db.lots[id]; !ok {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find lot %d", id)
+	}
+	records := lc.db.lotItems(id)
+	for _, lotID := range lotIDs {
+		if lotID == id {
+			continue
+		}
+		if _, ok := lc.db.lots[lotID]; !ok {
+			return errors.Wrapf(storage.ErrEntityNotFound, "unable to find lot %d", lotID)
+		}
+		records = append(records, lc.db.lotItems(lotID)...)
+	}
+
+	var standalone []uint64
+	for _, itemID := range itemIDs {
+		if _, record := lc.db.itemByID(itemID); record == nil || record.item.LotID != id {
+			standalone = append(standalone, itemID)
+		}
+	}
+	added, err := lc.standaloneItems(standalone)
+	if err != nil {
+		return errors.Wrapf(err, "unable to merge into lot %d", id)
+	}
+	records = append(records, added...)
+	if lc.hasBids(records) {
+		return errors.Wrapf(storage.ErrLotHasBids, "unable to merge into lot %d", id)
+	}
+
+	for _, lotID := range lotIDs {
+		if lotID != id {
+			delete(lc.db.lots, lotID)
+		}
+	}
+	moveToLot(records, id)
+	return nil
+}
+
+// Split takes the items of the IDs out of the lot with the ID. The lot is removed and all of its items are sold on their
+// own again when fewer than two items would be left or no IDs are given. This will return storage.ErrEntityNotFound if
+// the lot does not exist or an item is not in it and storage.ErrLotHasBids if the lot has bids.
+func (lc *lotClient) Split(ctx context.Context, id uint64, itemIDs []uint64) error {
+	lc.db.lock.Lock()
+	defer lc.db.lock.Unlock()
+
+	if _, ok := lc.db.lots[id]; !ok {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find lot %d", id)
+	}
+	records := lc.db.lotItems(id)
+	if lc.hasBids(records) {
+		return errors.Wrapf(storage.ErrLotHasBids, "unable to split lot %d", id)
+	}
+
+	split := make(map[uint64]bool, len(itemIDs))
+	for _, itemID := range itemIDs {
+		if _, record := lc.db.itemByID(itemID); record == nil || record.item.LotID != id {
+			return errors.Wrapf(storage.ErrEntityNotFound, "unable to find auction item %d in lot %d", itemID, id)
+		}
+		split[itemID] = true
+	}
+
+	var removed, kept []*itemRecord
+	for _, record := range records {
+		if split[record.item.ID] {
+			removed = append(removed, record)
+		} else {
+			kept = append(kept, record)
+		}
+	}
+	if len(itemIDs) == 0 || len(kept) < 2 {
+		removed = records
+		delete(lc.db.lots, id)
+	}
+	moveToLot(removed, 0)
+	return nil
+}
+
+// standaloneItems finds the items of the IDs without repeats. This will return storage.ErrEntityNotFound if an item does
+// not exist and storage.ErrItemInLot if an item is already in a lot. The read lock must be held by the caller.
+func (lc *lotClient) standaloneItems(itemIDs []uint64) ([]*itemRecord, error) {
+	seen := make(map[uint64]bool, len(itemIDs))
+	var records []*itemRecord
+	for _, itemID := range itemIDs {
+		if seen[itemID] {
+			continue
+		}
+		seen[itemID] = true
+
+		_, record := lc.db.itemByID(itemID)
+		if record == nil || record.deleted() {
+			return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to find auction item %d", itemID)
+		}
+		if record.item.LotID != 0 {
+			return nil, errors.Wrapf(storage.ErrItemInLot, "auction item %d is in lot %d", itemID, record.item.LotID)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// hasBids determines whether any of the items have bids. The read lock must be held by the caller.
+func (lc *lotClient) hasBids(records []*itemRecord) bool {
+	items := make(map[*itemRecord]bool, len(records))
+	for _, record := range records {
+		items[record] = true
+	}
+	for _, bid := range lc.db.bids {
+		if items[bid.item] {
+			return true
+		}
+	}
+	return false
+}
+
+// toModel copies the lot along with its items. The read lock must be held by the caller.
+func (lc *lotClient) toModel(lot model.Lot) *model.Lot {
+	lot.Items = itemRecordsToModels(lc.db.lotItems(lot.ID))
+	return &lot
+}
+
+// moveToLot puts the items in the lot with the ID, or sells them on their own when the ID is 0. The lock must be held
+// by the caller.
+func moveToLot(records []*itemRecord, lotID uint64) {
+	for _, record := range records {
+		if record.item.LotID != lotID {
+			record.item.LotID = lotID
+			record.item.Version++
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/MMarsolek/AuctionHouse/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestConformance(t *testing.T) {
+	suite.Run(t, &storagetest.ConformanceSuite{
+		NewClients: func(t *testing.T) *storagetest.Clients {
+			db := NewDatabase()
+			return &storagetest.Clients{
+				Users:      NewUserClient(db),
+				Items:      NewAuctionItemClient(db),
+				Bids:       NewAuctionBidClient(db),
+				Images:     NewItemImageClient(db),
+				Categories: NewCategoryClient(db),
+				Lots:       NewLotClient(db),
+				Donations:  NewDonationClient(db),
+				Raffles:    NewRaffleClient(db),
+				Limits:     NewSpendingLimitClient(db),
+				Outbids:    NewOutbidNoticeClient(db),
+				Transactor: NewTransactor(db),
+			}
+		},
+	})
+}
+
+func TestPlaceBidAcceptsEachAmountOnceWhenConcurrent(t *testing.T) {
+	ctx := context.Background()
+	db := NewDatabase()
+	userClient := NewUserClient(db)
+	itemClient := NewAuctionItemClient(db)
+	bidClient := NewAuctionBidClient(db)
+
+	item := &model.AuctionItem{Name: "item"}
+	require.NoError(t, itemClient.Create(ctx, item))
+
+	const bidders = 10
+	users := make([]*model.User, bidders)
+	for i := range users {
+		users[i] = &model.User{Username: fmt.Sprintf("user%d", i)}
+		require.NoError(t, userClient.Create(ctx, users[i]))
+	}
+
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		accepted int
+	)
+	for _, user := range users {
+		wg.Add(1)
+		go func(user *model.User) {
+			defer wg.Done()
+			_, err := bidClient.PlaceBid(ctx, user, item, model.Money{Amount: 100, Currency: "USD"})
+			if err == nil {
+				lock.Lock()
+				accepted++
+				lock.Unlock()
+				return
+			}
+			assert.ErrorIs(t, err, storage.ErrBidTooLow)
+		}(user)
+	}
+	wg.Wait()
+
+	require.EqualValues(t, 1, accepted)
+	bid, err := bidClient.GetHighestBid(ctx, item)
+	require.NoError(t, err)
+	require.EqualValues(t, model.Money{Amount: 100, Currency: "USD"}, bid.BidAmount)
+}
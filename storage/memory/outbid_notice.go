@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+type outbidNoticeClient struct {
+	db *Database
+}
+
+// NewOutbidNoticeClient returns an object that can perform various operations on model.OutbidNotices.
+func NewOutbidNoticeClient(db *Database) storage.OutbidNoticeClient {
+	return &outbidNoticeClient{
+		db: db,
+	}
+}
+
+// Add stores the model.OutbidNotice in place of any earlier notice for the same user about the same item, or the same
+// lot when the item is sold in a lot. The ID of the notice is set once it is stored. This will return
+// storage.ErrEntityNotFound if the user or item does not exist.
+func (oc *outbidNoticeClient) Add(ctx context.Context, notice *model.OutbidNotice) error {
+	oc.db.lock.Lock()
+	defer oc.db.lock.Unlock()
+
+	if record, ok := oc.db.users[notice.Username]; !ok || record.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find user '%s'", notice.Username)
+	}
+	if _, record := oc.db.itemByID(notice.ItemID); record == nil || record.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find item %d", notice.ItemID)
+	}
+
+	outbids := oc.db.outbids[:0]
+	for _, outbid := range oc.db.outbids {
+		if !sameOutbidSubject(&outbid, notice) {
+			outbids = append(outbids, outbid)
+		}
+	}
+	oc.db.nextID++
+	notice.ID = oc.db.nextID
+	oc.db.outbids = append(outbids, *notice)
+	return nil
+}
+
+// Remove deletes the model.OutbidNotice with the ID. Nothing is removed if the notice was already taken or replaced.
+func (oc *outbidNoticeClient) Remove(ctx context.Context, id uint64) error {
+	oc.db.lock.Lock()
+	defer oc.db.lock.Unlock()
+
+	outbids := oc.db.outbids[:0]
+	for _, outbid := range oc.db.outbids {
+		if outbid.ID != id {
+			outbids = append(outbids, outbid)
+		}
+	}
+	oc.db.outbids = outbids
+	return nil
+}
+
+// Take retrieves and removes every model.OutbidNotice of the user in the order they were added.
+func (oc *outbidNoticeClient) Take(ctx context.Context, username string) ([]*model.OutbidNotice, error) {
+	oc.db.lock.Lock()
+	defer oc.db.lock.Unlock()
+
+	var result []*model.OutbidNotice
+	outbids := oc.db.outbids[:0]
+	for _, outbid := range oc.db.outbids {
+		if outbid.Username != username {
+			outbids = append(outbids, outbid)
+			continue
+		}
+		taken := outbid
+		result = append(result, &taken)
+	}
+	oc.db.outbids = outbids
+	return result, nil
+}
+
+// sameOutbidSubject is true when both notices tell the same user about the same item, or the same lot when the item is
+// sold in a lot.
+func sameOutbidSubject(notice *model.OutbidNotice, other *model.OutbidNotice) bool {
+	if notice.Username != other.Username {
+		return false
+	}
+	if notice.LotID != 0 || other.LotID != 0 {
+		return notice.LotID == other.LotID
+	}
+	return notice.ItemID == other.ItemID
+}
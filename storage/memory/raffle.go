@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+type raffleClient struct {
+	db *Database
+}
+
+// NewRaffleClient returns an object that can perform various operations on model.Raffles and model.TicketPurchases.
+func NewRaffleClient(db *Database) storage.RaffleClient {
+	return &raffleClient{
+		db: db,
+	}
+}
+
+// GetAll retrieves every model.Raffle in storage in the order they were created.
+func (rc *raffleClient) GetAll(ctx context.Context) ([]*model.Raffle, error) {
+	rc.db.lock.RLock()
+	defer rc.db.lock.RUnlock()
+
+	result := make([]*model.Raffle, 0, len(rc.db.raffles))
+	for _, raffle := range rc.db.raffles {
+		result = append(result, copyRaffle(raffle))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// GetByID retrieves the model.Raffle with the ID. This will return storage.ErrEntityNotFound if the ID is not found in
+// storage.
+func (rc *raffleClient) GetByID(ctx context.Context, id uint64) (*model.Raffle, error) {
+	rc.db.lock.RLock()
+	defer rc.db.lock.RUnlock()
+
+	raffle, ok := rc.db.raffles[id]
+	if !ok {
+		return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to find raffle %d", id)
+	}
+	return copyRaffle(raffle), nil
+}
+
+// Create adds a new model.Raffle to storage without a drawing. This will return storage.ErrEntityAlreadyExists if the
+// name is already found in storage, ignoring case.
+func (rc *raffleClient) Create(ctx context.Context, raffle *model.Raffle) error {
+	rc.db.lock.Lock()
+	defer rc.db.lock.Unlock()
+
+	for _, existing := range rc.db.raffles {
+		if strings.EqualFold(existing.Name, raffle.Name) {
+			return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to create raffle '%s'", raffle.Name)
+		}
+	}
+
+	rc.db.nextID++
+	raffle.ID = rc.db.nextID
+	raffle.Drawing = nil
+	rc.db.raffles[raffle.ID] = *raffle
+	return nil
+}
+
+// GetTickets retrieves every model.TicketPurchase of the raffle in the order they were bought. Purchases by deleted
+// users are included. This will return storage.ErrEntityNotFound if the raffle does not exist.
+func (rc *raffleClient) GetTickets(ctx context.Context, raffleID uint64) ([]*model.TicketPurchase, error) {
+	rc.db.lock.RLock()
+	defer rc.db.lock.RUnlock()
+
+	if _, ok := rc.db.raffles[raffleID]; !ok {
+		return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to find raffle %d", raffleID)
+	}
+
+	result := []*model.TicketPurchase{}
+	for _, record := range rc.db.tickets {
+		if record.raffleID == raffleID {
+			result = append(result, record.toModel())
+		}
+	}
+	return result, nil
+}
+
+// BuyTickets adds a new model.TicketPurchase by the buyer at the ticket price of the raffle. This will return
+// storage.ErrEntityNotFound if the buyer or the raffle does not exist and storage.ErrRaffleDrawn if the raffle was
+// already drawn.
+func (rc *raffleClient) BuyTickets(ctx context.Context, purchase *model.TicketPurchase) error {
+	rc.db.lock.Lock()
+	defer rc.db.lock.Unlock()
+
+	buyer, ok := rc.db.users[purchase.Buyer.Username]
+	if !ok || buyer.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find buyer '%s'", purchase.Buyer.Username)
+	}
+	raffle, ok := rc.db.raffles[purchase.RaffleID]
+	if !ok {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find raffle %d", purchase.RaffleID)
+	}
+	if raffle.Drawing != nil {
+		return errors.Wrapf(storage.ErrRaffleDrawn, "unable to buy tickets for raffle %d", raffle.ID)
+	}
+
+	rc.db.nextID++
+	record := &ticketRecord{
+		id:         rc.db.nextID,
+		raffleID:   raffle.ID,
+		buyer:      buyer,
+		quantity:   purchase.Quantity,
+		price:      raffle.TicketPrice,
+		recordedBy: purchase.RecordedBy,
+	}
+	rc.db.tickets = append(rc.db.tickets, record)
+
+	*purchase = *record.toModel()
+	return nil
+}
+
+// RecordDrawing stores the drawing of the model.Raffle with the ID. This will return storage.ErrEntityNotFound if the
+// raffle does not exist and storage.ErrRaffleDrawn if it was already drawn.
+func (rc *raffleClient) RecordDrawing(ctx context.Context, raffleID uint64, drawing *model.RaffleDrawing) error {
+	rc.db.lock.Lock()
+	defer rc.db.lock.Unlock()
+
+	raffle, ok := rc.db.raffles[raffleID]
+	if !ok {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find raffle %d", raffleID)
+	}
+	if raffle.Drawing != nil {
+		return errors.Wrapf(storage.ErrRaffleDrawn, "unable to draw raffle %d", raffleID)
+	}
+
+	raffle.Drawing = copyDrawing(drawing)
+	rc.db.raffles[raffleID] = raffle
+	return nil
+}
+
+// toModel copies the ticket purchase along with its buyer.
+func (record *ticketRecord) toModel() *model.TicketPurchase {
+	buyer := record.buyer.user
+	return &model.TicketPurchase{
+		ID:         record.id,
+		RaffleID:   record.raffleID,
+		Buyer:      &buyer,
+		Quantity:   record.quantity,
+		Price:      record.price,
+		RecordedBy: record.recordedBy,
+	}
+}
+
+// copyRaffle copies the raffle so changes to it or its drawing do not affect storage.
+func copyRaffle(raffle model.Raffle) *model.Raffle {
+	raffle.Drawing = copyDrawing(raffle.Drawing)
+	return &raffle
+}
+
+func copyDrawing(drawing *model.RaffleDrawing) *model.RaffleDrawing {
+	if drawing == nil {
+		return nil
+	}
+	copied := *drawing
+	copied.Tickets = append([]string(nil), drawing.Tickets...)
+	copied.Winners = append([]int(nil), drawing.Winners...)
+	return &copied
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+type spendingLimitClient struct {
+	db *Database
+}
+
+// NewSpendingLimitClient returns an object that can perform various operations on model.SpendingLimits.
+func NewSpendingLimitClient(db *Database) storage.SpendingLimitClient {
+	return &spendingLimitClient{
+		db: db,
+	}
+}
+
+// GetAll retrieves the model.SpendingLimit of the event, when there is one, followed by the limits of the users ordered
+// by username. Limits of deleted users are included.
+func (lc *spendingLimitClient) GetAll(ctx context.Context) ([]*model.SpendingLimit, error) {
+	lc.db.lock.RLock()
+	defer lc.db.lock.RUnlock()
+
+	result := make([]*model.SpendingLimit, 0, len(lc.db.limits))
+	for username, amount := range lc.db.limits {
+		result = append(result, &model.SpendingLimit{Username: username, Amount: amount})
+	}
+	// The limit of the event has an empty username so it is ordered first.
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Username < result[j].Username
+	})
+	return result, nil
+}
+
+// Get retrieves the model.SpendingLimit of the user, or of the event when the username is empty. This will return
+// storage.ErrEntityNotFound if there is no limit.
+func (lc *spendingLimitClient) Get(ctx context.Context, username string) (*model.SpendingLimit, error) {
+	lc.db.lock.RLock()
+	defer lc.db.lock.RUnlock()
+
+	amount, ok := lc.db.limits[username]
+	if !ok {
+		return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to find spending limit of '%s'", username)
+	}
+	return &model.SpendingLimit{Username: username, Amount: amount}, nil
+}
+
+// Set stores the model.SpendingLimit in place of the limit the user or the event already had. This will return
+// storage.ErrEntityNotFound if the user does not exist.
+func (lc *spendingLimitClient) Set(ctx context.Context, limit *model.SpendingLimit) error {
+	lc.db.lock.Lock()
+	defer lc.db.lock.Unlock()
+
+	if limit.Username != "" {
+		if record, ok := lc.db.users[limit.Username]; !ok || record.deleted() {
+			return errors.Wrapf(storage.ErrEntityNotFound, "unable to find user '%s'", limit.Username)
+		}
+	}
+	lc.db.limits[limit.Username] = limit.Amount
+	return nil
+}
+
+// Delete removes the model.SpendingLimit of the user, or of the event when the username is empty. This will return
+// storage.ErrEntityNotFound if there is no limit.
+func (lc *spendingLimitClient) Delete(ctx context.Context, username string) error {
+	lc.db.lock.Lock()
+	defer lc.db.lock.Unlock()
+
+	if _, ok := lc.db.limits[username]; !ok {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to delete spending limit of '%s'", username)
+	}
+	delete(lc.db.limits, username)
+	return nil
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/MMarsolek/AuctionHouse/storage"
+)
+
+type transactor struct {
+	db *Database
+}
+
+// NewTransactor returns an object that runs operations across the clients as a single unit of work.
+func NewTransactor(db *Database) storage.Transactor {
+	return &transactor{
+		db: db,
+	}
+}
+
+// RunInTx runs fn with clients that operate on a copy of the database. The copy replaces the contents of the database
+// only if fn succeeds. Every other operation on the database waits until fn returns.
+func (t *transactor) RunInTx(ctx context.Context, fn func(ctx context.Context, clients *storage.Clients) error) error {
+	t.db.lock.Lock()
+	defer t.db.lock.Unlock()
+
+	tx := t.db.clone()
+	err := fn(ctx, &storage.Clients{
+		Users:      NewUserClient(tx),
+		Items:      NewAuctionItemClient(tx),
+		Bids:       NewAuctionBidClient(tx),
+		Images:     NewItemImageClient(tx),
+		Categories: NewCategoryClient(tx),
+		Lots:       NewLotClient(tx),
+		Donations:  NewDonationClient(tx),
+		Raffles:    NewRaffleClient(tx),
+		Limits:     NewSpendingLimitClient(tx),
+		Outbids:    NewOutbidNoticeClient(tx),
+	})
+	if err != nil {
+		return err
+	}
+
+	t.db.nextID = tx.nextID
+	t.db.users = tx.users
+	t.db.items = tx.items
+	t.db.bids = tx.bids
+	t.db.images = tx.images
+	t.db.categories = tx.categories
+	t.db.tags = tx.tags
+	t.db.lots = tx.lots
+	t.db.levels = tx.levels
+	t.db.pledges = tx.pledges
+	t.db.raffles = tx.raffles
+	t.db.tickets = tx.tickets
+	t.db.limits = tx.limits
+	t.db.closings = tx.closings
+	t.db.outbids = tx.outbids
+	return nil
+}
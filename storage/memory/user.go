@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+)
+
+type userClient struct {
+	db *Database
+}
+
+// NewUserClient returns an object that can perform various operations on model.Users.
+func NewUserClient(db *Database) storage.UserClient {
+	return &userClient{
+		db: db,
+	}
+}
+
+// Get retrieves the model.User by the username. This will return storage.ErrEntityNotFound if the username is not
+// found in storage.
+func (uc *userClient) Get(ctx context.Context, username string) (*model.User, error) {
+	uc.db.lock.RLock()
+	defer uc.db.lock.RUnlock()
+
+	record, ok := uc.db.users[username]
+	if !ok || record.deleted() {
+		return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to get user with username '%s'", username)
+	}
+
+	user := record.user
+	return &user, nil
+}
+
+// GetDeleted retrieves all deleted model.Users in the order they were deleted.
+func (uc *userClient) GetDeleted(ctx context.Context) ([]*model.User, error) {
+	uc.db.lock.RLock()
+	defer uc.db.lock.RUnlock()
+
+	var records []*userRecord
+	for _, record := range uc.db.users {
+		if record.deleted() {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].user.DeletedAt.Before(records[j].user.DeletedAt)
+	})
+
+	result := make([]*model.User, len(records))
+	for i, record := range records {
+		user := record.user
+		result[i] = &user
+	}
+	return result, nil
+}
+
+// Delete marks the model.User as deleted by username. Their bids are kept. This will return storage.ErrEntityNotFound
+// if the username is not found in storage.
+func (uc *userClient) Delete(ctx context.Context, username string) error {
+	uc.db.lock.Lock()
+	defer uc.db.lock.Unlock()
+
+	record, ok := uc.db.users[username]
+	if !ok || record.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to delete user with username '%s'", username)
+	}
+
+	record.user.DeletedAt = time.Now().UTC()
+	return nil
+}
+
+// Restore brings back the deleted model.User with the username. This will return storage.ErrEntityNotFound if there
+// is no deleted user with the username.
+func (uc *userClient) Restore(ctx context.Context, username string) error {
+	uc.db.lock.Lock()
+	defer uc.db.lock.Unlock()
+
+	record, ok := uc.db.users[username]
+	if !ok || !record.deleted() {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find deleted user with username '%s'", username)
+	}
+
+	record.user.DeletedAt = time.Time{}
+	return nil
+}
+
+// Update changes the existing user by the non-zero fields of the provided model.User object.
+func (uc *userClient) Update(ctx context.Context, user *model.User) error {
+	uc.db.lock.Lock()
+	defer uc.db.lock.Unlock()
+
+	record, ok := uc.db.users[user.Username]
+	if !ok || record.deleted() {
+		return nil
+	}
+
+	if user.DisplayName != "" {
+		record.user.DisplayName = user.DisplayName
+	}
+	if user.HashedPassword != "" {
+		record.user.HashedPassword = user.HashedPassword
+	}
+	if user.Permission != "" {
+		record.user.Permission = user.Permission
+	}
+	return nil
+}
+
+// Create adds a new model.User to storage. This will return storage.ErrEntityAlreadyExists if the username is already
+// found in storage.
+func (uc *userClient) Create(ctx context.Context, user *model.User) error {
+	uc.db.lock.Lock()
+	defer uc.db.lock.Unlock()
+
+	if _, ok := uc.db.users[user.Username]; ok {
+		return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to create user %s", user.Username)
+	}
+
+	uc.db.users[user.Username] = &userRecord{
+		user: *user,
+	}
+	return nil
+}
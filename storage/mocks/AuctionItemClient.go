@@ -6,7 +6,10 @@ import (
 	context "context"
 
 	model "github.com/MMarsolek/AuctionHouse/model"
+	storage "github.com/MMarsolek/AuctionHouse/storage"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // AuctionItemClient is an autogenerated mock type for the AuctionItemClient type
@@ -14,6 +17,29 @@ type AuctionItemClient struct {
 	mock.Mock
 }
 
+// ClaimClosed provides a mock function with given fields: ctx, now
+func (_m *AuctionItemClient) ClaimClosed(ctx context.Context, now time.Time) ([]*model.AuctionItem, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 []*model.AuctionItem
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []*model.AuctionItem); ok {
+		r0 = rf(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuctionItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, item
 func (_m *AuctionItemClient) Create(ctx context.Context, item *model.AuctionItem) error {
 	ret := _m.Called(ctx, item)
@@ -88,6 +114,140 @@ func (_m *AuctionItemClient) GetAll(ctx context.Context) ([]*model.AuctionItem,
 	return r0, r1
 }
 
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *AuctionItemClient) GetByID(ctx context.Context, id uint64) (*model.AuctionItem, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.AuctionItem
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *model.AuctionItem); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuctionItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeleted provides a mock function with given fields: ctx
+func (_m *AuctionItemClient) GetDeleted(ctx context.Context) ([]*model.AuctionItem, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*model.AuctionItem
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.AuctionItem); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuctionItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMatching provides a mock function with given fields: ctx, filter
+func (_m *AuctionItemClient) GetMatching(ctx context.Context, filter storage.ItemFilter) ([]*model.AuctionItem, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*model.AuctionItem
+	if rf, ok := ret.Get(0).(func(context.Context, storage.ItemFilter) []*model.AuctionItem); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuctionItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, storage.ItemFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, options
+func (_m *AuctionItemClient) List(ctx context.Context, options storage.ListOptions) (*storage.ItemPage, error) {
+	ret := _m.Called(ctx, options)
+
+	var r0 *storage.ItemPage
+	if rf, ok := ret.Get(0).(func(context.Context, storage.ListOptions) *storage.ItemPage); ok {
+		r0 = rf(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.ItemPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, storage.ListOptions) error); ok {
+		r1 = rf(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rename provides a mock function with given fields: ctx, id, name
+func (_m *AuctionItemClient) Rename(ctx context.Context, id uint64, name string) error {
+	ret := _m.Called(ctx, id, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Replace provides a mock function with given fields: ctx, item
+func (_m *AuctionItemClient) Replace(ctx context.Context, item *model.AuctionItem) error {
+	ret := _m.Called(ctx, item)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AuctionItem) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *AuctionItemClient) Restore(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, item
 func (_m *AuctionItemClient) Update(ctx context.Context, item *model.AuctionItem) error {
 	ret := _m.Called(ctx, item)
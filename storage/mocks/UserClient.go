@@ -65,6 +65,43 @@ func (_m *UserClient) Get(ctx context.Context, username string) (*model.User, er
 	return r0, r1
 }
 
+// GetDeleted provides a mock function with given fields: ctx
+func (_m *UserClient) GetDeleted(ctx context.Context) ([]*model.User, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*model.User
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.User); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, username
+func (_m *UserClient) Restore(ctx context.Context, username string) error {
+	ret := _m.Called(ctx, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, user
 func (_m *UserClient) Update(ctx context.Context, user *model.User) error {
 	ret := _m.Called(ctx, user)
@@ -8,7 +8,7 @@ import (
 	"github.com/MMarsolek/AuctionHouse/storage"
 	"github.com/pkg/errors"
 	"github.com/uptrace/bun"
-	"modernc.org/sqlite"
+	"github.com/uptrace/bun/dialect"
 )
 
 type auctionBidClient struct {
@@ -24,17 +24,17 @@ func NewAuctionBidClient(db bun.IDB) storage.AuctionBidClient {
 	}
 }
 
-// GetHighestBid gets the highest bid for the specified item. This will return storage.ErrEntityNotFound if the item
-// does not have a bid.
+// GetHighestBid gets the highest bid for the specified item, or for its lot when it is sold in one. Bids by deleted
+// users still count. This will return storage.ErrEntityNotFound if the item does not have a bid or was deleted.
 func (bc *auctionBidClient) GetHighestBid(ctx context.Context, item *model.AuctionItem) (*model.AuctionBid, error) {
 	var bid AuctionBid
 	err := bc.db.NewSelect().
 		Model(&bid).
-		Relation("Bidder").
+		Relation("Bidder", withDeletedBidder).
 		Relation("Item").
 		Where("item_id = (?)", bc.getRelatedItemQuery(item)).
-		Group("item_id").
-		Having("MAX(bid_amount)").
+		OrderExpr("bid_amount DESC").
+		Limit(1).
 		Scan(ctx)
 
 	if err != nil {
@@ -43,22 +43,63 @@ func (bc *auctionBidClient) GetHighestBid(ctx context.Context, item *model.Aucti
 		}
 		return nil, errors.Wrap(err, "retrieving highest bid")
 	}
+	if err = loadItemDetails(ctx, bc.db, bid.Item); err != nil {
+		return nil, errors.Wrap(err, "retrieving highest bid")
+	}
 	return bid.ToModel(), nil
 }
 
-// GetAllHighestBids gets the highest bid for all items in storage.
-func (bc *auctionBidClient) GetAllHighestBids(ctx context.Context) ([]*model.AuctionBid, error) {
+// GetWinningBids gets the highest bid of each bidder that is winning the specified item, or its lot when it is sold in
+// one, from the highest bid down. Bids by deleted users still count. This will return storage.ErrEntityNotFound if the
+// item does not have a bid or was deleted.
+func (bc *auctionBidClient) GetWinningBids(ctx context.Context, item *model.AuctionItem) ([]*model.AuctionBid, error) {
 	var bids []*AuctionBid
 	err := bc.db.NewSelect().
 		Model(&bids).
-		Relation("Bidder").
+		Relation("Bidder", withDeletedBidder).
 		Relation("Item").
-		Group("item_id").
-		Having("MAX(bid_amount)").
+		Where("item_id = (?)", bc.getRelatedItemQuery(item)).
+		Where("auction_bid.id IN (" + winningBidIDsQuery + ")").
+		OrderExpr("bid_amount DESC, auction_bid.id").
 		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving winning bids")
+	}
+	if len(bids) == 0 {
+		return nil, errors.Wrap(storage.ErrEntityNotFound, "item does not have a bid")
+	}
 
+	return bc.toModels(ctx, bids, "retrieving winning bids")
+}
+
+// GetAllWinningBids gets the winning bids for all items in storage that have not been deleted, ordered by item and then
+// from the highest bid down. The bids on a lot are on its first item. Bids by deleted users still count.
+func (bc *auctionBidClient) GetAllWinningBids(ctx context.Context) ([]*model.AuctionBid, error) {
+	var bids []*AuctionBid
+	err := bc.db.NewSelect().
+		Model(&bids).
+		Relation("Bidder", withDeletedBidder).
+		Relation("Item").
+		Where("item.deleted_at IS NULL").
+		Where("auction_bid.id IN (" + winningBidIDsQuery + ")").
+		OrderExpr("item_id, bid_amount DESC, auction_bid.id").
+		Scan(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "retrieving all highest bids")
+		return nil, errors.Wrap(err, "retrieving all winning bids")
+	}
+
+	return bc.toModels(ctx, bids, "retrieving all winning bids")
+}
+
+// toModels loads the details of the items of the bids and transforms the bids into model.AuctionBids. The message is
+// used to wrap any error.
+func (bc *auctionBidClient) toModels(ctx context.Context, bids []*AuctionBid, message string) ([]*model.AuctionBid, error) {
+	items := make([]*AuctionItem, len(bids))
+	for i, bid := range bids {
+		items[i] = bid.Item
+	}
+	if err := loadItemDetails(ctx, bc.db, items...); err != nil {
+		return nil, errors.Wrap(err, message)
 	}
 
 	result := make([]*model.AuctionBid, len(bids))
@@ -68,11 +109,20 @@ func (bc *auctionBidClient) GetAllHighestBids(ctx context.Context) ([]*model.Auc
 	return result, nil
 }
 
-// PlaceBid creates a new bid by the specified user for the specified item. This will return storage.ErrBidTooLow if the
-// specified amount is lower than another bid in storage.
-func (bc *auctionBidClient) PlaceBid(ctx context.Context, user *model.User, item *model.AuctionItem, amount int) (*model.AuctionBid, error) {
+// PlaceBid creates a new bid by the specified user for the specified item. A bid on an item in a lot is placed on the
+// first item of the lot. This will return storage.ErrBidTooLow if the specified amount is not higher than the other bids
+// of the user or would not take one of the winning places from the other bidders, storage.ErrOverSpendingLimit if the
+// bid and the winning bids of the user on other items add up to more than their spending limit, and
+// storage.ErrEntityNotFound if the user or item does not exist. The bid is returned with its bidder and the item it was
+// placed on.
+func (bc *auctionBidClient) PlaceBid(ctx context.Context, user *model.User, item *model.AuctionItem, amount model.Money) (*model.AuctionBid, error) {
+	if err := bc.checkSpendingLimit(ctx, user, item, amount); err != nil {
+		return nil, errors.Wrap(err, "unable to insert new auction bid")
+	}
+
 	bid := &AuctionBid{
-		BidAmount: amount,
+		BidAmount: amount.Amount,
+		Currency:  amount.Currency,
 		Bidder:    UserToDBModel(user),
 		Item:      AuctionItemToDBModel(item),
 	}
@@ -84,25 +134,113 @@ func (bc *auctionBidClient) PlaceBid(ctx context.Context, user *model.User, item
 		Exec(ctx)
 
 	if err != nil {
-		var sqliteErr *sqlite.Error
-		if errors.As(err, &sqliteErr) && sqliteErr.Code() == 1811 {
+		if isBidTooLow(err) {
 			return nil, errors.Wrap(storage.ErrBidTooLow, "unable to insert new auction bid")
 		}
+		// The bidder or item ID is NULL when the subquery does not find a match.
+		if isNotNullViolation(err) {
+			return nil, errors.Wrap(storage.ErrEntityNotFound, "unable to find bidder or auction item")
+		}
 		return nil, errors.Wrap(err, "inserting new auction bid")
 	}
-	return nil, nil
+
+	// The bid is retrieved again since the bidder and item were only given by name and the bid may be on a lot.
+	var placed AuctionBid
+	err = bc.db.NewSelect().
+		Model(&placed).
+		Relation("Bidder", withDeletedBidder).
+		Relation("Item").
+		Where("auction_bid.id = ?", bid.ID).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving new auction bid")
+	}
+	if err = loadItemDetails(ctx, bc.db, placed.Item); err != nil {
+		return nil, errors.Wrap(err, "retrieving new auction bid")
+	}
+	return placed.ToModel(), nil
+}
+
+// checkSpendingLimit returns storage.ErrOverSpendingLimit if the amount and the winning bids of the user on every item
+// other than the one given add up to more than the limit of the user, or of the event when the user does not have one.
+// The bid takes the place of the winning bid of the user on the item so it does not count. Bids on deleted items do not
+// count either. model.ErrCurrencyMismatch is returned if the limit is in a different currency than the bid.
+func (bc *auctionBidClient) checkSpendingLimit(
+	ctx context.Context,
+	user *model.User,
+	item *model.AuctionItem,
+	amount model.Money,
+) error {
+	// Concurrent bids by the same user on different items would each leave out the other one when adding up the winning
+	// bids, so the row of the user is locked until the bid is stored. Transactions on SQLite already run one at a time.
+	if dialectName(bc.db) == dialect.PG {
+		_, err := bc.db.NewSelect().
+			Model((*User)(nil)).
+			Column("id").
+			Where("username = ?", user.Username).
+			For("UPDATE").
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "locking bidder")
+		}
+	}
+
+	var limit SpendingLimit
+	err := bc.db.NewSelect().
+		Model(&limit).
+		Where("user_id = (?) OR user_id IS NULL", bc.getRelatedUserQuery(user)).
+		OrderExpr("user_id IS NULL").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return errors.Wrap(err, "retrieving spending limit")
+	}
+
+	var committed int64
+	err = bc.db.NewSelect().
+		Model((*AuctionBid)(nil)).
+		ColumnExpr("COALESCE(SUM(auction_bid.bid_amount), 0)").
+		Join("JOIN auction_items AS item ON item.id = auction_bid.item_id").
+		Where("item.deleted_at IS NULL").
+		Where("auction_bid.id IN ("+winningBidIDsQuery+")").
+		Where("auction_bid.bidder_id = (?)", bc.getRelatedUserQuery(user)).
+		Where("auction_bid.item_id <> (?)", bc.getRelatedItemQuery(item)).
+		Scan(ctx, &committed)
+	if err != nil {
+		return errors.Wrap(err, "adding up winning bids")
+	}
+
+	if limit.Currency != amount.Currency {
+		return errors.Wrapf(model.ErrCurrencyMismatch, "the spending limit is in %s", limit.Currency)
+	}
+	if committed+amount.Amount > limit.Amount {
+		return errors.Wrapf(storage.ErrOverSpendingLimit, "%d is already committed", committed)
+	}
+	return nil
 }
 
+// withDeletedBidder joins the bidder even when they were deleted so the bids of deleted users keep counting. bun applies
+// this to every relation of the query so deleted items must be filtered out by the query itself.
+func withDeletedBidder(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.WhereAllWithDeleted()
+}
+
+// getRelatedItemQuery selects the ID of the item that bids on the item are placed on, which is the first item of its lot
+// when it is sold in one. Deleted items are not selected.
 func (bc *auctionBidClient) getRelatedItemQuery(item *model.AuctionItem) *bun.SelectQuery {
 	return bc.db.NewSelect().
-		Model(item).
-		Column("id").
+		Model((*AuctionItem)(nil)).
+		ColumnExpr(bidItemIDExpr).
 		Where("name_id = ?", getAuctionItemNameID(item.Name))
 }
 
+// getRelatedUserQuery selects the ID of the user. Deleted users are not selected.
 func (bc *auctionBidClient) getRelatedUserQuery(user *model.User) *bun.SelectQuery {
 	return bc.db.NewSelect().
-		Model(user).
+		Model((*User)(nil)).
 		Column("id").
 		Where("username = ?", user.Username)
 }
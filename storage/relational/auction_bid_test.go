@@ -2,19 +2,18 @@ package relational
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 
 	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/storage"
 	"github.com/stretchr/testify/suite"
 	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/dialect/sqlitedialect"
 )
 
 type auctionBidClientTestSuite struct {
 	suite.Suite
 
+	dsn        string
 	ctx        context.Context
 	db         bun.IDB
 	client     *auctionBidClient
@@ -23,16 +22,17 @@ type auctionBidClientTestSuite struct {
 }
 
 func (ts *auctionBidClientTestSuite) SetupSuite() {
-	rawDB, err := sql.Open("sqlite", "file::memory:?_pragma=cache%3Dshared&_pragma=foreign_keys%3Dtrue")
+	db, err := Open(ts.dsn)
 	ts.Require().NoError(err)
 
 	ts.ctx = context.Background()
-	ts.db = bun.NewDB(rawDB, sqlitedialect.New())
+	ts.db = db
 	ts.client = &auctionBidClient{baseClient{ts.db}}
 	ts.userClient = &userClient{baseClient{ts.db}}
 	ts.itemClient = &auctionItemClient{baseClient{ts.db}}
 
-	ts.Require().NoError(CreateSchema(ts.ctx, ts.db))
+	_, err = NewMigrator(db).Up(ts.ctx)
+	ts.Require().NoError(err)
 }
 
 func (ts *auctionBidClientTestSuite) SetupTest() {
@@ -48,52 +48,54 @@ func (ts *auctionBidClientTestSuite) SetupTest() {
 }
 
 func TestAuctionBidClient(t *testing.T) {
-	suite.Run(t, new(auctionBidClientTestSuite))
+	runOnAllDatabases(t, func(t *testing.T, dsn string) {
+		suite.Run(t, &auctionBidClientTestSuite{dsn: dsn})
+	})
 }
 
 func (ts *auctionBidClientTestSuite) TestPlaceBidAddsNewBids() {
 	users, items := ts.createTestAssets()
 
-	_, err := ts.client.PlaceBid(ts.ctx, users[0], items[0], 10)
+	_, err := ts.client.PlaceBid(ts.ctx, users[0], items[0], usd(10))
 	ts.Require().NoError(err)
 }
 
 func (ts *auctionBidClientTestSuite) TestPlaceBidDoesNotAllowSmallerBidsForItem() {
 	users, items := ts.createTestAssets()
 
-	_, err := ts.client.PlaceBid(ts.ctx, users[0], items[0], 10)
+	_, err := ts.client.PlaceBid(ts.ctx, users[0], items[0], usd(10))
 	ts.Require().NoError(err)
 
-	_, err = ts.client.PlaceBid(ts.ctx, users[1], items[0], 20)
+	_, err = ts.client.PlaceBid(ts.ctx, users[1], items[0], usd(20))
 	ts.Require().NoError(err)
 
-	_, err = ts.client.PlaceBid(ts.ctx, users[0], items[0], 15)
+	_, err = ts.client.PlaceBid(ts.ctx, users[0], items[0], usd(15))
 	ts.Require().Error(err)
 	ts.Require().ErrorIs(err, storage.ErrBidTooLow)
 
-	_, err = ts.client.PlaceBid(ts.ctx, users[0], items[0], 40)
+	_, err = ts.client.PlaceBid(ts.ctx, users[0], items[0], usd(40))
 	ts.Require().NoError(err)
 }
 
 func (ts *auctionBidClientTestSuite) TestGetHighestBidReturnsHighestBidForItem() {
 	users, items := ts.createTestAssets()
 
-	_, err := ts.client.PlaceBid(ts.ctx, users[0], items[0], 10)
+	_, err := ts.client.PlaceBid(ts.ctx, users[0], items[0], usd(10))
 	ts.Require().NoError(err)
 
-	_, err = ts.client.PlaceBid(ts.ctx, users[1], items[0], 20)
+	_, err = ts.client.PlaceBid(ts.ctx, users[1], items[0], usd(20))
 	ts.Require().NoError(err)
 
-	_, err = ts.client.PlaceBid(ts.ctx, users[0], items[1], 10)
+	_, err = ts.client.PlaceBid(ts.ctx, users[0], items[1], usd(10))
 	ts.Require().NoError(err)
 
 	firstItemBid, err := ts.client.GetHighestBid(ts.ctx, items[0])
 	ts.Require().NoError(err)
-	ts.Require().EqualValues(20, firstItemBid.BidAmount)
+	ts.Require().EqualValues(usd(20), firstItemBid.BidAmount)
 
 	secondItemBid, err := ts.client.GetHighestBid(ts.ctx, items[1])
 	ts.Require().NoError(err)
-	ts.Require().EqualValues(10, secondItemBid.BidAmount)
+	ts.Require().EqualValues(usd(10), secondItemBid.BidAmount)
 }
 
 func (ts *auctionBidClientTestSuite) TestGetHighestBidReturnsEntityNotFoundWhenNoHighestBid() {
@@ -107,45 +109,45 @@ func (ts *auctionBidClientTestSuite) TestGetHighestBidReturnsEntityNotFoundWhenN
 func (ts *auctionBidClientTestSuite) TestGetAllHighestBidReturnsHighestBidForEachItem() {
 	users, items := ts.createTestAssets()
 
-	_, err := ts.client.PlaceBid(ts.ctx, users[0], items[0], 10)
+	_, err := ts.client.PlaceBid(ts.ctx, users[0], items[0], usd(10))
 	ts.Require().NoError(err)
 
-	_, err = ts.client.PlaceBid(ts.ctx, users[1], items[0], 20)
+	_, err = ts.client.PlaceBid(ts.ctx, users[1], items[0], usd(20))
 	ts.Require().NoError(err)
 
-	_, err = ts.client.PlaceBid(ts.ctx, users[0], items[1], 10)
+	_, err = ts.client.PlaceBid(ts.ctx, users[0], items[1], usd(10))
 	ts.Require().NoError(err)
 
-	_, err = ts.client.PlaceBid(ts.ctx, users[2], items[1], 100)
+	_, err = ts.client.PlaceBid(ts.ctx, users[2], items[1], usd(100))
 	ts.Require().NoError(err)
 
-	highestBids, err := ts.client.GetAllHighestBids(ts.ctx)
+	highestBids, err := ts.client.GetAllWinningBids(ts.ctx)
 	ts.Require().NoError(err)
 
 	ts.Require().Len(highestBids, 2)
-	ts.Require().EqualValues(20, highestBids[0].BidAmount)
+	ts.Require().EqualValues(usd(20), highestBids[0].BidAmount)
 	ts.Require().EqualValues(users[1].DisplayName, highestBids[0].Bidder.DisplayName)
-	ts.Require().EqualValues(100, highestBids[1].BidAmount)
+	ts.Require().EqualValues(usd(100), highestBids[1].BidAmount)
 	ts.Require().EqualValues(users[2].DisplayName, highestBids[1].Bidder.DisplayName)
 }
 
-func (ts *auctionBidClientTestSuite) TestDeletingAnItemRemovesBids() {
+func (ts *auctionBidClientTestSuite) TestDeletingAnItemHidesBids() {
 	users, items := ts.createTestAssets()
 
-	_, err := ts.client.PlaceBid(ts.ctx, users[0], items[0], 10)
+	_, err := ts.client.PlaceBid(ts.ctx, users[0], items[0], usd(10))
 	ts.Require().NoError(err)
-	_, err = ts.client.PlaceBid(ts.ctx, users[0], items[1], 100)
+	_, err = ts.client.PlaceBid(ts.ctx, users[0], items[1], usd(100))
 	ts.Require().NoError(err)
 
-	highestBids, err := ts.client.GetAllHighestBids(ts.ctx)
+	highestBids, err := ts.client.GetAllWinningBids(ts.ctx)
 	ts.Require().NoError(err)
 	ts.Require().Len(highestBids, 2)
-	ts.Require().EqualValues(highestBids[0].BidAmount, 10)
+	ts.Require().EqualValues(highestBids[0].BidAmount, usd(10))
 
 	ts.Require().NoError(ts.itemClient.Delete(ts.ctx, items[0].Name))
-	highestBids, err = ts.client.GetAllHighestBids(ts.ctx)
+	highestBids, err = ts.client.GetAllWinningBids(ts.ctx)
 	ts.Require().Len(highestBids, 1)
-	ts.Require().EqualValues(highestBids[0].BidAmount, 100)
+	ts.Require().EqualValues(highestBids[0].BidAmount, usd(100))
 }
 
 func (ts *auctionBidClientTestSuite) createTestAssets() ([]*model.User, []*model.AuctionItem) {
@@ -193,3 +195,8 @@ func (ts *auctionBidClientTestSuite) createTestAssets() ([]*model.User, []*model
 
 	return users, items
 }
+
+// usd is an amount of cents.
+func usd(amount int64) model.Money {
+	return model.Money{Amount: amount, Currency: "USD"}
+}
@@ -2,11 +2,46 @@ package relational
 
 import (
 	"context"
+	"database/sql"
+	"strings"
+	"time"
 
 	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/storage"
 	"github.com/pkg/errors"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+const (
+	// bidItemIDExpr is the ID of the item that bids on the item of each row of an auction_items query are placed on.
+	// Bids on a lot are placed on its first item so every item of the lot shares them.
+	bidItemIDExpr = "COALESCE((SELECT MIN(lot_item.id) FROM auction_items AS lot_item " +
+		"WHERE lot_item.lot_id = auction_item.lot_id), auction_item.id)"
+
+	// currentBidExpr is the highest bid on the item of each row of an auction_items query, or 0 without bids.
+	currentBidExpr = "COALESCE((SELECT MAX(bid.bid_amount) FROM auction_bids AS bid " +
+		"WHERE bid.item_id = " + bidItemIDExpr + "), 0)"
+
+	// bidCountExpr is how many bids were placed on the item of each row of an auction_items query.
+	bidCountExpr = "(SELECT COUNT(*) FROM auction_bids AS bid WHERE bid.item_id = " + bidItemIDExpr + ")"
+
+	// closingTimeExpr is when bidding on the item of each row of an auction_items query closes. Items without a closing
+	// time close at storage.NeverCloses, written the way bun writes times.
+	closingTimeExpr = "COALESCE(auction_item.closes_at, '9999-12-31 00:00:00+00:00')"
+
+	// winningBidIDsQuery selects the IDs of the bids that are winning their item. Only the highest bid of each bidder is
+	// ranked, ties go to whoever bid first and as many bidders win as the quantity of the item.
+	winningBidIDsQuery = "SELECT ranked.id FROM (" +
+		"SELECT best.id, best.item_id, " +
+		"ROW_NUMBER() OVER (PARTITION BY best.item_id ORDER BY best.bid_amount DESC, best.id) AS place " +
+		"FROM (" +
+		"SELECT id, item_id, bid_amount, " +
+		"ROW_NUMBER() OVER (PARTITION BY item_id, bidder_id ORDER BY bid_amount DESC, id) AS bidder_place " +
+		"FROM auction_bids" +
+		") AS best WHERE best.bidder_place = 1" +
+		") AS ranked JOIN auction_items AS ranked_item ON ranked_item.id = ranked.item_id " +
+		"WHERE ranked.place <= ranked_item.quantity"
 )
 
 type auctionItemClient struct {
@@ -31,6 +66,23 @@ func (ac *auctionItemClient) Get(ctx context.Context, name string) (*model.Aucti
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get auction item with name '%s'", nameID)
 	}
+	if err = loadItemDetails(ctx, ac.db, &item); err != nil {
+		return nil, errors.Wrapf(err, "unable to get auction item with name '%s'", nameID)
+	}
+	return item.ToModel(), nil
+}
+
+// GetByID retrieves the model.AuctionItem by the ID. This will return storage.ErrEntityNotFound if the ID is not found
+// in storage.
+func (ac *auctionItemClient) GetByID(ctx context.Context, id uint64) (*model.AuctionItem, error) {
+	var item AuctionItem
+	err := ac.baseClient.get(ctx, &item, "id", id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get auction item with ID %d", id)
+	}
+	if err = loadItemDetails(ctx, ac.db, &item); err != nil {
+		return nil, errors.Wrapf(err, "unable to get auction item with ID %d", id)
+	}
 	return item.ToModel(), nil
 }
 
@@ -40,6 +92,145 @@ func (ac *auctionItemClient) GetAll(ctx context.Context) ([]*model.AuctionItem,
 	if err := ac.baseClient.getAll(ctx, &dbModels); err != nil {
 		return nil, errors.Wrapf(err, "unable to get all auction items")
 	}
+	if err := loadItemDetails(ctx, ac.db, dbModels...); err != nil {
+		return nil, errors.Wrapf(err, "unable to get all auction items")
+	}
+
+	result := make([]*model.AuctionItem, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel()
+	}
+
+	return result, nil
+}
+
+// GetMatching retrieves every model.AuctionItem in the category of the filter that has all of its tags, in the order
+// they were created. A category that does not exist matches no items.
+func (ac *auctionItemClient) GetMatching(ctx context.Context, filter storage.ItemFilter) ([]*model.AuctionItem, error) {
+	var dbModels []*AuctionItem
+	query := ac.filter(ac.db.NewSelect().Model(&dbModels), filter).
+		Order("id")
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, errors.Wrap(err, "unable to get matching auction items")
+	}
+	if err := loadItemDetails(ctx, ac.db, dbModels...); err != nil {
+		return nil, errors.Wrap(err, "unable to get matching auction items")
+	}
+
+	result := make([]*model.AuctionItem, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel()
+	}
+	return result, nil
+}
+
+// List retrieves the page of model.AuctionItems that match the options, starting after the cursor of the options. This
+// will return storage.ErrInvalidCursor if the cursor does not continue a listing in the same order.
+func (ac *auctionItemClient) List(ctx context.Context, options storage.ListOptions) (*storage.ItemPage, error) {
+	cursor, err := storage.DecodeItemCursor(options)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list auction items")
+	}
+
+	var dbModels []*AuctionItem
+	query := ac.filter(ac.db.NewSelect().Model(&dbModels), options.ItemFilter)
+	if terms := storage.SearchTerms(options.Search); len(terms) > 0 {
+		if query, err = search(query, terms); err != nil {
+			return nil, errors.Wrap(err, "unable to list auction items")
+		}
+	}
+
+	direction, comparison := "ASC", ">"
+	if options.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	var sortExpr string
+	var cursorValue interface{}
+	switch sortBy := options.SortOrDefault(); sortBy {
+	case storage.ItemSortCreated:
+	case storage.ItemSortName:
+		sortExpr = "auction_item.name_id"
+		if cursor != nil {
+			cursorValue = cursor.NameID
+		}
+	case storage.ItemSortCurrentBid, storage.ItemSortBidCount:
+		sortExpr = currentBidExpr
+		if sortBy == storage.ItemSortBidCount {
+			sortExpr = bidCountExpr
+		}
+		if cursor != nil {
+			cursorValue = cursor.Value
+		}
+		query = query.ColumnExpr("auction_item.*").ColumnExpr("? AS sort_value", bun.Safe(sortExpr))
+	case storage.ItemSortClosing:
+		sortExpr = closingTimeExpr
+		if cursor != nil && cursor.ClosesAt != nil {
+			cursorValue = *cursor.ClosesAt
+		} else if cursor != nil {
+			return nil, errors.Wrap(storage.ErrInvalidCursor, "cursor has no closing time")
+		}
+	default:
+		return nil, errors.Errorf("unable to list auction items sorted by unknown order '%s'", sortBy)
+	}
+
+	if sortExpr != "" {
+		query = query.OrderExpr("? "+direction, bun.Safe(sortExpr))
+		if cursor != nil {
+			query = query.Where(
+				"? "+comparison+" ? OR (? = ? AND auction_item.id "+comparison+" ?)",
+				bun.Safe(sortExpr), cursorValue, bun.Safe(sortExpr), cursorValue, cursor.ID,
+			)
+		}
+	} else if cursor != nil {
+		query = query.Where("auction_item.id "+comparison+" ?", cursor.ID)
+	}
+	query = query.OrderExpr("auction_item.id " + direction)
+
+	// One more item than the limit is retrieved to know whether there is a next page.
+	if options.Limit > 0 {
+		query = query.Limit(options.Limit + 1)
+	}
+	if err = query.Scan(ctx); err != nil {
+		return nil, errors.Wrap(err, "unable to list auction items")
+	}
+
+	page := &storage.ItemPage{}
+	if options.Limit > 0 && len(dbModels) > options.Limit {
+		dbModels = dbModels[:options.Limit]
+		last := dbModels[len(dbModels)-1]
+		nameID := ""
+		if options.SortOrDefault() == storage.ItemSortName {
+			nameID = last.NameID
+		}
+		nextCursor := storage.NewItemCursor(options, nameID, last.SortValue, last.ID)
+		if options.SortOrDefault() == storage.ItemSortClosing {
+			closesAt := storage.ClosingTimeOrNever(last.ClosesAt)
+			nextCursor.ClosesAt = &closesAt
+		}
+		page.NextCursor = nextCursor.Encode()
+	}
+	if err = loadItemDetails(ctx, ac.db, dbModels...); err != nil {
+		return nil, errors.Wrap(err, "unable to list auction items")
+	}
+
+	page.Items = make([]*model.AuctionItem, len(dbModels))
+	for i, dbModel := range dbModels {
+		page.Items[i] = dbModel.ToModel()
+	}
+	return page, nil
+}
+
+// GetDeleted retrieves all deleted model.AuctionItems in the order they were deleted.
+func (ac *auctionItemClient) GetDeleted(ctx context.Context) ([]*model.AuctionItem, error) {
+	var dbModels []*AuctionItem
+	if err := ac.baseClient.getAllDeleted(ctx, &dbModels); err != nil {
+		return nil, errors.Wrapf(err, "unable to get deleted auction items")
+	}
+	if err := loadItemDetails(ctx, ac.db, dbModels...); err != nil {
+		return nil, errors.Wrapf(err, "unable to get deleted auction items")
+	}
 
 	result := make([]*model.AuctionItem, len(dbModels))
 	for i, dbModel := range dbModels {
@@ -49,38 +240,417 @@ func (ac *auctionItemClient) GetAll(ctx context.Context) ([]*model.AuctionItem,
 	return result, nil
 }
 
-// Delete removes the model.AuctionItem from storage by name. This will return storage.ErrEntityNotFound if the name is
-// not found in storage.
+// Delete marks the model.AuctionItem as deleted by name. Its bids are kept but are hidden along with the item. This
+// will return storage.ErrEntityNotFound if the name is not found in storage and storage.ErrItemInLot if the item is sold
+// in a lot.
 func (ac *auctionItemClient) Delete(ctx context.Context, name string) error {
 	nameID := getAuctionItemNameID(name)
-	err := ac.baseClient.delete(ctx, (*AuctionItem)(nil), "name_id", nameID)
+	inLot, err := ac.db.NewSelect().
+		Model((*AuctionItem)(nil)).
+		Where("name_id = ?", nameID).
+		Where("lot_id IS NOT NULL").
+		Exists(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to delete auction item with name '%s'", nameID)
+	}
+	if inLot {
+		return errors.Wrapf(storage.ErrItemInLot, "unable to delete auction item with name '%s'", nameID)
+	}
+
+	err = ac.baseClient.delete(ctx, &AuctionItem{}, "name_id", nameID)
 	if err != nil {
 		return errors.Wrapf(err, "unable to delete auction item with name '%s'", nameID)
 	}
 	return nil
 }
 
-// Update changes the existing item by the non-zero fields of the provided model.AuctionItem object.
+// Restore brings back the deleted model.AuctionItem with the ID along with its bids. This will return
+// storage.ErrEntityNotFound if there is no deleted item with the ID.
+func (ac *auctionItemClient) Restore(ctx context.Context, id uint64) error {
+	err := ac.baseClient.restore(ctx, &AuctionItem{}, "id", id)
+	if err != nil {
+		return errors.Wrapf(err, "unable to restore auction item %d", id)
+	}
+	return nil
+}
+
+// Update changes the existing item by the non-zero fields of the provided model.AuctionItem object. This will return
+// storage.ErrUnknownCategory if the category does not exist.
 func (ac *auctionItemClient) Update(ctx context.Context, item *model.AuctionItem) error {
 	dbModel := AuctionItemToDBModel(item)
 	nameID := getAuctionItemNameID(item.Name)
-	err := ac.baseClient.update(ctx, dbModel, "name_id", nameID)
+	columns := []string{"display_name"}
+	if item.ImageRef != "" {
+		columns = append(columns, "image_ref")
+	}
+	if item.Description != "" {
+		columns = append(columns, "description")
+	}
+	if item.Category != "" {
+		categoryID, err := ac.categoryID(ctx, item.Category)
+		if err != nil {
+			return errors.Wrapf(err, "unable to update auction item %s", nameID)
+		}
+		dbModel.CategoryID = categoryID
+		columns = append(columns, "category_id")
+	}
+	if item.DonorName != "" {
+		columns = append(columns, "donor_name")
+	}
+	if item.DonorBusiness != "" {
+		columns = append(columns, "donor_business")
+	}
+	if item.FairMarketValue.Amount != 0 {
+		columns = append(columns, "fair_market_value", "fair_market_value_currency")
+	}
+	if item.Quantity > 0 {
+		columns = append(columns, "quantity")
+	}
+	if !item.ClosesAt.IsZero() {
+		columns = append(columns, "closes_at")
+	}
+
+	err := ac.baseClient.update(ctx, dbModel, "name_id", nameID, columns...)
 	if err != nil {
 		return errors.Wrapf(err, "unable to update auction item %s", nameID)
 	}
+
+	if len(item.Tags) > 0 {
+		var existing AuctionItem
+		err = ac.db.NewSelect().Model(&existing).Column("id").Where("name_id = ?", nameID).Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		} else if err != nil {
+			return errors.Wrapf(err, "unable to update tags of auction item %s", nameID)
+		}
+		if err = ac.setTags(ctx, existing.ID, item.Tags); err != nil {
+			return errors.Wrapf(err, "unable to update tags of auction item %s", nameID)
+		}
+	}
 	return nil
 }
 
-// Create adds a new model.AuctionItem to storage. This will return storage.ErrEntityAlreadyExists if the name is already
-// found in storage.
+// Create adds a new model.AuctionItem to storage along with its tags. This will return storage.ErrEntityAlreadyExists if
+// the name is already found in storage and storage.ErrUnknownCategory if the category does not exist.
 func (ac *auctionItemClient) Create(ctx context.Context, item *model.AuctionItem) error {
 	dbModel := AuctionItemToDBModel(item)
 	nameID := getAuctionItemNameID(item.Name)
-	err := ac.baseClient.create(ctx, dbModel)
+	categoryID, err := ac.categoryID(ctx, item.Category)
 	if err != nil {
 		return errors.Wrapf(err, "unable to create auction item %s", nameID)
 	}
+	dbModel.CategoryID = categoryID
+
+	err = ac.baseClient.create(ctx, dbModel)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create auction item %s", nameID)
+	}
+	if err = ac.setTags(ctx, dbModel.ID, item.Tags); err != nil {
+		return errors.Wrapf(err, "unable to create auction item %s", nameID)
+	}
+	if err = loadItemDetails(ctx, ac.db, dbModel); err != nil {
+		return errors.Wrapf(err, "unable to create auction item %s", nameID)
+	}
 
 	*item = *dbModel.ToModel()
 	return nil
 }
+
+// Rename changes the name of the model.AuctionItem with the ID. Bids refer to the item by ID so they are kept. This will
+// return storage.ErrEntityNotFound if the ID is not found in storage and storage.ErrEntityAlreadyExists if another item
+// already has the name.
+func (ac *auctionItemClient) Rename(ctx context.Context, id uint64, name string) error {
+	dbModel := &AuctionItem{
+		NameID:      getAuctionItemNameID(name),
+		DisplayName: name,
+	}
+
+	results, err := ac.db.NewUpdate().
+		Model(dbModel).
+		Column("name_id", "display_name", "updated_at", "version").
+		Value("version", "version + 1").
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to rename auction item %d to '%s'", id, name)
+		}
+		return errors.Wrapf(err, "unable to rename auction item %d", id)
+	}
+
+	affected, err := results.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to determine rows affected")
+	}
+	if affected <= 0 {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find auction item %d", id)
+	}
+	return nil
+}
+
+// Replace overwrites every field of the model.AuctionItem with the ID, including the empty ones, as long as the item is
+// still at item.Version. item.Version is set to the new version and a quantity that is not set is stored as 1. This
+// will return storage.ErrEntityNotFound if the ID is not found in storage, storage.ErrVersionMismatch if the item was
+// changed since item.Version, storage.ErrEntityAlreadyExists if another item already has the name and
+// storage.ErrUnknownCategory if the category does not exist.
+func (ac *auctionItemClient) Replace(ctx context.Context, item *model.AuctionItem) error {
+	dbModel := AuctionItemToDBModel(item)
+	categoryID, err := ac.categoryID(ctx, item.Category)
+	if err != nil {
+		return errors.Wrapf(err, "unable to replace auction item %d", item.ID)
+	}
+	dbModel.CategoryID = categoryID
+
+	results, err := ac.db.NewUpdate().
+		Model(dbModel).
+		Column(
+			"name_id", "display_name", "image_ref", "description", "category_id", "donor_name", "donor_business",
+			"fair_market_value", "fair_market_value_currency", "quantity", "closes_at", "updated_at", "version",
+		).
+		Value("version", "version + 1").
+		Where("id = ?", item.ID).
+		Where("version = ?", item.Version).
+		Exec(ctx)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to rename auction item %d to '%s'", item.ID, item.Name)
+		}
+		return errors.Wrapf(err, "unable to replace auction item %d", item.ID)
+	}
+
+	affected, err := results.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to determine rows affected")
+	}
+	if affected <= 0 {
+		// Nothing tells the missing item apart from the changed one other than looking it up.
+		if _, err = ac.GetByID(ctx, item.ID); err != nil {
+			return errors.Wrapf(err, "unable to replace auction item %d", item.ID)
+		}
+		return errors.Wrapf(storage.ErrVersionMismatch, "auction item %d is no longer at version %d", item.ID, item.Version)
+	}
+	if err = ac.setTags(ctx, item.ID, item.Tags); err != nil {
+		return errors.Wrapf(err, "unable to replace tags of auction item %d", item.ID)
+	}
+
+	item.Quantity = dbModel.Quantity
+	item.Version++
+	return nil
+}
+
+// ClaimClosed retrieves the model.AuctionItems whose closing time has passed by now and records their closing times so
+// they are not retrieved again, ordered by closing time. An item whose closing time changes is retrieved again once the
+// new time has passed. Since the closing time is recorded only if it was not already, every closing is claimed by just
+// one of the callers sharing the database. Deleted items are not retrieved.
+func (ac *auctionItemClient) ClaimClosed(ctx context.Context, now time.Time) ([]*model.AuctionItem, error) {
+	var dbModels []*AuctionItem
+	err := ac.db.NewSelect().
+		Model(&dbModels).
+		Where("auction_item.closes_at <= ?", now).
+		Where(`NOT EXISTS (SELECT 1 FROM item_closings AS closing
+			WHERE closing.item_id = auction_item.id AND closing.closes_at = auction_item.closes_at)`).
+		OrderExpr("auction_item.closes_at, auction_item.id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get closed auction items")
+	}
+
+	claimed := make([]*AuctionItem, 0, len(dbModels))
+	for _, dbModel := range dbModels {
+		// The closing time is copied by the database so it matches the item exactly. Another caller that recorded it
+		// first leaves no rows affected.
+		affected, err := rowsAffected(ac.db.ExecContext(ctx, `
+			INSERT INTO item_closings (item_id, closes_at)
+			SELECT id, closes_at FROM auction_items WHERE id = ?
+			ON CONFLICT (item_id) DO UPDATE SET closes_at = excluded.closes_at
+			WHERE item_closings.closes_at <> excluded.closes_at`, dbModel.ID))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to claim closing of auction item %d", dbModel.ID)
+		}
+		if affected > 0 {
+			claimed = append(claimed, dbModel)
+		}
+	}
+
+	if err = loadItemDetails(ctx, ac.db, claimed...); err != nil {
+		return nil, errors.Wrap(err, "unable to get closed auction items")
+	}
+	result := make([]*model.AuctionItem, len(claimed))
+	for i, dbModel := range claimed {
+		result[i] = dbModel.ToModel()
+	}
+	return result, nil
+}
+
+// filter narrows down the query of auction items to the ones in the category of the filter that have all of its tags.
+func (ac *auctionItemClient) filter(query *bun.SelectQuery, filter storage.ItemFilter) *bun.SelectQuery {
+	if filter.Category != "" {
+		query = query.Where("auction_item.category_id = (?)", ac.db.NewSelect().
+			Model((*Category)(nil)).
+			Column("id").
+			Where("name_id = ?", getCategoryNameID(filter.Category)))
+	}
+	for _, tag := range filter.Tags {
+		query = query.Where("EXISTS (?)", ac.db.NewSelect().
+			Model((*ItemTag)(nil)).
+			Join("JOIN tags AS tag ON tag.id = item_tag.tag_id").
+			Where("item_tag.item_id = auction_item.id").
+			Where("tag.name_id = ?", getTagNameID(tag)))
+	}
+	return query
+}
+
+// search narrows down the query of auction items to the ones whose name or description has a word starting with each
+// of the terms. The terms must only contain letters and digits, as returned by storage.SearchTerms, so they cannot
+// change the meaning of the full-text query.
+func search(query *bun.SelectQuery, terms []string) (*bun.SelectQuery, error) {
+	switch name := query.DB().Dialect().Name(); name {
+	case dialect.SQLite:
+		match := make([]string, len(terms))
+		for i, term := range terms {
+			match[i] = `"` + term + `"*`
+		}
+		return query.Where(
+			"auction_item.id IN (SELECT rowid FROM auction_items_fts WHERE auction_items_fts MATCH ?)",
+			strings.Join(match, " "),
+		), nil
+	case dialect.PG:
+		match := make([]string, len(terms))
+		for i, term := range terms {
+			match[i] = term + ":*"
+		}
+		return query.Where(
+			"to_tsvector('simple', auction_item.display_name || ' ' || auction_item.description) @@ "+
+				"to_tsquery('simple', ?)",
+			strings.Join(match, " & "),
+		), nil
+	default:
+		return nil, errors.Errorf("unsupported dialect '%s'", name)
+	}
+}
+
+// categoryID finds the ID of the category by its name. The ID is 0 when the name is empty. This will return
+// storage.ErrUnknownCategory if the category does not exist.
+func (ac *auctionItemClient) categoryID(ctx context.Context, name string) (uint64, error) {
+	if name == "" {
+		return 0, nil
+	}
+
+	var category Category
+	err := ac.baseClient.get(ctx, &category, "name_id", getCategoryNameID(name))
+	if errors.Is(err, storage.ErrEntityNotFound) {
+		return 0, errors.Wrapf(storage.ErrUnknownCategory, "unable to find category '%s'", name)
+	} else if err != nil {
+		return 0, errors.Wrapf(err, "unable to get category '%s'", name)
+	}
+	return category.ID, nil
+}
+
+// setTags replaces the tags of the item with the ID. Tags that are not given to any item yet are created.
+func (ac *auctionItemClient) setTags(ctx context.Context, itemID uint64, tags []string) error {
+	_, err := ac.db.NewDelete().
+		Model((*ItemTag)(nil)).
+		Where("item_id = ?", itemID).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to remove previous tags")
+	}
+
+	for _, tag := range uniqueTags(tags) {
+		nameID := getTagNameID(tag)
+		_, err = ac.db.NewInsert().
+			Model(&Tag{NameID: nameID, DisplayName: tag}).
+			On("CONFLICT (name_id) DO NOTHING").
+			Returning("").
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to create tag '%s'", tag)
+		}
+
+		var existing Tag
+		if err = ac.baseClient.get(ctx, &existing, "name_id", nameID); err != nil {
+			return errors.Wrapf(err, "unable to get tag '%s'", tag)
+		}
+		_, err = ac.db.NewInsert().
+			Model(&ItemTag{ItemID: itemID, TagID: existing.ID}).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to add tag '%s'", tag)
+		}
+	}
+	return nil
+}
+
+// loadItemDetails fills in the category and tags of the items. The tags of each item are ordered by name.
+func loadItemDetails(ctx context.Context, db bun.IDB, items ...*AuctionItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	byID := make(map[uint64]*AuctionItem, len(items))
+	itemIDs := make([]uint64, 0, len(items))
+	var categoryIDs []uint64
+	for _, item := range items {
+		item.Category = ""
+		item.Tags = nil
+		byID[item.ID] = item
+		itemIDs = append(itemIDs, item.ID)
+		if item.CategoryID != 0 {
+			categoryIDs = append(categoryIDs, item.CategoryID)
+		}
+	}
+
+	if len(categoryIDs) > 0 {
+		var categories []*Category
+		err := db.NewSelect().
+			Model(&categories).
+			Where("id IN (?)", bun.In(categoryIDs)).
+			Scan(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to get categories")
+		}
+
+		names := make(map[uint64]string, len(categories))
+		for _, category := range categories {
+			names[category.ID] = category.DisplayName
+		}
+		for _, item := range items {
+			item.Category = names[item.CategoryID]
+		}
+	}
+
+	var itemTags []struct {
+		ItemID      uint64
+		DisplayName string
+	}
+	err := db.NewSelect().
+		Model((*ItemTag)(nil)).
+		ColumnExpr("item_tag.item_id, tag.display_name").
+		Join("JOIN tags AS tag ON tag.id = item_tag.tag_id").
+		Where("item_tag.item_id IN (?)", bun.In(itemIDs)).
+		OrderExpr("tag.name_id").
+		Scan(ctx, &itemTags)
+	if err != nil {
+		return errors.Wrap(err, "unable to get tags")
+	}
+	for _, itemTag := range itemTags {
+		item := byID[itemTag.ItemID]
+		item.Tags = append(item.Tags, itemTag.DisplayName)
+	}
+	return nil
+}
+
+// uniqueTags removes the empty tags and the tags that repeat an earlier one, ignoring case.
+func uniqueTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		nameID := getTagNameID(tag)
+		if nameID == "" || seen[nameID] {
+			continue
+		}
+		seen[nameID] = true
+		result = append(result, tag)
+	}
+	return result
+}
@@ -2,33 +2,33 @@ package relational
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 
 	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/storage"
 	"github.com/stretchr/testify/suite"
 	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/dialect/sqlitedialect"
 )
 
 type auctionItemClientTestSuite struct {
 	suite.Suite
 
+	dsn    string
 	ctx    context.Context
 	db     bun.IDB
 	client *auctionItemClient
 }
 
 func (ts *auctionItemClientTestSuite) SetupSuite() {
-	rawDB, err := sql.Open("sqlite", "file::memory:?_pragma=cache%3Dshared&_pragma=foreign_keys%3Dtrue")
+	db, err := Open(ts.dsn)
 	ts.Require().NoError(err)
 
 	ts.ctx = context.Background()
-	ts.db = bun.NewDB(rawDB, sqlitedialect.New())
+	ts.db = db
 	ts.client = &auctionItemClient{baseClient{ts.db}}
 
-	ts.Require().NoError(CreateSchema(ts.ctx, ts.db))
+	_, err = NewMigrator(db).Up(ts.ctx)
+	ts.Require().NoError(err)
 }
 
 func (ts *auctionItemClientTestSuite) SetupTest() {
@@ -37,7 +37,9 @@ func (ts *auctionItemClientTestSuite) SetupTest() {
 }
 
 func TestAuctionItemClient(t *testing.T) {
-	suite.Run(t, new(auctionItemClientTestSuite))
+	runOnAllDatabases(t, func(t *testing.T, dsn string) {
+		suite.Run(t, &auctionItemClientTestSuite{dsn: dsn})
+	})
 }
 
 func (ts *auctionItemClientTestSuite) TestCreateDoesNotErrorOnValidInput() {
@@ -0,0 +1,248 @@
+package relational
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/log"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+const backupFilePrefix = "biddr-"
+
+// ErrBackupUnsupported is returned when backing up or restoring a database that is not SQLite. Use the tools provided
+// by the database instead, e.g. pg_dump.
+var ErrBackupUnsupported = errors.New("backups are only supported for SQLite databases")
+
+// Backup writes a consistent snapshot of the database to the path. This is safe to run while the server is using the
+// database. The path must not already exist.
+func Backup(ctx context.Context, db bun.IDB, path string) error {
+	if dialectName(db) != dialect.SQLite {
+		return ErrBackupUnsupported
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return errors.Errorf("'%s' already exists", path)
+	}
+
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return errors.Wrapf(err, "unable to write backup to '%s'", path)
+	}
+	return nil
+}
+
+// Restore replaces the SQLite database file with the backup after verifying the backup is intact. The server must not
+// be running while the database is restored.
+func Restore(ctx context.Context, backupPath string, databasePath string) error {
+	if err := VerifyBackup(ctx, backupPath); err != nil {
+		return errors.Wrapf(err, "'%s' cannot be restored", backupPath)
+	}
+
+	// The backup is copied next to the database first so the database is replaced in a single rename.
+	tempPath := databasePath + ".restore"
+	if err := copyFile(backupPath, tempPath); err != nil {
+		os.Remove(tempPath)
+		return errors.Wrap(err, "unable to copy backup")
+	}
+
+	// Journals left behind by the old database would be applied to the restored one.
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if err := os.Remove(databasePath + suffix); err != nil && !os.IsNotExist(err) {
+			os.Remove(tempPath)
+			return errors.Wrapf(err, "unable to remove '%s%s'", databasePath, suffix)
+		}
+	}
+
+	if err := os.Rename(tempPath, databasePath); err != nil {
+		os.Remove(tempPath)
+		return errors.Wrapf(err, "unable to replace '%s'", databasePath)
+	}
+	return nil
+}
+
+// VerifyBackup checks that the file is an intact SQLite database created by this program whose migrations are all
+// known to this version.
+func VerifyBackup(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return errors.Wrap(err, "unable to read backup")
+	}
+
+	rawDB, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return errors.Wrap(err, "unable to open backup")
+	}
+	defer rawDB.Close()
+
+	var integrity string
+	if err = rawDB.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&integrity); err != nil {
+		return errors.Wrap(err, "unable to check integrity")
+	}
+	if integrity != "ok" {
+		return errors.Errorf("integrity check failed: %s", integrity)
+	}
+
+	rows, err := rawDB.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return errors.Wrap(err, "unable to check foreign keys")
+	}
+	hasViolations := rows.Next()
+	rows.Close()
+	if hasViolations {
+		return errors.New("foreign key check failed")
+	}
+
+	known := make(map[string]bool)
+	for _, migration := range migrations.Sorted() {
+		known[migration.Name] = true
+	}
+
+	rows, err = rawDB.QueryContext(ctx, "SELECT name FROM bun_migrations")
+	if err != nil {
+		return errors.Wrap(err, "unable to read migrations, this is not a biddr database")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return errors.Wrap(err, "unable to read migration")
+		}
+		if !known[name] {
+			return errors.Errorf("backup has unknown migration '%s' from a newer version", name)
+		}
+	}
+	return errors.Wrap(rows.Err(), "unable to read migrations")
+}
+
+// SQLiteFilePath returns the path of the file a SQLite DSN refers to.
+func SQLiteFilePath(dsn string) (string, error) {
+	if isPostgresDSN(dsn) {
+		return "", ErrBackupUnsupported
+	}
+
+	path := strings.TrimPrefix(dsn, "file:")
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+
+	path, err := url.PathUnescape(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read path from '%s'", dsn)
+	}
+	if path == "" || path == ":memory:" {
+		return "", errors.Errorf("'%s' is not stored in a file", dsn)
+	}
+	return path, nil
+}
+
+func copyFile(source string, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return errors.Wrapf(err, "unable to open '%s'", source)
+	}
+	defer in.Close()
+
+	out, err := os.Create(destination)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create '%s'", destination)
+	}
+
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return errors.Wrapf(err, "unable to copy '%s' to '%s'", source, destination)
+	}
+	if err = out.Sync(); err != nil {
+		out.Close()
+		return errors.Wrapf(err, "unable to flush '%s'", destination)
+	}
+	return errors.Wrapf(out.Close(), "unable to close '%s'", destination)
+}
+
+// BackupScheduler periodically backs up the database into a directory and removes the oldest backups so only a fixed
+// number are kept.
+type BackupScheduler struct {
+	db        bun.IDB
+	directory string
+	interval  time.Duration
+	retention int
+}
+
+// NewBackupScheduler creates a BackupScheduler that writes a backup to the directory every interval and keeps the
+// newest retention backups.
+func NewBackupScheduler(db bun.IDB, directory string, interval time.Duration, retention int) *BackupScheduler {
+	return &BackupScheduler{
+		db:        db,
+		directory: directory,
+		interval:  interval,
+		retention: retention,
+	}
+}
+
+// Run backs up the database every interval until the context is done. Failures are logged and retried at the next
+// interval so a full disk does not stop the server.
+func (bs *BackupScheduler) Run(ctx context.Context) error {
+	if dialectName(bs.db) != dialect.SQLite {
+		return ErrBackupUnsupported
+	}
+	if err := os.MkdirAll(bs.directory, 0755); err != nil {
+		return errors.Wrapf(err, "unable to create backup directory '%s'", bs.directory)
+	}
+
+	ticker := time.NewTicker(bs.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			path, err := bs.backup(ctx, now)
+			if err != nil {
+				log.Error(ctx, "unable to back up database", "err", err)
+				continue
+			}
+			log.Info(ctx, "Backed up database", "path", path)
+		}
+	}
+}
+
+func (bs *BackupScheduler) backup(ctx context.Context, now time.Time) (string, error) {
+	path := filepath.Join(bs.directory, fmt.Sprintf("%s%s.db", backupFilePrefix, now.UTC().Format("20060102T150405.000Z")))
+	if err := Backup(ctx, bs.db, path); err != nil {
+		return "", errors.Wrap(err, "unable to create backup")
+	}
+
+	if err := bs.prune(); err != nil {
+		return "", errors.Wrap(err, "unable to remove old backups")
+	}
+	return path, nil
+}
+
+// prune removes every backup except the newest. Backup names sort by the time they were taken.
+func (bs *BackupScheduler) prune() error {
+	backups, err := filepath.Glob(filepath.Join(bs.directory, backupFilePrefix+"*.db"))
+	if err != nil {
+		return errors.Wrap(err, "unable to list backups")
+	}
+	if len(backups) <= bs.retention {
+		return nil
+	}
+
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-bs.retention] {
+		if err := os.Remove(backup); err != nil {
+			return errors.Wrapf(err, "unable to remove '%s'", backup)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package relational
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+)
+
+type backupTestSuite struct {
+	suite.Suite
+
+	ctx          context.Context
+	directory    string
+	databasePath string
+	db           *bun.DB
+	userClient   *userClient
+}
+
+func (ts *backupTestSuite) SetupTest() {
+	ts.ctx = context.Background()
+	ts.directory = ts.T().TempDir()
+	ts.databasePath = filepath.Join(ts.directory, "biddr.db")
+	ts.db = ts.openDatabase()
+	ts.userClient = &userClient{baseClient{ts.db}}
+}
+
+func (ts *backupTestSuite) TearDownTest() {
+	ts.db.Close()
+}
+
+func TestBackup(t *testing.T) {
+	suite.Run(t, new(backupTestSuite))
+}
+
+func (ts *backupTestSuite) TestBackupAndRestoreRecoversData() {
+	ts.createUser("before")
+	backupPath := filepath.Join(ts.directory, "backup.db")
+	ts.Require().NoError(Backup(ts.ctx, ts.db, backupPath))
+	ts.createUser("after")
+	ts.Require().NoError(ts.db.Close())
+
+	ts.Require().NoError(Restore(ts.ctx, backupPath, ts.databasePath))
+
+	ts.db = ts.openDatabase()
+	ts.userClient = &userClient{baseClient{ts.db}}
+	_, err := ts.userClient.Get(ts.ctx, "before")
+	ts.Require().NoError(err)
+	_, err = ts.userClient.Get(ts.ctx, "after")
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *backupTestSuite) TestBackupErrorsWhenPathExists() {
+	backupPath := filepath.Join(ts.directory, "backup.db")
+	ts.Require().NoError(os.WriteFile(backupPath, []byte("existing"), 0644))
+
+	ts.Require().Error(Backup(ts.ctx, ts.db, backupPath))
+	contents, err := os.ReadFile(backupPath)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("existing", string(contents))
+}
+
+func (ts *backupTestSuite) TestRestoreRejectsCorruptBackup() {
+	ts.createUser("existing")
+	backupPath := filepath.Join(ts.directory, "backup.db")
+	ts.Require().NoError(os.WriteFile(backupPath, []byte("not a database"), 0644))
+
+	ts.Require().Error(Restore(ts.ctx, backupPath, ts.databasePath))
+	_, err := ts.userClient.Get(ts.ctx, "existing")
+	ts.Require().NoError(err)
+}
+
+func (ts *backupTestSuite) TestVerifyBackupRejectsDatabaseWithoutMigrations() {
+	backupPath := filepath.Join(ts.directory, "other.db")
+	other, err := Open(fmt.Sprintf("file:%s", backupPath))
+	ts.Require().NoError(err)
+	defer other.Close()
+	_, err = other.ExecContext(ts.ctx, "CREATE TABLE other (id INTEGER)")
+	ts.Require().NoError(err)
+
+	ts.Require().Error(VerifyBackup(ts.ctx, backupPath))
+}
+
+func (ts *backupTestSuite) TestVerifyBackupRejectsUnknownMigrations() {
+	_, err := ts.db.ExecContext(ts.ctx, "INSERT INTO bun_migrations (name, group_id) VALUES ('9999_from_the_future', 99)")
+	ts.Require().NoError(err)
+	backupPath := filepath.Join(ts.directory, "backup.db")
+	ts.Require().NoError(Backup(ts.ctx, ts.db, backupPath))
+
+	ts.Require().Error(VerifyBackup(ts.ctx, backupPath))
+}
+
+func (ts *backupTestSuite) TestVerifyBackupErrorsWhenMissing() {
+	ts.Require().Error(VerifyBackup(ts.ctx, filepath.Join(ts.directory, "missing.db")))
+}
+
+func (ts *backupTestSuite) TestSchedulerKeepsNewestBackups() {
+	backupDirectory := filepath.Join(ts.directory, "backups")
+	scheduler := NewBackupScheduler(ts.db, backupDirectory, time.Millisecond, 2)
+	ts.Require().NoError(os.MkdirAll(backupDirectory, 0755))
+
+	start := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	var paths []string
+	for i := 0; i < 3; i++ {
+		path, err := scheduler.backup(ts.ctx, start.Add(time.Duration(i)*time.Minute))
+		ts.Require().NoError(err)
+		paths = append(paths, path)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(backupDirectory, "*"))
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(paths[1:], backups)
+}
+
+func (ts *backupTestSuite) TestSchedulerRunsUntilContextDone() {
+	backupDirectory := filepath.Join(ts.directory, "backups")
+	scheduler := NewBackupScheduler(ts.db, backupDirectory, 10*time.Millisecond, 5)
+	ctx, cancel := context.WithCancel(ts.ctx)
+	done := make(chan error)
+	go func() {
+		done <- scheduler.Run(ctx)
+	}()
+
+	ts.Require().Eventually(func() bool {
+		backups, err := filepath.Glob(filepath.Join(backupDirectory, "*.db"))
+		return err == nil && len(backups) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	ts.Require().NoError(<-done)
+}
+
+func (ts *backupTestSuite) TestSQLiteFilePath() {
+	path, err := SQLiteFilePath("file:biddr.db?_pragma=foreign_keys%3Dtrue")
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("biddr.db", path)
+
+	path, err = SQLiteFilePath("/var/lib/biddr/biddr.db")
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("/var/lib/biddr/biddr.db", path)
+
+	_, err = SQLiteFilePath("file::memory:?cache=shared")
+	ts.Require().Error(err)
+
+	_, err = SQLiteFilePath("postgres://localhost/biddr")
+	ts.Require().ErrorIs(err, ErrBackupUnsupported)
+}
+
+func (ts *backupTestSuite) openDatabase() *bun.DB {
+	db, err := Open(fmt.Sprintf("file:%s?_pragma=foreign_keys%%3Dtrue", ts.databasePath))
+	ts.Require().NoError(err)
+	_, err = NewMigrator(db).Up(ts.ctx)
+	ts.Require().NoError(err)
+	return db
+}
+
+func (ts *backupTestSuite) createUser(username string) {
+	ts.Require().NoError(ts.userClient.Create(ts.ctx, &model.User{
+		Username:       username,
+		DisplayName:    username,
+		HashedPassword: "1234",
+		Permission:     model.PermissionLevelBidder,
+	}))
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"reflect"
+	"time"
 
 	"github.com/MMarsolek/AuctionHouse/storage"
 	"github.com/pkg/errors"
 	"github.com/uptrace/bun"
-	"modernc.org/sqlite"
 )
 
 type baseClient struct {
@@ -44,6 +44,22 @@ func (bc *baseClient) getAll(ctx context.Context, model interface{}) error {
 	return nil
 }
 
+// getAllDeleted retrieves every soft deleted entity, oldest deletion first.
+func (bc *baseClient) getAllDeleted(ctx context.Context, model interface{}) error {
+	err := bc.validatePointer(model)
+	if err != nil {
+		return errors.Wrap(err, "unable to validate pointer")
+	}
+
+	err = bc.db.NewSelect().Model(model).WhereDeleted().Order("deleted_at").Scan(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to retrieve all deleted entities")
+	}
+
+	return nil
+}
+
+// delete removes the entity. Models with a soft_delete column are only marked as deleted so they can be restored.
 func (bc *baseClient) delete(ctx context.Context, model interface{}, primaryCol string, primaryKey interface{}) error {
 	err := bc.validatePointer(model)
 	if err != nil {
@@ -64,12 +80,46 @@ func (bc *baseClient) delete(ctx context.Context, model interface{}, primaryCol
 	return nil
 }
 
+// restore clears the deleted_at column of the soft deleted entity.
+func (bc *baseClient) restore(ctx context.Context, model interface{}, primaryCol string, primaryKey interface{}) error {
+	err := bc.validatePointer(model)
+	if err != nil {
+		return errors.Wrap(err, "unable to validate pointer")
+	}
+	results, err := bc.db.NewUpdate().
+		Model(model).
+		Set("deleted_at = NULL").
+		Set("updated_at = ?", time.Now().UTC()).
+		Set("version = version + 1").
+		WhereDeleted().
+		Where("? = ?", bun.Ident(primaryCol), primaryKey).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to restore '%s':'%s'", primaryCol, primaryKey)
+	}
+	affected, err := results.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to determine rows affected")
+	}
+	if affected <= 0 {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find deleted '%s':'%s'", primaryCol, primaryKey)
+	}
+
+	return nil
+}
+
+// update writes the columns of the model to the entity, even when they are empty. The version is always incremented.
 func (bc *baseClient) update(ctx context.Context, model interface{}, primaryCol string, primaryKey interface{}, columns ...string) error {
 	err := bc.validatePointer(model)
 	if err != nil {
 		return errors.Wrap(err, "unable to validate pointer")
 	}
-	_, err = bc.db.NewUpdate().Model(model).OmitZero().Where("? = ?", bun.Ident(primaryCol), primaryKey).Exec(ctx)
+	_, err = bc.db.NewUpdate().
+		Model(model).
+		Column(append(columns, "updated_at", "version")...).
+		Value("version", "version + 1").
+		Where("? = ?", bun.Ident(primaryCol), primaryKey).
+		Exec(ctx)
 	if err != nil {
 		return errors.Wrap(err, "unable to update entity")
 	}
@@ -84,8 +134,7 @@ func (bc *baseClient) create(ctx context.Context, model interface{}) error {
 
 	_, err = bc.db.NewInsert().Model(model).Exec(ctx)
 	if err != nil {
-		var sqliteErr *sqlite.Error
-		if errors.As(err, &sqliteErr) && sqliteErr.Code() == 2067 {
+		if isUniqueViolation(err) {
 			return errors.Wrap(storage.ErrEntityAlreadyExists, "entity already exists")
 		}
 
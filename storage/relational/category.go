@@ -0,0 +1,82 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type categoryClient struct {
+	baseClient
+}
+
+// NewCategoryClient returns an object that can perform various operations on model.Categories.
+func NewCategoryClient(db bun.IDB) storage.CategoryClient {
+	return &categoryClient{
+		baseClient: baseClient{
+			db: db,
+		},
+	}
+}
+
+// GetAll retrieves every model.Category in storage ordered by name.
+func (cc *categoryClient) GetAll(ctx context.Context) ([]*model.Category, error) {
+	var dbModels []*Category
+	err := cc.db.NewSelect().
+		Model(&dbModels).
+		Order("name_id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get all categories")
+	}
+
+	result := make([]*model.Category, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel()
+	}
+	return result, nil
+}
+
+// Create adds a new model.Category to storage. This will return storage.ErrEntityAlreadyExists if the name is already
+// found in storage.
+func (cc *categoryClient) Create(ctx context.Context, category *model.Category) error {
+	dbModel := CategoryToDBModel(category)
+	err := cc.baseClient.create(ctx, dbModel)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create category %s", dbModel.NameID)
+	}
+
+	*category = *dbModel.ToModel()
+	return nil
+}
+
+// Delete removes the model.Category by name and takes every item out of it, including the deleted items. This will
+// return storage.ErrEntityNotFound if the name is not found in storage.
+func (cc *categoryClient) Delete(ctx context.Context, name string) error {
+	nameID := getCategoryNameID(name)
+	var dbModel Category
+	if err := cc.baseClient.get(ctx, &dbModel, "name_id", nameID); err != nil {
+		return errors.Wrapf(err, "unable to delete category with name '%s'", nameID)
+	}
+
+	_, err := cc.db.NewUpdate().
+		Model((*AuctionItem)(nil)).
+		Set("category_id = NULL").
+		Set("updated_at = ?", time.Now().UTC()).
+		Set("version = version + 1").
+		WhereAllWithDeleted().
+		Where("category_id = ?", dbModel.ID).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to remove auction items from category '%s'", nameID)
+	}
+
+	if err = cc.baseClient.delete(ctx, &Category{}, "id", dbModel.ID); err != nil {
+		return errors.Wrapf(err, "unable to delete category with name '%s'", nameID)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package relational
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MMarsolek/AuctionHouse/storage/storagetest"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestConformance(t *testing.T) {
+	runOnAllDatabases(t, func(t *testing.T, dsn string) {
+		ctx := context.Background()
+		db, err := Open(dsn)
+		require.NoError(t, err)
+		_, err = NewMigrator(db).Up(ctx)
+		require.NoError(t, err)
+
+		suite.Run(t, &storagetest.ConformanceSuite{
+			NewClients: func(t *testing.T) *storagetest.Clients {
+				models := []interface{}{
+					&AuctionBid{}, &Pledge{}, &AppealLevel{}, &TicketPurchase{}, &Raffle{}, &ItemImage{}, &ItemTag{}, &Tag{},
+					&SpendingLimit{}, &ItemClosing{}, &OutbidNotice{}, &User{}, &AuctionItem{}, &Category{}, &Lot{},
+				}
+				for _, model := range models {
+					_, err := db.NewTruncateTable().Model(model).Exec(ctx)
+					require.NoError(t, err)
+				}
+
+				return &storagetest.Clients{
+					Users:      NewUserClient(db),
+					Items:      NewAuctionItemClient(db),
+					Bids:       NewAuctionBidClient(db),
+					Images:     NewItemImageClient(db),
+					Categories: NewCategoryClient(db),
+					Lots:       NewLotClient(db),
+					Donations:  NewDonationClient(db),
+					Raffles:    NewRaffleClient(db),
+					Limits:     NewSpendingLimitClient(db),
+					Outbids:    NewOutbidNoticeClient(db),
+					Transactor: NewTransactor(db),
+				}
+			},
+		})
+	})
+}
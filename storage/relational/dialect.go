@@ -0,0 +1,69 @@
+package relational
+
+import (
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"modernc.org/sqlite"
+)
+
+const (
+	sqliteConstraintUnique  = 2067
+	sqliteConstraintTrigger = 1811
+	sqliteConstraintNotNull = 1299
+
+	postgresUniqueViolation  = "23505"
+	postgresNotNullViolation = "23502"
+
+	// postgresBidTooLow is the SQLSTATE raised by the highest_value_check trigger. Class BH is not used by PostgreSQL.
+	postgresBidTooLow = "BH001"
+)
+
+// dialectName returns the name of the dialect used by the database. bun.IDB does not expose the dialect directly so it
+// is taken from a query, which works for both bun.DB and bun.Tx.
+func dialectName(db bun.IDB) dialect.Name {
+	return db.NewSelect().DB().Dialect().Name()
+}
+
+// isUniqueViolation reports whether the error was caused by inserting a duplicate value into a unique column.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqliteConstraintUnique
+	}
+
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Field('C') == postgresUniqueViolation
+	}
+	return false
+}
+
+// isBidTooLow reports whether the error was raised by the highest_value_check trigger.
+func isBidTooLow(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqliteConstraintTrigger
+	}
+
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Field('C') == postgresBidTooLow
+	}
+	return false
+}
+
+// isNotNullViolation reports whether the error was caused by inserting NULL into a column that does not allow it.
+func isNotNullViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqliteConstraintNotNull
+	}
+
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Field('C') == postgresNotNullViolation
+	}
+	return false
+}
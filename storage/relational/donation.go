@@ -0,0 +1,166 @@
+package relational
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type donationClient struct {
+	baseClient
+}
+
+// NewDonationClient returns an object that can perform various operations on model.AppealLevels and model.Pledges.
+func NewDonationClient(db bun.IDB) storage.DonationClient {
+	return &donationClient{
+		baseClient: baseClient{
+			db: db,
+		},
+	}
+}
+
+// GetLevels retrieves every model.AppealLevel in storage from the highest amount down. Levels with the same amount are
+// ordered by ID.
+func (dc *donationClient) GetLevels(ctx context.Context) ([]*model.AppealLevel, error) {
+	var dbModels []*AppealLevel
+	err := dc.db.NewSelect().
+		Model(&dbModels).
+		OrderExpr("amount DESC, id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get all appeal levels")
+	}
+
+	result := make([]*model.AppealLevel, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel()
+	}
+	return result, nil
+}
+
+// CreateLevel adds a new model.AppealLevel to storage. This will return storage.ErrEntityAlreadyExists if the name is
+// already found in storage, ignoring case.
+func (dc *donationClient) CreateLevel(ctx context.Context, level *model.AppealLevel) error {
+	dbModel := AppealLevelToDBModel(level)
+	if err := dc.baseClient.create(ctx, dbModel); err != nil {
+		return errors.Wrapf(err, "unable to create appeal level '%s'", level.Name)
+	}
+
+	*level = *dbModel.ToModel()
+	return nil
+}
+
+// DeleteLevel removes the model.AppealLevel with the ID. This will return storage.ErrEntityNotFound if the ID is not
+// found in storage and storage.ErrLevelHasPledges if a pledge was made at the level.
+func (dc *donationClient) DeleteLevel(ctx context.Context, id uint64) error {
+	hasPledges, err := dc.db.NewSelect().
+		Model((*Pledge)(nil)).
+		Where("level_id = ?", id).
+		Exists(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to find pledges of appeal level %d", id)
+	}
+	if hasPledges {
+		return errors.Wrapf(storage.ErrLevelHasPledges, "unable to delete appeal level %d", id)
+	}
+
+	if err = dc.baseClient.delete(ctx, &AppealLevel{}, "id", id); err != nil {
+		return errors.Wrapf(err, "unable to delete appeal level %d", id)
+	}
+	return nil
+}
+
+// GetPledges retrieves every model.Pledge in storage in the order they were recorded. Pledges by deleted users are
+// included.
+func (dc *donationClient) GetPledges(ctx context.Context) ([]*model.Pledge, error) {
+	var dbModels []*Pledge
+	err := dc.db.NewSelect().
+		Model(&dbModels).
+		Relation("Bidder", withDeletedBidder).
+		Relation("Level").
+		Order("pledge.id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get all pledges")
+	}
+
+	result := make([]*model.Pledge, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel()
+	}
+	return result, nil
+}
+
+// CreatePledge adds a new model.Pledge by the bidder at the level. The amount of the pledge is the amount of the level.
+// This will return storage.ErrEntityNotFound if the bidder or the level does not exist.
+func (dc *donationClient) CreatePledge(ctx context.Context, pledge *model.Pledge) error {
+	dbModel := &Pledge{
+		RecordedBy: pledge.RecordedBy,
+		LevelID:    pledge.Level.ID,
+	}
+	_, err := dc.db.NewInsert().
+		Model(dbModel).
+		Value("bidder_id", "(?)", dc.db.NewSelect().
+			Model((*User)(nil)).
+			Column("id").
+			Where("username = ?", pledge.Bidder.Username)).
+		Value("amount", "(?)", dc.db.NewSelect().
+			Model((*AppealLevel)(nil)).
+			Column("amount").
+			Where("id = ?", pledge.Level.ID)).
+		Value("currency", "(?)", dc.db.NewSelect().
+			Model((*AppealLevel)(nil)).
+			Column("currency").
+			Where("id = ?", pledge.Level.ID)).
+		Exec(ctx)
+	if err != nil {
+		// The bidder ID or amount is NULL when the subquery does not find a match.
+		if isNotNullViolation(err) {
+			return errors.Wrap(storage.ErrEntityNotFound, "unable to find bidder or appeal level")
+		}
+		return errors.Wrap(err, "unable to create pledge")
+	}
+
+	created, err := dc.get(ctx, dbModel.ID)
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve created pledge")
+	}
+	*pledge = *created.ToModel()
+	return nil
+}
+
+// DeletePledge removes the model.Pledge with the ID and returns it. This will return storage.ErrEntityNotFound if the
+// ID is not found in storage.
+func (dc *donationClient) DeletePledge(ctx context.Context, id uint64) (*model.Pledge, error) {
+	dbModel, err := dc.get(ctx, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to delete pledge %d", id)
+	}
+
+	if err = dc.baseClient.delete(ctx, &Pledge{}, "id", id); err != nil {
+		return nil, errors.Wrapf(err, "unable to delete pledge %d", id)
+	}
+	return dbModel.ToModel(), nil
+}
+
+// get retrieves the pledge with the ID along with its bidder and level.
+func (dc *donationClient) get(ctx context.Context, id uint64) (*Pledge, error) {
+	var dbModel Pledge
+	err := dc.db.NewSelect().
+		Model(&dbModel).
+		Relation("Bidder", withDeletedBidder).
+		Relation("Level").
+		Where("pledge.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to find pledge %d", id)
+		}
+		return nil, errors.Wrapf(err, "unable to retrieve pledge %d", id)
+	}
+	return &dbModel, nil
+}
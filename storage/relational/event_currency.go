@@ -0,0 +1,58 @@
+package relational
+
+import (
+	"context"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// ErrEventCurrencyChanged is returned when the server is started with a different currency than the one the bids and
+// spending limits in the database are in.
+var ErrEventCurrencyChanged = errors.New("currency of the event has changed")
+
+// settingCurrency is the name of the EventSetting that holds the currency of the event.
+const settingCurrency = "currency"
+
+type eventCurrencyKey struct{}
+
+// WithEventCurrency returns a copy of the context that tells migrations the currency of the event. Bids stored before
+// they had a currency are converted as amounts in it.
+func WithEventCurrency(ctx context.Context, currency string) context.Context {
+	return context.WithValue(ctx, eventCurrencyKey{}, currency)
+}
+
+// eventCurrency returns the currency given to WithEventCurrency, or model.DefaultCurrency when none was given.
+func eventCurrency(ctx context.Context) string {
+	if currency, ok := ctx.Value(eventCurrencyKey{}).(string); ok {
+		return currency
+	}
+	return model.DefaultCurrency
+}
+
+// EnsureEventCurrency stores the currency the first time the event is started. Afterwards it returns
+// ErrEventCurrencyChanged if the currency is not the one that was stored, since the amounts of every bid and spending
+// limit are compared and added up without converting them.
+func EnsureEventCurrency(ctx context.Context, db bun.IDB, currency string) error {
+	_, err := db.NewInsert().
+		Model(&EventSetting{Name: settingCurrency, Value: currency}).
+		On("CONFLICT (name) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to store currency of the event")
+	}
+
+	var stored EventSetting
+	err = db.NewSelect().
+		Model(&stored).
+		Where("name = ?", settingCurrency).
+		Scan(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to get currency of the event")
+	}
+	if stored.Value != currency {
+		return errors.Wrapf(ErrEventCurrencyChanged, "the database is in %s, not %s", stored.Value, currency)
+	}
+	return nil
+}
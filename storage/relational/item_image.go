@@ -0,0 +1,153 @@
+package relational
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type itemImageClient struct {
+	baseClient
+}
+
+// NewItemImageClient returns an object that can perform various operations on model.ItemImages.
+func NewItemImageClient(db bun.IDB) storage.ItemImageClient {
+	return &itemImageClient{
+		baseClient: baseClient{
+			db: db,
+		},
+	}
+}
+
+// GetAll retrieves every model.ItemImage of the item ordered by position. Items without images have an empty result.
+func (ic *itemImageClient) GetAll(ctx context.Context, itemID uint64) ([]*model.ItemImage, error) {
+	var dbModels []*ItemImage
+	err := ic.db.NewSelect().
+		Model(&dbModels).
+		Where("item_id = ?", itemID).
+		Order("position").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get images of auction item %d", itemID)
+	}
+
+	result := make([]*model.ItemImage, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel()
+	}
+	return result, nil
+}
+
+// Add stores the model.ItemImage after the other images of its item. This will return storage.ErrEntityNotFound if the
+// item does not exist or was deleted.
+func (ic *itemImageClient) Add(ctx context.Context, image *model.ItemImage) error {
+	dbModel := ItemImageToDBModel(image)
+	_, err := ic.db.NewInsert().
+		Model(dbModel).
+		Value("item_id", "(?)", ic.db.NewSelect().
+			Model((*AuctionItem)(nil)).
+			Column("id").
+			Where("id = ?", image.ItemID)).
+		Value("position", "(?)", ic.db.NewSelect().
+			Model((*ItemImage)(nil)).
+			ColumnExpr("COALESCE(MAX(position) + 1, 0)").
+			Where("item_id = ?", image.ItemID)).
+		Returning("id, position").
+		Exec(ctx)
+	if err != nil {
+		// The item ID is NULL when the subquery does not find the item.
+		if isNotNullViolation(err) {
+			return errors.Wrapf(storage.ErrEntityNotFound, "unable to find auction item %d", image.ItemID)
+		}
+		return errors.Wrapf(err, "unable to add image to auction item %d", image.ItemID)
+	}
+
+	image.ID = dbModel.ID
+	image.Position = dbModel.Position
+	return nil
+}
+
+// Delete removes the model.ItemImage from the item and closes the gap it leaves in the positions. This will return
+// storage.ErrEntityNotFound if the item does not have the image.
+func (ic *itemImageClient) Delete(ctx context.Context, itemID uint64, imageID uint64) error {
+	var dbModel ItemImage
+	err := ic.db.NewSelect().
+		Model(&dbModel).
+		Where("id = ?", imageID).
+		Where("item_id = ?", itemID).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrapf(storage.ErrEntityNotFound, "unable to find image %d of auction item %d", imageID, itemID)
+		}
+		return errors.Wrapf(err, "unable to get image %d of auction item %d", imageID, itemID)
+	}
+
+	_, err = ic.db.NewDelete().
+		Model((*ItemImage)(nil)).
+		Where("id = ?", imageID).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to delete image %d of auction item %d", imageID, itemID)
+	}
+
+	_, err = ic.db.NewUpdate().
+		Model((*ItemImage)(nil)).
+		Set("position = position - 1").
+		Set("version = version + 1").
+		Where("item_id = ?", itemID).
+		Where("position > ?", dbModel.Position).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to move images of auction item %d", itemID)
+	}
+	return nil
+}
+
+// Reorder moves every model.ItemImage of the item to the position of its ID in imageIDs. This will return
+// storage.ErrInvalidOrder unless imageIDs has every image of the item exactly once.
+func (ic *itemImageClient) Reorder(ctx context.Context, itemID uint64, imageIDs []uint64) error {
+	images, err := ic.GetAll(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	if err = validateOrder(images, imageIDs); err != nil {
+		return errors.Wrapf(err, "unable to reorder images of auction item %d", itemID)
+	}
+
+	for position, imageID := range imageIDs {
+		_, err = ic.db.NewUpdate().
+			Model((*ItemImage)(nil)).
+			Set("position = ?", position).
+			Set("version = version + 1").
+			Where("id = ?", imageID).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to move image %d of auction item %d", imageID, itemID)
+		}
+	}
+	return nil
+}
+
+// validateOrder returns storage.ErrInvalidOrder unless imageIDs has the ID of every image exactly once.
+func validateOrder(images []*model.ItemImage, imageIDs []uint64) error {
+	if len(images) != len(imageIDs) {
+		return errors.Wrapf(storage.ErrInvalidOrder, "expected %d images but got %d", len(images), len(imageIDs))
+	}
+
+	remaining := make(map[uint64]bool, len(images))
+	for _, image := range images {
+		remaining[image.ID] = true
+	}
+	for _, imageID := range imageIDs {
+		if !remaining[imageID] {
+			return errors.Wrapf(storage.ErrInvalidOrder, "image %d is not an image of the item or is repeated", imageID)
+		}
+		delete(remaining, imageID)
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type lotClient struct {
+	baseClient
+}
+
+// NewLotClient returns an object that can perform various operations on model.Lots.
+func NewLotClient(db bun.IDB) storage.LotClient {
+	return &lotClient{
+		baseClient: baseClient{
+			db: db,
+		},
+	}
+}
+
+// GetAll retrieves every model.Lot in storage along with its items in the order they were created.
+func (lc *lotClient) GetAll(ctx context.Context) ([]*model.Lot, error) {
+	var dbModels []*Lot
+	err := lc.db.NewSelect().
+		Model(&dbModels).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get all lots")
+	}
+
+	lotIDs := make([]uint64, len(dbModels))
+	for i, dbModel := range dbModels {
+		lotIDs[i] = dbModel.ID
+	}
+	items, err := lc.items(ctx, lotIDs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get all lots")
+	}
+
+	byLot := make(map[uint64][]*AuctionItem, len(dbModels))
+	for _, item := range items {
+		byLot[item.LotID] = append(byLot[item.LotID], item)
+	}
+	result := make([]*model.Lot, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel(byLot[dbModel.ID])
+	}
+	return result, nil
+}
+
+// GetByID retrieves the model.Lot by the ID along with its items. This will return storage.ErrEntityNotFound if the ID
+// is not found in storage.
+func (lc *lotClient) GetByID(ctx context.Context, id uint64) (*model.Lot, error) {
+	var dbModel Lot
+	if err := lc.baseClient.get(ctx, &dbModel, "id", id); err != nil {
+		return nil, errors.Wrapf(err, "unable to get lot %d", id)
+	}
+
+	items, err := lc.items(ctx, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get lot %d", id)
+	}
+	return dbModel.ToModel(items), nil
+}
+
+// Create adds a new model.Lot to storage with the items of the IDs. This will return storage.ErrEntityAlreadyExists if
+// the name is already found in storage, storage.ErrLotTooSmall if fewer than two items are given,
+// storage.ErrEntityNotFound if an item does not exist, storage.ErrItemInLot if an item is already in a lot and
+// storage.ErrLotHasBids if an item has bids.
+func (lc *lotClient) Create(ctx context.Context, lot *model.Lot, itemIDs []uint64) error {
+	dbModel := LotToDBModel(lot)
+	exists, err := lc.db.NewSelect().
+		Model((*Lot)(nil)).
+		Where("name_id = ?", dbModel.NameID).
+		Exists(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create lot %s", dbModel.NameID)
+	}
+	if exists {
+		return errors.Wrapf(storage.ErrEntityAlreadyExists, "unable to create lot %s", dbModel.NameID)
+	}
+
+	itemIDs, err = lc.standaloneItems(ctx, itemIDs)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create lot %s", dbModel.NameID)
+	}
+	if len(itemIDs) < 2 {
+		return errors.Wrapf(storage.ErrLotTooSmall, "unable to create lot %s", dbModel.NameID)
+	}
+	if err = lc.checkNoBids(ctx, itemIDs); err != nil {
+		return errors.Wrapf(err, "unable to create lot %s", dbModel.NameID)
+	}
+
+	if err = lc.baseClient.create(ctx, dbModel); err != nil {
+		return errors.Wrapf(err, "unable to create lot %s", dbModel.NameID)
+	}
+	if err = lc.moveToLot(ctx, itemIDs, dbModel.ID); err != nil {
+		return errors.Wrapf(err, "unable to create lot %s", dbModel.NameID)
+	}
+
+	items, err := lc.items(ctx, dbModel.ID)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create lot %s", dbModel.NameID)
+	}
+	*lot = *dbModel.ToModel(items)
+	return nil
+}
+
+// Merge moves every item of the other lots and the items of the IDs into the lot with the ID and removes the other
+// lots. This will return storage.ErrEntityNotFound if a lot or item does not exist, storage.ErrItemInLot if an item is
+// in a lot that is not being merged and storage.ErrLotHasBids if any of the lots or items have bids.
+func (lc *lotClient) Merge(ctx context.Context, id uint64, lotIDs []uint64, itemIDs []uint64) error {
+	if _, err := lc.GetByID(ctx, id); err != nil {
+		return errors.Wrapf(err, "unable to merge into lot %d", id)
+	}
+
+	var otherIDs []uint64
+	for _, lotID := range uniqueIDs(lotIDs) {
+		if lotID != id {
+			otherIDs = append(otherIDs, lotID)
+		}
+	}
+	if len(otherIDs) > 0 {
+		count, err := lc.db.NewSelect().
+			Model((*Lot)(nil)).
+			Where("id IN (?)", bun.In(otherIDs)).
+			Count(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to merge into lot %d", id)
+		}
+		if count != len(otherIDs) {
+			return errors.Wrapf(storage.ErrEntityNotFound, "unable to find every lot to merge into lot %d", id)
+		}
+	}
+
+	items, err := lc.items(ctx, append([]uint64{id}, otherIDs...)...)
+	if err != nil {
+		return errors.Wrapf(err, "unable to merge into lot %d", id)
+	}
+	inLot := make(map[uint64]bool, len(items))
+	moved := make([]uint64, len(items))
+	for i, item := range items {
+		moved[i] = item.ID
+		if item.LotID == id {
+			inLot[item.ID] = true
+		}
+	}
+
+	var standalone []uint64
+	for _, itemID := range itemIDs {
+		if !inLot[itemID] {
+			standalone = append(standalone, itemID)
+		}
+	}
+	standalone, err = lc.standaloneItems(ctx, standalone)
+	if err != nil {
+		return errors.Wrapf(err, "unable to merge into lot %d", id)
+	}
+	moved = append(moved, standalone...)
+	if err = lc.checkNoBids(ctx, moved); err != nil {
+		return errors.Wrapf(err, "unable to merge into lot %d", id)
+	}
+
+	if len(otherIDs) > 0 {
+		_, err = lc.db.NewDelete().
+			Model((*Lot)(nil)).
+			Where("id IN (?)", bun.In(otherIDs)).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to remove lots merged into lot %d", id)
+		}
+	}
+	if err = lc.moveToLot(ctx, moved, id); err != nil {
+		return errors.Wrapf(err, "unable to merge into lot %d", id)
+	}
+	return nil
+}
+
+// Split takes the items of the IDs out of the lot with the ID. The lot is removed and all of its items are sold on their
+// own again when fewer than two items would be left or no IDs are given. This will return storage.ErrEntityNotFound if
+// the lot does not exist or an item is not in it and storage.ErrLotHasBids if the lot has bids.
+func (lc *lotClient) Split(ctx context.Context, id uint64, itemIDs []uint64) error {
+	lot, err := lc.GetByID(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "unable to split lot %d", id)
+	}
+
+	inLot := make(map[uint64]bool, len(lot.Items))
+	all := make([]uint64, len(lot.Items))
+	for i, item := range lot.Items {
+		inLot[item.ID] = true
+		all[i] = item.ID
+	}
+	if err = lc.checkNoBids(ctx, all); err != nil {
+		return errors.Wrapf(err, "unable to split lot %d", id)
+	}
+
+	itemIDs = uniqueIDs(itemIDs)
+	for _, itemID := range itemIDs {
+		if !inLot[itemID] {
+			return errors.Wrapf(storage.ErrEntityNotFound, "unable to find auction item %d in lot %d", itemID, id)
+		}
+	}
+
+	if len(itemIDs) > 0 && len(all)-len(itemIDs) >= 2 {
+		if err = lc.moveToLot(ctx, itemIDs, 0); err != nil {
+			return errors.Wrapf(err, "unable to split lot %d", id)
+		}
+		return nil
+	}
+
+	if err = lc.moveToLot(ctx, all, 0); err != nil {
+		return errors.Wrapf(err, "unable to split lot %d", id)
+	}
+	if err = lc.baseClient.delete(ctx, &Lot{}, "id", id); err != nil {
+		return errors.Wrapf(err, "unable to remove lot %d", id)
+	}
+	return nil
+}
+
+// items retrieves the items in the lots with the IDs ordered by ID.
+func (lc *lotClient) items(ctx context.Context, lotIDs ...uint64) ([]*AuctionItem, error) {
+	if len(lotIDs) == 0 {
+		return nil, nil
+	}
+
+	var items []*AuctionItem
+	err := lc.db.NewSelect().
+		Model(&items).
+		Where("lot_id IN (?)", bun.In(lotIDs)).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get auction items of lots")
+	}
+	if err = loadItemDetails(ctx, lc.db, items...); err != nil {
+		return nil, errors.Wrap(err, "unable to get auction items of lots")
+	}
+	return items, nil
+}
+
+// standaloneItems returns the IDs without repeats. This will return storage.ErrEntityNotFound if an item does not exist
+// and storage.ErrItemInLot if an item is already in a lot.
+func (lc *lotClient) standaloneItems(ctx context.Context, itemIDs []uint64) ([]uint64, error) {
+	itemIDs = uniqueIDs(itemIDs)
+	if len(itemIDs) == 0 {
+		return nil, nil
+	}
+
+	var items []*AuctionItem
+	err := lc.db.NewSelect().
+		Model(&items).
+		Column("id", "lot_id").
+		Where("id IN (?)", bun.In(itemIDs)).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get auction items")
+	}
+	if len(items) != len(itemIDs) {
+		return nil, errors.Wrap(storage.ErrEntityNotFound, "unable to find every auction item")
+	}
+	for _, item := range items {
+		if item.LotID != 0 {
+			return nil, errors.Wrapf(storage.ErrItemInLot, "auction item %d is in lot %d", item.ID, item.LotID)
+		}
+	}
+	return itemIDs, nil
+}
+
+// checkNoBids returns storage.ErrLotHasBids if any of the items have bids.
+func (lc *lotClient) checkNoBids(ctx context.Context, itemIDs []uint64) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	exists, err := lc.db.NewSelect().
+		Model((*AuctionBid)(nil)).
+		Where("item_id IN (?)", bun.In(itemIDs)).
+		Exists(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to get bids of auction items")
+	}
+	if exists {
+		return errors.Wrap(storage.ErrLotHasBids, "auction items have bids")
+	}
+	return nil
+}
+
+// moveToLot puts the items in the lot with the ID, or sells them on their own when the ID is 0. Only the items that
+// change lots have their version incremented.
+func (lc *lotClient) moveToLot(ctx context.Context, itemIDs []uint64, lotID uint64) error {
+	query := lc.db.NewUpdate().
+		Model((*AuctionItem)(nil)).
+		Set("updated_at = ?", time.Now().UTC()).
+		Set("version = version + 1").
+		Where("id IN (?)", bun.In(itemIDs))
+	if lotID == 0 {
+		query = query.Set("lot_id = NULL").Where("lot_id IS NOT NULL")
+	} else {
+		query = query.Set("lot_id = ?", lotID).Where("(lot_id IS NULL OR lot_id <> ?)", lotID)
+	}
+
+	if _, err := query.Exec(ctx); err != nil {
+		return errors.Wrap(err, "unable to move auction items")
+	}
+	return nil
+}
+
+// uniqueIDs removes the IDs that repeat an earlier one.
+func uniqueIDs(ids []uint64) []uint64 {
+	seen := make(map[uint64]bool, len(ids))
+	result := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0001_create_tables",
+		Up:   createTables,
+		Down: dropTables,
+	})
+}
+
+// The models below are the tables as they were created by this migration. Changes to the tables must be made by a new
+// migration instead of changing these models.
+
+type userV1 struct {
+	bun.BaseModel `bun:"users"`
+
+	ID             uint64    `bun:",pk"`
+	CreatedAt      time.Time `bun:",nullzero,notnull"`
+	UpdatedAt      time.Time `bun:",nullzero,notnull"`
+	Username       string    `bun:",notnull,unique"`
+	DisplayName    string    `bun:",notnull"`
+	HashedPassword string    `bun:",notnull"`
+	Permission     string    `bun:",notnull"`
+}
+
+type auctionItemV1 struct {
+	bun.BaseModel `bun:"auction_items"`
+
+	ID          uint64    `bun:",pk"`
+	CreatedAt   time.Time `bun:",nullzero,notnull"`
+	UpdatedAt   time.Time `bun:",nullzero,notnull"`
+	NameID      string    `bun:"name_id,notnull,unique"`
+	DisplayName string    `bun:",notnull"`
+	ImageRef    string    `bun:",notnull"`
+	Description string    `bun:",notnull"`
+}
+
+type auctionBidV1 struct {
+	bun.BaseModel `bun:"auction_bids"`
+
+	ID        uint64    `bun:",pk"`
+	CreatedAt time.Time `bun:",nullzero,notnull"`
+	UpdatedAt time.Time `bun:",nullzero,notnull"`
+	BidAmount int       `bun:",notnull"`
+	BidderID  uint64    `bun:",notnull"`
+	ItemID    uint64    `bun:",notnull"`
+}
+
+// createTables creates the tables as they were before migrations were introduced. IF NOT EXISTS is used so databases
+// created back then are adopted by the migrations without changes.
+func createTables(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewCreateTable().
+		Model((*userV1)(nil)).
+		IfNotExists().
+		Exec(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "unable to create users table")
+	}
+
+	if err = createIndex(ctx, db, (*userV1)(nil), "username_idx", "username"); err != nil {
+		return errors.Wrap(err, "unable to create index on users table")
+	}
+
+	_, err = db.NewCreateTable().
+		Model((*auctionItemV1)(nil)).
+		IfNotExists().
+		Exec(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "unable to create auction_items table")
+	}
+
+	if err = createIndex(ctx, db, (*auctionItemV1)(nil), "name_id_idx", "name_id"); err != nil {
+		return errors.Wrap(err, "unable to create index on auction_items table")
+	}
+
+	_, err = db.NewCreateTable().
+		Model((*auctionBidV1)(nil)).
+		IfNotExists().
+		ForeignKey(`("bidder_id") REFERENCES "users" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("item_id") REFERENCES "auction_items" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "unable to create auction_bids table")
+	}
+
+	if err = createHighestValueTrigger(ctx, db); err != nil {
+		return errors.Wrap(err, "unable to create trigger on auction_bids table")
+	}
+
+	return nil
+}
+
+func dropTables(ctx context.Context, db *bun.DB) error {
+	models := []interface{}{
+		(*auctionBidV1)(nil),
+		(*auctionItemV1)(nil),
+		(*userV1)(nil),
+	}
+	for _, model := range models {
+		if _, err := db.NewDropTable().Model(model).IfExists().Exec(ctx); err != nil {
+			return errors.Wrap(err, "unable to drop table")
+		}
+	}
+
+	// Triggers are dropped along with their table but PostgreSQL keeps the function the trigger executed.
+	if dialectName(db) == dialect.PG {
+		if _, err := db.ExecContext(ctx, "DROP FUNCTION IF EXISTS highest_value_check();"); err != nil {
+			return errors.Wrap(err, "unable to drop highest_value_check function")
+		}
+	}
+	return nil
+}
+
+// createHighestValueTrigger prevents a bid from being inserted unless it is higher than every other bid on the item.
+// Each dialect raises its own error which is recognized by isBidTooLow.
+func createHighestValueTrigger(ctx context.Context, db bun.IDB) error {
+	var statements []string
+	switch name := dialectName(db); name {
+	case dialect.SQLite:
+		statements = []string{`
+		CREATE TRIGGER IF NOT EXISTS highest_value_check
+		BEFORE INSERT ON auction_bids
+		BEGIN
+			SELECT RAISE(FAIL, "cannot bid lower")
+			FROM auction_bids
+			WHERE item_id = NEW.item_id
+			GROUP BY bidder_id, item_id
+			HAVING MAX(bid_amount) >= NEW.bid_amount;
+		END;`,
+		}
+	case dialect.PG:
+		statements = []string{`
+		CREATE OR REPLACE FUNCTION highest_value_check() RETURNS trigger AS $$
+		BEGIN
+			IF EXISTS (
+				SELECT 1
+				FROM auction_bids
+				WHERE item_id = NEW.item_id AND bid_amount >= NEW.bid_amount
+			) THEN
+				RAISE EXCEPTION 'cannot bid lower' USING ERRCODE = '` + postgresBidTooLow + `';
+			END IF;
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql;`,
+			`DROP TRIGGER IF EXISTS highest_value_check ON auction_bids;`,
+			`CREATE TRIGGER highest_value_check
+		BEFORE INSERT ON auction_bids
+		FOR EACH ROW EXECUTE FUNCTION highest_value_check();`,
+		}
+	default:
+		return errors.Errorf("unsupported dialect '%s'", name)
+	}
+
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return errors.Wrap(err, "unable to execute trigger statement")
+		}
+	}
+	return nil
+}
+
+func createIndex(ctx context.Context, db bun.IDB, model interface{}, indexName string, columnName string) error {
+	_, err := db.NewCreateIndex().
+		Model(model).
+		Index(indexName).
+		Column(columnName).
+		IfNotExists().
+		Exec(ctx)
+
+	if err != nil {
+		return errors.Wrapf(err, "unable to create index '%s' on table", indexName)
+	}
+
+	return nil
+}
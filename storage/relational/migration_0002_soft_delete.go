@@ -0,0 +1,55 @@
+package relational
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0002_soft_delete",
+		Up:   addDeletedAtColumns,
+		Down: dropDeletedAtColumns,
+	})
+}
+
+// softDeleteTables are the tables whose rows are marked as deleted instead of being removed.
+var softDeleteTables = []string{"users", "auction_items"}
+
+// addDeletedAtColumns adds the deleted_at column used to soft delete users and items. The column is NULL until the row
+// is deleted.
+func addDeletedAtColumns(ctx context.Context, db *bun.DB) error {
+	columnType := "TIMESTAMP"
+	if dialectName(db) == dialect.PG {
+		columnType = "TIMESTAMPTZ"
+	}
+
+	for _, table := range softDeleteTables {
+		_, err := db.NewAddColumn().
+			Table(table).
+			ColumnExpr("? ?", bun.Ident("deleted_at"), bun.Safe(columnType)).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to add deleted_at column to %s table", table)
+		}
+	}
+	return nil
+}
+
+// dropDeletedAtColumns removes the deleted_at columns. Rows that were deleted become visible again.
+func dropDeletedAtColumns(ctx context.Context, db *bun.DB) error {
+	for _, table := range softDeleteTables {
+		_, err := db.NewDropColumn().
+			Table(table).
+			Column("deleted_at").
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to drop deleted_at column from %s table", table)
+		}
+	}
+	return nil
+}
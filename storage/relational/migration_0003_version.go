@@ -0,0 +1,48 @@
+package relational
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0003_version",
+		Up:   addVersionColumns,
+		Down: dropVersionColumns,
+	})
+}
+
+// versionTables are the tables of every model that embeds baseDBModel.
+var versionTables = []string{"users", "auction_items", "auction_bids"}
+
+// addVersionColumns adds the version column used to detect concurrent changes. Existing rows start at version 1 like
+// new ones do.
+func addVersionColumns(ctx context.Context, db *bun.DB) error {
+	for _, table := range versionTables {
+		_, err := db.NewAddColumn().
+			Table(table).
+			ColumnExpr("? BIGINT NOT NULL DEFAULT 1", bun.Ident("version")).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to add version column to %s table", table)
+		}
+	}
+	return nil
+}
+
+func dropVersionColumns(ctx context.Context, db *bun.DB) error {
+	for _, table := range versionTables {
+		_, err := db.NewDropColumn().
+			Table(table).
+			Column("version").
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to drop version column from %s table", table)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0004_item_images",
+		Up:   createItemImagesTable,
+		Down: dropItemImagesTable,
+	})
+}
+
+// itemImageV4 is the table as it was created by this migration.
+type itemImageV4 struct {
+	bun.BaseModel `bun:"item_images"`
+
+	ID           uint64    `bun:",pk"`
+	CreatedAt    time.Time `bun:",nullzero,notnull"`
+	UpdatedAt    time.Time `bun:",nullzero,notnull"`
+	Version      uint64    `bun:",notnull"`
+	ItemID       uint64    `bun:",notnull"`
+	Position     int       `bun:",notnull"`
+	Key          string    `bun:",notnull"`
+	ThumbnailKey string    `bun:",notnull"`
+	ContentType  string    `bun:",notnull"`
+}
+
+// createItemImagesTable creates the table of the images of each item. Images are removed along with their item when it
+// is purged.
+func createItemImagesTable(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewCreateTable().
+		Model((*itemImageV4)(nil)).
+		IfNotExists().
+		ForeignKey(`("item_id") REFERENCES "auction_items" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to create item_images table")
+	}
+
+	if err = createIndex(ctx, db, (*itemImageV4)(nil), "item_image_item_id_idx", "item_id"); err != nil {
+		return errors.Wrap(err, "unable to create index on item_images table")
+	}
+	return nil
+}
+
+func dropItemImagesTable(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewDropTable().
+		Model((*itemImageV4)(nil)).
+		IfExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop item_images table")
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0005_categories",
+		Up:   addCategoriesAndTags,
+		Down: dropCategoriesAndTags,
+	})
+}
+
+// categoryV5 is the table as it was created by this migration.
+type categoryV5 struct {
+	bun.BaseModel `bun:"categories"`
+
+	ID          uint64    `bun:",pk"`
+	CreatedAt   time.Time `bun:",nullzero,notnull"`
+	UpdatedAt   time.Time `bun:",nullzero,notnull"`
+	Version     uint64    `bun:",notnull"`
+	NameID      string    `bun:"name_id,notnull,unique"`
+	DisplayName string    `bun:",notnull"`
+}
+
+// tagV5 is the table as it was created by this migration.
+type tagV5 struct {
+	bun.BaseModel `bun:"tags"`
+
+	ID          uint64    `bun:",pk"`
+	CreatedAt   time.Time `bun:",nullzero,notnull"`
+	UpdatedAt   time.Time `bun:",nullzero,notnull"`
+	Version     uint64    `bun:",notnull"`
+	NameID      string    `bun:"name_id,notnull,unique"`
+	DisplayName string    `bun:",notnull"`
+}
+
+// itemTagV5 is the table as it was created by this migration.
+type itemTagV5 struct {
+	bun.BaseModel `bun:"item_tags"`
+
+	ItemID uint64 `bun:",pk"`
+	TagID  uint64 `bun:",pk"`
+}
+
+// donorColumns are the columns added to auction_items to record who donated each item.
+var donorColumns = []struct {
+	name       string
+	definition string
+}{
+	{name: "donor_name", definition: "VARCHAR NOT NULL DEFAULT ''"},
+	{name: "donor_business", definition: "VARCHAR NOT NULL DEFAULT ''"},
+	{name: "fair_market_value", definition: "BIGINT NOT NULL DEFAULT 0"},
+}
+
+// addCategoriesAndTags creates the categories, tags and item_tags tables and adds the category and donor columns to
+// auction_items. The category_id column has no foreign key since SQLite cannot drop a column that has one; the client
+// clears it when the category is deleted instead.
+func addCategoriesAndTags(ctx context.Context, db *bun.DB) error {
+	for _, model := range []interface{}{(*categoryV5)(nil), (*tagV5)(nil)} {
+		_, err := db.NewCreateTable().
+			Model(model).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to create table")
+		}
+	}
+
+	_, err := db.NewCreateTable().
+		Model((*itemTagV5)(nil)).
+		IfNotExists().
+		ForeignKey(`("item_id") REFERENCES "auction_items" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("tag_id") REFERENCES "tags" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to create item_tags table")
+	}
+	if err = createIndex(ctx, db, (*itemTagV5)(nil), "item_tag_tag_id_idx", "tag_id"); err != nil {
+		return errors.Wrap(err, "unable to create index on item_tags table")
+	}
+
+	_, err = db.NewAddColumn().
+		Table("auction_items").
+		ColumnExpr("? BIGINT", bun.Ident("category_id")).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to add category_id column to auction_items table")
+	}
+	_, err = db.NewCreateIndex().
+		Table("auction_items").
+		Index("auction_item_category_id_idx").
+		Column("category_id").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to create index on auction_items table")
+	}
+
+	for _, column := range donorColumns {
+		_, err = db.NewAddColumn().
+			Table("auction_items").
+			ColumnExpr("? ?", bun.Ident(column.name), bun.Safe(column.definition)).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to add %s column to auction_items table", column.name)
+		}
+	}
+	return nil
+}
+
+// dropCategoriesAndTags removes the category, tag and donor data of every item.
+func dropCategoriesAndTags(ctx context.Context, db *bun.DB) error {
+	for _, column := range donorColumns {
+		_, err := db.NewDropColumn().
+			Table("auction_items").
+			Column(column.name).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to drop %s column from auction_items table", column.name)
+		}
+	}
+
+	// SQLite refuses to drop a column that is indexed.
+	_, err := db.NewDropIndex().
+		Index("auction_item_category_id_idx").
+		IfExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop index on auction_items table")
+	}
+	_, err = db.NewDropColumn().
+		Table("auction_items").
+		Column("category_id").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop category_id column from auction_items table")
+	}
+
+	for _, model := range []interface{}{(*itemTagV5)(nil), (*tagV5)(nil), (*categoryV5)(nil)} {
+		_, err = db.NewDropTable().
+			Model(model).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to drop table")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package relational
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0006_item_search",
+		Up:   addItemSearch,
+		Down: dropItemSearch,
+	})
+}
+
+// addItemSearch indexes the name and description of every item for full-text search. SQLite keeps the index in the
+// auction_items_fts FTS5 table, which triggers keep in sync with auction_items. PostgreSQL uses a GIN index of the
+// same expression the client searches with.
+func addItemSearch(ctx context.Context, db *bun.DB) error {
+	var statements []string
+	switch name := dialectName(db); name {
+	case dialect.SQLite:
+		statements = []string{`
+		CREATE VIRTUAL TABLE IF NOT EXISTS auction_items_fts USING fts5(
+			display_name, description, content='auction_items', content_rowid='id'
+		);`, `
+		CREATE TRIGGER IF NOT EXISTS auction_items_fts_insert
+		AFTER INSERT ON auction_items
+		BEGIN
+			INSERT INTO auction_items_fts (rowid, display_name, description)
+			VALUES (NEW.id, NEW.display_name, NEW.description);
+		END;`, `
+		CREATE TRIGGER IF NOT EXISTS auction_items_fts_delete
+		AFTER DELETE ON auction_items
+		BEGIN
+			INSERT INTO auction_items_fts (auction_items_fts, rowid, display_name, description)
+			VALUES ('delete', OLD.id, OLD.display_name, OLD.description);
+		END;`, `
+		CREATE TRIGGER IF NOT EXISTS auction_items_fts_update
+		AFTER UPDATE OF display_name, description ON auction_items
+		BEGIN
+			INSERT INTO auction_items_fts (auction_items_fts, rowid, display_name, description)
+			VALUES ('delete', OLD.id, OLD.display_name, OLD.description);
+			INSERT INTO auction_items_fts (rowid, display_name, description)
+			VALUES (NEW.id, NEW.display_name, NEW.description);
+		END;`,
+			`INSERT INTO auction_items_fts (auction_items_fts) VALUES ('rebuild');`,
+		}
+	case dialect.PG:
+		statements = []string{`
+		CREATE INDEX IF NOT EXISTS auction_item_search_idx ON auction_items
+		USING GIN (to_tsvector('simple', display_name || ' ' || description));`,
+		}
+	default:
+		return errors.Errorf("unsupported dialect '%s'", name)
+	}
+
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return errors.Wrap(err, "unable to execute search statement")
+		}
+	}
+	return nil
+}
+
+// dropItemSearch removes the full-text search index of the items.
+func dropItemSearch(ctx context.Context, db *bun.DB) error {
+	var statements []string
+	switch name := dialectName(db); name {
+	case dialect.SQLite:
+		statements = []string{
+			`DROP TRIGGER IF EXISTS auction_items_fts_insert;`,
+			`DROP TRIGGER IF EXISTS auction_items_fts_delete;`,
+			`DROP TRIGGER IF EXISTS auction_items_fts_update;`,
+			`DROP TABLE IF EXISTS auction_items_fts;`,
+		}
+	case dialect.PG:
+		statements = []string{`DROP INDEX IF EXISTS auction_item_search_idx;`}
+	default:
+		return errors.Errorf("unsupported dialect '%s'", name)
+	}
+
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return errors.Wrap(err, "unable to execute search statement")
+		}
+	}
+	return nil
+}
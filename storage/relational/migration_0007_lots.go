@@ -0,0 +1,88 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0007_lots",
+		Up:   addLots,
+		Down: dropLots,
+	})
+}
+
+// lotV7 is the table as it was created by this migration.
+type lotV7 struct {
+	bun.BaseModel `bun:"lots"`
+
+	ID          uint64    `bun:",pk"`
+	CreatedAt   time.Time `bun:",nullzero,notnull"`
+	UpdatedAt   time.Time `bun:",nullzero,notnull"`
+	Version     uint64    `bun:",notnull"`
+	NameID      string    `bun:"name_id,notnull,unique"`
+	DisplayName string    `bun:",notnull"`
+}
+
+// addLots creates the lots table and adds the lot_id column to auction_items. Like category_id, the column has no foreign
+// key since SQLite cannot drop a column that has one; the client clears it when the lot is removed instead.
+func addLots(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewCreateTable().
+		Model((*lotV7)(nil)).
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to create lots table")
+	}
+
+	_, err = db.NewAddColumn().
+		Table("auction_items").
+		ColumnExpr("? BIGINT", bun.Ident("lot_id")).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to add lot_id column to auction_items table")
+	}
+	_, err = db.NewCreateIndex().
+		Table("auction_items").
+		Index("auction_item_lot_id_idx").
+		Column("lot_id").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to create index on auction_items table")
+	}
+	return nil
+}
+
+// dropLots removes every lot so all items are sold on their own again.
+func dropLots(ctx context.Context, db *bun.DB) error {
+	// SQLite refuses to drop a column that is indexed.
+	_, err := db.NewDropIndex().
+		Index("auction_item_lot_id_idx").
+		IfExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop index on auction_items table")
+	}
+	_, err = db.NewDropColumn().
+		Table("auction_items").
+		Column("lot_id").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop lot_id column from auction_items table")
+	}
+
+	_, err = db.NewDropTable().
+		Model((*lotV7)(nil)).
+		IfExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop lots table")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package relational
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0008_item_quantity",
+		Up:   addItemQuantity,
+		Down: dropItemQuantity,
+	})
+}
+
+// addItemQuantity adds the quantity column to auction_items so an item can be won by several bidders. Existing items
+// keep a single winner. The highest_value_check trigger is replaced so a bid only has to beat the bid in the last
+// winning place instead of every bid.
+func addItemQuantity(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewAddColumn().
+		Table("auction_items").
+		ColumnExpr("? BIGINT NOT NULL DEFAULT 1", bun.Ident("quantity")).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to add quantity column to auction_items table")
+	}
+
+	if err = createWinningPlaceTrigger(ctx, db); err != nil {
+		return errors.Wrap(err, "unable to replace trigger on auction_bids table")
+	}
+	return nil
+}
+
+// dropItemQuantity puts back the trigger that only lets a bid in when it beats every other bid and removes the quantity
+// of every item.
+func dropItemQuantity(ctx context.Context, db *bun.DB) error {
+	// SQLite refuses to drop a column that is used by a trigger and only creates the trigger if it does not exist.
+	if dialectName(db) == dialect.SQLite {
+		if _, err := db.ExecContext(ctx, "DROP TRIGGER IF EXISTS highest_value_check;"); err != nil {
+			return errors.Wrap(err, "unable to drop trigger on auction_bids table")
+		}
+	}
+	if err := createHighestValueTrigger(ctx, db); err != nil {
+		return errors.Wrap(err, "unable to restore trigger on auction_bids table")
+	}
+
+	_, err := db.NewDropColumn().
+		Table("auction_items").
+		Column("quantity").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop quantity column from auction_items table")
+	}
+	return nil
+}
+
+// createWinningPlaceTrigger prevents a bid from being inserted unless it is higher than the other bids of the bidder and
+// fewer other bidders than the quantity of the item have bid at least as much, so the bid takes a winning place. Each
+// dialect raises the same error as the trigger it replaces.
+func createWinningPlaceTrigger(ctx context.Context, db bun.IDB) error {
+	const tooLowCondition = `EXISTS (
+				SELECT 1
+				FROM auction_bids
+				WHERE item_id = NEW.item_id AND bidder_id = NEW.bidder_id AND bid_amount >= NEW.bid_amount
+			) OR (
+				SELECT COUNT(DISTINCT bidder_id)
+				FROM auction_bids
+				WHERE item_id = NEW.item_id AND bidder_id <> NEW.bidder_id AND bid_amount >= NEW.bid_amount
+			) >= (SELECT quantity FROM auction_items WHERE id = NEW.item_id)`
+
+	var statements []string
+	switch name := dialectName(db); name {
+	case dialect.SQLite:
+		statements = []string{
+			`DROP TRIGGER IF EXISTS highest_value_check;`, `
+		CREATE TRIGGER highest_value_check
+		BEFORE INSERT ON auction_bids
+		BEGIN
+			SELECT RAISE(FAIL, 'cannot bid lower')
+			WHERE ` + tooLowCondition + `;
+		END;`,
+		}
+	case dialect.PG:
+		// The existing trigger executes the function so only the function has to be replaced.
+		statements = []string{`
+		CREATE OR REPLACE FUNCTION highest_value_check() RETURNS trigger AS $$
+		BEGIN
+			IF ` + tooLowCondition + ` THEN
+				RAISE EXCEPTION 'cannot bid lower' USING ERRCODE = '` + postgresBidTooLow + `';
+			END IF;
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql;`,
+		}
+	default:
+		return errors.Errorf("unsupported dialect '%s'", name)
+	}
+
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return errors.Wrap(err, "unable to execute trigger statement")
+		}
+	}
+	return nil
+}
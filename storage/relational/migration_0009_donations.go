@@ -0,0 +1,88 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0009_donations",
+		Up:   addDonations,
+		Down: dropDonations,
+	})
+}
+
+// appealLevelV9 is the table as it was created by this migration.
+type appealLevelV9 struct {
+	bun.BaseModel `bun:"appeal_levels"`
+
+	ID          uint64    `bun:",pk"`
+	CreatedAt   time.Time `bun:",nullzero,notnull"`
+	UpdatedAt   time.Time `bun:",nullzero,notnull"`
+	Version     uint64    `bun:",notnull"`
+	NameID      string    `bun:"name_id,notnull,unique"`
+	DisplayName string    `bun:",notnull"`
+	Amount      int       `bun:",notnull"`
+}
+
+// pledgeV9 is the table as it was created by this migration.
+type pledgeV9 struct {
+	bun.BaseModel `bun:"pledges"`
+
+	ID         uint64    `bun:",pk"`
+	CreatedAt  time.Time `bun:",nullzero,notnull"`
+	UpdatedAt  time.Time `bun:",nullzero,notnull"`
+	Version    uint64    `bun:",notnull"`
+	Amount     int       `bun:",notnull"`
+	RecordedBy string    `bun:",notnull"`
+	LevelID    uint64    `bun:",notnull"`
+	BidderID   uint64    `bun:",notnull"`
+}
+
+// addDonations creates the appeal_levels and pledges tables. A level cannot be removed while it has pledges so the
+// amounts owed by bidders never change behind their back.
+func addDonations(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewCreateTable().
+		Model((*appealLevelV9)(nil)).
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to create appeal_levels table")
+	}
+
+	_, err = db.NewCreateTable().
+		Model((*pledgeV9)(nil)).
+		IfNotExists().
+		ForeignKey(`("level_id") REFERENCES "appeal_levels" ("id")`).
+		ForeignKey(`("bidder_id") REFERENCES "users" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to create pledges table")
+	}
+	if err = createIndex(ctx, db, (*pledgeV9)(nil), "pledge_level_id_idx", "level_id"); err != nil {
+		return errors.Wrap(err, "unable to create index on pledges table")
+	}
+	if err = createIndex(ctx, db, (*pledgeV9)(nil), "pledge_bidder_id_idx", "bidder_id"); err != nil {
+		return errors.Wrap(err, "unable to create index on pledges table")
+	}
+	return nil
+}
+
+// dropDonations removes every appeal level and pledge.
+func dropDonations(ctx context.Context, db *bun.DB) error {
+	for _, model := range []interface{}{(*pledgeV9)(nil), (*appealLevelV9)(nil)} {
+		_, err := db.NewDropTable().
+			Model(model).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to drop table")
+		}
+	}
+	return nil
+}
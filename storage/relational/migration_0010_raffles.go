@@ -0,0 +1,96 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0010_raffles",
+		Up:   addRaffles,
+		Down: dropRaffles,
+	})
+}
+
+// raffleV10 is the table as it was created by this migration.
+type raffleV10 struct {
+	bun.BaseModel `bun:"raffles"`
+
+	ID             uint64    `bun:",pk"`
+	CreatedAt      time.Time `bun:",nullzero,notnull"`
+	UpdatedAt      time.Time `bun:",nullzero,notnull"`
+	Version        uint64    `bun:",notnull"`
+	NameID         string    `bun:"name_id,notnull,unique"`
+	DisplayName    string    `bun:",notnull"`
+	Description    string    `bun:",notnull"`
+	TicketPrice    int       `bun:",notnull"`
+	Prizes         int       `bun:",notnull"`
+	DrawingSeed    string    `bun:",nullzero"`
+	DrawingTickets []string  `bun:",nullzero"`
+	DrawingWinners []int     `bun:",nullzero"`
+	DrawnAt        time.Time `bun:",nullzero"`
+}
+
+// ticketPurchaseV10 is the table as it was created by this migration.
+type ticketPurchaseV10 struct {
+	bun.BaseModel `bun:"ticket_purchases"`
+
+	ID         uint64    `bun:",pk"`
+	CreatedAt  time.Time `bun:",nullzero,notnull"`
+	UpdatedAt  time.Time `bun:",nullzero,notnull"`
+	Version    uint64    `bun:",notnull"`
+	Quantity   int       `bun:",notnull"`
+	Price      int       `bun:",notnull"`
+	RecordedBy string    `bun:",notnull"`
+	RaffleID   uint64    `bun:",notnull"`
+	BuyerID    uint64    `bun:",notnull"`
+}
+
+// addRaffles creates the raffles and ticket_purchases tables. The drawing of a raffle is kept in the columns of the
+// raffle, which are NULL until it is drawn, and lists the buyer of every ticket by username so the drawing can still be
+// audited after the buyers are purged.
+func addRaffles(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewCreateTable().
+		Model((*raffleV10)(nil)).
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to create raffles table")
+	}
+
+	_, err = db.NewCreateTable().
+		Model((*ticketPurchaseV10)(nil)).
+		IfNotExists().
+		ForeignKey(`("raffle_id") REFERENCES "raffles" ("id")`).
+		ForeignKey(`("buyer_id") REFERENCES "users" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to create ticket_purchases table")
+	}
+	if err = createIndex(ctx, db, (*ticketPurchaseV10)(nil), "ticket_purchase_raffle_id_idx", "raffle_id"); err != nil {
+		return errors.Wrap(err, "unable to create index on ticket_purchases table")
+	}
+	if err = createIndex(ctx, db, (*ticketPurchaseV10)(nil), "ticket_purchase_buyer_id_idx", "buyer_id"); err != nil {
+		return errors.Wrap(err, "unable to create index on ticket_purchases table")
+	}
+	return nil
+}
+
+// dropRaffles removes every raffle and ticket purchase.
+func dropRaffles(ctx context.Context, db *bun.DB) error {
+	for _, model := range []interface{}{(*ticketPurchaseV10)(nil), (*raffleV10)(nil)} {
+		_, err := db.NewDropTable().
+			Model(model).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to drop table")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package relational
+
+import (
+	"context"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0011_bid_currency",
+		Up:   addBidCurrency,
+		Down: dropBidCurrency,
+	})
+}
+
+// addBidCurrency converts the amount of every bid from whole units to the minor units of its currency and adds the
+// currency column to auction_bids. Bids used to be whole units without a currency so they are converted as amounts in
+// the currency given to WithEventCurrency.
+func addBidCurrency(ctx context.Context, db *bun.DB) error {
+	legacyBidCurrency := eventCurrency(ctx)
+	legacyMinorUnits := model.WholeUnits(1, legacyBidCurrency).Amount
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// bun refuses to update a table without a WHERE clause.
+		_, err := tx.NewUpdate().
+			Table("auction_bids").
+			Set("bid_amount = bid_amount * ?", legacyMinorUnits).
+			Where("1 = 1").
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to convert bid amounts to minor units")
+		}
+
+		_, err = tx.NewAddColumn().
+			Table("auction_bids").
+			ColumnExpr("? VARCHAR NOT NULL DEFAULT ?", bun.Ident("currency"), legacyBidCurrency).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to add currency column to auction_bids table")
+		}
+		return nil
+	})
+}
+
+// dropBidCurrency removes the currency of every bid and converts the amounts back to whole units. Amounts that are not
+// whole units are rounded down.
+func dropBidCurrency(ctx context.Context, db *bun.DB) error {
+	legacyMinorUnits := model.WholeUnits(1, eventCurrency(ctx)).Amount
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDropColumn().
+			Table("auction_bids").
+			Column("currency").
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to drop currency column from auction_bids table")
+		}
+
+		_, err = tx.NewUpdate().
+			Table("auction_bids").
+			Set("bid_amount = bid_amount / ?", legacyMinorUnits).
+			Where("1 = 1").
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to convert bid amounts to whole units")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,56 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0012_spending_limits",
+		Up:   addSpendingLimits,
+		Down: dropSpendingLimits,
+	})
+}
+
+// spendingLimitV12 is the table as it was created by this migration.
+type spendingLimitV12 struct {
+	bun.BaseModel `bun:"spending_limits"`
+
+	ID        uint64    `bun:",pk"`
+	CreatedAt time.Time `bun:",nullzero,notnull"`
+	UpdatedAt time.Time `bun:",nullzero,notnull"`
+	Version   uint64    `bun:",notnull"`
+	Amount    int64     `bun:",notnull"`
+	Currency  string    `bun:",notnull"`
+	UserID    uint64    `bun:",nullzero,unique"`
+}
+
+// addSpendingLimits creates the spending_limits table. The limit of the event is the row without a user.
+func addSpendingLimits(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewCreateTable().
+		Model((*spendingLimitV12)(nil)).
+		IfNotExists().
+		ForeignKey(`("user_id") REFERENCES "users" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to create spending_limits table")
+	}
+	return nil
+}
+
+// dropSpendingLimits removes every spending limit.
+func dropSpendingLimits(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewDropTable().
+		Model((*spendingLimitV12)(nil)).
+		IfExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop spending_limits table")
+	}
+	return nil
+}
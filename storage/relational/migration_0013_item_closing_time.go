@@ -0,0 +1,48 @@
+package relational
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0013_item_closing_time",
+		Up:   addItemClosingTime,
+		Down: dropItemClosingTime,
+	})
+}
+
+// addItemClosingTime adds the closes_at column to auction_items so bidding on each item can close on its own. The
+// column is NULL for items that stay open until the auction ends, which is every existing item.
+func addItemClosingTime(ctx context.Context, db *bun.DB) error {
+	columnType := "TIMESTAMP"
+	if dialectName(db) == dialect.PG {
+		columnType = "TIMESTAMPTZ"
+	}
+
+	_, err := db.NewAddColumn().
+		Table("auction_items").
+		ColumnExpr("? ?", bun.Ident("closes_at"), bun.Safe(columnType)).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to add closes_at column to auction_items table")
+	}
+	return nil
+}
+
+// dropItemClosingTime removes the closing time of every item.
+func dropItemClosingTime(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewDropColumn().
+		Table("auction_items").
+		Column("closes_at").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop closes_at column from auction_items table")
+	}
+	return nil
+}
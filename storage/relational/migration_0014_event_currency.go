@@ -0,0 +1,74 @@
+package relational
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0014_event_currency",
+		Up:   addEventSettings,
+		Down: dropEventSettings,
+	})
+}
+
+// eventSettingV14 is the table as it was created by this migration.
+type eventSettingV14 struct {
+	bun.BaseModel `bun:"event_settings"`
+
+	Name  string `bun:",pk"`
+	Value string `bun:",notnull"`
+}
+
+// addEventSettings creates the event_settings table. The currency of the event is taken from the earliest bid, or the
+// earliest spending limit when there are no bids, so a server started with a different currency refuses to start.
+func addEventSettings(ctx context.Context, db *bun.DB) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewCreateTable().
+			Model((*eventSettingV14)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to create event_settings table")
+		}
+
+		for _, table := range []string{"auction_bids", "spending_limits"} {
+			var currency string
+			err = tx.NewSelect().
+				Table(table).
+				Column("currency").
+				Order("id").
+				Limit(1).
+				Scan(ctx, &currency)
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "unable to get currency of %s", table)
+			}
+
+			_, err = tx.NewInsert().
+				Model(&eventSettingV14{Name: settingCurrency, Value: currency}).
+				Exec(ctx)
+			return errors.Wrap(err, "unable to store currency of the event")
+		}
+		return nil
+	})
+}
+
+// dropEventSettings removes every setting of the event.
+func dropEventSettings(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewDropTable().
+		Model((*eventSettingV14)(nil)).
+		IfExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop event_settings table")
+	}
+	return nil
+}
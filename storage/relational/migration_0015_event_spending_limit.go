@@ -0,0 +1,53 @@
+package relational
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0015_event_spending_limit",
+		Up:   addEventSpendingLimitIndex,
+		Down: dropEventSpendingLimitIndex,
+	})
+}
+
+// addEventSpendingLimitIndex makes sure the event has at most one spending limit. The unique constraint on user_id does
+// not cover the limit of the event since NULLs are never equal, so the latest of any duplicate limits is kept.
+func addEventSpendingLimitIndex(ctx context.Context, db *bun.DB) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Table("spending_limits").
+			Where("user_id IS NULL").
+			Where("id < (SELECT MAX(id) FROM spending_limits WHERE user_id IS NULL)").
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to delete duplicate spending limits of the event")
+		}
+
+		// Every limit of the event has the same value for the expression so only one of them can be stored.
+		_, err = tx.ExecContext(ctx, `
+		CREATE UNIQUE INDEX IF NOT EXISTS spending_limit_event_idx ON spending_limits ((user_id IS NULL))
+		WHERE user_id IS NULL;`)
+		if err != nil {
+			return errors.Wrap(err, "unable to create index on spending_limits table")
+		}
+		return nil
+	})
+}
+
+// dropEventSpendingLimitIndex allows the event to have more than one spending limit again.
+func dropEventSpendingLimitIndex(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewDropIndex().
+		Index("spending_limit_event_idx").
+		IfExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop index on spending_limits table")
+	}
+	return nil
+}
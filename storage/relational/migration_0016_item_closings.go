@@ -0,0 +1,61 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0016_item_closings",
+		Up:   addItemClosings,
+		Down: dropItemClosings,
+	})
+}
+
+// itemClosingV16 is the table as it was created by this migration.
+type itemClosingV16 struct {
+	bun.BaseModel `bun:"item_closings"`
+
+	ItemID   uint64    `bun:",pk"`
+	ClosesAt time.Time `bun:",notnull"`
+}
+
+// addItemClosings creates the item_closings table. The items that already closed are recorded as announced so their
+// closing is not announced long after it happened.
+func addItemClosings(ctx context.Context, db *bun.DB) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewCreateTable().
+			Model((*itemClosingV16)(nil)).
+			IfNotExists().
+			ForeignKey(`("item_id") REFERENCES "auction_items" ("id") ON DELETE CASCADE`).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to create item_closings table")
+		}
+
+		_, err = tx.ExecContext(ctx, `
+		INSERT INTO item_closings (item_id, closes_at)
+		SELECT id, closes_at FROM auction_items WHERE closes_at <= ?;`, time.Now())
+		if err != nil {
+			return errors.Wrap(err, "unable to record closed items")
+		}
+		return nil
+	})
+}
+
+// dropItemClosings forgets which closings were announced.
+func dropItemClosings(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewDropTable().
+		Model((*itemClosingV16)(nil)).
+		IfExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop item_closings table")
+	}
+	return nil
+}
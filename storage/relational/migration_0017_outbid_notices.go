@@ -0,0 +1,75 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0017_outbid_notices",
+		Up:   addOutbidNotices,
+		Down: dropOutbidNotices,
+	})
+}
+
+// outbidNoticeV17 is the table as it was created by this migration.
+type outbidNoticeV17 struct {
+	bun.BaseModel `bun:"outbid_notices"`
+
+	ID         uint64    `bun:",pk"`
+	CreatedAt  time.Time `bun:",nullzero,notnull"`
+	UpdatedAt  time.Time `bun:",nullzero,notnull"`
+	Version    uint64    `bun:",notnull"`
+	UserID     uint64    `bun:",notnull"`
+	ItemID     uint64    `bun:",notnull"`
+	ItemName   string    `bun:",notnull"`
+	LotID      uint64    `bun:",nullzero"`
+	LotName    string    `bun:",nullzero"`
+	CurrentBid int64     `bun:",notnull"`
+	MinimumBid int64     `bun:",notnull"`
+	Currency   string    `bun:",notnull"`
+}
+
+// addOutbidNotices creates the outbid_notices table so bidders who are not connected are told they were outbid by
+// whichever instance they connect to next.
+func addOutbidNotices(ctx context.Context, db *bun.DB) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewCreateTable().
+			Model((*outbidNoticeV17)(nil)).
+			IfNotExists().
+			ForeignKey(`("user_id") REFERENCES "users" ("id") ON DELETE CASCADE`).
+			ForeignKey(`("item_id") REFERENCES "auction_items" ("id") ON DELETE CASCADE`).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to create outbid_notices table")
+		}
+
+		_, err = tx.NewCreateIndex().
+			Model((*outbidNoticeV17)(nil)).
+			Index("outbid_notice_user_id_idx").
+			Column("user_id").
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to create outbid_notice_user_id_idx index")
+		}
+		return nil
+	})
+}
+
+// dropOutbidNotices removes every outbid notice that was not delivered.
+func dropOutbidNotices(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewDropTable().
+		Model((*outbidNoticeV17)(nil)).
+		IfExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to drop outbid_notices table")
+	}
+	return nil
+}
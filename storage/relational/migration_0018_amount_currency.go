@@ -0,0 +1,93 @@
+package relational
+
+import (
+	"context"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func init() {
+	migrations.Add(migrate.Migration{
+		Name: "0018_amount_currency",
+		Up:   addAmountCurrency,
+		Down: dropAmountCurrency,
+	})
+}
+
+// amountColumnV18 is a column of whole units that is converted to an amount of money by this migration.
+type amountColumnV18 struct {
+	table    string
+	amount   string
+	currency string
+}
+
+var amountColumnsV18 = []amountColumnV18{
+	{table: "auction_items", amount: "fair_market_value", currency: "fair_market_value_currency"},
+	{table: "appeal_levels", amount: "amount", currency: "currency"},
+	{table: "pledges", amount: "amount", currency: "currency"},
+	{table: "raffles", amount: "ticket_price", currency: "currency"},
+	{table: "ticket_purchases", amount: "price", currency: "currency"},
+}
+
+// addAmountCurrency converts the fair market values of items, the amounts of appeal levels and pledges and the ticket
+// prices of raffles from whole units to the minor units of their currency and adds a currency column next to each of
+// them. They used to be whole units without a currency like bids were before 0011_bid_currency, so they are converted
+// as amounts in the currency given to WithEventCurrency. The currency is left empty for an amount of 0, which is an item
+// whose donor did not state a value.
+func addAmountCurrency(ctx context.Context, db *bun.DB) error {
+	legacyCurrency := eventCurrency(ctx)
+	legacyMinorUnits := model.WholeUnits(1, legacyCurrency).Amount
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, column := range amountColumnsV18 {
+			_, err := tx.NewAddColumn().
+				Table(column.table).
+				ColumnExpr("? VARCHAR NOT NULL DEFAULT ''", bun.Ident(column.currency)).
+				Exec(ctx)
+			if err != nil {
+				return errors.Wrapf(err, "unable to add %s column to %s table", column.currency, column.table)
+			}
+
+			_, err = tx.NewUpdate().
+				Table(column.table).
+				Set("? = ? * ?", bun.Ident(column.amount), bun.Ident(column.amount), legacyMinorUnits).
+				Set("? = ?", bun.Ident(column.currency), legacyCurrency).
+				Where("? <> 0", bun.Ident(column.amount)).
+				Exec(ctx)
+			if err != nil {
+				return errors.Wrapf(err, "unable to convert %s of %s table to minor units", column.amount, column.table)
+			}
+		}
+		return nil
+	})
+}
+
+// dropAmountCurrency removes the currency columns added by addAmountCurrency and converts the amounts back to whole
+// units. Amounts that are not whole units are rounded down.
+func dropAmountCurrency(ctx context.Context, db *bun.DB) error {
+	legacyMinorUnits := model.WholeUnits(1, eventCurrency(ctx)).Amount
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, column := range amountColumnsV18 {
+			_, err := tx.NewDropColumn().
+				Table(column.table).
+				Column(column.currency).
+				Exec(ctx)
+			if err != nil {
+				return errors.Wrapf(err, "unable to drop %s column from %s table", column.currency, column.table)
+			}
+
+			// bun refuses to update a table without a WHERE clause.
+			_, err = tx.NewUpdate().
+				Table(column.table).
+				Set("? = ? / ?", bun.Ident(column.amount), bun.Ident(column.amount), legacyMinorUnits).
+				Where("1 = 1").
+				Exec(ctx)
+			if err != nil {
+				return errors.Wrapf(err, "unable to convert %s of %s table to whole units", column.amount, column.table)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,126 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+// migrations are every change made to the schema. Each migration lives in its own file and is named after the order
+// it is applied in, e.g. 0002_add_column. Applied migrations are tracked in the bun_migrations table.
+var migrations = migrate.NewMigrations()
+
+// ErrSchemaOutOfDate is returned when the database has not had every migration applied to it.
+var ErrSchemaOutOfDate = errors.New("database schema is out of date")
+
+// MigrationStatus describes whether a migration has been applied to the database.
+type MigrationStatus struct {
+	Name       string
+	Applied    bool
+	MigratedAt time.Time
+}
+
+// Migrator applies and rolls back the migrations of the database schema.
+type Migrator struct {
+	migrator *migrate.Migrator
+}
+
+// NewMigrator creates a Migrator for the database.
+func NewMigrator(db *bun.DB) *Migrator {
+	return &Migrator{
+		migrator: migrate.NewMigrator(db, migrations),
+	}
+}
+
+// Up applies every migration that has not been applied yet and returns their names.
+func (m *Migrator) Up(ctx context.Context) ([]string, error) {
+	if err := m.migrator.Init(ctx); err != nil {
+		return nil, errors.Wrap(err, "unable to create migration tables")
+	}
+
+	group, err := m.migrator.Migrate(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to apply migrations")
+	}
+
+	names := make([]string, len(group.Migrations))
+	for i, migration := range group.Migrations {
+		names[i] = migration.Name
+	}
+	return names, nil
+}
+
+// Down rolls back the most recently applied migration and returns its name. An empty name is returned if no migrations
+// have been applied.
+func (m *Migrator) Down(ctx context.Context) (string, error) {
+	if err := m.migrator.Init(ctx); err != nil {
+		return "", errors.Wrap(err, "unable to create migration tables")
+	}
+
+	if err := m.migrator.Lock(ctx); err != nil {
+		return "", errors.Wrap(err, "unable to lock migrations")
+	}
+	defer m.migrator.Unlock(ctx)
+
+	all, err := m.migrator.MigrationsWithStatus(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to get migration status")
+	}
+
+	applied := all.Applied()
+	if len(applied) == 0 {
+		return "", nil
+	}
+
+	migration := &applied[0]
+	if migration.Down != nil {
+		if err := migration.Down(ctx, m.migrator.DB()); err != nil {
+			return "", errors.Wrapf(err, "unable to roll back migration '%s'", migration.Name)
+		}
+	}
+
+	if err := m.migrator.MarkUnapplied(ctx, migration); err != nil {
+		return "", errors.Wrapf(err, "unable to mark migration '%s' as rolled back", migration.Name)
+	}
+	return migration.Name, nil
+}
+
+// Status returns every migration in the order they are applied.
+func (m *Migrator) Status(ctx context.Context) ([]*MigrationStatus, error) {
+	if err := m.migrator.Init(ctx); err != nil {
+		return nil, errors.Wrap(err, "unable to create migration tables")
+	}
+
+	all, err := m.migrator.MigrationsWithStatus(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get migration status")
+	}
+
+	result := make([]*MigrationStatus, len(all))
+	for i, migration := range all {
+		result[i] = &MigrationStatus{
+			Name:       migration.Name,
+			Applied:    migration.IsApplied(),
+			MigratedAt: migration.MigratedAt,
+		}
+	}
+	return result, nil
+}
+
+// VerifyUpToDate returns ErrSchemaOutOfDate if any migration has not been applied.
+func (m *Migrator) VerifyUpToDate(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to get migration status")
+	}
+
+	for _, status := range statuses {
+		if !status.Applied {
+			return errors.Wrapf(ErrSchemaOutOfDate, "migration '%s' has not been applied", status.Name)
+		}
+	}
+	return nil
+}
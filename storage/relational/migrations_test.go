@@ -0,0 +1,283 @@
+package relational
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+)
+
+type migratorTestSuite struct {
+	suite.Suite
+
+	dsn      string
+	ctx      context.Context
+	db       *bun.DB
+	migrator *Migrator
+}
+
+func (ts *migratorTestSuite) SetupTest() {
+	db, err := Open(ts.dsn)
+	ts.Require().NoError(err)
+
+	ts.ctx = context.Background()
+	ts.db = db
+	ts.migrator = NewMigrator(db)
+
+	for {
+		name, err := ts.migrator.Down(ts.ctx)
+		ts.Require().NoError(err)
+		if name == "" {
+			break
+		}
+	}
+}
+
+func (ts *migratorTestSuite) TearDownTest() {
+	// Other suites may share the database so it is always left with every migration applied.
+	_, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().NoError(ts.db.Close())
+}
+
+func TestMigrator(t *testing.T) {
+	runOnAllDatabases(t, func(t *testing.T, dsn string) {
+		// Rolling back the shared in-memory SQLite database would interfere with the other suites.
+		if !isPostgresDSN(dsn) {
+			dsn = fmt.Sprintf("file:%s?_pragma=foreign_keys%%3Dtrue", filepath.Join(t.TempDir(), "biddr.db"))
+		}
+		suite.Run(t, &migratorTestSuite{dsn: dsn})
+	})
+}
+
+func (ts *migratorTestSuite) TestUpAppliesEveryMigration() {
+	applied, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(applied, len(migrations.Sorted()))
+	ts.Require().NoError(ts.migrator.VerifyUpToDate(ts.ctx))
+
+	statuses, err := ts.migrator.Status(ts.ctx)
+	ts.Require().NoError(err)
+	for _, status := range statuses {
+		ts.Require().True(status.Applied, status.Name)
+		ts.Require().False(status.MigratedAt.IsZero(), status.Name)
+	}
+}
+
+func (ts *migratorTestSuite) TestUpDoesNothingWhenUpToDate() {
+	_, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+
+	applied, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Empty(applied)
+}
+
+func (ts *migratorTestSuite) TestDownRollsBackOnlyTheLatestMigration() {
+	_, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+
+	rolledBack, err := ts.migrator.Down(ts.ctx)
+	ts.Require().NoError(err)
+
+	sorted := migrations.Sorted()
+	ts.Require().EqualValues(sorted[len(sorted)-1].Name, rolledBack)
+
+	statuses, err := ts.migrator.Status(ts.ctx)
+	ts.Require().NoError(err)
+	for i, status := range statuses {
+		ts.Require().EqualValues(i < len(statuses)-1, status.Applied, status.Name)
+	}
+}
+
+func (ts *migratorTestSuite) TestDownReturnsEmptyNameWhenNothingApplied() {
+	rolledBack, err := ts.migrator.Down(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Empty(rolledBack)
+}
+
+func (ts *migratorTestSuite) TestDownRemovesTables() {
+	_, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	for {
+		name, err := ts.migrator.Down(ts.ctx)
+		ts.Require().NoError(err)
+		if name == "" {
+			break
+		}
+	}
+
+	_, err = ts.db.NewSelect().Model((*User)(nil)).Count(ts.ctx)
+	ts.Require().Error(err)
+}
+
+func (ts *migratorTestSuite) TestVerifyUpToDateReturnsErrSchemaOutOfDateWhenPending() {
+	err := ts.migrator.VerifyUpToDate(ts.ctx)
+	ts.Require().ErrorIs(err, ErrSchemaOutOfDate)
+}
+
+func (ts *migratorTestSuite) TestUpAdoptsDatabaseCreatedBeforeMigrations() {
+	ts.Require().NoError(createTables(ts.ctx, ts.db))
+	user := &userV1{Username: "existing", Permission: "Bidder", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	_, err := ts.db.NewInsert().Model(user).Exec(ts.ctx)
+	ts.Require().NoError(err)
+
+	_, err = ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+
+	retrieved, err := NewUserClient(ts.db).Get(ts.ctx, "existing")
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("existing", retrieved.Username)
+}
+
+func (ts *migratorTestSuite) TestBidCurrencyConvertsExistingBidsToCents() {
+	_, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	user := &model.User{Username: "bidder", Permission: model.PermissionLevelBidder}
+	ts.Require().NoError(NewUserClient(ts.db).Create(ts.ctx, user))
+	item := &model.AuctionItem{Name: "item"}
+	ts.Require().NoError(NewAuctionItemClient(ts.db).Create(ts.ctx, item))
+	ts.rollBackTo("0011_bid_currency")
+
+	bid := &auctionBidV1{CreatedAt: time.Now(), UpdatedAt: time.Now(), BidAmount: 12, ItemID: item.ID}
+	_, err = ts.db.NewInsert().
+		Model(bid).
+		Value("bidder_id", "(SELECT id FROM users WHERE username = ?)", user.Username).
+		Exec(ts.ctx)
+	ts.Require().NoError(err)
+
+	_, err = ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	highest, err := NewAuctionBidClient(ts.db).GetHighestBid(ts.ctx, item)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(model.Money{Amount: 1200, Currency: "USD"}, highest.BidAmount)
+	ts.Require().ErrorIs(EnsureEventCurrency(ts.ctx, ts.db, "EUR"), ErrEventCurrencyChanged)
+
+	ts.rollBackTo("0011_bid_currency")
+	var amount int
+	ts.Require().NoError(ts.db.NewSelect().Table("auction_bids").Column("bid_amount").Scan(ts.ctx, &amount))
+	ts.Require().EqualValues(12, amount)
+}
+
+func (ts *migratorTestSuite) TestBidCurrencyConvertsExistingBidsInEventCurrency() {
+	_, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	user := &model.User{Username: "bidder", Permission: model.PermissionLevelBidder}
+	ts.Require().NoError(NewUserClient(ts.db).Create(ts.ctx, user))
+	item := &model.AuctionItem{Name: "item"}
+	ts.Require().NoError(NewAuctionItemClient(ts.db).Create(ts.ctx, item))
+	ts.rollBackTo("0011_bid_currency")
+
+	bid := &auctionBidV1{CreatedAt: time.Now(), UpdatedAt: time.Now(), BidAmount: 1200, ItemID: item.ID}
+	_, err = ts.db.NewInsert().
+		Model(bid).
+		Value("bidder_id", "(SELECT id FROM users WHERE username = ?)", user.Username).
+		Exec(ts.ctx)
+	ts.Require().NoError(err)
+
+	_, err = ts.migrator.Up(WithEventCurrency(ts.ctx, "JPY"))
+	ts.Require().NoError(err)
+	highest, err := NewAuctionBidClient(ts.db).GetHighestBid(ts.ctx, item)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(model.Money{Amount: 1200, Currency: "JPY"}, highest.BidAmount)
+	ts.Require().NoError(EnsureEventCurrency(ts.ctx, ts.db, "JPY"))
+	ts.Require().ErrorIs(EnsureEventCurrency(ts.ctx, ts.db, "USD"), ErrEventCurrencyChanged)
+}
+
+func (ts *migratorTestSuite) TestEnsureEventCurrencyStoresCurrencyOfNewEvent() {
+	_, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+
+	ts.Require().NoError(EnsureEventCurrency(ts.ctx, ts.db, "EUR"))
+	ts.Require().NoError(EnsureEventCurrency(ts.ctx, ts.db, "EUR"))
+	ts.Require().ErrorIs(EnsureEventCurrency(ts.ctx, ts.db, "USD"), ErrEventCurrencyChanged)
+}
+
+func (ts *migratorTestSuite) TestEventSpendingLimitKeepsOnlyLatestLimitOfEvent() {
+	_, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	ts.rollBackTo("0015_event_spending_limit")
+
+	for _, amount := range []int64{100, 200} {
+		_, err = ts.db.NewInsert().Model(&SpendingLimit{Amount: amount, Currency: "USD"}).Exec(ts.ctx)
+		ts.Require().NoError(err)
+	}
+
+	_, err = ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	limits, err := NewSpendingLimitClient(ts.db).GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(limits, 1)
+	ts.Require().EqualValues(model.Money{Amount: 200, Currency: "USD"}, limits[0].Amount)
+
+	_, err = ts.db.NewInsert().Model(&SpendingLimit{Amount: 300, Currency: "USD"}).Exec(ts.ctx)
+	ts.Require().True(isUniqueViolation(err), "%v", err)
+}
+
+func (ts *migratorTestSuite) TestAmountCurrencyConvertsExistingAmountsToCents() {
+	_, err := ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	user := &model.User{Username: "bidder", Permission: model.PermissionLevelBidder}
+	ts.Require().NoError(NewUserClient(ts.db).Create(ts.ctx, user))
+	itemClient := NewAuctionItemClient(ts.db)
+	valued := &model.AuctionItem{Name: "valued", FairMarketValue: usd(15000)}
+	ts.Require().NoError(itemClient.Create(ts.ctx, valued))
+	unvalued := &model.AuctionItem{Name: "unvalued"}
+	ts.Require().NoError(itemClient.Create(ts.ctx, unvalued))
+	donationClient := NewDonationClient(ts.db)
+	level := &model.AppealLevel{Name: "Classroom", Amount: usd(50000)}
+	ts.Require().NoError(donationClient.CreateLevel(ts.ctx, level))
+	ts.Require().NoError(donationClient.CreatePledge(ts.ctx, &model.Pledge{Level: level, Bidder: user}))
+	raffleClient := NewRaffleClient(ts.db)
+	raffle := &model.Raffle{Name: "Quilt", TicketPrice: usd(500), Prizes: 1}
+	ts.Require().NoError(raffleClient.Create(ts.ctx, raffle))
+	purchase := &model.TicketPurchase{RaffleID: raffle.ID, Buyer: user, Quantity: 2}
+	ts.Require().NoError(raffleClient.BuyTickets(ts.ctx, purchase))
+
+	ts.rollBackTo("0018_amount_currency")
+	var amounts []int
+	err = ts.db.NewSelect().Table("auction_items").Column("fair_market_value").Order("id").Scan(ts.ctx, &amounts)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]int{150, 0}, amounts)
+	var amount int
+	ts.Require().NoError(ts.db.NewSelect().Table("pledges").Column("amount").Scan(ts.ctx, &amount))
+	ts.Require().EqualValues(500, amount)
+
+	_, err = ts.migrator.Up(ts.ctx)
+	ts.Require().NoError(err)
+	retrieved, err := itemClient.GetByID(ts.ctx, valued.ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(usd(15000), retrieved.FairMarketValue)
+	retrieved, err = itemClient.GetByID(ts.ctx, unvalued.ID)
+	ts.Require().NoError(err)
+	ts.Require().Zero(retrieved.FairMarketValue)
+	pledges, err := donationClient.GetPledges(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(pledges, 1)
+	ts.Require().EqualValues(usd(50000), pledges[0].Amount)
+	ts.Require().EqualValues(usd(50000), pledges[0].Level.Amount)
+	tickets, err := raffleClient.GetTickets(ts.ctx, raffle.ID)
+	ts.Require().NoError(err)
+	ts.Require().Len(tickets, 1)
+	ts.Require().EqualValues(usd(500), tickets[0].Price)
+	retrievedRaffle, err := raffleClient.GetByID(ts.ctx, raffle.ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(usd(500), retrievedRaffle.TicketPrice)
+}
+
+// rollBackTo rolls back migrations up to and including the one with the name.
+func (ts *migratorTestSuite) rollBackTo(name string) {
+	for {
+		rolledBack, err := ts.migrator.Down(ts.ctx)
+		ts.Require().NoError(err)
+		ts.Require().NotEmpty(rolledBack, "%s was never applied", name)
+		if rolledBack == name {
+			return
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/MMarsolek/AuctionHouse/model"
-	"github.com/pkg/errors"
 	"github.com/uptrace/bun"
 )
 
@@ -14,6 +13,9 @@ type baseDBModel struct {
 	ID        uint64    `bun:",pk"`
 	CreatedAt time.Time `bun:",nullzero,notnull"`
 	UpdatedAt time.Time `bun:",nullzero,notnull"`
+
+	// Version starts at 1 and is incremented by every update.
+	Version uint64 `bun:",notnull"`
 }
 
 func (model *baseDBModel) updateTime() {
@@ -24,12 +26,17 @@ func (model *baseDBModel) updateCreateTime() {
 	model.CreatedAt = time.Now().UTC()
 }
 
+func (model *baseDBModel) initVersion() {
+	model.Version = 1
+}
+
 var _ bun.BeforeAppendModelHook = (*baseDBModel)(nil)
 
 func (*baseDBModel) BeforeAppendModel(ctx context.Context, query bun.Query) error {
 	type timeUpdater interface {
 		updateTime()
 		updateCreateTime()
+		initVersion()
 	}
 
 	updater, ok := query.GetModel().Value().(timeUpdater)
@@ -40,6 +47,7 @@ func (*baseDBModel) BeforeAppendModel(ctx context.Context, query bun.Query) erro
 	updater.updateTime()
 	if _, ok = query.(*bun.InsertQuery); ok {
 		updater.updateCreateTime()
+		updater.initVersion()
 	}
 
 	return nil
@@ -52,12 +60,7 @@ type User struct {
 	DisplayName    string                `bun:",notnull"`
 	HashedPassword string                `bun:",notnull"`
 	Permission     model.PermissionLevel `bun:",notnull"`
-}
-
-var _ bun.AfterCreateTableHook = (*User)(nil)
-
-func (u *User) AfterCreateTable(ctx context.Context, query *bun.CreateTableQuery) error {
-	return createIndex(ctx, query, (*User)(nil), "username_idx", "username")
+	DeletedAt      time.Time             `bun:",soft_delete,nullzero"`
 }
 
 // ToModel transforms the User into a model.User.
@@ -67,6 +70,7 @@ func (u *User) ToModel() *model.User {
 		DisplayName:    u.DisplayName,
 		HashedPassword: u.HashedPassword,
 		Permission:     u.Permission,
+		DeletedAt:      u.DeletedAt,
 	}
 }
 
@@ -83,34 +87,76 @@ func UserToDBModel(user *model.User) *User {
 // AuctionItem represents the model.AuctionItem as it exists in storage.
 type AuctionItem struct {
 	baseDBModel
-	NameID      string `bun:"name_id,notnull,unique"`
-	DisplayName string `bun:",notnull"`
-	ImageRef    string `bun:",notnull"`
-	Description string `bun:",notnull"`
-}
+	NameID      string    `bun:"name_id,notnull,unique"`
+	DisplayName string    `bun:",notnull"`
+	ImageRef    string    `bun:",notnull"`
+	Description string    `bun:",notnull"`
+	DeletedAt   time.Time `bun:",soft_delete,nullzero"`
+
+	// CategoryID is 0 when the item is not in a category.
+	CategoryID    uint64 `bun:",nullzero"`
+	DonorName     string `bun:",notnull"`
+	DonorBusiness string `bun:",notnull"`
+	Quantity      int    `bun:",notnull"`
+
+	// FairMarketValueCurrency is empty when the donor did not state a value.
+	FairMarketValue         int64  `bun:",notnull"`
+	FairMarketValueCurrency string `bun:",notnull"`
+
+	// LotID is 0 when the item is sold on its own.
+	LotID uint64 `bun:",nullzero"`
 
-var _ bun.AfterCreateTableHook = (*AuctionItem)(nil)
+	// ClosesAt is NULL when bidding on the item stays open until the auction ends.
+	ClosesAt time.Time `bun:",nullzero"`
 
-func (ai *AuctionItem) AfterCreateTable(ctx context.Context, query *bun.CreateTableQuery) error {
-	return createIndex(ctx, query, (*AuctionItem)(nil), "name_id_idx", "name_id")
+	// Category and Tags are not columns of the table. They are loaded by the client after the item is retrieved.
+	Category string   `bun:"-"`
+	Tags     []string `bun:"-"`
+
+	// SortValue is only filled in when listing items ordered by one of their bid totals.
+	SortValue int `bun:"sort_value,scanonly"`
 }
 
 // ToModel transforms the AuctionItem into a model.AuctionItem.
 func (ai *AuctionItem) ToModel() *model.AuctionItem {
 	return &model.AuctionItem{
-		Name:        ai.DisplayName,
-		ImageRef:    ai.ImageRef,
-		Description: ai.Description,
+		ID:              ai.ID,
+		Name:            ai.DisplayName,
+		ImageRef:        ai.ImageRef,
+		Description:     ai.Description,
+		Category:        ai.Category,
+		Tags:            ai.Tags,
+		DonorName:       ai.DonorName,
+		DonorBusiness:   ai.DonorBusiness,
+		FairMarketValue: model.Money{Amount: ai.FairMarketValue, Currency: ai.FairMarketValueCurrency},
+		Quantity:        ai.Quantity,
+		LotID:           ai.LotID,
+		ClosesAt:        ai.ClosesAt,
+		DeletedAt:       ai.DeletedAt,
+		Version:         ai.Version,
 	}
 }
 
-// AuctionItemToDBModel transforms the model.AuctionItem into an AuctionItem.
+// AuctionItemToDBModel transforms the model.AuctionItem into an AuctionItem. The CategoryID is left for the client to
+// look up. An item without a quantity is won by a single bidder.
 func AuctionItemToDBModel(auctionItem *model.AuctionItem) *AuctionItem {
+	quantity := auctionItem.Quantity
+	if quantity < 1 {
+		quantity = 1
+	}
 	return &AuctionItem{
-		NameID:      getAuctionItemNameID(auctionItem.Name),
-		DisplayName: auctionItem.Name,
-		ImageRef:    auctionItem.ImageRef,
-		Description: auctionItem.Description,
+		NameID:                  getAuctionItemNameID(auctionItem.Name),
+		DisplayName:             auctionItem.Name,
+		ImageRef:                auctionItem.ImageRef,
+		Description:             auctionItem.Description,
+		DonorName:               auctionItem.DonorName,
+		DonorBusiness:           auctionItem.DonorBusiness,
+		FairMarketValue:         auctionItem.FairMarketValue.Amount,
+		FairMarketValueCurrency: auctionItem.FairMarketValue.Currency,
+		Quantity:                quantity,
+		ClosesAt:                auctionItem.ClosesAt,
+		Category:                auctionItem.Category,
+		Tags:                    auctionItem.Tags,
 	}
 }
 
@@ -121,7 +167,8 @@ func getAuctionItemNameID(name string) string {
 // AuctionBid represents the model.AuctionBid as it exists in storage.
 type AuctionBid struct {
 	baseDBModel
-	BidAmount int          `bun:",notnull"`
+	BidAmount int64        `bun:",notnull"`
+	Currency  string       `bun:",notnull"`
 	Bidder    *User        `bun:"rel:has-one,join:bidder_id=id"`
 	Item      *AuctionItem `bun:"rel:has-one,join:item_id=id"`
 
@@ -132,24 +179,318 @@ type AuctionBid struct {
 // ToModel transforms the AuctionBid into a model.AuctionBid.
 func (ab *AuctionBid) ToModel() *model.AuctionBid {
 	return &model.AuctionBid{
-		BidAmount: ab.BidAmount,
+		BidAmount: model.Money{Amount: ab.BidAmount, Currency: ab.Currency},
 		Bidder:    ab.Bidder.ToModel(),
 		Item:      ab.Item.ToModel(),
 	}
 }
 
-func createIndex(ctx context.Context, query *bun.CreateTableQuery, model interface{}, indexName string, columnName string) error {
-	_, err := query.DB().
-		NewCreateIndex().
-		Model(model).
-		Index(indexName).
-		Column(columnName).
-		IfNotExists().
-		Exec(ctx)
+// ItemImage represents the model.ItemImage as it exists in storage.
+type ItemImage struct {
+	baseDBModel
+	ItemID       uint64 `bun:",notnull"`
+	Position     int    `bun:",notnull"`
+	Key          string `bun:",notnull"`
+	ThumbnailKey string `bun:",notnull"`
+	ContentType  string `bun:",notnull"`
+}
+
+// ToModel transforms the ItemImage into a model.ItemImage.
+func (ii *ItemImage) ToModel() *model.ItemImage {
+	return &model.ItemImage{
+		ID:           ii.ID,
+		ItemID:       ii.ItemID,
+		Position:     ii.Position,
+		Key:          ii.Key,
+		ThumbnailKey: ii.ThumbnailKey,
+		ContentType:  ii.ContentType,
+	}
+}
+
+// ItemImageToDBModel transforms the model.ItemImage into an ItemImage.
+func ItemImageToDBModel(image *model.ItemImage) *ItemImage {
+	return &ItemImage{
+		ItemID:       image.ItemID,
+		Position:     image.Position,
+		Key:          image.Key,
+		ThumbnailKey: image.ThumbnailKey,
+		ContentType:  image.ContentType,
+	}
+}
 
-	if err != nil {
-		return errors.Wrapf(err, "unable to create index '%s' on table", indexName)
+// Category represents the model.Category as it exists in storage.
+type Category struct {
+	baseDBModel
+	NameID      string `bun:"name_id,notnull,unique"`
+	DisplayName string `bun:",notnull"`
+}
+
+// ToModel transforms the Category into a model.Category.
+func (c *Category) ToModel() *model.Category {
+	return &model.Category{
+		ID:   c.ID,
+		Name: c.DisplayName,
 	}
+}
 
-	return nil
+// CategoryToDBModel transforms the model.Category into a Category.
+func CategoryToDBModel(category *model.Category) *Category {
+	return &Category{
+		NameID:      getCategoryNameID(category.Name),
+		DisplayName: category.Name,
+	}
+}
+
+func getCategoryNameID(name string) string {
+	return strings.ToLower(name)
+}
+
+// Lot represents the model.Lot as it exists in storage. The items of the lot refer to it by their LotID.
+type Lot struct {
+	baseDBModel
+	NameID      string `bun:"name_id,notnull,unique"`
+	DisplayName string `bun:",notnull"`
+}
+
+// ToModel transforms the Lot into a model.Lot along with the items.
+func (l *Lot) ToModel(items []*AuctionItem) *model.Lot {
+	lot := &model.Lot{
+		ID:    l.ID,
+		Name:  l.DisplayName,
+		Items: make([]*model.AuctionItem, len(items)),
+	}
+	for i, item := range items {
+		lot.Items[i] = item.ToModel()
+	}
+	return lot
+}
+
+// LotToDBModel transforms the model.Lot into a Lot. The items are left for the client to move into the lot.
+func LotToDBModel(lot *model.Lot) *Lot {
+	return &Lot{
+		NameID:      getLotNameID(lot.Name),
+		DisplayName: lot.Name,
+	}
+}
+
+func getLotNameID(name string) string {
+	return strings.ToLower(name)
+}
+
+// AppealLevel represents the model.AppealLevel as it exists in storage.
+type AppealLevel struct {
+	baseDBModel
+	NameID      string `bun:"name_id,notnull,unique"`
+	DisplayName string `bun:",notnull"`
+	Amount      int64  `bun:",notnull"`
+	Currency    string `bun:",notnull"`
+}
+
+// ToModel transforms the AppealLevel into a model.AppealLevel.
+func (al *AppealLevel) ToModel() *model.AppealLevel {
+	return &model.AppealLevel{
+		ID:     al.ID,
+		Name:   al.DisplayName,
+		Amount: model.Money{Amount: al.Amount, Currency: al.Currency},
+	}
+}
+
+// AppealLevelToDBModel transforms the model.AppealLevel into an AppealLevel.
+func AppealLevelToDBModel(level *model.AppealLevel) *AppealLevel {
+	return &AppealLevel{
+		NameID:      getAppealLevelNameID(level.Name),
+		DisplayName: level.Name,
+		Amount:      level.Amount.Amount,
+		Currency:    level.Amount.Currency,
+	}
+}
+
+func getAppealLevelNameID(name string) string {
+	return strings.ToLower(name)
+}
+
+// Pledge represents the model.Pledge as it exists in storage.
+type Pledge struct {
+	baseDBModel
+	Amount     int64        `bun:",notnull"`
+	Currency   string       `bun:",notnull"`
+	RecordedBy string       `bun:",notnull"`
+	Level      *AppealLevel `bun:"rel:has-one,join:level_id=id"`
+	Bidder     *User        `bun:"rel:has-one,join:bidder_id=id"`
+
+	LevelID  uint64 `bun:",notnull"`
+	BidderID uint64 `bun:",notnull"`
+}
+
+// ToModel transforms the Pledge into a model.Pledge.
+func (p *Pledge) ToModel() *model.Pledge {
+	return &model.Pledge{
+		ID:         p.ID,
+		Level:      p.Level.ToModel(),
+		Bidder:     p.Bidder.ToModel(),
+		Amount:     model.Money{Amount: p.Amount, Currency: p.Currency},
+		RecordedBy: p.RecordedBy,
+	}
+}
+
+// Raffle represents the model.Raffle as it exists in storage. The drawing columns are empty until the raffle is drawn.
+type Raffle struct {
+	baseDBModel
+	NameID         string    `bun:"name_id,notnull,unique"`
+	DisplayName    string    `bun:",notnull"`
+	Description    string    `bun:",notnull"`
+	TicketPrice    int64     `bun:",notnull"`
+	Currency       string    `bun:",notnull"`
+	Prizes         int       `bun:",notnull"`
+	DrawingSeed    string    `bun:",nullzero"`
+	DrawingTickets []string  `bun:",nullzero"`
+	DrawingWinners []int     `bun:",nullzero"`
+	DrawnAt        time.Time `bun:",nullzero"`
+}
+
+// ToModel transforms the Raffle into a model.Raffle along with its drawing.
+func (r *Raffle) ToModel() *model.Raffle {
+	raffle := &model.Raffle{
+		ID:          r.ID,
+		Name:        r.DisplayName,
+		Description: r.Description,
+		TicketPrice: model.Money{Amount: r.TicketPrice, Currency: r.Currency},
+		Prizes:      r.Prizes,
+	}
+	if !r.DrawnAt.IsZero() {
+		raffle.Drawing = &model.RaffleDrawing{
+			Seed:    r.DrawingSeed,
+			Tickets: r.DrawingTickets,
+			Winners: r.DrawingWinners,
+			DrawnAt: r.DrawnAt,
+		}
+	}
+	return raffle
+}
+
+// RaffleToDBModel transforms the model.Raffle into a Raffle without its drawing.
+func RaffleToDBModel(raffle *model.Raffle) *Raffle {
+	return &Raffle{
+		NameID:      getRaffleNameID(raffle.Name),
+		DisplayName: raffle.Name,
+		Description: raffle.Description,
+		TicketPrice: raffle.TicketPrice.Amount,
+		Currency:    raffle.TicketPrice.Currency,
+		Prizes:      raffle.Prizes,
+	}
+}
+
+func getRaffleNameID(name string) string {
+	return strings.ToLower(name)
+}
+
+// TicketPurchase represents the model.TicketPurchase as it exists in storage.
+type TicketPurchase struct {
+	baseDBModel
+	Quantity   int    `bun:",notnull"`
+	Price      int64  `bun:",notnull"`
+	Currency   string `bun:",notnull"`
+	RecordedBy string `bun:",notnull"`
+	Buyer      *User  `bun:"rel:has-one,join:buyer_id=id"`
+
+	RaffleID uint64 `bun:",notnull"`
+	BuyerID  uint64 `bun:",notnull"`
+}
+
+// ToModel transforms the TicketPurchase into a model.TicketPurchase.
+func (tp *TicketPurchase) ToModel() *model.TicketPurchase {
+	return &model.TicketPurchase{
+		ID:         tp.ID,
+		RaffleID:   tp.RaffleID,
+		Buyer:      tp.Buyer.ToModel(),
+		Quantity:   tp.Quantity,
+		Price:      model.Money{Amount: tp.Price, Currency: tp.Currency},
+		RecordedBy: tp.RecordedBy,
+	}
+}
+
+// SpendingLimit represents the model.SpendingLimit as it exists in storage.
+type SpendingLimit struct {
+	baseDBModel
+	Amount   int64  `bun:",notnull"`
+	Currency string `bun:",notnull"`
+
+	// UserID is 0 for the limit of the event.
+	UserID uint64 `bun:",nullzero,unique"`
+
+	// Username is not a column of the table. It is selected along with the limit and is empty for the limit of the
+	// event.
+	Username string `bun:"username,scanonly"`
+}
+
+// ToModel transforms the SpendingLimit into a model.SpendingLimit.
+func (sl *SpendingLimit) ToModel() *model.SpendingLimit {
+	return &model.SpendingLimit{
+		Username: sl.Username,
+		Amount:   model.Money{Amount: sl.Amount, Currency: sl.Currency},
+	}
+}
+
+// EventSetting is a setting of the event that is kept in storage so it cannot change between restarts, such as the
+// currency every bid is in.
+type EventSetting struct {
+	bun.BaseModel `bun:"event_settings"`
+
+	Name  string `bun:",pk"`
+	Value string `bun:",notnull"`
+}
+
+// ItemClosing is the closing time of an item that was already announced, so each closing is only announced once by
+// every instance sharing the database.
+type ItemClosing struct {
+	ItemID   uint64    `bun:",pk"`
+	ClosesAt time.Time `bun:",notnull"`
+}
+
+// OutbidNotice represents the model.OutbidNotice as it exists in storage. Both bids are in the same currency.
+type OutbidNotice struct {
+	baseDBModel
+	UserID     uint64 `bun:",notnull"`
+	ItemID     uint64 `bun:",notnull"`
+	ItemName   string `bun:",notnull"`
+	LotID      uint64 `bun:",nullzero"`
+	LotName    string `bun:",nullzero"`
+	CurrentBid int64  `bun:",notnull"`
+	MinimumBid int64  `bun:",notnull"`
+	Currency   string `bun:",notnull"`
+
+	// Username is not a column of the table. It is selected along with the notice.
+	Username string `bun:"username,scanonly"`
+}
+
+// ToModel transforms the OutbidNotice into a model.OutbidNotice.
+func (on *OutbidNotice) ToModel() *model.OutbidNotice {
+	return &model.OutbidNotice{
+		ID:         on.ID,
+		Username:   on.Username,
+		ItemID:     on.ItemID,
+		ItemName:   on.ItemName,
+		LotID:      on.LotID,
+		LotName:    on.LotName,
+		CurrentBid: model.Money{Amount: on.CurrentBid, Currency: on.Currency},
+		MinimumBid: model.Money{Amount: on.MinimumBid, Currency: on.Currency},
+	}
+}
+
+// Tag is a label that can be given to any number of items. Tags are shared by every item with the same label, ignoring
+// case, and keep the display name they were first given.
+type Tag struct {
+	baseDBModel
+	NameID      string `bun:"name_id,notnull,unique"`
+	DisplayName string `bun:",notnull"`
+}
+
+func getTagNameID(name string) string {
+	return strings.ToLower(name)
+}
+
+// ItemTag links an AuctionItem to one of its Tags.
+type ItemTag struct {
+	ItemID uint64 `bun:",pk"`
+	TagID  uint64 `bun:",pk"`
 }
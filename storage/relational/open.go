@@ -0,0 +1,121 @@
+package relational
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// Option changes how the connection to the database is made.
+type Option func(options *openOptions)
+
+type openOptions struct {
+	busyTimeout     time.Duration
+	wal             bool
+	maxOpenConns    *int
+	maxIdleConns    *int
+	connMaxLifetime time.Duration
+}
+
+// WithBusyTimeout makes SQLite wait up to the timeout for a lock held by another connection instead of failing
+// immediately. It has no effect on PostgreSQL.
+func WithBusyTimeout(timeout time.Duration) Option {
+	return func(options *openOptions) {
+		options.busyTimeout = timeout
+	}
+}
+
+// WithWAL switches SQLite to write-ahead logging so readers do not block the writer. It has no effect on PostgreSQL.
+func WithWAL() Option {
+	return func(options *openOptions) {
+		options.wal = true
+	}
+}
+
+// WithMaxOpenConns limits the number of open connections. Zero means there is no limit.
+func WithMaxOpenConns(count int) Option {
+	return func(options *openOptions) {
+		options.maxOpenConns = &count
+	}
+}
+
+// WithMaxIdleConns limits the number of connections kept open while idle.
+func WithMaxIdleConns(count int) Option {
+	return func(options *openOptions) {
+		options.maxIdleConns = &count
+	}
+}
+
+// WithConnMaxLifetime closes connections once they have been open for the duration. Zero means connections are reused
+// forever.
+func WithConnMaxLifetime(lifetime time.Duration) Option {
+	return func(options *openOptions) {
+		options.connMaxLifetime = lifetime
+	}
+}
+
+// Open connects to the database at the location. Locations starting with postgres:// or postgresql:// connect to
+// PostgreSQL, locations starting with file: are SQLite data source names and anything else is the path of a SQLite
+// file. Foreign keys are always enforced by SQLite since the schema depends on them.
+func Open(location string, opts ...Option) (*bun.DB, error) {
+	var options openOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var db *bun.DB
+	if isPostgresDSN(location) {
+		rawDB := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(location)))
+		db = bun.NewDB(rawDB, pgdialect.New())
+	} else {
+		rawDB, err := sql.Open("sqlite", sqliteDSN(location, &options))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to open sqlite database")
+		}
+		db = bun.NewDB(rawDB, sqlitedialect.New())
+	}
+
+	if options.maxOpenConns != nil {
+		db.SetMaxOpenConns(*options.maxOpenConns)
+	}
+	if options.maxIdleConns != nil {
+		db.SetMaxIdleConns(*options.maxIdleConns)
+	}
+	db.SetConnMaxLifetime(options.connMaxLifetime)
+	return db, nil
+}
+
+// sqliteDSN turns the location into a DSN with the pragmas needed by the options. The pragmas are run in order for
+// every new connection so the busy timeout comes first to let the switch to WAL wait for other connections.
+func sqliteDSN(location string, options *openOptions) string {
+	dsn := location
+	if !strings.HasPrefix(dsn, "file:") {
+		dsn = "file:" + strings.NewReplacer("%", "%25", "?", "%3F", "#", "%23").Replace(location)
+	}
+
+	var pragmas []string
+	if options.busyTimeout > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("busy_timeout%%3D%d", options.busyTimeout.Milliseconds()))
+	}
+	pragmas = append(pragmas, "foreign_keys%3Dtrue")
+	if options.wal {
+		pragmas = append(pragmas, "journal_mode%3DWAL")
+	}
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%s_pragma=%s", dsn, separator, strings.Join(pragmas, "&_pragma="))
+}
+
+func isPostgresDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://")
+}
@@ -0,0 +1,62 @@
+package relational
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+func TestOpenAppliesSQLiteOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "biddr.db")
+	db, err := Open(path, WithBusyTimeout(3*time.Second), WithWAL(), WithMaxOpenConns(4))
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.EqualValues(t, "wal", queryPragma(t, db, "journal_mode"))
+	require.EqualValues(t, "3000", queryPragma(t, db, "busy_timeout"))
+	require.EqualValues(t, "1", queryPragma(t, db, "foreign_keys"))
+	require.EqualValues(t, 4, db.Stats().MaxOpenConnections)
+}
+
+func TestOpenUsesRollbackJournalWithoutWAL(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "biddr.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.EqualValues(t, "delete", queryPragma(t, db, "journal_mode"))
+	require.EqualValues(t, "1", queryPragma(t, db, "foreign_keys"))
+}
+
+func TestOpenTreatsLocationWithoutFilePrefixAsPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "odd?name#.db")
+	db, err := Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = NewMigrator(db).Up(context.Background())
+	require.NoError(t, err)
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+}
+
+func TestOpenKeepsExistingSQLiteDSNParameters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "biddr.db")
+	db, err := Open(fmt.Sprintf("file:%s?_pragma=busy_timeout%%3D1234", path))
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.EqualValues(t, "1234", queryPragma(t, db, "busy_timeout"))
+	require.EqualValues(t, "1", queryPragma(t, db, "foreign_keys"))
+}
+
+func queryPragma(t *testing.T, db *bun.DB, pragma string) string {
+	var value string
+	require.NoError(t, db.QueryRowContext(context.Background(), fmt.Sprintf("PRAGMA %s", pragma)).Scan(&value))
+	return value
+}
@@ -0,0 +1,102 @@
+package relational
+
+import (
+	"context"
+	"sort"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type outbidNoticeClient struct {
+	baseClient
+}
+
+// NewOutbidNoticeClient returns an object that can perform various operations on model.OutbidNotices.
+func NewOutbidNoticeClient(db bun.IDB) storage.OutbidNoticeClient {
+	return &outbidNoticeClient{
+		baseClient: baseClient{
+			db: db,
+		},
+	}
+}
+
+// Add stores the model.OutbidNotice in place of any earlier notice for the same user about the same item, or the same
+// lot when the item is sold in a lot. The ID of the notice is set once it is stored. This will return
+// storage.ErrEntityNotFound if the user or item does not exist.
+func (oc *outbidNoticeClient) Add(ctx context.Context, notice *model.OutbidNotice) error {
+	var user User
+	if err := oc.baseClient.get(ctx, &user, "username", notice.Username); err != nil {
+		return errors.Wrapf(err, "unable to add outbid notice of '%s'", notice.Username)
+	}
+	var item AuctionItem
+	if err := oc.baseClient.get(ctx, &item, "id", notice.ItemID); err != nil {
+		return errors.Wrapf(err, "unable to add outbid notice of '%s'", notice.Username)
+	}
+
+	query := oc.db.NewDelete().
+		Model((*OutbidNotice)(nil)).
+		Where("user_id = ?", user.ID)
+	if notice.LotID != 0 {
+		query = query.Where("lot_id = ?", notice.LotID)
+	} else {
+		query = query.Where("lot_id IS NULL").Where("item_id = ?", notice.ItemID)
+	}
+	if _, err := query.Exec(ctx); err != nil {
+		return errors.Wrapf(err, "unable to replace outbid notice of '%s'", notice.Username)
+	}
+
+	dbModel := &OutbidNotice{
+		UserID:     user.ID,
+		ItemID:     notice.ItemID,
+		ItemName:   notice.ItemName,
+		LotID:      notice.LotID,
+		LotName:    notice.LotName,
+		CurrentBid: notice.CurrentBid.Amount,
+		MinimumBid: notice.MinimumBid.Amount,
+		Currency:   notice.CurrentBid.Currency,
+	}
+	if err := oc.baseClient.create(ctx, dbModel); err != nil {
+		return errors.Wrapf(err, "unable to add outbid notice of '%s'", notice.Username)
+	}
+	notice.ID = dbModel.ID
+	return nil
+}
+
+// Remove deletes the model.OutbidNotice with the ID. Nothing is removed if the notice was already taken or replaced.
+func (oc *outbidNoticeClient) Remove(ctx context.Context, id uint64) error {
+	_, err := oc.db.NewDelete().
+		Model((*OutbidNotice)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to remove outbid notice %d", id)
+	}
+	return nil
+}
+
+// Take retrieves and removes every model.OutbidNotice of the user in the order they were added. The notices are
+// removed and returned by a single statement so each of them is only taken by one caller.
+func (oc *outbidNoticeClient) Take(ctx context.Context, username string) ([]*model.OutbidNotice, error) {
+	var dbModels []*OutbidNotice
+	_, err := oc.db.NewDelete().
+		Model(&dbModels).
+		Where("user_id = (SELECT id FROM users WHERE username = ?)", username).
+		Returning("*").
+		Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to take outbid notices of '%s'", username)
+	}
+
+	sort.Slice(dbModels, func(i, j int) bool {
+		return dbModels[i].ID < dbModels[j].ID
+	})
+	result := make([]*model.OutbidNotice, len(dbModels))
+	for i, dbModel := range dbModels {
+		dbModel.Username = username
+		result[i] = dbModel.ToModel()
+	}
+	return result, nil
+}
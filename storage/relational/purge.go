@@ -0,0 +1,145 @@
+package relational
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// PurgeResult counts the rows permanently removed by Purge.
+type PurgeResult struct {
+	Users   int64
+	Items   int64
+	Bids    int64
+	Images  int64
+	Pledges int64
+	Tickets int64
+}
+
+// Purge permanently removes the users and items that were deleted before the time along with every bid and outbid
+// notice of the items and the users, every pledge, ticket purchase and spending limit of the users and every image and
+// tag of the items.
+// Purged entities cannot be restored. Raffles that were already drawn keep the usernames of their ticket buyers for auditing.
+func Purge(ctx context.Context, db *bun.DB, deletedBefore time.Time) (*PurgeResult, error) {
+	result := &PurgeResult{}
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		purgedItems := tx.NewSelect().
+			Model((*AuctionItem)(nil)).
+			Column("id").
+			WhereDeleted().
+			Where("deleted_at < ?", deletedBefore)
+		purgedUsers := tx.NewSelect().
+			Model((*User)(nil)).
+			Column("id").
+			WhereDeleted().
+			Where("deleted_at < ?", deletedBefore)
+
+		// Bids, pledges, ticket purchases, spending limits, outbid notices, images, tags and closings are removed first so
+		// nothing relies on the foreign keys cascading.
+		var err error
+		result.Bids, err = rowsAffected(tx.NewDelete().
+			Model((*AuctionBid)(nil)).
+			Where("item_id IN (?)", purgedItems).
+			WhereOr("bidder_id IN (?)", purgedUsers).
+			Exec(ctx))
+		if err != nil {
+			return errors.Wrap(err, "unable to purge bids")
+		}
+
+		result.Pledges, err = rowsAffected(tx.NewDelete().
+			Model((*Pledge)(nil)).
+			Where("bidder_id IN (?)", purgedUsers).
+			Exec(ctx))
+		if err != nil {
+			return errors.Wrap(err, "unable to purge pledges")
+		}
+
+		result.Tickets, err = rowsAffected(tx.NewDelete().
+			Model((*TicketPurchase)(nil)).
+			Where("buyer_id IN (?)", purgedUsers).
+			Exec(ctx))
+		if err != nil {
+			return errors.Wrap(err, "unable to purge ticket purchases")
+		}
+
+		_, err = tx.NewDelete().
+			Model((*SpendingLimit)(nil)).
+			Where("user_id IN (?)", purgedUsers).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to purge spending limits")
+		}
+
+		result.Images, err = rowsAffected(tx.NewDelete().
+			Model((*ItemImage)(nil)).
+			Where("item_id IN (?)", purgedItems).
+			Exec(ctx))
+		if err != nil {
+			return errors.Wrap(err, "unable to purge item images")
+		}
+
+		_, err = tx.NewDelete().
+			Model((*ItemTag)(nil)).
+			Where("item_id IN (?)", purgedItems).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to purge item tags")
+		}
+
+		_, err = tx.NewDelete().
+			Model((*OutbidNotice)(nil)).
+			Where("item_id IN (?)", purgedItems).
+			WhereOr("user_id IN (?)", purgedUsers).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to purge outbid notices")
+		}
+
+		_, err = tx.NewDelete().
+			Model((*ItemClosing)(nil)).
+			Where("item_id IN (?)", purgedItems).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to purge item closings")
+		}
+
+		result.Items, err = rowsAffected(tx.NewDelete().
+			Model((*AuctionItem)(nil)).
+			ForceDelete().
+			WhereDeleted().
+			Where("deleted_at < ?", deletedBefore).
+			Exec(ctx))
+		if err != nil {
+			return errors.Wrap(err, "unable to purge auction items")
+		}
+
+		result.Users, err = rowsAffected(tx.NewDelete().
+			Model((*User)(nil)).
+			ForceDelete().
+			WhereDeleted().
+			Where("deleted_at < ?", deletedBefore).
+			Exec(ctx))
+		if err != nil {
+			return errors.Wrap(err, "unable to purge users")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func rowsAffected(results sql.Result, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	affected, err := results.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to determine rows affected")
+	}
+	return affected, nil
+}
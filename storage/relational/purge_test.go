@@ -0,0 +1,137 @@
+package relational
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+)
+
+type purgeTestSuite struct {
+	suite.Suite
+
+	ctx        context.Context
+	db         *bun.DB
+	userClient storage.UserClient
+	itemClient storage.AuctionItemClient
+	bidClient  storage.AuctionBidClient
+}
+
+func (ts *purgeTestSuite) SetupTest() {
+	ts.ctx = context.Background()
+	db, err := Open(fmt.Sprintf("file:%s?_pragma=foreign_keys%%3Dtrue", filepath.Join(ts.T().TempDir(), "biddr.db")))
+	ts.Require().NoError(err)
+	_, err = NewMigrator(db).Up(ts.ctx)
+	ts.Require().NoError(err)
+
+	ts.db = db
+	ts.userClient = NewUserClient(db)
+	ts.itemClient = NewAuctionItemClient(db)
+	ts.bidClient = NewAuctionBidClient(db)
+}
+
+func (ts *purgeTestSuite) TearDownTest() {
+	ts.db.Close()
+}
+
+func TestPurge(t *testing.T) {
+	suite.Run(t, new(purgeTestSuite))
+}
+
+func (ts *purgeTestSuite) TestPurgeRemovesDeletedEntitiesAndTheirBids() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+	ts.placeBid(users[1], items[0], 20)
+	ts.placeBid(users[0], items[1], 30)
+	ts.placeBid(users[1], items[1], 40)
+	ts.Require().NoError(NewItemImageClient(ts.db).Add(ts.ctx, &model.ItemImage{ItemID: items[0].ID, Key: "image.png"}))
+	items[0].Tags = []string{"Golf"}
+	ts.Require().NoError(ts.itemClient.Update(ts.ctx, items[0]))
+	donationClient := NewDonationClient(ts.db)
+	level := &model.AppealLevel{Name: "Classroom", Amount: usd(50000)}
+	ts.Require().NoError(donationClient.CreateLevel(ts.ctx, level))
+	ts.Require().NoError(donationClient.CreatePledge(ts.ctx, &model.Pledge{Level: level, Bidder: users[1]}))
+	raffleClient := NewRaffleClient(ts.db)
+	raffle := &model.Raffle{Name: "Quilt", TicketPrice: usd(500), Prizes: 1}
+	ts.Require().NoError(raffleClient.Create(ts.ctx, raffle))
+	ts.Require().NoError(raffleClient.BuyTickets(ts.ctx, &model.TicketPurchase{RaffleID: raffle.ID, Buyer: users[1], Quantity: 3}))
+	limitClient := NewSpendingLimitClient(ts.db)
+	ts.Require().NoError(limitClient.Set(ts.ctx, &model.SpendingLimit{Username: users[1].Username, Amount: usd(100)}))
+	outbidClient := NewOutbidNoticeClient(ts.db)
+	notices := []*model.OutbidNotice{
+		{Username: users[0].Username, ItemID: items[0].ID, ItemName: items[0].Name, CurrentBid: usd(20)},
+		{Username: users[0].Username, ItemID: items[1].ID, ItemName: items[1].Name, CurrentBid: usd(40)},
+		{Username: users[1].Username, ItemID: items[1].ID, ItemName: items[1].Name, CurrentBid: usd(40)},
+	}
+	for _, notice := range notices {
+		ts.Require().NoError(outbidClient.Add(ts.ctx, notice))
+	}
+	ts.Require().NoError(ts.itemClient.Delete(ts.ctx, items[0].Name))
+	ts.Require().NoError(ts.userClient.Delete(ts.ctx, users[1].Username))
+
+	result, err := Purge(ts.ctx, ts.db, time.Now().Add(time.Minute))
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(&PurgeResult{Users: 1, Items: 1, Bids: 3, Images: 1, Pledges: 1, Tickets: 1}, result)
+
+	ts.Require().ErrorIs(ts.itemClient.Restore(ts.ctx, items[0].ID), storage.ErrEntityNotFound)
+	ts.Require().ErrorIs(ts.userClient.Restore(ts.ctx, users[1].Username), storage.ErrEntityNotFound)
+	limits, err := limitClient.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Empty(limits)
+	var remaining int
+	ts.Require().NoError(ts.db.NewSelect().Model((*OutbidNotice)(nil)).ColumnExpr("COUNT(*)").Scan(ts.ctx, &remaining))
+	ts.Require().EqualValues(1, remaining)
+
+	bids, err := ts.bidClient.GetAllWinningBids(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(bids, 1)
+	ts.Require().EqualValues(usd(30), bids[0].BidAmount)
+
+	// Purged names can be used again.
+	ts.Require().NoError(ts.itemClient.Create(ts.ctx, &model.AuctionItem{Name: items[0].Name}))
+}
+
+func (ts *purgeTestSuite) TestPurgeKeepsEntitiesDeletedAfterTheTime() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+	ts.Require().NoError(ts.itemClient.Delete(ts.ctx, items[0].Name))
+
+	result, err := Purge(ts.ctx, ts.db, time.Now().Add(-time.Hour))
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(&PurgeResult{}, result)
+
+	ts.Require().NoError(ts.itemClient.Restore(ts.ctx, items[0].ID))
+	bid, err := ts.bidClient.GetHighestBid(ts.ctx, items[0])
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(usd(10), bid.BidAmount)
+}
+
+func (ts *purgeTestSuite) placeBid(user *model.User, item *model.AuctionItem, amount int64) {
+	_, err := ts.bidClient.PlaceBid(ts.ctx, user, item, usd(amount))
+	ts.Require().NoError(err)
+}
+
+func (ts *purgeTestSuite) createTestAssets() ([]*model.User, []*model.AuctionItem) {
+	users := []*model.User{
+		{Username: "user1", HashedPassword: "1234", Permission: model.PermissionLevelBidder},
+		{Username: "user2", HashedPassword: "5678", Permission: model.PermissionLevelBidder},
+	}
+	for _, user := range users {
+		ts.Require().NoError(ts.userClient.Create(ts.ctx, user))
+	}
+
+	items := []*model.AuctionItem{
+		{Name: "item one"},
+		{Name: "item two"},
+	}
+	for _, item := range items {
+		ts.Require().NoError(ts.itemClient.Create(ts.ctx, item))
+	}
+	return users, items
+}
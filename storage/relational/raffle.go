@@ -0,0 +1,180 @@
+package relational
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type raffleClient struct {
+	baseClient
+}
+
+// NewRaffleClient returns an object that can perform various operations on model.Raffles and model.TicketPurchases.
+func NewRaffleClient(db bun.IDB) storage.RaffleClient {
+	return &raffleClient{
+		baseClient: baseClient{
+			db: db,
+		},
+	}
+}
+
+// GetAll retrieves every model.Raffle in storage in the order they were created.
+func (rc *raffleClient) GetAll(ctx context.Context) ([]*model.Raffle, error) {
+	var dbModels []*Raffle
+	err := rc.db.NewSelect().
+		Model(&dbModels).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get all raffles")
+	}
+
+	result := make([]*model.Raffle, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel()
+	}
+	return result, nil
+}
+
+// GetByID retrieves the model.Raffle with the ID. This will return storage.ErrEntityNotFound if the ID is not found in
+// storage.
+func (rc *raffleClient) GetByID(ctx context.Context, id uint64) (*model.Raffle, error) {
+	var dbModel Raffle
+	if err := rc.baseClient.get(ctx, &dbModel, "id", id); err != nil {
+		return nil, errors.Wrapf(err, "unable to get raffle %d", id)
+	}
+	return dbModel.ToModel(), nil
+}
+
+// Create adds a new model.Raffle to storage without a drawing. This will return storage.ErrEntityAlreadyExists if the
+// name is already found in storage, ignoring case.
+func (rc *raffleClient) Create(ctx context.Context, raffle *model.Raffle) error {
+	dbModel := RaffleToDBModel(raffle)
+	if err := rc.baseClient.create(ctx, dbModel); err != nil {
+		return errors.Wrapf(err, "unable to create raffle '%s'", raffle.Name)
+	}
+
+	*raffle = *dbModel.ToModel()
+	return nil
+}
+
+// GetTickets retrieves every model.TicketPurchase of the raffle in the order they were bought. Purchases by deleted
+// users are included. This will return storage.ErrEntityNotFound if the raffle does not exist.
+func (rc *raffleClient) GetTickets(ctx context.Context, raffleID uint64) ([]*model.TicketPurchase, error) {
+	if _, err := rc.GetByID(ctx, raffleID); err != nil {
+		return nil, errors.Wrapf(err, "unable to get tickets of raffle %d", raffleID)
+	}
+
+	var dbModels []*TicketPurchase
+	err := rc.db.NewSelect().
+		Model(&dbModels).
+		Relation("Buyer", withDeletedBidder).
+		Where("raffle_id = ?", raffleID).
+		Order("ticket_purchase.id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get tickets of raffle %d", raffleID)
+	}
+
+	result := make([]*model.TicketPurchase, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel()
+	}
+	return result, nil
+}
+
+// BuyTickets adds a new model.TicketPurchase by the buyer at the ticket price of the raffle. This will return
+// storage.ErrEntityNotFound if the buyer or the raffle does not exist and storage.ErrRaffleDrawn if the raffle was
+// already drawn.
+func (rc *raffleClient) BuyTickets(ctx context.Context, purchase *model.TicketPurchase) error {
+	raffle, err := rc.GetByID(ctx, purchase.RaffleID)
+	if err != nil {
+		return errors.Wrap(err, "unable to buy tickets")
+	}
+	if raffle.Drawing != nil {
+		return errors.Wrapf(storage.ErrRaffleDrawn, "unable to buy tickets for raffle %d", raffle.ID)
+	}
+
+	dbModel := &TicketPurchase{
+		Quantity:   purchase.Quantity,
+		Price:      raffle.TicketPrice.Amount,
+		Currency:   raffle.TicketPrice.Currency,
+		RecordedBy: purchase.RecordedBy,
+		RaffleID:   raffle.ID,
+	}
+	_, err = rc.db.NewInsert().
+		Model(dbModel).
+		Value("buyer_id", "(?)", rc.db.NewSelect().
+			Model((*User)(nil)).
+			Column("id").
+			Where("username = ?", purchase.Buyer.Username)).
+		Exec(ctx)
+	if err != nil {
+		// The buyer ID is NULL when the subquery does not find the buyer.
+		if isNotNullViolation(err) {
+			return errors.Wrapf(storage.ErrEntityNotFound, "unable to find buyer '%s'", purchase.Buyer.Username)
+		}
+		return errors.Wrap(err, "unable to buy tickets")
+	}
+
+	created, err := rc.getTicketPurchase(ctx, dbModel.ID)
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve ticket purchase")
+	}
+	*purchase = *created.ToModel()
+	return nil
+}
+
+// RecordDrawing stores the drawing of the model.Raffle with the ID. This will return storage.ErrEntityNotFound if the
+// raffle does not exist and storage.ErrRaffleDrawn if it was already drawn.
+func (rc *raffleClient) RecordDrawing(ctx context.Context, raffleID uint64, drawing *model.RaffleDrawing) error {
+	results, err := rc.db.NewUpdate().
+		Model(&Raffle{
+			DrawingSeed:    drawing.Seed,
+			DrawingTickets: drawing.Tickets,
+			DrawingWinners: drawing.Winners,
+			DrawnAt:        drawing.DrawnAt,
+		}).
+		Column("drawing_seed", "drawing_tickets", "drawing_winners", "drawn_at", "updated_at", "version").
+		Value("version", "version + 1").
+		Where("id = ?", raffleID).
+		Where("drawn_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to record drawing of raffle %d", raffleID)
+	}
+	affected, err := results.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to determine rows affected")
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	if _, err = rc.GetByID(ctx, raffleID); err != nil {
+		return errors.Wrapf(err, "unable to record drawing of raffle %d", raffleID)
+	}
+	return errors.Wrapf(storage.ErrRaffleDrawn, "unable to record drawing of raffle %d", raffleID)
+}
+
+// getTicketPurchase retrieves the ticket purchase with the ID along with its buyer.
+func (rc *raffleClient) getTicketPurchase(ctx context.Context, id uint64) (*TicketPurchase, error) {
+	var dbModel TicketPurchase
+	err := rc.db.NewSelect().
+		Model(&dbModel).
+		Relation("Buyer", withDeletedBidder).
+		Where("ticket_purchase.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to find ticket purchase %d", id)
+		}
+		return nil, errors.Wrapf(err, "unable to retrieve ticket purchase %d", id)
+	}
+	return &dbModel, nil
+}
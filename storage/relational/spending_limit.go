@@ -0,0 +1,137 @@
+package relational
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// limitUsernameExpr is the username of the user of each row of a spending_limits query, or NULL for the limit of the
+// event. Deleted users are included.
+const limitUsernameExpr = "(SELECT username FROM users WHERE users.id = spending_limit.user_id) AS username"
+
+type spendingLimitClient struct {
+	baseClient
+}
+
+// NewSpendingLimitClient returns an object that can perform various operations on model.SpendingLimits.
+func NewSpendingLimitClient(db bun.IDB) storage.SpendingLimitClient {
+	return &spendingLimitClient{
+		baseClient: baseClient{
+			db: db,
+		},
+	}
+}
+
+// GetAll retrieves the model.SpendingLimit of the event, when there is one, followed by the limits of the users ordered
+// by username. Limits of deleted users are included.
+func (lc *spendingLimitClient) GetAll(ctx context.Context) ([]*model.SpendingLimit, error) {
+	var dbModels []*SpendingLimit
+	err := lc.db.NewSelect().
+		Model(&dbModels).
+		ColumnExpr("spending_limit.*").
+		ColumnExpr(limitUsernameExpr).
+		OrderExpr("spending_limit.user_id IS NOT NULL, username").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get all spending limits")
+	}
+
+	result := make([]*model.SpendingLimit, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel()
+	}
+	return result, nil
+}
+
+// Get retrieves the model.SpendingLimit of the user, or of the event when the username is empty. This will return
+// storage.ErrEntityNotFound if there is no limit.
+func (lc *spendingLimitClient) Get(ctx context.Context, username string) (*model.SpendingLimit, error) {
+	var dbModel SpendingLimit
+	condition, args := limitCondition(username)
+	err := lc.db.NewSelect().
+		Model(&dbModel).
+		ColumnExpr("spending_limit.*").
+		ColumnExpr(limitUsernameExpr).
+		Where(condition, args...).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(storage.ErrEntityNotFound, "unable to find spending limit of '%s'", username)
+		}
+		return nil, errors.Wrapf(err, "unable to get spending limit of '%s'", username)
+	}
+	return dbModel.ToModel(), nil
+}
+
+// Set stores the model.SpendingLimit in place of the limit the user or the event already had. This will return
+// storage.ErrEntityNotFound if the user does not exist.
+func (lc *spendingLimitClient) Set(ctx context.Context, limit *model.SpendingLimit) error {
+	dbModel := &SpendingLimit{
+		Amount:   limit.Amount.Amount,
+		Currency: limit.Amount.Currency,
+	}
+	if limit.Username != "" {
+		var user User
+		if err := lc.baseClient.get(ctx, &user, "username", limit.Username); err != nil {
+			return errors.Wrapf(err, "unable to set spending limit of '%s'", limit.Username)
+		}
+		dbModel.UserID = user.ID
+	}
+
+	// Inserting first keeps concurrent requests from both creating a limit since the insert waits on the other one.
+	results, err := lc.db.NewInsert().
+		Model(dbModel).
+		On("CONFLICT DO NOTHING").
+		Returning("").
+		Exec(ctx)
+	inserted, err := rowsAffected(results, err)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create spending limit of '%s'", limit.Username)
+	}
+	if inserted > 0 {
+		return nil
+	}
+
+	condition, args := limitCondition(limit.Username)
+	results, err = lc.db.NewUpdate().
+		Model(dbModel).
+		Column("amount", "currency", "updated_at", "version").
+		Value("version", "version + 1").
+		Where(condition, args...).
+		Exec(ctx)
+	if _, err = rowsAffected(results, err); err != nil {
+		return errors.Wrapf(err, "unable to update spending limit of '%s'", limit.Username)
+	}
+	return nil
+}
+
+// Delete removes the model.SpendingLimit of the user, or of the event when the username is empty. This will return
+// storage.ErrEntityNotFound if there is no limit.
+func (lc *spendingLimitClient) Delete(ctx context.Context, username string) error {
+	condition, args := limitCondition(username)
+	affected, err := rowsAffected(lc.db.NewDelete().
+		Model((*SpendingLimit)(nil)).
+		Where(condition, args...).
+		Exec(ctx))
+	if err != nil {
+		return errors.Wrapf(err, "unable to delete spending limit of '%s'", username)
+	}
+	if affected <= 0 {
+		return errors.Wrapf(storage.ErrEntityNotFound, "unable to find spending limit of '%s'", username)
+	}
+	return nil
+}
+
+// limitCondition narrows a spending_limits query down to the limit of the user, including deleted users, or of the
+// event when the username is empty.
+func limitCondition(username string) (string, []interface{}) {
+	if username == "" {
+		return "user_id IS NULL", nil
+	}
+	return "user_id = (SELECT id FROM users WHERE username = ?)", []interface{}{username}
+}
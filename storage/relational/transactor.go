@@ -0,0 +1,48 @@
+package relational
+
+import (
+	"context"
+	"sync"
+
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+type transactor struct {
+	db *bun.DB
+
+	// sqliteLock serializes transactions on SQLite. A SQLite transaction reads from a snapshot and fails with
+	// SQLITE_BUSY instead of waiting if another transaction wrote since, and SQLite only allows one writer anyway.
+	sqliteLock sync.Mutex
+}
+
+// NewTransactor returns an object that runs operations across the clients within a database transaction.
+func NewTransactor(db *bun.DB) storage.Transactor {
+	return &transactor{
+		db: db,
+	}
+}
+
+// RunInTx runs fn with clients that use the same transaction. The transaction is rolled back if fn returns an error.
+func (t *transactor) RunInTx(ctx context.Context, fn func(ctx context.Context, clients *storage.Clients) error) error {
+	if t.db.Dialect().Name() == dialect.SQLite {
+		t.sqliteLock.Lock()
+		defer t.sqliteLock.Unlock()
+	}
+
+	return t.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(ctx, &storage.Clients{
+			Users:      NewUserClient(tx),
+			Items:      NewAuctionItemClient(tx),
+			Bids:       NewAuctionBidClient(tx),
+			Images:     NewItemImageClient(tx),
+			Categories: NewCategoryClient(tx),
+			Lots:       NewLotClient(tx),
+			Donations:  NewDonationClient(tx),
+			Raffles:    NewRaffleClient(tx),
+			Limits:     NewSpendingLimitClient(tx),
+			Outbids:    NewOutbidNoticeClient(tx),
+		})
+	})
+}
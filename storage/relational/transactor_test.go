@@ -0,0 +1,63 @@
+package relational
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunInTxDoesNotFailWhenConcurrentOnSQLite(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(filepath.Join(t.TempDir(), "biddr.db"), WithWAL(), WithBusyTimeout(5*time.Second))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = NewMigrator(db).Up(ctx)
+	require.NoError(t, err)
+
+	const bidders = 10
+	require.NoError(t, NewAuctionItemClient(db).Create(ctx, &model.AuctionItem{Name: "item"}))
+	for i := 0; i < bidders; i++ {
+		require.NoError(t, NewUserClient(db).Create(ctx, &model.User{
+			Username:   fmt.Sprintf("bidder%d", i),
+			Permission: model.PermissionLevelBidder,
+		}))
+	}
+
+	transactor := NewTransactor(db)
+	var wg sync.WaitGroup
+	for i := 0; i < bidders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for amount := 1; amount <= 10; amount++ {
+				err := transactor.RunInTx(ctx, func(ctx context.Context, clients *storage.Clients) error {
+					user, err := clients.Users.Get(ctx, fmt.Sprintf("bidder%d", i))
+					if err != nil {
+						return err
+					}
+
+					item, err := clients.Items.Get(ctx, "item")
+					if err != nil {
+						return err
+					}
+
+					_, err = clients.Bids.PlaceBid(ctx, user, item, usd(int64(amount*bidders+i)))
+					return err
+				})
+				if !errors.Is(err, storage.ErrBidTooLow) {
+					assert.NoError(t, err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
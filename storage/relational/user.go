@@ -33,20 +33,56 @@ func (uc *userClient) Get(ctx context.Context, username string) (*model.User, er
 	return user.ToModel(), nil
 }
 
-// Delete removes the model.User from storage by username. This will return storage.ErrEntityNotFound if the username
-// is not found in storage.
+// GetDeleted retrieves all deleted model.Users in the order they were deleted.
+func (uc *userClient) GetDeleted(ctx context.Context) ([]*model.User, error) {
+	var dbModels []*User
+	if err := uc.baseClient.getAllDeleted(ctx, &dbModels); err != nil {
+		return nil, errors.Wrapf(err, "unable to get deleted users")
+	}
+
+	result := make([]*model.User, len(dbModels))
+	for i, dbModel := range dbModels {
+		result[i] = dbModel.ToModel()
+	}
+
+	return result, nil
+}
+
+// Delete marks the model.User as deleted by username. Their bids are kept. This will return storage.ErrEntityNotFound
+// if the username is not found in storage.
 func (uc *userClient) Delete(ctx context.Context, username string) error {
-	err := uc.baseClient.delete(ctx, (*User)(nil), "username", username)
+	err := uc.baseClient.delete(ctx, &User{}, "username", username)
 	if err != nil {
 		return errors.Wrapf(err, "unable to delete user with username '%s'", username)
 	}
 	return nil
 }
 
+// Restore brings back the deleted model.User with the username. This will return storage.ErrEntityNotFound if there
+// is no deleted user with the username.
+func (uc *userClient) Restore(ctx context.Context, username string) error {
+	err := uc.baseClient.restore(ctx, &User{}, "username", username)
+	if err != nil {
+		return errors.Wrapf(err, "unable to restore user with username '%s'", username)
+	}
+	return nil
+}
+
 // Update changes the existing user by the non-zero fields of the provided model.User object.
 func (uc *userClient) Update(ctx context.Context, user *model.User) error {
 	dbModel := UserToDBModel(user)
-	err := uc.baseClient.update(ctx, dbModel, "username", user.Username)
+	var columns []string
+	if user.DisplayName != "" {
+		columns = append(columns, "display_name")
+	}
+	if user.HashedPassword != "" {
+		columns = append(columns, "hashed_password")
+	}
+	if user.Permission != "" {
+		columns = append(columns, "permission")
+	}
+
+	err := uc.baseClient.update(ctx, dbModel, "username", user.Username, columns...)
 	if err != nil {
 		return errors.Wrapf(err, "unable to update user %s", user.Username)
 	}
@@ -2,33 +2,33 @@ package relational
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 
 	"github.com/MMarsolek/AuctionHouse/model"
 	"github.com/MMarsolek/AuctionHouse/storage"
 	"github.com/stretchr/testify/suite"
 	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/dialect/sqlitedialect"
 )
 
 type userClientTestSuite struct {
 	suite.Suite
 
+	dsn    string
 	ctx    context.Context
 	db     bun.IDB
 	client *userClient
 }
 
 func (ts *userClientTestSuite) SetupSuite() {
-	rawDB, err := sql.Open("sqlite", "file::memory:?_pragma=cache%3Dshared&_pragma=foreign_keys%3Dtrue")
+	db, err := Open(ts.dsn)
 	ts.Require().NoError(err)
 
 	ts.ctx = context.Background()
-	ts.db = bun.NewDB(rawDB, sqlitedialect.New())
+	ts.db = db
 	ts.client = &userClient{baseClient{ts.db}}
 
-	ts.Require().NoError(CreateSchema(ts.ctx, ts.db))
+	_, err = NewMigrator(db).Up(ts.ctx)
+	ts.Require().NoError(err)
 }
 
 func (ts *userClientTestSuite) SetupTest() {
@@ -37,7 +37,9 @@ func (ts *userClientTestSuite) SetupTest() {
 }
 
 func TestUserClient(t *testing.T) {
-	suite.Run(t, new(userClientTestSuite))
+	runOnAllDatabases(t, func(t *testing.T, dsn string) {
+		suite.Run(t, &userClientTestSuite{dsn: dsn})
+	})
 }
 
 func (ts *userClientTestSuite) TestCreateDoesNotErrorOnValidInput() {
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/MMarsolek/AuctionHouse/model"
 )
@@ -11,6 +12,16 @@ var (
 	ErrEntityNotFound      = errors.New("entity not found")
 	ErrEntityAlreadyExists = errors.New("entity already exists")
 	ErrBidTooLow           = errors.New("bid is lower than current bid")
+	ErrVersionMismatch     = errors.New("entity was changed since it was retrieved")
+	ErrInvalidOrder        = errors.New("order does not match the existing entities")
+	ErrUnknownCategory     = errors.New("category does not exist")
+	ErrInvalidCursor       = errors.New("cursor does not continue the listing")
+	ErrItemInLot           = errors.New("item is sold in a lot")
+	ErrLotHasBids          = errors.New("lot has bids")
+	ErrLotTooSmall         = errors.New("lot must have at least two items")
+	ErrLevelHasPledges     = errors.New("appeal level has pledges")
+	ErrRaffleDrawn         = errors.New("raffle was already drawn")
+	ErrOverSpendingLimit   = errors.New("bid would exceed the spending limit of the bidder")
 )
 
 // UserClient defines how to store model.User objects.
@@ -20,9 +31,16 @@ type UserClient interface {
 	// Get retrieves the model from storage.
 	Get(ctx context.Context, username string) (*model.User, error)
 
-	// Delete does a hard remove from storage.
+	// GetDeleted retrieves every deleted model from storage in the order they were deleted.
+	GetDeleted(ctx context.Context) ([]*model.User, error)
+
+	// Delete hides the model from every other method until it is restored. The username stays taken and the bids of
+	// the user are kept.
 	Delete(ctx context.Context, username string) error
 
+	// Restore brings back a deleted model.
+	Restore(ctx context.Context, username string) error
+
 	// Update changes the non-zero fields in the supplied model.
 	Update(ctx context.Context, user *model.User) error
 
@@ -34,32 +52,301 @@ type UserClient interface {
 //go:generate mockery --name AuctionItemClient
 type AuctionItemClient interface {
 
-	// Get retrieves the model from storage.
+	// Get retrieves the model from storage by its name, ignoring case.
 	Get(ctx context.Context, name string) (*model.AuctionItem, error)
 
+	// GetByID retrieves the model from storage by its ID.
+	GetByID(ctx context.Context, id uint64) (*model.AuctionItem, error)
+
 	// GetAll retrieves all models from storage.
 	GetAll(ctx context.Context) ([]*model.AuctionItem, error)
 
-	// Delete does a hard remove from storage.
+	// GetMatching retrieves every model from storage that matches the filter in the order they were created.
+	GetMatching(ctx context.Context, filter ItemFilter) ([]*model.AuctionItem, error)
+
+	// List retrieves one page of the models from storage that match the options.
+	List(ctx context.Context, options ListOptions) (*ItemPage, error)
+
+	// GetDeleted retrieves every deleted model from storage in the order they were deleted.
+	GetDeleted(ctx context.Context) ([]*model.AuctionItem, error)
+
+	// Delete hides the model and its bids from every other method until it is restored. The name stays taken. Items
+	// sold in a lot cannot be deleted.
 	Delete(ctx context.Context, name string) error
 
+	// Restore brings back a deleted model along with its bids.
+	Restore(ctx context.Context, id uint64) error
+
 	// Update changes the non-zero fields in the supplied model.
 	Update(ctx context.Context, item *model.AuctionItem) error
 
-	// Create adds a new model to storage.
+	// Create adds a new model to storage. The ID of the model is set once it is stored. The category of the model must
+	// already exist. A model without a quantity is stored with a quantity of 1.
 	Create(ctx context.Context, item *model.AuctionItem) error
+
+	// Rename changes the name of the item with the ID. The bids on the item are kept.
+	Rename(ctx context.Context, id uint64, name string) error
+
+	// Replace overwrites every field of the item with the ID, including empty ones, as long as the item is still at
+	// the version of the supplied model. The version and quantity of the supplied model are set to the stored ones.
+	// A quantity that is not set is stored as 1.
+	Replace(ctx context.Context, item *model.AuctionItem) error
+
+	// ClaimClosed retrieves the models whose closing time has passed by now and that were not retrieved for that closing
+	// time before, ordered by closing time. Each closing is only retrieved once, even by the other instances sharing the
+	// storage, so it can be announced once. Deleted models are not retrieved.
+	ClaimClosed(ctx context.Context, now time.Time) ([]*model.AuctionItem, error)
+}
+
+// ItemFilter narrows down the items retrieved by AuctionItemClient.GetMatching. Empty fields match every item.
+type ItemFilter struct {
+
+	// Category matches the items in the category, ignoring case.
+	Category string
+
+	// Tags matches the items that have every one of the tags, ignoring case.
+	Tags []string
+}
+
+// ItemSort is the order of the items retrieved by AuctionItemClient.List. Items that are equal by the order are ordered
+// by their ID.
+type ItemSort string
+
+const (
+	// ItemSortCreated orders the items in the order they were created. It is used when no order is given.
+	ItemSortCreated ItemSort = "created"
+
+	// ItemSortName orders the items by their name, ignoring case.
+	ItemSortName ItemSort = "name"
+
+	// ItemSortCurrentBid orders the items by their highest bid. Items without bids have a current bid of 0.
+	ItemSortCurrentBid ItemSort = "currentBid"
+
+	// ItemSortBidCount orders the items by how many bids were placed on them.
+	ItemSortBidCount ItemSort = "bidCount"
+
+	// ItemSortClosing orders the items by when bidding on them closes. Items without a closing time close after every
+	// other item.
+	ItemSortClosing ItemSort = "closing"
+)
+
+// ListOptions narrows down, orders and pages the items retrieved by AuctionItemClient.List.
+type ListOptions struct {
+	ItemFilter
+
+	// Search matches the items whose name or description has a word starting with each word of the search, ignoring
+	// case.
+	Search string
+
+	// Sort is the order of the items. The zero value is ItemSortCreated.
+	Sort ItemSort
+
+	// Descending reverses the order of the items.
+	Descending bool
+
+	// Limit is the most items in the page. Every item is retrieved when it is 0.
+	Limit int
+
+	// Cursor continues the listing after the page it was returned with. It must be used with the same Sort and
+	// Descending as that page, otherwise ErrInvalidCursor is returned.
+	Cursor string
+}
+
+// ItemPage is one page of the items retrieved by AuctionItemClient.List.
+type ItemPage struct {
+	Items []*model.AuctionItem
+
+	// NextCursor retrieves the next page when it is given as ListOptions.Cursor. It is empty on the last page.
+	NextCursor string
+}
+
+// CategoryClient defines how to store model.Category objects.
+//go:generate mockery --name CategoryClient
+type CategoryClient interface {
+
+	// GetAll retrieves every model from storage ordered by name.
+	GetAll(ctx context.Context) ([]*model.Category, error)
+
+	// Create adds a new model to storage. The ID of the model is set once it is stored.
+	Create(ctx context.Context, category *model.Category) error
+
+	// Delete removes the model by its name, ignoring case. The items in the category are kept without a category.
+	Delete(ctx context.Context, name string) error
+}
+
+// LotClient defines how to store model.Lot objects. Items can only be moved between lots while none of them have bids.
+//go:generate mockery --name LotClient
+type LotClient interface {
+
+	// GetAll retrieves every model from storage in the order they were created.
+	GetAll(ctx context.Context) ([]*model.Lot, error)
+
+	// GetByID retrieves the model from storage by its ID.
+	GetByID(ctx context.Context, id uint64) (*model.Lot, error)
+
+	// Create adds a new model to storage with the items of the IDs, which must not be in another lot. The ID and items
+	// of the model are set once it is stored.
+	Create(ctx context.Context, lot *model.Lot, itemIDs []uint64) error
+
+	// Merge moves every item of the other lots and the items of the IDs into the lot. The other lots are removed.
+	Merge(ctx context.Context, id uint64, lotIDs []uint64, itemIDs []uint64) error
+
+	// Split takes the items of the IDs out of the lot so they are sold on their own again. The lot is removed when
+	// fewer than two items would be left in it or when no IDs are given.
+	Split(ctx context.Context, id uint64, itemIDs []uint64) error
 }
 
 // AuctionItemClient defines how to store model.AuctionBid objects.
 //go:generate mockery --name AuctionBidClient
 type AuctionBidClient interface {
 
-	// GetHighestBid retrieves the highest bid for the item.
+	// GetHighestBid retrieves the highest bid for the item. The highest bid of an item in a lot is the highest bid on the
+	// lot.
 	GetHighestBid(ctx context.Context, item *model.AuctionItem) (*model.AuctionBid, error)
 
-	// GetAllHighestBids retrieves the highest bid for every item.
-	GetAllHighestBids(ctx context.Context) ([]*model.AuctionBid, error)
+	// GetWinningBids retrieves the highest bid of each bidder that is winning the item, from the highest bid down. As
+	// many bidders win as the quantity of the item, with ties going to whoever bid first. The winning bids of an item in
+	// a lot are the winning bids of the lot.
+	GetWinningBids(ctx context.Context, item *model.AuctionItem) ([]*model.AuctionBid, error)
+
+	// GetAllWinningBids retrieves the winning bids for every item that is sold on its own and for every lot.
+	GetAllWinningBids(ctx context.Context) ([]*model.AuctionBid, error)
+
+	// PlaceBid makes a new bid for the item by the supplied user. The bid must be higher than the other bids of the user
+	// on the item and high enough to take one of the winning places from the other bidders. A bid on an item in a lot
+	// is a bid on the lot. Bids are compared by their amount alone so every bid must be in the currency of the event.
+	// The bid plus the winning bids of the user on every other item must not exceed the spending limit of the user, or
+	// the limit of the event when the user does not have one. The bid is returned with its bidder and the item it was
+	// placed on, which is the first item of the lot for a bid on a lot.
+	PlaceBid(ctx context.Context, user *model.User, item *model.AuctionItem, amount model.Money) (*model.AuctionBid, error)
+}
+
+// DonationClient defines how to store model.AppealLevel and model.Pledge objects.
+//go:generate mockery --name DonationClient
+type DonationClient interface {
+
+	// GetLevels retrieves every appeal level from storage from the highest amount down.
+	GetLevels(ctx context.Context) ([]*model.AppealLevel, error)
+
+	// CreateLevel adds a new appeal level to storage. The ID of the model is set once it is stored. The name must be
+	// unique, ignoring case.
+	CreateLevel(ctx context.Context, level *model.AppealLevel) error
+
+	// DeleteLevel removes the appeal level with the ID. Levels that have pledges cannot be deleted.
+	DeleteLevel(ctx context.Context, id uint64) error
+
+	// GetPledges retrieves every pledge from storage in the order they were recorded. Pledges by deleted users are kept.
+	GetPledges(ctx context.Context) ([]*model.Pledge, error)
+
+	// CreatePledge adds a new pledge by the bidder at the level of the model. The ID, level and amount of the model are
+	// set once it is stored.
+	CreatePledge(ctx context.Context, pledge *model.Pledge) error
+
+	// DeletePledge removes the pledge with the ID and returns it.
+	DeletePledge(ctx context.Context, id uint64) (*model.Pledge, error)
+}
+
+// RaffleClient defines how to store model.Raffle and model.TicketPurchase objects.
+//go:generate mockery --name RaffleClient
+type RaffleClient interface {
+
+	// GetAll retrieves every raffle from storage in the order they were created.
+	GetAll(ctx context.Context) ([]*model.Raffle, error)
+
+	// GetByID retrieves the raffle with the ID along with its drawing.
+	GetByID(ctx context.Context, id uint64) (*model.Raffle, error)
+
+	// Create adds a new raffle to storage. The ID of the model is set once it is stored. The name must be unique,
+	// ignoring case.
+	Create(ctx context.Context, raffle *model.Raffle) error
+
+	// GetTickets retrieves every ticket purchase of the raffle in the order they were bought. Purchases by deleted users
+	// are kept.
+	GetTickets(ctx context.Context, raffleID uint64) ([]*model.TicketPurchase, error)
+
+	// BuyTickets adds a new ticket purchase by the buyer of the model. The ID and price of the model are set once it is
+	// stored. Tickets cannot be bought once the raffle is drawn.
+	BuyTickets(ctx context.Context, purchase *model.TicketPurchase) error
+
+	// RecordDrawing stores the drawing of the raffle. A raffle can only be drawn once.
+	RecordDrawing(ctx context.Context, raffleID uint64, drawing *model.RaffleDrawing) error
+}
+
+// SpendingLimitClient defines how to store model.SpendingLimit objects. The limit of the event has an empty username.
+//go:generate mockery --name SpendingLimitClient
+type SpendingLimitClient interface {
+
+	// GetAll retrieves the limit of the event, when there is one, followed by the limits of the users ordered by
+	// username. Limits of deleted users are kept.
+	GetAll(ctx context.Context) ([]*model.SpendingLimit, error)
+
+	// Get retrieves the limit of the user, or of the event when the username is empty.
+	Get(ctx context.Context, username string) (*model.SpendingLimit, error)
+
+	// Set stores the limit, replacing the limit the user or the event already had. The user must exist.
+	Set(ctx context.Context, limit *model.SpendingLimit) error
+
+	// Delete removes the limit of the user, or of the event when the username is empty.
+	Delete(ctx context.Context, username string) error
+}
+
+// OutbidNoticeClient defines how to store model.OutbidNotice objects until the bidders are told about them. The notices
+// are shared by every instance using the storage, so a bidder is told once on whichever instance they are connected to.
+//go:generate mockery --name OutbidNoticeClient
+type OutbidNoticeClient interface {
+
+	// Add stores the model in place of any earlier model for the same user about the same item, or the same lot when
+	// the item is sold in a lot. The ID of the model is set once it is stored. The user and item must exist.
+	Add(ctx context.Context, notice *model.OutbidNotice) error
+
+	// Remove deletes the model with the ID once the user was told about it. Nothing is removed if the model was already
+	// taken or replaced.
+	Remove(ctx context.Context, id uint64) error
+
+	// Take retrieves and removes every model of the user in the order they were added. Each model is only retrieved by
+	// one caller.
+	Take(ctx context.Context, username string) ([]*model.OutbidNotice, error)
+}
+
+// ItemImageClient defines how to store model.ItemImage objects.
+//go:generate mockery --name ItemImageClient
+type ItemImageClient interface {
+
+	// GetAll retrieves every image of the item in order of their position.
+	GetAll(ctx context.Context, itemID uint64) ([]*model.ItemImage, error)
+
+	// Add stores the image after the other images of its item. The ID and position of the model are set once it is
+	// stored.
+	Add(ctx context.Context, image *model.ItemImage) error
+
+	// Delete removes the image from the item and moves the images after it up one position.
+	Delete(ctx context.Context, itemID uint64, imageID uint64) error
+
+	// Reorder changes the positions of the images of the item to the order of the IDs. The IDs must include every
+	// image of the item exactly once.
+	Reorder(ctx context.Context, itemID uint64, imageIDs []uint64) error
+}
+
+// Clients groups a client for each kind of model.
+type Clients struct {
+	Users      UserClient
+	Items      AuctionItemClient
+	Bids       AuctionBidClient
+	Images     ItemImageClient
+	Categories CategoryClient
+	Lots       LotClient
+	Donations  DonationClient
+	Raffles    RaffleClient
+	Limits     SpendingLimitClient
+	Outbids    OutbidNoticeClient
+}
+
+// Transactor runs several operations across the clients as a single unit of work.
+//go:generate mockery --name Transactor
+type Transactor interface {
 
-	// PlaceBid makes a new bid for the item by the supplied user.
-	PlaceBid(ctx context.Context, user *model.User, item *model.AuctionItem, amount int) (*model.AuctionBid, error)
+	// RunInTx runs fn with clients that all operate within one transaction. The transaction is committed when fn
+	// returns nil and rolled back otherwise, in which case the error from fn is returned. Only the supplied clients may
+	// be used within fn.
+	RunInTx(ctx context.Context, fn func(ctx context.Context, clients *Clients) error) error
 }
@@ -0,0 +1,1939 @@
+// Package storagetest provides a test suite that every implementation of the storage interfaces must pass so they can
+// be used interchangeably.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MMarsolek/AuctionHouse/model"
+	"github.com/MMarsolek/AuctionHouse/storage"
+	"github.com/stretchr/testify/suite"
+)
+
+// Clients are the implementations of the storage interfaces tested by the ConformanceSuite. They must share the same
+// storage.
+type Clients struct {
+	Users      storage.UserClient
+	Items      storage.AuctionItemClient
+	Bids       storage.AuctionBidClient
+	Images     storage.ItemImageClient
+	Categories storage.CategoryClient
+	Lots       storage.LotClient
+	Donations  storage.DonationClient
+	Raffles    storage.RaffleClient
+	Limits     storage.SpendingLimitClient
+	Outbids    storage.OutbidNoticeClient
+	Transactor storage.Transactor
+}
+
+// ConformanceSuite verifies the behavior that callers of the storage interfaces rely on.
+type ConformanceSuite struct {
+	suite.Suite
+
+	// NewClients is called before every test and must return clients whose storage is empty.
+	NewClients func(t *testing.T) *Clients
+
+	ctx     context.Context
+	clients *Clients
+}
+
+func (ts *ConformanceSuite) SetupTest() {
+	ts.ctx = context.Background()
+	ts.clients = ts.NewClients(ts.T())
+}
+
+func (ts *ConformanceSuite) TestUserCreateThenGetReturnsUser() {
+	users, _ := ts.createTestAssets()
+
+	user, err := ts.clients.Users.Get(ts.ctx, users[0].Username)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(users[0], user)
+}
+
+func (ts *ConformanceSuite) TestUserCreateReturnsErrEntityAlreadyExistsOnDuplicateUsername() {
+	users, _ := ts.createTestAssets()
+
+	err := ts.clients.Users.Create(ts.ctx, &model.User{
+		Username:       users[0].Username,
+		DisplayName:    "other",
+		HashedPassword: "5678",
+		Permission:     model.PermissionLevelAdmin,
+	})
+	ts.Require().ErrorIs(err, storage.ErrEntityAlreadyExists)
+}
+
+func (ts *ConformanceSuite) TestUserGetReturnsErrEntityNotFoundWhenMissing() {
+	_, err := ts.clients.Users.Get(ts.ctx, "missing")
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestUserUpdateOnlyChangesNonZeroFields() {
+	users, _ := ts.createTestAssets()
+
+	ts.Require().NoError(ts.clients.Users.Update(ts.ctx, &model.User{
+		Username:    users[0].Username,
+		DisplayName: "updated",
+	}))
+
+	user, err := ts.clients.Users.Get(ts.ctx, users[0].Username)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("updated", user.DisplayName)
+	ts.Require().EqualValues(users[0].HashedPassword, user.HashedPassword)
+	ts.Require().EqualValues(users[0].Permission, user.Permission)
+}
+
+func (ts *ConformanceSuite) TestUserDeleteHidesUserButKeepsTheirBids() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+	ts.placeBid(users[1], items[1], 20)
+
+	ts.Require().NoError(ts.clients.Users.Delete(ts.ctx, users[0].Username))
+
+	_, err := ts.clients.Users.Get(ts.ctx, users[0].Username)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	bids, err := ts.clients.Bids.GetAllWinningBids(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(bids, 2)
+	ts.Require().EqualValues(users[0].Username, bids[0].Bidder.Username)
+	ts.Require().False(bids[0].Bidder.DeletedAt.IsZero())
+
+	_, err = ts.clients.Bids.PlaceBid(ts.ctx, users[0], items[0], usd(30))
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestUserDeleteKeepsUsernameTaken() {
+	users, _ := ts.createTestAssets()
+	ts.Require().NoError(ts.clients.Users.Delete(ts.ctx, users[0].Username))
+
+	err := ts.clients.Users.Create(ts.ctx, &model.User{
+		Username:       users[0].Username,
+		DisplayName:    "someone else",
+		HashedPassword: "1234",
+		Permission:     model.PermissionLevelBidder,
+	})
+	ts.Require().ErrorIs(err, storage.ErrEntityAlreadyExists)
+}
+
+func (ts *ConformanceSuite) TestUserGetDeletedReturnsDeletedUsersInDeletionOrder() {
+	users, _ := ts.createTestAssets()
+	ts.Require().NoError(ts.clients.Users.Delete(ts.ctx, users[1].Username))
+	ts.Require().NoError(ts.clients.Users.Delete(ts.ctx, users[0].Username))
+
+	deleted, err := ts.clients.Users.GetDeleted(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(deleted, 2)
+	ts.Require().EqualValues(users[1].Username, deleted[0].Username)
+	ts.Require().EqualValues(users[0].Username, deleted[1].Username)
+	ts.Require().False(deleted[0].DeletedAt.IsZero())
+	ts.Require().False(deleted[0].DeletedAt.After(deleted[1].DeletedAt))
+}
+
+func (ts *ConformanceSuite) TestUserRestoreBringsBackUser() {
+	users, _ := ts.createTestAssets()
+	ts.Require().NoError(ts.clients.Users.Delete(ts.ctx, users[0].Username))
+
+	ts.Require().NoError(ts.clients.Users.Restore(ts.ctx, users[0].Username))
+
+	user, err := ts.clients.Users.Get(ts.ctx, users[0].Username)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(users[0], user)
+
+	deleted, err := ts.clients.Users.GetDeleted(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Empty(deleted)
+}
+
+func (ts *ConformanceSuite) TestUserRestoreReturnsErrEntityNotFoundUnlessDeleted() {
+	users, _ := ts.createTestAssets()
+
+	ts.Require().ErrorIs(ts.clients.Users.Restore(ts.ctx, users[0].Username), storage.ErrEntityNotFound)
+	ts.Require().ErrorIs(ts.clients.Users.Restore(ts.ctx, "missing"), storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestUserDeleteReturnsErrEntityNotFoundWhenMissing() {
+	err := ts.clients.Users.Delete(ts.ctx, "missing")
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestItemGetIgnoresCase() {
+	_, items := ts.createTestAssets()
+
+	item, err := ts.clients.Items.Get(ts.ctx, "ITEM ONE")
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(items[0], item)
+}
+
+func (ts *ConformanceSuite) TestItemCreateReturnsErrEntityAlreadyExistsWhenNameDiffersByCase() {
+	ts.createTestAssets()
+
+	err := ts.clients.Items.Create(ts.ctx, &model.AuctionItem{
+		Name: "ITEM ONE",
+	})
+	ts.Require().ErrorIs(err, storage.ErrEntityAlreadyExists)
+}
+
+func (ts *ConformanceSuite) TestItemGetReturnsErrEntityNotFoundWhenMissing() {
+	_, err := ts.clients.Items.Get(ts.ctx, "missing")
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestItemGetAllReturnsItemsInCreationOrder() {
+	_, items := ts.createTestAssets()
+
+	allItems, err := ts.clients.Items.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(items, allItems)
+}
+
+func (ts *ConformanceSuite) TestItemGetAllReturnsEmptyWhenNoItems() {
+	allItems, err := ts.clients.Items.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Empty(allItems)
+}
+
+func (ts *ConformanceSuite) TestItemUpdateChangesNameCaseAndNonZeroFields() {
+	_, items := ts.createTestAssets()
+
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{
+		Name:        "Item One",
+		Description: "updated",
+	}))
+
+	item, err := ts.clients.Items.Get(ts.ctx, items[0].Name)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("Item One", item.Name)
+	ts.Require().EqualValues(items[0].ImageRef, item.ImageRef)
+	ts.Require().EqualValues("updated", item.Description)
+}
+
+func (ts *ConformanceSuite) TestItemDeleteHidesItemAndItsBids() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+	ts.placeBid(users[0], items[1], 20)
+
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, "ITEM ONE"))
+
+	_, err := ts.clients.Items.Get(ts.ctx, items[0].Name)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+	_, err = ts.clients.Items.GetByID(ts.ctx, items[0].ID)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	allItems, err := ts.clients.Items.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(items[1:], allItems)
+
+	bids, err := ts.clients.Bids.GetAllWinningBids(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(bids, 1)
+	ts.Require().EqualValues(items[1].Name, bids[0].Item.Name)
+
+	_, err = ts.clients.Bids.GetHighestBid(ts.ctx, items[0])
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+	_, err = ts.clients.Bids.PlaceBid(ts.ctx, users[0], items[0], usd(30))
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestItemDeleteKeepsNameTaken() {
+	_, items := ts.createTestAssets()
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, items[0].Name))
+
+	err := ts.clients.Items.Create(ts.ctx, &model.AuctionItem{Name: items[0].Name})
+	ts.Require().ErrorIs(err, storage.ErrEntityAlreadyExists)
+	err = ts.clients.Items.Rename(ts.ctx, items[1].ID, items[0].Name)
+	ts.Require().ErrorIs(err, storage.ErrEntityAlreadyExists)
+}
+
+func (ts *ConformanceSuite) TestItemGetDeletedReturnsDeletedItemsInDeletionOrder() {
+	_, items := ts.createTestAssets()
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, items[1].Name))
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, items[0].Name))
+
+	deleted, err := ts.clients.Items.GetDeleted(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(deleted, 2)
+	ts.Require().EqualValues(items[1].ID, deleted[0].ID)
+	ts.Require().EqualValues(items[0].ID, deleted[1].ID)
+	ts.Require().False(deleted[0].DeletedAt.IsZero())
+	ts.Require().False(deleted[0].DeletedAt.After(deleted[1].DeletedAt))
+}
+
+func (ts *ConformanceSuite) TestItemRestoreBringsBackItemAndItsBids() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, items[0].Name))
+
+	ts.Require().NoError(ts.clients.Items.Restore(ts.ctx, items[0].ID))
+
+	item, err := ts.clients.Items.GetByID(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(items[0].Name, item.Name)
+	ts.Require().EqualValues(items[0].Description, item.Description)
+	ts.Require().EqualValues(items[0].Version+1, item.Version)
+
+	bid, err := ts.clients.Bids.GetHighestBid(ts.ctx, items[0])
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(usd(10), bid.BidAmount)
+
+	_, err = ts.clients.Bids.PlaceBid(ts.ctx, users[1], items[0], usd(5))
+	ts.Require().ErrorIs(err, storage.ErrBidTooLow)
+}
+
+func (ts *ConformanceSuite) TestItemRestoreReturnsErrEntityNotFoundUnlessDeleted() {
+	_, items := ts.createTestAssets()
+
+	ts.Require().ErrorIs(ts.clients.Items.Restore(ts.ctx, items[0].ID), storage.ErrEntityNotFound)
+	ts.Require().ErrorIs(ts.clients.Items.Restore(ts.ctx, 999), storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestItemDeleteReturnsErrEntityNotFoundWhenMissing() {
+	err := ts.clients.Items.Delete(ts.ctx, "missing")
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestItemCreateSetsUniqueID() {
+	_, items := ts.createTestAssets()
+
+	ts.Require().NotZero(items[0].ID)
+	ts.Require().NotZero(items[1].ID)
+	ts.Require().NotEqual(items[0].ID, items[1].ID)
+}
+
+func (ts *ConformanceSuite) TestItemGetByIDReturnsItem() {
+	_, items := ts.createTestAssets()
+
+	item, err := ts.clients.Items.GetByID(ts.ctx, items[1].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(items[1], item)
+}
+
+func (ts *ConformanceSuite) TestItemGetByIDReturnsErrEntityNotFoundWhenMissing() {
+	_, err := ts.clients.Items.GetByID(ts.ctx, 12345)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestItemRenameKeepsIDAndBids() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+
+	ts.Require().NoError(ts.clients.Items.Rename(ts.ctx, items[0].ID, "Renamed"))
+
+	_, err := ts.clients.Items.Get(ts.ctx, items[0].Name)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	renamed, err := ts.clients.Items.Get(ts.ctx, "renamed")
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(items[0].ID, renamed.ID)
+	ts.Require().EqualValues("Renamed", renamed.Name)
+	ts.Require().EqualValues(items[0].Description, renamed.Description)
+
+	bid, err := ts.clients.Bids.GetHighestBid(ts.ctx, renamed)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(usd(10), bid.BidAmount)
+	ts.Require().EqualValues(renamed, bid.Item)
+
+	ts.placeBid(users[1], renamed, 20)
+}
+
+func (ts *ConformanceSuite) TestItemRenameCanChangeCase() {
+	_, items := ts.createTestAssets()
+
+	ts.Require().NoError(ts.clients.Items.Rename(ts.ctx, items[0].ID, "ITEM ONE"))
+
+	item, err := ts.clients.Items.GetByID(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("ITEM ONE", item.Name)
+}
+
+func (ts *ConformanceSuite) TestItemRenameReturnsErrEntityAlreadyExistsWhenNameTaken() {
+	_, items := ts.createTestAssets()
+
+	err := ts.clients.Items.Rename(ts.ctx, items[0].ID, "Item Two")
+	ts.Require().ErrorIs(err, storage.ErrEntityAlreadyExists)
+
+	item, err := ts.clients.Items.GetByID(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(items[0].Name, item.Name)
+}
+
+func (ts *ConformanceSuite) TestItemRenameReturnsErrEntityNotFoundWhenMissing() {
+	err := ts.clients.Items.Rename(ts.ctx, 12345, "anything")
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestItemCreateStartsAtVersionOne() {
+	_, items := ts.createTestAssets()
+	ts.Require().EqualValues(1, items[0].Version)
+
+	item, err := ts.clients.Items.GetByID(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(1, item.Version)
+}
+
+func (ts *ConformanceSuite) TestItemUpdateAndRenameIncrementVersion() {
+	_, items := ts.createTestAssets()
+
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: items[0].Name, Description: "updated"}))
+	ts.Require().NoError(ts.clients.Items.Rename(ts.ctx, items[0].ID, "Renamed"))
+
+	item, err := ts.clients.Items.GetByID(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(items[0].Version+2, item.Version)
+}
+
+func (ts *ConformanceSuite) TestItemReplaceOverwritesEveryFieldAndIncrementsVersion() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+
+	replacement := &model.AuctionItem{
+		ID:      items[0].ID,
+		Name:    "Replaced",
+		Version: items[0].Version,
+	}
+	ts.Require().NoError(ts.clients.Items.Replace(ts.ctx, replacement))
+	ts.Require().EqualValues(items[0].Version+1, replacement.Version)
+
+	item, err := ts.clients.Items.GetByID(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(replacement, item)
+
+	_, err = ts.clients.Items.Get(ts.ctx, items[0].Name)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	bid, err := ts.clients.Bids.GetHighestBid(ts.ctx, item)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(usd(10), bid.BidAmount)
+}
+
+func (ts *ConformanceSuite) TestItemReplaceReturnsErrVersionMismatchWhenChanged() {
+	_, items := ts.createTestAssets()
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: items[0].Name, Description: "updated"}))
+
+	err := ts.clients.Items.Replace(ts.ctx, &model.AuctionItem{
+		ID:      items[0].ID,
+		Name:    items[0].Name,
+		Version: items[0].Version,
+	})
+	ts.Require().ErrorIs(err, storage.ErrVersionMismatch)
+
+	item, err := ts.clients.Items.GetByID(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("updated", item.Description)
+}
+
+func (ts *ConformanceSuite) TestItemReplaceReturnsErrEntityAlreadyExistsWhenNameTaken() {
+	_, items := ts.createTestAssets()
+
+	err := ts.clients.Items.Replace(ts.ctx, &model.AuctionItem{
+		ID:      items[0].ID,
+		Name:    "Item Two",
+		Version: items[0].Version,
+	})
+	ts.Require().ErrorIs(err, storage.ErrEntityAlreadyExists)
+}
+
+func (ts *ConformanceSuite) TestItemReplaceReturnsErrEntityNotFoundWhenMissingOrDeleted() {
+	_, items := ts.createTestAssets()
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, items[0].Name))
+
+	err := ts.clients.Items.Replace(ts.ctx, &model.AuctionItem{ID: 12345, Name: "anything", Version: 1})
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+	err = ts.clients.Items.Replace(ts.ctx, items[0])
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestItemCreateStoresCategoryTagsAndDonor() {
+	ts.createCategory("Sports")
+
+	item := &model.AuctionItem{
+		Name:            "Golf Clubs",
+		Category:        "SPORTS",
+		Tags:            []string{"Outdoors", "golf", "GOLF", ""},
+		DonorName:       "Pat Smith",
+		DonorBusiness:   "Smith Golf",
+		FairMarketValue: usd(50000),
+	}
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	ts.Require().EqualValues("Sports", item.Category)
+	ts.Require().EqualValues([]string{"golf", "Outdoors"}, item.Tags)
+
+	stored, err := ts.clients.Items.GetByID(ts.ctx, item.ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(item, stored)
+}
+
+func (ts *ConformanceSuite) TestItemCreateReturnsErrUnknownCategoryWhenMissing() {
+	err := ts.clients.Items.Create(ts.ctx, &model.AuctionItem{Name: "Golf Clubs", Category: "missing"})
+	ts.Require().ErrorIs(err, storage.ErrUnknownCategory)
+
+	_, err = ts.clients.Items.Get(ts.ctx, "Golf Clubs")
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestItemTagsKeepTheNameTheyWereFirstGiven() {
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, &model.AuctionItem{Name: "one", Tags: []string{"Golf"}}))
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, &model.AuctionItem{Name: "two", Tags: []string{"GOLF"}}))
+
+	item, err := ts.clients.Items.Get(ts.ctx, "two")
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]string{"Golf"}, item.Tags)
+}
+
+func (ts *ConformanceSuite) TestItemUpdateChangesCategoryTagsAndDonorWhenNonZero() {
+	_, items := ts.createTestAssets()
+	ts.createCategory("Sports")
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{
+		Name:            items[0].Name,
+		Category:        "Sports",
+		Tags:            []string{"golf"},
+		DonorName:       "Pat Smith",
+		FairMarketValue: usd(50000),
+	}))
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{
+		Name:          items[0].Name,
+		DonorBusiness: "Smith Golf",
+	}))
+
+	item, err := ts.clients.Items.GetByID(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("Sports", item.Category)
+	ts.Require().EqualValues([]string{"golf"}, item.Tags)
+	ts.Require().EqualValues("Pat Smith", item.DonorName)
+	ts.Require().EqualValues("Smith Golf", item.DonorBusiness)
+	ts.Require().EqualValues(usd(50000), item.FairMarketValue)
+
+	err = ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: items[0].Name, Category: "missing"})
+	ts.Require().ErrorIs(err, storage.ErrUnknownCategory)
+}
+
+func (ts *ConformanceSuite) TestItemReplaceOverwritesCategoryTagsAndDonor() {
+	ts.createCategory("Sports")
+	ts.createCategory("Travel")
+	item := &model.AuctionItem{
+		Name:            "Golf Trip",
+		Category:        "Sports",
+		Tags:            []string{"golf", "outdoors"},
+		DonorName:       "Pat Smith",
+		FairMarketValue: usd(50000),
+	}
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+
+	replacement := &model.AuctionItem{
+		ID:            item.ID,
+		Name:          item.Name,
+		Category:      "Travel",
+		Tags:          []string{"weekend"},
+		DonorBusiness: "Smith Travel",
+		Version:       item.Version,
+	}
+	ts.Require().NoError(ts.clients.Items.Replace(ts.ctx, replacement))
+
+	stored, err := ts.clients.Items.GetByID(ts.ctx, item.ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(replacement, stored)
+
+	replacement.Category = "missing"
+	ts.Require().ErrorIs(ts.clients.Items.Replace(ts.ctx, replacement), storage.ErrUnknownCategory)
+}
+
+func (ts *ConformanceSuite) TestItemGetMatchingFiltersByCategoryAndEveryTag() {
+	ts.createCategory("Sports")
+	items := []*model.AuctionItem{
+		{Name: "Golf Clubs", Category: "Sports", Tags: []string{"golf", "outdoors"}},
+		{Name: "Golf Lessons", Category: "Sports", Tags: []string{"golf"}},
+		{Name: "Golf Painting", Tags: []string{"golf", "art"}},
+		{Name: "Kayak", Category: "Sports", Tags: []string{"outdoors"}},
+	}
+	for _, item := range items {
+		ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	}
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, "Golf Lessons"))
+
+	matching, err := ts.clients.Items.GetMatching(ts.ctx, storage.ItemFilter{Category: "sports"})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AuctionItem{items[0], items[3]}, matching)
+
+	matching, err = ts.clients.Items.GetMatching(ts.ctx, storage.ItemFilter{Tags: []string{"GOLF"}})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AuctionItem{items[0], items[2]}, matching)
+
+	matching, err = ts.clients.Items.GetMatching(ts.ctx, storage.ItemFilter{
+		Category: "Sports",
+		Tags:     []string{"golf", "outdoors"},
+	})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AuctionItem{items[0]}, matching)
+
+	matching, err = ts.clients.Items.GetMatching(ts.ctx, storage.ItemFilter{})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AuctionItem{items[0], items[2], items[3]}, matching)
+
+	matching, err = ts.clients.Items.GetMatching(ts.ctx, storage.ItemFilter{Category: "missing"})
+	ts.Require().NoError(err)
+	ts.Require().Empty(matching)
+}
+
+func (ts *ConformanceSuite) TestItemListSearchesNameAndDescription() {
+	ts.createCategory("Drinks")
+	items := []*model.AuctionItem{
+		{Name: "Red Wine", Description: "A case of merlot", Category: "Drinks"},
+		{Name: "Wine Glasses", Description: "A set of six"},
+		{Name: "Cruise", Description: "Includes a WINE tasting"},
+		{Name: "Golf Clubs", Description: "Driver and irons"},
+		{Name: "Wine Rack", Description: "Holds twelve bottles"},
+	}
+	for _, item := range items {
+		ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	}
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, "Wine Rack"))
+
+	page, err := ts.clients.Items.List(ts.ctx, storage.ListOptions{Search: "wine"})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AuctionItem{items[0], items[1], items[2]}, page.Items)
+
+	page, err = ts.clients.Items.List(ts.ctx, storage.ListOptions{Search: "red, WIN"})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AuctionItem{items[0]}, page.Items)
+
+	page, err = ts.clients.Items.List(ts.ctx, storage.ListOptions{Search: "merl"})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AuctionItem{items[0]}, page.Items)
+
+	page, err = ts.clients.Items.List(ts.ctx, storage.ListOptions{
+		Search:     "wine",
+		ItemFilter: storage.ItemFilter{Category: "drinks"},
+	})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AuctionItem{items[0]}, page.Items)
+
+	ts.Require().NoError(ts.clients.Items.Rename(ts.ctx, items[3].ID, "Golf and Wine Weekend"))
+	renamed, err := ts.clients.Items.GetByID(ts.ctx, items[3].ID)
+	ts.Require().NoError(err)
+	ts.Require().NoError(ts.clients.Items.Rename(ts.ctx, items[1].ID, "Glasses"))
+	items[1], err = ts.clients.Items.GetByID(ts.ctx, items[1].ID)
+	ts.Require().NoError(err)
+
+	page, err = ts.clients.Items.List(ts.ctx, storage.ListOptions{Search: "wine"})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AuctionItem{items[0], items[2], renamed}, page.Items)
+	ts.Require().Empty(page.NextCursor)
+}
+
+func (ts *ConformanceSuite) TestItemListSortsByNameCurrentBidAndBidCount() {
+	users, _ := ts.createTestAssets()
+	items := []*model.AuctionItem{{Name: "banjo"}, {Name: "Accordion"}, {Name: "cello"}, {Name: "Drum"}}
+	for _, item := range items {
+		ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	}
+	ts.placeBid(users[0], items[0], 10)
+	ts.placeBid(users[1], items[0], 20)
+	ts.placeBid(users[0], items[0], 30)
+	ts.placeBid(users[0], items[2], 50)
+	ts.placeBid(users[0], items[3], 15)
+	ts.placeBid(users[1], items[3], 25)
+
+	tests := []struct {
+		sort       storage.ItemSort
+		descending bool
+		expected   []*model.AuctionItem
+	}{
+		{
+			sort:     storage.ItemSortName,
+			expected: []*model.AuctionItem{items[1], items[0], items[2], items[3]},
+		},
+		{
+			sort:       storage.ItemSortName,
+			descending: true,
+			expected:   []*model.AuctionItem{items[3], items[2], items[0], items[1]},
+		},
+		{
+			sort:     storage.ItemSortCurrentBid,
+			expected: []*model.AuctionItem{items[1], items[3], items[0], items[2]},
+		},
+		{
+			sort:       storage.ItemSortBidCount,
+			descending: true,
+			expected:   []*model.AuctionItem{items[0], items[3], items[2], items[1]},
+		},
+	}
+	for _, test := range tests {
+		page, err := ts.clients.Items.List(ts.ctx, storage.ListOptions{Sort: test.sort, Descending: test.descending})
+		ts.Require().NoError(err)
+
+		// The items of createTestAssets come first when ordered by creation, so only the instruments are compared.
+		var instruments []*model.AuctionItem
+		for _, item := range page.Items {
+			if item.ID >= items[0].ID {
+				instruments = append(instruments, item)
+			}
+		}
+		ts.Require().EqualValues(test.expected, instruments, "sorted by %s, descending %t", test.sort, test.descending)
+	}
+}
+
+func (ts *ConformanceSuite) TestItemListSortsByClosingTime() {
+	ts.createTestAssets()
+	closesAt := time.Date(2030, time.June, 1, 20, 0, 0, 0, time.UTC)
+	items := []*model.AuctionItem{
+		{Name: "cello", ClosesAt: closesAt.Add(time.Hour)},
+		{Name: "drum"},
+		{Name: "flute", ClosesAt: closesAt},
+		{Name: "harp", ClosesAt: closesAt},
+	}
+	for _, item := range items {
+		ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	}
+
+	for _, test := range []struct {
+		descending bool
+		expected   []string
+	}{
+		{descending: false, expected: []string{"flute", "harp", "cello", "drum"}},
+		{descending: true, expected: []string{"drum", "cello", "harp", "flute"}},
+	} {
+		options := storage.ListOptions{Sort: storage.ItemSortClosing, Descending: test.descending}
+		page, err := ts.clients.Items.List(ts.ctx, options)
+		ts.Require().NoError(err)
+
+		// The items of createTestAssets do not close either, so only the instruments are compared.
+		var names []string
+		for _, item := range page.Items {
+			if item.ID >= items[0].ID {
+				names = append(names, item.Name)
+			}
+		}
+		ts.Require().EqualValues(test.expected, names, "descending %t", test.descending)
+	}
+}
+
+func (ts *ConformanceSuite) TestItemClosingTimeIsStoredUpdatedAndReplaced() {
+	closesAt := time.Date(2030, time.June, 1, 20, 0, 0, 0, time.UTC)
+	item := &model.AuctionItem{Name: "cello", ClosesAt: closesAt}
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+
+	stored, err := ts.clients.Items.GetByID(ts.ctx, item.ID)
+	ts.Require().NoError(err)
+	ts.Require().True(closesAt.Equal(stored.ClosesAt), "closes at %s", stored.ClosesAt)
+
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: item.Name, Description: "wooden"}))
+	stored, err = ts.clients.Items.GetByID(ts.ctx, item.ID)
+	ts.Require().NoError(err)
+	ts.Require().True(closesAt.Equal(stored.ClosesAt), "closes at %s", stored.ClosesAt)
+
+	later := closesAt.Add(time.Hour)
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: item.Name, ClosesAt: later}))
+	stored, err = ts.clients.Items.GetByID(ts.ctx, item.ID)
+	ts.Require().NoError(err)
+	ts.Require().True(later.Equal(stored.ClosesAt), "closes at %s", stored.ClosesAt)
+
+	stored.ClosesAt = time.Time{}
+	ts.Require().NoError(ts.clients.Items.Replace(ts.ctx, stored))
+	stored, err = ts.clients.Items.GetByID(ts.ctx, item.ID)
+	ts.Require().NoError(err)
+	ts.Require().True(stored.ClosesAt.IsZero(), "closes at %s", stored.ClosesAt)
+}
+
+func (ts *ConformanceSuite) TestClaimClosedRetrievesEachClosingOnce() {
+	now := time.Now().UTC().Truncate(time.Second)
+	items := []*model.AuctionItem{
+		{Name: "cello", ClosesAt: now.Add(-time.Minute)},
+		{Name: "drum"},
+		{Name: "flute", ClosesAt: now.Add(-time.Hour)},
+		{Name: "harp", ClosesAt: now.Add(time.Hour)},
+		{Name: "oboe", ClosesAt: now.Add(-time.Hour)},
+	}
+	for _, item := range items {
+		ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	}
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, "oboe"))
+
+	closed, err := ts.clients.Items.ClaimClosed(ts.ctx, now)
+	ts.Require().NoError(err)
+	ts.Require().Equal([]string{"flute", "cello"}, itemNames(closed))
+
+	closed, err = ts.clients.Items.ClaimClosed(ts.ctx, now)
+	ts.Require().NoError(err)
+	ts.Require().Empty(closed)
+
+	closed, err = ts.clients.Items.ClaimClosed(ts.ctx, now.Add(2*time.Hour))
+	ts.Require().NoError(err)
+	ts.Require().Equal([]string{"harp"}, itemNames(closed))
+}
+
+func (ts *ConformanceSuite) TestClaimClosedRetrievesItemAgainOnceNewClosingTimePasses() {
+	now := time.Now().UTC().Truncate(time.Second)
+	item := &model.AuctionItem{Name: "cello", ClosesAt: now.Add(-time.Minute)}
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+
+	closed, err := ts.clients.Items.ClaimClosed(ts.ctx, now)
+	ts.Require().NoError(err)
+	ts.Require().Len(closed, 1)
+
+	later := now.Add(time.Hour)
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: item.Name, ClosesAt: later}))
+	closed, err = ts.clients.Items.ClaimClosed(ts.ctx, now)
+	ts.Require().NoError(err)
+	ts.Require().Empty(closed)
+
+	closed, err = ts.clients.Items.ClaimClosed(ts.ctx, later)
+	ts.Require().NoError(err)
+	ts.Require().Len(closed, 1)
+	ts.Require().Equal(item.ID, closed[0].ID)
+	ts.Require().True(later.Equal(closed[0].ClosesAt), "closes at %s", closed[0].ClosesAt)
+}
+
+func (ts *ConformanceSuite) TestItemListPagesWithCursor() {
+	users, _ := ts.createTestAssets()
+	closesAt := time.Date(2030, time.June, 1, 20, 0, 0, 0, time.UTC)
+	items := []*model.AuctionItem{
+		{Name: "one"}, {Name: "two", ClosesAt: closesAt}, {Name: "three"}, {Name: "four", ClosesAt: closesAt},
+		{Name: "five", ClosesAt: closesAt.Add(-time.Hour)},
+	}
+	for _, item := range items {
+		ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	}
+	ts.placeBid(users[0], items[1], 10)
+	ts.placeBid(users[0], items[3], 10)
+
+	sorts := []storage.ItemSort{
+		storage.ItemSortCreated, storage.ItemSortName, storage.ItemSortCurrentBid, storage.ItemSortClosing,
+	}
+	for _, sortBy := range sorts {
+		for _, descending := range []bool{false, true} {
+			options := storage.ListOptions{Sort: sortBy, Descending: descending}
+			everything, err := ts.clients.Items.List(ts.ctx, options)
+			ts.Require().NoError(err)
+			ts.Require().Empty(everything.NextCursor)
+
+			var paged []*model.AuctionItem
+			options.Limit = 2
+			for pages := 1; ; pages++ {
+				page, err := ts.clients.Items.List(ts.ctx, options)
+				ts.Require().NoError(err)
+				ts.Require().LessOrEqual(len(page.Items), 2)
+				paged = append(paged, page.Items...)
+				if page.NextCursor == "" {
+					ts.Require().EqualValues(4, pages, "sorted by %s, descending %t", sortBy, descending)
+					break
+				}
+				options.Cursor = page.NextCursor
+			}
+			ts.Require().EqualValues(everything.Items, paged, "sorted by %s, descending %t", sortBy, descending)
+		}
+	}
+}
+
+func (ts *ConformanceSuite) TestItemListReturnsErrInvalidCursorWhenOrderChanges() {
+	ts.createTestAssets()
+
+	page, err := ts.clients.Items.List(ts.ctx, storage.ListOptions{Sort: storage.ItemSortName, Limit: 1})
+	ts.Require().NoError(err)
+	ts.Require().NotEmpty(page.NextCursor)
+
+	_, err = ts.clients.Items.List(ts.ctx, storage.ListOptions{
+		Sort:       storage.ItemSortName,
+		Descending: true,
+		Cursor:     page.NextCursor,
+	})
+	ts.Require().ErrorIs(err, storage.ErrInvalidCursor)
+
+	_, err = ts.clients.Items.List(ts.ctx, storage.ListOptions{Cursor: "not a cursor"})
+	ts.Require().ErrorIs(err, storage.ErrInvalidCursor)
+}
+
+func (ts *ConformanceSuite) TestCategoryGetAllReturnsCategoriesByName() {
+	travel := ts.createCategory("travel")
+	sports := ts.createCategory("Sports")
+	art := ts.createCategory("Art")
+
+	categories, err := ts.clients.Categories.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.Category{art, sports, travel}, categories)
+}
+
+func (ts *ConformanceSuite) TestCategoryCreateReturnsErrEntityAlreadyExistsWhenNameDiffersByCase() {
+	ts.createCategory("Sports")
+
+	err := ts.clients.Categories.Create(ts.ctx, &model.Category{Name: "SPORTS"})
+	ts.Require().ErrorIs(err, storage.ErrEntityAlreadyExists)
+}
+
+func (ts *ConformanceSuite) TestCategoryDeleteTakesItemsOutOfCategory() {
+	ts.createCategory("Sports")
+	item := &model.AuctionItem{Name: "Golf Clubs", Category: "Sports"}
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+
+	ts.Require().NoError(ts.clients.Categories.Delete(ts.ctx, "sports"))
+
+	stored, err := ts.clients.Items.GetByID(ts.ctx, item.ID)
+	ts.Require().NoError(err)
+	ts.Require().Empty(stored.Category)
+	ts.Require().EqualValues(item.Version+1, stored.Version)
+
+	categories, err := ts.clients.Categories.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Empty(categories)
+	ts.Require().ErrorIs(ts.clients.Categories.Delete(ts.ctx, "sports"), storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestLotCreatePutsItemsInLot() {
+	_, items := ts.createTestAssets()
+
+	lot := ts.createLot("Dinner and a Show", items[1], items[0])
+	ts.Require().NotZero(lot.ID)
+	ts.Require().EqualValues("Dinner and a Show", lot.Name)
+	ts.Require().Len(lot.Items, 2)
+	ts.Require().EqualValues([]uint64{items[0].ID, items[1].ID}, []uint64{lot.Items[0].ID, lot.Items[1].ID})
+
+	for _, item := range items {
+		stored, err := ts.clients.Items.GetByID(ts.ctx, item.ID)
+		ts.Require().NoError(err)
+		ts.Require().EqualValues(lot.ID, stored.LotID)
+		ts.Require().EqualValues(item.Version+1, stored.Version)
+	}
+
+	stored, err := ts.clients.Lots.GetByID(ts.ctx, lot.ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(lot, stored)
+
+	lots, err := ts.clients.Lots.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.Lot{lot}, lots)
+}
+
+func (ts *ConformanceSuite) TestLotCreateReturnsErrorsForInvalidItems() {
+	users, items := ts.createTestAssets()
+	others := []*model.AuctionItem{{Name: "tickets"}, {Name: "dinner"}, {Name: "deleted"}}
+	for _, item := range others {
+		ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	}
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, others[2].Name))
+	ts.createLot("Date Night", others[0], others[1])
+	ts.placeBid(users[0], items[1], 10)
+
+	tests := []struct {
+		name     string
+		itemIDs  []uint64
+		expected error
+	}{
+		{name: "date night", itemIDs: []uint64{items[0].ID, items[1].ID}, expected: storage.ErrEntityAlreadyExists},
+		{name: "one item", itemIDs: []uint64{items[0].ID, items[0].ID}, expected: storage.ErrLotTooSmall},
+		{name: "missing", itemIDs: []uint64{items[0].ID, 999999}, expected: storage.ErrEntityNotFound},
+		{name: "deleted", itemIDs: []uint64{items[0].ID, others[2].ID}, expected: storage.ErrEntityNotFound},
+		{name: "in lot", itemIDs: []uint64{items[0].ID, others[0].ID}, expected: storage.ErrItemInLot},
+		{name: "bids", itemIDs: []uint64{items[0].ID, items[1].ID}, expected: storage.ErrLotHasBids},
+	}
+	for _, test := range tests {
+		err := ts.clients.Lots.Create(ts.ctx, &model.Lot{Name: test.name}, test.itemIDs)
+		ts.Require().ErrorIs(err, test.expected, test.name)
+	}
+
+	lots, err := ts.clients.Lots.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(lots, 1)
+}
+
+func (ts *ConformanceSuite) TestLotBidsOnAnyItemAreBidsOnTheLot() {
+	users, items := ts.createTestAssets()
+	single := &model.AuctionItem{Name: "single"}
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, single))
+	lot := ts.createLot("Bundle", items[0], items[1])
+
+	ts.placeBid(users[0], items[1], 10)
+	_, err := ts.clients.Bids.PlaceBid(ts.ctx, users[1], items[0], usd(10))
+	ts.Require().ErrorIs(err, storage.ErrBidTooLow)
+	ts.placeBid(users[1], items[0], 20)
+	ts.placeBid(users[0], single, 5)
+
+	for _, item := range items {
+		bid, err := ts.clients.Bids.GetHighestBid(ts.ctx, item)
+		ts.Require().NoError(err)
+		ts.Require().EqualValues(usd(20), bid.BidAmount)
+		ts.Require().EqualValues(users[1].Username, bid.Bidder.Username)
+		ts.Require().EqualValues(lot.ID, bid.Item.LotID)
+	}
+
+	bids, err := ts.clients.Bids.GetAllWinningBids(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(bids, 2)
+	ts.Require().EqualValues(usd(20), bids[0].BidAmount)
+	ts.Require().EqualValues(lot.ID, bids[0].Item.LotID)
+	ts.Require().EqualValues(usd(5), bids[1].BidAmount)
+
+	page, err := ts.clients.Items.List(ts.ctx, storage.ListOptions{Sort: storage.ItemSortCurrentBid, Descending: true})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]uint64{items[1].ID, items[0].ID, single.ID},
+		[]uint64{page.Items[0].ID, page.Items[1].ID, page.Items[2].ID})
+}
+
+func (ts *ConformanceSuite) TestLotMergeMovesItemsAndRemovesOtherLots() {
+	_, items := ts.createTestAssets()
+	others := []*model.AuctionItem{{Name: "tickets"}, {Name: "dinner"}, {Name: "parking"}}
+	for _, item := range others {
+		ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	}
+	lot := ts.createLot("Bundle", items[0], items[1])
+	other := ts.createLot("Date Night", others[0], others[1])
+
+	ts.Require().NoError(ts.clients.Lots.Merge(ts.ctx, lot.ID, []uint64{other.ID}, []uint64{others[2].ID, items[0].ID}))
+
+	merged, err := ts.clients.Lots.GetByID(ts.ctx, lot.ID)
+	ts.Require().NoError(err)
+	ts.Require().Len(merged.Items, 5)
+	for i, item := range append(items, others...) {
+		ts.Require().EqualValues(item.ID, merged.Items[i].ID)
+		ts.Require().EqualValues(lot.ID, merged.Items[i].LotID)
+	}
+
+	_, err = ts.clients.Lots.GetByID(ts.ctx, other.ID)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+	ts.Require().ErrorIs(ts.clients.Lots.Merge(ts.ctx, lot.ID, []uint64{other.ID}, nil), storage.ErrEntityNotFound)
+	ts.Require().ErrorIs(ts.clients.Lots.Merge(ts.ctx, other.ID, nil, nil), storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestLotMergeReturnsErrLotHasBidsWhenAnyItemHasBids() {
+	users, items := ts.createTestAssets()
+	others := []*model.AuctionItem{{Name: "tickets"}, {Name: "dinner"}}
+	for _, item := range others {
+		ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	}
+	lot := ts.createLot("Bundle", items[0], items[1])
+	ts.placeBid(users[0], others[1], 10)
+
+	err := ts.clients.Lots.Merge(ts.ctx, lot.ID, nil, []uint64{others[1].ID})
+	ts.Require().ErrorIs(err, storage.ErrLotHasBids)
+
+	ts.placeBid(users[0], items[0], 10)
+	err = ts.clients.Lots.Merge(ts.ctx, lot.ID, nil, []uint64{others[0].ID})
+	ts.Require().ErrorIs(err, storage.ErrLotHasBids)
+
+	stored, err := ts.clients.Items.GetByID(ts.ctx, others[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().Zero(stored.LotID)
+}
+
+func (ts *ConformanceSuite) TestLotSplitTakesItemsOutOfLot() {
+	_, items := ts.createTestAssets()
+	third := &model.AuctionItem{Name: "tickets"}
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, third))
+	lot := ts.createLot("Bundle", items[0], items[1], third)
+
+	ts.Require().NoError(ts.clients.Lots.Split(ts.ctx, lot.ID, []uint64{third.ID}))
+
+	split, err := ts.clients.Lots.GetByID(ts.ctx, lot.ID)
+	ts.Require().NoError(err)
+	ts.Require().Len(split.Items, 2)
+	stored, err := ts.clients.Items.GetByID(ts.ctx, third.ID)
+	ts.Require().NoError(err)
+	ts.Require().Zero(stored.LotID)
+	ts.Require().EqualValues(third.Version+2, stored.Version)
+
+	ts.Require().ErrorIs(ts.clients.Lots.Split(ts.ctx, lot.ID, []uint64{third.ID}), storage.ErrEntityNotFound)
+
+	// Taking one of the last two items out leaves a lot of one, so the lot is removed.
+	ts.Require().NoError(ts.clients.Lots.Split(ts.ctx, lot.ID, []uint64{items[0].ID}))
+	_, err = ts.clients.Lots.GetByID(ts.ctx, lot.ID)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+	for _, item := range items {
+		stored, err = ts.clients.Items.GetByID(ts.ctx, item.ID)
+		ts.Require().NoError(err)
+		ts.Require().Zero(stored.LotID)
+	}
+}
+
+func (ts *ConformanceSuite) TestLotSplitReturnsErrLotHasBidsWhenLotHasBids() {
+	users, items := ts.createTestAssets()
+	lot := ts.createLot("Bundle", items[0], items[1])
+	ts.placeBid(users[0], items[1], 10)
+
+	ts.Require().ErrorIs(ts.clients.Lots.Split(ts.ctx, lot.ID, nil), storage.ErrLotHasBids)
+	_, err := ts.clients.Lots.GetByID(ts.ctx, lot.ID)
+	ts.Require().NoError(err)
+}
+
+func (ts *ConformanceSuite) TestItemDeleteReturnsErrItemInLot() {
+	_, items := ts.createTestAssets()
+	ts.createLot("Bundle", items[0], items[1])
+
+	ts.Require().ErrorIs(ts.clients.Items.Delete(ts.ctx, items[0].Name), storage.ErrItemInLot)
+	_, err := ts.clients.Items.Get(ts.ctx, items[0].Name)
+	ts.Require().NoError(err)
+}
+
+func (ts *ConformanceSuite) TestImageAddAppendsImagesInOrder() {
+	_, items := ts.createTestAssets()
+	first := ts.addImage(items[0], "first")
+	second := ts.addImage(items[0], "second")
+	ts.addImage(items[1], "other")
+
+	ts.Require().NotEqual(first.ID, second.ID)
+	ts.Require().EqualValues(0, first.Position)
+	ts.Require().EqualValues(1, second.Position)
+
+	images, err := ts.clients.Images.GetAll(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.ItemImage{first, second}, images)
+}
+
+func (ts *ConformanceSuite) TestImageAddReturnsErrEntityNotFoundWhenItemMissingOrDeleted() {
+	_, items := ts.createTestAssets()
+	ts.Require().NoError(ts.clients.Items.Delete(ts.ctx, items[0].Name))
+
+	err := ts.clients.Images.Add(ts.ctx, &model.ItemImage{ItemID: items[0].ID, Key: "key", ThumbnailKey: "thumbnail"})
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+	err = ts.clients.Images.Add(ts.ctx, &model.ItemImage{ItemID: 12345, Key: "key", ThumbnailKey: "thumbnail"})
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestImageGetAllReturnsEmptyWithoutImages() {
+	_, items := ts.createTestAssets()
+
+	images, err := ts.clients.Images.GetAll(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().Empty(images)
+}
+
+func (ts *ConformanceSuite) TestImageDeleteMovesLaterImagesUp() {
+	_, items := ts.createTestAssets()
+	first := ts.addImage(items[0], "first")
+	second := ts.addImage(items[0], "second")
+	third := ts.addImage(items[0], "third")
+
+	ts.Require().NoError(ts.clients.Images.Delete(ts.ctx, items[0].ID, second.ID))
+
+	images, err := ts.clients.Images.GetAll(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().Len(images, 2)
+	ts.Require().EqualValues(first.ID, images[0].ID)
+	ts.Require().EqualValues(0, images[0].Position)
+	ts.Require().EqualValues(third.ID, images[1].ID)
+	ts.Require().EqualValues(1, images[1].Position)
+}
+
+func (ts *ConformanceSuite) TestImageDeleteReturnsErrEntityNotFoundUnlessImageOfItem() {
+	_, items := ts.createTestAssets()
+	image := ts.addImage(items[0], "first")
+
+	err := ts.clients.Images.Delete(ts.ctx, items[1].ID, image.ID)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+	err = ts.clients.Images.Delete(ts.ctx, items[0].ID, 12345)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestImageReorderChangesPositions() {
+	_, items := ts.createTestAssets()
+	first := ts.addImage(items[0], "first")
+	second := ts.addImage(items[0], "second")
+	third := ts.addImage(items[0], "third")
+
+	ts.Require().NoError(ts.clients.Images.Reorder(ts.ctx, items[0].ID, []uint64{third.ID, first.ID, second.ID}))
+
+	images, err := ts.clients.Images.GetAll(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().Len(images, 3)
+	for position, expected := range []*model.ItemImage{third, first, second} {
+		ts.Require().EqualValues(expected.ID, images[position].ID)
+		ts.Require().EqualValues(expected.Key, images[position].Key)
+		ts.Require().EqualValues(position, images[position].Position)
+	}
+}
+
+func (ts *ConformanceSuite) TestImageReorderReturnsErrInvalidOrderUnlessEveryImageOnce() {
+	_, items := ts.createTestAssets()
+	first := ts.addImage(items[0], "first")
+	second := ts.addImage(items[0], "second")
+	other := ts.addImage(items[1], "other")
+
+	for _, imageIDs := range [][]uint64{
+		{first.ID},
+		{first.ID, first.ID},
+		{first.ID, other.ID},
+		{first.ID, second.ID, other.ID},
+	} {
+		err := ts.clients.Images.Reorder(ts.ctx, items[0].ID, imageIDs)
+		ts.Require().ErrorIs(err, storage.ErrInvalidOrder, imageIDs)
+	}
+
+	images, err := ts.clients.Images.GetAll(ts.ctx, items[0].ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.ItemImage{first, second}, images)
+}
+
+func (ts *ConformanceSuite) TestPlaceBidReturnsErrBidTooLowUnlessHigherThanEveryBid() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+	ts.placeBid(users[1], items[0], 20)
+
+	_, err := ts.clients.Bids.PlaceBid(ts.ctx, users[0], items[0], usd(20))
+	ts.Require().ErrorIs(err, storage.ErrBidTooLow)
+
+	_, err = ts.clients.Bids.PlaceBid(ts.ctx, users[1], items[0], usd(15))
+	ts.Require().ErrorIs(err, storage.ErrBidTooLow)
+
+	ts.placeBid(users[1], items[1], 5)
+	ts.placeBid(users[0], items[0], 21)
+}
+
+func (ts *ConformanceSuite) TestPlaceBidOnlyNeedsToBeatTheLastWinningPlace() {
+	users, items := ts.createTestAssets()
+	third := ts.createBidder("user3")
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: items[0].Name, Quantity: 2}))
+	ts.placeBid(users[0], items[0], 10)
+	ts.placeBid(users[1], items[0], 20)
+
+	_, err := ts.clients.Bids.PlaceBid(ts.ctx, third, items[0], usd(10))
+	ts.Require().ErrorIs(err, storage.ErrBidTooLow)
+	ts.placeBid(third, items[0], 15)
+
+	_, err = ts.clients.Bids.PlaceBid(ts.ctx, users[0], items[0], usd(15))
+	ts.Require().ErrorIs(err, storage.ErrBidTooLow)
+	ts.placeBid(users[0], items[0], 16)
+
+	_, err = ts.clients.Bids.PlaceBid(ts.ctx, users[1], items[0], usd(19))
+	ts.Require().ErrorIs(err, storage.ErrBidTooLow)
+}
+
+func (ts *ConformanceSuite) TestPlaceBidReturnsErrEntityNotFoundWhenUserOrItemMissing() {
+	users, items := ts.createTestAssets()
+
+	_, err := ts.clients.Bids.PlaceBid(ts.ctx, &model.User{Username: "missing"}, items[0], usd(10))
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	_, err = ts.clients.Bids.PlaceBid(ts.ctx, users[0], &model.AuctionItem{Name: "missing"}, usd(10))
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestPlaceBidReturnsBidWithBidderAndItem() {
+	users, items := ts.createTestAssets()
+
+	bid, err := ts.clients.Bids.PlaceBid(ts.ctx, users[0], &model.AuctionItem{Name: "ITEM ONE"}, usd(10))
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(&model.AuctionBid{
+		BidAmount: usd(10),
+		Bidder:    users[0],
+		Item:      items[0],
+	}, bid)
+}
+
+func (ts *ConformanceSuite) TestPlaceBidOnLotReturnsBidOnFirstItemOfLot() {
+	users, items := ts.createTestAssets()
+	lot := ts.createLot("Bundle", items[0], items[1])
+
+	bid, err := ts.clients.Bids.PlaceBid(ts.ctx, users[0], items[1], usd(10))
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(lot.ID, bid.Item.LotID)
+
+	highest, err := ts.clients.Bids.GetHighestBid(ts.ctx, items[1])
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(highest, bid)
+}
+
+func (ts *ConformanceSuite) TestGetHighestBidReturnsHighestBidWithBidderAndItem() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+	ts.placeBid(users[1], items[0], 20)
+	ts.placeBid(users[0], items[1], 30)
+
+	bid, err := ts.clients.Bids.GetHighestBid(ts.ctx, &model.AuctionItem{Name: "ITEM ONE"})
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(&model.AuctionBid{
+		BidAmount: usd(20),
+		Bidder:    users[1],
+		Item:      items[0],
+	}, bid)
+}
+
+func (ts *ConformanceSuite) TestGetHighestBidReflectsUpdatedBidder() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+
+	ts.Require().NoError(ts.clients.Users.Update(ts.ctx, &model.User{
+		Username:    users[0].Username,
+		DisplayName: "updated",
+	}))
+
+	bid, err := ts.clients.Bids.GetHighestBid(ts.ctx, items[0])
+	ts.Require().NoError(err)
+	ts.Require().EqualValues("updated", bid.Bidder.DisplayName)
+}
+
+func (ts *ConformanceSuite) TestGetHighestBidReturnsErrEntityNotFoundWithoutBids() {
+	_, items := ts.createTestAssets()
+
+	_, err := ts.clients.Bids.GetHighestBid(ts.ctx, items[0])
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	_, err = ts.clients.Bids.GetHighestBid(ts.ctx, &model.AuctionItem{Name: "missing"})
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestItemCreateDefaultsQuantityToOne() {
+	_, items := ts.createTestAssets()
+	ts.Require().EqualValues(1, items[0].Quantity)
+
+	item := &model.AuctionItem{Name: "chef's table", Quantity: 10}
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	stored, err := ts.clients.Items.GetByID(ts.ctx, item.ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(10, stored.Quantity)
+}
+
+func (ts *ConformanceSuite) TestGetWinningBidsReturnsHighestBidOfTopBidders() {
+	users, items := ts.createTestAssets()
+	third := ts.createBidder("user3")
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: items[0].Name, Quantity: 2}))
+	ts.placeBid(users[0], items[0], 10)
+	ts.placeBid(users[1], items[0], 20)
+	ts.placeBid(users[0], items[0], 30)
+	ts.placeBid(third, items[0], 25)
+
+	bids, err := ts.clients.Bids.GetWinningBids(ts.ctx, items[0])
+	ts.Require().NoError(err)
+	ts.Require().Len(bids, 2)
+	ts.Require().EqualValues(usd(30), bids[0].BidAmount)
+	ts.Require().EqualValues(users[0].Username, bids[0].Bidder.Username)
+	ts.Require().EqualValues(usd(25), bids[1].BidAmount)
+	ts.Require().EqualValues(third.Username, bids[1].Bidder.Username)
+	ts.Require().EqualValues(2, bids[1].Item.Quantity)
+}
+
+func (ts *ConformanceSuite) TestGetWinningBidsBreaksTiesByWhoBidFirst() {
+	users, items := ts.createTestAssets()
+	third := ts.createBidder("user3")
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: items[0].Name, Quantity: 3}))
+	ts.placeBid(third, items[0], 20)
+	ts.placeBid(users[0], items[0], 10)
+	ts.placeBid(users[1], items[0], 20)
+
+	bids, err := ts.clients.Bids.GetWinningBids(ts.ctx, items[0])
+	ts.Require().NoError(err)
+	ts.Require().Len(bids, 3)
+	ts.Require().EqualValues(third.Username, bids[0].Bidder.Username)
+	ts.Require().EqualValues(users[1].Username, bids[1].Bidder.Username)
+	ts.Require().EqualValues(users[0].Username, bids[2].Bidder.Username)
+}
+
+func (ts *ConformanceSuite) TestGetWinningBidsReturnsErrEntityNotFoundWithoutBids() {
+	_, items := ts.createTestAssets()
+
+	_, err := ts.clients.Bids.GetWinningBids(ts.ctx, items[0])
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	_, err = ts.clients.Bids.GetWinningBids(ts.ctx, &model.AuctionItem{Name: "missing"})
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestGetWinningBidsUsesQuantityOfFirstItemOfLot() {
+	users, items := ts.createTestAssets()
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: items[0].Name, Quantity: 2}))
+	ts.createLot("bundle", items...)
+	ts.placeBid(users[0], items[1], 10)
+	ts.placeBid(users[1], items[1], 5)
+
+	bids, err := ts.clients.Bids.GetWinningBids(ts.ctx, items[1])
+	ts.Require().NoError(err)
+	ts.Require().Len(bids, 2)
+}
+
+func (ts *ConformanceSuite) TestGetAllWinningBidsReturnsHighestBidForEachItemInCreationOrder() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[1], 5)
+	ts.placeBid(users[0], items[0], 10)
+	ts.placeBid(users[1], items[1], 50)
+	ts.placeBid(users[1], items[0], 20)
+
+	bids, err := ts.clients.Bids.GetAllWinningBids(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AuctionBid{
+		{
+			BidAmount: usd(20),
+			Bidder:    users[1],
+			Item:      items[0],
+		},
+		{
+			BidAmount: usd(50),
+			Bidder:    users[1],
+			Item:      items[1],
+		},
+	}, bids)
+}
+
+func (ts *ConformanceSuite) TestGetAllWinningBidsReturnsEveryWinnerOfItemsWithQuantity() {
+	users, items := ts.createTestAssets()
+	ts.Require().NoError(ts.clients.Items.Update(ts.ctx, &model.AuctionItem{Name: items[1].Name, Quantity: 2}))
+	ts.placeBid(users[0], items[1], 5)
+	ts.placeBid(users[1], items[1], 50)
+	ts.placeBid(users[0], items[0], 10)
+
+	bids, err := ts.clients.Bids.GetAllWinningBids(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(bids, 3)
+	ts.Require().EqualValues(usd(10), bids[0].BidAmount)
+	ts.Require().EqualValues(usd(50), bids[1].BidAmount)
+	ts.Require().EqualValues(usd(5), bids[2].BidAmount)
+}
+
+func (ts *ConformanceSuite) TestGetAllWinningBidsReturnsEmptyWithoutBids() {
+	ts.createTestAssets()
+
+	bids, err := ts.clients.Bids.GetAllWinningBids(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Empty(bids)
+}
+
+func (ts *ConformanceSuite) TestDonationGetLevelsOrdersByAmountDescending() {
+	small := ts.createLevel("Books", 100)
+	large := ts.createLevel("Classroom", 1000)
+	tied := ts.createLevel("Laptops", 100)
+
+	levels, err := ts.clients.Donations.GetLevels(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.AppealLevel{large, small, tied}, levels)
+}
+
+func (ts *ConformanceSuite) TestDonationCreateLevelReturnsErrEntityAlreadyExistsIgnoringCase() {
+	ts.createLevel("Classroom", 1000)
+
+	err := ts.clients.Donations.CreateLevel(ts.ctx, &model.AppealLevel{Name: "CLASSROOM", Amount: usd(500)})
+	ts.Require().ErrorIs(err, storage.ErrEntityAlreadyExists)
+}
+
+func (ts *ConformanceSuite) TestDonationCreatePledgeUsesAmountOfLevel() {
+	users, _ := ts.createTestAssets()
+	level := ts.createLevel("Classroom", 1000)
+
+	pledge := &model.Pledge{Level: &model.AppealLevel{ID: level.ID}, Bidder: users[0], Amount: usd(5), RecordedBy: "admin"}
+	ts.Require().NoError(ts.clients.Donations.CreatePledge(ts.ctx, pledge))
+	ts.Require().NotZero(pledge.ID)
+	ts.Require().EqualValues(usd(1000), pledge.Amount)
+	ts.Require().EqualValues(level, pledge.Level)
+	ts.Require().EqualValues(users[0].Username, pledge.Bidder.Username)
+
+	pledges, err := ts.clients.Donations.GetPledges(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.Pledge{pledge}, pledges)
+}
+
+func (ts *ConformanceSuite) TestDonationCreatePledgeReturnsErrEntityNotFoundForMissingBidderOrLevel() {
+	users, _ := ts.createTestAssets()
+	level := ts.createLevel("Classroom", 1000)
+
+	err := ts.clients.Donations.CreatePledge(ts.ctx, &model.Pledge{Level: level, Bidder: &model.User{Username: "missing"}})
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	err = ts.clients.Donations.CreatePledge(ts.ctx, &model.Pledge{Level: &model.AppealLevel{ID: 999}, Bidder: users[0]})
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	ts.Require().NoError(ts.clients.Users.Delete(ts.ctx, users[0].Username))
+	err = ts.clients.Donations.CreatePledge(ts.ctx, &model.Pledge{Level: level, Bidder: users[0]})
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestDonationGetPledgesKeepsPledgesOfDeletedUsers() {
+	users, _ := ts.createTestAssets()
+	level := ts.createLevel("Classroom", 1000)
+	ts.createPledge(level, users[0])
+	ts.createPledge(level, users[1])
+	ts.Require().NoError(ts.clients.Users.Delete(ts.ctx, users[0].Username))
+
+	pledges, err := ts.clients.Donations.GetPledges(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Len(pledges, 2)
+	ts.Require().EqualValues(users[0].Username, pledges[0].Bidder.Username)
+	ts.Require().False(pledges[0].Bidder.DeletedAt.IsZero())
+	ts.Require().EqualValues(users[1].Username, pledges[1].Bidder.Username)
+}
+
+func (ts *ConformanceSuite) TestDonationDeleteLevelReturnsErrLevelHasPledges() {
+	users, _ := ts.createTestAssets()
+	level := ts.createLevel("Classroom", 1000)
+	pledge := ts.createPledge(level, users[0])
+
+	ts.Require().ErrorIs(ts.clients.Donations.DeleteLevel(ts.ctx, level.ID), storage.ErrLevelHasPledges)
+
+	deleted, err := ts.clients.Donations.DeletePledge(ts.ctx, pledge.ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(pledge, deleted)
+	ts.Require().NoError(ts.clients.Donations.DeleteLevel(ts.ctx, level.ID))
+
+	levels, err := ts.clients.Donations.GetLevels(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Empty(levels)
+}
+
+func (ts *ConformanceSuite) TestDonationDeleteReturnsErrEntityNotFoundWhenMissing() {
+	ts.Require().ErrorIs(ts.clients.Donations.DeleteLevel(ts.ctx, 999), storage.ErrEntityNotFound)
+
+	_, err := ts.clients.Donations.DeletePledge(ts.ctx, 999)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestRaffleCreateAndGetAll() {
+	quilt := ts.createRaffle("Quilt", 5)
+	basket := &model.Raffle{Name: "Wine Basket", Description: "Six bottles", TicketPrice: usd(10), Prizes: 2}
+	ts.Require().NoError(ts.clients.Raffles.Create(ts.ctx, basket))
+	ts.Require().NotZero(basket.ID)
+	ts.Require().Nil(basket.Drawing)
+
+	raffles, err := ts.clients.Raffles.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.Raffle{quilt, basket}, raffles)
+
+	retrieved, err := ts.clients.Raffles.GetByID(ts.ctx, basket.ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(basket, retrieved)
+
+	_, err = ts.clients.Raffles.GetByID(ts.ctx, 999)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestRaffleCreateReturnsErrEntityAlreadyExistsIgnoringCase() {
+	ts.createRaffle("Quilt", 5)
+
+	err := ts.clients.Raffles.Create(ts.ctx, &model.Raffle{Name: "QUILT", TicketPrice: usd(10), Prizes: 1})
+	ts.Require().ErrorIs(err, storage.ErrEntityAlreadyExists)
+}
+
+func (ts *ConformanceSuite) TestRaffleBuyTicketsUsesTicketPriceOfRaffle() {
+	users, _ := ts.createTestAssets()
+	quilt := ts.createRaffle("Quilt", 5)
+	basket := ts.createRaffle("Wine Basket", 10)
+
+	purchase := &model.TicketPurchase{RaffleID: quilt.ID, Buyer: users[0], Quantity: 3, Price: usd(1), RecordedBy: "admin"}
+	ts.Require().NoError(ts.clients.Raffles.BuyTickets(ts.ctx, purchase))
+	ts.Require().NotZero(purchase.ID)
+	ts.Require().EqualValues(usd(5), purchase.Price)
+	ts.Require().EqualValues(3, purchase.Quantity)
+	ts.Require().EqualValues("admin", purchase.RecordedBy)
+	ts.Require().EqualValues(users[0].Username, purchase.Buyer.Username)
+	other := ts.buyTickets(basket, users[0], 1)
+	last := ts.buyTickets(quilt, users[1], 2)
+
+	tickets, err := ts.clients.Raffles.GetTickets(ts.ctx, quilt.ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.TicketPurchase{purchase, last}, tickets)
+
+	tickets, err = ts.clients.Raffles.GetTickets(ts.ctx, basket.ID)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.TicketPurchase{other}, tickets)
+}
+
+func (ts *ConformanceSuite) TestRaffleBuyTicketsReturnsErrEntityNotFoundForMissingBuyerOrRaffle() {
+	users, _ := ts.createTestAssets()
+	quilt := ts.createRaffle("Quilt", 5)
+
+	purchase := &model.TicketPurchase{RaffleID: quilt.ID, Buyer: &model.User{Username: "missing"}, Quantity: 1}
+	ts.Require().ErrorIs(ts.clients.Raffles.BuyTickets(ts.ctx, purchase), storage.ErrEntityNotFound)
+
+	purchase = &model.TicketPurchase{RaffleID: 999, Buyer: users[0], Quantity: 1}
+	ts.Require().ErrorIs(ts.clients.Raffles.BuyTickets(ts.ctx, purchase), storage.ErrEntityNotFound)
+
+	ts.Require().NoError(ts.clients.Users.Delete(ts.ctx, users[0].Username))
+	purchase = &model.TicketPurchase{RaffleID: quilt.ID, Buyer: users[0], Quantity: 1}
+	ts.Require().ErrorIs(ts.clients.Raffles.BuyTickets(ts.ctx, purchase), storage.ErrEntityNotFound)
+
+	_, err := ts.clients.Raffles.GetTickets(ts.ctx, 999)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestRaffleGetTicketsKeepsPurchasesOfDeletedUsers() {
+	users, _ := ts.createTestAssets()
+	quilt := ts.createRaffle("Quilt", 5)
+	ts.buyTickets(quilt, users[0], 1)
+	ts.Require().NoError(ts.clients.Users.Delete(ts.ctx, users[0].Username))
+
+	tickets, err := ts.clients.Raffles.GetTickets(ts.ctx, quilt.ID)
+	ts.Require().NoError(err)
+	ts.Require().Len(tickets, 1)
+	ts.Require().EqualValues(users[0].Username, tickets[0].Buyer.Username)
+	ts.Require().False(tickets[0].Buyer.DeletedAt.IsZero())
+}
+
+func (ts *ConformanceSuite) TestRaffleRecordDrawingOnlyOnce() {
+	users, _ := ts.createTestAssets()
+	quilt := ts.createRaffle("Quilt", 5)
+	ts.buyTickets(quilt, users[0], 2)
+	drawing := &model.RaffleDrawing{
+		Seed:    "8f14e45fceea167a",
+		Tickets: []string{users[0].Username, users[0].Username},
+		Winners: []int{2},
+		DrawnAt: time.Now().UTC(),
+	}
+	ts.Require().NoError(ts.clients.Raffles.RecordDrawing(ts.ctx, quilt.ID, drawing))
+
+	retrieved, err := ts.clients.Raffles.GetByID(ts.ctx, quilt.ID)
+	ts.Require().NoError(err)
+	ts.Require().NotNil(retrieved.Drawing)
+	ts.Require().EqualValues(drawing.Seed, retrieved.Drawing.Seed)
+	ts.Require().EqualValues(drawing.Tickets, retrieved.Drawing.Tickets)
+	ts.Require().EqualValues(drawing.Winners, retrieved.Drawing.Winners)
+	ts.Require().WithinDuration(drawing.DrawnAt, retrieved.Drawing.DrawnAt, time.Millisecond)
+
+	err = ts.clients.Raffles.RecordDrawing(ts.ctx, quilt.ID, drawing)
+	ts.Require().ErrorIs(err, storage.ErrRaffleDrawn)
+	err = ts.clients.Raffles.BuyTickets(ts.ctx, &model.TicketPurchase{RaffleID: quilt.ID, Buyer: users[1], Quantity: 1})
+	ts.Require().ErrorIs(err, storage.ErrRaffleDrawn)
+	err = ts.clients.Raffles.RecordDrawing(ts.ctx, 999, drawing)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestSpendingLimitSetReplacesLimitAndGetAllListsEventFirst() {
+	users, _ := ts.createTestAssets()
+
+	ts.setLimit(users[1].Username, 500)
+	ts.setLimit(users[0].Username, 100)
+	ts.setLimit("", 200)
+	ts.setLimit(users[0].Username, 150)
+
+	limits, err := ts.clients.Limits.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.SpendingLimit{
+		{Amount: usd(200)},
+		{Username: users[0].Username, Amount: usd(150)},
+		{Username: users[1].Username, Amount: usd(500)},
+	}, limits)
+
+	limit, err := ts.clients.Limits.Get(ts.ctx, "")
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(&model.SpendingLimit{Amount: usd(200)}, limit)
+
+	ts.Require().NoError(ts.clients.Limits.Delete(ts.ctx, users[0].Username))
+	_, err = ts.clients.Limits.Get(ts.ctx, users[0].Username)
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+	ts.Require().ErrorIs(ts.clients.Limits.Delete(ts.ctx, users[0].Username), storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestSpendingLimitSetReturnsErrEntityNotFoundForMissingUser() {
+	err := ts.clients.Limits.Set(ts.ctx, &model.SpendingLimit{Username: "missing", Amount: usd(100)})
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	limits, err := ts.clients.Limits.GetAll(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().Empty(limits)
+}
+
+func (ts *ConformanceSuite) TestOutbidNoticeTakeReturnsNoticesOfUserOnce() {
+	users, items := ts.createTestAssets()
+	notices := []*model.OutbidNotice{
+		ts.outbidNotice(users[0], items[0], 10),
+		ts.outbidNotice(users[1], items[0], 20),
+		ts.outbidNotice(users[0], items[1], 30),
+	}
+	for _, notice := range notices {
+		ts.Require().NoError(ts.clients.Outbids.Add(ts.ctx, notice))
+		ts.Require().NotZero(notice.ID)
+	}
+
+	taken, err := ts.clients.Outbids.Take(ts.ctx, users[0].Username)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.OutbidNotice{notices[0], notices[2]}, taken)
+
+	taken, err = ts.clients.Outbids.Take(ts.ctx, users[0].Username)
+	ts.Require().NoError(err)
+	ts.Require().Empty(taken)
+
+	taken, err = ts.clients.Outbids.Take(ts.ctx, users[1].Username)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.OutbidNotice{notices[1]}, taken)
+}
+
+func (ts *ConformanceSuite) TestOutbidNoticeAddReplacesNoticeAboutSameItemOrLot() {
+	users, items := ts.createTestAssets()
+	third := &model.AuctionItem{Name: "item three"}
+	ts.Require().NoError(ts.clients.Items.Create(ts.ctx, third))
+	lot := ts.createLot("lot", items[1], third)
+
+	first := ts.outbidNotice(users[0], items[0], 10)
+	ts.Require().NoError(ts.clients.Outbids.Add(ts.ctx, first))
+	onLot := ts.outbidNotice(users[0], items[1], 20)
+	onLot.LotID, onLot.LotName = lot.ID, lot.Name
+	ts.Require().NoError(ts.clients.Outbids.Add(ts.ctx, onLot))
+
+	latest := ts.outbidNotice(users[0], items[0], 30)
+	ts.Require().NoError(ts.clients.Outbids.Add(ts.ctx, latest))
+	latestOnLot := ts.outbidNotice(users[0], third, 40)
+	latestOnLot.LotID, latestOnLot.LotName = lot.ID, lot.Name
+	ts.Require().NoError(ts.clients.Outbids.Add(ts.ctx, latestOnLot))
+
+	taken, err := ts.clients.Outbids.Take(ts.ctx, users[0].Username)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.OutbidNotice{latest, latestOnLot}, taken)
+}
+
+func (ts *ConformanceSuite) TestOutbidNoticeRemoveDeletesOnlyNoticeWithID() {
+	users, items := ts.createTestAssets()
+	notices := []*model.OutbidNotice{ts.outbidNotice(users[0], items[0], 10), ts.outbidNotice(users[0], items[1], 20)}
+	for _, notice := range notices {
+		ts.Require().NoError(ts.clients.Outbids.Add(ts.ctx, notice))
+	}
+
+	ts.Require().NoError(ts.clients.Outbids.Remove(ts.ctx, notices[0].ID))
+	ts.Require().NoError(ts.clients.Outbids.Remove(ts.ctx, notices[0].ID))
+
+	taken, err := ts.clients.Outbids.Take(ts.ctx, users[0].Username)
+	ts.Require().NoError(err)
+	ts.Require().EqualValues([]*model.OutbidNotice{notices[1]}, taken)
+}
+
+func (ts *ConformanceSuite) TestOutbidNoticeAddReturnsErrEntityNotFoundForMissingUserOrItem() {
+	users, items := ts.createTestAssets()
+
+	err := ts.clients.Outbids.Add(ts.ctx, ts.outbidNotice(&model.User{Username: "missing"}, items[0], 10))
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+	err = ts.clients.Outbids.Add(ts.ctx, ts.outbidNotice(users[0], &model.AuctionItem{ID: 1000, Name: "missing"}, 10))
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestPlaceBidReturnsErrOverSpendingLimitWhenWinningBidsAddUpToMore() {
+	users, items := ts.createTestAssets()
+	ts.setLimit(users[0].Username, 100)
+
+	ts.placeBid(users[0], items[0], 50)
+	_, err := ts.clients.Bids.PlaceBid(ts.ctx, users[0], items[1], usd(51))
+	ts.Require().ErrorIs(err, storage.ErrOverSpendingLimit)
+	ts.placeBid(users[0], items[1], 40)
+
+	// Raising a bid only commits the difference.
+	ts.placeBid(users[0], items[0], 60)
+	_, err = ts.clients.Bids.PlaceBid(ts.ctx, users[0], items[0], usd(61))
+	ts.Require().ErrorIs(err, storage.ErrOverSpendingLimit)
+
+	// Bids that were outbid are no longer committed.
+	ts.placeBid(users[1], items[1], 1000)
+	ts.placeBid(users[0], items[0], 100)
+
+	bid, err := ts.clients.Bids.GetHighestBid(ts.ctx, items[0])
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(usd(100), bid.BidAmount)
+}
+
+func (ts *ConformanceSuite) TestPlaceBidUsesLimitOfEventUnlessUserHasOwnLimit() {
+	users, items := ts.createTestAssets()
+	ts.setLimit("", 100)
+	ts.setLimit(users[1].Username, 300)
+
+	_, err := ts.clients.Bids.PlaceBid(ts.ctx, users[0], items[0], usd(101))
+	ts.Require().ErrorIs(err, storage.ErrOverSpendingLimit)
+	ts.placeBid(users[0], items[0], 100)
+	ts.placeBid(users[1], items[1], 300)
+
+	ts.Require().NoError(ts.clients.Limits.Delete(ts.ctx, ""))
+	ts.placeBid(users[0], items[1], 1000)
+}
+
+func (ts *ConformanceSuite) TestPlaceBidReturnsErrCurrencyMismatchWhenLimitIsInOtherCurrency() {
+	users, items := ts.createTestAssets()
+	limit := &model.SpendingLimit{Amount: model.Money{Amount: 100000, Currency: "EUR"}}
+	ts.Require().NoError(ts.clients.Limits.Set(ts.ctx, limit))
+
+	_, err := ts.clients.Bids.PlaceBid(ts.ctx, users[0], items[0], usd(10))
+	ts.Require().ErrorIs(err, model.ErrCurrencyMismatch)
+
+	_, err = ts.clients.Bids.GetHighestBid(ts.ctx, items[0])
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+}
+
+func (ts *ConformanceSuite) TestRunInTxCommitsWhenFnSucceeds() {
+	users, items := ts.createTestAssets()
+
+	err := ts.clients.Transactor.RunInTx(ts.ctx, func(ctx context.Context, clients *storage.Clients) error {
+		user, err := clients.Users.Get(ctx, users[0].Username)
+		if err != nil {
+			return err
+		}
+
+		item, err := clients.Items.Get(ctx, items[0].Name)
+		if err != nil {
+			return err
+		}
+
+		_, err = clients.Bids.PlaceBid(ctx, user, item, usd(10))
+		return err
+	})
+	ts.Require().NoError(err)
+
+	bid, err := ts.clients.Bids.GetHighestBid(ts.ctx, items[0])
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(usd(10), bid.BidAmount)
+}
+
+func (ts *ConformanceSuite) TestRunInTxSeesItsOwnChanges() {
+	err := ts.clients.Transactor.RunInTx(ts.ctx, func(ctx context.Context, clients *storage.Clients) error {
+		if err := clients.Users.Create(ctx, &model.User{Username: "new", Permission: model.PermissionLevelBidder}); err != nil {
+			return err
+		}
+
+		_, err := clients.Users.Get(ctx, "new")
+		return err
+	})
+	ts.Require().NoError(err)
+}
+
+func (ts *ConformanceSuite) TestRunInTxRollsBackWhenFnFails() {
+	users, items := ts.createTestAssets()
+	ts.placeBid(users[0], items[0], 10)
+	failure := errors.New("failure")
+
+	err := ts.clients.Transactor.RunInTx(ts.ctx, func(ctx context.Context, clients *storage.Clients) error {
+		if err := clients.Users.Create(ctx, &model.User{Username: "new", Permission: model.PermissionLevelBidder}); err != nil {
+			return err
+		}
+		if _, err := clients.Bids.PlaceBid(ctx, users[1], items[0], usd(20)); err != nil {
+			return err
+		}
+		if err := clients.Items.Delete(ctx, items[1].Name); err != nil {
+			return err
+		}
+		return failure
+	})
+	ts.Require().ErrorIs(err, failure)
+
+	_, err = ts.clients.Users.Get(ts.ctx, "new")
+	ts.Require().ErrorIs(err, storage.ErrEntityNotFound)
+
+	_, err = ts.clients.Items.Get(ts.ctx, items[1].Name)
+	ts.Require().NoError(err)
+
+	bid, err := ts.clients.Bids.GetHighestBid(ts.ctx, items[0])
+	ts.Require().NoError(err)
+	ts.Require().EqualValues(usd(10), bid.BidAmount)
+	ts.Require().EqualValues(users[0].Username, bid.Bidder.Username)
+}
+
+func (ts *ConformanceSuite) placeBid(user *model.User, item *model.AuctionItem, amount int64) {
+	_, err := ts.clients.Bids.PlaceBid(ts.ctx, user, item, usd(amount))
+	ts.Require().NoError(err)
+}
+
+func (ts *ConformanceSuite) setLimit(username string, amount int64) {
+	ts.Require().NoError(ts.clients.Limits.Set(ts.ctx, &model.SpendingLimit{Username: username, Amount: usd(amount)}))
+}
+
+// itemNames are the names of the items in order.
+func itemNames(items []*model.AuctionItem) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return names
+}
+
+// usd is an amount of cents.
+func usd(amount int64) model.Money {
+	return model.Money{Amount: amount, Currency: "USD"}
+}
+
+func (ts *ConformanceSuite) addImage(item *model.AuctionItem, name string) *model.ItemImage {
+	image := &model.ItemImage{
+		ItemID:       item.ID,
+		Key:          name + ".png",
+		ThumbnailKey: name + "-thumbnail.jpg",
+		ContentType:  "image/png",
+	}
+	ts.Require().NoError(ts.clients.Images.Add(ts.ctx, image))
+	return image
+}
+
+func (ts *ConformanceSuite) createBidder(username string) *model.User {
+	user := &model.User{
+		Username:       username,
+		DisplayName:    username,
+		HashedPassword: "1234",
+		Permission:     model.PermissionLevelBidder,
+	}
+	ts.Require().NoError(ts.clients.Users.Create(ts.ctx, user))
+	return user
+}
+
+func (ts *ConformanceSuite) createCategory(name string) *model.Category {
+	category := &model.Category{Name: name}
+	ts.Require().NoError(ts.clients.Categories.Create(ts.ctx, category))
+	return category
+}
+
+func (ts *ConformanceSuite) createLevel(name string, amount int64) *model.AppealLevel {
+	level := &model.AppealLevel{Name: name, Amount: usd(amount)}
+	ts.Require().NoError(ts.clients.Donations.CreateLevel(ts.ctx, level))
+	return level
+}
+
+func (ts *ConformanceSuite) createPledge(level *model.AppealLevel, bidder *model.User) *model.Pledge {
+	pledge := &model.Pledge{Level: level, Bidder: bidder}
+	ts.Require().NoError(ts.clients.Donations.CreatePledge(ts.ctx, pledge))
+	return pledge
+}
+
+func (ts *ConformanceSuite) createRaffle(name string, ticketPrice int64) *model.Raffle {
+	raffle := &model.Raffle{Name: name, TicketPrice: usd(ticketPrice), Prizes: 1}
+	ts.Require().NoError(ts.clients.Raffles.Create(ts.ctx, raffle))
+	return raffle
+}
+
+func (ts *ConformanceSuite) buyTickets(raffle *model.Raffle, buyer *model.User, quantity int) *model.TicketPurchase {
+	purchase := &model.TicketPurchase{RaffleID: raffle.ID, Buyer: buyer, Quantity: quantity}
+	ts.Require().NoError(ts.clients.Raffles.BuyTickets(ts.ctx, purchase))
+	return purchase
+}
+
+func (ts *ConformanceSuite) outbidNotice(user *model.User, item *model.AuctionItem, amount int64) *model.OutbidNotice {
+	return &model.OutbidNotice{
+		Username:   user.Username,
+		ItemID:     item.ID,
+		ItemName:   item.Name,
+		CurrentBid: usd(amount),
+		MinimumBid: usd(amount + 1),
+	}
+}
+
+func (ts *ConformanceSuite) createLot(name string, items ...*model.AuctionItem) *model.Lot {
+	itemIDs := make([]uint64, len(items))
+	for i, item := range items {
+		itemIDs[i] = item.ID
+	}
+	lot := &model.Lot{Name: name}
+	ts.Require().NoError(ts.clients.Lots.Create(ts.ctx, lot, itemIDs))
+	return lot
+}
+
+func (ts *ConformanceSuite) createTestAssets() ([]*model.User, []*model.AuctionItem) {
+	users := []*model.User{
+		{
+			Username:       "user1",
+			DisplayName:    "USER 1",
+			HashedPassword: "1234",
+			Permission:     model.PermissionLevelBidder,
+		},
+		{
+			Username:       "user2",
+			DisplayName:    "USER 2",
+			HashedPassword: "5678",
+			Permission:     model.PermissionLevelAdmin,
+		},
+	}
+
+	for _, user := range users {
+		ts.Require().NoError(ts.clients.Users.Create(ts.ctx, user))
+	}
+
+	items := []*model.AuctionItem{
+		{
+			Name:        "item one",
+			ImageRef:    "some image",
+			Description: "this is the first item",
+		},
+		{
+			Name:        "item two",
+			ImageRef:    "another image",
+			Description: "this is the second item",
+		},
+	}
+
+	for _, item := range items {
+		ts.Require().NoError(ts.clients.Items.Create(ts.ctx, item))
+	}
+
+	return users, items
+}